@@ -26,6 +26,7 @@ import (
 	posthogTelemetry "github.com/devtron-labs/common-lib/telemetry"
 	util4 "github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appAsCode"
 	appStoreRestHandler "github.com/devtron-labs/devtron/api/appStore"
 	chartGroup2 "github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	chartProvider "github.com/devtron-labs/devtron/api/appStore/chartProvider"
@@ -37,13 +38,20 @@ import (
 	"github.com/devtron-labs/devtron/api/auth/user"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
+	"github.com/devtron-labs/devtron/api/configDraft"
+	"github.com/devtron-labs/devtron/api/configPromotion"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentAdmission"
+	"github.com/devtron-labs/devtron/api/deploymentDrift"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSigning"
+	"github.com/devtron-labs/devtron/api/incident"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/resourceScan"
@@ -70,6 +78,7 @@ import (
 	status3 "github.com/devtron-labs/devtron/api/router/app/pipeline/status"
 	trigger2 "github.com/devtron-labs/devtron/api/router/app/pipeline/trigger"
 	workflow2 "github.com/devtron-labs/devtron/api/router/app/workflow"
+	"github.com/devtron-labs/devtron/api/sbom"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/api/team"
@@ -115,6 +124,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/app/dbMigration"
 	"github.com/devtron-labs/devtron/pkg/app/status"
 	"github.com/devtron-labs/devtron/pkg/appClone"
+	appAsCode2 "github.com/devtron-labs/devtron/pkg/appClone/appAsCode"
 	"github.com/devtron-labs/devtron/pkg/appClone/batch"
 	"github.com/devtron-labs/devtron/pkg/appStatus"
 	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup"
@@ -135,9 +145,15 @@ import (
 	"github.com/devtron-labs/devtron/pkg/commonService"
 	"github.com/devtron-labs/devtron/pkg/config"
 	"github.com/devtron-labs/devtron/pkg/config/configDiff"
+	configPromotion2 "github.com/devtron-labs/devtron/pkg/config/configPromotion"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	deployment2 "github.com/devtron-labs/devtron/pkg/deployment"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/watch"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary"
+	canaryController "github.com/devtron-labs/devtron/pkg/deployment/canary/controller"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift"
 	git2 "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
@@ -153,6 +169,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	repository7 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/notifier"
+	incident2 "github.com/devtron-labs/devtron/pkg/notifier/incident"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService"
 	"github.com/devtron-labs/devtron/pkg/pipeline/executors"
@@ -170,6 +187,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/ucid"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
 	"github.com/devtron-labs/devtron/pkg/variables"
+	"github.com/devtron-labs/devtron/pkg/variables/externalSource"
+	"github.com/devtron-labs/devtron/pkg/variables/impact"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
 	repository10 "github.com/devtron-labs/devtron/pkg/variables/repository"
 	workflow3 "github.com/devtron-labs/devtron/pkg/workflow"
@@ -222,6 +241,23 @@ func InitializeApp() (*App, error) {
 		userResource.UserResourceWireSet,
 		policyGovernance.PolicyGovernanceWireSet,
 		resourceScan.ScanningResultWireSet,
+		deploymentAdmission.DeploymentAdmissionPolicyWireSet,
+		deploymentWindow.DeploymentWindowWireSet,
+		imageSigning.ImageSigningWireSet,
+		sbom.SbomWireSet,
+		deploymentDrift.DeploymentDriftWireSet,
+		incident2.IncidentWireSet,
+		incident.IncidentWireSet,
+		configDraft.ConfigDraftWireSet,
+		configPromotion.ConfigPromotionWireSet,
+		configPromotion2.ConfigPromotionWireSet,
+		appAsCode.AppAsCodeWireSet,
+		appAsCode2.AppAsCodeWireSet,
+		autoRollback.AutoRollbackWireSet,
+		watch.AutoRollbackWatchWireSet,
+		canary.CanaryAnalysisWireSet,
+		canaryController.CanaryAnalysisControllerWireSet,
+		drift.DeploymentDriftWireSet,
 		executor.ExecutorWireSet,
 		// -------wireset end ----------
 		// -------
@@ -343,6 +379,10 @@ func InitializeApp() (*App, error) {
 		// scoped variables start
 		variables.NewScopedVariableServiceImpl,
 		wire.Bind(new(variables.ScopedVariableService), new(*variables.ScopedVariableServiceImpl)),
+		impact.NewVariableImpactServiceImpl,
+		wire.Bind(new(impact.VariableImpactService), new(*impact.VariableImpactServiceImpl)),
+		externalSource.NewExternalValueResolverImpl,
+		wire.Bind(new(externalSource.ExternalValueResolver), new(*externalSource.ExternalValueResolverImpl)),
 
 		parsers.NewVariableTemplateParserImpl,
 		wire.Bind(new(parsers.VariableTemplateParser), new(*parsers.VariableTemplateParserImpl)),
@@ -470,6 +510,10 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(notifier.SlackNotificationService), new(*notifier.SlackNotificationServiceImpl)),
 		repository.NewSlackNotificationRepositoryImpl,
 		wire.Bind(new(repository.SlackNotificationRepository), new(*repository.SlackNotificationRepositoryImpl)),
+		notifier.NewTeamsNotificationServiceImpl,
+		wire.Bind(new(notifier.TeamsNotificationService), new(*notifier.TeamsNotificationServiceImpl)),
+		repository.NewTeamsNotificationRepositoryImpl,
+		wire.Bind(new(repository.TeamsNotificationRepository), new(*repository.TeamsNotificationRepositoryImpl)),
 		notifier.NewWebhookNotificationServiceImpl,
 		wire.Bind(new(notifier.WebhookNotificationService), new(*notifier.WebhookNotificationServiceImpl)),
 		repository.NewWebhookNotificationRepositoryImpl,
@@ -535,8 +579,7 @@ func InitializeApp() (*App, error) {
 		chartConfig.NewConfigMapRepositoryImpl,
 		wire.Bind(new(chartConfig.ConfigMapRepository), new(*chartConfig.ConfigMapRepositoryImpl)),
 
-		draftAwareConfigService.NewDraftAwareResourceServiceImpl,
-		wire.Bind(new(draftAwareConfigService.DraftAwareConfigService), new(*draftAwareConfigService.DraftAwareConfigServiceImpl)),
+		draftAwareConfigService.DraftAwareConfigWireSet,
 
 		config.WireSet,
 
@@ -608,6 +651,12 @@ func InitializeApp() (*App, error) {
 
 		repository.NewDeploymentGroupAppRepositoryImpl,
 		wire.Bind(new(repository.DeploymentGroupAppRepository), new(*repository.DeploymentGroupAppRepositoryImpl)),
+		repository.NewDeploymentGroupWaveRepositoryImpl,
+		wire.Bind(new(repository.DeploymentGroupWaveRepository), new(*repository.DeploymentGroupWaveRepositoryImpl)),
+		repository.NewDeploymentGroupReleaseRepositoryImpl,
+		wire.Bind(new(repository.DeploymentGroupReleaseRepository), new(*repository.DeploymentGroupReleaseRepositoryImpl)),
+		deploymentGroup.NewDeploymentGroupReleaseServiceImpl,
+		wire.Bind(new(deploymentGroup.DeploymentGroupReleaseService), new(*deploymentGroup.DeploymentGroupReleaseServiceImpl)),
 		restHandler.NewPubSubClientRestHandlerImpl,
 		wire.Bind(new(restHandler.PubSubClientRestHandler), new(*restHandler.PubSubClientRestHandlerImpl)),
 
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userUtil "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"io"
+	"k8s.io/utils/pointer"
+	"net/http"
+	"sigs.k8s.io/yaml"
+)
+
+type AppAsCodeRestHandler interface {
+	Export(w http.ResponseWriter, r *http.Request)
+	Plan(w http.ResponseWriter, r *http.Request)
+	Apply(w http.ResponseWriter, r *http.Request)
+}
+
+type AppAsCodeRestHandlerImpl struct {
+	logger           *zap.SugaredLogger
+	userService      user.UserService
+	appAsCodeService appAsCode.AppAsCodeService
+	enforcer         casbin.Enforcer
+	enforcerUtil     rbac.EnforcerUtil
+	validator        *validator.Validate
+}
+
+func NewAppAsCodeRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	appAsCodeService appAsCode.AppAsCodeService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *AppAsCodeRestHandlerImpl {
+	return &AppAsCodeRestHandlerImpl{
+		logger:           logger,
+		userService:      userService,
+		appAsCodeService: appAsCodeService,
+		enforcer:         enforcer,
+		enforcerUtil:     enforcerUtil,
+		validator:        validator,
+	}
+}
+
+func (handler *AppAsCodeRestHandlerImpl) Export(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	appObject := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appObject); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	// secret values are exported for users with admin access on the app only
+	userHasAdminAccess := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, appObject)
+	//RBAC enforcer Ends
+	bundle, err := handler.appAsCodeService.Export(r.Context(), appId, userHasAdminAccess)
+	if err != nil {
+		handler.logger.Errorw("service err, Export", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	bundleYaml, err := yaml.Marshal(bundle)
+	if err != nil {
+		handler.logger.Errorw("error in marshalling bundle", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteOctetStreamResp(w, r, bundleYaml, fmt.Sprintf("%s.yaml", pointer.StringDeref(bundle.Destination.App, "")))
+}
+
+func (handler *AppAsCodeRestHandlerImpl) Plan(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, err := handler.getImportRequest(w, r, userId)
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	plan, err := handler.appAsCodeService.Plan(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, Plan", "appId", request.AppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, plan, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) Apply(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, err := handler.getImportRequest(w, r, userId)
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	plan, err := handler.appAsCodeService.Plan(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, Apply", "appId", request.AppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// the cd pipelines and the overrides are changed on their environments, which need access of their own
+	checkedEnvs := make(map[int]bool)
+	for _, item := range plan.Items {
+		if !item.Action.IsChange() || item.EnvironmentId == 0 || checkedEnvs[item.EnvironmentId] {
+			continue
+		}
+		checkedEnvs[item.EnvironmentId] = true
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, handler.enforcerUtil.GetEnvRBACNameByAppId(request.AppId, item.EnvironmentId)); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized for environment %s", item.Environment), nil, http.StatusForbidden)
+			return
+		}
+	}
+	//RBAC enforcer Ends
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+	ctx := util.SetSuperAdminInContext(r.Context(), isSuperAdmin)
+	userMetadata := userUtil.GetUserMetadata(ctx, userId, isSuperAdmin)
+	resp, err := handler.appAsCodeService.Apply(ctx, request, userMetadata)
+	if err != nil {
+		handler.logger.Errorw("service err, Apply", "appId", request.AppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// getImportRequest reads the bundle from the body, as yaml or json, and the app to apply it to from the query params.
+// The error is written to the response.
+func (handler *AppAsCodeRestHandlerImpl) getImportRequest(w http.ResponseWriter, r *http.Request, userId int32) (*bean.ImportRequest, error) {
+	request := &bean.ImportRequest{UserId: userId}
+	var err error
+	request.AppId, err = common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return nil, err
+	}
+	request.Prune, err = common.ExtractBoolQueryParam(r, "prune")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, err
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		handler.logger.Errorw("request err, getImportRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, err
+	}
+	bundle := &v1.App{}
+	err = yaml.UnmarshalStrict(body, bundle)
+	if err != nil {
+		handler.logger.Errorw("request err, getImportRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, err
+	}
+	request.Bundle = bundle
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in getImportRequest", "appId", request.AppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, err
+	}
+	return request, nil
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AppAsCodeRouter interface {
+	InitAppAsCodeRouter(appAsCodeRouter *mux.Router)
+}
+
+type AppAsCodeRouterImpl struct {
+	appAsCodeRestHandler AppAsCodeRestHandler
+}
+
+func NewAppAsCodeRouterImpl(appAsCodeRestHandler AppAsCodeRestHandler) *AppAsCodeRouterImpl {
+	return &AppAsCodeRouterImpl{appAsCodeRestHandler: appAsCodeRestHandler}
+}
+
+func (router *AppAsCodeRouterImpl) InitAppAsCodeRouter(appAsCodeRouter *mux.Router) {
+	appAsCodeRouter.Path("/export").HandlerFunc(router.appAsCodeRestHandler.Export).Methods("GET")
+	appAsCodeRouter.Path("/plan").HandlerFunc(router.appAsCodeRestHandler.Plan).Methods("POST")
+	appAsCodeRouter.Path("/apply").HandlerFunc(router.appAsCodeRestHandler.Apply).Methods("POST")
+}
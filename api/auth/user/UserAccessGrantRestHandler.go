@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/util/response"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+	"strconv"
+)
+
+// accessGrantAction is one of the review actions on an access grant
+type accessGrantAction func(grantId int, request *bean2.UserAccessGrantActionRequest) (*bean2.UserAccessGrant, error)
+
+func (handler UserRestHandlerImpl) CreateAccessGrant(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean2.UserAccessGrantRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateAccessGrant", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.RequestedBy = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateAccessGrant", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying, any user can request access for self, the approver is checked on approval
+	if request.UserId != userId {
+		token := r.Header.Get("token")
+		isAuthorised, err := handler.checkRBACForUserCreate(token, false, request.RoleFilters, nil)
+		if err != nil {
+			common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
+			return
+		}
+		if !isAuthorised {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+	}
+	//RBAC enforcer Ends
+	res, err := handler.userAccessGrantService.CreateGrant(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateAccessGrant", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) GetAccessGrantById(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	grantId, err := strconv.Atoi(mux.Vars(r)["grantId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetAccessGrantById", "err", err, "grantId", grantId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.userAccessGrantService.GetGrantById(grantId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessGrantById", "err", err, "grantId", grantId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	isAuthorised, err := handler.checkRBACForAccessGrant(token, userId, res, true)
+	if err != nil {
+		common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
+		return
+	}
+	if !isAuthorised {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	//RBAC enforcer Ends
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) ListAccessGrants(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	filter := &bean2.UserAccessGrantFilter{}
+	err = schema.NewDecoder().Decode(filter, r.URL.Query())
+	if err != nil {
+		handler.logger.Errorw("request err, ListAccessGrants", "err", err, "query", r.URL.Query())
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	grants, err := handler.userAccessGrantService.ListGrants(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, ListAccessGrants", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying, only the grants the user requested, holds or can approve are listed
+	token := r.Header.Get("token")
+	res := make([]*bean2.UserAccessGrant, 0, len(grants))
+	for _, grant := range grants {
+		isAuthorised, err := handler.checkRBACForAccessGrant(token, userId, grant, true)
+		if err != nil {
+			common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
+			return
+		}
+		if isAuthorised {
+			res = append(res, grant)
+		}
+	}
+	//RBAC enforcer Ends
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) ApproveAccessGrant(w http.ResponseWriter, r *http.Request) {
+	handler.handleAccessGrantAction(w, r, handler.userAccessGrantService.ApproveGrant, false)
+}
+
+func (handler UserRestHandlerImpl) RejectAccessGrant(w http.ResponseWriter, r *http.Request) {
+	handler.handleAccessGrantAction(w, r, handler.userAccessGrantService.RejectGrant, false)
+}
+
+func (handler UserRestHandlerImpl) RevokeAccessGrant(w http.ResponseWriter, r *http.Request) {
+	handler.handleAccessGrantAction(w, r, handler.userAccessGrantService.RevokeGrant, true)
+}
+
+// handleAccessGrantAction applies the action on the grant for a user who can approve it, allowSelf lets the requester
+// and the user holding the grant apply the action too
+func (handler UserRestHandlerImpl) handleAccessGrantAction(w http.ResponseWriter, r *http.Request, action accessGrantAction, allowSelf bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	grantId, err := strconv.Atoi(mux.Vars(r)["grantId"])
+	if err != nil {
+		handler.logger.Errorw("request err, handleAccessGrantAction", "err", err, "grantId", grantId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request := &bean2.UserAccessGrantActionRequest{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			handler.logger.Errorw("request err, handleAccessGrantAction", "err", err, "grantId", grantId)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, handleAccessGrantAction", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	grant, err := handler.userAccessGrantService.GetGrantById(grantId)
+	if err != nil {
+		handler.logger.Errorw("service err, handleAccessGrantAction", "err", err, "grantId", grantId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	isAuthorised, err := handler.checkRBACForAccessGrant(token, userId, grant, allowSelf)
+	if err != nil {
+		common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
+		return
+	}
+	if !isAuthorised {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	//RBAC enforcer Ends
+	res, err := action(grantId, request)
+	if err != nil {
+		handler.logger.Errorw("service err, handleAccessGrantAction", "err", err, "grantId", grantId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// checkRBACForAccessGrant authorises the users who can create the role filters of the grant, allowSelf authorises the
+// requester and the user holding the grant as well
+func (handler UserRestHandlerImpl) checkRBACForAccessGrant(token string, userId int32, grant *bean2.UserAccessGrant, allowSelf bool) (bool, error) {
+	if allowSelf && (grant.RequestedBy == userId || grant.UserId == userId) {
+		return true, nil
+	}
+	return handler.checkRBACForUserCreate(token, false, grant.RoleFilters, nil)
+}
@@ -61,6 +61,13 @@ type UserRestHandler interface {
 	UpdateTriggerPolicyForTerminalAccess(w http.ResponseWriter, r *http.Request)
 	GetRoleCacheDump(w http.ResponseWriter, r *http.Request)
 	InvalidateRoleCache(w http.ResponseWriter, r *http.Request)
+
+	CreateAccessGrant(w http.ResponseWriter, r *http.Request)
+	GetAccessGrantById(w http.ResponseWriter, r *http.Request)
+	ListAccessGrants(w http.ResponseWriter, r *http.Request)
+	ApproveAccessGrant(w http.ResponseWriter, r *http.Request)
+	RejectAccessGrant(w http.ResponseWriter, r *http.Request)
+	RevokeAccessGrant(w http.ResponseWriter, r *http.Request)
 }
 
 type userNamePassword struct {
@@ -69,27 +76,30 @@ type userNamePassword struct {
 }
 
 type UserRestHandlerImpl struct {
-	userService         user2.UserService
-	validator           *validator.Validate
-	logger              *zap.SugaredLogger
-	enforcer            casbin.Enforcer
-	roleGroupService    user2.RoleGroupService
-	userCommonService   user2.UserCommonService
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil
+	userService            user2.UserService
+	validator              *validator.Validate
+	logger                 *zap.SugaredLogger
+	enforcer               casbin.Enforcer
+	roleGroupService       user2.RoleGroupService
+	userCommonService      user2.UserCommonService
+	rbacEnforcementUtil    commonEnforcementFunctionsUtil.CommonEnforcementUtil
+	userAccessGrantService user2.UserAccessGrantService
 }
 
 func NewUserRestHandlerImpl(userService user2.UserService, validator *validator.Validate,
 	logger *zap.SugaredLogger, enforcer casbin.Enforcer, roleGroupService user2.RoleGroupService,
 	userCommonService user2.UserCommonService,
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil) *UserRestHandlerImpl {
+	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil,
+	userAccessGrantService user2.UserAccessGrantService) *UserRestHandlerImpl {
 	userAuthHandler := &UserRestHandlerImpl{
-		userService:         userService,
-		validator:           validator,
-		logger:              logger,
-		enforcer:            enforcer,
-		roleGroupService:    roleGroupService,
-		userCommonService:   userCommonService,
-		rbacEnforcementUtil: rbacEnforcementUtil,
+		userService:            userService,
+		validator:              validator,
+		logger:                 logger,
+		enforcer:               enforcer,
+		roleGroupService:       roleGroupService,
+		userCommonService:      userCommonService,
+		rbacEnforcementUtil:    rbacEnforcementUtil,
+		userAccessGrantService: userAccessGrantService,
 	}
 	return userAuthHandler
 }
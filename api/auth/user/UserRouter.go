@@ -36,6 +36,20 @@ func NewUserRouterImpl(userRestHandler UserRestHandler) *UserRouterImpl {
 }
 
 func (router UserRouterImpl) InitUserRouter(userAuthRouter *mux.Router) {
+	//Time bound access grants, registered before the user routes matching /{id}
+	userAuthRouter.Path("/access-grant").
+		HandlerFunc(router.userRestHandler.ListAccessGrants).Methods("GET")
+	userAuthRouter.Path("/access-grant").
+		HandlerFunc(router.userRestHandler.CreateAccessGrant).Methods("POST")
+	userAuthRouter.Path("/access-grant/{grantId}").
+		HandlerFunc(router.userRestHandler.GetAccessGrantById).Methods("GET")
+	userAuthRouter.Path("/access-grant/{grantId}/approve").
+		HandlerFunc(router.userRestHandler.ApproveAccessGrant).Methods("PUT")
+	userAuthRouter.Path("/access-grant/{grantId}/reject").
+		HandlerFunc(router.userRestHandler.RejectAccessGrant).Methods("PUT")
+	userAuthRouter.Path("/access-grant/{grantId}/revoke").
+		HandlerFunc(router.userRestHandler.RevokeAccessGrant).Methods("PUT")
+
 	//User management
 	userAuthRouter.Path("/v2").
 		HandlerFunc(router.userRestHandler.GetAllV2).Methods("GET")
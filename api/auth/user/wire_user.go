@@ -20,6 +20,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/authentication"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	user2 "github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	"github.com/google/wire"
 )
@@ -77,4 +78,10 @@ var UserWireSet = wire.NewSet(
 	wire.Bind(new(RbacRoleRestHandler), new(*RbacRoleRestHandlerImpl)),
 	user2.NewRbacRoleServiceImpl,
 	wire.Bind(new(user2.RbacRoleService), new(*user2.RbacRoleServiceImpl)),
+
+	bean.GetUserAccessGrantConfig,
+	repository2.NewUserAccessGrantRepositoryImpl,
+	wire.Bind(new(repository2.UserAccessGrantRepository), new(*repository2.UserAccessGrantRepositoryImpl)),
+	user2.NewUserAccessGrantServiceImpl,
+	wire.Bind(new(user2.UserAccessGrantService), new(*user2.UserAccessGrantServiceImpl)),
 )
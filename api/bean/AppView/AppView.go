@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	driftBean "github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
 	"time"
 )
 
@@ -198,6 +199,8 @@ type AppDetailContainer struct {
 	LinkOuts                  []LinkOuts             `json:"linkOuts,omitempty"`
 	ResourceTree              map[string]interface{} `json:"resourceTree,omitempty"`
 	Notes                     string                 `json:"notes,omitempty"`
+	// Drift is the last drift scan of the helm deployed pipeline, nil if it was never scanned
+	Drift *driftBean.DriftSummary `json:"drift,omitempty"`
 }
 type AppDetailsContainer struct {
 	ResourceTree  map[string]interface{} `json:"resourceTree,omitempty"`
@@ -92,6 +92,7 @@ type ValuesOverrideRequest struct {
 	Namespace                             string                      `json:"-"`
 	ReleaseName                           string                      `json:"-"`
 	Image                                 string                      `json:"-"`
+	AdmissionPolicyWarnings               []string                    `json:"-"` // set in trigger flow, warnings raised by deployment admission policies
 }
 
 type BulkCdDeployEvent struct {
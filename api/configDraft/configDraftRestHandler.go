@@ -0,0 +1,339 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configDraft
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ConfigDraftRestHandler interface {
+	GetDrafts(w http.ResponseWriter, r *http.Request)
+	GetDraft(w http.ResponseWriter, r *http.Request)
+	GetDraftDiff(w http.ResponseWriter, r *http.Request)
+	AddComment(w http.ResponseWriter, r *http.Request)
+	ApproveDraft(w http.ResponseWriter, r *http.Request)
+	RejectDraft(w http.ResponseWriter, r *http.Request)
+	DiscardDraft(w http.ResponseWriter, r *http.Request)
+	GetConfigProtections(w http.ResponseWriter, r *http.Request)
+	SaveConfigProtection(w http.ResponseWriter, r *http.Request)
+}
+
+type ConfigDraftRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userService        user.UserService
+	configDraftService draftAwareConfigService.ConfigDraftService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+	validator          *validator.Validate
+}
+
+func NewConfigDraftRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	configDraftService draftAwareConfigService.ConfigDraftService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *ConfigDraftRestHandlerImpl {
+	return &ConfigDraftRestHandlerImpl{
+		logger:             logger,
+		userService:        userService,
+		configDraftService: configDraftService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+		validator:          validator,
+	}
+}
+
+func (handler *ConfigDraftRestHandlerImpl) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	envId, err := common.ExtractIntQueryParam(w, r, "envId", 0)
+	if err != nil {
+		return
+	}
+	var states []bean.DraftState
+	if statesParam := r.URL.Query().Get("states"); len(statesParam) > 0 {
+		for _, stateParam := range strings.Split(statesParam, ",") {
+			state, err := strconv.Atoi(strings.TrimSpace(stateParam))
+			if err != nil {
+				common.WriteJsonResp(w, err, "invalid draft state", http.StatusBadRequest)
+				return
+			}
+			states = append(states, bean.DraftState(state))
+		}
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(appId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.configDraftService.GetDrafts(appId, envId, states)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDrafts", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) GetDraft(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	draftId, err := common.ExtractIntPathParam(w, r, "draftId")
+	if err != nil {
+		return
+	}
+	resp, err := handler.configDraftService.GetDraft(draftId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDraft", "draftId", draftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(resp.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) GetDraftDiff(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	draftId, err := common.ExtractIntPathParam(w, r, "draftId")
+	if err != nil {
+		return
+	}
+	draft, err := handler.configDraftService.GetDraft(draftId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDraftDiff", "draftId", draftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(draft.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	// secret values are shown to users with admin access on the app only
+	userHasAdminAccess := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, handler.enforcerUtil.GetAppRBACNameByAppId(draft.AppId))
+	//RBAC enforcer Ends
+	resp, err := handler.configDraftService.GetDraftDiff(r.Context(), draftId, userId, userHasAdminAccess)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDraftDiff", "draftId", draftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) AddComment(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeActionRequest(w, r, false)
+	if !ok {
+		return
+	}
+	resp, err := handler.configDraftService.AddComment(request)
+	if err != nil {
+		handler.logger.Errorw("service err, AddComment", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) ApproveDraft(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeActionRequest(w, r, true)
+	if !ok {
+		return
+	}
+	resp, err := handler.configDraftService.ApproveDraft(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, ApproveDraft", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) RejectDraft(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeActionRequest(w, r, true)
+	if !ok {
+		return
+	}
+	resp, err := handler.configDraftService.RejectDraft(request)
+	if err != nil {
+		handler.logger.Errorw("service err, RejectDraft", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeActionRequest(w, r, false)
+	if !ok {
+		return
+	}
+	resp, err := handler.configDraftService.DiscardDraft(request)
+	if err != nil {
+		handler.logger.Errorw("service err, DiscardDraft", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// decodeActionRequest decodes the draft action request and enforces view access on the app and environment of the draft,
+// along with the config approval access if isReview. The response is written if the request is not to be processed further
+func (handler *ConfigDraftRestHandlerImpl) decodeActionRequest(w http.ResponseWriter, r *http.Request, isReview bool) (*bean.ConfigDraftActionRequest, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	request := &bean.ConfigDraftActionRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeActionRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in decodeActionRequest", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	draft, err := handler.configDraftService.GetDraft(request.DraftId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDraft", "draftId", request.DraftId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, handler.enforcerUtil.GetAppRBACNameByAppId(draft.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, handler.enforcerUtil.GetEnvRBACNameByAppId(draft.AppId, draft.EnvironmentId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	if isReview {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceConfig, casbin.ActionApprove, handler.enforcerUtil.GetTeamEnvRBACNameByAppId(draft.AppId, draft.EnvironmentId)); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return nil, false
+		}
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	return request, true
+}
+
+func (handler *ConfigDraftRestHandlerImpl) GetConfigProtections(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.configDraftService.GetConfigProtections(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetConfigProtections", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigDraftRestHandlerImpl) SaveConfigProtection(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ConfigProtectionDto{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveConfigProtection", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in SaveConfigProtection", "appId", request.AppId, "envId", request.EnvironmentId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	err = handler.configDraftService.SaveConfigProtection(request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveConfigProtection", "appId", request.AppId, "envId", request.EnvironmentId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, request, http.StatusOK)
+}
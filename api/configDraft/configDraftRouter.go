@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configDraft
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ConfigDraftRouter interface {
+	InitConfigDraftRouter(configRouter *mux.Router)
+}
+
+type ConfigDraftRouterImpl struct {
+	configDraftRestHandler ConfigDraftRestHandler
+}
+
+func NewConfigDraftRouterImpl(configDraftRestHandler ConfigDraftRestHandler) *ConfigDraftRouterImpl {
+	return &ConfigDraftRouterImpl{configDraftRestHandler: configDraftRestHandler}
+}
+
+func (router *ConfigDraftRouterImpl) InitConfigDraftRouter(configRouter *mux.Router) {
+	configRouter.Path("/protection").HandlerFunc(router.configDraftRestHandler.GetConfigProtections).Methods("GET")
+	configRouter.Path("/protection").HandlerFunc(router.configDraftRestHandler.SaveConfigProtection).Methods("POST")
+
+	configRouter.Path("").HandlerFunc(router.configDraftRestHandler.GetDrafts).Methods("GET")
+	configRouter.Path("/comment").HandlerFunc(router.configDraftRestHandler.AddComment).Methods("POST")
+	configRouter.Path("/approve").HandlerFunc(router.configDraftRestHandler.ApproveDraft).Methods("POST")
+	configRouter.Path("/reject").HandlerFunc(router.configDraftRestHandler.RejectDraft).Methods("POST")
+	configRouter.Path("/discard").HandlerFunc(router.configDraftRestHandler.DiscardDraft).Methods("POST")
+	configRouter.Path("/{draftId}").HandlerFunc(router.configDraftRestHandler.GetDraft).Methods("GET")
+	configRouter.Path("/{draftId}/diff").HandlerFunc(router.configDraftRestHandler.GetDraftDiff).Methods("GET")
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configPromotion
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userUtil "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion/bean"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ConfigPromotionRestHandler interface {
+	GetPromotionDiff(w http.ResponseWriter, r *http.Request)
+	Promote(w http.ResponseWriter, r *http.Request)
+}
+
+type ConfigPromotionRestHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	userService            user.UserService
+	configPromotionService configPromotion.ConfigPromotionService
+	enforcer               casbin.Enforcer
+	enforcerUtil           rbac.EnforcerUtil
+	validator              *validator.Validate
+}
+
+func NewConfigPromotionRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	configPromotionService configPromotion.ConfigPromotionService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *ConfigPromotionRestHandlerImpl {
+	return &ConfigPromotionRestHandlerImpl{
+		logger:                 logger,
+		userService:            userService,
+		configPromotionService: configPromotionService,
+		enforcer:               enforcer,
+		enforcerUtil:           enforcerUtil,
+		validator:              validator,
+	}
+}
+
+func (handler *ConfigPromotionRestHandlerImpl) GetPromotionDiff(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.PromotionDiffRequest{UserId: userId}
+	request.AppId, err = common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	request.SourceEnvId, err = common.ExtractIntQueryParam(w, r, "sourceEnvId", 0)
+	if err != nil {
+		return
+	}
+	request.TargetEnvId, err = common.ExtractIntQueryParam(w, r, "targetEnvId", 0)
+	if err != nil {
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in GetPromotionDiff", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	appObject := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appObject); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	// secret values are shown to users with admin access on the app only
+	userHasAdminAccess := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, appObject)
+	//RBAC enforcer Ends
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+	ctx := util.SetSuperAdminInContext(r.Context(), isSuperAdmin)
+	resp, err := handler.configPromotionService.GetPromotionDiff(ctx, request, userHasAdminAccess)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPromotionDiff", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ConfigPromotionRestHandlerImpl) Promote(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.PromotionRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, Promote", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in Promote", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, handler.enforcerUtil.GetEnvRBACNameByAppId(request.AppId, request.TargetEnvId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
+	ctx := util.SetSuperAdminInContext(r.Context(), isSuperAdmin)
+	userMetadata := userUtil.GetUserMetadata(ctx, userId, isSuperAdmin)
+	resp, err := handler.configPromotionService.Promote(ctx, request, userMetadata)
+	if err != nil {
+		handler.logger.Errorw("service err, Promote", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
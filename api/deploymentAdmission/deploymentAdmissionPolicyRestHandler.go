@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentAdmission
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type DeploymentAdmissionPolicyRestHandler interface {
+	CreatePolicy(w http.ResponseWriter, r *http.Request)
+	UpdatePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetPolicyById(w http.ResponseWriter, r *http.Request)
+	GetAllPolicies(w http.ResponseWriter, r *http.Request)
+	ValidateExpression(w http.ResponseWriter, r *http.Request)
+	GetExpressionParams(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentAdmissionPolicyRestHandlerImpl struct {
+	logger                           *zap.SugaredLogger
+	userService                      user.UserService
+	deploymentAdmissionPolicyService deploymentAdmission.DeploymentAdmissionPolicyService
+	enforcer                         casbin.Enforcer
+	validator                        *validator.Validate
+}
+
+func NewDeploymentAdmissionPolicyRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	deploymentAdmissionPolicyService deploymentAdmission.DeploymentAdmissionPolicyService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate,
+) *DeploymentAdmissionPolicyRestHandlerImpl {
+	return &DeploymentAdmissionPolicyRestHandlerImpl{
+		logger:                           logger,
+		userService:                      userService,
+		deploymentAdmissionPolicyService: deploymentAdmissionPolicyService,
+		enforcer:                         enforcer,
+		validator:                        validator,
+	}
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodePolicyRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentAdmissionPolicyService.CreatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreatePolicy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	policyId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	request, ok := handler.decodePolicyRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.Id = policyId
+	request.UserId = userId
+	resp, err := handler.deploymentAdmissionPolicyService.UpdatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdatePolicy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	policyId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	err = handler.deploymentAdmissionPolicyService.DeletePolicy(policyId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "policyId", policyId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policyId, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) GetPolicyById(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	policyId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentAdmissionPolicyService.GetPolicyById(policyId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPolicyById", "policyId", policyId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentAdmissionPolicyService.GetAllPolicies()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) ValidateExpression(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ValidateExpressionRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, ValidateExpression", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in ValidateExpression", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	err = handler.deploymentAdmissionPolicyService.ValidateExpression(request.Expression)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, request, http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) GetExpressionParams(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	common.WriteJsonResp(w, nil, handler.deploymentAdmissionPolicyService.GetExpressionParams(), http.StatusOK)
+}
+
+func (handler *DeploymentAdmissionPolicyRestHandlerImpl) decodePolicyRequest(w http.ResponseWriter, r *http.Request) (*bean.DeploymentAdmissionPolicyDto, bool) {
+	request := &bean.DeploymentAdmissionPolicyDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodePolicyRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in decodePolicyRequest", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentAdmission
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentAdmissionPolicyRouter interface {
+	InitDeploymentAdmissionPolicyRouter(configRouter *mux.Router)
+}
+
+type DeploymentAdmissionPolicyRouterImpl struct {
+	deploymentAdmissionPolicyRestHandler DeploymentAdmissionPolicyRestHandler
+}
+
+func NewDeploymentAdmissionPolicyRouterImpl(deploymentAdmissionPolicyRestHandler DeploymentAdmissionPolicyRestHandler) *DeploymentAdmissionPolicyRouterImpl {
+	return &DeploymentAdmissionPolicyRouterImpl{deploymentAdmissionPolicyRestHandler: deploymentAdmissionPolicyRestHandler}
+}
+
+func (router *DeploymentAdmissionPolicyRouterImpl) InitDeploymentAdmissionPolicyRouter(configRouter *mux.Router) {
+	configRouter.Path("/params").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.GetExpressionParams).Methods("GET")
+	configRouter.Path("/validate").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.ValidateExpression).Methods("POST")
+	configRouter.Path("").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.GetAllPolicies).Methods("GET")
+	configRouter.Path("").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.CreatePolicy).Methods("POST")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.GetPolicyById).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.UpdatePolicy).Methods("PUT")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentAdmissionPolicyRestHandler.DeletePolicy).Methods("DELETE")
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentDrift
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type DeploymentDriftRestHandler interface {
+	GetDrift(w http.ResponseWriter, r *http.Request)
+	ScanDrift(w http.ResponseWriter, r *http.Request)
+	UpdateDriftConfig(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentDriftRestHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	userService            user.UserService
+	deploymentDriftService drift.DeploymentDriftService
+	enforcer               casbin.Enforcer
+	enforcerUtil           rbac.EnforcerUtil
+	validator              *validator.Validate
+}
+
+func NewDeploymentDriftRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	deploymentDriftService drift.DeploymentDriftService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *DeploymentDriftRestHandlerImpl {
+	return &DeploymentDriftRestHandlerImpl{
+		logger:                 logger,
+		userService:            userService,
+		deploymentDriftService: deploymentDriftService,
+		enforcer:               enforcer,
+		enforcerUtil:           enforcerUtil,
+		validator:              validator,
+	}
+}
+
+func (handler *DeploymentDriftRestHandlerImpl) GetDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, envId, ok := handler.extractAppIdAndEnvId(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentDriftService.GetDriftDetail(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDrift", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentDriftRestHandlerImpl) ScanDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, envId, ok := handler.extractAppIdAndEnvId(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentDriftService.Scan(r.Context(), appId, envId)
+	if err != nil {
+		handler.logger.Errorw("service err, ScanDrift", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentDriftRestHandlerImpl) UpdateDriftConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.DriftConfigRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, UpdateDriftConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in UpdateDriftConfig", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying, auto reconcile redeploys the pipeline so trigger access on the env is needed
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(request.AppId, request.EnvId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentDriftService.UpdateConfig(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateDriftConfig", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentDriftRestHandlerImpl) extractAppIdAndEnvId(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return 0, 0, false
+	}
+	envId, err := common.ExtractIntPathParam(w, r, "envId")
+	if err != nil {
+		return 0, 0, false
+	}
+	return appId, envId, true
+}
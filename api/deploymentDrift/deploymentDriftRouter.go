@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentDrift
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentDriftRouter interface {
+	InitDeploymentDriftRouter(configRouter *mux.Router)
+}
+
+type DeploymentDriftRouterImpl struct {
+	deploymentDriftRestHandler DeploymentDriftRestHandler
+}
+
+func NewDeploymentDriftRouterImpl(deploymentDriftRestHandler DeploymentDriftRestHandler) *DeploymentDriftRouterImpl {
+	return &DeploymentDriftRouterImpl{deploymentDriftRestHandler: deploymentDriftRestHandler}
+}
+
+func (router *DeploymentDriftRouterImpl) InitDeploymentDriftRouter(configRouter *mux.Router) {
+	configRouter.Path("/config").HandlerFunc(router.deploymentDriftRestHandler.UpdateDriftConfig).Methods("PUT")
+	configRouter.Path("/app/{appId}/env/{envId}").HandlerFunc(router.deploymentDriftRestHandler.GetDrift).Methods("GET")
+	configRouter.Path("/app/{appId}/env/{envId}/scan").HandlerFunc(router.deploymentDriftRestHandler.ScanDrift).Methods("POST")
+}
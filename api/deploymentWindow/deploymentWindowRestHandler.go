@@ -0,0 +1,479 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"time"
+)
+
+type DeploymentWindowRestHandler interface {
+	CreateWindow(w http.ResponseWriter, r *http.Request)
+	UpdateWindow(w http.ResponseWriter, r *http.Request)
+	DeleteWindow(w http.ResponseWriter, r *http.Request)
+	GetWindowById(w http.ResponseWriter, r *http.Request)
+	GetAllWindows(w http.ResponseWriter, r *http.Request)
+	EvaluateWindows(w http.ResponseWriter, r *http.Request)
+
+	RequestException(w http.ResponseWriter, r *http.Request)
+	ApproveException(w http.ResponseWriter, r *http.Request)
+	RejectException(w http.ResponseWriter, r *http.Request)
+	RevokeException(w http.ResponseWriter, r *http.Request)
+	GetExceptionsByPipelineId(w http.ResponseWriter, r *http.Request)
+	GetPendingExceptions(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                           *zap.SugaredLogger
+	userService                      user.UserService
+	deploymentWindowService          deploymentWindow.DeploymentWindowService
+	deploymentWindowExceptionService deploymentWindow.DeploymentWindowExceptionService
+	pipelineRepository               pipelineConfig.PipelineRepository
+	enforcer                         casbin.Enforcer
+	enforcerUtil                     rbac.EnforcerUtil
+	validator                        *validator.Validate
+}
+
+func NewDeploymentWindowRestHandlerImpl(
+	logger *zap.SugaredLogger,
+	userService user.UserService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	deploymentWindowExceptionService deploymentWindow.DeploymentWindowExceptionService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                           logger,
+		userService:                      userService,
+		deploymentWindowService:          deploymentWindowService,
+		deploymentWindowExceptionService: deploymentWindowExceptionService,
+		pipelineRepository:               pipelineRepository,
+		enforcer:                         enforcer,
+		enforcerUtil:                     enforcerUtil,
+		validator:                        validator,
+	}
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) CreateWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeWindowRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentWindowService.CreateWindow(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateWindow", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) UpdateWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	windowId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	request, ok := handler.decodeWindowRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.Id = windowId
+	request.UserId = userId
+	resp, err := handler.deploymentWindowService.UpdateWindow(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateWindow", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) DeleteWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	windowId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	err = handler.deploymentWindowService.DeleteWindow(windowId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteWindow", "windowId", windowId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, windowId, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetWindowById(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	windowId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentWindowService.GetWindowById(windowId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetWindowById", "windowId", windowId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetAllWindows(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentWindowService.GetAllWindows()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllWindows", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) EvaluateWindows(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	cdPipeline, ok := handler.getCdPipelineFromQueryParam(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentWindowService.EvaluateWindows(cdPipeline, time.Now())
+	if err != nil {
+		handler.logger.Errorw("service err, EvaluateWindows", "cdPipelineId", cdPipeline.Id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) RequestException(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ExceptionRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, RequestException", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in RequestException", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying, only users who can trigger the pipeline can request an exception for it
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	object = handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(request.AppId, request.CdPipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentWindowExceptionService.RequestException(request)
+	if err != nil {
+		handler.logger.Errorw("service err, RequestException", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) ApproveException(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentWindowExceptionService.ApproveException(request)
+	if err != nil {
+		handler.logger.Errorw("service err, ApproveException", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) RejectException(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.deploymentWindowExceptionService.RejectException(request)
+	if err != nil {
+		handler.logger.Errorw("service err, RejectException", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) RevokeException(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	exceptionId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	exception, err := handler.deploymentWindowExceptionService.GetExceptionById(exceptionId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetExceptionById", "exceptionId", exceptionId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying, exception can be revoked by the requester or by super admin
+	token := r.Header.Get("token")
+	if exception.CreatedBy != userId {
+		if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return
+		}
+	}
+	//RBAC enforcer Ends
+	err = handler.deploymentWindowExceptionService.RevokeException(exceptionId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, RevokeException", "exceptionId", exceptionId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, exceptionId, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetExceptionsByPipelineId(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	cdPipeline, ok := handler.getCdPipelineFromQueryParam(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentWindowExceptionService.GetExceptionsByPipelineId(cdPipeline.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetExceptionsByPipelineId", "cdPipelineId", cdPipeline.Id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetPendingExceptions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.deploymentWindowExceptionService.GetPendingExceptions()
+	if err != nil {
+		handler.logger.Errorw("service err, GetPendingExceptions", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) decodeWindowRequest(w http.ResponseWriter, r *http.Request) (*bean.DeploymentWindowDto, bool) {
+	request := &bean.DeploymentWindowDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, decodeWindowRequest", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in decodeWindowRequest", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) decodeReviewRequest(w http.ResponseWriter, r *http.Request) (*bean.ExceptionReviewRequest, bool) {
+	exceptionId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return nil, false
+	}
+	request := &bean.ExceptionReviewRequest{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			handler.logger.Errorw("request err, decodeReviewRequest", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	request.Id = exceptionId
+	return request, true
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) getCdPipelineFromQueryParam(w http.ResponseWriter, r *http.Request) (*pipelineConfig.Pipeline, bool) {
+	cdPipelineId, err := common.ExtractIntQueryParam(w, r, "cdPipelineId", 0)
+	if err != nil {
+		return nil, false
+	}
+	if cdPipelineId <= 0 {
+		common.WriteJsonResp(w, errors.New("invalid cdPipelineId"), nil, http.StatusBadRequest)
+		return nil, false
+	}
+	cdPipeline, err := handler.pipelineRepository.FindById(cdPipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching cd pipeline", "cdPipelineId", cdPipelineId, "err", err)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, errors.New("cd pipeline not found"), nil, http.StatusNotFound)
+			return nil, false
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return cdPipeline, true
+}
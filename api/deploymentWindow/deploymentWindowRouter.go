@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(configRouter *mux.Router)
+}
+
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{deploymentWindowRestHandler: deploymentWindowRestHandler}
+}
+
+func (router *DeploymentWindowRouterImpl) InitDeploymentWindowRouter(configRouter *mux.Router) {
+	configRouter.Path("/evaluate").HandlerFunc(router.deploymentWindowRestHandler.EvaluateWindows).Methods("GET")
+
+	configRouter.Path("/exception/pending").HandlerFunc(router.deploymentWindowRestHandler.GetPendingExceptions).Methods("GET")
+	configRouter.Path("/exception").HandlerFunc(router.deploymentWindowRestHandler.GetExceptionsByPipelineId).Methods("GET")
+	configRouter.Path("/exception").HandlerFunc(router.deploymentWindowRestHandler.RequestException).Methods("POST")
+	configRouter.Path("/exception/{id}/approve").HandlerFunc(router.deploymentWindowRestHandler.ApproveException).Methods("PUT")
+	configRouter.Path("/exception/{id}/reject").HandlerFunc(router.deploymentWindowRestHandler.RejectException).Methods("PUT")
+	configRouter.Path("/exception/{id}/revoke").HandlerFunc(router.deploymentWindowRestHandler.RevokeException).Methods("PUT")
+
+	configRouter.Path("").HandlerFunc(router.deploymentWindowRestHandler.GetAllWindows).Methods("GET")
+	configRouter.Path("").HandlerFunc(router.deploymentWindowRestHandler.CreateWindow).Methods("POST")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentWindowRestHandler.GetWindowById).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentWindowRestHandler.UpdateWindow).Methods("PUT")
+	configRouter.Path("/{id}").HandlerFunc(router.deploymentWindowRestHandler.DeleteWindow).Methods("DELETE")
+}
@@ -0,0 +1,363 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ImageSigningRestHandler interface {
+	CreateKey(w http.ResponseWriter, r *http.Request)
+	GetAllKeys(w http.ResponseWriter, r *http.Request)
+	GetKeyById(w http.ResponseWriter, r *http.Request)
+	DeleteKey(w http.ResponseWriter, r *http.Request)
+
+	SaveAppSigningConfig(w http.ResponseWriter, r *http.Request)
+	GetAppSigningConfig(w http.ResponseWriter, r *http.Request)
+	SaveSignaturePolicy(w http.ResponseWriter, r *http.Request)
+	GetSignaturePolicy(w http.ResponseWriter, r *http.Request)
+
+	SignArtifact(w http.ResponseWriter, r *http.Request)
+	GetArtifactSignatures(w http.ResponseWriter, r *http.Request)
+}
+
+type ImageSigningRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	imageSigningService imageSigning.ImageSigningService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+	validator           *validator.Validate
+}
+
+func NewImageSigningRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	imageSigningService imageSigning.ImageSigningService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *ImageSigningRestHandlerImpl {
+	return &ImageSigningRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		imageSigningService: imageSigningService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+		validator:           validator,
+	}
+}
+
+func (handler *ImageSigningRestHandlerImpl) CreateKey(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.SigningKeyRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateKey", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in CreateKey", "name", request.Name, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.UserId = userId
+	resp, err := handler.imageSigningService.CreateKey(request)
+	if err != nil {
+		// request is not logged as it carries the private key
+		handler.logger.Errorw("service err, CreateKey", "name", request.Name, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// GetAllKeys lists the keys with their public keys for every user, so that app admins can choose the signing key
+func (handler *ImageSigningRestHandlerImpl) GetAllKeys(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	resp, err := handler.imageSigningService.GetAllKeys()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllKeys", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetKeyById(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	keyId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.imageSigningService.GetKeyById(keyId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetKeyById", "keyId", keyId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) DeleteKey(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	keyId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	err = handler.imageSigningService.DeleteKey(keyId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteKey", "keyId", keyId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, keyId, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SaveAppSigningConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return
+	}
+	request := &bean.AppSigningConfigDto{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveAppSigningConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in SaveAppSigningConfig", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying, the key an app is signed with decides which signature policies its images pass
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.AppId = appId
+	request.UserId = userId
+	resp, err := handler.imageSigningService.SaveAppSigningConfig(request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveAppSigningConfig", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetAppSigningConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.imageSigningService.GetAppSigningConfig(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAppSigningConfig", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SaveSignaturePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := common.ExtractIntPathParam(w, r, "envId")
+	if err != nil {
+		return
+	}
+	request := &bean.SignaturePolicyDto{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSignaturePolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	request.EnvId = envId
+	request.UserId = userId
+	resp, err := handler.imageSigningService.SaveSignaturePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSignaturePolicy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetSignaturePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := common.ExtractIntPathParam(w, r, "envId")
+	if err != nil {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.imageSigningService.GetSignaturePolicy(envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSignaturePolicy", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SignArtifact(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, artifactId, ok := handler.extractAppAndArtifactId(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying, manual signing can sign any image of the app including the ones of external ci
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.imageSigningService.SignArtifact(appId, artifactId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SignArtifact", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetArtifactSignatures(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, artifactId, ok := handler.extractAppAndArtifactId(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	resp, err := handler.imageSigningService.GetArtifactSignatures(appId, artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetArtifactSignatures", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) extractAppAndArtifactId(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return 0, 0, false
+	}
+	artifactId, err := common.ExtractIntPathParam(w, r, "artifactId")
+	if err != nil {
+		return 0, 0, false
+	}
+	return appId, artifactId, true
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ImageSigningRouter interface {
+	InitImageSigningRouter(configRouter *mux.Router)
+}
+
+type ImageSigningRouterImpl struct {
+	imageSigningRestHandler ImageSigningRestHandler
+}
+
+func NewImageSigningRouterImpl(imageSigningRestHandler ImageSigningRestHandler) *ImageSigningRouterImpl {
+	return &ImageSigningRouterImpl{imageSigningRestHandler: imageSigningRestHandler}
+}
+
+func (router *ImageSigningRouterImpl) InitImageSigningRouter(configRouter *mux.Router) {
+	configRouter.Path("/key").HandlerFunc(router.imageSigningRestHandler.GetAllKeys).Methods("GET")
+	configRouter.Path("/key").HandlerFunc(router.imageSigningRestHandler.CreateKey).Methods("POST")
+	configRouter.Path("/key/{id}").HandlerFunc(router.imageSigningRestHandler.GetKeyById).Methods("GET")
+	configRouter.Path("/key/{id}").HandlerFunc(router.imageSigningRestHandler.DeleteKey).Methods("DELETE")
+
+	configRouter.Path("/app/{appId}/config").HandlerFunc(router.imageSigningRestHandler.GetAppSigningConfig).Methods("GET")
+	configRouter.Path("/app/{appId}/config").HandlerFunc(router.imageSigningRestHandler.SaveAppSigningConfig).Methods("PUT")
+	configRouter.Path("/app/{appId}/artifact/{artifactId}/signature").HandlerFunc(router.imageSigningRestHandler.GetArtifactSignatures).Methods("GET")
+	configRouter.Path("/app/{appId}/artifact/{artifactId}/sign").HandlerFunc(router.imageSigningRestHandler.SignArtifact).Methods("POST")
+
+	configRouter.Path("/env/{envId}/policy").HandlerFunc(router.imageSigningRestHandler.GetSignaturePolicy).Methods("GET")
+	configRouter.Path("/env/{envId}/policy").HandlerFunc(router.imageSigningRestHandler.SaveSignaturePolicy).Methods("PUT")
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incident
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type IncidentRouter interface {
+	InitIncidentRouter(configRouter *mux.Router)
+}
+
+type IncidentRouterImpl struct {
+	incidentRestHandler IncidentRestHandler
+}
+
+func NewIncidentRouterImpl(incidentRestHandler IncidentRestHandler) *IncidentRouterImpl {
+	return &IncidentRouterImpl{incidentRestHandler: incidentRestHandler}
+}
+
+func (router *IncidentRouterImpl) InitIncidentRouter(configRouter *mux.Router) {
+	configRouter.Path("/config").HandlerFunc(router.incidentRestHandler.GetAllConfigs).Methods("GET")
+	configRouter.Path("/config").HandlerFunc(router.incidentRestHandler.SaveConfig).Methods("POST")
+	configRouter.Path("/config/{id}").HandlerFunc(router.incidentRestHandler.DeleteConfig).Methods("DELETE")
+
+	configRouter.Path("").HandlerFunc(router.incidentRestHandler.GetIncidents).Methods("GET")
+}
@@ -597,9 +597,10 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentDelete(w http.ResponseWrite
 	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
 	userMetadata := util.GetUserMetadata(r.Context(), userId, isSuperAdmin)
 	deleteReq := &bean.ConfigDataRequest{
-		Id:     id,
-		AppId:  appId,
-		UserId: userId,
+		Id:            id,
+		AppId:         appId,
+		EnvironmentId: envId,
+		UserId:        userId,
 	}
 	res, err := handler.draftAwareResourceService.CMEnvironmentDelete(ctx, name, deleteReq, userMetadata)
 	if err != nil {
@@ -707,9 +708,10 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentDelete(w http.ResponseWrite
 	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*")
 	userMetadata := util.GetUserMetadata(r.Context(), userId, isSuperAdmin)
 	deleteReq := &bean.ConfigDataRequest{
-		Id:     id,
-		AppId:  appId,
-		UserId: userId,
+		Id:            id,
+		AppId:         appId,
+		EnvironmentId: envId,
+		UserId:        userId,
 	}
 	res, err := handler.draftAwareResourceService.CSEnvironmentDelete(ctx, name, deleteReq, userMetadata)
 	if err != nil {
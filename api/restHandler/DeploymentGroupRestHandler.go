@@ -43,22 +43,28 @@ type DeploymentGroupRestHandler interface {
 	UpdateDeploymentGroup(w http.ResponseWriter, r *http.Request)
 	GetArtifactsByCiPipeline(w http.ResponseWriter, r *http.Request)
 	GetDeploymentGroupById(w http.ResponseWriter, r *http.Request)
+	GetReleasesForDeploymentGroup(w http.ResponseWriter, r *http.Request)
+	GetDeploymentGroupRelease(w http.ResponseWriter, r *http.Request)
+	HandleDeploymentGroupReleaseAction(w http.ResponseWriter, r *http.Request)
 }
 
 type DeploymentGroupRestHandlerImpl struct {
-	deploymentGroupService deploymentGroup.DeploymentGroupService
-	logger                 *zap.SugaredLogger
-	validator              *validator.Validate
-	enforcer               casbin.Enforcer
-	teamService            team.TeamService
-	userAuthService        user.UserService
-	enforcerUtil           rbac.EnforcerUtil
+	deploymentGroupService        deploymentGroup.DeploymentGroupService
+	logger                        *zap.SugaredLogger
+	validator                     *validator.Validate
+	enforcer                      casbin.Enforcer
+	teamService                   team.TeamService
+	userAuthService               user.UserService
+	enforcerUtil                  rbac.EnforcerUtil
+	deploymentGroupReleaseService deploymentGroup.DeploymentGroupReleaseService
 }
 
 func NewDeploymentGroupRestHandlerImpl(deploymentGroupService deploymentGroup.DeploymentGroupService, logger *zap.SugaredLogger,
-	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService, userAuthService user.UserService, enforcerUtil rbac.EnforcerUtil) *DeploymentGroupRestHandlerImpl {
+	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService, userAuthService user.UserService, enforcerUtil rbac.EnforcerUtil,
+	deploymentGroupReleaseService deploymentGroup.DeploymentGroupReleaseService) *DeploymentGroupRestHandlerImpl {
 	return &DeploymentGroupRestHandlerImpl{deploymentGroupService: deploymentGroupService, logger: logger, validator: validator,
-		enforcer: enforcer, teamService: teamService, userAuthService: userAuthService, enforcerUtil: enforcerUtil}
+		enforcer: enforcer, teamService: teamService, userAuthService: userAuthService, enforcerUtil: enforcerUtil,
+		deploymentGroupReleaseService: deploymentGroupReleaseService}
 }
 
 func (impl *DeploymentGroupRestHandlerImpl) CreateDeploymentGroup(w http.ResponseWriter, r *http.Request) {
@@ -420,3 +426,128 @@ func (impl *DeploymentGroupRestHandlerImpl) GetDeploymentGroupById(w http.Respon
 	}
 	common.WriteJsonResp(w, err, deploymentGroup, http.StatusOK)
 }
+
+func (impl *DeploymentGroupRestHandlerImpl) GetReleasesForDeploymentGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deploymentGroupId, err := strconv.Atoi(vars["deploymentGroupId"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetReleasesForDeploymentGroup", "deploymentGroupId", deploymentGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	dg, err := impl.deploymentGroupService.FindById(deploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetReleasesForDeploymentGroup", "err", err, "deploymentGroupId", deploymentGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	resourceName := impl.enforcerUtil.GetTeamRBACByCiPipelineId(dg.CiPipelineId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, resourceName); !ok {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	releases, err := impl.deploymentGroupReleaseService.GetReleases(deploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetReleasesForDeploymentGroup", "err", err, "deploymentGroupId", deploymentGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, releases, http.StatusOK)
+}
+
+func (impl *DeploymentGroupRestHandlerImpl) GetDeploymentGroupRelease(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	releaseId, err := strconv.Atoi(vars["releaseId"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentGroupRelease", "releaseId", releaseId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	release, err := impl.deploymentGroupReleaseService.GetRelease(releaseId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentGroupRelease", "err", err, "releaseId", releaseId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	dg, err := impl.deploymentGroupService.FindById(release.DeploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentGroupRelease", "err", err, "releaseId", releaseId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	resourceName := impl.enforcerUtil.GetTeamRBACByCiPipelineId(dg.CiPipelineId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, resourceName); !ok {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	common.WriteJsonResp(w, err, release, http.StatusOK)
+}
+
+func (impl *DeploymentGroupRestHandlerImpl) HandleDeploymentGroupReleaseAction(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var bean deploymentGroup.DeploymentGroupReleaseActionRequest
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, HandleDeploymentGroupReleaseAction", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	bean.UserId = userId
+	impl.logger.Infow("request payload, HandleDeploymentGroupReleaseAction", "payload", bean)
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, HandleDeploymentGroupReleaseAction", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	release, err := impl.deploymentGroupReleaseService.GetRelease(bean.ReleaseId)
+	if err != nil {
+		impl.logger.Errorw("service err, HandleDeploymentGroupReleaseAction", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	dg, err := impl.deploymentGroupService.FindById(release.DeploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("service err, HandleDeploymentGroupReleaseAction", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	token := r.Header.Get("token")
+	// RBAC enforcer applying, release actions need the same access as triggering the release
+	object := impl.enforcerUtil.GetTeamRBACByCiPipelineId(dg.CiPipelineId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	object = impl.enforcerUtil.GetEnvRBACNameByCiPipelineIdAndEnvId(dg.CiPipelineId, dg.EnvironmentId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	// RBAC enforcer Ends
+
+	res, err := impl.deploymentGroupReleaseService.HandleReleaseAction(&bean)
+	if err != nil {
+		impl.logger.Errorw("service err, HandleDeploymentGroupReleaseAction", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/adapter"
 	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
 	security2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/util"
@@ -45,6 +47,8 @@ type ImageScanRestHandler interface {
 	FetchExecutionDetail(w http.ResponseWriter, r *http.Request)
 	FetchMinScanResultByAppIdAndEnvId(w http.ResponseWriter, r *http.Request)
 	VulnerabilityExposure(w http.ResponseWriter, r *http.Request)
+	RescanDeployedImages(w http.ResponseWriter, r *http.Request)
+	VulnerabilityReport(w http.ResponseWriter, r *http.Request)
 }
 
 type ImageScanRestHandlerImpl struct {
@@ -54,11 +58,15 @@ type ImageScanRestHandlerImpl struct {
 	enforcer           casbin.Enforcer
 	enforcerUtil       rbac.EnforcerUtil
 	environmentService environment.EnvironmentService
+	rescanService      imageScanning.DeployedImageRescanService
+	reportService      imageScanning.VulnerabilityReportService
 }
 
 func NewImageScanRestHandlerImpl(logger *zap.SugaredLogger,
 	imageScanService imageScanning.ImageScanService, userService user.UserService, enforcer casbin.Enforcer,
-	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService) *ImageScanRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService,
+	rescanService imageScanning.DeployedImageRescanService,
+	reportService imageScanning.VulnerabilityReportService) *ImageScanRestHandlerImpl {
 	return &ImageScanRestHandlerImpl{
 		logger:             logger,
 		imageScanService:   imageScanService,
@@ -66,6 +74,8 @@ func NewImageScanRestHandlerImpl(logger *zap.SugaredLogger,
 		enforcer:           enforcer,
 		enforcerUtil:       enforcerUtil,
 		environmentService: environmentService,
+		rescanService:      rescanService,
+		reportService:      reportService,
 	}
 }
 
@@ -380,3 +390,108 @@ func (impl ImageScanRestHandlerImpl) VulnerabilityExposure(w http.ResponseWriter
 	results.VulnerabilityExposure = vulnerabilityExposure
 	common.WriteJsonResp(w, err, results, http.StatusOK)
 }
+
+// RescanDeployedImages triggers a re-scan of deployed images without waiting for the scheduled run, the re-scans are
+// processed asynchronously
+func (impl ImageScanRestHandlerImpl) RescanDeployedImages(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	go impl.rescanService.RescanDeployedImages()
+	common.WriteJsonResp(w, nil, "deployed image rescan triggered", http.StatusOK)
+}
+
+// VulnerabilityReport returns the trend of open cves, mean time to remediate and sla breaches of the apps the user can view,
+// format=csv downloads the section (trend, remediation or sla) of the report as csv
+func (impl ImageScanRestHandlerImpl) VulnerabilityReport(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	queryParams := r.URL.Query()
+	request := &securityBean.VulnerabilityReportRequest{
+		Interval:       securityBean.VulnerabilityTrendInterval(queryParams.Get("interval")),
+		CheckAuthBatch: impl.checkAuthBatch,
+	}
+	if request.From, err = parseReportTime(queryParams.Get("from")); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.To, err = parseReportTime(queryParams.Get("to")); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.TeamIds, err = common.ExtractIntArrayFromQueryParam(r, "teamIds"); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.EnvIds, err = common.ExtractIntArrayFromQueryParam(r, "envIds"); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.AppIds, err = common.ExtractIntArrayFromQueryParam(r, "appIds"); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.ProdOnly, err = common.ExtractBoolQueryParam(r, "prodOnly"); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	isCsv := queryParams.Get("format") == "csv"
+	section := securityBean.VulnerabilityReportSection(queryParams.Get("section"))
+	if len(section) == 0 {
+		section = securityBean.VulnerabilityReportSectionTrend
+	}
+	if isCsv && !section.IsValid() {
+		common.WriteJsonResp(w, fmt.Errorf("invalid section %s", section), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	report, err := impl.reportService.GetVulnerabilityReport(request, token)
+	if err != nil {
+		impl.logger.Errorw("service err, VulnerabilityReport", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !isCsv {
+		common.WriteJsonResp(w, nil, report, http.StatusOK)
+		return
+	}
+	w.Header().Set(common.CONTENT_DISPOSITION, fmt.Sprintf("attachment; filename=vulnerability-%s.csv", section))
+	w.Header().Set(common.CONTENT_TYPE, "text/csv")
+	w.WriteHeader(http.StatusOK)
+	if err = adapter.WriteVulnerabilityReportCsv(w, report, section); err != nil {
+		impl.logger.Errorw("error in writing vulnerability report", "section", section, "err", err)
+	}
+}
+
+// parseReportTime parses the report time range given as a date (2006-01-02) or in RFC3339, zero time is returned if not set
+func parseReportTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	if reportTime, err := time.Parse(time.DateOnly, value); err == nil {
+		return reportTime, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (impl ImageScanRestHandlerImpl) checkAuthBatch(token string, appObject []string, envObject []string) (map[string]bool, map[string]bool) {
+	var appResult map[string]bool
+	var envResult map[string]bool
+	if len(appObject) > 0 {
+		appResult = impl.enforcer.EnforceInBatch(token, casbin.ResourceApplications, casbin.ActionGet, appObject)
+	}
+	if len(envObject) > 0 {
+		envResult = impl.enforcer.EnforceInBatch(token, casbin.ResourceEnvironment, casbin.ActionGet, envObject)
+	}
+	return appResult, envResult
+}
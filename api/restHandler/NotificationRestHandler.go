@@ -48,6 +48,7 @@ const (
 	WEBHOOK_CONFIG_DELETE_SUCCESS_RESP = "Webhook config deleted successfully."
 	SES_CONFIG_DELETE_SUCCESS_RESP     = "SES config deleted successfully."
 	SMTP_CONFIG_DELETE_SUCCESS_RESP    = "SMTP config deleted successfully."
+	TEAMS_CONFIG_DELETE_SUCCESS_RESP   = "Teams config deleted successfully."
 )
 
 type NotificationRestHandler interface {
@@ -58,6 +59,7 @@ type NotificationRestHandler interface {
 	FindSlackConfig(w http.ResponseWriter, r *http.Request)
 	FindSMTPConfig(w http.ResponseWriter, r *http.Request)
 	FindWebhookConfig(w http.ResponseWriter, r *http.Request)
+	FindTeamsConfig(w http.ResponseWriter, r *http.Request)
 	GetWebhookVariables(w http.ResponseWriter, r *http.Request)
 	FindAllNotificationConfig(w http.ResponseWriter, r *http.Request)
 	GetAllNotificationSettings(w http.ResponseWriter, r *http.Request)
@@ -79,6 +81,7 @@ type NotificationRestHandlerImpl struct {
 	webhookService       notifier.WebhookNotificationService
 	sesService           notifier.SESNotificationService
 	smtpService          notifier.SMTPNotificationService
+	teamsService         notifier.TeamsNotificationService
 	enforcer             casbin.Enforcer
 	environmentService   environment.EnvironmentService
 	pipelineBuilder      pipeline.PipelineBuilder
@@ -97,7 +100,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 	slackService notifier.SlackNotificationService, webhookService notifier.WebhookNotificationService, sesService notifier.SESNotificationService, smtpService notifier.SMTPNotificationService,
 	enforcer casbin.Enforcer, environmentService environment.EnvironmentService, pipelineBuilder pipeline.PipelineBuilder,
 	enforcerUtil rbac.EnforcerUtil,
-	teamReadService read.TeamReadService) *NotificationRestHandlerImpl {
+	teamReadService read.TeamReadService, teamsService notifier.TeamsNotificationService) *NotificationRestHandlerImpl {
 	return &NotificationRestHandlerImpl{
 		dockerRegistryConfig: dockerRegistryConfig,
 		logger:               logger,
@@ -109,6 +112,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 		webhookService:       webhookService,
 		sesService:           sesService,
 		smtpService:          smtpService,
+		teamsService:         teamsService,
 		enforcer:             enforcer,
 		environmentService:   environmentService,
 		pipelineBuilder:      pipelineBuilder,
@@ -417,6 +421,48 @@ func (impl NotificationRestHandlerImpl) SaveNotificationChannelConfig(w http.Res
 		}
 		w.Header().Set("Content-Type", "application/json")
 		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else if util.Teams == channelReq.Channel {
+		var teamsReq *beans.TeamsChannelConfig
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&teamsReq)
+		if err != nil {
+			impl.logger.Errorw("request err, SaveNotificationChannelConfig", "err", err, "teamsReq", teamsReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(teamsReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, SaveNotificationChannelConfig", "err", err, "teamsReq", teamsReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		//RBAC
+		var teamIds []*int
+		for _, item := range teamsReq.TeamsConfigDtos {
+			teamIds = append(teamIds, &item.TeamId)
+		}
+		teams, err := impl.teamReadService.FindByIds(teamIds)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		for _, item := range teams {
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, fmt.Sprintf("%s/*", item.Name)); !ok {
+				common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+				return
+			}
+		}
+		//RBAC
+
+		res, cErr := impl.teamsService.SaveOrEditNotificationConfig(teamsReq.TeamsConfigDtos, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, SaveNotificationChannelConfig", "err", cErr, "teamsReq", teamsReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
 	}
 }
 
@@ -425,6 +471,7 @@ type ChannelResponseDTO struct {
 	WebhookConfigs []*beans.WebhookConfigDto `json:"webhookConfigs"`
 	SESConfigs     []*beans.SESConfigDto     `json:"sesConfigs"`
 	SMTPConfigs    []*beans.SMTPConfigDto    `json:"smtpConfigs"`
+	TeamsConfigs   []*beans.TeamsConfigDto   `json:"teamsConfigs"`
 }
 
 func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.ResponseWriter, r *http.Request) {
@@ -511,6 +558,39 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.Respons
 	if pass {
 		channelsResponse.SMTPConfigs = smtpConfigs
 	}
+
+	teamsConfigs, err := impl.teamsService.FetchAllTeamsNotificationConfig()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("service err, FindAllNotificationConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//RBAC
+	authorizedTeamsConfigs := make([]*beans.TeamsConfigDto, 0, len(teamsConfigs))
+	if len(teamsConfigs) > 0 {
+		var teamIds []*int
+		for _, item := range teamsConfigs {
+			teamIds = append(teamIds, &item.TeamId)
+		}
+		teams, err := impl.teamReadService.FindByIds(teamIds)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		authorizedTeamIds := make(map[int]bool)
+		for _, item := range teams {
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", item.Name)); ok {
+				authorizedTeamIds[item.Id] = true
+			}
+		}
+		for _, item := range teamsConfigs {
+			if authorizedTeamIds[item.TeamId] {
+				authorizedTeamsConfigs = append(authorizedTeamsConfigs, item)
+			}
+		}
+	}
+	//RBAC
+	channelsResponse.TeamsConfigs = authorizedTeamsConfigs
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, channelsResponse, http.StatusOK)
 }
@@ -620,6 +700,48 @@ func (impl NotificationRestHandlerImpl) FindWebhookConfig(w http.ResponseWriter,
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, webhookConfig, http.StatusOK)
 }
+func (impl NotificationRestHandlerImpl) FindTeamsConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, FindTeamsConfig", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	teamsConfig, fErr := impl.teamsService.FetchTeamsNotificationConfigById(id)
+	if fErr != nil {
+		impl.logger.Errorw("service err, FindTeamsConfig, cannot find teams config", "err", fErr, "id", id)
+		if fErr == pg.ErrNoRows {
+			common.WriteJsonResp(w, fErr, nil, http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, fErr, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	team, err := impl.teamReadService.FindOne(teamsConfig.TeamId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", team.Name)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	w.Header().Set("Content-Type", "application/json")
+	common.WriteJsonResp(w, nil, teamsConfig, http.StatusOK)
+}
+
 func (impl NotificationRestHandlerImpl) GetWebhookVariables(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -659,6 +781,13 @@ func (impl NotificationRestHandlerImpl) RecipientListingSuggestion(w http.Respon
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	teamsChannelsResponse, err := impl.teamsService.RecipientListingSuggestion(value)
+	if err != nil {
+		impl.logger.Errorw("service err, RecipientListingSuggestion", "err", err, "value", value)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	channelsResponse = append(channelsResponse, teamsChannelsResponse...)
 
 	if channelsResponse == nil {
 		channelsResponse = make([]*beans.NotificationRecipientListingResponse, 0)
@@ -703,6 +832,23 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfigAutocomplete(w
 			}
 		}
 
+	} else if cType == string(util.Teams) {
+		channelsResponseAll, err := impl.teamsService.FetchAllTeamsNotificationConfigAutocomplete()
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("service err, FindAllNotificationConfigAutocomplete", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		for _, item := range channelsResponseAll {
+			team, err := impl.teamReadService.FindOne(item.TeamId)
+			if err != nil {
+				common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+				return
+			}
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, fmt.Sprintf("%s/*", team.Name)); ok {
+				channelsResponse = append(channelsResponse, item)
+			}
+		}
 	} else if cType == string(util.Webhook) {
 		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
 			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
@@ -858,6 +1004,30 @@ func (impl NotificationRestHandlerImpl) DeleteNotificationChannelConfig(w http.R
 			return
 		}
 		common.WriteJsonResp(w, nil, WEBHOOK_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
+	} else if util.Teams == channelReq.Channel {
+		var deleteReq *beans.TeamsConfigDto
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
+		if err != nil {
+			impl.logger.Errorw("request err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		token := r.Header.Get("token")
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		cErr := impl.teamsService.DeleteNotificationConfig(deleteReq, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, DeleteNotificationChannelConfig", "err", cErr, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		common.WriteJsonResp(w, nil, TEAMS_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
 	} else if util.SES == channelReq.Channel {
 		var deleteReq *beans.SESConfigDto
 		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
@@ -20,18 +20,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
 	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/adapter"
+	imageScanningBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
 	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	user2 "github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +47,14 @@ type PolicyRestHandler interface {
 	UpdatePolicy(w http.ResponseWriter, r *http.Request)
 	GetPolicy(w http.ResponseWriter, r *http.Request)
 	VerifyImage(w http.ResponseWriter, r *http.Request)
+	CreateVulnerabilityOverride(w http.ResponseWriter, r *http.Request)
+	RevokeVulnerabilityOverride(w http.ResponseWriter, r *http.Request)
+	GetVulnerabilityOverrides(w http.ResponseWriter, r *http.Request)
+	CreateCveException(w http.ResponseWriter, r *http.Request)
+	RenewCveException(w http.ResponseWriter, r *http.Request)
+	RevokeCveException(w http.ResponseWriter, r *http.Request)
+	GetCveException(w http.ResponseWriter, r *http.Request)
+	GetCveExceptionReport(w http.ResponseWriter, r *http.Request)
 }
 type PolicyRestHandlerImpl struct {
 	logger             *zap.SugaredLogger
@@ -49,21 +64,31 @@ type PolicyRestHandlerImpl struct {
 	enforcer           casbin.Enforcer
 	enforcerUtil       rbac.EnforcerUtil
 	environmentService environment.EnvironmentService
+
+	vulnerabilityOverrideService imageScanning.VulnerabilityOverrideService
+	pipelineRepository           pipelineConfig.PipelineRepository
+	cveExceptionService          imageScanning.CveExceptionService
 }
 
 func NewPolicyRestHandlerImpl(logger *zap.SugaredLogger,
 	policyService imageScanning.PolicyService,
 	userService user2.UserService, userAuthService user2.UserAuthService,
 	enforcer casbin.Enforcer,
-	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService) *PolicyRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService,
+	vulnerabilityOverrideService imageScanning.VulnerabilityOverrideService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cveExceptionService imageScanning.CveExceptionService) *PolicyRestHandlerImpl {
 	return &PolicyRestHandlerImpl{
-		logger:             logger,
-		policyService:      policyService,
-		userService:        userService,
-		userAuthService:    userAuthService,
-		enforcer:           enforcer,
-		enforcerUtil:       enforcerUtil,
-		environmentService: environmentService,
+		logger:                       logger,
+		policyService:                policyService,
+		userService:                  userService,
+		userAuthService:              userAuthService,
+		enforcer:                     enforcer,
+		enforcerUtil:                 enforcerUtil,
+		environmentService:           environmentService,
+		vulnerabilityOverrideService: vulnerabilityOverrideService,
+		pipelineRepository:           pipelineRepository,
+		cveExceptionService:          cveExceptionService,
 	}
 }
 
@@ -321,3 +346,376 @@ func (impl PolicyRestHandlerImpl) VerifyImage(w http.ResponseWriter, r *http.Req
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
+
+func (impl PolicyRestHandlerImpl) CreateVulnerabilityOverride(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req imageScanningBean.VulnerabilityOverrideRequest
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateVulnerabilityOverride", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	req.UserId = userId
+	impl.logger.Infow("request payload, CreateVulnerabilityOverride", "payload", req)
+	cdPipeline, err := impl.pipelineRepository.FindById(req.CdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateVulnerabilityOverride", "err", err, "cdPipelineId", req.CdPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//AUTH - only app admin can override vulnerability check
+	token := r.Header.Get("token")
+	if ok := impl.isAuthorisedToCreateOnApp(token, cdPipeline.AppId, cdPipeline.EnvironmentId); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.vulnerabilityOverrideService.CreateOverride(&req)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateVulnerabilityOverride", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) RevokeVulnerabilityOverride(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	overrideId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, RevokeVulnerabilityOverride", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	override, err := impl.vulnerabilityOverrideService.GetOverrideById(overrideId)
+	if util.IsErrNoRows(err) {
+		common.WriteJsonResp(w, err, "override not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		impl.logger.Errorw("service err, RevokeVulnerabilityOverride", "err", err, "overrideId", overrideId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(override.CdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, RevokeVulnerabilityOverride", "err", err, "cdPipelineId", override.CdPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//AUTH
+	token := r.Header.Get("token")
+	if ok := impl.isAuthorisedToCreateOnApp(token, cdPipeline.AppId, cdPipeline.EnvironmentId); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	err = impl.vulnerabilityOverrideService.RevokeOverride(overrideId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RevokeVulnerabilityOverride", "err", err, "overrideId", overrideId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "override revoked successfully", http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) GetVulnerabilityOverrides(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	cdPipelineId, err := strconv.Atoi(r.URL.Query().Get("cdPipelineId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetVulnerabilityOverrides", "err", err, "cdPipelineId", r.URL.Query().Get("cdPipelineId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetVulnerabilityOverrides", "err", err, "cdPipelineId", cdPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//AUTH
+	token := r.Header.Get("token")
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.vulnerabilityOverrideService.GetOverridesByPipelineId(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetVulnerabilityOverrides", "err", err, "cdPipelineId", cdPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// isAuthorisedToCreateOnApp checks create access on the app and, if envId is set, on the environment of the app,
+// required for the vulnerability overrides of a cd pipeline and the cve exceptions of an app
+func (impl PolicyRestHandlerImpl) isAuthorisedToCreateOnApp(token string, appId, envId int) bool {
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, object); !ok {
+		return false
+	}
+	if envId == 0 {
+		return true
+	}
+	object = impl.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	return impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionCreate, object)
+}
+
+func (impl PolicyRestHandlerImpl) CreateCveException(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req imageScanningBean.CveExceptionRequest
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateCveException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	req.UserId = userId
+	impl.logger.Infow("request payload, CreateCveException", "payload", req)
+	//AUTH
+	token := r.Header.Get("token")
+	if ok, err := impl.isAuthorisedForCveException(token, userId, req.AppId, req.EnvId); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.cveExceptionService.CreateException(&req)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateCveException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) RenewCveException(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	exceptionId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	var req imageScanningBean.CveExceptionRenewRequest
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, RenewCveException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	req.Id = exceptionId
+	req.UserId = userId
+	if ok := impl.authoriseCveExceptionUpdate(w, r, userId, exceptionId); !ok {
+		return
+	}
+	res, err := impl.cveExceptionService.RenewException(&req)
+	if err != nil {
+		impl.logger.Errorw("service err, RenewCveException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) RevokeCveException(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	exceptionId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	var req imageScanningBean.CveExceptionRevokeRequest
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, RevokeCveException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if len(strings.TrimSpace(req.Reason)) == 0 {
+		common.WriteJsonResp(w, errors.New("reason is required for revoking a cve exception"), nil, http.StatusBadRequest)
+		return
+	}
+	req.Id = exceptionId
+	req.UserId = userId
+	if ok := impl.authoriseCveExceptionUpdate(w, r, userId, exceptionId); !ok {
+		return
+	}
+	err = impl.cveExceptionService.RevokeException(&req)
+	if err != nil {
+		impl.logger.Errorw("service err, RevokeCveException", "err", err, "exceptionId", exceptionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "cve exception revoked successfully", http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) GetCveException(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	exceptionId, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	exception, err := impl.cveExceptionService.GetExceptionById(exceptionId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//AUTH
+	token := r.Header.Get("token")
+	if ok, err := impl.isAuthorisedToViewCveExceptions(token, userId, exception.AppId); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.cveExceptionService.GetException(exceptionId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetCveException", "err", err, "exceptionId", exceptionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetCveExceptionReport lists the active cve exceptions, format=csv downloads the report as csv
+func (impl PolicyRestHandlerImpl) GetCveExceptionReport(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	queryParams := r.URL.Query()
+	req := &imageScanningBean.CveExceptionReportRequest{
+		CveName:       queryParams.Get("cveName"),
+		ExceptionType: repository.CveExceptionType(queryParams.Get("exceptionType")),
+	}
+	if req.ClusterId, err = common.ExtractIntQueryParam(w, r, "clusterId", 0); err != nil {
+		return
+	}
+	if req.EnvId, err = common.ExtractIntQueryParam(w, r, "envId", 0); err != nil {
+		return
+	}
+	if req.AppId, err = common.ExtractIntQueryParam(w, r, "appId", 0); err != nil {
+		return
+	}
+	if req.ExpiringInDays, err = common.ExtractIntQueryParam(w, r, "expiringInDays", 0); err != nil {
+		return
+	}
+	if req.IncludeInactive, err = common.ExtractBoolQueryParam(r, "includeInactive"); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//AUTH - exceptions of an app can be viewed by its viewers, rest of the report is restricted to super admins
+	token := r.Header.Get("token")
+	if ok, err := impl.isAuthorisedToViewCveExceptions(token, userId, req.AppId); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.cveExceptionService.GetExceptionReport(req)
+	if err != nil {
+		impl.logger.Errorw("service err, GetCveExceptionReport", "err", err, "request", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if queryParams.Get("format") != "csv" {
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
+		return
+	}
+	w.Header().Set(common.CONTENT_DISPOSITION, "attachment; filename=cve-exceptions.csv")
+	w.Header().Set(common.CONTENT_TYPE, "text/csv")
+	w.WriteHeader(http.StatusOK)
+	if err = adapter.WriteCveExceptionReportCsv(w, res); err != nil {
+		impl.logger.Errorw("error in writing cve exception report", "err", err)
+	}
+}
+
+// authoriseCveExceptionUpdate writes the error response and returns false if the user cannot modify the exception
+func (impl PolicyRestHandlerImpl) authoriseCveExceptionUpdate(w http.ResponseWriter, r *http.Request, userId int32, exceptionId int) bool {
+	exception, err := impl.cveExceptionService.GetExceptionById(exceptionId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	token := r.Header.Get("token")
+	ok, err := impl.isAuthorisedForCveException(token, userId, exception.AppId, exception.EnvId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// isAuthorisedForCveException mirrors the access required for saving a cve policy on the same scope
+func (impl PolicyRestHandlerImpl) isAuthorisedForCveException(token string, userId int32, appId, envId int) (bool, error) {
+	if appId > 0 {
+		return impl.isAuthorisedToCreateOnApp(token, appId, envId), nil
+	} else if envId > 0 {
+		return impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionCreate, "*"), nil
+	}
+	return impl.isSuperAdmin(userId)
+}
+
+func (impl PolicyRestHandlerImpl) isAuthorisedToViewCveExceptions(token string, userId int32, appId int) (bool, error) {
+	if appId > 0 {
+		object := impl.enforcerUtil.GetAppRBACNameByAppId(appId)
+		return impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object), nil
+	}
+	return impl.isSuperAdmin(userId)
+}
+
+func (impl PolicyRestHandlerImpl) isSuperAdmin(userId int32) (bool, error) {
+	roles, err := impl.userService.CheckUserRoles(userId, "")
+	if err != nil {
+		return false, err
+	}
+	for _, item := range roles {
+		if item == bean2.SUPERADMIN {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -45,6 +45,7 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	bean4 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/deployedApp/status/resourceTree"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift"
 	"github.com/devtron-labs/devtron/pkg/deploymentGroup"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	k8sApplication "github.com/devtron-labs/devtron/pkg/k8s/application"
@@ -98,6 +99,7 @@ type AppListingRestHandlerImpl struct {
 	k8sApplicationService       k8sApplication.K8sApplicationService
 	deploymentConfigService     common2.DeploymentConfigService
 	resourceTreeService         resourceTree.Service
+	deploymentDriftService      drift.DeploymentDriftService
 }
 
 type AppStatus struct {
@@ -125,7 +127,8 @@ func NewAppListingRestHandlerImpl(appListingService app.AppListingService,
 	pipelineRepository pipelineConfig.PipelineRepository,
 	k8sApplicationService k8sApplication.K8sApplicationService,
 	deploymentConfigService common2.DeploymentConfigService,
-	resourceTreeService resourceTree.Service) *AppListingRestHandlerImpl {
+	resourceTreeService resourceTree.Service,
+	deploymentDriftService drift.DeploymentDriftService) *AppListingRestHandlerImpl {
 	appListingHandler := &AppListingRestHandlerImpl{
 		appListingService:           appListingService,
 		logger:                      logger,
@@ -141,6 +144,7 @@ func NewAppListingRestHandlerImpl(appListingService app.AppListingService,
 		k8sApplicationService:       k8sApplicationService,
 		deploymentConfigService:     deploymentConfigService,
 		resourceTreeService:         resourceTreeService,
+		deploymentDriftService:      deploymentDriftService,
 	}
 	return appListingHandler
 }
@@ -514,6 +518,12 @@ func (handler AppListingRestHandlerImpl) FetchAppDetailsV2(w http.ResponseWriter
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	// drift is informational, app details are served without it on error
+	appDetail.Drift, err = handler.deploymentDriftService.GetDriftSummary(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching deployment drift, FetchAppDetailsV2", "appId", appId, "envId", envId, "err", err)
+		err = nil
+	}
 	common.WriteJsonResp(w, err, appDetail, http.StatusOK)
 }
 
@@ -28,6 +28,7 @@ import (
 
 	devtronAppGitOpConfigBean "github.com/devtron-labs/devtron/pkg/chart/gitOpsConfig/bean"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
 	"io"
 	"net/http"
 	"strconv"
@@ -1213,6 +1214,10 @@ func (handler *PipelineConfigRestHandlerImpl) GetArtifactsByCDPipeline(w http.Re
 		if err != nil {
 			handler.Logger.Errorw("service err, GetArtifactsByCDPipeline", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
 		}
+		cveExceptions, err := handler.policyService.GetCveExceptions(pipeline.Environment.ClusterId, pipeline.EnvironmentId, pipeline.AppId)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetCveExceptions", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
+		}
 
 		// get image scan results from DB for given digests
 		imageScanResults, err := handler.imageScanResultReadService.FindByImageDigests(digests)
@@ -1251,7 +1256,8 @@ func (handler *PipelineConfigRestHandlerImpl) GetArtifactsByCDPipeline(w http.Re
 			}
 
 			cveStores, _ := digestVsCveStores[item.ImageDigest]
-			item.IsVulnerable = handler.policyService.HasBlockedCVE(cveStores, cvePolicy, severityPolicy)
+			exceptedCves := cveExceptions.GetExceptedCves(&securityBean.ImageIdentifier{ImageDigest: item.ImageDigest, CiArtifactId: item.Id})
+			item.IsVulnerable = handler.policyService.HasBlockedCVE(cveStores, cvePolicy, severityPolicy, exceptedCves)
 			ciArtifactsFinal = append(ciArtifactsFinal, item)
 		}
 		ciArtifactResponse.CiArtifacts = ciArtifactsFinal
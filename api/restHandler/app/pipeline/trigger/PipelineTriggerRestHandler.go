@@ -156,6 +156,9 @@ func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWrit
 		return
 	}
 	res := map[string]interface{}{"releaseId": mergeResp, "helmPackageName": helmPackageName}
+	if len(overrideRequest.AdmissionPolicyWarnings) > 0 {
+		res["admissionPolicyWarnings"] = overrideRequest.AdmissionPolicyWarnings
+	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
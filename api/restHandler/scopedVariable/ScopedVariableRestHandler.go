@@ -30,6 +30,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
+	"github.com/devtron-labs/devtron/pkg/variables/impact"
 	"github.com/devtron-labs/devtron/pkg/variables/models"
 	"github.com/devtron-labs/devtron/pkg/variables/utils"
 	"github.com/devtron-labs/devtron/util"
@@ -42,6 +43,9 @@ type ScopedVariableRestHandler interface {
 	CreateVariables(w http.ResponseWriter, r *http.Request)
 	GetScopedVariables(w http.ResponseWriter, r *http.Request)
 	GetJsonForVariables(w http.ResponseWriter, r *http.Request)
+	DryRunVariables(w http.ResponseWriter, r *http.Request)
+	GetVariableImpact(w http.ResponseWriter, r *http.Request)
+	ApplyVariables(w http.ResponseWriter, r *http.Request)
 }
 
 type ScopedVariableRestHandlerImpl struct {
@@ -52,13 +56,14 @@ type ScopedVariableRestHandlerImpl struct {
 	enforcerUtil          rbac.EnforcerUtil
 	enforcer              casbin.Enforcer
 	scopedVariableService variables.ScopedVariableService
+	variableImpactService impact.VariableImpactService
 }
 type JsonResponse struct {
 	Manifest   *models.ScopedVariableManifest `json:"manifest"`
 	JsonSchema string                         `json:"jsonSchema"`
 }
 
-func NewScopedVariableRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate, pipelineBuilder pipeline.PipelineBuilder, enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, scopedVariableService variables.ScopedVariableService) *ScopedVariableRestHandlerImpl {
+func NewScopedVariableRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate, pipelineBuilder pipeline.PipelineBuilder, enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, scopedVariableService variables.ScopedVariableService, variableImpactService impact.VariableImpactService) *ScopedVariableRestHandlerImpl {
 	return &ScopedVariableRestHandlerImpl{
 		logger:                logger,
 		userAuthService:       userAuthService,
@@ -67,6 +72,7 @@ func NewScopedVariableRestHandlerImpl(logger *zap.SugaredLogger, userAuthService
 		enforcerUtil:          enforcerUtil,
 		enforcer:              enforcer,
 		scopedVariableService: scopedVariableService,
+		variableImpactService: variableImpactService,
 	}
 }
 func (handler *ScopedVariableRestHandlerImpl) CreateVariables(w http.ResponseWriter, r *http.Request) {
@@ -206,3 +212,107 @@ func (handler *ScopedVariableRestHandlerImpl) GetJsonForVariables(w http.Respons
 	}
 	common.WriteJsonResp(w, nil, jsonResponse, http.StatusOK)
 }
+
+func (handler *ScopedVariableRestHandlerImpl) DryRunVariables(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := models.VariableRequest{}
+	decoder.UseNumber()
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, DryRunVariables", "error", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in DryRunVariables", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusNotAcceptable)
+		return
+	}
+
+	payload := utils.ManifestToPayload(request.Manifest, userId)
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	response, err := handler.scopedVariableService.DryRunVariables(payload)
+	if err != nil {
+		if errors.As(err, &models.ValidationError{}) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotAcceptable)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *ScopedVariableRestHandlerImpl) GetVariableImpact(w http.ResponseWriter, r *http.Request) {
+	handler.handleVariableImpact(w, r, false)
+}
+
+func (handler *ScopedVariableRestHandlerImpl) ApplyVariables(w http.ResponseWriter, r *http.Request) {
+	handler.handleVariableImpact(w, r, true)
+}
+
+// handleVariableImpact previews the impact of the proposed variables, and saves them if apply is set
+func (handler *ScopedVariableRestHandlerImpl) handleVariableImpact(w http.ResponseWriter, r *http.Request, apply bool) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := models.VariableImpactRequest{}
+	decoder.UseNumber()
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, VariableImpact", "error", err, "apply", apply)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in VariableImpact", "err", err, "apply", apply)
+		common.WriteJsonResp(w, err, nil, http.StatusNotAcceptable)
+		return
+	}
+
+	payload := utils.ManifestToPayload(request.Manifest, userId)
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	var response *models.VariableImpactResponse
+	if apply {
+		response, err = handler.variableImpactService.ApplyVariables(payload, request.Redeploy)
+	} else {
+		response, err = handler.variableImpactService.GetVariableImpact(payload)
+	}
+	if err != nil {
+		if errors.As(err, &models.ValidationError{}) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotAcceptable)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
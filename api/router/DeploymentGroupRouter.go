@@ -43,5 +43,8 @@ func (router DeploymentGroupRouterImpl) initDeploymentGroupRouter(configRouter *
 	configRouter.Path("/dg/update").HandlerFunc(router.restHandler.UpdateDeploymentGroup).Methods("PUT")
 	configRouter.Path("/dg/material/{deploymentGroupId}").HandlerFunc(router.restHandler.GetArtifactsByCiPipeline).Methods("GET")
 	configRouter.Path("/dg/{deploymentGroupId}").HandlerFunc(router.restHandler.GetDeploymentGroupById).Methods("GET")
+	configRouter.Path("/dg/{deploymentGroupId}/releases").HandlerFunc(router.restHandler.GetReleasesForDeploymentGroup).Methods("GET")
+	configRouter.Path("/dg/release/{releaseId}").HandlerFunc(router.restHandler.GetDeploymentGroupRelease).Methods("GET")
+	configRouter.Path("/dg/release/action").HandlerFunc(router.restHandler.HandleDeploymentGroupReleaseAction).Methods("POST")
 
 }
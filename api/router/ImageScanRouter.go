@@ -41,4 +41,9 @@ func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
 
 	configRouter.Path("/cve/exposure").HandlerFunc(impl.imageScanRestHandler.VulnerabilityExposure).Methods("POST")
 
+	configRouter.Path("/deployed-images/rescan").HandlerFunc(impl.imageScanRestHandler.RescanDeployedImages).Methods("POST")
+
+	//from=2024-01-01&to=2024-02-01&interval=week&teamIds=1,2&envIds=3&appIds=4&prodOnly=true&format=csv&section=sla
+	configRouter.Path("/vulnerability/report").HandlerFunc(impl.imageScanRestHandler.VulnerabilityReport).Methods("GET")
+
 }
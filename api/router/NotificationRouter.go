@@ -70,6 +70,9 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 	configRouter.Path("/channel/webhook/{id}").
 		HandlerFunc(impl.notificationRestHandler.FindWebhookConfig).
 		Methods("GET")
+	configRouter.Path("/channel/teams/{id}").
+		HandlerFunc(impl.notificationRestHandler.FindTeamsConfig).
+		Methods("GET")
 	configRouter.Path("/variables").
 		HandlerFunc(impl.notificationRestHandler.GetWebhookVariables).
 		Methods("GET")
@@ -38,4 +38,13 @@ func (impl PolicyRouterImpl) InitPolicyRouter(configRouter *mux.Router) {
 	configRouter.Path("/update").HandlerFunc(impl.policyRestHandler.UpdatePolicy).Methods("POST")
 	configRouter.Path("/list").HandlerFunc(impl.policyRestHandler.GetPolicy).Methods("GET")
 	configRouter.Path("/verify/webhook").HandlerFunc(impl.policyRestHandler.VerifyImage).Methods("POST")
+	configRouter.Path("/vulnerability-override").HandlerFunc(impl.policyRestHandler.CreateVulnerabilityOverride).Methods("POST")
+	configRouter.Path("/vulnerability-override").HandlerFunc(impl.policyRestHandler.GetVulnerabilityOverrides).
+		Queries("cdPipelineId", "{cdPipelineId}").Methods("GET")
+	configRouter.Path("/vulnerability-override/{id}/revoke").HandlerFunc(impl.policyRestHandler.RevokeVulnerabilityOverride).Methods("PUT")
+	configRouter.Path("/cve-exception").HandlerFunc(impl.policyRestHandler.CreateCveException).Methods("POST")
+	configRouter.Path("/cve-exception/report").HandlerFunc(impl.policyRestHandler.GetCveExceptionReport).Methods("GET")
+	configRouter.Path("/cve-exception/{id}").HandlerFunc(impl.policyRestHandler.GetCveException).Methods("GET")
+	configRouter.Path("/cve-exception/{id}/renew").HandlerFunc(impl.policyRestHandler.RenewCveException).Methods("PUT")
+	configRouter.Path("/cve-exception/{id}/revoke").HandlerFunc(impl.policyRestHandler.RevokeCveException).Methods("PUT")
 }
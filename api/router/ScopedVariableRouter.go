@@ -46,5 +46,14 @@ func (impl ScopedVariableRouterImpl) InitScopedVariableRouter(router *mux.Router
 	router.Path("/variables/detail").
 		HandlerFunc(impl.scopedVariableRestHandler.GetJsonForVariables).
 		Methods("GET")
+	router.Path("/variables/dry-run").
+		HandlerFunc(impl.scopedVariableRestHandler.DryRunVariables).
+		Methods("POST")
+	router.Path("/variables/impact").
+		HandlerFunc(impl.scopedVariableRestHandler.GetVariableImpact).
+		Methods("POST")
+	router.Path("/variables/apply").
+		HandlerFunc(impl.scopedVariableRestHandler.ApplyVariables).
+		Methods("POST")
 
 }
@@ -19,6 +19,7 @@ package router
 import (
 	"encoding/json"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appAsCode"
 	"github.com/devtron-labs/devtron/api/appStore"
 	"github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
@@ -27,12 +28,19 @@ import (
 	"github.com/devtron-labs/devtron/api/auth/user"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
+	"github.com/devtron-labs/devtron/api/configDraft"
+	"github.com/devtron-labs/devtron/api/configPromotion"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentAdmission"
+	"github.com/devtron-labs/devtron/api/deploymentDrift"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication2 "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSigning"
+	"github.com/devtron-labs/devtron/api/incident"
 	"github.com/devtron-labs/devtron/api/infraConfig"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
@@ -41,6 +49,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/app"
 	"github.com/devtron-labs/devtron/api/router/app/configDiff"
+	"github.com/devtron-labs/devtron/api/sbom"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/team"
 	terminal2 "github.com/devtron-labs/devtron/api/terminal"
@@ -124,6 +133,15 @@ type MuxRouter struct {
 	devtronResourceRouter              devtronResource.DevtronResourceRouter
 	scanningResultRouter               resourceScan.ScanningResultRouter
 	userResourceRouter                 userResource.Router
+	deploymentAdmissionPolicyRouter    deploymentAdmission.DeploymentAdmissionPolicyRouter
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	incidentRouter                     incident.IncidentRouter
+	configDraftRouter                  configDraft.ConfigDraftRouter
+	deploymentDriftRouter              deploymentDrift.DeploymentDriftRouter
+	configPromotionRouter              configPromotion.ConfigPromotionRouter
+	appAsCodeRouter                    appAsCode.AppAsCodeRouter
+	imageSigningRouter                 imageSigning.ImageSigningRouter
+	sbomRouter                         sbom.SbomRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger,
@@ -159,6 +177,15 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	fluxApplicationRouter fluxApplication2.FluxApplicationRouter,
 	scanningResultRouter resourceScan.ScanningResultRouter,
 	userResourceRouter userResource.Router,
+	deploymentAdmissionPolicyRouter deploymentAdmission.DeploymentAdmissionPolicyRouter,
+	deploymentWindowRouter deploymentWindow.DeploymentWindowRouter,
+	incidentRouter incident.IncidentRouter,
+	configDraftRouter configDraft.ConfigDraftRouter,
+	deploymentDriftRouter deploymentDrift.DeploymentDriftRouter,
+	configPromotionRouter configPromotion.ConfigPromotionRouter,
+	appAsCodeRouter appAsCode.AppAsCodeRouter,
+	imageSigningRouter imageSigning.ImageSigningRouter,
+	sbomRouter sbom.SbomRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
@@ -226,6 +253,15 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		fluxApplicationRouter:              fluxApplicationRouter,
 		scanningResultRouter:               scanningResultRouter,
 		userResourceRouter:                 userResourceRouter,
+		deploymentAdmissionPolicyRouter:    deploymentAdmissionPolicyRouter,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		incidentRouter:                     incidentRouter,
+		configDraftRouter:                  configDraftRouter,
+		deploymentDriftRouter:              deploymentDriftRouter,
+		configPromotionRouter:              configPromotionRouter,
+		appAsCodeRouter:                    appAsCodeRouter,
+		imageSigningRouter:                 imageSigningRouter,
+		sbomRouter:                         sbomRouter,
 	}
 	return r
 }
@@ -335,6 +371,33 @@ func (r MuxRouter) Init() {
 	policyRouter := r.Router.PathPrefix("/orchestrator/security/policy").Subrouter()
 	r.policyRouter.InitPolicyRouter(policyRouter)
 
+	deploymentAdmissionPolicyRouter := r.Router.PathPrefix("/orchestrator/deployment-admission/policy").Subrouter()
+	r.deploymentAdmissionPolicyRouter.InitDeploymentAdmissionPolicyRouter(deploymentAdmissionPolicyRouter)
+
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	imageSigningRouter := r.Router.PathPrefix("/orchestrator/image-signing").Subrouter()
+	r.imageSigningRouter.InitImageSigningRouter(imageSigningRouter)
+
+	sbomRouter := r.Router.PathPrefix("/orchestrator/sbom").Subrouter()
+	r.sbomRouter.InitSbomRouter(sbomRouter)
+
+	deploymentDriftRouter := r.Router.PathPrefix("/orchestrator/deployment-drift").Subrouter()
+	r.deploymentDriftRouter.InitDeploymentDriftRouter(deploymentDriftRouter)
+
+	incidentRouter := r.Router.PathPrefix("/orchestrator/incident").Subrouter()
+	r.incidentRouter.InitIncidentRouter(incidentRouter)
+
+	configDraftRouter := r.Router.PathPrefix("/orchestrator/config-draft").Subrouter()
+	r.configDraftRouter.InitConfigDraftRouter(configDraftRouter)
+
+	configPromotionRouter := r.Router.PathPrefix("/orchestrator/config-promotion").Subrouter()
+	r.configPromotionRouter.InitConfigPromotionRouter(configPromotionRouter)
+
+	appAsCodeRouter := r.Router.PathPrefix("/orchestrator/app-as-code").Subrouter()
+	r.appAsCodeRouter.InitAppAsCodeRouter(appAsCodeRouter)
+
 	gitOpsRouter := r.Router.PathPrefix("/orchestrator/gitops").Subrouter()
 	r.gitOpsConfigRouter.InitGitOpsConfigRouter(gitOpsRouter)
 
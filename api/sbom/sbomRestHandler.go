@@ -0,0 +1,279 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"io"
+	"net/http"
+)
+
+type SbomRestHandler interface {
+	UploadSbom(w http.ResponseWriter, r *http.Request)
+	GetSbom(w http.ResponseWriter, r *http.Request)
+	DownloadSbom(w http.ResponseWriter, r *http.Request)
+	SearchPackages(w http.ResponseWriter, r *http.Request)
+}
+
+type SbomRestHandlerImpl struct {
+	logger       *zap.SugaredLogger
+	userService  user.UserService
+	sbomService  sbom.SbomService
+	enforcer     casbin.Enforcer
+	enforcerUtil rbac.EnforcerUtil
+	validator    *validator.Validate
+}
+
+func NewSbomRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	sbomService sbom.SbomService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *SbomRestHandlerImpl {
+	return &SbomRestHandlerImpl{
+		logger:       logger,
+		userService:  userService,
+		sbomService:  sbomService,
+		enforcer:     enforcer,
+		enforcerUtil: enforcerUtil,
+		validator:    validator,
+	}
+}
+
+// UploadSbom replaces the sbom of an artifact with the SPDX or CycloneDX json document in the request body
+func (handler *SbomRestHandlerImpl) UploadSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, artifactId, ok := handler.extractAppAndArtifactId(w, r)
+	if !ok {
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		handler.logger.Errorw("request err, UploadSbom", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.sbomService.UploadSbom(appId, artifactId, document, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, UploadSbom", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *SbomRestHandlerImpl) GetSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, artifactId, ok := handler.extractAppAndArtifactId(w, r)
+	if !ok {
+		return
+	}
+	if !handler.enforceAppGet(r, appId) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.sbomService.GetSbom(appId, artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSbom", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// DownloadSbom writes the sbom document of the artifact as it was uploaded
+func (handler *SbomRestHandlerImpl) DownloadSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, artifactId, ok := handler.extractAppAndArtifactId(w, r)
+	if !ok {
+		return
+	}
+	if !handler.enforceAppGet(r, appId) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	document, err := handler.sbomService.GetSbomDocument(appId, artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, DownloadSbom", "appId", appId, "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(common.CONTENT_DISPOSITION, fmt.Sprintf("attachment; filename=sbom-%d.json", artifactId))
+	w.Header().Set(common.CONTENT_TYPE, common.APPLICATION_JSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write([]byte(document)); err != nil {
+		handler.logger.Errorw("error in writing sbom document", "artifactId", artifactId, "err", err)
+	}
+}
+
+// SearchPackages finds the artifacts shipping a package, results are limited to the apps the user can view
+func (handler *SbomRestHandlerImpl) SearchPackages(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, err := handler.getPackageSearchRequest(w, r)
+	if err != nil {
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("struct validation err in SearchPackages", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC enforcer applying
+	appIds, err := handler.sbomService.GetAppIdsWithSbom()
+	if err != nil {
+		handler.logger.Errorw("service err, SearchPackages", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	request.AppIds = handler.getAuthorisedAppIds(r.Header.Get("token"), filterAppIds(appIds, request.AppIds))
+	//RBAC enforcer Ends
+	if len(request.AppIds) == 0 {
+		common.WriteJsonResp(w, nil, &bean.PackageSearchResponse{Results: make([]*bean.PackageSearchResult, 0)}, http.StatusOK)
+		return
+	}
+	resp, err := handler.sbomService.SearchPackages(request)
+	if err != nil {
+		handler.logger.Errorw("service err, SearchPackages", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *SbomRestHandlerImpl) getPackageSearchRequest(w http.ResponseWriter, r *http.Request) (*bean.PackageSearchRequest, error) {
+	offset, err := common.ExtractIntQueryParam(w, r, "offset", 0)
+	if err != nil {
+		return nil, err
+	}
+	size, err := common.ExtractIntQueryParam(w, r, "size", bean.DefaultPackageSearchSize)
+	if err != nil {
+		return nil, err
+	}
+	deployedOnly, err := common.ExtractBoolQueryParam(r, "deployedOnly")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid deployedOnly", http.StatusBadRequest)
+		return nil, err
+	}
+	appIds, err := common.ExtractIntArrayFromQueryParam(r, "appIds")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appIds", http.StatusBadRequest)
+		return nil, err
+	}
+	envIds, err := common.ExtractIntArrayFromQueryParam(r, "envIds")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envIds", http.StatusBadRequest)
+		return nil, err
+	}
+	return &bean.PackageSearchRequest{
+		Name:         r.URL.Query().Get("name"),
+		Version:      r.URL.Query().Get("version"),
+		DeployedOnly: deployedOnly,
+		AppIds:       appIds,
+		EnvIds:       envIds,
+		Offset:       offset,
+		Size:         size,
+	}, nil
+}
+
+func (handler *SbomRestHandlerImpl) getAuthorisedAppIds(token string, appIds []int) []int {
+	authorisedAppIds := make([]int, 0, len(appIds))
+	if len(appIds) == 0 {
+		return authorisedAppIds
+	}
+	appObjects := handler.enforcerUtil.GetRbacObjectsByAppIds(appIds)
+	objects := make([]string, 0, len(appObjects))
+	for _, object := range appObjects {
+		objects = append(objects, object)
+	}
+	results := handler.enforcer.EnforceInBatch(token, casbin.ResourceApplications, casbin.ActionGet, objects)
+	for _, appId := range appIds {
+		if object, ok := appObjects[appId]; ok && results[object] {
+			authorisedAppIds = append(authorisedAppIds, appId)
+		}
+	}
+	return authorisedAppIds
+}
+
+// filterAppIds returns the app ids with sbom which are requested, all of them if no app is requested
+func filterAppIds(appIdsWithSbom []int, requestedAppIds []int) []int {
+	if len(requestedAppIds) == 0 {
+		return appIdsWithSbom
+	}
+	requested := make(map[int]bool, len(requestedAppIds))
+	for _, appId := range requestedAppIds {
+		requested[appId] = true
+	}
+	appIds := make([]int, 0, len(requestedAppIds))
+	for _, appId := range appIdsWithSbom {
+		if requested[appId] {
+			appIds = append(appIds, appId)
+		}
+	}
+	return appIds
+}
+
+func (handler *SbomRestHandlerImpl) enforceAppGet(r *http.Request, appId int) bool {
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	return handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object)
+}
+
+func (handler *SbomRestHandlerImpl) extractAppAndArtifactId(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return 0, 0, false
+	}
+	artifactId, err := common.ExtractIntPathParam(w, r, "artifactId")
+	if err != nil {
+		return 0, 0, false
+	}
+	return appId, artifactId, true
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type SbomRouter interface {
+	InitSbomRouter(configRouter *mux.Router)
+}
+
+type SbomRouterImpl struct {
+	sbomRestHandler SbomRestHandler
+}
+
+func NewSbomRouterImpl(sbomRestHandler SbomRestHandler) *SbomRouterImpl {
+	return &SbomRouterImpl{sbomRestHandler: sbomRestHandler}
+}
+
+func (router *SbomRouterImpl) InitSbomRouter(configRouter *mux.Router) {
+	configRouter.Path("/package/search").HandlerFunc(router.sbomRestHandler.SearchPackages).Methods("GET")
+
+	configRouter.Path("/app/{appId}/artifact/{artifactId}").HandlerFunc(router.sbomRestHandler.GetSbom).Methods("GET")
+	configRouter.Path("/app/{appId}/artifact/{artifactId}").HandlerFunc(router.sbomRestHandler.UploadSbom).Methods("PUT")
+	configRouter.Path("/app/{appId}/artifact/{artifactId}/document").HandlerFunc(router.sbomRestHandler.DownloadSbom).Methods("GET")
+}
@@ -9,6 +9,7 @@ const (
 	ParamTypeList           ParamValuesType = "list"
 	ParamTypeBool           ParamValuesType = "bool"
 	ParamTypeMapStringToAny ParamValuesType = "mapStringToAny"
+	ParamTypeTimestamp      ParamValuesType = "timestamp"
 )
 
 type ParamName string
@@ -25,6 +26,13 @@ const ContainerRepo ParamName = "containerRepository"
 const ContainerImage ParamName = "containerImage"
 const ContainerImageTag ParamName = "containerImageTag"
 const ImageLabels ParamName = "imageLabels"
+const CommitHashes ParamName = "commitHashes"
+const CommitMessages ParamName = "commitMessages"
+const CommitAuthors ParamName = "commitAuthors"
+const SourceBranches ParamName = "sourceBranches"
+const TriggerTime ParamName = "triggerTime"
+const DeploymentTriggerType ParamName = "deploymentTriggerType"
+const IsRollbackDeployment ParamName = "isRollbackDeployment"
 
 type Request struct {
 	Expression         string             `json:"expression"`
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvaluatorServiceImpl_EvaluateDeploymentAdmissionRequest(t *testing.T) {
+	log, err := util.NewSugardLogger()
+	if err != nil {
+		log.Panic(err)
+	}
+	impl := cel.NewCELServiceImpl(log)
+	testParams := getDeploymentAdmissionTestParams()
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "TEST 1: Trigger time outside business hours",
+			expression: "triggerTime.getHours('UTC') >= 18 || triggerTime.getHours('UTC') < 9",
+			want:       true,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "TEST 2: Trigger on weekend",
+			expression: "triggerTime.getDayOfWeek('UTC') in [0, 6]",
+			want:       false,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "TEST 3: Commit message check",
+			expression: "commitMessages.exists(message, message.contains('hotfix'))",
+			want:       true,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "TEST 4: Source branch check",
+			expression: "isProdEnv && !sourceBranches.all(branch, branch == 'main')",
+			want:       true,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "TEST 5: Trigger type and rollback check",
+			expression: "deploymentTriggerType == 'AUTOMATIC' && !isRollbackDeployment",
+			want:       false,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "TEST 6: Invalid timestamp comparison",
+			expression: "triggerTime > 10",
+			want:       false,
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := cel.Request{
+				Expression: tt.expression,
+				ExpressionMetadata: cel.ExpressionMetadata{
+					Params: testParams,
+				},
+			}
+			got, err := impl.EvaluateCELRequest(request)
+			if !tt.wantErr(t, err, fmt.Sprintf("EvaluateCELRequest(Expression: %v)", tt.expression)) {
+				return
+			}
+			assert.Equalf(t, tt.want, got, "EvaluateCELRequest(Expression: %v)", tt.expression)
+		})
+	}
+}
+
+func getDeploymentAdmissionTestParams() []cel.ExpressionParam {
+	return append(getTestParams(),
+		cel.ExpressionParam{
+			ParamName: cel.CommitMessages,
+			Value:     []string{"fix: hotfix for login", "chore: bump version"},
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.SourceBranches,
+			Value:     []string{"main", "release-1.2"},
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			// Wednesday
+			ParamName: cel.TriggerTime,
+			Value:     time.Date(2024, 7, 10, 20, 30, 0, 0, time.UTC),
+			Type:      cel.ParamTypeTimestamp,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.DeploymentTriggerType,
+			Value:     "MANUAL",
+			Type:      cel.ParamTypeString,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.IsRollbackDeployment,
+			Value:     false,
+			Type:      cel.ParamTypeBool,
+		},
+	)
+}
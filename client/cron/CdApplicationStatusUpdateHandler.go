@@ -29,7 +29,12 @@ import (
 	installedAppReadBean "github.com/devtron-labs/devtron/pkg/appStore/installedApp/read/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/EAMode"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/watch"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/controller"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift"
+	driftBean "github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentGroup"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
 	"github.com/devtron-labs/devtron/pkg/workflow/dag"
@@ -46,6 +51,10 @@ type CdApplicationStatusUpdateHandler interface {
 	HelmApplicationStatusUpdate()
 	ArgoApplicationStatusUpdate()
 	ArgoPipelineTimelineUpdate()
+	AutoRollbackWatchUpdate()
+	CanaryAnalysisUpdate()
+	DeploymentGroupReleaseUpdate()
+	DeploymentDriftScan()
 	SyncPipelineStatusForResourceTreeCall(pipeline *pipelineConfig.Pipeline) error
 	SyncPipelineStatusForAppStoreForResourceTreeCall(installedAppVersion *repository2.InstalledAppVersions) error
 	ManualSyncPipelineStatus(appId, envId int, userId int32) error
@@ -67,6 +76,10 @@ type CdApplicationStatusUpdateHandlerImpl struct {
 	installedAppReadService              installedAppReader.InstalledAppReadService
 	cdWorkflowCommonService              cd.CdWorkflowCommonService
 	workflowStatusService                status.WorkflowStatusService
+	autoRollbackWatchService             watch.AutoRollbackWatchService
+	canaryAnalysisController             controller.CanaryAnalysisController
+	deploymentGroupReleaseService        deploymentGroup.DeploymentGroupReleaseService
+	deploymentDriftService               drift.DeploymentDriftService
 }
 
 func NewCdApplicationStatusUpdateHandlerImpl(logger *zap.SugaredLogger, appService app.AppService,
@@ -78,7 +91,12 @@ func NewCdApplicationStatusUpdateHandlerImpl(logger *zap.SugaredLogger, appServi
 	pipelineRepository pipelineConfig.PipelineRepository, installedAppVersionHistoryRepository repository2.InstalledAppVersionHistoryRepository,
 	installedAppReadService installedAppReader.InstalledAppReadService, cronLogger *cron2.CronLoggerImpl,
 	cdWorkflowCommonService cd.CdWorkflowCommonService,
-	workflowStatusService status.WorkflowStatusService) *CdApplicationStatusUpdateHandlerImpl {
+	workflowStatusService status.WorkflowStatusService,
+	autoRollbackWatchService watch.AutoRollbackWatchService,
+	canaryAnalysisController controller.CanaryAnalysisController,
+	deploymentGroupReleaseService deploymentGroup.DeploymentGroupReleaseService,
+	deploymentDriftService drift.DeploymentDriftService,
+	deploymentDriftConfig *driftBean.DeploymentDriftConfig) *CdApplicationStatusUpdateHandlerImpl {
 
 	cron := cron.New(
 		cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
@@ -99,6 +117,10 @@ func NewCdApplicationStatusUpdateHandlerImpl(logger *zap.SugaredLogger, appServi
 		installedAppReadService:              installedAppReadService,
 		cdWorkflowCommonService:              cdWorkflowCommonService,
 		workflowStatusService:                workflowStatusService,
+		autoRollbackWatchService:             autoRollbackWatchService,
+		canaryAnalysisController:             canaryAnalysisController,
+		deploymentGroupReleaseService:        deploymentGroupReleaseService,
+		deploymentDriftService:               deploymentDriftService,
 	}
 	_, err := cron.AddFunc(AppStatusConfig.CdHelmPipelineStatusCronTime, impl.HelmApplicationStatusUpdate)
 	if err != nil {
@@ -115,6 +137,28 @@ func NewCdApplicationStatusUpdateHandlerImpl(logger *zap.SugaredLogger, appServi
 		logger.Errorw("error in starting argo application status update cron job", "err", err)
 		return nil
 	}
+	_, err = cron.AddFunc(AppStatusConfig.AutoRollbackWatchCronTime, impl.AutoRollbackWatchUpdate)
+	if err != nil {
+		logger.Errorw("error in starting auto rollback watch cron job", "err", err)
+		return nil
+	}
+	_, err = cron.AddFunc(AppStatusConfig.CanaryAnalysisCronTime, impl.CanaryAnalysisUpdate)
+	if err != nil {
+		logger.Errorw("error in starting canary analysis cron job", "err", err)
+		return nil
+	}
+	_, err = cron.AddFunc("@every 1m", impl.DeploymentGroupReleaseUpdate)
+	if err != nil {
+		logger.Errorw("error in starting deployment group release cron job", "err", err)
+		return nil
+	}
+	if deploymentDriftConfig.ScanEnabled {
+		_, err = cron.AddFunc(deploymentDriftConfig.ScanCronTime, impl.DeploymentDriftScan)
+		if err != nil {
+			logger.Errorw("error in starting deployment drift scan cron job", "err", err)
+			return nil
+		}
+	}
 	return impl
 }
 
@@ -174,6 +218,26 @@ func (impl *CdApplicationStatusUpdateHandlerImpl) ArgoPipelineTimelineUpdate() {
 	return
 }
 
+// AutoRollbackWatchUpdate rolls back the deployments which went bad within the auto rollback window of their pipeline
+func (impl *CdApplicationStatusUpdateHandlerImpl) AutoRollbackWatchUpdate() {
+	impl.autoRollbackWatchService.ProcessWatches()
+}
+
+// CanaryAnalysisUpdate promotes or aborts the canary deployments paused at an analysis step
+func (impl *CdApplicationStatusUpdateHandlerImpl) CanaryAnalysisUpdate() {
+	impl.canaryAnalysisController.ProcessRuns()
+}
+
+// DeploymentGroupReleaseUpdate moves the running deployment group releases to their next waves
+func (impl *CdApplicationStatusUpdateHandlerImpl) DeploymentGroupReleaseUpdate() {
+	impl.deploymentGroupReleaseService.ProcessReleases()
+}
+
+// DeploymentDriftScan compares the live resources of the helm deployed pipelines against their latest deployment
+func (impl *CdApplicationStatusUpdateHandlerImpl) DeploymentDriftScan() {
+	impl.deploymentDriftService.ScanAll()
+}
+
 func (impl *CdApplicationStatusUpdateHandlerImpl) SyncPipelineStatusForResourceTreeCall(pipeline *pipelineConfig.Pipeline) error {
 	cdWfr, err := impl.cdWorkflowRepository.FindLatestByPipelineIdAndRunnerType(pipeline.Id, bean.CD_WORKFLOW_TYPE_DEPLOY)
 	if err != nil {
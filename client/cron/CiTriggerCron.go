@@ -17,48 +17,67 @@
 package cron
 
 import (
+	"context"
 	"fmt"
 	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/client/gitSensor"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule/helper"
+	scheduleRepository "github.com/devtron-labs/devtron/pkg/build/pipeline/schedule/repository"
 	"github.com/devtron-labs/devtron/pkg/build/trigger"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	repository3 "github.com/devtron-labs/devtron/pkg/plugin/repository"
 	cron2 "github.com/devtron-labs/devtron/util/cron"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
+	"time"
 )
 
 type CiTriggerCron interface {
 	TriggerCiCron()
+	TriggerScheduledCi()
 }
 
 type CiTriggerCronImpl struct {
-	logger                  *zap.SugaredLogger
-	cron                    *cron.Cron
-	cfg                     *CiTriggerCronConfig
-	pipelineStageRepository repository.PipelineStageRepository
-	ciArtifactRepository    repository2.CiArtifactRepository
-	globalPluginRepository  repository3.GlobalPluginRepository
-	ciHandlerService        trigger.HandlerService
+	logger                       *zap.SugaredLogger
+	cron                         *cron.Cron
+	cfg                          *CiTriggerCronConfig
+	pipelineStageRepository      repository.PipelineStageRepository
+	ciArtifactRepository         repository2.CiArtifactRepository
+	globalPluginRepository       repository3.GlobalPluginRepository
+	ciHandlerService             trigger.HandlerService
+	ciPipelineScheduleService    schedule.CiPipelineScheduleService
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository
+	gitSensorClient              gitSensor.Client
 }
 
 func NewCiTriggerCronImpl(logger *zap.SugaredLogger, cfg *CiTriggerCronConfig, pipelineStageRepository repository.PipelineStageRepository,
 	ciArtifactRepository repository2.CiArtifactRepository, globalPluginRepository repository3.GlobalPluginRepository, cronLogger *cron2.CronLoggerImpl,
-	ciHandlerService trigger.HandlerService) *CiTriggerCronImpl {
+	ciHandlerService trigger.HandlerService, ciPipelineScheduleService schedule.CiPipelineScheduleService,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	gitSensorClient gitSensor.Client) *CiTriggerCronImpl {
 	cron := cron.New(
 		cron.WithChain(cron.Recover(cronLogger)))
 	cron.Start()
 	impl := &CiTriggerCronImpl{
-		logger:                  logger,
-		cron:                    cron,
-		pipelineStageRepository: pipelineStageRepository,
-		cfg:                     cfg,
-		ciArtifactRepository:    ciArtifactRepository,
-		globalPluginRepository:  globalPluginRepository,
-		ciHandlerService:        ciHandlerService,
+		logger:                       logger,
+		cron:                         cron,
+		pipelineStageRepository:      pipelineStageRepository,
+		cfg:                          cfg,
+		ciArtifactRepository:         ciArtifactRepository,
+		globalPluginRepository:       globalPluginRepository,
+		ciHandlerService:             ciHandlerService,
+		ciPipelineScheduleService:    ciPipelineScheduleService,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciPipelineMaterialRepository: ciPipelineMaterialRepository,
+		gitSensorClient:              gitSensorClient,
 	}
 
 	_, err := cron.AddFunc(fmt.Sprintf("@every %dm", cfg.SourceControllerCronTime), impl.TriggerCiCron)
@@ -66,12 +85,18 @@ func NewCiTriggerCronImpl(logger *zap.SugaredLogger, cfg *CiTriggerCronConfig, p
 		logger.Errorw("error while configure cron job for ci workflow status update", "err", err)
 		return impl
 	}
+	_, err = cron.AddFunc(fmt.Sprintf("@every %ds", cfg.ScheduledCiPollIntervalSecs), impl.TriggerScheduledCi)
+	if err != nil {
+		logger.Errorw("error while configure cron job for scheduled ci builds", "err", err)
+		return impl
+	}
 	return impl
 }
 
 type CiTriggerCronConfig struct {
-	SourceControllerCronTime int    `env:"CI_TRIGGER_CRON_TIME" envDefault:"2" description:"For image poll plugin"`
-	PluginName               string `env:"PLUGIN_NAME"  envDefault:"Pull images from container repository" description:"Handles image retrieval from a container repository and triggers subsequent CI processes upon detecting new images.Current default plugin name: Pull Images from Container Repository."`
+	SourceControllerCronTime    int    `env:"CI_TRIGGER_CRON_TIME" envDefault:"2" description:"For image poll plugin"`
+	PluginName                  string `env:"PLUGIN_NAME"  envDefault:"Pull images from container repository" description:"Handles image retrieval from a container repository and triggers subsequent CI processes upon detecting new images.Current default plugin name: Pull Images from Container Repository."`
+	ScheduledCiPollIntervalSecs int    `env:"CI_SCHEDULE_POLL_INTERVAL_SECS" envDefault:"30" description:"Interval (in seconds) at which due schedules of ci pipelines are looked up and triggered"`
 }
 
 func GetCiTriggerCronConfig() (*CiTriggerCronConfig, error) {
@@ -111,3 +136,96 @@ func (impl *CiTriggerCronImpl) TriggerCiCron() {
 	}
 	return
 }
+
+// TriggerScheduledCi triggers the ci pipelines whose schedules are due, every run is claimed before triggering
+// so that a schedule is triggered only once across replicas
+func (impl *CiTriggerCronImpl) TriggerScheduledCi() {
+	now := time.Now()
+	schedules, materialsBySchedule, err := impl.ciPipelineScheduleService.GetDueSchedules(now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching due ci pipeline schedules", "err", err)
+		return
+	}
+	for _, ciPipelineSchedule := range schedules {
+		claimed, err := impl.ciPipelineScheduleService.ClaimRun(ciPipelineSchedule, now)
+		if err != nil || !claimed {
+			continue
+		}
+		status, message, ciWorkflowId, builtCommits := impl.triggerScheduledCi(ciPipelineSchedule, materialsBySchedule[ciPipelineSchedule.Id])
+		err = impl.ciPipelineScheduleService.RecordRun(ciPipelineSchedule, status, message, ciWorkflowId, builtCommits)
+		if err != nil {
+			impl.logger.Errorw("error in recording scheduled ci run", "scheduleId", ciPipelineSchedule.Id, "status", status, "err", err)
+		}
+	}
+}
+
+func (impl *CiTriggerCronImpl) triggerScheduledCi(ciPipelineSchedule *scheduleRepository.CiPipelineSchedule,
+	scheduleMaterials []*scheduleRepository.CiPipelineScheduleMaterial) (scheduleRepository.ScheduleRunStatus, string, int, map[int]string) {
+	ciPipeline, err := impl.ciPipelineRepository.FindById(ciPipelineSchedule.CiPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline of schedule", "scheduleId", ciPipelineSchedule.Id, "ciPipelineId", ciPipelineSchedule.CiPipelineId, "err", err)
+		return scheduleRepository.ScheduleRunStatusFailed, "error in fetching ci pipeline", 0, nil
+	}
+	if ciPipeline.Deleted || !ciPipeline.Active {
+		return scheduleRepository.ScheduleRunStatusFailed, "ci pipeline is deleted", 0, nil
+	}
+	ciPipelineMaterials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(ciPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline materials", "ciPipelineId", ciPipeline.Id, "err", err)
+		return scheduleRepository.ScheduleRunStatusFailed, "error in fetching ci pipeline materials", 0, nil
+	}
+	branchOverrides := make(map[int]string, len(scheduleMaterials))
+	for _, scheduleMaterial := range scheduleMaterials {
+		branchOverrides[scheduleMaterial.GitMaterialId] = scheduleMaterial.BranchName
+	}
+	commitHashes := make(map[int]pipelineConfig.GitCommit)
+	latestCommits := make(map[int]string)
+	for _, ciPipelineMaterial := range ciPipelineMaterials {
+		if ciPipelineMaterial.GitMaterial == nil || !ciPipelineMaterial.GitMaterial.Active {
+			continue
+		}
+		if ciPipelineMaterial.Type != constants.SOURCE_TYPE_BRANCH_FIXED {
+			return scheduleRepository.ScheduleRunStatusFailed, fmt.Sprintf("source type %s is not supported by schedules", ciPipelineMaterial.Type), 0, nil
+		}
+		branchName := ciPipelineMaterial.Value
+		if branchOverride, ok := branchOverrides[ciPipelineMaterial.GitMaterialId]; ok {
+			branchName = branchOverride
+		}
+		commitMetadataRequest := &gitSensor.CommitMetadataRequest{
+			PipelineMaterialId: ciPipelineMaterial.Id,
+			BranchName:         branchName,
+		}
+		latestCommit, err := impl.gitSensorClient.GetCommitMetadata(context.Background(), commitMetadataRequest)
+		if err != nil || latestCommit == nil {
+			impl.logger.Errorw("error in fetching latest commit of branch", "commitMetadataRequest", commitMetadataRequest, "err", err)
+			return scheduleRepository.ScheduleRunStatusFailed, fmt.Sprintf("could not fetch latest commit of branch %s", branchName), 0, nil
+		}
+		gitCommit := trigger.SetGitCommitValuesForBuildingCommitHash(ciPipelineMaterial, pipelineConfig.GitCommit{
+			Commit:  latestCommit.Commit,
+			Author:  latestCommit.Author,
+			Date:    latestCommit.Date,
+			Message: latestCommit.Message,
+			Changes: latestCommit.Changes,
+		})
+		gitCommit.CiConfigureSourceValue = branchName
+		commitHashes[ciPipelineMaterial.Id] = gitCommit
+		latestCommits[ciPipelineMaterial.Id] = latestCommit.Commit
+	}
+	if len(commitHashes) == 0 {
+		return scheduleRepository.ScheduleRunStatusFailed, "no active git material found in ci pipeline", 0, nil
+	}
+	if ciPipelineSchedule.SkipIfNoNewCommit {
+		lastBuiltCommits, err := helper.DecodeBuiltCommits(ciPipelineSchedule.LastBuiltCommits)
+		if err != nil {
+			impl.logger.Warnw("error in decoding last built commits of schedule, building anyway", "scheduleId", ciPipelineSchedule.Id, "err", err)
+		} else if !helper.HasNewCommits(lastBuiltCommits, latestCommits) {
+			return scheduleRepository.ScheduleRunStatusSkipped, "no new commit since last scheduled build", 0, nil
+		}
+	}
+	ciWorkflowId, err := impl.ciHandlerService.HandleCIScheduled(ciPipeline.Id, ciPipeline.PipelineType, commitHashes)
+	if err != nil {
+		impl.logger.Errorw("error in triggering scheduled ci", "scheduleId", ciPipelineSchedule.Id, "ciPipelineId", ciPipeline.Id, "err", err)
+		return scheduleRepository.ScheduleRunStatusFailed, err.Error(), 0, nil
+	}
+	return scheduleRepository.ScheduleRunStatusTriggered, "", ciWorkflowId, latestCommits
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
 	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -31,15 +32,20 @@ import (
 	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/module"
 	bean3 "github.com/devtron-labs/devtron/pkg/module/bean"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident"
+	incidentBean "github.com/devtron-labs/devtron/pkg/notifier/incident/bean"
 	util "github.com/devtron-labs/devtron/util/event"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
 type EventClientConfig struct {
 	DestinationURL     string             `env:"EVENT_URL" envDefault:"http://localhost:3000/notify" description:"Notifier service url"`
 	NotificationMedium NotificationMedium `env:"NOTIFICATION_MEDIUM" envDefault:"rest" description:"notification medium"`
 	EnableNotifierV2   bool               `env:"ENABLE_NOTIFIER_V2" envDefault:"false" description:"enable notifier v2"`
+	// TeamsWebhookTimeoutSecs bounds the requests to Teams webhooks, which are posted by devtron itself
+	TeamsWebhookTimeoutSecs int `env:"TEAMS_WEBHOOK_TIMEOUT_SECS" envDefault:"10" description:"timeout in seconds of the requests posting notifications to Microsoft Teams webhooks"`
 }
 type NotificationMedium string
 
@@ -95,6 +101,9 @@ type Payload struct {
 	BuildHistoryLink      string                         `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *buildBean.MaterialTriggerInfo `json:"material"`
 	FailureReason         string                         `json:"failureReason"`
+	DriftSummary          string                         `json:"driftSummary,omitempty"`
+	CveExceptionSummary   string                         `json:"cveExceptionSummary,omitempty"`
+	NewCriticalCveSummary string                         `json:"newCriticalCveSummary,omitempty"`
 }
 
 type EventRESTClientImpl struct {
@@ -107,16 +116,24 @@ type EventRESTClientImpl struct {
 	attributesRepository           repository.AttributesRepository
 	moduleService                  module.ModuleService
 	notificationSettingsRepository repository.NotificationSettingsRepository
+	teamsRepository                repository.TeamsNotificationRepository
+	incidentService                incident.IncidentService
+	teamsClient                    *http.Client
+	asyncRunnable                  *async.Runnable
 }
 
 func NewEventRESTClientImpl(logger *zap.SugaredLogger, client *http.Client, config *EventClientConfig, pubsubClient *pubsub.PubSubClientServiceImpl,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
 	attributesRepository repository.AttributesRepository, moduleService module.ModuleService,
-	notificationSettingsRepository repository.NotificationSettingsRepository) *EventRESTClientImpl {
+	notificationSettingsRepository repository.NotificationSettingsRepository,
+	teamsRepository repository.TeamsNotificationRepository, incidentService incident.IncidentService,
+	asyncRunnable *async.Runnable) *EventRESTClientImpl {
 	return &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
 		ciPipelineRepository: ciPipelineRepository, pipelineRepository: pipelineRepository,
 		attributesRepository: attributesRepository, moduleService: moduleService,
-		notificationSettingsRepository: notificationSettingsRepository}
+		notificationSettingsRepository: notificationSettingsRepository, teamsRepository: teamsRepository,
+		incidentService: incidentService, asyncRunnable: asyncRunnable,
+		teamsClient: &http.Client{Timeout: time.Duration(config.TeamsWebhookTimeoutSecs) * time.Second}}
 }
 
 func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
@@ -147,6 +164,10 @@ func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipe
 }
 
 func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, error) {
+	// incidents are raised for failed production deployments irrespective of the notification integration
+	if event.EventTypeId == int(util.Fail) && event.PipelineType == string(util.CD) && event.IsProdEnv {
+		impl.incidentService.HandleDeploymentFailure(buildDeploymentFailureEvent(event))
+	}
 	// if notification integration is not installed then do not send the notification
 	moduleInfo, err := impl.moduleService.GetModuleInfo(bean3.ModuleNameNotification)
 	if err != nil {
@@ -227,6 +248,20 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 	}
 	return true, err
 }
+func buildDeploymentFailureEvent(event Event) *incidentBean.DeploymentFailureEvent {
+	failureEvent := &incidentBean.DeploymentFailureEvent{
+		AppId:              event.AppId,
+		EnvId:              event.EnvId,
+		PipelineId:         event.PipelineId,
+		CdWorkflowRunnerId: event.CdWorkflowRunnerId,
+		Stage:              string(event.CdWorkflowType),
+	}
+	if event.Payload != nil {
+		failureEvent.FailureReason = event.Payload.FailureReason
+	}
+	return failureEvent
+}
+
 func (impl *EventRESTClientImpl) sendEventsOnNats(body []byte) error {
 
 	err := impl.pubsubClient.Publish(pubsub.NOTIFICATION_EVENT_TOPIC, string(body))
@@ -243,48 +278,36 @@ func (impl *EventRESTClientImpl) sendEvent(event Event) (bool, error) {
 	impl.logger.Debugw("event before send", "event", event)
 
 	// Step 1: Create payload and destination URL based on config
-	bodyBytes, destinationUrl, err := impl.createPayloadAndDestination(event)
+	bodyBytes, destinationUrl, notificationSettings, err := impl.createPayloadAndDestination(event)
 	if err != nil {
 		return false, err
 	}
 
+	// Teams channels are delivered from here as notifier service does not support them, asynchronously so that
+	// slow webhooks do not hold up the event
+	impl.asyncRunnable.Execute(func() {
+		impl.sendTeamsNotifications(event, notificationSettings)
+	})
+
 	// Step 2: Send via appropriate medium (NATS or REST)
 	return impl.deliverEvent(bodyBytes, destinationUrl)
 }
 
-func (impl *EventRESTClientImpl) createPayloadAndDestination(event Event) ([]byte, string, error) {
+// createPayloadAndDestination also returns the notification settings of the event if they are fetched for the payload
+func (impl *EventRESTClientImpl) createPayloadAndDestination(event Event) ([]byte, string, []*repository.NotificationSettingsBean, error) {
 	if impl.config.EnableNotifierV2 {
 		return impl.createV2PayloadAndDestination(event)
 	}
-	return impl.createDefaultPayloadAndDestination(event)
+	bodyBytes, destinationUrl, err := impl.createDefaultPayloadAndDestination(event)
+	return bodyBytes, destinationUrl, nil, err
 }
 
-func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]byte, string, error) {
+func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]byte, string, []*repository.NotificationSettingsBean, error) {
 	destinationUrl := impl.config.DestinationURL + "/v2"
 
-	// Fetch notification settings
-	req := repository.GetRulesRequest{
-		TeamId:              event.TeamId,
-		EnvId:               event.EnvId,
-		AppId:               event.AppId,
-		PipelineId:          event.PipelineId,
-		PipelineType:        event.PipelineType,
-		IsProdEnv:           &event.IsProdEnv,
-		ClusterId:           event.ClusterId,
-		EnvIdsForCiPipeline: event.EnvIdsForCiPipeline,
-	}
-	notificationSettings, err := impl.notificationSettingsRepository.FindNotificationSettingsWithRules(
-		context.Background(), event.EventTypeId, req,
-	)
-	if err != nil {
-		impl.logger.Errorw("error while fetching notification settings", "err", err)
-		return nil, "", err
-	}
-
-	// Process notification settings into beans
-	notificationSettingsBean, err := impl.processNotificationSettings(notificationSettings)
+	notificationSettingsBean, err := impl.getNotificationSettings(event)
 	if err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
 	// Create combined payload
@@ -296,10 +319,35 @@ func (impl *EventRESTClientImpl) createV2PayloadAndDestination(event Event) ([]b
 	bodyBytes, err := json.Marshal(combinedPayload)
 	if err != nil {
 		impl.logger.Errorw("error while marshaling combined event request", "err", err)
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
-	return bodyBytes, destinationUrl, nil
+	return bodyBytes, destinationUrl, notificationSettingsBean, nil
+}
+
+// getNotificationSettings fetches the notification settings matching the event and processes them into beans
+func (impl *EventRESTClientImpl) getNotificationSettings(event Event) ([]*repository.NotificationSettingsBean, error) {
+	notificationSettings, err := impl.notificationSettingsRepository.FindNotificationSettingsWithRules(
+		context.Background(), event.EventTypeId, buildGetRulesRequest(event),
+	)
+	if err != nil {
+		impl.logger.Errorw("error while fetching notification settings", "err", err)
+		return nil, err
+	}
+	return impl.processNotificationSettings(notificationSettings)
+}
+
+func buildGetRulesRequest(event Event) repository.GetRulesRequest {
+	return repository.GetRulesRequest{
+		TeamId:              event.TeamId,
+		EnvId:               event.EnvId,
+		AppId:               event.AppId,
+		PipelineId:          event.PipelineId,
+		PipelineType:        event.PipelineType,
+		IsProdEnv:           &event.IsProdEnv,
+		ClusterId:           event.ClusterId,
+		EnvIdsForCiPipeline: event.EnvIdsForCiPipeline,
+	}
 }
 
 func (impl *EventRESTClientImpl) createDefaultPayloadAndDestination(event Event) ([]byte, string, error) {
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/notifier/adapter"
+	"github.com/devtron-labs/devtron/pkg/notifier/beans"
+	util "github.com/devtron-labs/devtron/util/event"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// sendTeamsNotifications posts the event as an adaptive card to the Teams channels of the matching notification settings,
+// the settings are fetched if not already fetched for the notifier payload. notifier service does not deliver to Teams,
+// failures are logged and not propagated so that other channels are not affected
+func (impl *EventRESTClientImpl) sendTeamsNotifications(event Event, notificationSettingsBean []*repository.NotificationSettingsBean) {
+	if notificationSettingsBean == nil {
+		var err error
+		notificationSettingsBean, err = impl.getNotificationSettings(event)
+		if err != nil {
+			impl.logger.Errorw("error while fetching notification settings for teams", "eventTypeId", event.EventTypeId, "pipelineId", event.PipelineId, "err", err)
+			return
+		}
+	}
+	teamsConfigIds := getConfigIdsForDestination(notificationSettingsBean, util.Teams)
+	if len(teamsConfigIds) == 0 {
+		return
+	}
+	teamsConfigs, err := impl.teamsRepository.FindByIdsIn(teamsConfigIds)
+	if err != nil {
+		impl.logger.Errorw("error while fetching teams configs", "teamsConfigIds", teamsConfigIds, "err", err)
+		return
+	}
+	message := adapter.BuildTeamsAdaptiveCardMessage(buildTeamsCardContent(event))
+	bodyBytes, err := json.Marshal(message)
+	if err != nil {
+		impl.logger.Errorw("error while marshaling teams message", "err", err)
+		return
+	}
+	for _, teamsConfig := range teamsConfigs {
+		err = impl.postTeamsMessage(teamsConfig.WebHookUrl, bodyBytes)
+		if err != nil {
+			impl.logger.Errorw("error while sending teams notification", "teamsConfigId", teamsConfig.Id, "eventTypeId", event.EventTypeId, "pipelineId", event.PipelineId, "err", err)
+		}
+	}
+}
+
+func (impl *EventRESTClientImpl) postTeamsMessage(webhookUrl string, bodyBytes []byte) error {
+	req, err := http.NewRequest(http.MethodPost, webhookUrl, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := impl.teamsClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func getConfigIdsForDestination(notificationSettings []*repository.NotificationSettingsBean, dest util.Channel) []int {
+	configIds := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, notificationSetting := range notificationSettings {
+		for _, config := range notificationSetting.Config {
+			if config.Dest != dest.String() || seen[config.ConfigId] {
+				continue
+			}
+			seen[config.ConfigId] = true
+			configIds = append(configIds, config.ConfigId)
+		}
+	}
+	return configIds
+}
+
+func buildTeamsCardContent(event Event) *beans.TeamsCardContent {
+	payload := event.Payload
+	if payload == nil {
+		payload = &Payload{}
+	}
+	content := &beans.TeamsCardContent{
+		Title: getTeamsCardTitle(event),
+		Color: getTeamsCardColor(util.EventType(event.EventTypeId)),
+	}
+	if len(payload.TriggeredBy) > 0 {
+		content.Subtitle = fmt.Sprintf("by %s", payload.TriggeredBy)
+	}
+	content.Facts = []beans.AdaptiveCardFact{
+		{Title: "Application", Value: payload.AppName},
+		{Title: "Environment", Value: payload.EnvName},
+		{Title: "Pipeline", Value: payload.PipelineName},
+		{Title: "Stage", Value: payload.Stage},
+		{Title: "Source", Value: getTeamsCardSource(payload)},
+		{Title: "Image", Value: payload.DockerImageUrl},
+		{Title: "Failure reason", Value: payload.FailureReason},
+		{Title: "Drift", Value: payload.DriftSummary},
+		{Title: "CVE exception", Value: payload.CveExceptionSummary},
+		{Title: "New critical CVEs", Value: payload.NewCriticalCveSummary},
+		{Title: "Time", Value: event.EventTime},
+	}
+	if len(event.BaseUrl) > 0 {
+		baseUrl := strings.TrimSuffix(event.BaseUrl, "/")
+		addAction := func(title, link string) {
+			if len(link) > 0 {
+				content.Actions = append(content.Actions, beans.AdaptiveCardAction{Title: title, Url: baseUrl + link})
+			}
+		}
+		if event.PipelineType == string(util.CI) {
+			addAction("View build", payload.BuildHistoryLink)
+		} else {
+			addAction("View deployment", payload.DeploymentHistoryLink)
+			addAction("View app details", payload.AppDetailLink)
+		}
+	}
+	return content
+}
+
+func getTeamsCardTitle(event Event) string {
+	eventType := util.EventType(event.EventTypeId)
+	switch eventType {
+	case util.Approval:
+		return "Image approval requested"
+	case util.ConfigApproval:
+		return "Configuration approval requested"
+	case util.ConfigDrift:
+		return "Configuration drift detected"
+	case util.CveExceptionExpiry:
+		return "CVE exception expiring"
+	case util.NewCriticalCves:
+		return "New critical CVEs in deployed image"
+	}
+	subject := "Build pipeline"
+	if event.PipelineType == string(util.CD) {
+		switch event.CdWorkflowType {
+		case bean.CD_WORKFLOW_TYPE_PRE:
+			subject = "Pre-deployment"
+		case bean.CD_WORKFLOW_TYPE_POST:
+			subject = "Post-deployment"
+		default:
+			subject = "Deployment pipeline"
+		}
+	}
+	switch eventType {
+	case util.Trigger:
+		return fmt.Sprintf("%s triggered", subject)
+	case util.Success:
+		return fmt.Sprintf("%s succeeded", subject)
+	case util.Fail:
+		return fmt.Sprintf("%s failed", subject)
+	}
+	return event.EventName
+}
+
+func getTeamsCardColor(eventType util.EventType) beans.AdaptiveCardColor {
+	switch eventType {
+	case util.Trigger:
+		return beans.AdaptiveCardColorAccent
+	case util.Success:
+		return beans.AdaptiveCardColorGood
+	case util.Fail, util.NewCriticalCves:
+		return beans.AdaptiveCardColorAttention
+	case util.Approval, util.ConfigApproval, util.ConfigDrift, util.CveExceptionExpiry:
+		return beans.AdaptiveCardColorWarning
+	}
+	return beans.AdaptiveCardColorDefault
+}
+
+// getTeamsCardSource lists the built commits as branch@commit for CI events
+func getTeamsCardSource(payload *Payload) string {
+	if payload.MaterialTriggerInfo == nil || len(payload.MaterialTriggerInfo.GitTriggers) == 0 {
+		return payload.Source
+	}
+	materialIds := make([]int, 0, len(payload.MaterialTriggerInfo.GitTriggers))
+	for materialId := range payload.MaterialTriggerInfo.GitTriggers {
+		materialIds = append(materialIds, materialId)
+	}
+	sort.Ints(materialIds)
+	sources := make([]string, 0, len(materialIds))
+	for _, materialId := range materialIds {
+		gitCommit := payload.MaterialTriggerInfo.GitTriggers[materialId]
+		commit := gitCommit.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		source := commit
+		if len(gitCommit.CiConfigureSourceValue) > 0 {
+			source = fmt.Sprintf("%s@%s", gitCommit.CiConfigureSourceValue, commit)
+		}
+		if len(gitCommit.GitRepoName) > 0 {
+			source = fmt.Sprintf("%s/%s", gitCommit.GitRepoName, source)
+		}
+		sources = append(sources, source)
+	}
+	return strings.Join(sources, ", ")
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/notifier/adapter"
+	"github.com/devtron-labs/devtron/pkg/notifier/beans"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildTeamsCardContent(t *testing.T) {
+	t.Run("ci failure with commits and links", func(t *testing.T) {
+		event := Event{
+			EventTypeId:  int(util.Fail),
+			PipelineType: string(util.CI),
+			EventTime:    "2024-05-09T12:00:00Z",
+			BaseUrl:      "https://devtron.example.com/",
+			Payload: &Payload{
+				AppName:          "payments",
+				PipelineName:     "ci-payments",
+				TriggeredBy:      "admin@example.com",
+				FailureReason:    "exit code 1",
+				BuildHistoryLink: "/dashboard/app/1/ci-details/2/3/artifacts",
+				MaterialTriggerInfo: &buildBean.MaterialTriggerInfo{
+					GitTriggers: map[int]pipelineConfig.GitCommit{
+						2: {Commit: "0123456789abcdef", CiConfigureSourceValue: "main", GitRepoName: "payments"},
+					},
+				},
+			},
+		}
+		content := buildTeamsCardContent(event)
+		assert.Equal(t, "Build pipeline failed", content.Title)
+		assert.Equal(t, beans.AdaptiveCardColorAttention, content.Color)
+		assert.Equal(t, "by admin@example.com", content.Subtitle)
+		assert.Contains(t, content.Facts, beans.AdaptiveCardFact{Title: "Source", Value: "payments/main@01234567"})
+		assert.Contains(t, content.Facts, beans.AdaptiveCardFact{Title: "Failure reason", Value: "exit code 1"})
+		assert.Equal(t, []beans.AdaptiveCardAction{{Title: "View build", Url: "https://devtron.example.com/dashboard/app/1/ci-details/2/3/artifacts"}}, content.Actions)
+	})
+	t.Run("cd stages and approvals", func(t *testing.T) {
+		assert.Equal(t, "Pre-deployment triggered", getTeamsCardTitle(Event{EventTypeId: int(util.Trigger), PipelineType: string(util.CD), CdWorkflowType: bean.CD_WORKFLOW_TYPE_PRE}))
+		assert.Equal(t, "Deployment pipeline succeeded", getTeamsCardTitle(Event{EventTypeId: int(util.Success), PipelineType: string(util.CD), CdWorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY}))
+		assert.Equal(t, "Image approval requested", getTeamsCardTitle(Event{EventTypeId: int(util.Approval), PipelineType: string(util.CD)}))
+		assert.Equal(t, beans.AdaptiveCardColorWarning, getTeamsCardColor(util.ConfigApproval))
+		assert.Equal(t, "Configuration drift detected", getTeamsCardTitle(Event{EventTypeId: int(util.ConfigDrift), PipelineType: string(util.CD)}))
+		assert.Equal(t, beans.AdaptiveCardColorWarning, getTeamsCardColor(util.ConfigDrift))
+		assert.Equal(t, "CVE exception expiring", getTeamsCardTitle(Event{EventTypeId: int(util.CveExceptionExpiry)}))
+		assert.Equal(t, beans.AdaptiveCardColorWarning, getTeamsCardColor(util.CveExceptionExpiry))
+		assert.Equal(t, "New critical CVEs in deployed image", getTeamsCardTitle(Event{EventTypeId: int(util.NewCriticalCves)}))
+		assert.Equal(t, beans.AdaptiveCardColorAttention, getTeamsCardColor(util.NewCriticalCves))
+	})
+	t.Run("relative links are dropped without base url", func(t *testing.T) {
+		content := buildTeamsCardContent(Event{EventTypeId: int(util.Success), PipelineType: string(util.CD), Payload: &Payload{AppDetailLink: "/dashboard/app/1/details/2/pod"}})
+		assert.Empty(t, content.Actions)
+	})
+}
+
+func TestBuildTeamsAdaptiveCardMessage(t *testing.T) {
+	message := adapter.BuildTeamsAdaptiveCardMessage(&beans.TeamsCardContent{
+		Title: "Deployment pipeline succeeded",
+		Color: beans.AdaptiveCardColorGood,
+		Facts: []beans.AdaptiveCardFact{
+			{Title: "Application", Value: "payments"},
+			{Title: "Environment", Value: ""},
+		},
+		Actions: []beans.AdaptiveCardAction{{Title: "View app details", Url: "https://devtron.example.com/dashboard/app/1/details/2/pod"}},
+	})
+	raw, err := json.Marshal(message)
+	assert.NoError(t, err)
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &payload))
+	assert.Equal(t, "message", payload["type"])
+	attachment := payload["attachments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, beans.AdaptiveCardContentType, attachment["contentType"])
+	card := attachment["content"].(map[string]interface{})
+	assert.Equal(t, beans.AdaptiveCardType, card["type"])
+	assert.Equal(t, beans.AdaptiveCardSchema, card["$schema"])
+	body := card["body"].([]interface{})
+	// title and fact set, the empty environment fact is dropped
+	assert.Len(t, body, 2)
+	facts := body[1].(map[string]interface{})["facts"].([]interface{})
+	assert.Len(t, facts, 1)
+	action := card["actions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, beans.AdaptiveCardActionOpenUrl, action["type"])
+}
+
+func TestGetConfigIdsForDestination(t *testing.T) {
+	settings := []*repository.NotificationSettingsBean{
+		{Config: []repository.ConfigEntry{{Dest: "teams", ConfigId: 1}, {Dest: "slack", ConfigId: 1}}},
+		{Config: []repository.ConfigEntry{{Dest: "teams", ConfigId: 1}, {Dest: "teams", ConfigId: 4}}},
+	}
+	assert.Equal(t, []int{1, 4}, getConfigIdsForDestination(settings, util.Teams))
+	assert.Empty(t, getConfigIdsForDestination(nil, util.Teams))
+}
@@ -165,6 +165,7 @@ const (
 	MandatoryTagNotAdded                 string = "10010"
 	SecurityScanFail                     string = "10011"
 	ApprovalConfigDependentActionFailure string = "10012"
+	ImageSignatureVerificationFail       string = "10013"
 
 	// Feasibility Errors End ----------------
 )
@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ReleaseStatus string
+
+const (
+	ReleaseStatusRunning   ReleaseStatus = "RUNNING"
+	ReleaseStatusPaused    ReleaseStatus = "PAUSED"
+	ReleaseStatusSucceeded ReleaseStatus = "SUCCEEDED"
+	ReleaseStatusFailed    ReleaseStatus = "FAILED"
+	ReleaseStatusAborted   ReleaseStatus = "ABORTED"
+)
+
+type ReleaseWaveStatus string
+
+const (
+	ReleaseWaveStatusPending          ReleaseWaveStatus = "PENDING"
+	ReleaseWaveStatusAwaitingApproval ReleaseWaveStatus = "AWAITING_APPROVAL"
+	ReleaseWaveStatusDeploying        ReleaseWaveStatus = "DEPLOYING"
+	ReleaseWaveStatusVerifying        ReleaseWaveStatus = "VERIFYING"
+	ReleaseWaveStatusSucceeded        ReleaseWaveStatus = "SUCCEEDED"
+	ReleaseWaveStatusFailed           ReleaseWaveStatus = "FAILED"
+	ReleaseWaveStatusSkipped          ReleaseWaveStatus = "SKIPPED"
+)
+
+type ReleaseAppStatus string
+
+const (
+	ReleaseAppStatusPending   ReleaseAppStatus = "PENDING"
+	ReleaseAppStatusDeploying ReleaseAppStatus = "DEPLOYING"
+	ReleaseAppStatusDeployed  ReleaseAppStatus = "DEPLOYED"
+	ReleaseAppStatusHealthy   ReleaseAppStatus = "HEALTHY"
+	ReleaseAppStatusFailed    ReleaseAppStatus = "FAILED"
+)
+
+type DeploymentGroupReleaseRepository interface {
+	// SaveRelease saves the release with its waves and the apps of the waves
+	SaveRelease(release *DeploymentGroupRelease) error
+	UpdateRelease(release *DeploymentGroupRelease) error
+	// UpdateReleaseStatus moves the release from status expected to status updated, returns false if the release
+	// was already moved (by another request or replica)
+	UpdateReleaseStatus(id int, expected, updated ReleaseStatus, userId int32) (bool, error)
+	FindReleaseById(id int) (*DeploymentGroupRelease, error)
+	FindReleasesByDeploymentGroupId(deploymentGroupId int, limit int) ([]*DeploymentGroupRelease, error)
+	FindReleasesByStatus(statuses []ReleaseStatus) ([]*DeploymentGroupRelease, error)
+
+	UpdateWave(wave *DeploymentGroupReleaseWave) error
+	// UpdateWaveStatus moves the wave from status expected to status updated, returns false if the wave
+	// was already moved (by another request or replica)
+	UpdateWaveStatus(id int, expected, updated ReleaseWaveStatus) (bool, error)
+	UpdateApp(app *DeploymentGroupReleaseApp) error
+}
+
+type DeploymentGroupReleaseRepositoryImpl struct {
+	dbConnection *pg.DB
+	Logger       *zap.SugaredLogger
+}
+
+func NewDeploymentGroupReleaseRepositoryImpl(Logger *zap.SugaredLogger, dbConnection *pg.DB) *DeploymentGroupReleaseRepositoryImpl {
+	return &DeploymentGroupReleaseRepositoryImpl{dbConnection: dbConnection, Logger: Logger}
+}
+
+// DeploymentGroupRelease is a release of the artifact CiArtifactId of the parent ci pipeline of a
+// deployment group, released wave by wave
+type DeploymentGroupRelease struct {
+	TableName         struct{}                      `sql:"deployment_group_release" pg:",discard_unknown_columns"`
+	Id                int                           `sql:"id,pk"`
+	DeploymentGroupId int                           `sql:"deployment_group_id,notnull"`
+	CiArtifactId      int                           `sql:"ci_artifact_id,notnull"`
+	Status            ReleaseStatus                 `sql:"status,notnull"`
+	Message           string                        `sql:"message"`
+	FinishedOn        time.Time                     `sql:"finished_on"`
+	Waves             []*DeploymentGroupReleaseWave `sql:"-"`
+	sql.AuditLog
+}
+
+// DeploymentGroupReleaseWave is the snapshot of a wave of the deployment group taken when the release was triggered
+type DeploymentGroupReleaseWave struct {
+	TableName            struct{}                     `sql:"deployment_group_release_wave" pg:",discard_unknown_columns"`
+	Id                   int                          `sql:"id,pk"`
+	ReleaseId            int                          `sql:"release_id,notnull"`
+	Name                 string                       `sql:"name,notnull"`
+	WaveOrder            int                          `sql:"wave_order,notnull"`
+	DependsOn            []string                     `sql:"depends_on" pg:",array"`
+	RequiresApproval     bool                         `sql:"requires_approval,notnull"`
+	HealthGate           bool                         `sql:"health_gate,notnull"`
+	HealthTimeoutMinutes int                          `sql:"health_timeout_minutes,notnull"`
+	Status               ReleaseWaveStatus            `sql:"status,notnull"`
+	ApprovedBy           int32                        `sql:"approved_by"`
+	ApprovedOn           time.Time                    `sql:"approved_on"`
+	StartedOn            time.Time                    `sql:"started_on"`
+	FinishedOn           time.Time                    `sql:"finished_on"`
+	Message              string                       `sql:"message"`
+	Apps                 []*DeploymentGroupReleaseApp `sql:"-"`
+	sql.AuditLog
+}
+
+// DeploymentGroupReleaseApp is the deployment of an app of the wave, CdWorkflowId is set once the deployment is triggered
+type DeploymentGroupReleaseApp struct {
+	TableName     struct{}         `sql:"deployment_group_release_app" pg:",discard_unknown_columns"`
+	Id            int              `sql:"id,pk"`
+	ReleaseWaveId int              `sql:"release_wave_id,notnull"`
+	AppId         int              `sql:"app_id,notnull"`
+	CdPipelineId  int              `sql:"cd_pipeline_id,notnull"`
+	CiArtifactId  int              `sql:"ci_artifact_id,notnull"`
+	CdWorkflowId  int              `sql:"cd_workflow_id"`
+	Status        ReleaseAppStatus `sql:"status,notnull"`
+	Message       string           `sql:"message"`
+	sql.AuditLog
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) SaveRelease(release *DeploymentGroupRelease) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		err := tx.Insert(release)
+		if err != nil {
+			return err
+		}
+		for _, wave := range release.Waves {
+			wave.ReleaseId = release.Id
+			err = tx.Insert(wave)
+			if err != nil {
+				return err
+			}
+			for _, app := range wave.Apps {
+				app.ReleaseWaveId = wave.Id
+				err = tx.Insert(app)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) UpdateRelease(release *DeploymentGroupRelease) error {
+	return impl.dbConnection.Update(release)
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) UpdateReleaseStatus(id int, expected, updated ReleaseStatus, userId int32) (bool, error) {
+	res, err := impl.dbConnection.Model(&DeploymentGroupRelease{}).
+		Set("status = ?", updated).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("status = ?", expected).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) FindReleaseById(id int) (*DeploymentGroupRelease, error) {
+	release := &DeploymentGroupRelease{}
+	err := impl.dbConnection.Model(release).
+		Where("id = ?", id).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	err = impl.dbConnection.Model(&release.Waves).
+		Where("release_id = ?", id).
+		Order("wave_order ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	if len(release.Waves) == 0 {
+		return release, nil
+	}
+	waveIds := make([]int, 0, len(release.Waves))
+	for _, wave := range release.Waves {
+		waveIds = append(waveIds, wave.Id)
+	}
+	var apps []*DeploymentGroupReleaseApp
+	err = impl.dbConnection.Model(&apps).
+		Where("release_wave_id in (?)", pg.In(waveIds)).
+		Order("id ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	for _, wave := range release.Waves {
+		for _, app := range apps {
+			if app.ReleaseWaveId == wave.Id {
+				wave.Apps = append(wave.Apps, app)
+			}
+		}
+	}
+	return release, nil
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) FindReleasesByDeploymentGroupId(deploymentGroupId int, limit int) ([]*DeploymentGroupRelease, error) {
+	var models []*DeploymentGroupRelease
+	err := impl.dbConnection.Model(&models).
+		Where("deployment_group_id = ?", deploymentGroupId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return models, err
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) FindReleasesByStatus(statuses []ReleaseStatus) ([]*DeploymentGroupRelease, error) {
+	var models []*DeploymentGroupRelease
+	err := impl.dbConnection.Model(&models).
+		Where("status in (?)", pg.In(statuses)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) UpdateWave(wave *DeploymentGroupReleaseWave) error {
+	return impl.dbConnection.Update(wave)
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) UpdateWaveStatus(id int, expected, updated ReleaseWaveStatus) (bool, error) {
+	res, err := impl.dbConnection.Model(&DeploymentGroupReleaseWave{}).
+		Set("status = ?", updated).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", expected).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl DeploymentGroupReleaseRepositoryImpl) UpdateApp(app *DeploymentGroupReleaseApp) error {
+	return impl.dbConnection.Update(app)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type DeploymentGroupWaveRepository interface {
+	FindActiveByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentGroupWave, error)
+	// ReplaceWaves deactivates the active waves of the deployment group and saves waves in their place
+	ReplaceWaves(deploymentGroupId int, waves []*DeploymentGroupWave, userId int32) error
+}
+
+type DeploymentGroupWaveRepositoryImpl struct {
+	dbConnection *pg.DB
+	Logger       *zap.SugaredLogger
+}
+
+func NewDeploymentGroupWaveRepositoryImpl(Logger *zap.SugaredLogger, dbConnection *pg.DB) *DeploymentGroupWaveRepositoryImpl {
+	return &DeploymentGroupWaveRepositoryImpl{dbConnection: dbConnection, Logger: Logger}
+}
+
+// DeploymentGroupWave is a set of apps of the deployment group released together, after all
+// the waves in DependsOn (names of waves of the same group) are released
+type DeploymentGroupWave struct {
+	TableName            struct{} `sql:"deployment_group_wave" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	DeploymentGroupId    int      `sql:"deployment_group_id,notnull"`
+	Name                 string   `sql:"name,notnull"`
+	WaveOrder            int      `sql:"wave_order,notnull"`
+	AppIds               []int    `sql:"app_ids" pg:",array"`
+	DependsOn            []string `sql:"depends_on" pg:",array"`
+	RequiresApproval     bool     `sql:"requires_approval,notnull"`
+	HealthGate           bool     `sql:"health_gate,notnull"`
+	HealthTimeoutMinutes int      `sql:"health_timeout_minutes,notnull"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+func (impl DeploymentGroupWaveRepositoryImpl) FindActiveByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentGroupWave, error) {
+	var models []*DeploymentGroupWave
+	err := impl.dbConnection.Model(&models).
+		Where("deployment_group_id = ?", deploymentGroupId).
+		Where("active = ?", true).
+		Order("wave_order ASC").
+		Select()
+	return models, err
+}
+
+func (impl DeploymentGroupWaveRepositoryImpl) ReplaceWaves(deploymentGroupId int, waves []*DeploymentGroupWave, userId int32) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model(&DeploymentGroupWave{}).
+			Set("active = ?", false).
+			Set("updated_on = NOW()").
+			Set("updated_by = ?", userId).
+			Where("deployment_group_id = ?", deploymentGroupId).
+			Where("active = ?", true).
+			Update()
+		if err != nil {
+			return err
+		}
+		for _, wave := range waves {
+			err = tx.Insert(wave)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
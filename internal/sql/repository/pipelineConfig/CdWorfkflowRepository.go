@@ -49,6 +49,8 @@ type CdWorkflowRepository interface {
 	UpdateWorkFlowRunners(wfr []*CdWorkflowRunner) error
 	FindWorkflowRunnerByCdWorkflowId(wfIds []int) ([]*CdWorkflowRunner, error)
 	FindPreviousCdWfRunnerByStatus(pipelineId int, currentWFRunnerId int, status []string) ([]*CdWorkflowRunner, error)
+	// FindLastDeployRunnerBeforeByStatus returns the latest deploy runner of the pipeline older than currentWFRunnerId having one of the statuses
+	FindLastDeployRunnerBeforeByStatus(pipelineId int, currentWFRunnerId int, statuses []string) (*CdWorkflowRunner, error)
 	FindWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
 	FindPreOrPostCdWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
 	FindBasicWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
@@ -204,6 +206,21 @@ func (impl *CdWorkflowRepositoryImpl) FindPreviousCdWfRunnerByStatus(pipelineId
 	return runner, err
 }
 
+func (impl *CdWorkflowRepositoryImpl) FindLastDeployRunnerBeforeByStatus(pipelineId int, currentWFRunnerId int, statuses []string) (*CdWorkflowRunner, error) {
+	runner := &CdWorkflowRunner{}
+	err := impl.dbConnection.
+		Model(runner).
+		Column("cd_workflow_runner.*", "CdWorkflow", "CdWorkflow.CiArtifact").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow_runner.id < ?", currentWFRunnerId).
+		Where("cd_workflow_runner.workflow_type = ?", apiBean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.status in (?)", pg.In(statuses)).
+		Order("cd_workflow_runner.id DESC").
+		Limit(1).
+		Select()
+	return runner, err
+}
+
 func (impl *CdWorkflowRepositoryImpl) SaveWorkFlow(ctx context.Context, wf *CdWorkflow) error {
 	_, span := otel.Tracer("orchestrator").Start(ctx, "cdWorkflowRepository.SaveWorkFlow")
 	defer span.End()
@@ -51,6 +51,13 @@ const (
 	TIMELINE_STATUS_UNABLE_TO_FETCH_STATUS TimelineStatus = "UNABLE_TO_FETCH_STATUS"
 	TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED  TimelineStatus = "DEPLOYMENT_SUPERSEDED"
 	TIMELINE_STATUS_MANIFEST_GENERATED     TimelineStatus = "HELM_PACKAGE_GENERATED" // TODO: remove as this deployment type is not supported
+	// TIMELINE_STATUS_AUTO_ROLLBACK_TRIGGERED - is recorded on a deployment after it is rolled back by the auto rollback policy of the pipeline
+	TIMELINE_STATUS_AUTO_ROLLBACK_TRIGGERED TimelineStatus = "AUTO_ROLLBACK_TRIGGERED"
+	TIMELINE_STATUS_AUTO_ROLLBACK_FAILED    TimelineStatus = "AUTO_ROLLBACK_FAILED"
+	// TIMELINE_STATUS_CANARY_STEP_PROMOTED - is recorded on a canary deployment each time the analysis passes and traffic is promoted to the next step
+	TIMELINE_STATUS_CANARY_STEP_PROMOTED TimelineStatus = "CANARY_STEP_PROMOTED"
+	TIMELINE_STATUS_CANARY_PROMOTED      TimelineStatus = "CANARY_PROMOTED"
+	TIMELINE_STATUS_CANARY_ABORTED       TimelineStatus = "CANARY_ABORTED"
 )
 
 const (
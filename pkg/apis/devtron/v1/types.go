@@ -16,6 +16,12 @@
 
 package v1
 
+import (
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
 // App defines model for app .
 type App struct {
 
@@ -40,6 +46,12 @@ type App struct {
 
 	// Workflow for app
 	Workflow *[]Workflow `json:"workflow,omitempty"`
+
+	// Base deployment template of the app, the environment overrides are in the deployments
+	DeploymentTemplate *DeploymentTemplate `json:"deploymentTemplate,omitempty"`
+
+	// Plugins used in the pre/post steps, the plugin ids of the steps are resolved by identifier and version
+	Plugins []PluginReference `json:"plugins,omitempty"`
 }
 
 // BlueGreenStrategy defines model for blueGreenStrategy.
@@ -76,6 +88,13 @@ type Build struct {
 	// How will this action be initiated
 	Trigger    Trigger `json:"trigger"`
 	WebHookUrl *string `json:"webHookUrl,omitempty"`
+
+	ScanEnabled bool `json:"scanEnabled,omitempty"`
+
+	// Overrides the docker config of the app for this pipeline if set
+	DockerConfig   *DockerConfig `json:"dockerConfig,omitempty"`
+	DockerRegistry string        `json:"dockerRegistry,omitempty"`
+	DockerRepo     string        `json:"dockerRepo,omitempty"`
 }
 
 // BuildMaterial defines model for buildMaterial.
@@ -120,6 +139,14 @@ type DataHolder struct {
 	// Unique identification of resource
 	Source *ResourcePath `json:"source,omitempty"`
 	Type   string        `json:"type"`
+
+	MergeStrategy  models.MergeStrategy          `json:"mergeStrategy,omitempty"`
+	SubPath        bool                          `json:"subPath,omitempty"`
+	FilePermission string                        `json:"filePermission,omitempty"`
+	RoleARN        string                        `json:"roleARN,omitempty"`
+	ESOSecretData  *pipelineBean.ESOSecretData   `json:"esoSecretData,omitempty"`
+	ESOSubPath     []string                      `json:"esoSubPath,omitempty"`
+	ExternalSecret []pipelineBean.ExternalSecret `json:"secretData,omitempty"`
 }
 
 // Deployment defines model for deployment.
@@ -155,6 +182,9 @@ type Deployment struct {
 
 	// How will this action be initiated
 	Trigger *Trigger `json:"trigger,omitempty"`
+
+	DeploymentAppType string `json:"deploymentAppType,omitempty"`
+	IsDigestEnforced  bool   `json:"isDigestEnforced,omitempty"`
 }
 
 // DeploymentStrategy defines model for deploymentStrategy.
@@ -186,6 +216,9 @@ type DeploymentTemplate struct {
 	// Unique identification of resource
 	Source         *ResourcePath          `json:"source,omitempty"`
 	ValuesOverride map[string]interface{} `json:"valuesOverride"`
+
+	// Applies to the environment overrides only
+	MergeStrategy models.MergeStrategy `json:"mergeStrategy,omitempty"`
 }
 
 // DockerConfig defines model for dockerConfig.
@@ -195,6 +228,12 @@ type DockerConfig struct {
 	DockerFileRelativePath string                 `json:"dockerFileRelativePath"`
 	DockerFileRepository   string                 `json:"dockerFileRepository"`
 	GitMaterial            string                 `json:"gitMaterial"`
+
+	// Git url of the build context, the git material is used if empty
+	BuildContextGitMaterial string `json:"buildContextGitMaterial,omitempty"`
+
+	// Build type and its options, the git material ids in it are ignored
+	CiBuildConfig *buildBean.CiBuildConfigBean `json:"ciBuildConfig,omitempty"`
 }
 
 // InheritedProps defines model for inheritedProps.
@@ -241,6 +280,13 @@ type PreBuild Task
 // PreDeployment defines model for preDeployment.
 type PreDeployment Task
 
+// PluginReference defines model for pluginReference.
+type PluginReference struct {
+	Id         int    `json:"id"`
+	Identifier string `json:"identifier"`
+	Version    string `json:"version"`
+}
+
 // RecreateStrategy defines model for recreateStrategy.
 type RecreateStrategy map[string]interface{}
 
@@ -255,6 +301,10 @@ type Repo struct {
 
 	// git url
 	Url *string `json:"url,omitempty"`
+
+	GitProvider     *string  `json:"gitProvider,omitempty"`
+	FetchSubmodules bool     `json:"fetchSubmodules,omitempty"`
+	FilterPattern   []string `json:"filterPattern,omitempty"`
 }
 
 // ResourcePath defines model for resourcePath.
@@ -310,6 +360,9 @@ type Task struct {
 
 	// How will this action be initiated
 	Trigger *Trigger `json:"trigger,omitempty"`
+
+	// Steps of the stage, inline scripts or plugins
+	Steps []*pipelineBean.PipelineStageStepDto `json:"steps,omitempty"`
 }
 
 // Trigger defines model for trigger.
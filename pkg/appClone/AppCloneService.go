@@ -23,6 +23,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/constants"
 	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
@@ -32,10 +33,12 @@ import (
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/bean"
+	gitProviderRead "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/read"
 	pipeline2 "github.com/devtron-labs/devtron/pkg/build/pipeline"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	bean5 "github.com/devtron-labs/devtron/pkg/chart/bean"
 	read3 "github.com/devtron-labs/devtron/pkg/chart/read"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
@@ -50,22 +53,25 @@ type AppCloneService interface {
 	CloneApp(createReq *bean.CreateAppDTO, context context.Context) (*bean.CreateAppDTO, error)
 }
 type AppCloneServiceImpl struct {
-	logger                  *zap.SugaredLogger
-	pipelineBuilder         pipeline.PipelineBuilder
-	attributesService       attributes.AttributesService
-	chartService            chart.ChartService
-	configMapService        pipeline.ConfigMapService
-	appWorkflowService      appWorkflow.AppWorkflowService
-	appListingService       app.AppListingService
-	propertiesConfigService pipeline.PropertiesConfigService
-	pipelineStageService    pipeline.PipelineStageService
-	ciTemplateService       pipeline2.CiTemplateReadService
-	appRepository           app2.AppRepository
-	ciPipelineRepository    pipelineConfig.CiPipelineRepository
-	pipelineRepository      pipelineConfig.PipelineRepository
-	ciPipelineConfigService pipeline.CiPipelineConfigService
-	gitOpsConfigReadService config.GitOpsConfigReadService
-	chartReadService        read3.ChartReadService
+	logger                        *zap.SugaredLogger
+	pipelineBuilder               pipeline.PipelineBuilder
+	attributesService             attributes.AttributesService
+	chartService                  chart.ChartService
+	configMapService              pipeline.ConfigMapService
+	appWorkflowService            appWorkflow.AppWorkflowService
+	appListingService             app.AppListingService
+	propertiesConfigService       pipeline.PropertiesConfigService
+	pipelineStageService          pipeline.PipelineStageService
+	ciTemplateService             pipeline2.CiTemplateReadService
+	appRepository                 app2.AppRepository
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	pipelineRepository            pipelineConfig.PipelineRepository
+	ciPipelineConfigService       pipeline.CiPipelineConfigService
+	gitOpsConfigReadService       config.GitOpsConfigReadService
+	chartReadService              read3.ChartReadService
+	environmentRepository         repository.EnvironmentRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	gitProviderReadService        gitProviderRead.GitProviderReadService
 }
 
 func NewAppCloneServiceImpl(logger *zap.SugaredLogger,
@@ -81,24 +87,30 @@ func NewAppCloneServiceImpl(logger *zap.SugaredLogger,
 	pipelineRepository pipelineConfig.PipelineRepository,
 	ciPipelineConfigService pipeline.CiPipelineConfigService,
 	gitOpsConfigReadService config.GitOpsConfigReadService,
-	chartReadService read3.ChartReadService) *AppCloneServiceImpl {
+	chartReadService read3.ChartReadService,
+	environmentRepository repository.EnvironmentRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	gitProviderReadService gitProviderRead.GitProviderReadService) *AppCloneServiceImpl {
 	return &AppCloneServiceImpl{
-		logger:                  logger,
-		pipelineBuilder:         pipelineBuilder,
-		attributesService:       attributesService,
-		chartService:            chartService,
-		configMapService:        configMapService,
-		appWorkflowService:      appWorkflowService,
-		appListingService:       appListingService,
-		propertiesConfigService: propertiesConfigService,
-		pipelineStageService:    pipelineStageService,
-		ciTemplateService:       ciTemplateService,
-		appRepository:           appRepository,
-		ciPipelineRepository:    ciPipelineRepository,
-		pipelineRepository:      pipelineRepository,
-		ciPipelineConfigService: ciPipelineConfigService,
-		gitOpsConfigReadService: gitOpsConfigReadService,
-		chartReadService:        chartReadService,
+		logger:                        logger,
+		pipelineBuilder:               pipelineBuilder,
+		attributesService:             attributesService,
+		chartService:                  chartService,
+		configMapService:              configMapService,
+		appWorkflowService:            appWorkflowService,
+		appListingService:             appListingService,
+		propertiesConfigService:       propertiesConfigService,
+		pipelineStageService:          pipelineStageService,
+		ciTemplateService:             ciTemplateService,
+		appRepository:                 appRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+		pipelineRepository:            pipelineRepository,
+		ciPipelineConfigService:       ciPipelineConfigService,
+		gitOpsConfigReadService:       gitOpsConfigReadService,
+		chartReadService:              chartReadService,
+		environmentRepository:         environmentRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		gitProviderReadService:        gitProviderReadService,
 	}
 }
 
@@ -119,6 +131,7 @@ type CreateWorkflowMappingDto struct {
 	gitMaterialMapping   map[int]int
 	externalCiPipelineId int
 	oldToNewCDPipelineId map[int]int
+	mapping              *cloneMapping
 }
 
 func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context context.Context) (*bean.CreateAppDTO, error) {
@@ -127,10 +140,19 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 	if err != nil && err != pg.ErrNoRows {
 		return nil, err
 	}
-	//If the template does not exist then don't clone
+	//If the template does not exist then don't clone, templates are looked up on this instance only
+	if templateApp == nil || templateApp.Id == 0 {
+		impl.logger.Warnw("template app does not exist", "id", createReq.TemplateId)
+		userMsg := fmt.Sprintf("template app %d not found, apps can only be cloned from apps of this instance", createReq.TemplateId)
+		return nil, &util.ApiError{
+			Code:            constants.AppDoesNotExist.Code,
+			InternalMessage: "app does not exist",
+			UserMessage:     userMsg,
+		}
+	}
 	//If the template app-type is chart-store app then don't clone
 	//If the template app-type and create request app-type is not same then don't clone
-	if (templateApp == nil && templateApp.Id == 0) || (templateApp.AppType == helper.ChartStoreApp) || (templateApp.AppType != createReq.AppType) {
+	if (templateApp.AppType == helper.ChartStoreApp) || (templateApp.AppType != createReq.AppType) {
 		impl.logger.Warnw("template app does not exist", "id", createReq.TemplateId)
 		err = &util.ApiError{
 			Code:            constants.AppDoesNotExist.Code,
@@ -149,6 +171,11 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 		Description: createReq.GenericNote,
 	}
 	userId := createReq.UserId
+	mapping, err := impl.resolveCloneMapping(context, cloneReq.RefAppId, createReq.AppType == helper.Job, createReq.CloneOptions)
+	if err != nil {
+		impl.logger.Errorw("error in resolving clone options", "refAppId", cloneReq.RefAppId, "err", err)
+		return nil, err
+	}
 	appStatus, err := impl.appListingService.FetchAppStageStatus(cloneReq.RefAppId, int(cloneReq.AppType))
 	if err != nil {
 		return nil, err
@@ -176,17 +203,25 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 		impl.logger.Errorw("status not", "MATERIAL", cloneReq.RefAppId)
 		return app, nil
 	}
-	_, gitMaerialMap, err := impl.CloneGitRepo(cloneReq.RefAppId, newAppId, userId)
+	_, gitMaerialMap, err := impl.CloneGitRepo(cloneReq.RefAppId, newAppId, userId, mapping)
 	if err != nil {
 		impl.logger.Errorw("error in cloning git", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
 		return nil, err
 	}
 
-	_, err = impl.CreateCiTemplate(cloneReq.RefAppId, newAppId, userId, gitMaerialMap)
+	_, err = impl.CreateCiTemplate(cloneReq.RefAppId, newAppId, userId, gitMaerialMap, mapping)
 	if err != nil {
 		impl.logger.Errorw("error in cloning docker template", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
 		return nil, err
 	}
+	if mapping.ciOnly {
+		_, err = impl.CreateWf(cloneReq.RefAppId, newAppId, userId, gitMaerialMap, mapping, context)
+		if err != nil {
+			impl.logger.Errorw("error in creating wf", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
+			return nil, err
+		}
+		return app, nil
+	}
 	if createReq.AppType != helper.Job {
 		if !refAppStatus["TEMPLATE"] {
 			impl.logger.Errorw("status not", "TEMPLATE", cloneReq.RefAppId)
@@ -215,29 +250,29 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 	}
 
 	if createReq.AppType != helper.Job {
-		_, err = impl.CreateEnvCm(context, cloneReq.RefAppId, newAppId, userId)
+		_, err = impl.CreateEnvCm(context, cloneReq.RefAppId, newAppId, userId, mapping)
 		if err != nil {
 			impl.logger.Errorw("error in creating env cm", "err", err)
 			return nil, err
 		}
-		_, err = impl.CreateEnvSecret(context, cloneReq.RefAppId, newAppId, userId)
+		_, err = impl.CreateEnvSecret(context, cloneReq.RefAppId, newAppId, userId, mapping)
 		if err != nil {
 			impl.logger.Errorw("error in creating env secret", "err", err)
 			return nil, err
 		}
-		_, err = impl.createEnvOverride(cloneReq.RefAppId, newAppId, userId, context)
+		_, err = impl.createEnvOverride(cloneReq.RefAppId, newAppId, userId, mapping, context)
 		if err != nil {
 			impl.logger.Errorw("error in cloning  env override", "err", err)
 			return nil, err
 		}
 	} else {
-		_, err := impl.configMapService.ConfigSecretEnvironmentClone(cloneReq.RefAppId, newAppId, userId)
+		_, err := impl.configMapService.ConfigSecretEnvironmentClone(cloneReq.RefAppId, newAppId, mapping.environmentIdMapping(), userId)
 		if err != nil {
 			impl.logger.Errorw("error in cloning cm cs env override", "err", err)
 			return nil, err
 		}
 	}
-	_, err = impl.CreateWf(cloneReq.RefAppId, newAppId, userId, gitMaerialMap, context)
+	_, err = impl.CreateWf(cloneReq.RefAppId, newAppId, userId, gitMaerialMap, mapping, context)
 	if err != nil {
 		impl.logger.Errorw("error in creating wf", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
 		return nil, err
@@ -259,7 +294,7 @@ func (impl *AppCloneServiceImpl) CreateApp(cloneReq *CloneRequest, userId int32)
 	return createRes, err
 }
 
-func (impl *AppCloneServiceImpl) CloneGitRepo(oldAppId, newAppId int, userId int32) (*bean.CreateMaterialDTO, map[int]int, error) {
+func (impl *AppCloneServiceImpl) CloneGitRepo(oldAppId, newAppId int, userId int32, mapping *cloneMapping) (*bean.CreateMaterialDTO, map[int]int, error) {
 	originalApp, err := impl.pipelineBuilder.GetApp(oldAppId)
 	if err != nil {
 		return nil, nil, err
@@ -273,9 +308,9 @@ func (impl *AppCloneServiceImpl) CloneGitRepo(oldAppId, newAppId int, userId int
 	for _, material := range originalApp.Material {
 		gitMaterial := &bean.GitMaterial{
 			Name:          material.Name,
-			Url:           material.Url,
+			Url:           mapping.gitUrl(material.Url),
 			Id:            0,
-			GitProviderId: material.GitProviderId,
+			GitProviderId: mapping.gitProviderId(material.GitProviderId),
 			CheckoutPath:  material.CheckoutPath,
 			FilterPattern: material.FilterPattern,
 		}
@@ -292,7 +327,7 @@ func (impl *AppCloneServiceImpl) CloneGitRepo(oldAppId, newAppId int, userId int
 	return createMaterial, gitMaterialsMap, err
 }
 
-func (impl *AppCloneServiceImpl) CreateCiTemplate(oldAppId, newAppId int, userId int32, gitMaterialMap map[int]int) (*bean.PipelineCreateResponse, error) {
+func (impl *AppCloneServiceImpl) CreateCiTemplate(oldAppId, newAppId int, userId int32, gitMaterialMap map[int]int, mapping *cloneMapping) (*bean.PipelineCreateResponse, error) {
 	refCiConf, err := impl.pipelineBuilder.GetCiPipeline(oldAppId)
 	if err != nil {
 		return nil, err
@@ -323,10 +358,10 @@ func (impl *AppCloneServiceImpl) CreateCiTemplate(oldAppId, newAppId int, userId
 	ciConfRequest := &bean.CiConfigRequest{
 		Id:                0,
 		AppId:             newAppId,
-		DockerRegistry:    refCiConf.DockerRegistry,
+		DockerRegistry:    mapping.dockerRegistry(refCiConf.DockerRegistry),
 		DockerRepository:  refCiConf.DockerRepository,
 		CiBuildConfig:     ciBuildConfig,
-		DockerRegistryUrl: refCiConf.DockerRegistry,
+		DockerRegistryUrl: mapping.dockerRegistry(refCiConf.DockerRegistry),
 		CiTemplateName:    refCiConf.CiTemplateName,
 		UserId:            userId,
 		BeforeDockerBuild: refCiConf.BeforeDockerBuild,
@@ -393,18 +428,22 @@ func (impl *AppCloneServiceImpl) CreateGlobalCM(oldAppId, newAppId int, userId i
 
 }
 
-func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newAppId int, userId int32) (interface{}, error) {
+func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newAppId int, userId int32, mapping *cloneMapping) (interface{}, error) {
 	refEnvs, err := impl.appListingService.FetchOtherEnvironment(ctx, oldAppId)
 	if err != nil {
 		return nil, err
 	}
 	for _, refEnv := range refEnvs {
-		impl.logger.Debugw("cloning cfg for env", "env", refEnv)
+		if !mapping.isEnvironmentSelected(refEnv.EnvironmentId) {
+			continue
+		}
+		environmentId := mapping.environmentId(refEnv.EnvironmentId)
+		impl.logger.Debugw("cloning cfg for env", "env", refEnv, "targetEnvId", environmentId)
 		refCm, err := impl.configMapService.CMEnvironmentFetch(oldAppId, refEnv.EnvironmentId)
 		if err != nil {
 			return nil, err
 		}
-		thisCm, err := impl.configMapService.CMEnvironmentFetch(newAppId, refEnv.EnvironmentId)
+		thisCm, err := impl.configMapService.CMEnvironmentFetch(newAppId, environmentId)
 		if err != nil {
 			return nil, err
 		}
@@ -423,7 +462,7 @@ func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newA
 		for _, cfgData := range cfgDatas {
 			newCm := &bean3.ConfigDataRequest{
 				AppId:         newAppId,
-				EnvironmentId: refEnv.EnvironmentId,
+				EnvironmentId: environmentId,
 				ConfigData:    []*bean3.ConfigData{cfgData},
 				UserId:        userId,
 				Id:            thisCm.Id,
@@ -437,18 +476,22 @@ func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newA
 	return nil, nil
 }
 
-func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId, newAppId int, userId int32) (interface{}, error) {
+func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId, newAppId int, userId int32, mapping *cloneMapping) (interface{}, error) {
 	refEnvs, err := impl.appListingService.FetchOtherEnvironment(ctx, oldAppId)
 	if err != nil {
 		return nil, err
 	}
 	for _, refEnv := range refEnvs {
-		impl.logger.Debugw("cloning cfg for env", "env", refEnv)
+		if !mapping.isEnvironmentSelected(refEnv.EnvironmentId) {
+			continue
+		}
+		environmentId := mapping.environmentId(refEnv.EnvironmentId)
+		impl.logger.Debugw("cloning cfg for env", "env", refEnv, "targetEnvId", environmentId)
 		refCm, err := impl.configMapService.CSEnvironmentFetch(oldAppId, refEnv.EnvironmentId)
 		if err != nil {
 			return nil, err
 		}
-		thisCm, err := impl.configMapService.CSEnvironmentFetch(newAppId, refEnv.EnvironmentId)
+		thisCm, err := impl.configMapService.CSEnvironmentFetch(newAppId, environmentId)
 		if err != nil {
 			return nil, err
 		}
@@ -469,7 +512,7 @@ func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId,
 			configData = append(configData, cfgData)
 			newCm := &bean3.ConfigDataRequest{
 				AppId:         newAppId,
-				EnvironmentId: refEnv.EnvironmentId,
+				EnvironmentId: environmentId,
 				ConfigData:    configData,
 				UserId:        userId,
 				Id:            thisCm.Id,
@@ -483,12 +526,16 @@ func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId,
 	return nil, nil
 }
 
-func (impl *AppCloneServiceImpl) createEnvOverride(oldAppId, newAppId int, userId int32, ctx context.Context) (interface{}, error) {
+func (impl *AppCloneServiceImpl) createEnvOverride(oldAppId, newAppId int, userId int32, mapping *cloneMapping, ctx context.Context) (interface{}, error) {
 	refEnvs, err := impl.appListingService.FetchOtherEnvironment(ctx, oldAppId)
 	if err != nil {
 		return nil, err
 	}
 	for _, refEnv := range refEnvs {
+		if !mapping.isEnvironmentSelected(refEnv.EnvironmentId) {
+			continue
+		}
+		environmentId := mapping.environmentId(refEnv.EnvironmentId)
 
 		chartRefRes, err := impl.chartService.ChartRefAutocompleteForAppOrEnv(oldAppId, refEnv.EnvironmentId)
 		if err != nil {
@@ -502,7 +549,7 @@ func (impl *AppCloneServiceImpl) createEnvOverride(oldAppId, newAppId int, userI
 			impl.logger.Debugw("no env override", "env", refEnv.EnvironmentId)
 			continue
 		}
-		thisEnvProperties, err := impl.propertiesConfigService.GetEnvironmentProperties(newAppId, refEnv.EnvironmentId, chartRefRes.LatestEnvChartRef)
+		thisEnvProperties, err := impl.propertiesConfigService.GetEnvironmentProperties(newAppId, environmentId, chartRefRes.LatestEnvChartRef)
 		if err != nil {
 			return nil, err
 		}
@@ -523,6 +570,11 @@ func (impl *AppCloneServiceImpl) createEnvOverride(oldAppId, newAppId int, userI
 			IsBasicViewLocked: refEnvProperties.EnvironmentConfig.IsBasicViewLocked,
 			CurrentViewEditor: refEnvProperties.EnvironmentConfig.CurrentViewEditor,
 		}
+		if environment := mapping.environment(refEnv.EnvironmentId); environment != nil {
+			envPropertiesReq.EnvironmentId = environment.Id
+			envPropertiesReq.EnvironmentName = environment.Name
+			envPropertiesReq.Namespace = environment.Namespace
+		}
 		createResp, err := impl.propertiesConfigService.CreateEnvironmentProperties(newAppId, envPropertiesReq)
 		if err != nil {
 			if err.Error() == bean2.NOCHARTEXIST {
@@ -609,7 +661,7 @@ func (impl *AppCloneServiceImpl) CreateGlobalSecret(oldAppId, newAppId int, user
 	return thisCm, err
 }
 
-func (impl *AppCloneServiceImpl) CreateWf(oldAppId, newAppId int, userId int32, gitMaterialMapping map[int]int, ctx context.Context) (interface{}, error) {
+func (impl *AppCloneServiceImpl) CreateWf(oldAppId, newAppId int, userId int32, gitMaterialMapping map[int]int, mapping *cloneMapping, ctx context.Context) (interface{}, error) {
 	refAppWFs, err := impl.appWorkflowService.FindAppWorkflows(oldAppId)
 	if err != nil {
 		return nil, err
@@ -621,6 +673,22 @@ func (impl *AppCloneServiceImpl) CreateWf(oldAppId, newAppId int, userId int32,
 		oldToNewCDPipelineId: make(map[int]int),
 	}
 	for _, refAppWF := range refAppWFs {
+		if !mapping.isWorkflowSelected(refAppWF.Name) {
+			impl.logger.Debugw("skipping workflow not selected for clone", "wf", refAppWF.Name)
+			continue
+		}
+		isExternalCiPresent := false
+		for _, awm := range refAppWF.AppWorkflowMappingDto {
+			if awm.Type == appWorkflow2.WEBHOOK {
+				isExternalCiPresent = true
+				break
+			}
+		}
+		if isExternalCiPresent && mapping.ciOnly {
+			// an external ci workflow has cd pipelines only
+			impl.logger.Debugw("skipping external ci workflow in ci only clone", "wf", refAppWF.Name)
+			continue
+		}
 		thisWf := bean4.AppWorkflowDto{
 			Id:                    0,
 			Name:                  refAppWF.Name,
@@ -634,19 +702,13 @@ func (impl *AppCloneServiceImpl) CreateWf(oldAppId, newAppId int, userId int32,
 			return nil, err
 		}
 
-		isExternalCiPresent := false
-		for _, awm := range refAppWF.AppWorkflowMappingDto {
-			if awm.Type == appWorkflow2.WEBHOOK {
-				isExternalCiPresent = true
-				break
-			}
-		}
 		createWorkflowMappingDto := CreateWorkflowMappingDto{
 			newAppId:             newAppId,
 			oldAppId:             oldAppId,
 			newWfId:              thisWf.Id,
 			userId:               userId,
 			oldToNewCDPipelineId: createWorkflowMappingDtoResp.oldToNewCDPipelineId,
+			mapping:              mapping,
 		}
 		var externalCiPipelineId int
 		if isExternalCiPresent {
@@ -726,6 +788,7 @@ func (impl *AppCloneServiceImpl) createWfInstances(refWfMappings []bean4.AppWork
 				refAppName:            refApp.AppName,
 				sourceToNewPipelineId: sourceToNewPipelineIdMapping,
 				externalCiPipelineId:  createWorkflowMappingDto.externalCiPipelineId,
+				mapping:               createWorkflowMappingDto.mapping,
 			}
 			pipeline, err := impl.createClonedCdPipeline(cdCloneReq, ctx)
 			impl.logger.Debugw("cd pipeline created", "pipeline", pipeline)
@@ -761,6 +824,7 @@ func (impl *AppCloneServiceImpl) createWfInstances(refWfMappings []bean4.AppWork
 			gitMaterialMapping:    createWorkflowMappingDto.gitMaterialMapping,
 			refAppName:            refApp.AppName,
 			oldToNewIdForLinkedCD: createWorkflowMappingDto.oldToNewCDPipelineId,
+			mapping:               createWorkflowMappingDto.mapping,
 		}
 		ci, err = impl.CreateCiPipeline(cloneCiPipelineRequest)
 		if err != nil {
@@ -769,6 +833,9 @@ func (impl *AppCloneServiceImpl) createWfInstances(refWfMappings []bean4.AppWork
 		}
 		impl.logger.Debugw("ci created", "ci", ci)
 	}
+	if createWorkflowMappingDto.mapping.ciOnly {
+		return createWorkflowMappingDto, nil
+	}
 
 	for _, refCdMapping := range cdMappings {
 		cdCloneReq := &cloneCdPipelineRequest{
@@ -780,6 +847,7 @@ func (impl *AppCloneServiceImpl) createWfInstances(refWfMappings []bean4.AppWork
 			appWfId:               createWorkflowMappingDto.newWfId,
 			refAppName:            refApp.AppName,
 			sourceToNewPipelineId: sourceToNewPipelineIdMapping,
+			mapping:               createWorkflowMappingDto.mapping,
 		}
 		pipeline, err := impl.createClonedCdPipeline(cdCloneReq, ctx)
 		if err != nil {
@@ -807,6 +875,7 @@ type cloneCiPipelineRequest struct {
 	gitMaterialMapping    map[int]int
 	refAppName            string
 	oldToNewIdForLinkedCD map[int]int
+	mapping               *cloneMapping
 }
 
 func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (*bean.CiConfigRequest, error) {
@@ -852,7 +921,7 @@ func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (
 			Id:            0,
 			Source: &bean.SourceTypeConfig{
 				Type:  refCiMaterial.Source.Type,
-				Value: refCiMaterial.Source.Value,
+				Value: req.mapping.sourceValue(refCiMaterial.Source),
 				Regex: refCiMaterial.Source.Regex,
 			},
 		}
@@ -908,7 +977,7 @@ func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (
 			IsDockerConfigOverridden: refCiPipeline.IsDockerConfigOverridden,
 			PreBuildStage:            preStageDetail,
 			PostBuildStage:           postStageDetail,
-			EnvironmentId:            refCiPipeline.EnvironmentId,
+			EnvironmentId:            req.mapping.environmentId(refCiPipeline.EnvironmentId),
 			ScanEnabled:              refCiPipeline.ScanEnabled,
 			PipelineType:             refCiPipeline.PipelineType,
 		},
@@ -951,7 +1020,7 @@ func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (
 		templateOverride.GitMaterialId = gitMaterialId
 		ciBuildConfig.Id = 0
 		ciPatchReq.CiPipeline.DockerConfigOverride = bean.DockerConfigOverride{
-			DockerRegistry:   templateOverride.DockerRegistryId,
+			DockerRegistry:   req.mapping.dockerRegistry(templateOverride.DockerRegistryId),
 			DockerRepository: templateOverride.DockerRepository,
 			CiBuildConfig:    ciBuildConfig,
 		}
@@ -971,6 +1040,7 @@ type cloneCdPipelineRequest struct {
 	refAppName            string
 	sourceToNewPipelineId map[int]int
 	externalCiPipelineId  int
+	mapping               *cloneMapping
 }
 
 func (impl *AppCloneServiceImpl) createClonedCdPipeline(req *cloneCdPipelineRequest, ctx context.Context) (*bean.CdPipelines, error) {
@@ -993,12 +1063,16 @@ func (impl *AppCloneServiceImpl) createClonedCdPipeline(req *cloneCdPipelineRequ
 	if strings.HasPrefix(pipelineName, req.refAppName) {
 		pipelineName = strings.Replace(pipelineName, req.refAppName+"-", "", 1)
 	}
+	environmentId, namespace := refCdPipeline.EnvironmentId, refCdPipeline.Namespace
+	if environment := req.mapping.environment(refCdPipeline.EnvironmentId); environment != nil {
+		environmentId, namespace = environment.Id, environment.Namespace
+	}
 	// by default all deployment types are allowed
 	AllowedDeploymentAppTypes := map[string]bool{
 		util.PIPELINE_DEPLOYMENT_TYPE_ACD:  true,
 		util.PIPELINE_DEPLOYMENT_TYPE_HELM: true,
 	}
-	deploymentAppConfigForEnvironment, err := impl.attributesService.GetDeploymentEnforcementConfig(refCdPipeline.EnvironmentId)
+	deploymentAppConfigForEnvironment, err := impl.attributesService.GetDeploymentEnforcementConfig(environmentId)
 	if err != nil {
 		impl.logger.Errorw("error in fetching deployment config for environment", "err", err)
 	}
@@ -1035,12 +1109,12 @@ func (impl *AppCloneServiceImpl) createClonedCdPipeline(req *cloneCdPipelineRequ
 
 	cdPipeline := &bean.CDPipelineConfigObject{
 		Id:                            0,
-		EnvironmentId:                 refCdPipeline.EnvironmentId,
+		EnvironmentId:                 environmentId,
 		CiPipelineId:                  req.ciPipelineId,
 		TriggerType:                   refCdPipeline.TriggerType,
 		Name:                          pipelineName,
 		Strategies:                    refCdPipeline.Strategies,
-		Namespace:                     refCdPipeline.Namespace,
+		Namespace:                     namespace,
 		AppWorkflowId:                 req.appWfId,
 		DeploymentTemplate:            refCdPipeline.DeploymentTemplate,
 		PreStage:                      refCdPipeline.PreStage, //FIXME
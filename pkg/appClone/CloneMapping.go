@@ -0,0 +1,320 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appClone
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"net/http"
+	"strings"
+)
+
+// cloneMapping is the resolved form of bean.CloneOptions, every lookup falls back to the template value when the
+// value is not mapped
+type cloneMapping struct {
+	ciOnly bool
+	// workflows are the selected workflow names, nil selects all
+	workflows map[string]bool
+	// environmentIds are the template environments deployed by the selected workflows, nil selects all
+	environmentIds   map[int]bool
+	environments     map[int]*repository.Environment
+	dockerRegistries map[string]string
+	gitProviderIds   map[int]int
+	gitUrlRewrites   []*bean.GitUrlRewrite
+	branches         map[string]string
+}
+
+func (mapping *cloneMapping) isWorkflowSelected(name string) bool {
+	return mapping.workflows == nil || mapping.workflows[name]
+}
+
+func (mapping *cloneMapping) isEnvironmentSelected(environmentId int) bool {
+	return mapping.environmentIds == nil || mapping.environmentIds[environmentId]
+}
+
+// environment returns the environment of the clone mapped to the template environment, nil if not mapped
+func (mapping *cloneMapping) environment(environmentId int) *repository.Environment {
+	return mapping.environments[environmentId]
+}
+
+func (mapping *cloneMapping) environmentId(environmentId int) int {
+	if environment, ok := mapping.environments[environmentId]; ok {
+		return environment.Id
+	}
+	return environmentId
+}
+
+func (mapping *cloneMapping) environmentIdMapping() map[int]int {
+	environmentIds := make(map[int]int, len(mapping.environments))
+	for sourceEnvironmentId, environment := range mapping.environments {
+		environmentIds[sourceEnvironmentId] = environment.Id
+	}
+	return environmentIds
+}
+
+func (mapping *cloneMapping) dockerRegistry(registry string) string {
+	if targetRegistry, ok := mapping.dockerRegistries[registry]; ok {
+		return targetRegistry
+	}
+	return registry
+}
+
+func (mapping *cloneMapping) gitProviderId(gitProviderId int) int {
+	if targetGitProviderId, ok := mapping.gitProviderIds[gitProviderId]; ok {
+		return targetGitProviderId
+	}
+	return gitProviderId
+}
+
+func (mapping *cloneMapping) gitUrl(url string) string {
+	for _, rewrite := range mapping.gitUrlRewrites {
+		if strings.HasPrefix(url, rewrite.SourcePrefix) {
+			return rewrite.TargetPrefix + strings.TrimPrefix(url, rewrite.SourcePrefix)
+		}
+	}
+	return url
+}
+
+// sourceValue returns the value of the ci material source, with the fixed branch renamed
+func (mapping *cloneMapping) sourceValue(source *bean.SourceTypeConfig) string {
+	if source.Type != constants.SOURCE_TYPE_BRANCH_FIXED {
+		return source.Value
+	}
+	if targetBranch, ok := mapping.branches[source.Value]; ok {
+		return targetBranch
+	}
+	return source.Value
+}
+
+// getSharedTargetEnvironments returns the problems of the environment mappings which map two template environments
+// into the same environment
+func getSharedTargetEnvironments(environmentMappings []*bean.EnvironmentMapping) []string {
+	var problems []string
+	targetEnvironmentSources := make(map[int]int, len(environmentMappings))
+	for _, environmentMapping := range environmentMappings {
+		sourceEnvironmentId, ok := targetEnvironmentSources[environmentMapping.TargetEnvironmentId]
+		if !ok {
+			targetEnvironmentSources[environmentMapping.TargetEnvironmentId] = environmentMapping.SourceEnvironmentId
+			continue
+		}
+		if sourceEnvironmentId != environmentMapping.SourceEnvironmentId {
+			problems = append(problems, fmt.Sprintf("environments %d and %d are both mapped to environment %d", sourceEnvironmentId, environmentMapping.SourceEnvironmentId, environmentMapping.TargetEnvironmentId))
+		}
+	}
+	return problems
+}
+
+// getEnvironmentCollisions returns the problems of the environment mappings which would make two template environments
+// clone into the same environment, given the template environments whose configuration is cloned
+func (mapping *cloneMapping) getEnvironmentCollisions(sourceEnvironmentIds []int) []string {
+	var problems []string
+	unmappedEnvironmentIds := make(map[int]bool)
+	for _, sourceEnvironmentId := range sourceEnvironmentIds {
+		if _, ok := mapping.environments[sourceEnvironmentId]; !ok && mapping.isEnvironmentSelected(sourceEnvironmentId) {
+			unmappedEnvironmentIds[sourceEnvironmentId] = true
+		}
+	}
+	for _, sourceEnvironmentId := range sourceEnvironmentIds {
+		environment, ok := mapping.environments[sourceEnvironmentId]
+		if !ok || !mapping.isEnvironmentSelected(sourceEnvironmentId) {
+			continue
+		}
+		if unmappedEnvironmentIds[environment.Id] {
+			problems = append(problems, fmt.Sprintf("environment %d is mapped to environment %d, which is also cloned as is, map environment %d as well", sourceEnvironmentId, environment.Id, environment.Id))
+		}
+	}
+	return problems
+}
+
+// resolveCloneMapping validates the clone options against the template app and the instance, all the problems found
+// are returned together. Nothing is created until the options are valid.
+func (impl *AppCloneServiceImpl) resolveCloneMapping(ctx context.Context, refAppId int, isJob bool, options *bean.CloneOptions) (*cloneMapping, error) {
+	mapping := &cloneMapping{
+		environments:     make(map[int]*repository.Environment),
+		dockerRegistries: make(map[string]string),
+		gitProviderIds:   make(map[int]int),
+		branches:         make(map[string]string),
+	}
+	if options == nil {
+		return mapping, nil
+	}
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	mapping.ciOnly = options.IsCiOnly() && !isJob
+	mapping.gitUrlRewrites = options.GitUrlRewrites
+
+	problems = append(problems, getSharedTargetEnvironments(options.EnvironmentMappings)...)
+	for _, environmentMapping := range options.EnvironmentMappings {
+		if _, ok := mapping.environments[environmentMapping.SourceEnvironmentId]; ok {
+			addProblem("environment %d is mapped more than once", environmentMapping.SourceEnvironmentId)
+			continue
+		}
+		environment, err := impl.environmentRepository.FindById(environmentMapping.TargetEnvironmentId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environment", "envId", environmentMapping.TargetEnvironmentId, "err", err)
+			return nil, err
+		}
+		if util.IsErrNoRows(err) {
+			addProblem("environment %d not found", environmentMapping.TargetEnvironmentId)
+			continue
+		}
+		mapping.environments[environmentMapping.SourceEnvironmentId] = environment
+	}
+	for _, registryMapping := range options.DockerRegistryMappings {
+		if _, ok := mapping.dockerRegistries[registryMapping.SourceRegistry]; ok {
+			addProblem("docker registry %s is mapped more than once", registryMapping.SourceRegistry)
+			continue
+		}
+		_, err := impl.dockerArtifactStoreRepository.FindOne(registryMapping.TargetRegistry)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching docker registry", "registry", registryMapping.TargetRegistry, "err", err)
+			return nil, err
+		}
+		if util.IsErrNoRows(err) {
+			addProblem("docker registry %s not found", registryMapping.TargetRegistry)
+			continue
+		}
+		mapping.dockerRegistries[registryMapping.SourceRegistry] = registryMapping.TargetRegistry
+	}
+	if len(options.GitProviderMappings) > 0 {
+		gitProviders, err := impl.gitProviderReadService.GetAll()
+		if err != nil {
+			impl.logger.Errorw("error in fetching git providers", "err", err)
+			return nil, err
+		}
+		gitProviderIds := make(map[int]bool, len(gitProviders))
+		for _, gitProvider := range gitProviders {
+			gitProviderIds[gitProvider.Id] = true
+		}
+		for _, gitProviderMapping := range options.GitProviderMappings {
+			if _, ok := mapping.gitProviderIds[gitProviderMapping.SourceGitProviderId]; ok {
+				addProblem("git provider %d is mapped more than once", gitProviderMapping.SourceGitProviderId)
+				continue
+			}
+			if !gitProviderIds[gitProviderMapping.TargetGitProviderId] {
+				addProblem("git provider %d not found", gitProviderMapping.TargetGitProviderId)
+				continue
+			}
+			mapping.gitProviderIds[gitProviderMapping.SourceGitProviderId] = gitProviderMapping.TargetGitProviderId
+		}
+	}
+	for _, branchRewrite := range options.BranchRewrites {
+		if _, ok := mapping.branches[branchRewrite.SourceBranch]; ok {
+			addProblem("branch %s is rewritten more than once", branchRewrite.SourceBranch)
+			continue
+		}
+		mapping.branches[branchRewrite.SourceBranch] = branchRewrite.TargetBranch
+	}
+	if len(options.Workflows) > 0 {
+		workflowProblems, err := impl.selectWorkflows(refAppId, isJob, options.Workflows, mapping)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, workflowProblems...)
+	}
+	if len(mapping.environments) > 0 && !mapping.ciOnly {
+		sourceEnvironmentIds, err := impl.getTemplateEnvironmentIds(ctx, refAppId, isJob)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, mapping.getEnvironmentCollisions(sourceEnvironmentIds)...)
+	}
+
+	if len(problems) > 0 {
+		userMsg := fmt.Sprintf("invalid clone options: %s", strings.Join(problems, "; "))
+		return nil, util.NewApiError(http.StatusBadRequest, userMsg, userMsg)
+	}
+	return mapping, nil
+}
+
+// selectWorkflows sets the selected workflows of the mapping and, for apps, the environments they deploy to
+func (impl *AppCloneServiceImpl) selectWorkflows(refAppId int, isJob bool, workflowNames []string, mapping *cloneMapping) ([]string, error) {
+	refAppWFs, err := impl.appWorkflowService.FindAppWorkflows(refAppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workflows", "appId", refAppId, "err", err)
+		return nil, err
+	}
+	refWorkflowNames := make(map[string]bool, len(refAppWFs))
+	for _, refAppWF := range refAppWFs {
+		refWorkflowNames[refAppWF.Name] = true
+	}
+	var problems []string
+	mapping.workflows = make(map[string]bool, len(workflowNames))
+	for _, workflowName := range workflowNames {
+		if !refWorkflowNames[workflowName] {
+			problems = append(problems, fmt.Sprintf("workflow %s not found", workflowName))
+			continue
+		}
+		mapping.workflows[workflowName] = true
+	}
+	if isJob {
+		return problems, nil
+	}
+	refPipelines, err := impl.pipelineRepository.FindActiveByAppId(refAppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines", "appId", refAppId, "err", err)
+		return nil, err
+	}
+	pipelineEnvironmentIds := make(map[int]int, len(refPipelines))
+	for _, refPipeline := range refPipelines {
+		pipelineEnvironmentIds[refPipeline.Id] = refPipeline.EnvironmentId
+	}
+	mapping.environmentIds = make(map[int]bool)
+	for _, refAppWF := range refAppWFs {
+		if !mapping.workflows[refAppWF.Name] {
+			continue
+		}
+		for _, refWfMapping := range refAppWF.AppWorkflowMappingDto {
+			if refWfMapping.Type == appWorkflow2.CDPIPELINE {
+				mapping.environmentIds[pipelineEnvironmentIds[refWfMapping.ComponentId]] = true
+			}
+		}
+	}
+	return problems, nil
+}
+
+// getTemplateEnvironmentIds returns the environments of the template whose configuration or pipelines are cloned
+func (impl *AppCloneServiceImpl) getTemplateEnvironmentIds(ctx context.Context, refAppId int, isJob bool) ([]int, error) {
+	var environmentIds []int
+	if isJob {
+		envOverrides, err := impl.configMapService.ConfigSecretEnvironmentGet(refAppId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching env overrides of job", "appId", refAppId, "err", err)
+			return nil, err
+		}
+		for _, envOverride := range envOverrides {
+			environmentIds = append(environmentIds, envOverride.EnvironmentId)
+		}
+		return environmentIds, nil
+	}
+	refEnvs, err := impl.appListingService.FetchOtherEnvironment(ctx, refAppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "appId", refAppId, "err", err)
+		return nil, err
+	}
+	for _, refEnv := range refEnvs {
+		environmentIds = append(environmentIds, refEnv.EnvironmentId)
+	}
+	return environmentIds, nil
+}
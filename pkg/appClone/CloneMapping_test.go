@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appClone
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCloneMapping(t *testing.T) {
+	mapping := &cloneMapping{
+		environments:     map[int]*repository.Environment{1: {Id: 11, Namespace: "prod-eu"}},
+		dockerRegistries: map[string]string{"docker-hub": "ecr-eu"},
+		gitProviderIds:   map[int]int{2: 3},
+		gitUrlRewrites: []*bean.GitUrlRewrite{
+			{SourcePrefix: "https://github.com/acme/", TargetPrefix: "https://gitlab.acme.io/platform/"},
+			{SourcePrefix: "https://github.com/", TargetPrefix: "https://mirror.acme.io/"},
+		},
+		branches: map[string]string{"main": "release"},
+	}
+	assert.Equal(t, 11, mapping.environmentId(1))
+	assert.Equal(t, 5, mapping.environmentId(5), "unmapped environment is kept")
+	assert.Nil(t, mapping.environment(5))
+	assert.Equal(t, map[int]int{1: 11}, mapping.environmentIdMapping())
+	assert.Equal(t, "ecr-eu", mapping.dockerRegistry("docker-hub"))
+	assert.Equal(t, "quay", mapping.dockerRegistry("quay"))
+	assert.Equal(t, 3, mapping.gitProviderId(2))
+	assert.Equal(t, 4, mapping.gitProviderId(4))
+
+	assert.Equal(t, "https://gitlab.acme.io/platform/payments.git", mapping.gitUrl("https://github.com/acme/payments.git"), "first matching rewrite wins")
+	assert.Equal(t, "https://mirror.acme.io/other/lib.git", mapping.gitUrl("https://github.com/other/lib.git"))
+	assert.Equal(t, "git@github.com:acme/payments.git", mapping.gitUrl("git@github.com:acme/payments.git"))
+
+	assert.Equal(t, "release", mapping.sourceValue(&bean.SourceTypeConfig{Type: constants.SOURCE_TYPE_BRANCH_FIXED, Value: "main"}))
+	assert.Equal(t, "develop", mapping.sourceValue(&bean.SourceTypeConfig{Type: constants.SOURCE_TYPE_BRANCH_FIXED, Value: "develop"}))
+	assert.Equal(t, "main", mapping.sourceValue(&bean.SourceTypeConfig{Type: constants.SOURCE_TYPE_BRANCH_REGEX, Value: "main"}), "only fixed branches are rewritten")
+
+	assert.True(t, mapping.isWorkflowSelected("any"), "all workflows are selected without a subset")
+	assert.True(t, mapping.isEnvironmentSelected(7))
+	mapping.workflows = map[string]bool{"build-deploy": true}
+	mapping.environmentIds = map[int]bool{1: true}
+	assert.True(t, mapping.isWorkflowSelected("build-deploy"))
+	assert.False(t, mapping.isWorkflowSelected("hotfix"))
+	assert.False(t, mapping.isEnvironmentSelected(7))
+}
+
+func TestGetSharedTargetEnvironments(t *testing.T) {
+	problems := getSharedTargetEnvironments([]*bean.EnvironmentMapping{
+		{SourceEnvironmentId: 1, TargetEnvironmentId: 11},
+		{SourceEnvironmentId: 2, TargetEnvironmentId: 11},
+		{SourceEnvironmentId: 3, TargetEnvironmentId: 12},
+	})
+	assert.Equal(t, []string{"environments 1 and 2 are both mapped to environment 11"}, problems)
+
+	problems = getSharedTargetEnvironments([]*bean.EnvironmentMapping{
+		{SourceEnvironmentId: 1, TargetEnvironmentId: 2},
+		{SourceEnvironmentId: 2, TargetEnvironmentId: 1},
+	})
+	assert.Empty(t, problems, "swapped environments are mapped one to one")
+}
+
+func TestGetEnvironmentCollisions(t *testing.T) {
+	mapping := &cloneMapping{
+		environments: map[int]*repository.Environment{1: {Id: 2}, 3: {Id: 4}},
+	}
+	problems := mapping.getEnvironmentCollisions([]int{1, 2, 3})
+	assert.Equal(t, []string{"environment 1 is mapped to environment 2, which is also cloned as is, map environment 2 as well"}, problems)
+
+	assert.Empty(t, mapping.getEnvironmentCollisions([]int{1, 3}), "target environments which are not template environments are free")
+
+	mapping.environments[2] = &repository.Environment{Id: 1}
+	assert.Empty(t, mapping.getEnvironmentCollisions([]int{1, 2, 3}), "swapped environments are both remapped")
+
+	delete(mapping.environments, 2)
+	mapping.environmentIds = map[int]bool{1: true, 3: true}
+	assert.Empty(t, mapping.getEnvironmentCollisions([]int{1, 2, 3}), "environments outside the selected workflows are not cloned")
+}
@@ -0,0 +1,2156 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	appWorkflowRepo "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	repoHelper "github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode/adapter"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode/helper"
+	"github.com/devtron-labs/devtron/pkg/appClone/batch"
+	"github.com/devtron-labs/devtron/pkg/appWorkflow"
+	appWorkflowBean "github.com/devtron-labs/devtron/pkg/appWorkflow/bean"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	pkgBean "github.com/devtron-labs/devtron/pkg/bean"
+	gitProviderRead "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/read"
+	buildPipeline "github.com/devtron-labs/devtron/pkg/build/pipeline"
+	buildCommonBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
+	chartBean "github.com/devtron-labs/devtron/pkg/chart/bean"
+	"github.com/devtron-labs/devtron/pkg/chart/read"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
+	chartRefRead "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/read"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService"
+	draftRead "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/read"
+	pipelineStageRepository "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	pluginRepository "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"k8s.io/utils/pointer"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AppAsCodeService exports a devtron app as a bundle and applies a bundle to an app declaratively. The bundle is the
+// app of the batch operation api (v1.App): the git materials (repo), the docker config, the deployment template, the
+// config maps and secrets and the workflows with their build and deployment pipelines, a deployment carrying the config
+// overrides of its environment. The components are matched with the ones of the app by name, so a bundle can be kept
+// in git and applied to the same or another app, on this or another devtron instance. The operations, sources and
+// api versions of the components are not used.
+type AppAsCodeService interface {
+	// Export returns the bundle of the app, secret values are masked for the users without admin access
+	Export(ctx context.Context, appId int, userHasAdminAccess bool) (*v1.App, error)
+	// Plan compares the bundle with the app and returns the changes applying it would make, nothing is saved
+	Plan(ctx context.Context, request *bean.ImportRequest) (*bean.Plan, error)
+	// Apply makes the changes of the plan in order. A plan with conflicts is rejected. Each change is saved by the
+	// service owning the component in its own transaction, so the changes saved before a failing one are kept and are
+	// listed in the error. Applying the same bundle again continues from the failed change.
+	Apply(ctx context.Context, request *bean.ImportRequest, userMetadata *userBean.UserMetadata) (*bean.ApplyResponse, error)
+}
+
+type AppAsCodeServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	pipelineBuilder           pipeline.PipelineBuilder
+	appWorkflowService        appWorkflow.AppWorkflowService
+	pipelineStageService      pipeline.PipelineStageService
+	configMapService          pipeline.ConfigMapService
+	propertiesConfigService   pipeline.PropertiesConfigService
+	draftAwareConfigService   draftAwareConfigService.DraftAwareConfigService
+	configDraftReadService    draftRead.ConfigDraftReadService
+	chartReadService          read.ChartReadService
+	chartRefReadService       chartRefRead.ChartRefReadService
+	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService
+	ciTemplateReadService     buildPipeline.CiTemplateReadService
+	gitProviderReadService    gitProviderRead.GitProviderReadService
+	globalPluginRepository    pluginRepository.GlobalPluginRepository
+	chartRepository           chartRepoRepository.ChartRepository
+	pipelineRepository        pipelineConfig.PipelineRepository
+	appRepository             app.AppRepository
+	environmentRepository     repository.EnvironmentRepository
+}
+
+func NewAppAsCodeServiceImpl(logger *zap.SugaredLogger,
+	pipelineBuilder pipeline.PipelineBuilder,
+	appWorkflowService appWorkflow.AppWorkflowService,
+	pipelineStageService pipeline.PipelineStageService,
+	configMapService pipeline.ConfigMapService,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	draftAwareConfigService draftAwareConfigService.DraftAwareConfigService,
+	configDraftReadService draftRead.ConfigDraftReadService,
+	chartReadService read.ChartReadService,
+	chartRefReadService chartRefRead.ChartRefReadService,
+	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
+	ciTemplateReadService buildPipeline.CiTemplateReadService,
+	gitProviderReadService gitProviderRead.GitProviderReadService,
+	globalPluginRepository pluginRepository.GlobalPluginRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	appRepository app.AppRepository,
+	environmentRepository repository.EnvironmentRepository) *AppAsCodeServiceImpl {
+	return &AppAsCodeServiceImpl{
+		logger:                    logger,
+		pipelineBuilder:           pipelineBuilder,
+		appWorkflowService:        appWorkflowService,
+		pipelineStageService:      pipelineStageService,
+		configMapService:          configMapService,
+		propertiesConfigService:   propertiesConfigService,
+		draftAwareConfigService:   draftAwareConfigService,
+		configDraftReadService:    configDraftReadService,
+		chartReadService:          chartReadService,
+		chartRefReadService:       chartRefReadService,
+		deployedAppMetricsService: deployedAppMetricsService,
+		ciTemplateReadService:     ciTemplateReadService,
+		gitProviderReadService:    gitProviderReadService,
+		globalPluginRepository:    globalPluginRepository,
+		chartRepository:           chartRepository,
+		pipelineRepository:        pipelineRepository,
+		appRepository:             appRepository,
+		environmentRepository:     environmentRepository,
+	}
+}
+
+// appState is the app as a bundle along with the ids of its components. The ids of the components created while
+// applying a bundle are added as they are created, so that the later changes can refer to them.
+type appState struct {
+	appId   int
+	appName string
+	bundle  *v1.App
+	// skipped lists the workflows left out of the bundle, like the ones with a linked ci pipeline
+	skipped []string
+	// gitMaterialIds by checkout path
+	gitMaterialIds map[string]int
+	// ciTemplate is nil if the build config is not saved yet
+	ciTemplate *pkgBean.CiConfigRequest
+	// baseChart is nil if the deployment template is not saved yet
+	baseChart *chartBean.TemplateRequest
+	workflows map[string]*workflowIds
+	// ciPipelines and cdPipelines by their names in the bundle
+	ciPipelines   map[string]*pkgBean.CiPipeline
+	cdPipelines   map[string]*pkgBean.CDPipelineConfigObject
+	ciPipelineIds map[string]int
+	cdPipelineIds map[string]int
+}
+
+type workflowIds struct {
+	id int
+	// externalCiId is the id of the webhook the workflow is triggered by, 0 if it has a ci pipeline
+	externalCiId int
+}
+
+// desiredState is the bundle to apply with its references to the instance level resources resolved
+type desiredState struct {
+	bundle         *v1.App
+	gitProviderIds map[string]int
+	// gitMaterialPaths are the checkout paths of the git materials by url, the build materials and the docker
+	// configs refer to the git materials by url
+	gitMaterialPaths map[string]string
+	environments     map[string]*repository.Environment
+	chartRefIds      map[string]int
+}
+
+type importState struct {
+	appId   int
+	userId  int32
+	prune   bool
+	current *appState
+	desired *desiredState
+}
+
+// getGitMaterialIds returns the ids of the git materials by url, a material created on apply is included once created
+func (state *importState) getGitMaterialIds() map[string]int {
+	gitMaterialIds := make(map[string]int, len(state.desired.gitMaterialPaths))
+	for url, checkoutPath := range state.desired.gitMaterialPaths {
+		if id, ok := state.current.gitMaterialIds[checkoutPath]; ok {
+			gitMaterialIds[url] = id
+		}
+	}
+	return gitMaterialIds
+}
+
+// planStep applies the change of a plan item, apply is nil for the items which are not changes
+type planStep struct {
+	item  *bean.PlanItem
+	apply func(ctx context.Context, userMetadata *userBean.UserMetadata) error
+	// savedAsDraft is set by apply when the change was saved as a draft of the protected environment
+	savedAsDraft bool
+}
+
+func (impl *AppAsCodeServiceImpl) Export(ctx context.Context, appId int, userHasAdminAccess bool) (*v1.App, error) {
+	state, err := impl.getAppState(appId)
+	if err != nil {
+		return nil, err
+	}
+	bundle := state.bundle
+	bundle.Plugins, err = impl.getPluginReferences(getTasks(bundle)...)
+	if err != nil {
+		return nil, err
+	}
+	if !userHasAdminAccess {
+		secretLists := [][]v1.DataHolder{bundle.Secrets}
+		for _, deployment := range getDeployments(bundle) {
+			secretLists = append(secretLists, deployment.Secrets)
+		}
+		for _, secrets := range secretLists {
+			for i := range secrets {
+				secrets[i].Data = helper.MaskSecretData(secrets[i].Data)
+			}
+		}
+	}
+	return bundle, nil
+}
+
+func (impl *AppAsCodeServiceImpl) Plan(ctx context.Context, request *bean.ImportRequest) (*bean.Plan, error) {
+	state, steps, err := impl.getPlanSteps(request)
+	if err != nil {
+		return nil, err
+	}
+	return getPlan(state, steps), nil
+}
+
+func (impl *AppAsCodeServiceImpl) Apply(ctx context.Context, request *bean.ImportRequest, userMetadata *userBean.UserMetadata) (*bean.ApplyResponse, error) {
+	state, steps, err := impl.getPlanSteps(request)
+	if err != nil {
+		return nil, err
+	}
+	plan := getPlan(state, steps)
+	if plan.HasConflicts() {
+		errMsg := "the bundle has conflicting changes, see the plan for details"
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	response := &bean.ApplyResponse{Applied: make([]*bean.PlanItem, 0), Warnings: plan.Warnings}
+	draftEnvs := make(map[string]bool)
+	for _, step := range steps {
+		if !step.item.Action.IsChange() {
+			continue
+		}
+		err = step.apply(ctx, userMetadata)
+		if err != nil {
+			impl.logger.Errorw("error in applying bundle change", "appId", request.AppId, "kind", step.item.Kind, "name", step.item.Name, "environment", step.item.Environment, "err", err)
+			return nil, getApplyError(step.item, response.Applied, err)
+		}
+		response.Applied = append(response.Applied, step.item)
+		if step.savedAsDraft && !draftEnvs[step.item.Environment] {
+			draftEnvs[step.item.Environment] = true
+			response.SavedAsDraft = append(response.SavedAsDraft, step.item.Environment)
+		}
+		if step.item.EnvironmentId > 0 && isConfigKind(step.item.Kind) && !draftEnvs[step.item.Environment] {
+			isProtected, err := impl.configDraftReadService.IsConfigProtected(request.AppId, step.item.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in checking config protection", "envId", step.item.EnvironmentId, "err", err)
+				return nil, getApplyError(step.item, response.Applied, err)
+			}
+			if isProtected {
+				draftEnvs[step.item.Environment] = true
+				response.SavedAsDraft = append(response.SavedAsDraft, step.item.Environment)
+			}
+		}
+	}
+	return response, nil
+}
+
+// getApplyError lists the changes applied before the failing one in the error, they are kept
+func getApplyError(item *bean.PlanItem, applied []*bean.PlanItem, err error) error {
+	userMsg := fmt.Sprintf("error in applying %s, %d changes were applied before it and are kept: %s", describePlanItem(item), len(applied), err.Error())
+	apiErr := util.NewApiError(http.StatusInternalServerError, userMsg, err.Error())
+	if cause, ok := err.(*util.ApiError); ok && cause.HttpStatusCode != 0 {
+		apiErr = util.NewApiError(cause.HttpStatusCode, userMsg, cause.InternalMessage)
+	}
+	appliedChanges := make([]string, 0, len(applied))
+	for _, appliedItem := range applied {
+		appliedChanges = append(appliedChanges, fmt.Sprintf("%s %s", appliedItem.Action, describePlanItem(appliedItem)))
+	}
+	apiErr.UserDetailMessage = fmt.Sprintf("applied changes: [%s]", strings.Join(appliedChanges, ", "))
+	return apiErr
+}
+
+func describePlanItem(item *bean.PlanItem) string {
+	description := string(item.Kind)
+	if item.Name != "" {
+		description = fmt.Sprintf("%s %s", description, item.Name)
+	}
+	if item.Environment != "" {
+		description = fmt.Sprintf("%s on %s", description, item.Environment)
+	}
+	return description
+}
+
+func isConfigKind(kind bean.ResourceKind) bool {
+	return kind == bean.KindDeploymentTemplate || kind == bean.KindConfigMap || kind == bean.KindSecret
+}
+
+func getPlan(state *importState, steps []*planStep) *bean.Plan {
+	plan := &bean.Plan{AppId: state.appId, Items: make([]*bean.PlanItem, 0, len(steps))}
+	for _, step := range steps {
+		plan.Items = append(plan.Items, step.item)
+	}
+	for _, skipped := range state.current.skipped {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("not managed by the bundle: %s", skipped))
+	}
+	return plan
+}
+
+func (impl *AppAsCodeServiceImpl) getPlanSteps(request *bean.ImportRequest) (*importState, []*planStep, error) {
+	current, err := impl.getAppState(request.AppId)
+	if err != nil {
+		return nil, nil, err
+	}
+	desired, err := impl.resolveBundle(current, request.Bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &importState{
+		appId:   request.AppId,
+		userId:  request.UserId,
+		prune:   request.Prune,
+		current: current,
+		desired: desired,
+	}
+	// the order of the steps is the order of the changes on apply, every component is saved after the ones it refers to
+	getSteps := []func(state *importState) ([]*planStep, error){
+		impl.getGitMaterialSteps,
+		impl.getBuildConfigSteps,
+		impl.getDeploymentTemplateSteps,
+		func(state *importState) ([]*planStep, error) {
+			return impl.getConfigDataSteps(state, bean.KindConfigMap, nil, state.current.bundle.ConfigMaps, state.desired.bundle.ConfigMaps)
+		},
+		func(state *importState) ([]*planStep, error) {
+			return impl.getConfigDataSteps(state, bean.KindSecret, nil, state.current.bundle.Secrets, state.desired.bundle.Secrets)
+		},
+		impl.getWorkflowSteps,
+		impl.getEnvironmentConfigSteps,
+	}
+	steps := make([]*planStep, 0)
+	for _, getStep := range getSteps {
+		kindSteps, err := getStep(state)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps = append(steps, kindSteps...)
+	}
+	return state, steps, nil
+}
+
+func getWorkflowName(workflow v1.Workflow) string {
+	if workflow.Destination == nil {
+		return ""
+	}
+	return pointer.StringDeref(workflow.Destination.Workflow, "")
+}
+
+func getPipelineName(path *v1.ResourcePath) string {
+	if path == nil {
+		return ""
+	}
+	return pointer.StringDeref(path.Pipeline, "")
+}
+
+func getEnvironmentName(deployment *v1.Deployment) string {
+	if deployment.Destination == nil {
+		return ""
+	}
+	return pointer.StringDeref(deployment.Destination.Environment, "")
+}
+
+// getDependsOn returns the deployment of the workflow deploying before this one, empty if the deployment is
+// triggered by the workflow source
+func getDependsOn(deployment *v1.Deployment) string {
+	if deployment.PreviousPipeline == nil || deployment.PreviousPipeline.Deployment == nil {
+		return ""
+	}
+	return getPipelineName(deployment.PreviousPipeline.Deployment.Destination)
+}
+
+func getWorkflows(bundle *v1.App) []v1.Workflow {
+	if bundle.Workflow == nil {
+		return nil
+	}
+	return *bundle.Workflow
+}
+
+// getWorkflowPipelines returns the build of the workflow, nil if it has none, and its deployments in order
+func getWorkflowPipelines(workflow v1.Workflow) (*v1.Build, []*v1.Deployment) {
+	if workflow.Pipelines == nil {
+		return nil, nil
+	}
+	var build *v1.Build
+	deployments := make([]*v1.Deployment, 0)
+	for _, pipeline := range *workflow.Pipelines {
+		if pipeline.Build != nil {
+			build = pipeline.Build
+		}
+		if pipeline.Deployment != nil {
+			deployments = append(deployments, pipeline.Deployment)
+		}
+	}
+	return build, deployments
+}
+
+// isExternalCi is true for a workflow whose deployments are triggered by a webhook, as it has no build
+func isExternalCi(workflow v1.Workflow) bool {
+	build, deployments := getWorkflowPipelines(workflow)
+	return build == nil && len(deployments) > 0
+}
+
+func getDeployments(bundle *v1.App) []*v1.Deployment {
+	deployments := make([]*v1.Deployment, 0)
+	for _, workflow := range getWorkflows(bundle) {
+		_, workflowDeployments := getWorkflowPipelines(workflow)
+		deployments = append(deployments, workflowDeployments...)
+	}
+	return deployments
+}
+
+// getTasks returns the pre/post tasks of the builds and the deployments of the bundle
+func getTasks(bundle *v1.App) []*v1.Task {
+	tasks := make([]*v1.Task, 0)
+	for _, workflow := range getWorkflows(bundle) {
+		build, deployments := getWorkflowPipelines(workflow)
+		if build != nil {
+			tasks = append(tasks, build.PreBuild, build.PostBuild)
+		}
+		for _, deployment := range deployments {
+			tasks = append(tasks, deployment.PreDeployment, deployment.PostDeployment)
+		}
+	}
+	return tasks
+}
+
+// getAppState exports the app. Linked ci pipelines, jobs and the workflows with them or with linked cd pipelines are
+// left out, as they refer to other apps, and are listed as skipped.
+func (impl *AppAsCodeServiceImpl) getAppState(appId int) (*appState, error) {
+	appModel, err := impl.appRepository.FindAppAndProjectByAppId(appId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "app not found", err.Error())
+		}
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return nil, err
+	}
+	if appModel.AppType != repoHelper.CustomApp {
+		errMsg := "only devtron apps can be exported and imported"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	state := &appState{
+		appId:   appId,
+		appName: appModel.AppName,
+		bundle: &v1.App{
+			ApiVersion:  bean.BundleApiVersion,
+			Destination: &v1.ResourcePath{App: pointer.String(appModel.AppName)},
+			Team:        appModel.Team.Name,
+			Repo:        make([]v1.Repo, 0),
+			ConfigMaps:  make([]v1.DataHolder, 0),
+			Secrets:     make([]v1.DataHolder, 0),
+		},
+		gitMaterialIds: make(map[string]int),
+		workflows:      make(map[string]*workflowIds),
+		ciPipelines:    make(map[string]*pkgBean.CiPipeline),
+		cdPipelines:    make(map[string]*pkgBean.CDPipelineConfigObject),
+		ciPipelineIds:  make(map[string]int),
+		cdPipelineIds:  make(map[string]int),
+	}
+	gitMaterialUrls, err := impl.setGitMaterials(state)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setBuildConfig(state, gitMaterialUrls)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setDeploymentTemplate(state)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setBaseConfigData(state)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setWorkflows(state, gitMaterialUrls)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// setGitMaterials returns the urls of the git materials by id
+func (impl *AppAsCodeServiceImpl) setGitMaterials(state *appState) (map[int]string, error) {
+	appDto, err := impl.pipelineBuilder.GetApp(state.appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching git materials", "appId", state.appId, "err", err)
+		return nil, err
+	}
+	gitProviders, err := impl.gitProviderReadService.GetAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching git providers", "err", err)
+		return nil, err
+	}
+	gitProviderNames := make(map[int]string, len(gitProviders))
+	for _, gitProvider := range gitProviders {
+		gitProviderNames[gitProvider.Id] = gitProvider.Name
+	}
+	gitMaterialUrls := make(map[int]string, len(appDto.Material))
+	for _, material := range appDto.Material {
+		gitMaterialUrls[material.Id] = material.Url
+		state.gitMaterialIds[material.CheckoutPath] = material.Id
+		state.bundle.Repo = append(state.bundle.Repo, adapter.ConvertGitMaterialToRepo(material, gitProviderNames[material.GitProviderId]))
+	}
+	sort.Slice(state.bundle.Repo, func(i, j int) bool {
+		return *state.bundle.Repo[i].Path < *state.bundle.Repo[j].Path
+	})
+	return gitMaterialUrls, nil
+}
+
+func (impl *AppAsCodeServiceImpl) setBuildConfig(state *appState, gitMaterialUrls map[int]string) error {
+	_, err := impl.ciTemplateReadService.FindByAppId(state.appId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching ci template", "appId", state.appId, "err", err)
+		return err
+	}
+	ciConfig, err := impl.pipelineBuilder.GetCiPipeline(state.appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci config", "appId", state.appId, "err", err)
+		return err
+	}
+	state.ciTemplate = ciConfig
+	state.bundle.DockerRegistry = ciConfig.DockerRegistry
+	state.bundle.DockerRepo = ciConfig.DockerRepository
+	state.bundle.DockerConfig = *adapter.ConvertCiBuildConfigToDockerConfig(ciConfig.CiBuildConfig, gitMaterialUrls)
+	return nil
+}
+
+func (impl *AppAsCodeServiceImpl) setDeploymentTemplate(state *appState) error {
+	chart, err := impl.chartReadService.FindLatestChartForAppByAppId(state.appId)
+	if util.IsErrNoRows(err) {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment template", "appId", state.appId, "err", err)
+		return err
+	}
+	if chart == nil || chart.Id == 0 {
+		return nil
+	}
+	state.baseChart = chart
+	state.bundle.DeploymentTemplate, err = impl.toBundleDeploymentTemplate(chart.ChartRefId, chart.DefaultAppOverride, "")
+	return err
+}
+
+func (impl *AppAsCodeServiceImpl) toBundleDeploymentTemplate(chartRefId int, values []byte, mergeStrategy models.MergeStrategy) (*v1.DeploymentTemplate, error) {
+	chartRef, err := impl.chartRefReadService.FindById(chartRefId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart ref", "chartRefId", chartRefId, "err", err)
+		return nil, err
+	}
+	return adapter.ConvertToDeploymentTemplate(chartRef.Name, chartRef.Version, values, mergeStrategy)
+}
+
+func (impl *AppAsCodeServiceImpl) setBaseConfigData(state *appState) error {
+	configMaps, err := impl.configMapService.CMGlobalFetch(state.appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching base config maps", "appId", state.appId, "err", err)
+		return err
+	}
+	secrets, err := impl.configMapService.CSGlobalFetch(state.appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching base secrets", "appId", state.appId, "err", err)
+		return err
+	}
+	state.bundle.ConfigMaps, err = toBundleDataHolders(configMaps, v1.ConfigMap, true)
+	if err != nil {
+		return err
+	}
+	state.bundle.Secrets, err = toBundleDataHolders(secrets, v1.Secret, true)
+	return err
+}
+
+// toBundleDataHolders returns the config maps or secrets saved at the level of the request, the ones of an env
+// level request inherited from the base config are left out
+func toBundleDataHolders(request *pipelineBean.ConfigDataRequest, dataType string, global bool) ([]v1.DataHolder, error) {
+	holders := make([]v1.DataHolder, 0)
+	if request == nil {
+		return holders, nil
+	}
+	for _, configData := range request.ConfigData {
+		if !global && configData.Global && !configData.Overridden {
+			continue
+		}
+		holder, err := adapter.ConvertConfigDataToDataHolder(configData, dataType)
+		if err != nil {
+			return nil, err
+		}
+		holder.Global = global
+		holders = append(holders, holder)
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		return adapter.GetDataHolderName(holders[i], dataType) < adapter.GetDataHolderName(holders[j], dataType)
+	})
+	return holders, nil
+}
+
+func (impl *AppAsCodeServiceImpl) setWorkflows(state *appState, gitMaterialUrls map[int]string) error {
+	ciPipelines := make(map[int]*pkgBean.CiPipeline)
+	if state.ciTemplate != nil {
+		for _, ciPipeline := range state.ciTemplate.CiPipelines {
+			ciPipelines[ciPipeline.Id] = ciPipeline
+		}
+	}
+	cdPipelines := make(map[int]*pkgBean.CDPipelineConfigObject)
+	cdPipelinesResp, err := impl.pipelineBuilder.GetCdPipelinesForApp(state.appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipelines", "appId", state.appId, "err", err)
+		return err
+	}
+	if cdPipelinesResp != nil {
+		for _, cdPipeline := range cdPipelinesResp.Pipelines {
+			cdPipelines[cdPipeline.Id] = cdPipeline
+		}
+	}
+	workflows, err := impl.appWorkflowService.FindAppWorkflows(state.appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching workflows", "appId", state.appId, "err", err)
+		return err
+	}
+	bundleWorkflows := make([]v1.Workflow, 0, len(workflows))
+	for _, workflow := range workflows {
+		bundleWorkflow, skipReason, err := impl.toBundleWorkflow(state, workflow, ciPipelines, cdPipelines, gitMaterialUrls)
+		if err != nil {
+			return err
+		}
+		if len(skipReason) > 0 {
+			state.skipped = append(state.skipped, fmt.Sprintf("workflow %s, %s", workflow.Name, skipReason))
+			continue
+		}
+		bundleWorkflows = append(bundleWorkflows, *bundleWorkflow)
+	}
+	sort.Slice(bundleWorkflows, func(i, j int) bool {
+		return getWorkflowName(bundleWorkflows[i]) < getWorkflowName(bundleWorkflows[j])
+	})
+	state.bundle.Workflow = &bundleWorkflows
+	return nil
+}
+
+// toBundleWorkflow returns the reason for leaving the workflow out of the bundle if it can not be exported
+func (impl *AppAsCodeServiceImpl) toBundleWorkflow(state *appState, workflow appWorkflowBean.AppWorkflowDto, ciPipelines map[int]*pkgBean.CiPipeline,
+	cdPipelines map[int]*pkgBean.CDPipelineConfigObject, gitMaterialUrls map[int]string) (*v1.Workflow, string, error) {
+	pipelines := make([]v1.Pipeline, 0)
+	ids := &workflowIds{id: workflow.Id}
+	var ciPipeline *pkgBean.CiPipeline
+	var ciPipelineName string
+	cdPipelineNames := make(map[int]string)
+	workflowCdPipelines := make(map[string]*pkgBean.CDPipelineConfigObject)
+	for _, mapping := range appWorkflow.LevelWiseSort(workflow.AppWorkflowMappingDto) {
+		switch mapping.Type {
+		case appWorkflowRepo.CIPIPELINE:
+			ciPipeline = ciPipelines[mapping.ComponentId]
+			if ciPipeline == nil || !isBuildPipeline(ciPipeline) {
+				return nil, "its ci pipeline is a linked ci or a job", nil
+			}
+			build, err := impl.toBundleBuild(state.appName, ciPipeline, gitMaterialUrls)
+			if err != nil {
+				return nil, "", err
+			}
+			ciPipelineName = getPipelineName(build.Destination)
+			pipelines = append(pipelines, v1.Pipeline{Build: build})
+		case appWorkflowRepo.WEBHOOK:
+			ids.externalCiId = mapping.ComponentId
+		case appWorkflowRepo.CDPIPELINE:
+			cdPipeline := cdPipelines[mapping.ComponentId]
+			if cdPipeline == nil {
+				continue
+			}
+			if cdPipeline.IsExternalArgoAppLinkRequest() {
+				return nil, fmt.Sprintf("its cd pipeline %s is linked to an external argo cd app", cdPipeline.Name), nil
+			}
+			deployment, err := impl.toBundleDeployment(state, cdPipeline)
+			if err != nil {
+				return nil, "", err
+			}
+			if mapping.ParentType == appWorkflowRepo.CDPIPELINE {
+				deployment.PreviousPipeline = &v1.Pipeline{Deployment: &v1.Deployment{
+					Destination: &v1.ResourcePath{Pipeline: pointer.String(cdPipelineNames[mapping.ParentId])},
+				}}
+			}
+			name := getPipelineName(deployment.Destination)
+			cdPipelineNames[cdPipeline.Id] = name
+			workflowCdPipelines[name] = cdPipeline
+			pipelines = append(pipelines, v1.Pipeline{Deployment: deployment})
+		}
+	}
+	state.workflows[workflow.Name] = ids
+	if ciPipeline != nil {
+		state.ciPipelines[ciPipelineName] = ciPipeline
+		state.ciPipelineIds[ciPipelineName] = ciPipeline.Id
+	}
+	for name, cdPipeline := range workflowCdPipelines {
+		state.cdPipelines[name] = cdPipeline
+		state.cdPipelineIds[name] = cdPipeline.Id
+	}
+	return &v1.Workflow{
+		Destination: &v1.ResourcePath{Workflow: pointer.String(workflow.Name)},
+		Pipelines:   &pipelines,
+	}, "", nil
+}
+
+func isBuildPipeline(ciPipeline *pkgBean.CiPipeline) bool {
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline != 0 || ciPipeline.EnvironmentId != 0 {
+		return false
+	}
+	return len(ciPipeline.PipelineType) == 0 || ciPipeline.PipelineType == buildCommonBean.CI_BUILD
+}
+
+func (impl *AppAsCodeServiceImpl) toBundleBuild(appName string, ciPipeline *pkgBean.CiPipeline, gitMaterialUrls map[int]string) (*v1.Build, error) {
+	preStage, postStage, err := impl.pipelineStageService.GetCiPipelineStageDataDeepCopy(ciPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline stages", "ciPipelineId", ciPipeline.Id, "err", err)
+		return nil, err
+	}
+	trigger := v1.Automatic
+	if ciPipeline.IsManual {
+		trigger = v1.Manual
+	}
+	build := &v1.Build{
+		Destination:     &v1.ResourcePath{Pipeline: pointer.String(helper.TrimAppNamePrefix(ciPipeline.Name, appName, "-ci-"))},
+		Trigger:         trigger,
+		DockerArguments: make(map[string]interface{}, len(ciPipeline.DockerArgs)),
+		BuildMaterials:  make([]v1.BuildMaterial, 0, len(ciPipeline.CiMaterial)),
+		ScanEnabled:     ciPipeline.ScanEnabled,
+		PreBuild:        adapter.ConvertPipelineStageToTask(preStage),
+		PostBuild:       adapter.ConvertPipelineStageToTask(postStage),
+	}
+	for key, value := range ciPipeline.DockerArgs {
+		build.DockerArguments[key] = value
+	}
+	for _, material := range ciPipeline.CiMaterial {
+		buildMaterial := v1.BuildMaterial{GitMaterialUrl: gitMaterialUrls[material.GitMaterialId]}
+		if material.Source != nil {
+			buildMaterial.Source.Type = batch.TransformToV1SourceType(material.Source.Type)
+			buildMaterial.Source.Value = material.Source.Value
+			buildMaterial.Source.Regex = material.Source.Regex
+		}
+		build.BuildMaterials = append(build.BuildMaterials, buildMaterial)
+	}
+	if ciPipeline.IsDockerConfigOverridden {
+		override := ciPipeline.DockerConfigOverride
+		build.DockerRegistry = override.DockerRegistry
+		build.DockerRepo = override.DockerRepository
+		build.DockerConfig = adapter.ConvertCiBuildConfigToDockerConfig(override.CiBuildConfig, gitMaterialUrls)
+	}
+	return build, nil
+}
+
+// toBundleDeployment returns the deployment along with the config overrides of its environment
+func (impl *AppAsCodeServiceImpl) toBundleDeployment(state *appState, cdPipeline *pkgBean.CDPipelineConfigObject) (*v1.Deployment, error) {
+	pipelineModel, err := impl.pipelineRepository.FindById(cdPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	preStage, postStage, err := impl.pipelineStageService.GetCdPipelineStageDataDeepCopy(pipelineModel)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline stages", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	strategy, err := batch.TransformToV1Strategy(cdPipeline.Strategies)
+	if err != nil {
+		impl.logger.Errorw("error in transforming deployment strategies", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	trigger := adapter.ConvertTriggerTypeToTrigger(cdPipeline.TriggerType)
+	deployment := &v1.Deployment{
+		Destination: &v1.ResourcePath{
+			Pipeline:    pointer.String(helper.TrimAppNamePrefix(cdPipeline.Name, state.appName, "-")),
+			Environment: pointer.String(cdPipeline.EnvironmentName),
+		},
+		Trigger:           &trigger,
+		Strategy:          strategy,
+		PreDeployment:     adapter.ConvertCdStageToTask(preStage, cdPipeline.PreStageConfigMapSecretNames.ConfigMaps, cdPipeline.PreStageConfigMapSecretNames.Secrets),
+		PostDeployment:    adapter.ConvertCdStageToTask(postStage, cdPipeline.PostStageConfigMapSecretNames.ConfigMaps, cdPipeline.PostStageConfigMapSecretNames.Secrets),
+		RunPreStageInEnv:  cdPipeline.RunPreStageInEnv,
+		RunPostStageInEnv: cdPipeline.RunPostStageInEnv,
+		DeploymentAppType: cdPipeline.DeploymentAppType,
+		IsDigestEnforced:  cdPipeline.IsDigestEnforcedForPipeline,
+	}
+	err = impl.setEnvironmentConfig(state.appId, cdPipeline.EnvironmentId, deployment)
+	if err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// setEnvironmentConfig sets the deployment template, config maps and secrets overridden on the environment of the
+// deployment, the template is nil if the environment inherits the base deployment template
+func (impl *AppAsCodeServiceImpl) setEnvironmentConfig(appId, envId int, deployment *v1.Deployment) error {
+	properties, err := impl.propertiesConfigService.GetLatestEnvironmentProperties(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env deployment template", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	if properties != nil && properties.IsOverride {
+		deployment.Template, err = impl.toBundleDeploymentTemplate(properties.ChartRefId, properties.EnvOverrideValues, getMergeStrategy(properties.MergeStrategy))
+		if err != nil {
+			return err
+		}
+	}
+	configMaps, err := impl.configMapService.CMEnvironmentFetch(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env config maps", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	secrets, err := impl.configMapService.CSEnvironmentFetch(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env secrets", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	deployment.ConfigMaps, err = toBundleDataHolders(configMaps, v1.ConfigMap, false)
+	if err != nil {
+		return err
+	}
+	deployment.Secrets, err = toBundleDataHolders(secrets, v1.Secret, false)
+	return err
+}
+
+func getMergeStrategy(mergeStrategy models.MergeStrategy) models.MergeStrategy {
+	if len(mergeStrategy) == 0 {
+		return models.MERGE_STRATEGY_REPLACE
+	}
+	return mergeStrategy
+}
+
+// getPluginReferences lists the plugins used in the tasks by identifier and version, which are the same across instances
+func (impl *AppAsCodeServiceImpl) getPluginReferences(tasks ...*v1.Task) ([]v1.PluginReference, error) {
+	pluginIds := helper.GetPluginIds(tasks...)
+	if len(pluginIds) == 0 {
+		return nil, nil
+	}
+	plugins, err := impl.globalPluginRepository.GetMetaDataByPluginIds(pluginIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching plugins", "pluginIds", pluginIds, "err", err)
+		return nil, err
+	}
+	parentIds := make([]int, 0, len(plugins))
+	for _, plugin := range plugins {
+		parentIds = append(parentIds, plugin.PluginParentMetadataId)
+	}
+	parents, err := impl.globalPluginRepository.GetPluginParentMetadataByIds(parentIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching parent plugins", "parentIds", parentIds, "err", err)
+		return nil, err
+	}
+	identifiers := make(map[int]string, len(parents))
+	for _, parent := range parents {
+		identifiers[parent.Id] = parent.Identifier
+	}
+	references := make([]v1.PluginReference, 0, len(plugins))
+	for _, plugin := range plugins {
+		references = append(references, v1.PluginReference{
+			Id:         plugin.Id,
+			Identifier: identifiers[plugin.PluginParentMetadataId],
+			Version:    plugin.PluginVersion,
+		})
+	}
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].Id < references[j].Id
+	})
+	return references, nil
+}
+
+func getChartKey(deploymentTemplate *v1.DeploymentTemplate) string {
+	return deploymentTemplate.RefChartTemplate + "/" + deploymentTemplate.RefChartTemplateVersion
+}
+
+// resolveBundle validates the bundle and resolves the instance level resources it refers to by name. The components
+// are normalized in place to the form they are exported in, so that they can be compared with the ones of the app.
+// All the problems found are returned together.
+func (impl *AppAsCodeServiceImpl) resolveBundle(current *appState, bundle *v1.App) (*desiredState, error) {
+	desired := &desiredState{
+		bundle:           bundle,
+		gitProviderIds:   make(map[string]int),
+		gitMaterialPaths: make(map[string]string),
+		environments:     make(map[string]*repository.Environment),
+		chartRefIds:      make(map[string]int),
+	}
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if len(bundle.ApiVersion) > 0 && bundle.ApiVersion != bean.BundleApiVersion {
+		addProblem(v1.UnsupportedVersion, bundle.ApiVersion, "app")
+	}
+
+	gitProviders, err := impl.gitProviderReadService.GetAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching git providers", "err", err)
+		return nil, err
+	}
+	for _, gitProvider := range gitProviders {
+		desired.gitProviderIds[gitProvider.Name] = gitProvider.Id
+	}
+	checkoutPaths := make(map[string]bool)
+	for i, repo := range bundle.Repo {
+		checkoutPath, url, gitProvider := pointer.StringDeref(repo.Path, ""), pointer.StringDeref(repo.Url, ""), pointer.StringDeref(repo.GitProvider, "")
+		if len(checkoutPath) == 0 || len(url) == 0 {
+			addProblem("the path and the url of a repo can not be empty")
+			continue
+		}
+		if checkoutPaths[checkoutPath] {
+			addProblem("repo %s is listed more than once", checkoutPath)
+		}
+		if _, ok := desired.gitMaterialPaths[url]; ok {
+			addProblem("repo url %s is listed more than once", url)
+		}
+		checkoutPaths[checkoutPath], desired.gitMaterialPaths[url] = true, checkoutPath
+		if _, ok := desired.gitProviderIds[gitProvider]; !ok {
+			addProblem("git provider %s of repo %s is not found", gitProvider, checkoutPath)
+		}
+		bundle.Repo[i] = v1.Repo{
+			Path:            pointer.String(checkoutPath),
+			Url:             pointer.String(url),
+			GitProvider:     pointer.String(gitProvider),
+			FetchSubmodules: repo.FetchSubmodules,
+			FilterPattern:   repo.FilterPattern,
+		}
+	}
+	// the git materials of the app missing in the bundle can still be used by the builds
+	for _, repo := range current.bundle.Repo {
+		checkoutPath, url := pointer.StringDeref(repo.Path, ""), pointer.StringDeref(repo.Url, "")
+		if _, ok := desired.gitMaterialPaths[url]; !ok && !checkoutPaths[checkoutPath] {
+			desired.gitMaterialPaths[url] = checkoutPath
+		}
+	}
+	checkGitMaterial := func(url, usedIn string) {
+		if _, ok := desired.gitMaterialPaths[url]; !ok {
+			addProblem("git material %s of %s is not found", url, usedIn)
+		}
+	}
+	checkDockerConfig := func(dockerConfig *v1.DockerConfig, usedIn string) {
+		// the docker file options are set in the ci build config
+		dockerConfig.Args, dockerConfig.DockerFilePath, dockerConfig.DockerFileRelativePath, dockerConfig.DockerFileRepository = nil, "", "", ""
+		if dockerConfig.CiBuildConfig == nil {
+			addProblem("the ci build config of %s can not be empty", usedIn)
+			return
+		}
+		dockerConfig.CiBuildConfig = adapter.GetCiBuildConfigWithoutIds(dockerConfig.CiBuildConfig)
+		if len(dockerConfig.BuildContextGitMaterial) == 0 {
+			dockerConfig.BuildContextGitMaterial = dockerConfig.GitMaterial
+		}
+		checkGitMaterial(dockerConfig.GitMaterial, usedIn)
+		checkGitMaterial(dockerConfig.BuildContextGitMaterial, usedIn)
+	}
+	if len(bundle.DockerRegistry) > 0 {
+		checkDockerConfig(&bundle.DockerConfig, "the docker config")
+	}
+
+	normalizeTemplate := func(template *v1.DeploymentTemplate, mergeStrategy models.MergeStrategy, usedIn string) (*v1.DeploymentTemplate, error) {
+		if template == nil {
+			return nil, nil
+		}
+		normalized := &v1.DeploymentTemplate{
+			RefChartTemplate:        template.RefChartTemplate,
+			RefChartTemplateVersion: template.RefChartTemplateVersion,
+			ValuesOverride:          template.ValuesOverride,
+			MergeStrategy:           mergeStrategy,
+		}
+		if normalized.ValuesOverride == nil {
+			normalized.ValuesOverride = make(map[string]interface{})
+		}
+		if len(normalized.RefChartTemplate) == 0 || len(normalized.RefChartTemplateVersion) == 0 {
+			addProblem("the chart and the chart version of %s can not be empty", usedIn)
+			return normalized, nil
+		}
+		key := getChartKey(normalized)
+		if _, ok := desired.chartRefIds[key]; ok {
+			return normalized, nil
+		}
+		chartRef, err := impl.chartRefReadService.FindByVersionAndName(normalized.RefChartTemplateVersion, normalized.RefChartTemplate)
+		if util.IsErrNoRows(err) {
+			addProblem("chart %s version %s of %s is not found", normalized.RefChartTemplate, normalized.RefChartTemplateVersion, usedIn)
+			return normalized, nil
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching chart ref", "name", normalized.RefChartTemplate, "version", normalized.RefChartTemplateVersion, "err", err)
+			return nil, err
+		}
+		desired.chartRefIds[key] = chartRef.Id
+		return normalized, nil
+	}
+	// the merge strategy applies to the environment overrides only
+	bundle.DeploymentTemplate, err = normalizeTemplate(bundle.DeploymentTemplate, "", "the deployment template")
+	if err != nil {
+		return nil, err
+	}
+
+	normalizeDataHolders := func(holders []v1.DataHolder, dataType string, global bool, usedIn string) []v1.DataHolder {
+		names := make(map[string]bool, len(holders))
+		normalized := make([]v1.DataHolder, 0, len(holders))
+		for _, holder := range holders {
+			name := adapter.GetDataHolderName(holder, dataType)
+			if len(name) == 0 {
+				addProblem(v1.NameEmpty, dataType+usedIn)
+				continue
+			}
+			if names[name] {
+				addProblem("%s %s%s is listed more than once", dataType, name, usedIn)
+			}
+			names[name] = true
+			holder.ApiVersion, holder.Operation, holder.Source = "", "", nil
+			holder.Destination = &v1.ResourcePath{}
+			if dataType == v1.Secret {
+				holder.Destination.Secret = pointer.String(name)
+			} else {
+				holder.Destination.ConfigMap = pointer.String(name)
+			}
+			holder.Global = global
+			normalized = append(normalized, holder)
+		}
+		return normalized
+	}
+	bundle.ConfigMaps = normalizeDataHolders(bundle.ConfigMaps, v1.ConfigMap, true, "")
+	bundle.Secrets = normalizeDataHolders(bundle.Secrets, v1.Secret, true, "")
+
+	normalizeTrigger := func(trigger v1.Trigger, usedIn string) v1.Trigger {
+		switch trigger {
+		case "":
+			return v1.Automatic
+		case v1.Automatic, v1.Manual:
+			return trigger
+		}
+		addProblem("trigger %s of %s is not supported", trigger, usedIn)
+		return trigger
+	}
+	normalizeTask := func(task *v1.Task, usedIn string) *v1.Task {
+		if task == nil {
+			return nil
+		}
+		if len(task.Stages) > 0 {
+			addProblem("the stages of %s are not supported, use steps", usedIn)
+		}
+		if len(task.Steps) == 0 {
+			return nil
+		}
+		return &v1.Task{Steps: task.Steps}
+	}
+	normalizeCdTask := func(task *v1.Task, usedIn string) *v1.Task {
+		if task == nil {
+			return nil
+		}
+		normalized := normalizeTask(task, usedIn)
+		if normalized == nil && len(task.ConfigMaps) == 0 && len(task.Secrets) == 0 {
+			return nil
+		} else if normalized == nil {
+			normalized = &v1.Task{}
+		}
+		trigger := normalizeTrigger(getTrigger(task.Trigger), usedIn)
+		normalized.Trigger = &trigger
+		if len(task.ConfigMaps) > 0 {
+			normalized.ConfigMaps = task.ConfigMaps
+		}
+		if len(task.Secrets) > 0 {
+			normalized.Secrets = task.Secrets
+		}
+		return normalized
+	}
+	resolveEnv := func(envName, usedIn string) error {
+		if _, ok := desired.environments[envName]; ok {
+			return nil
+		}
+		env, err := impl.environmentRepository.FindByName(envName)
+		if util.IsErrNoRows(err) || (err == nil && env == nil) {
+			addProblem("environment %s of %s is not found", envName, usedIn)
+			return nil
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching environment", "name", envName, "err", err)
+			return err
+		}
+		desired.environments[envName] = env
+		return nil
+	}
+
+	workflowNames, buildNames, deploymentNames := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	deploymentEnvs := make(map[string]string)
+	for i := range getWorkflows(bundle) {
+		workflow := &(*bundle.Workflow)[i]
+		workflowName := getWorkflowName(*workflow)
+		if len(workflowName) == 0 {
+			addProblem(v1.NameEmpty, "workflow")
+			continue
+		}
+		if workflowNames[workflowName] {
+			addProblem("workflow %s is listed more than once", workflowName)
+		}
+		workflowNames[workflowName] = true
+		workflow.ApiVersion, workflow.Operation, workflow.Source = "", "", nil
+		workflow.Destination = &v1.ResourcePath{Workflow: pointer.String(workflowName)}
+		builds := 0
+		if workflow.Pipelines != nil {
+			for _, pipeline := range *workflow.Pipelines {
+				if (pipeline.Build == nil) == (pipeline.Deployment == nil) {
+					addProblem("a pipeline of workflow %s must be either a build or a deployment", workflowName)
+				}
+				if pipeline.Build != nil {
+					builds++
+				}
+			}
+		}
+		if builds > 1 {
+			addProblem("workflow %s can have only one build", workflowName)
+		}
+
+		build, deployments := getWorkflowPipelines(*workflow)
+		buildName := ""
+		if build != nil {
+			buildName = getPipelineName(build.Destination)
+			usedIn := "build " + buildName
+			if len(buildName) == 0 {
+				addProblem(v1.NameEmpty, "the build of workflow "+workflowName)
+			} else if buildNames[buildName] {
+				addProblem("build %s is listed more than once", buildName)
+			}
+			buildNames[buildName] = true
+			if len(build.BuildMaterials) == 0 {
+				addProblem("the build materials of %s can not be empty", usedIn)
+			}
+			for _, material := range build.BuildMaterials {
+				checkGitMaterial(material.GitMaterialUrl, usedIn)
+				if len(batch.TransformSourceType(material.Source.Type)) == 0 {
+					addProblem("source type %s of %s is not supported", material.Source.Type, usedIn)
+				}
+			}
+			normalized := v1.Build{
+				Destination:     &v1.ResourcePath{Pipeline: pointer.String(buildName)},
+				Trigger:         normalizeTrigger(build.Trigger, usedIn),
+				DockerArguments: build.DockerArguments,
+				BuildMaterials:  build.BuildMaterials,
+				ScanEnabled:     build.ScanEnabled,
+				PreBuild:        normalizeTask(build.PreBuild, "the pre build of "+usedIn),
+				PostBuild:       normalizeTask(build.PostBuild, "the post build of "+usedIn),
+			}
+			if normalized.DockerArguments == nil {
+				normalized.DockerArguments = make(map[string]interface{})
+			}
+			if build.DockerConfig != nil {
+				normalized.DockerRegistry, normalized.DockerRepo, normalized.DockerConfig = build.DockerRegistry, build.DockerRepo, build.DockerConfig
+				checkDockerConfig(normalized.DockerConfig, "the docker config of "+usedIn)
+			}
+			*build = normalized
+		}
+
+		workflowDeployments := make(map[string]bool)
+		for _, deployment := range deployments {
+			name, envName := getPipelineName(deployment.Destination), getEnvironmentName(deployment)
+			usedIn := "deployment " + name
+			if len(name) == 0 {
+				addProblem(v1.NameEmpty, "a deployment of workflow "+workflowName)
+			} else if deploymentNames[name] {
+				addProblem("deployment %s is listed more than once", name)
+			}
+			deploymentNames[name] = true
+			if len(envName) == 0 {
+				addProblem(v1.EnvironmentEmpty, "deployment", name)
+			} else {
+				if otherDeployment, ok := deploymentEnvs[envName]; ok {
+					addProblem("deployments %s and %s deploy to the same environment %s", otherDeployment, name, envName)
+				}
+				deploymentEnvs[envName] = name
+				err = resolveEnv(envName, usedIn)
+				if err != nil {
+					return nil, err
+				}
+			}
+			var previousPipeline *v1.Pipeline
+			if previous := deployment.PreviousPipeline; previous != nil && previous.Deployment != nil {
+				dependsOn := getPipelineName(previous.Deployment.Destination)
+				if !workflowDeployments[dependsOn] {
+					addProblem("deployment %s depends on %s, which is not listed before it in workflow %s", name, dependsOn, workflowName)
+				}
+				previousPipeline = &v1.Pipeline{Deployment: &v1.Deployment{
+					Destination: &v1.ResourcePath{Pipeline: pointer.String(dependsOn)},
+				}}
+			} else if previous != nil && previous.Build != nil && (build == nil || getPipelineName(previous.Build.Destination) != buildName) {
+				addProblem("the previous pipeline of deployment %s is not the build of workflow %s", name, workflowName)
+			}
+			workflowDeployments[name] = true
+			trigger := normalizeTrigger(getTrigger(deployment.Trigger), usedIn)
+			template, err := normalizeTemplate(deployment.Template, getMergeStrategy(getTemplateMergeStrategy(deployment.Template)), usedIn)
+			if err != nil {
+				return nil, err
+			}
+			*deployment = v1.Deployment{
+				Destination:       &v1.ResourcePath{Pipeline: pointer.String(name), Environment: pointer.String(envName)},
+				PreviousPipeline:  previousPipeline,
+				Trigger:           &trigger,
+				Strategy:          deployment.Strategy,
+				PreDeployment:     normalizeCdTask(deployment.PreDeployment, "the pre deployment of "+usedIn),
+				PostDeployment:    normalizeCdTask(deployment.PostDeployment, "the post deployment of "+usedIn),
+				RunPreStageInEnv:  deployment.RunPreStageInEnv,
+				RunPostStageInEnv: deployment.RunPostStageInEnv,
+				DeploymentAppType: deployment.DeploymentAppType,
+				IsDigestEnforced:  deployment.IsDigestEnforced,
+				Template:          template,
+				ConfigMaps:        normalizeDataHolders(deployment.ConfigMaps, v1.ConfigMap, false, " of "+usedIn),
+				Secrets:           normalizeDataHolders(deployment.Secrets, v1.Secret, false, " of "+usedIn),
+			}
+			if _, err = batch.TransformStrategy(deployment); err != nil {
+				addProblem("strategy of %s: %s", usedIn, err.Error())
+			}
+		}
+	}
+
+	pluginIds, pluginProblems, err := impl.resolvePlugins(bundle.Plugins)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, pluginProblems...)
+	if len(pluginProblems) == 0 {
+		err = helper.ReplacePluginIds(pluginIds, getTasks(bundle)...)
+		if err != nil {
+			addProblem(err.Error())
+		}
+	}
+	problems = append(problems, unmaskSecrets(current.bundle, bundle)...)
+
+	if len(problems) > 0 {
+		userMsg := fmt.Sprintf("invalid bundle: %s", strings.Join(problems, "; "))
+		return nil, util.NewApiError(http.StatusBadRequest, userMsg, userMsg)
+	}
+	return desired, nil
+}
+
+func getTrigger(trigger *v1.Trigger) v1.Trigger {
+	if trigger == nil {
+		return ""
+	}
+	return *trigger
+}
+
+func getTemplateMergeStrategy(template *v1.DeploymentTemplate) models.MergeStrategy {
+	if template == nil {
+		return ""
+	}
+	return template.MergeStrategy
+}
+
+// resolvePlugins maps the plugin ids of the bundle to the ids of the same plugin versions on this instance
+func (impl *AppAsCodeServiceImpl) resolvePlugins(references []v1.PluginReference) (map[int]int, []string, error) {
+	pluginIds := make(map[int]int, len(references))
+	var problems []string
+	for _, reference := range references {
+		parent, err := impl.globalPluginRepository.GetPluginParentMetadataByIdentifier(reference.Identifier)
+		if util.IsErrNoRows(err) {
+			problems = append(problems, fmt.Sprintf("plugin %s is not found", reference.Identifier))
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching plugin", "identifier", reference.Identifier, "err", err)
+			return nil, nil, err
+		}
+		versions, err := impl.globalPluginRepository.GetPluginVersionsByParentId(parent.Id)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching plugin versions", "identifier", reference.Identifier, "err", err)
+			return nil, nil, err
+		}
+		for _, version := range versions {
+			if version.PluginVersion == reference.Version {
+				pluginIds[reference.Id] = version.Id
+				break
+			}
+		}
+		if _, ok := pluginIds[reference.Id]; !ok {
+			problems = append(problems, fmt.Sprintf("version %s of plugin %s is not found", reference.Version, reference.Identifier))
+		}
+	}
+	return pluginIds, problems, nil
+}
+
+// unmaskSecrets replaces the masked values of the bundle secrets with the current values, the secrets of a deployment
+// are unmasked with the ones of the deployment of the app with the same name
+func unmaskSecrets(current, desired *v1.App) []string {
+	var problems []string
+	unmask := func(currentSecrets, desiredSecrets []v1.DataHolder, usedIn string) {
+		currentByName := make(map[string]v1.DataHolder, len(currentSecrets))
+		for _, secret := range currentSecrets {
+			currentByName[adapter.GetDataHolderName(secret, v1.Secret)] = secret
+		}
+		for i := range desiredSecrets {
+			name := adapter.GetDataHolderName(desiredSecrets[i], v1.Secret)
+			data, err := helper.UnmaskSecretData(desiredSecrets[i].Data, currentByName[name].Data)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("secret %s%s: %s", name, usedIn, err.Error()))
+				continue
+			}
+			desiredSecrets[i].Data = data
+		}
+	}
+	unmask(current.Secrets, desired.Secrets, "")
+	currentDeployments := make(map[string]*v1.Deployment)
+	for _, deployment := range getDeployments(current) {
+		currentDeployments[getPipelineName(deployment.Destination)] = deployment
+	}
+	for _, deployment := range getDeployments(desired) {
+		name := getPipelineName(deployment.Destination)
+		var currentSecrets []v1.DataHolder
+		if currentDeployment, ok := currentDeployments[name]; ok && getEnvironmentName(currentDeployment) == getEnvironmentName(deployment) {
+			currentSecrets = currentDeployment.Secrets
+		}
+		unmask(currentSecrets, deployment.Secrets, " of deployment "+name)
+	}
+	return problems
+}
+
+func isNil(value interface{}) bool {
+	return value == nil || reflect.ValueOf(value).IsNil()
+}
+
+// compare returns the plan item of a resource from its current and desired forms, nil if missing. A resource missing
+// in the bundle is deleted if deleteMissing is set, else it is left unmanaged.
+func compare(kind bean.ResourceKind, name string, current, desired interface{}, deleteMissing bool) (*bean.PlanItem, error) {
+	item := &bean.PlanItem{Kind: kind, Name: name}
+	switch {
+	case isNil(current):
+		item.Action = bean.PlanActionCreate
+	case isNil(desired):
+		item.Action = bean.PlanActionUnmanaged
+		if deleteMissing {
+			item.Action = bean.PlanActionDelete
+		}
+	default:
+		changes, err := helper.Diff(current, desired)
+		if err != nil {
+			return nil, err
+		}
+		item.Action = bean.PlanActionNoChange
+		if len(changes) > 0 {
+			item.Action = bean.PlanActionUpdate
+			item.Changes = changes
+		}
+	}
+	return item, nil
+}
+
+func unmanagedStep(kind bean.ResourceKind, name string) *planStep {
+	return &planStep{item: &bean.PlanItem{Kind: kind, Name: name, Action: bean.PlanActionUnmanaged}}
+}
+
+func conflictStep(item *bean.PlanItem, message string) *planStep {
+	item.Action = bean.PlanActionConflict
+	item.Changes = nil
+	item.Message = message
+	return &planStep{item: item}
+}
+
+func (impl *AppAsCodeServiceImpl) getGitMaterialSteps(state *importState) ([]*planStep, error) {
+	currentRepos := make(map[string]*v1.Repo)
+	for i, repo := range state.current.bundle.Repo {
+		currentRepos[pointer.StringDeref(repo.Path, "")] = &state.current.bundle.Repo[i]
+	}
+	steps := make([]*planStep, 0)
+	desiredRepos := make(map[string]bool)
+	for i, repo := range state.desired.bundle.Repo {
+		checkoutPath := pointer.StringDeref(repo.Path, "")
+		desiredRepos[checkoutPath] = true
+		item, err := compare(bean.KindGitMaterial, checkoutPath, currentRepos[checkoutPath], &state.desired.bundle.Repo[i], false)
+		if err != nil {
+			return nil, err
+		}
+		gitMaterial := &pkgBean.GitMaterial{
+			Url:             pointer.StringDeref(repo.Url, ""),
+			GitProviderId:   state.desired.gitProviderIds[pointer.StringDeref(repo.GitProvider, "")],
+			CheckoutPath:    checkoutPath,
+			FetchSubmodules: repo.FetchSubmodules,
+			FilterPattern:   repo.FilterPattern,
+		}
+		step := &planStep{item: item}
+		switch item.Action {
+		case bean.PlanActionCreate:
+			step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+				resp, err := impl.pipelineBuilder.CreateMaterialsForApp(&pkgBean.CreateMaterialDTO{
+					AppId:    state.appId,
+					Material: []*pkgBean.GitMaterial{gitMaterial},
+					UserId:   state.userId,
+				})
+				if err != nil {
+					return err
+				}
+				state.current.gitMaterialIds[checkoutPath] = resp.Material[0].Id
+				return nil
+			}
+		case bean.PlanActionUpdate:
+			gitMaterial.Id = state.current.gitMaterialIds[checkoutPath]
+			step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+				_, err := impl.pipelineBuilder.UpdateMaterialsForApp(&pkgBean.UpdateMaterialDTO{
+					AppId:    state.appId,
+					Material: gitMaterial,
+					UserId:   state.userId,
+				})
+				return err
+			}
+		}
+		steps = append(steps, step)
+	}
+	for _, repo := range state.current.bundle.Repo {
+		if checkoutPath := pointer.StringDeref(repo.Path, ""); !desiredRepos[checkoutPath] {
+			steps = append(steps, unmanagedStep(bean.KindGitMaterial, checkoutPath))
+		}
+	}
+	return steps, nil
+}
+
+// getBuildConfig returns the build config of the app as compared, nil if the app has no docker registry set
+func getBuildConfig(bundle *v1.App) *v1.App {
+	if len(bundle.DockerRegistry) == 0 {
+		return nil
+	}
+	return &v1.App{DockerRegistry: bundle.DockerRegistry, DockerRepo: bundle.DockerRepo, DockerConfig: bundle.DockerConfig}
+}
+
+func (impl *AppAsCodeServiceImpl) getBuildConfigSteps(state *importState) ([]*planStep, error) {
+	currentConfig, desiredConfig := getBuildConfig(state.current.bundle), getBuildConfig(state.desired.bundle)
+	if desiredConfig == nil {
+		if currentConfig == nil {
+			return nil, nil
+		}
+		return []*planStep{unmanagedStep(bean.KindBuildConfig, "")}, nil
+	}
+	item, err := compare(bean.KindBuildConfig, "", currentConfig, desiredConfig, false)
+	if err != nil {
+		return nil, err
+	}
+	step := &planStep{item: item}
+	switch item.Action {
+	case bean.PlanActionCreate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			ciBuildConfig, err := adapter.ConvertDockerConfigToCiBuildConfig(&desiredConfig.DockerConfig, state.getGitMaterialIds())
+			if err != nil {
+				return err
+			}
+			_, err = impl.pipelineBuilder.CreateCiPipeline(&pkgBean.CiConfigRequest{
+				AppId:             state.appId,
+				DockerRegistry:    desiredConfig.DockerRegistry,
+				DockerRepository:  desiredConfig.DockerRepo,
+				CiBuildConfig:     ciBuildConfig,
+				DockerRegistryUrl: desiredConfig.DockerRegistry,
+				UserId:            state.userId,
+			})
+			return err
+		}
+	case bean.PlanActionUpdate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			ciBuildConfig, err := adapter.ConvertDockerConfigToCiBuildConfig(&desiredConfig.DockerConfig, state.getGitMaterialIds())
+			if err != nil {
+				return err
+			}
+			request := *state.current.ciTemplate
+			request.AppId = state.appId
+			request.DockerRegistry = desiredConfig.DockerRegistry
+			request.DockerRepository = desiredConfig.DockerRepo
+			request.CiBuildConfig = ciBuildConfig
+			request.CiPipelines = nil
+			request.UserId = state.userId
+			_, err = impl.pipelineBuilder.UpdateCiTemplate(&request)
+			return err
+		}
+	}
+	return []*planStep{step}, nil
+}
+
+func (impl *AppAsCodeServiceImpl) getDeploymentTemplateSteps(state *importState) ([]*planStep, error) {
+	currentTemplate, desiredTemplate := state.current.bundle.DeploymentTemplate, state.desired.bundle.DeploymentTemplate
+	if desiredTemplate == nil {
+		if currentTemplate == nil {
+			return nil, nil
+		}
+		return []*planStep{unmanagedStep(bean.KindDeploymentTemplate, "")}, nil
+	}
+	item, err := compare(bean.KindDeploymentTemplate, "", currentTemplate, desiredTemplate, false)
+	if err != nil {
+		return nil, err
+	}
+	step := &planStep{item: item}
+	if item.Action.IsChange() {
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			return impl.saveBaseDeploymentTemplate(ctx, state, desiredTemplate, userMetadata)
+		}
+	}
+	return []*planStep{step}, nil
+}
+
+// saveBaseDeploymentTemplate updates the chart of the app for the chart version of the template, the chart is
+// created if the app never used that version
+func (impl *AppAsCodeServiceImpl) saveBaseDeploymentTemplate(ctx context.Context, state *importState, deploymentTemplate *v1.DeploymentTemplate,
+	userMetadata *userBean.UserMetadata) error {
+	values, err := json.Marshal(deploymentTemplate.ValuesOverride)
+	if err != nil {
+		return err
+	}
+	chartRefId := state.desired.chartRefIds[getChartKey(deploymentTemplate)]
+	chartId := 0
+	if baseChart := state.current.baseChart; baseChart != nil && baseChart.ChartRefId == chartRefId {
+		chartId = baseChart.Id
+	} else if baseChart != nil {
+		chart, err := impl.chartRepository.FindChartByAppIdAndRefId(state.appId, chartRefId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching chart of app", "appId", state.appId, "chartRefId", chartRefId, "err", err)
+			return err
+		}
+		if chart != nil {
+			chartId = chart.Id
+		}
+	}
+	if chartId == 0 {
+		_, err = impl.draftAwareConfigService.Create(ctx, chartBean.TemplateRequest{
+			AppId:          state.appId,
+			ChartRefId:     chartRefId,
+			ValuesOverride: values,
+			UserId:         state.userId,
+		}, userMetadata)
+		return err
+	}
+	request := &chartBean.TemplateRequest{
+		Id:             chartId,
+		AppId:          state.appId,
+		ChartRefId:     chartRefId,
+		ValuesOverride: values,
+		UserId:         state.userId,
+	}
+	if baseChart := state.current.baseChart; baseChart.Id == chartId {
+		request.IsBasicViewLocked = baseChart.IsBasicViewLocked
+		request.CurrentViewEditor = baseChart.CurrentViewEditor
+	}
+	_, err = impl.draftAwareConfigService.UpdateAppOverride(ctx, request, "", userMetadata)
+	return err
+}
+
+func getDataType(kind bean.ResourceKind) string {
+	if kind == bean.KindSecret {
+		return v1.Secret
+	}
+	return v1.ConfigMap
+}
+
+// getConfigDataSteps compares the config maps or secrets of the app, or of an environment if env is set
+func (impl *AppAsCodeServiceImpl) getConfigDataSteps(state *importState, kind bean.ResourceKind, env *repository.Environment,
+	current, desired []v1.DataHolder) ([]*planStep, error) {
+	dataType := getDataType(kind)
+	currentByName := make(map[string]*v1.DataHolder, len(current))
+	for i := range current {
+		currentByName[adapter.GetDataHolderName(current[i], dataType)] = &current[i]
+	}
+	desiredByName := make(map[string]*v1.DataHolder, len(desired))
+	names := make([]string, 0, len(current)+len(desired))
+	for i := range desired {
+		name := adapter.GetDataHolderName(desired[i], dataType)
+		desiredByName[name] = &desired[i]
+		names = append(names, name)
+	}
+	for i := range current {
+		if name := adapter.GetDataHolderName(current[i], dataType); desiredByName[name] == nil {
+			names = append(names, name)
+		}
+	}
+	steps := make([]*planStep, 0, len(names))
+	for _, name := range names {
+		item, err := compare(kind, name, currentByName[name], desiredByName[name], state.prune)
+		if err != nil {
+			return nil, err
+		}
+		setEnvironment(item, env)
+		if kind == bean.KindSecret && item.Action == bean.PlanActionUpdate {
+			// the secret values are not shown, only which of them change
+			for _, change := range item.Changes {
+				if strings.HasPrefix(change.Path, "/data/") {
+					change.Current, change.Desired = bean.SecretMaskedValue, bean.SecretMaskedValue
+				}
+			}
+		}
+		step := &planStep{item: item}
+		name, holder := name, desiredByName[name]
+		switch item.Action {
+		case bean.PlanActionCreate, bean.PlanActionUpdate:
+			step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+				configData, err := adapter.ConvertDataHolderToConfigData(*holder, dataType)
+				if err != nil {
+					return err
+				}
+				return impl.saveConfigData(ctx, state, kind, env, configData, userMetadata)
+			}
+		case bean.PlanActionDelete:
+			step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+				return impl.deleteConfigData(ctx, state, kind, env, name, userMetadata)
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+func setEnvironment(item *bean.PlanItem, env *repository.Environment) {
+	if env != nil {
+		item.Environment = env.Name
+		item.EnvironmentId = env.Id
+	}
+}
+
+func (impl *AppAsCodeServiceImpl) saveConfigData(ctx context.Context, state *importState, kind bean.ResourceKind, env *repository.Environment,
+	configData *pipelineBean.ConfigData, userMetadata *userBean.UserMetadata) error {
+	request, err := impl.getConfigDataRequest(state, kind, env)
+	if err != nil {
+		return err
+	}
+	request.ConfigData = []*pipelineBean.ConfigData{configData}
+	switch {
+	case env == nil && kind == bean.KindConfigMap:
+		_, err = impl.draftAwareConfigService.CMGlobalAddUpdate(ctx, request, userMetadata)
+	case env == nil:
+		_, err = impl.draftAwareConfigService.CSGlobalAddUpdate(ctx, request, userMetadata)
+	case kind == bean.KindConfigMap:
+		_, err = impl.draftAwareConfigService.CMEnvironmentAddUpdate(ctx, request, userMetadata)
+	default:
+		_, err = impl.draftAwareConfigService.CSEnvironmentAddUpdate(ctx, request, userMetadata)
+	}
+	return err
+}
+
+func (impl *AppAsCodeServiceImpl) deleteConfigData(ctx context.Context, state *importState, kind bean.ResourceKind, env *repository.Environment,
+	name string, userMetadata *userBean.UserMetadata) error {
+	request, err := impl.getConfigDataRequest(state, kind, env)
+	if err != nil {
+		return err
+	}
+	switch {
+	case env == nil && kind == bean.KindConfigMap:
+		_, err = impl.draftAwareConfigService.CMGlobalDelete(ctx, name, request, userMetadata)
+	case env == nil:
+		_, err = impl.draftAwareConfigService.CSGlobalDelete(ctx, name, request, userMetadata)
+	case kind == bean.KindConfigMap:
+		_, err = impl.draftAwareConfigService.CMEnvironmentDelete(ctx, name, request, userMetadata)
+	default:
+		_, err = impl.draftAwareConfigService.CSEnvironmentDelete(ctx, name, request, userMetadata)
+	}
+	return err
+}
+
+// getConfigDataRequest returns the request with the id of the config maps or secrets saved at the level of env, the
+// id is fetched when the change is applied as it is created by the first config saved
+func (impl *AppAsCodeServiceImpl) getConfigDataRequest(state *importState, kind bean.ResourceKind, env *repository.Environment) (*pipelineBean.ConfigDataRequest, error) {
+	request := &pipelineBean.ConfigDataRequest{AppId: state.appId, UserId: state.userId}
+	var configDataRequest *pipelineBean.ConfigDataRequest
+	var err error
+	switch {
+	case env == nil && kind == bean.KindConfigMap:
+		configDataRequest, err = impl.configMapService.CMGlobalFetch(state.appId)
+	case env == nil:
+		configDataRequest, err = impl.configMapService.CSGlobalFetch(state.appId)
+	case kind == bean.KindConfigMap:
+		request.EnvironmentId = env.Id
+		configDataRequest, err = impl.configMapService.CMEnvironmentFetch(state.appId, env.Id)
+	default:
+		request.EnvironmentId = env.Id
+		configDataRequest, err = impl.configMapService.CSEnvironmentFetch(state.appId, env.Id)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching config id", "kind", kind, "appId", state.appId, "err", err)
+		return nil, err
+	}
+	if configDataRequest != nil {
+		request.Id = configDataRequest.Id
+	}
+	return request, nil
+}
+
+// getWorkflowSteps compares the workflows by name and their pipelines within. Workflows and pipelines are created and
+// updated, never deleted, and a pipeline can not move to another workflow or environment.
+func (impl *AppAsCodeServiceImpl) getWorkflowSteps(state *importState) ([]*planStep, error) {
+	currentWorkflows := make(map[string]v1.Workflow)
+	buildWorkflows, deploymentWorkflows := make(map[string]string), make(map[string]string)
+	deploymentEnvs := make(map[string]string)
+	for _, workflow := range getWorkflows(state.current.bundle) {
+		workflowName := getWorkflowName(workflow)
+		currentWorkflows[workflowName] = workflow
+		build, deployments := getWorkflowPipelines(workflow)
+		if build != nil {
+			buildWorkflows[getPipelineName(build.Destination)] = workflowName
+		}
+		for _, deployment := range deployments {
+			deploymentWorkflows[getPipelineName(deployment.Destination)] = workflowName
+			deploymentEnvs[getEnvironmentName(deployment)] = getPipelineName(deployment.Destination)
+		}
+	}
+	steps := make([]*planStep, 0)
+	desiredWorkflows := make(map[string]bool)
+	for _, workflow := range getWorkflows(state.desired.bundle) {
+		workflowName := getWorkflowName(workflow)
+		desiredWorkflows[workflowName] = true
+		build, deployments := getWorkflowPipelines(workflow)
+		currentWorkflow, exists := currentWorkflows[workflowName]
+		var currentBuild *v1.Build
+		currentDeployments := make(map[string]*v1.Deployment)
+		if exists {
+			var workflowDeployments []*v1.Deployment
+			currentBuild, workflowDeployments = getWorkflowPipelines(currentWorkflow)
+			for _, deployment := range workflowDeployments {
+				currentDeployments[getPipelineName(deployment.Destination)] = deployment
+			}
+		}
+		item := &bean.PlanItem{Kind: bean.KindWorkflow, Name: workflowName, Action: bean.PlanActionNoChange}
+		if !exists {
+			item.Action = bean.PlanActionCreate
+			externalCi := isExternalCi(workflow)
+			steps = append(steps, &planStep{
+				item: item,
+				apply: func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+					return impl.createWorkflow(state, workflowName, externalCi)
+				},
+			})
+		} else if currentExternalCi := state.current.workflows[workflowName].externalCiId != 0; (currentExternalCi && build != nil) ||
+			(currentBuild != nil && (build == nil || getPipelineName(currentBuild.Destination) != getPipelineName(build.Destination))) ||
+			(!currentExternalCi && currentBuild == nil && isExternalCi(workflow)) {
+			steps = append(steps, conflictStep(item, "the build or the external ci of a workflow can not be changed"))
+			continue
+		} else {
+			steps = append(steps, &planStep{item: item})
+		}
+		if build != nil {
+			step, err := impl.getCiPipelineStep(state, workflowName, build, currentBuild, buildWorkflows)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		desiredDeployments := make(map[string]bool)
+		for _, deployment := range deployments {
+			name := getPipelineName(deployment.Destination)
+			desiredDeployments[name] = true
+			step, err := impl.getCdPipelineStep(state, workflowName, build, deployment, currentDeployments[name], deploymentWorkflows, deploymentEnvs)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		for name, deployment := range currentDeployments {
+			if !desiredDeployments[name] {
+				step := unmanagedStep(bean.KindCdPipeline, name)
+				step.item.Environment = getEnvironmentName(deployment)
+				steps = append(steps, step)
+			}
+		}
+	}
+	for _, workflow := range getWorkflows(state.current.bundle) {
+		if workflowName := getWorkflowName(workflow); !desiredWorkflows[workflowName] {
+			steps = append(steps, unmanagedStep(bean.KindWorkflow, workflowName))
+		}
+	}
+	return steps, nil
+}
+
+func (impl *AppAsCodeServiceImpl) createWorkflow(state *importState, workflowName string, externalCi bool) error {
+	savedWorkflow, err := impl.appWorkflowService.CreateAppWorkflow(appWorkflowBean.AppWorkflowDto{
+		Name:   workflowName,
+		AppId:  state.appId,
+		UserId: state.userId,
+	})
+	if err != nil {
+		return err
+	}
+	ids := &workflowIds{id: savedWorkflow.Id}
+	state.current.workflows[workflowName] = ids
+	if !externalCi {
+		return nil
+	}
+	tx, err := impl.pipelineRepository.GetConnection().Begin()
+	if err != nil {
+		impl.logger.Errorw("error in beginning transaction", "err", err)
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	ids.externalCiId, _, err = impl.pipelineBuilder.CreateExternalCiAndAppWorkflowMapping(state.appId, savedWorkflow.Id, state.userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in creating external ci", "appId", state.appId, "appWorkflowId", savedWorkflow.Id, "err", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *AppAsCodeServiceImpl) getCiPipelineStep(state *importState, workflowName string, build, current *v1.Build,
+	buildWorkflows map[string]string) (*planStep, error) {
+	name := getPipelineName(build.Destination)
+	item, err := compare(bean.KindCiPipeline, name, current, build, false)
+	if err != nil {
+		return nil, err
+	}
+	if item.Action == bean.PlanActionCreate {
+		if otherWorkflow, ok := buildWorkflows[name]; ok {
+			return conflictStep(item, fmt.Sprintf("the ci pipeline is in workflow %s", otherWorkflow)), nil
+		}
+	}
+	step := &planStep{item: item}
+	switch item.Action {
+	case bean.PlanActionCreate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			request := &pkgBean.CiPipeline{Name: name, Active: true, PipelineType: buildCommonBean.CI_BUILD}
+			err := setCiPipelineFields(state, build, request)
+			if err != nil {
+				return err
+			}
+			resp, err := impl.pipelineBuilder.PatchCiPipeline(&pkgBean.CiPatchRequest{
+				CiPipeline:    request,
+				AppId:         state.appId,
+				Action:        pkgBean.CREATE,
+				AppWorkflowId: state.current.workflows[workflowName].id,
+				UserId:        state.userId,
+			})
+			if err != nil {
+				return err
+			}
+			state.current.ciPipelineIds[name] = resp.CiPipelines[0].Id
+			return nil
+		}
+	case bean.PlanActionUpdate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			request := *state.current.ciPipelines[name]
+			err := setCiPipelineFields(state, build, &request)
+			if err != nil {
+				return err
+			}
+			_, err = impl.pipelineBuilder.PatchCiPipeline(&pkgBean.CiPatchRequest{
+				CiPipeline:    &request,
+				AppId:         state.appId,
+				Action:        pkgBean.UPDATE_SOURCE,
+				AppWorkflowId: state.current.workflows[workflowName].id,
+				UserId:        state.userId,
+			})
+			return err
+		}
+	}
+	return step, nil
+}
+
+// setCiPipelineFields sets the fields of the build on the ci pipeline request, the other fields of an existing
+// pipeline like its schedules and custom tag are kept
+func setCiPipelineFields(state *importState, build *v1.Build, request *pkgBean.CiPipeline) error {
+	gitMaterialIds := state.getGitMaterialIds()
+	ciMaterialIds := make(map[int]int, len(request.CiMaterial))
+	for _, material := range request.CiMaterial {
+		ciMaterialIds[material.GitMaterialId] = material.Id
+	}
+	request.CiMaterial = make([]*pkgBean.CiMaterial, 0, len(build.BuildMaterials))
+	for _, material := range build.BuildMaterials {
+		gitMaterialId := gitMaterialIds[material.GitMaterialUrl]
+		if gitMaterialId == 0 {
+			return fmt.Errorf("git material %q is not found", material.GitMaterialUrl)
+		}
+		request.CiMaterial = append(request.CiMaterial, &pkgBean.CiMaterial{
+			Id:            ciMaterialIds[gitMaterialId],
+			GitMaterialId: gitMaterialId,
+			Source: &pkgBean.SourceTypeConfig{
+				Type:  batch.TransformSourceType(material.Source.Type),
+				Value: material.Source.Value,
+				Regex: material.Source.Regex,
+			},
+		})
+	}
+	request.IsManual = build.Trigger == v1.Manual
+	request.ScanEnabled = build.ScanEnabled
+	request.DockerArgs = make(map[string]string, len(build.DockerArguments))
+	for key, value := range build.DockerArguments {
+		request.DockerArgs[key] = fmt.Sprint(value)
+	}
+	request.PreBuildStage = getStageOrEmpty(adapter.ConvertTaskToPipelineStage(build.PreBuild, pipelineStageRepository.PIPELINE_STAGE_TYPE_PRE_CI))
+	request.PostBuildStage = getStageOrEmpty(adapter.ConvertTaskToPipelineStage(build.PostBuild, pipelineStageRepository.PIPELINE_STAGE_TYPE_POST_CI))
+	request.IsDockerConfigOverridden = build.DockerConfig != nil
+	request.DockerConfigOverride = pkgBean.DockerConfigOverride{}
+	if build.DockerConfig != nil {
+		ciBuildConfig, err := adapter.ConvertDockerConfigToCiBuildConfig(build.DockerConfig, gitMaterialIds)
+		if err != nil {
+			return err
+		}
+		request.DockerConfigOverride = pkgBean.DockerConfigOverride{
+			DockerRegistry:   build.DockerRegistry,
+			DockerRepository: build.DockerRepo,
+			CiBuildConfig:    ciBuildConfig,
+		}
+	}
+	return nil
+}
+
+// getStageOrEmpty returns an empty stage for a missing one, saving an empty stage removes the existing one
+func getStageOrEmpty(stage *pipelineBean.PipelineStageDto) *pipelineBean.PipelineStageDto {
+	if stage == nil {
+		return &pipelineBean.PipelineStageDto{}
+	}
+	return stage
+}
+
+// getCdPipelineForm returns the deployment as compared for its cd pipeline, the config of its environment is
+// compared separately
+func getCdPipelineForm(deployment *v1.Deployment) *v1.Deployment {
+	if deployment == nil {
+		return nil
+	}
+	form := *deployment
+	form.ConfigMaps, form.Secrets, form.Template = nil, nil, nil
+	return &form
+}
+
+func (impl *AppAsCodeServiceImpl) getCdPipelineStep(state *importState, workflowName string, build *v1.Build, deployment, current *v1.Deployment,
+	deploymentWorkflows, deploymentEnvs map[string]string) (*planStep, error) {
+	name, envName := getPipelineName(deployment.Destination), getEnvironmentName(deployment)
+	if current != nil && len(deployment.DeploymentAppType) == 0 {
+		deployment.DeploymentAppType = current.DeploymentAppType
+	}
+	item, err := compare(bean.KindCdPipeline, name, getCdPipelineForm(current), getCdPipelineForm(deployment), false)
+	if err != nil {
+		return nil, err
+	}
+	env := state.desired.environments[envName]
+	setEnvironment(item, env)
+	switch {
+	case current == nil:
+		if otherWorkflow, ok := deploymentWorkflows[name]; ok {
+			return conflictStep(item, fmt.Sprintf("the cd pipeline is in workflow %s", otherWorkflow)), nil
+		}
+		if otherPipeline, ok := deploymentEnvs[envName]; ok {
+			return conflictStep(item, fmt.Sprintf("cd pipeline %s already deploys to the environment", otherPipeline)), nil
+		}
+	case getEnvironmentName(current) != envName || getDependsOn(current) != getDependsOn(deployment) || current.DeploymentAppType != deployment.DeploymentAppType:
+		return conflictStep(item, "the environment, deployment app type and the pipeline a cd pipeline depends on can not be changed"), nil
+	}
+	step := &planStep{item: item}
+	switch item.Action {
+	case bean.PlanActionCreate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			ids := state.current.workflows[workflowName]
+			request := &pkgBean.CDPipelineConfigObject{
+				Name:              name,
+				EnvironmentId:     env.Id,
+				Namespace:         env.Namespace,
+				AppWorkflowId:     ids.id,
+				DeploymentAppType: deployment.DeploymentAppType,
+			}
+			err := setCdPipelineFields(deployment, request)
+			if err != nil {
+				return err
+			}
+			if build != nil {
+				request.CiPipelineId = state.current.ciPipelineIds[getPipelineName(build.Destination)]
+			}
+			switch dependsOn := getDependsOn(deployment); {
+			case len(dependsOn) > 0:
+				request.ParentPipelineType = appWorkflowRepo.CDPIPELINE
+				request.ParentPipelineId = state.current.cdPipelineIds[dependsOn]
+			case build == nil:
+				request.ParentPipelineType = appWorkflowRepo.WEBHOOK
+				request.ParentPipelineId = ids.externalCiId
+			default:
+				request.ParentPipelineType = appWorkflowRepo.CIPIPELINE
+			}
+			// a new pipeline is created with its strategies like through the cd pipeline api, only the changes to
+			// the strategies of an existing pipeline go through the draft flow of a protected environment
+			resp, err := impl.pipelineBuilder.CreateCdPipelines(&pkgBean.CdPipelines{
+				Pipelines: []*pkgBean.CDPipelineConfigObject{request},
+				AppId:     state.appId,
+				UserId:    state.userId,
+			}, ctx)
+			if err != nil {
+				return err
+			}
+			state.current.cdPipelineIds[name] = resp.Pipelines[0].Id
+			return nil
+		}
+	case bean.PlanActionUpdate:
+		step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+			request := *state.current.cdPipelines[name]
+			err := setCdPipelineFields(deployment, &request)
+			if err != nil {
+				return err
+			}
+			request.PreDeployStage = getStageOrEmpty(request.PreDeployStage)
+			request.PostDeployStage = getStageOrEmpty(request.PostDeployStage)
+			// the strategies are part of the protected config of the environment, they are saved separately
+			// through the draft aware service so that a protected environment gets a draft instead
+			strategies := request.Strategies
+			request.Strategies = state.current.cdPipelines[name].Strategies
+			request.DeploymentTemplate = state.current.cdPipelines[name].DeploymentTemplate
+			_, err = impl.pipelineBuilder.PatchCdPipelines(&pkgBean.CDPatchRequest{
+				Pipeline: &request,
+				AppId:    state.appId,
+				Action:   pkgBean.CD_UPDATE,
+				UserId:   state.userId,
+			}, ctx)
+			if err != nil || reflect.DeepEqual(current.Strategy, deployment.Strategy) {
+				return err
+			}
+			step.savedAsDraft, err = impl.draftAwareConfigService.UpdatePipelineStrategies(ctx, state.appId, env.Id, request.Id, strategies, state.userId)
+			return err
+		}
+	}
+	return step, nil
+}
+
+// setCdPipelineFields sets the fields of the deployment on the cd pipeline request, the other fields of an existing
+// pipeline like its custom tag and rollback config are kept
+func setCdPipelineFields(deployment *v1.Deployment, request *pkgBean.CDPipelineConfigObject) error {
+	strategies, err := batch.TransformStrategy(deployment)
+	if err != nil {
+		return err
+	}
+	request.TriggerType = adapter.ConvertTriggerToTriggerType(getTrigger(deployment.Trigger))
+	request.Strategies = strategies
+	for _, strategy := range strategies {
+		if strategy.Default {
+			request.DeploymentTemplate = strategy.DeploymentTemplate
+		}
+	}
+	request.PreDeployStage = adapter.ConvertTaskToPipelineStage(deployment.PreDeployment, pipelineStageRepository.PIPELINE_STAGE_TYPE_PRE_CD)
+	request.PostDeployStage = adapter.ConvertTaskToPipelineStage(deployment.PostDeployment, pipelineStageRepository.PIPELINE_STAGE_TYPE_POST_CD)
+	request.PreStageConfigMapSecretNames = pkgBean.PreStageConfigMapSecretNames{}
+	if task := deployment.PreDeployment; task != nil {
+		request.PreStageConfigMapSecretNames = pkgBean.PreStageConfigMapSecretNames{ConfigMaps: task.ConfigMaps, Secrets: task.Secrets}
+	}
+	request.PostStageConfigMapSecretNames = pkgBean.PostStageConfigMapSecretNames{}
+	if task := deployment.PostDeployment; task != nil {
+		request.PostStageConfigMapSecretNames = pkgBean.PostStageConfigMapSecretNames{ConfigMaps: task.ConfigMaps, Secrets: task.Secrets}
+	}
+	request.RunPreStageInEnv = deployment.RunPreStageInEnv
+	request.RunPostStageInEnv = deployment.RunPostStageInEnv
+	request.IsDigestEnforcedForPipeline = deployment.IsDigestEnforced
+	return nil
+}
+
+// getEnvironmentConfigSteps compares the deployment template, config maps and secrets overridden on the environment
+// of each deployment of the bundle, the config of the environments of the other deployments is left as it is
+func (impl *AppAsCodeServiceImpl) getEnvironmentConfigSteps(state *importState) ([]*planStep, error) {
+	currentDeployments := make(map[string]*v1.Deployment)
+	for _, deployment := range getDeployments(state.current.bundle) {
+		currentDeployments[getPipelineName(deployment.Destination)] = deployment
+	}
+	steps := make([]*planStep, 0)
+	for _, desired := range getDeployments(state.desired.bundle) {
+		envName := getEnvironmentName(desired)
+		current := currentDeployments[getPipelineName(desired.Destination)]
+		if current == nil || getEnvironmentName(current) != envName {
+			current = &v1.Deployment{}
+		}
+		env := state.desired.environments[envName]
+		if current.Template != nil || desired.Template != nil {
+			item, err := compare(bean.KindDeploymentTemplate, "", current.Template, desired.Template, state.prune)
+			if err != nil {
+				return nil, err
+			}
+			setEnvironment(item, env)
+			step := &planStep{item: item}
+			deploymentTemplate := desired.Template
+			switch item.Action {
+			case bean.PlanActionCreate, bean.PlanActionUpdate:
+				step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+					return impl.saveEnvDeploymentTemplate(ctx, state, env, deploymentTemplate, userMetadata)
+				}
+			case bean.PlanActionDelete:
+				step.apply = func(ctx context.Context, userMetadata *userBean.UserMetadata) error {
+					return impl.resetEnvDeploymentTemplate(ctx, state, env, userMetadata)
+				}
+			}
+			steps = append(steps, step)
+		}
+		configMapSteps, err := impl.getConfigDataSteps(state, bean.KindConfigMap, env, current.ConfigMaps, desired.ConfigMaps)
+		if err != nil {
+			return nil, err
+		}
+		secretSteps, err := impl.getConfigDataSteps(state, bean.KindSecret, env, current.Secrets, desired.Secrets)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(append(steps, configMapSteps...), secretSteps...)
+	}
+	return steps, nil
+}
+
+// saveEnvDeploymentTemplate overrides the deployment template on the env, the env properties are fetched when the
+// change is applied as they are created with the cd pipeline
+func (impl *AppAsCodeServiceImpl) saveEnvDeploymentTemplate(ctx context.Context, state *importState, env *repository.Environment,
+	deploymentTemplate *v1.DeploymentTemplate, userMetadata *userBean.UserMetadata) error {
+	values, err := json.Marshal(deploymentTemplate.ValuesOverride)
+	if err != nil {
+		return err
+	}
+	properties, err := impl.propertiesConfigService.GetLatestEnvironmentProperties(state.appId, env.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env properties", "appId", state.appId, "envId", env.Id, "err", err)
+		return err
+	}
+	appMetrics, err := impl.deployedAppMetricsService.GetMetricsFlagForAPipelineByAppIdAndEnvId(state.appId, env.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app metrics flag", "appId", state.appId, "envId", env.Id, "err", err)
+		return err
+	}
+	chartRefId := state.desired.chartRefIds[getChartKey(deploymentTemplate)]
+	if properties == nil || properties.Id == 0 {
+		_, err = impl.draftAwareConfigService.CreateEnvironmentPropertiesAndBaseIfNeeded(ctx, &pipelineBean.EnvironmentProperties{
+			AppId:             state.appId,
+			EnvironmentId:     env.Id,
+			ChartRefId:        chartRefId,
+			EnvOverrideValues: values,
+			AppMetrics:        &appMetrics,
+			Namespace:         env.Namespace,
+			MergeStrategy:     deploymentTemplate.MergeStrategy,
+			IsOverride:        true,
+			Active:            true,
+			UserId:            state.userId,
+		}, userMetadata)
+		return err
+	}
+	request := *properties
+	request.AppId = state.appId
+	request.EnvironmentId = env.Id
+	request.ChartRefId = chartRefId
+	request.EnvOverrideValues = values
+	request.MergeStrategy = deploymentTemplate.MergeStrategy
+	request.AppMetrics = &appMetrics
+	request.IsOverride = true
+	request.UserId = state.userId
+	_, err = impl.draftAwareConfigService.UpdateEnvironmentProperties(ctx, &request, "", userMetadata)
+	return err
+}
+
+func (impl *AppAsCodeServiceImpl) resetEnvDeploymentTemplate(ctx context.Context, state *importState, env *repository.Environment,
+	userMetadata *userBean.UserMetadata) error {
+	properties, err := impl.propertiesConfigService.GetLatestEnvironmentProperties(state.appId, env.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env properties", "appId", state.appId, "envId", env.Id, "err", err)
+		return err
+	}
+	if properties == nil || !properties.IsOverride {
+		return nil
+	}
+	_, err = impl.draftAwareConfigService.ResetEnvironmentProperties(ctx, &pipelineBean.EnvironmentProperties{
+		Id:            properties.Id,
+		AppId:         state.appId,
+		EnvironmentId: env.Id,
+		UserId:        state.userId,
+	}, userMetadata)
+	return err
+}
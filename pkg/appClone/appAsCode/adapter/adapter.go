@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+	pkgBean "github.com/devtron-labs/devtron/pkg/bean"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"k8s.io/utils/pointer"
+	"reflect"
+)
+
+func ConvertGitMaterialToRepo(material *pkgBean.GitMaterial, gitProvider string) v1.Repo {
+	return v1.Repo{
+		Path:            pointer.String(material.CheckoutPath),
+		Url:             pointer.String(material.Url),
+		GitProvider:     pointer.String(gitProvider),
+		FetchSubmodules: material.FetchSubmodules,
+		FilterPattern:   material.FilterPattern,
+	}
+}
+
+// ConvertCiBuildConfigToDockerConfig refers to the git materials of the build config by url
+func ConvertCiBuildConfigToDockerConfig(ciBuildConfig *buildBean.CiBuildConfigBean, gitMaterialUrls map[int]string) *v1.DockerConfig {
+	if ciBuildConfig == nil {
+		return &v1.DockerConfig{}
+	}
+	dockerConfig := &v1.DockerConfig{
+		GitMaterial:             gitMaterialUrls[ciBuildConfig.GitMaterialId],
+		BuildContextGitMaterial: gitMaterialUrls[ciBuildConfig.BuildContextGitMaterialId],
+		CiBuildConfig:           GetCiBuildConfigWithoutIds(ciBuildConfig),
+	}
+	if len(dockerConfig.BuildContextGitMaterial) == 0 {
+		dockerConfig.BuildContextGitMaterial = dockerConfig.GitMaterial
+	}
+	return dockerConfig
+}
+
+func GetCiBuildConfigWithoutIds(ciBuildConfig *buildBean.CiBuildConfigBean) *buildBean.CiBuildConfigBean {
+	if ciBuildConfig == nil {
+		return nil
+	}
+	config := *ciBuildConfig
+	config.Id, config.GitMaterialId, config.BuildContextGitMaterialId = 0, 0, 0
+	return &config
+}
+
+// ConvertDockerConfigToCiBuildConfig resolves the git materials of the docker config by url
+func ConvertDockerConfigToCiBuildConfig(dockerConfig *v1.DockerConfig, gitMaterialIds map[string]int) (*buildBean.CiBuildConfigBean, error) {
+	ciBuildConfig := GetCiBuildConfigWithoutIds(dockerConfig.CiBuildConfig)
+	ciBuildConfig.GitMaterialId = gitMaterialIds[dockerConfig.GitMaterial]
+	ciBuildConfig.BuildContextGitMaterialId = gitMaterialIds[dockerConfig.BuildContextGitMaterial]
+	if ciBuildConfig.GitMaterialId == 0 || ciBuildConfig.BuildContextGitMaterialId == 0 {
+		return nil, fmt.Errorf("git material %q or %q of the docker config is not found", dockerConfig.GitMaterial, dockerConfig.BuildContextGitMaterial)
+	}
+	return ciBuildConfig, nil
+}
+
+func ConvertToDeploymentTemplate(chartName, chartVersion string, values json.RawMessage, mergeStrategy models.MergeStrategy) (*v1.DeploymentTemplate, error) {
+	valuesOverride := make(map[string]interface{})
+	if len(values) > 0 {
+		err := json.Unmarshal(values, &valuesOverride)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &v1.DeploymentTemplate{
+		RefChartTemplate:        chartName,
+		RefChartTemplateVersion: chartVersion,
+		ValuesOverride:          valuesOverride,
+		MergeStrategy:           mergeStrategy,
+	}, nil
+}
+
+// ConvertConfigDataToDataHolder names the data holder by the config map or the secret as per dataType
+func ConvertConfigDataToDataHolder(configData *pipelineBean.ConfigData, dataType string) (v1.DataHolder, error) {
+	name := configData.Name
+	holder := v1.DataHolder{
+		Destination:    &v1.ResourcePath{},
+		External:       configData.External,
+		ExternalType:   configData.ExternalSecretType,
+		Global:         configData.Global,
+		MountPath:      configData.MountPath,
+		Type:           configData.Type,
+		MergeStrategy:  configData.MergeStrategy,
+		SubPath:        configData.SubPath,
+		FilePermission: configData.FilePermission,
+		RoleARN:        configData.RoleARN,
+		ESOSubPath:     configData.ESOSubPath,
+		ExternalSecret: configData.ExternalSecret,
+	}
+	if dataType == v1.Secret {
+		holder.Destination.Secret = &name
+	} else {
+		holder.Destination.ConfigMap = &name
+	}
+	if !reflect.ValueOf(configData.ESOSecretData).IsZero() {
+		esoSecretData := configData.ESOSecretData
+		holder.ESOSecretData = &esoSecretData
+	}
+	if len(configData.Data) > 0 {
+		err := json.Unmarshal(configData.Data, &holder.Data)
+		if err != nil {
+			return holder, err
+		}
+	}
+	return holder, nil
+}
+
+func ConvertDataHolderToConfigData(holder v1.DataHolder, dataType string) (*pipelineBean.ConfigData, error) {
+	configData := &pipelineBean.ConfigData{
+		Name:               GetDataHolderName(holder, dataType),
+		Type:               holder.Type,
+		External:           holder.External,
+		MountPath:          holder.MountPath,
+		MergeStrategy:      holder.MergeStrategy,
+		Global:             holder.Global,
+		ExternalSecretType: holder.ExternalType,
+		ExternalSecret:     holder.ExternalSecret,
+		RoleARN:            holder.RoleARN,
+		SubPath:            holder.SubPath,
+		ESOSubPath:         holder.ESOSubPath,
+		FilePermission:     holder.FilePermission,
+	}
+	if holder.ESOSecretData != nil {
+		configData.ESOSecretData = *holder.ESOSecretData
+	}
+	if holder.Data != nil {
+		data, err := json.Marshal(holder.Data)
+		if err != nil {
+			return nil, err
+		}
+		configData.Data = data
+	}
+	return configData, nil
+}
+
+func GetDataHolderName(holder v1.DataHolder, dataType string) string {
+	if holder.Destination == nil {
+		return ""
+	}
+	if dataType == v1.Secret {
+		return pointer.StringDeref(holder.Destination.Secret, "")
+	}
+	return pointer.StringDeref(holder.Destination.ConfigMap, "")
+}
+
+func ConvertTriggerTypeToTrigger(triggerType pipelineConfig.TriggerType) v1.Trigger {
+	if triggerType.IsManual() {
+		return v1.Manual
+	}
+	return v1.Automatic
+}
+
+func ConvertTriggerToTriggerType(trigger v1.Trigger) pipelineConfig.TriggerType {
+	if trigger == v1.Manual {
+		return pipelineConfig.TRIGGER_TYPE_MANUAL
+	}
+	return pipelineConfig.TRIGGER_TYPE_AUTOMATIC
+}
+
+// ConvertPipelineStageToTask returns nil for a stage without steps
+func ConvertPipelineStageToTask(stage *pipelineBean.PipelineStageDto) *v1.Task {
+	if stage == nil || len(stage.Steps) == 0 {
+		return nil
+	}
+	return &v1.Task{Steps: stage.Steps}
+}
+
+// ConvertCdStageToTask returns nil for a stage without steps and without config maps and secrets
+func ConvertCdStageToTask(stage *pipelineBean.PipelineStageDto, configMaps, secrets []string) *v1.Task {
+	task := ConvertPipelineStageToTask(stage)
+	if task == nil && len(configMaps) == 0 && len(secrets) == 0 {
+		return nil
+	} else if task == nil {
+		task = &v1.Task{}
+	}
+	triggerType := pipelineConfig.TRIGGER_TYPE_AUTOMATIC
+	if stage != nil {
+		triggerType = stage.TriggerType
+	}
+	trigger := ConvertTriggerTypeToTrigger(triggerType)
+	task.Trigger = &trigger
+	if len(configMaps) > 0 {
+		task.ConfigMaps = configMaps
+	}
+	if len(secrets) > 0 {
+		task.Secrets = secrets
+	}
+	return task
+}
+
+// ConvertTaskToPipelineStage returns nil for a task without steps
+func ConvertTaskToPipelineStage(task *v1.Task, stageType repository.PipelineStageType) *pipelineBean.PipelineStageDto {
+	if task == nil || len(task.Steps) == 0 {
+		return nil
+	}
+	stage := &pipelineBean.PipelineStageDto{Type: stageType, Steps: task.Steps}
+	if task.Trigger != nil {
+		stage.TriggerType = ConvertTriggerToTriggerType(*task.Trigger)
+	}
+	return stage
+}
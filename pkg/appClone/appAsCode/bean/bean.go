@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+)
+
+// BundleApiVersion is the api version of the bundle, which is the app of the batch operation api (v1.App). The
+// components refer to each other and to the instance level resources by name, never by id, so a bundle can be applied
+// to any app.
+const BundleApiVersion = "app/v1"
+
+type ResourceKind string
+
+const (
+	KindGitMaterial        ResourceKind = "GitMaterial"
+	KindBuildConfig        ResourceKind = "BuildConfig"
+	KindDeploymentTemplate ResourceKind = "DeploymentTemplate"
+	KindConfigMap          ResourceKind = "ConfigMap"
+	KindSecret             ResourceKind = "Secret"
+	KindWorkflow           ResourceKind = "Workflow"
+	KindCiPipeline         ResourceKind = "CiPipeline"
+	KindCdPipeline         ResourceKind = "CdPipeline"
+)
+
+type PlanAction string
+
+const (
+	PlanActionCreate   PlanAction = "Create"
+	PlanActionUpdate   PlanAction = "Update"
+	PlanActionDelete   PlanAction = "Delete"
+	PlanActionNoChange PlanAction = "NoChange"
+	// PlanActionUnmanaged the resource exists in the app only and is left as it is
+	PlanActionUnmanaged PlanAction = "Unmanaged"
+	// PlanActionConflict the change can not be applied in place, like moving a cd pipeline to another environment.
+	// A plan with conflicts is not applied.
+	PlanActionConflict PlanAction = "Conflict"
+)
+
+func (action PlanAction) IsChange() bool {
+	return action == PlanActionCreate || action == PlanActionUpdate || action == PlanActionDelete
+}
+
+type ImportRequest struct {
+	AppId int `json:"appId" validate:"required,number,gt=0"`
+	// Prune deletes the config maps, secrets and deployment template overrides missing in the bundle. Git materials,
+	// workflows and pipelines are never deleted.
+	Prune  bool    `json:"prune"`
+	Bundle *v1.App `json:"bundle" validate:"required"`
+	UserId int32   `json:"-"`
+}
+
+type Plan struct {
+	AppId    int         `json:"appId"`
+	Items    []*PlanItem `json:"items"`
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+func (plan *Plan) HasConflicts() bool {
+	for _, item := range plan.Items {
+		if item.Action == PlanActionConflict {
+			return true
+		}
+	}
+	return false
+}
+
+type PlanItem struct {
+	Kind          ResourceKind   `json:"kind"`
+	Name          string         `json:"name,omitempty"`
+	Environment   string         `json:"environment,omitempty"`
+	EnvironmentId int            `json:"-"`
+	Action        PlanAction     `json:"action"`
+	Changes       []*FieldChange `json:"changes,omitempty"`
+	Message       string         `json:"message,omitempty"`
+}
+
+// FieldChange is a changed value of an updated resource, Path is the json pointer of the value
+type FieldChange struct {
+	Path    string      `json:"path"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+type ApplyResponse struct {
+	Applied []*PlanItem `json:"applied"`
+	// SavedAsDraft lists the environments whose config is protected, the changes to their config and to the
+	// deployment strategies of their cd pipelines are saved as drafts to be approved
+	SavedAsDraft []string `json:"savedAsDraft,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// SecretMaskedValue replaces the secret values in the export for the users without admin access. A masked value
+// in an applied bundle keeps the current value of the key.
+const SecretMaskedValue = "********"
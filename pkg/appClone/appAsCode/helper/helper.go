@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+	"github.com/devtron-labs/devtron/pkg/appClone/appAsCode/bean"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Diff compares the json forms of current and desired leaf by leaf, the changes are keyed by the json pointer of the
+// leaf. Lists are compared as a whole. No changes means both are equal.
+func Diff(current, desired interface{}) ([]*bean.FieldChange, error) {
+	currentValue, err := toJsonValue(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredValue, err := toJsonValue(desired)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]*bean.FieldChange, 0)
+	diff("", currentValue, desiredValue, &changes)
+	return changes, nil
+}
+
+func diff(pointer string, current, desired interface{}, changes *[]*bean.FieldChange) {
+	currentMap, isCurrentMap := current.(map[string]interface{})
+	desiredMap, isDesiredMap := desired.(map[string]interface{})
+	if !isCurrentMap || !isDesiredMap {
+		if !reflect.DeepEqual(current, desired) {
+			*changes = append(*changes, &bean.FieldChange{Path: pointer, Current: current, Desired: desired})
+		}
+		return
+	}
+	keys := make([]string, 0, len(currentMap)+len(desiredMap))
+	for key := range currentMap {
+		keys = append(keys, key)
+	}
+	for key := range desiredMap {
+		if _, ok := currentMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		diff(pointer+"/"+pointerEscaper.Replace(key), currentMap[key], desiredMap[key], changes)
+	}
+}
+
+// toJsonValue converts value to its generic json form, so that raw json and typed values compare alike
+func toJsonValue(value interface{}) (interface{}, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return nil, nil
+	}
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var jsonValue interface{}
+	err = json.Unmarshal(valueJson, &jsonValue)
+	return jsonValue, err
+}
+
+// TrimAppNamePrefix removes the app name prefix added to the pipeline names by the app clone and the pipeline
+// creation, so that the names in a bundle do not depend on the app
+func TrimAppNamePrefix(name, appName, separator string) string {
+	return strings.TrimPrefix(name, appName+separator)
+}
+
+// GetPluginIds returns the ids of the plugins used in the steps of the tasks, without duplicates
+func GetPluginIds(tasks ...*v1.Task) []int {
+	pluginIds := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		for _, step := range task.Steps {
+			if step.RefPluginStepDetail == nil || seen[step.RefPluginStepDetail.PluginId] {
+				continue
+			}
+			seen[step.RefPluginStepDetail.PluginId] = true
+			pluginIds = append(pluginIds, step.RefPluginStepDetail.PluginId)
+		}
+	}
+	return pluginIds
+}
+
+// ReplacePluginIds maps the plugin ids used in the steps of the tasks to the ids in pluginIdMap
+func ReplacePluginIds(pluginIdMap map[int]int, tasks ...*v1.Task) error {
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		for _, step := range task.Steps {
+			if step.RefPluginStepDetail == nil {
+				continue
+			}
+			pluginId, ok := pluginIdMap[step.RefPluginStepDetail.PluginId]
+			if !ok {
+				return fmt.Errorf("plugin %d used in step %q is not listed in the plugins", step.RefPluginStepDetail.PluginId, step.Name)
+			}
+			step.RefPluginStepDetail.PluginId = pluginId
+		}
+	}
+	return nil
+}
+
+// MaskSecretData replaces the values of the secret data with bean.SecretMaskedValue
+func MaskSecretData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(data))
+	for key := range data {
+		masked[key] = bean.SecretMaskedValue
+	}
+	return masked
+}
+
+// UnmaskSecretData replaces the masked values of the desired secret data with the values of the same keys in the
+// current data. A masked key missing in the current data is an error, as its value is unknown.
+func UnmaskSecretData(desired, current map[string]interface{}) (map[string]interface{}, error) {
+	if desired == nil {
+		return nil, nil
+	}
+	unmasked := make(map[string]interface{}, len(desired))
+	for key, value := range desired {
+		unmasked[key] = value
+		if value != bean.SecretMaskedValue {
+			continue
+		}
+		currentValue, ok := current[key]
+		if !ok {
+			return nil, fmt.Errorf("value of key %q is masked and the key is not set yet", key)
+		}
+		unmasked[key] = currentValue
+	}
+	return unmasked, nil
+}
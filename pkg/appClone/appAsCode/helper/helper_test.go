@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	v1 "github.com/devtron-labs/devtron/pkg/apis/devtron/v1"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	current := &v1.DeploymentTemplate{
+		RefChartTemplate:        "Deployment",
+		RefChartTemplateVersion: "4.18.0",
+		ValuesOverride:          map[string]interface{}{"replicaCount": 1, "resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}}, "hosts": []string{"a"}},
+	}
+	desired := &v1.DeploymentTemplate{
+		RefChartTemplate:        "Deployment",
+		RefChartTemplateVersion: "4.18.0",
+		ValuesOverride:          map[string]interface{}{},
+	}
+	err := json.Unmarshal([]byte(`{"replicaCount":1,"resources":{"limits":{"cpu":"2","memory":"1Gi"}},"hosts":["a","b"]}`), &desired.ValuesOverride)
+	assert.NoError(t, err)
+	changes, err := Diff(current, desired)
+	assert.NoError(t, err)
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	assert.Equal(t, []string{"/valuesOverride/hosts", "/valuesOverride/resources/limits/cpu", "/valuesOverride/resources/limits/memory"}, paths)
+	assert.Nil(t, changes[2].Current)
+	assert.Equal(t, "1Gi", changes[2].Desired)
+
+	changes, err = Diff(current, current)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = Diff(nil, desired)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "", changes[0].Path)
+}
+
+func TestReplacePluginIds(t *testing.T) {
+	task := &v1.Task{Steps: []*pipelineBean.PipelineStageStepDto{
+		{Name: "script", InlineStepDetail: &pipelineBean.InlineStepDetailDto{Script: "echo"}},
+		{Name: "sonar", RefPluginStepDetail: &pipelineBean.RefPluginStepDetailDto{PluginId: 7}},
+		{Name: "sonar again", RefPluginStepDetail: &pipelineBean.RefPluginStepDetailDto{PluginId: 7}},
+	}}
+	other := &v1.Task{Steps: []*pipelineBean.PipelineStageStepDto{
+		{Name: "trivy", RefPluginStepDetail: &pipelineBean.RefPluginStepDetailDto{PluginId: 3}},
+	}}
+	assert.Equal(t, []int{7, 3}, GetPluginIds(task, nil, other))
+
+	assert.NoError(t, ReplacePluginIds(map[int]int{7: 70, 3: 30}, task, nil, other))
+	assert.Equal(t, []int{70, 30}, GetPluginIds(task, other))
+
+	err := ReplacePluginIds(map[int]int{70: 7}, task, other)
+	assert.Error(t, err, "plugin 30 is not mapped")
+}
+
+func TestUnmaskSecretData(t *testing.T) {
+	current := map[string]interface{}{"user": "YWRtaW4=", "password": "c2VjcmV0"}
+	assert.Equal(t, map[string]interface{}{"user": "********", "password": "********"}, MaskSecretData(current))
+
+	unmasked, err := UnmaskSecretData(map[string]interface{}{"user": "cm9vdA==", "password": "********"}, current)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user": "cm9vdA==", "password": "c2VjcmV0"}, unmasked)
+
+	_, err = UnmaskSecretData(map[string]interface{}{"token": "********"}, current)
+	assert.Error(t, err, "masked value of a new key can not be resolved")
+}
+
+func TestTrimAppNamePrefix(t *testing.T) {
+	assert.Equal(t, "build", TrimAppNamePrefix("payments-ci-build", "payments", "-ci-"))
+	assert.Equal(t, "prod", TrimAppNamePrefix("payments-prod", "payments", "-"))
+	assert.Equal(t, "orders-prod", TrimAppNamePrefix("orders-prod", "payments", "-"))
+}
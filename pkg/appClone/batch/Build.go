@@ -181,18 +181,10 @@ func executeBuildCreate(impl BuildActionImpl, build *v1.Build) error {
 
 	for _, material := range build.BuildMaterials {
 		stc := bean.SourceTypeConfig{
+			Type:  sourceTypes[material.Source.Type],
 			Value: material.Source.Value,
 			Regex: material.Source.Regex,
 		}
-		if material.Source.Type == v1.BranchFixed {
-			stc.Type = constants.SOURCE_TYPE_BRANCH_FIXED
-		} else if material.Source.Type == v1.BranchRegex {
-			stc.Type = constants.SOURCE_TYPE_BRANCH_REGEX
-		} else if material.Source.Type == v1.TagAny {
-			stc.Type = constants.SOURCE_TYPE_TAG_ANY
-		} else if material.Source.Type == v1.Webhook {
-			stc.Type = constants.SOURCE_TYPE_WEBHOOK
-		}
 
 		cm := bean.CiMaterial{
 			Source: &stc,
@@ -230,6 +222,28 @@ func executeBuildCreate(impl BuildActionImpl, build *v1.Build) error {
 	return nil
 }
 
+var sourceTypes = map[string]constants.SourceType{
+	v1.BranchFixed: constants.SOURCE_TYPE_BRANCH_FIXED,
+	v1.BranchRegex: constants.SOURCE_TYPE_BRANCH_REGEX,
+	v1.TagAny:      constants.SOURCE_TYPE_TAG_ANY,
+	v1.Webhook:     constants.SOURCE_TYPE_WEBHOOK,
+}
+
+// TransformSourceType returns the source type of a ci material for the v1 source type, empty if it is unknown
+func TransformSourceType(sourceType string) constants.SourceType {
+	return sourceTypes[sourceType]
+}
+
+// TransformToV1SourceType is the inverse of TransformSourceType
+func TransformToV1SourceType(sourceType constants.SourceType) string {
+	for v1SourceType, ciSourceType := range sourceTypes {
+		if ciSourceType == sourceType {
+			return v1SourceType
+		}
+	}
+	return ""
+}
+
 func transformScripts(task *v1.Task) []*bean.CiScript {
 	prePostDockerBuildScripts := make([]*bean.CiScript, 0)
 	if task != nil {
@@ -194,7 +194,7 @@ func transformToDeploymentConfig(deployment *v1.Deployment, env *bean2.Environme
 		return nil, fmt.Errorf("incorrect workflow name %s", *deployment.Destination.Workflow)
 	}
 
-	strategies, err2 := transformStrategy(deployment)
+	strategies, err2 := TransformStrategy(deployment)
 	if err2 != nil {
 		return nil, err2
 	}
@@ -279,7 +279,7 @@ func transformStages(deploymentStages []v1.Stage, preOrPost string) ([]byte, err
 	return postCDConf, err
 }
 
-func transformStrategy(deployment *v1.Deployment) ([]bean.Strategy, error) {
+func TransformStrategy(deployment *v1.Deployment) ([]bean.Strategy, error) {
 	strategies := make([]bean.Strategy, 0)
 	m := make(map[string]interface{}, 0)
 	strategy := make(map[string]interface{}, 0)
@@ -348,3 +348,51 @@ func transformStrategy(deployment *v1.Deployment) ([]bean.Strategy, error) {
 	}
 	return strategies, nil
 }
+
+// TransformToV1Strategy is the inverse of TransformStrategy, the keys of a strategy config which are not in the v1
+// model are left out
+func TransformToV1Strategy(strategies []bean.Strategy) (v1.DeploymentStrategy, error) {
+	deploymentStrategy := v1.DeploymentStrategy{}
+	for _, strategy := range strategies {
+		var key string
+		var target interface{}
+		switch strategy.DeploymentTemplate {
+		case "BLUE-GREEN":
+			deploymentStrategy.BlueGreen = &v1.BlueGreenStrategy{}
+			key, target = "blueGreen", deploymentStrategy.BlueGreen
+		case "RECREATE":
+			deploymentStrategy.Recreate = &v1.RecreateStrategy{}
+			key, target = "recreate", deploymentStrategy.Recreate
+		case "CANARY":
+			deploymentStrategy.Canary = &v1.CanaryStrategy{}
+			key, target = "canary", deploymentStrategy.Canary
+		case "ROLLING":
+			deploymentStrategy.Rolling = &v1.RollingStrategy{}
+			key, target = "rolling", deploymentStrategy.Rolling
+		default:
+			return deploymentStrategy, fmt.Errorf("unsupported strategy %s", strategy.DeploymentTemplate)
+		}
+		if strategy.Default {
+			deploymentStrategy.Default = string(strategy.DeploymentTemplate)
+		}
+		if len(strategy.Config) == 0 {
+			continue
+		}
+		config := struct {
+			Deployment struct {
+				Strategy map[string]json.RawMessage `json:"strategy"`
+			} `json:"deployment"`
+		}{}
+		err := json.Unmarshal(strategy.Config, &config)
+		if err != nil {
+			return deploymentStrategy, fmt.Errorf("unable to parse %s strategy", strategy.DeploymentTemplate)
+		}
+		if value, ok := config.Deployment.Strategy[key]; ok {
+			err = json.Unmarshal(value, target)
+			if err != nil {
+				return deploymentStrategy, fmt.Errorf("unable to parse %s strategy", strategy.DeploymentTemplate)
+			}
+		}
+	}
+	return deploymentStrategy, nil
+}
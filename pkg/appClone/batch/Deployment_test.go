@@ -118,15 +118,15 @@ func Test_transformStrategy(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := transformStrategy(tt.args.deployment)
+			got, err := TransformStrategy(tt.args.deployment)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("transformStrategy() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("TransformStrategy() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
 				d, _ := json.Marshal(got)
 				fmt.Printf("%s\n", d)
-				t.Errorf("transformStrategy() got = %+v, want %+v", got, tt.want)
+				t.Errorf("TransformStrategy() got = %+v, want %+v", got, tt.want)
 			}
 		})
 	}
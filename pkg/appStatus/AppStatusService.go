@@ -20,6 +20,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident"
 	"github.com/devtron-labs/devtron/util/rbac"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -53,14 +54,17 @@ type AppStatusServiceImpl struct {
 	logger              *zap.SugaredLogger
 	enforcer            casbin.Enforcer
 	enforcerUtil        rbac.EnforcerUtil
+	incidentService     incident.IncidentService
 }
 
-func NewAppStatusServiceImpl(appStatusRepository appStatus.AppStatusRepository, logger *zap.SugaredLogger, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *AppStatusServiceImpl {
+func NewAppStatusServiceImpl(appStatusRepository appStatus.AppStatusRepository, logger *zap.SugaredLogger, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	incidentService incident.IncidentService) *AppStatusServiceImpl {
 	return &AppStatusServiceImpl{
 		appStatusRepository: appStatusRepository,
 		logger:              logger,
 		enforcer:            enforcer,
 		enforcerUtil:        enforcerUtil,
+		incidentService:     incidentService,
 	}
 
 }
@@ -75,6 +79,7 @@ func (impl *AppStatusServiceImpl) UpdateStatusWithAppIdEnvId(appId, envId int, s
 	if status == HealthStatusSuspended || status == HealthStatusHibernating {
 		status = HealthStatusHibernatingFilter
 	}
+	statusChanged := container.Status != status
 	if container.AppId == 0 {
 		container.AppId = appId
 		container.EnvId = envId
@@ -84,7 +89,7 @@ func (impl *AppStatusServiceImpl) UpdateStatusWithAppIdEnvId(appId, envId int, s
 			impl.logger.Errorw("error in Creating appStatus", "appId", appId, "envId", envId, "err", err)
 			return err
 		}
-	} else if container.Status != status {
+	} else if statusChanged {
 		container.Status = status
 		err = impl.appStatusRepository.Update(container)
 		if err != nil {
@@ -92,7 +97,7 @@ func (impl *AppStatusServiceImpl) UpdateStatusWithAppIdEnvId(appId, envId int, s
 			return err
 		}
 	}
-
+	impl.incidentService.HandleAppStatusUpdate(appId, envId, status, statusChanged)
 	return nil
 }
 
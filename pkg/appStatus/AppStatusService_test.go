@@ -34,7 +34,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 	assert.Nil(t, err)
 	t.Run("Test-1 error in getting app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testOutputContainer := appStatus.AppStatusContainer{
 			AppId:  1,
 			EnvId:  1,
@@ -50,7 +50,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-2 error in creating app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testInputContainer := appStatus.AppStatusContainer{}
 
 		db, _ := getDbConn()
@@ -65,7 +65,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-3 success in creating app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testInputContainer := appStatus.AppStatusContainer{}
 		testOutputContainerFromDb := appStatus.AppStatusContainer{
 			AppId:  1,
@@ -84,7 +84,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-4 No change in app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testInputContainer := appStatus.AppStatusContainer{
 			AppId:  1,
 			EnvId:  1,
@@ -101,7 +101,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-5 error in updating app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testOutputContainerFromDb := appStatus.AppStatusContainer{
 			AppId:  1,
 			EnvId:  1,
@@ -125,7 +125,7 @@ func TestUpdateStatusWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-6 success in updating app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testOutputContainerFromDb := appStatus.AppStatusContainer{
 			AppId:  2,
 			EnvId:  2,
@@ -154,7 +154,7 @@ func TestDeleteWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-1 error in deleting app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testInputContainer := appStatus.AppStatusContainer{
 			AppId: 1,
 			EnvId: 1,
@@ -172,7 +172,7 @@ func TestDeleteWithAppIdEnvId(t *testing.T) {
 
 	t.Run("Test-2 success in deleting app-status", func(tt *testing.T) {
 		appStatusRepositoryMocked := mocks.NewAppStatusRepository(t)
-		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil)
+		appStatusService := NewAppStatusServiceImpl(appStatusRepositoryMocked, logger, nil, nil, nil)
 		testInputContainer := appStatus.AppStatusContainer{
 			AppId: 1,
 			EnvId: 1,
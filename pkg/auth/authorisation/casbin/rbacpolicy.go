@@ -41,6 +41,9 @@ const (
 	ResourceJobsEnv  = "jobenv"
 	ResourceWorkflow = "workflow"
 
+	// ResourceConfig is the config approval resource, its object is {team}/{env}/{app}
+	ResourceConfig = "config"
+
 	ResourceTeam    = "team"
 	ResourceAdmin   = "admin"
 	ResourceGlobal  = "global-resource"
@@ -53,6 +56,7 @@ const (
 	ActionTrigger   = "trigger"
 	ActionNotify    = "notify"
 	ActionExec      = "exec"
+	ActionApprove   = "approve"
 
 	ClusterResourceRegex         = "%s/%s"    // {cluster}/{namespace}
 	ClusterObjectRegex           = "%s/%s/%s" // {groupName}/{kindName}/{objectName}
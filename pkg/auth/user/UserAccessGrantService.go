@@ -0,0 +1,655 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	casbin2 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	bean4 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	repositoryBean "github.com/devtron-labs/devtron/pkg/auth/user/repository/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type UserAccessGrantService interface {
+	// CreateGrant requests the role filters for the user or the role group, the grant is active only once approved
+	CreateGrant(request *userBean.UserAccessGrantRequest) (*userBean.UserAccessGrant, error)
+	ApproveGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error)
+	RejectGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error)
+	// RevokeGrant ends the grant before its end time, removing the roles if it is active
+	RevokeGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error)
+	GetGrantById(grantId int) (*userBean.UserAccessGrant, error)
+	ListGrants(filter *userBean.UserAccessGrantFilter) ([]*userBean.UserAccessGrant, error)
+	// ProcessGrants activates the approved grants whose start time has come and expires the grants whose end time has
+	// passed, run by the access grant cron
+	ProcessGrants()
+}
+
+type UserAccessGrantServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	config                    *userBean.UserAccessGrantConfig
+	userAccessGrantRepository repository.UserAccessGrantRepository
+	userAuthRepository        repository.UserAuthRepository
+	userRepository            repository.UserRepository
+	roleGroupRepository       repository.RoleGroupRepository
+	userCommonService         UserCommonService
+	userAuditService          UserAuditService
+	enforcer                  casbin2.Enforcer
+	cron                      *cron.Cron
+}
+
+func NewUserAccessGrantServiceImpl(logger *zap.SugaredLogger,
+	config *userBean.UserAccessGrantConfig,
+	userAccessGrantRepository repository.UserAccessGrantRepository,
+	userAuthRepository repository.UserAuthRepository,
+	userRepository repository.UserRepository,
+	roleGroupRepository repository.RoleGroupRepository,
+	userCommonService UserCommonService,
+	userAuditService UserAuditService,
+	enforcer casbin2.Enforcer,
+	cronLogger *cron2.CronLoggerImpl) (*UserAccessGrantServiceImpl, error) {
+	impl := &UserAccessGrantServiceImpl{
+		logger:                    logger,
+		config:                    config,
+		userAccessGrantRepository: userAccessGrantRepository,
+		userAuthRepository:        userAuthRepository,
+		userRepository:            userRepository,
+		roleGroupRepository:       roleGroupRepository,
+		userCommonService:         userCommonService,
+		userAuditService:          userAuditService,
+		enforcer:                  enforcer,
+	}
+	impl.cron = cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	_, err := impl.cron.AddFunc(config.CronTime, impl.ProcessGrants)
+	if err != nil {
+		logger.Errorw("error in adding access grant cron", "cronTime", config.CronTime, "err", err)
+		return nil, err
+	}
+	impl.cron.Start()
+	return impl, nil
+}
+
+// grantSubject is the casbin subject the roles of a grant are added to
+type grantSubject struct {
+	casbinName string
+	// isRoleGroup is set when the subject is the casbin name of a role group, the enforcer cache of its members is
+	// invalidated on every change of its roles
+	isRoleGroup bool
+}
+
+func (impl *UserAccessGrantServiceImpl) CreateGrant(request *userBean.UserAccessGrantRequest) (*userBean.UserAccessGrant, error) {
+	err := userHelper.ValidateRoleFilters(request.RoleFilters)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	startTime := now
+	if request.StartTime != nil && request.StartTime.After(now) {
+		startTime = *request.StartTime
+	}
+	problems := validateGrantWindow(startTime, request.EndTime, now, time.Duration(impl.config.MaxDurationInMins)*time.Minute)
+	subjectType := userBean.AccessGrantSubjectUser
+	if request.RoleGroupId > 0 {
+		subjectType = userBean.AccessGrantSubjectRoleGroup
+	}
+	if (request.UserId > 0) == (request.RoleGroupId > 0) {
+		problems = append(problems, "exactly one of userId and roleGroupId is required")
+	} else {
+		subjectProblem, err := impl.validateSubject(subjectType, request.UserId, request.RoleGroupId)
+		if err != nil {
+			return nil, err
+		}
+		if len(subjectProblem) > 0 {
+			problems = append(problems, subjectProblem)
+		}
+	}
+	if len(problems) > 0 {
+		userMsg := fmt.Sprintf("invalid access grant request: %s", strings.Join(problems, "; "))
+		return nil, util.NewApiError(http.StatusBadRequest, userMsg, userMsg)
+	}
+	roleFilters, err := json.Marshal(request.RoleFilters)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling role filters", "roleFilters", request.RoleFilters, "err", err)
+		return nil, err
+	}
+	grant := &repository.UserAccessGrant{
+		SubjectType: subjectType,
+		UserId:      request.UserId,
+		RoleGroupId: request.RoleGroupId,
+		RoleFilters: string(roleFilters),
+		StartTime:   startTime,
+		EndTime:     request.EndTime,
+		Reason:      request.Reason,
+		Status:      userBean.AccessGrantStatusPendingApproval,
+		RequestedBy: request.RequestedBy,
+		AuditLog:    sql.NewDefaultAuditLog(request.RequestedBy),
+	}
+	err = impl.userAccessGrantRepository.Save(grant)
+	if err != nil {
+		impl.logger.Errorw("error in saving access grant", "request", request, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(grant.Id, userBean.AccessGrantAuditRequested, request.Reason, request.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGrantById(grant.Id)
+}
+
+// validateGrantWindow returns the problems of the time window of a grant, the start time is never before now
+func validateGrantWindow(startTime, endTime, now time.Time, maxDuration time.Duration) []string {
+	var problems []string
+	if !endTime.After(now) {
+		problems = append(problems, "endTime must be in the future")
+	}
+	if !endTime.After(startTime) {
+		problems = append(problems, "endTime must be after startTime")
+	} else if endTime.Sub(startTime) > maxDuration {
+		problems = append(problems, fmt.Sprintf("access can be granted for at most %s", maxDuration))
+	}
+	return problems
+}
+
+// validateSubject returns the problem with the user or the role group of the request, empty if it exists
+func (impl *UserAccessGrantServiceImpl) validateSubject(subjectType userBean.AccessGrantSubjectType, userId, roleGroupId int32) (string, error) {
+	if subjectType == userBean.AccessGrantSubjectUser {
+		user, err := impl.userRepository.GetById(userId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching user", "userId", userId, "err", err)
+			return "", err
+		}
+		if util.IsErrNoRows(err) {
+			return fmt.Sprintf("active user %d not found", userId), nil
+		}
+		if userHelper.IsSystemOrAdminUserByEmail(user.EmailId) {
+			return "access cannot be granted to system users", nil
+		}
+		return "", nil
+	}
+	_, err := impl.roleGroupRepository.GetRoleGroupById(roleGroupId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching role group", "roleGroupId", roleGroupId, "err", err)
+		return "", err
+	}
+	if util.IsErrNoRows(err) {
+		return fmt.Sprintf("role group %d not found", roleGroupId), nil
+	}
+	return "", nil
+}
+
+func (impl *UserAccessGrantServiceImpl) ApproveGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error) {
+	grant, err := impl.getGrantForAction(grantId, userBean.AccessGrantStatusPendingApproval)
+	if err != nil {
+		return nil, err
+	}
+	if grant.RequestedBy == request.UserId {
+		return nil, util.NewApiError(http.StatusForbidden, "access grant cannot be approved by the requester", "access grant cannot be approved by the requester")
+	}
+	now := time.Now()
+	if !grant.EndTime.After(now) {
+		return nil, util.NewApiError(http.StatusBadRequest, "access grant has already ended", "access grant has already ended")
+	}
+	grant.Status = userBean.AccessGrantStatusApproved
+	grant.ReviewedBy = request.UserId
+	grant.ReviewComment = request.Comment
+	grant.UpdateAuditLog(request.UserId)
+	if !grant.StartTime.After(now) {
+		grant.StartTime = now
+		err = impl.activateGrant(grant, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = impl.userAccessGrantRepository.Update(grant)
+		if err != nil {
+			impl.logger.Errorw("error in updating access grant", "grantId", grantId, "err", err)
+			return nil, err
+		}
+	}
+	err = impl.saveAudit(grant.Id, userBean.AccessGrantAuditApproved, request.Comment, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status == userBean.AccessGrantStatusActive {
+		err = impl.saveAudit(grant.Id, userBean.AccessGrantAuditActivated, "", request.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetGrantById(grant.Id)
+}
+
+func (impl *UserAccessGrantServiceImpl) RejectGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error) {
+	grant, err := impl.getGrantForAction(grantId, userBean.AccessGrantStatusPendingApproval)
+	if err != nil {
+		return nil, err
+	}
+	grant.Status = userBean.AccessGrantStatusRejected
+	grant.ReviewedBy = request.UserId
+	grant.ReviewComment = request.Comment
+	grant.UpdateAuditLog(request.UserId)
+	err = impl.userAccessGrantRepository.Update(grant)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "grantId", grantId, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(grant.Id, userBean.AccessGrantAuditRejected, request.Comment, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGrantById(grant.Id)
+}
+
+func (impl *UserAccessGrantServiceImpl) RevokeGrant(grantId int, request *userBean.UserAccessGrantActionRequest) (*userBean.UserAccessGrant, error) {
+	grant, err := impl.getGrantForAction(grantId, userBean.AccessGrantStatusPendingApproval, userBean.AccessGrantStatusApproved, userBean.AccessGrantStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.endGrant(grant, userBean.AccessGrantStatusRevoked, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.saveAudit(grant.Id, userBean.AccessGrantAuditRevoked, request.Comment, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGrantById(grant.Id)
+}
+
+// getGrantForAction returns the grant if it is in one of the statuses, the action on the grant is rejected otherwise
+func (impl *UserAccessGrantServiceImpl) getGrantForAction(grantId int, statuses ...userBean.AccessGrantStatus) (*repository.UserAccessGrant, error) {
+	grant, err := impl.userAccessGrantRepository.FindById(grantId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access grant", "grantId", grantId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "access grant not found", "access grant not found")
+	}
+	for _, status := range statuses {
+		if grant.Status == status {
+			return grant, nil
+		}
+	}
+	userMsg := fmt.Sprintf("access grant is %s", grant.Status)
+	return nil, util.NewApiError(http.StatusConflict, userMsg, userMsg)
+}
+
+func (impl *UserAccessGrantServiceImpl) GetGrantById(grantId int) (*userBean.UserAccessGrant, error) {
+	grant, err := impl.userAccessGrantRepository.FindById(grantId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access grant", "grantId", grantId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "access grant not found", "access grant not found")
+	}
+	grantDto, err := impl.getGrantDto(grant)
+	if err != nil {
+		return nil, err
+	}
+	grantDto.Audits, err = impl.userAuditService.GetAccessGrantAudits(grant.Id)
+	if err != nil {
+		return nil, err
+	}
+	return grantDto, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) ListGrants(filter *userBean.UserAccessGrantFilter) ([]*userBean.UserAccessGrant, error) {
+	grants, err := impl.userAccessGrantRepository.FindByFilter(filter)
+	if err != nil {
+		impl.logger.Errorw("error in fetching access grants", "filter", filter, "err", err)
+		return nil, err
+	}
+	grantDtos := make([]*userBean.UserAccessGrant, 0, len(grants))
+	for _, grant := range grants {
+		grantDto, err := impl.getGrantDto(grant)
+		if err != nil {
+			return nil, err
+		}
+		grantDtos = append(grantDtos, grantDto)
+	}
+	return grantDtos, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) getGrantDto(grant *repository.UserAccessGrant) (*userBean.UserAccessGrant, error) {
+	grantDto := &userBean.UserAccessGrant{
+		Id:            grant.Id,
+		SubjectType:   grant.SubjectType,
+		UserId:        grant.UserId,
+		RoleGroupId:   grant.RoleGroupId,
+		StartTime:     grant.StartTime,
+		EndTime:       grant.EndTime,
+		Reason:        grant.Reason,
+		Status:        grant.Status,
+		RequestedBy:   grant.RequestedBy,
+		ReviewedBy:    grant.ReviewedBy,
+		ReviewComment: grant.ReviewComment,
+	}
+	err := json.Unmarshal([]byte(grant.RoleFilters), &grantDto.RoleFilters)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling role filters of access grant", "grantId", grant.Id, "err", err)
+		return nil, err
+	}
+	if grant.SubjectType == userBean.AccessGrantSubjectUser {
+		user, err := impl.userRepository.GetByIdIncludeDeleted(grant.UserId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching user", "userId", grant.UserId, "err", err)
+			return nil, err
+		}
+		grantDto.EmailId = user.EmailId
+	} else {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(grant.RoleGroupId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching role group", "roleGroupId", grant.RoleGroupId, "err", err)
+			return nil, err
+		}
+		grantDto.RoleGroupName = roleGroup.Name
+	}
+	return grantDto, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) ProcessGrants() {
+	now := time.Now()
+	expiredGrants, err := impl.userAccessGrantRepository.FindByStatusesAndEndTimeBefore([]userBean.AccessGrantStatus{
+		userBean.AccessGrantStatusPendingApproval, userBean.AccessGrantStatusApproved, userBean.AccessGrantStatusActive}, now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expired access grants", "err", err)
+		return
+	}
+	for _, grant := range expiredGrants {
+		err = impl.endGrant(grant, userBean.AccessGrantStatusExpired, userBean.SYSTEM_USER_ID)
+		if err != nil {
+			impl.logger.Errorw("error in expiring access grant", "grantId", grant.Id, "err", err)
+			continue
+		}
+		_ = impl.saveAudit(grant.Id, userBean.AccessGrantAuditExpired, "", userBean.SYSTEM_USER_ID)
+	}
+	grantsToActivate, err := impl.userAccessGrantRepository.FindByStatusAndStartTimeBefore(userBean.AccessGrantStatusApproved, now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approved access grants", "err", err)
+		return
+	}
+	for _, grant := range grantsToActivate {
+		err = impl.activateGrant(grant, userBean.SYSTEM_USER_ID)
+		if err != nil {
+			impl.logger.Errorw("error in activating access grant", "grantId", grant.Id, "err", err)
+			continue
+		}
+		_ = impl.saveAudit(grant.Id, userBean.AccessGrantAuditActivated, "", userBean.SYSTEM_USER_ID)
+	}
+}
+
+// activateGrant saves the grant active along with the roles of its role filters and then adds the roles to its subject.
+// The grant is saved first so that the roles added are always known to endGrant, which removes them on expiry.
+func (impl *UserAccessGrantServiceImpl) activateGrant(grant *repository.UserAccessGrant, userId int32) error {
+	subject, err := impl.getGrantSubject(grant)
+	if err != nil {
+		return err
+	}
+	var roleFilters []userBean.RoleFilter
+	err = json.Unmarshal([]byte(grant.RoleFilters), &roleFilters)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling role filters of access grant", "grantId", grant.Id, "err", err)
+		return err
+	}
+	var policies []bean4.Policy
+	var roles []string
+	for _, roleFilter := range roleFilters {
+		for _, roleFieldsDto := range getRoleFieldsDtos(roleFilter) {
+			roleModel, err := impl.userAuthRepository.GetRoleByFilterForAllTypes(roleFieldsDto)
+			if err != nil {
+				impl.logger.Errorw("error in getting role by filter", "grantId", grant.Id, "roleFilter", roleFilter, "err", err)
+				return err
+			}
+			if roleModel.Id == 0 {
+				flag, err, policiesAdded := impl.userCommonService.CreateDefaultPoliciesForAllTypes(roleFieldsDto, grant.RequestedBy)
+				if err != nil || !flag {
+					impl.logger.Errorw("error in creating default policies", "grantId", grant.Id, "roleFilter", roleFilter, "err", err)
+					return err
+				}
+				policies = append(policies, policiesAdded...)
+				roleModel, err = impl.userAuthRepository.GetRoleByFilterForAllTypes(roleFieldsDto)
+				if err != nil {
+					impl.logger.Errorw("error in getting role by filter", "grantId", grant.Id, "roleFilter", roleFilter, "err", err)
+					return err
+				}
+				if roleModel.Id == 0 {
+					continue
+				}
+			}
+			roles = append(roles, roleModel.Role)
+			policies = append(policies, adapter.GetCasbinGroupPolicyForEmailAndRoleOnly(subject.casbinName, roleModel.Role))
+		}
+	}
+	rolesJson, err := json.Marshal(roles)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling roles of access grant", "grantId", grant.Id, "err", err)
+		return err
+	}
+	grant.Roles = string(rolesJson)
+	grant.Status = userBean.AccessGrantStatusActive
+	grant.UpdateAuditLog(userId)
+	err = impl.userAccessGrantRepository.Update(grant)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "grantId", grant.Id, "err", err)
+		return err
+	}
+	casbin2.AddPolicy(policies)
+	impl.invalidateCache(subject)
+	return nil
+}
+
+// endGrant moves the grant to the terminal status and removes the roles it added, if any. Roles are removed whatever
+// the status of the grant, as they are saved before being added to the subject.
+func (impl *UserAccessGrantServiceImpl) endGrant(grant *repository.UserAccessGrant, status userBean.AccessGrantStatus, userId int32) error {
+	if len(grant.Roles) > 0 {
+		err := impl.removeGrantRoles(grant)
+		if err != nil {
+			return err
+		}
+	}
+	grant.Status = status
+	grant.UpdateAuditLog(userId)
+	err := impl.userAccessGrantRepository.Update(grant)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "grantId", grant.Id, "status", status, "err", err)
+		return err
+	}
+	return nil
+}
+
+// removeGrantRoles removes the roles of the grant from its subject, except the ones the subject holds permanently or
+// through another active grant
+func (impl *UserAccessGrantServiceImpl) removeGrantRoles(grant *repository.UserAccessGrant) error {
+	subject, err := impl.getGrantSubject(grant)
+	if err != nil {
+		return err
+	}
+	var grantRoles []string
+	if len(grant.Roles) > 0 {
+		err = json.Unmarshal([]byte(grant.Roles), &grantRoles)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling roles of access grant", "grantId", grant.Id, "err", err)
+			return err
+		}
+	}
+	retainedRoles, err := impl.getRetainedRoles(grant)
+	if err != nil {
+		return err
+	}
+	var policies []bean4.Policy
+	for _, role := range getRolesToRemove(grantRoles, retainedRoles) {
+		policies = append(policies, adapter.GetCasbinGroupPolicyForEmailAndRoleOnly(subject.casbinName, role))
+	}
+	if len(policies) > 0 {
+		casbin2.RemovePolicy(policies)
+		impl.invalidateCache(subject)
+	}
+	return nil
+}
+
+// getRetainedRoles returns the roles of the subject of the grant which are not granted by it alone
+func (impl *UserAccessGrantServiceImpl) getRetainedRoles(grant *repository.UserAccessGrant) (map[string]bool, error) {
+	var permanentRoles []*repository.RoleModel
+	var err error
+	subjectId := grant.UserId
+	if grant.SubjectType == userBean.AccessGrantSubjectUser {
+		permanentRoles, err = impl.userAuthRepository.GetRolesByUserId(grant.UserId)
+	} else {
+		subjectId = grant.RoleGroupId
+		permanentRoles, err = impl.userAuthRepository.GetRolesByGroupId(grant.RoleGroupId)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching roles of access grant subject", "grantId", grant.Id, "err", err)
+		return nil, err
+	}
+	retainedRoles := make(map[string]bool, len(permanentRoles))
+	for _, role := range permanentRoles {
+		retainedRoles[role.Role] = true
+	}
+	activeGrants, err := impl.userAccessGrantRepository.FindActiveBySubject(grant.SubjectType, subjectId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching active access grants of subject", "grantId", grant.Id, "err", err)
+		return nil, err
+	}
+	for _, activeGrant := range activeGrants {
+		if activeGrant.Id == grant.Id || len(activeGrant.Roles) == 0 {
+			continue
+		}
+		var roles []string
+		err = json.Unmarshal([]byte(activeGrant.Roles), &roles)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling roles of access grant", "grantId", activeGrant.Id, "err", err)
+			return nil, err
+		}
+		for _, role := range roles {
+			retainedRoles[role] = true
+		}
+	}
+	return retainedRoles, nil
+}
+
+func getRolesToRemove(grantRoles []string, retainedRoles map[string]bool) []string {
+	var rolesToRemove []string
+	for _, role := range grantRoles {
+		if !retainedRoles[role] {
+			rolesToRemove = append(rolesToRemove, role)
+		}
+	}
+	return rolesToRemove
+}
+
+func (impl *UserAccessGrantServiceImpl) getGrantSubject(grant *repository.UserAccessGrant) (*grantSubject, error) {
+	if grant.SubjectType == userBean.AccessGrantSubjectUser {
+		user, err := impl.userRepository.GetByIdIncludeDeleted(grant.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching user", "userId", grant.UserId, "err", err)
+			return nil, err
+		}
+		return &grantSubject{casbinName: user.EmailId}, nil
+	}
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(grant.RoleGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching role group", "roleGroupId", grant.RoleGroupId, "err", err)
+		return nil, err
+	}
+	return &grantSubject{casbinName: roleGroup.CasbinName, isRoleGroup: true}, nil
+}
+
+// invalidateCache invalidates the enforcer cache of the members of a role group subject, the cache of a user subject is
+// invalidated by the casbin policy update itself
+func (impl *UserAccessGrantServiceImpl) invalidateCache(subject *grantSubject) {
+	if !subject.isRoleGroup {
+		return
+	}
+	emailIds, err := casbin2.GetUserByRole(subject.casbinName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users of role group, invalidating complete cache", "roleGroup", subject.casbinName, "err", err)
+		impl.enforcer.InvalidateCompleteCache()
+		return
+	}
+	for _, emailId := range emailIds {
+		impl.enforcer.InvalidateCache(emailId)
+	}
+}
+
+func (impl *UserAccessGrantServiceImpl) saveAudit(grantId int, action userBean.AccessGrantAuditAction, comment string, userId int32) error {
+	return impl.userAuditService.SaveAccessGrantAudit(&userBean.UserAccessGrantAudit{
+		GrantId:  grantId,
+		Action:   action,
+		Comment:  comment,
+		ActionBy: userId,
+		ActionOn: time.Now(),
+	})
+}
+
+// getRoleFieldsDtos expands the role filter into the role of every entity, environment and action it selects, the
+// same way the roles of a user are created from it
+func getRoleFieldsDtos(roleFilter userBean.RoleFilter) []*repositoryBean.RoleModelFieldsDto {
+	var roleFieldsDtos []*repositoryBean.RoleModelFieldsDto
+	entity := roleFilter.Entity
+	subActions := strings.Split(getSubactionFromRoleFilter(roleFilter), ",")
+	switch entity {
+	case userBean.CLUSTER_ENTITIY:
+		for _, namespace := range strings.Split(roleFilter.Namespace, ",") {
+			for _, group := range strings.Split(roleFilter.Group, ",") {
+				for _, kind := range strings.Split(roleFilter.Kind, ",") {
+					for _, resource := range strings.Split(roleFilter.Resource, ",") {
+						for _, subAction := range subActions {
+							roleFieldsDtos = append(roleFieldsDtos, adapter.BuildClusterRoleFieldsDto(entity, roleFilter.AccessType, roleFilter.Cluster, namespace, group, kind, resource, roleFilter.Action, subAction))
+						}
+					}
+				}
+			}
+		}
+	case userBean.EntityJobs:
+		for _, environment := range strings.Split(roleFilter.Environment, ",") {
+			for _, entityName := range strings.Split(roleFilter.EntityName, ",") {
+				for _, workflow := range strings.Split(roleFilter.Workflow, ",") {
+					for _, subAction := range subActions {
+						roleFieldsDtos = append(roleFieldsDtos, adapter.BuildJobsRoleFieldsDto(entity, roleFilter.Team, entityName, environment, roleFilter.Action, roleFilter.AccessType, workflow, subAction))
+					}
+				}
+			}
+		}
+	default:
+		approver := getApproverFromRoleFilter(roleFilter)
+		for _, environment := range strings.Split(roleFilter.Environment, ",") {
+			for _, entityName := range strings.Split(roleFilter.EntityName, ",") {
+				for _, action := range strings.Split(roleFilter.Action, ",") {
+					for _, subAction := range subActions {
+						roleFieldsDtos = append(roleFieldsDtos, adapter.BuildOtherRoleFieldsDto(entity, roleFilter.Team, entityName, environment, action, roleFilter.AccessType, false, subAction, approver))
+					}
+				}
+			}
+		}
+	}
+	return roleFieldsDtos
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateGrantWindow(t *testing.T) {
+	now := time.Now()
+	maxDuration := 24 * time.Hour
+	assert.Empty(t, validateGrantWindow(now, now.Add(time.Hour), now, maxDuration))
+	assert.Empty(t, validateGrantWindow(now.Add(time.Hour), now.Add(25*time.Hour), now, maxDuration), "duration is counted from the start time")
+	assert.Equal(t, []string{"access can be granted for at most 24h0m0s"}, validateGrantWindow(now, now.Add(25*time.Hour), now, maxDuration))
+	assert.Equal(t, []string{"endTime must be in the future", "endTime must be after startTime"}, validateGrantWindow(now, now.Add(-time.Minute), now, maxDuration))
+	assert.Equal(t, []string{"endTime must be after startTime"}, validateGrantWindow(now.Add(2*time.Hour), now.Add(time.Hour), now, maxDuration))
+}
+
+func TestGetRoleFieldsDtos(t *testing.T) {
+	appRoles := getRoleFieldsDtos(bean.RoleFilter{Team: "payments", EntityName: "api,worker", Environment: "prod", Action: "view,trigger", AccessType: ""})
+	assert.Len(t, appRoles, 4)
+	assert.Equal(t, "api", appRoles[0].App)
+	assert.Equal(t, "view", appRoles[0].Action)
+	assert.Equal(t, "trigger", appRoles[1].Action)
+	assert.Equal(t, "worker", appRoles[2].App)
+
+	clusterRoles := getRoleFieldsDtos(bean.RoleFilter{Entity: bean.CLUSTER_ENTITIY, Cluster: "default", Namespace: "ns1,ns2", Group: "apps", Kind: "Deployment", Resource: "", Action: "admin"})
+	assert.Len(t, clusterRoles, 2)
+	assert.Equal(t, "ns2", clusterRoles[1].Namespace)
+	assert.Equal(t, "default", clusterRoles[1].Cluster)
+
+	jobRoles := getRoleFieldsDtos(bean.RoleFilter{Entity: bean.EntityJobs, Team: "ops", EntityName: "backup", Environment: "prod,staging", Workflow: "nightly", Action: "run"})
+	assert.Len(t, jobRoles, 2)
+	assert.Equal(t, "nightly", jobRoles[0].Workflow)
+	assert.Equal(t, "staging", jobRoles[1].Env)
+}
+
+func TestGetRolesToRemove(t *testing.T) {
+	grantRoles := []string{"role:admin_payments_api_prod", "role:view_payments_api_staging"}
+	assert.Equal(t, grantRoles, getRolesToRemove(grantRoles, nil))
+	assert.Equal(t, []string{"role:view_payments_api_staging"}, getRolesToRemove(grantRoles, map[string]bool{"role:admin_payments_api_prod": true}))
+	assert.Empty(t, getRolesToRemove(nil, map[string]bool{"role:admin_payments_api_prod": true}))
+}
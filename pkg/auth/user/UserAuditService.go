@@ -19,6 +19,7 @@ package user
 import (
 	"time"
 
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -37,6 +38,8 @@ type UserAuditService interface {
 	GetLatestUser() (*UserAudit, error)
 	Update(userAudit *UserAudit) error
 	GetActiveUsersCountInLast30Days() (int, error)
+	SaveAccessGrantAudit(audit *bean.UserAccessGrantAudit) error
+	GetAccessGrantAudits(grantId int) ([]*bean.UserAccessGrantAudit, error)
 }
 
 type UserAuditServiceImpl struct {
@@ -134,3 +137,38 @@ func (impl UserAuditServiceImpl) GetActiveUsersCountInLast30Days() (int, error)
 	}
 	return count, nil
 }
+
+func (impl UserAuditServiceImpl) SaveAccessGrantAudit(audit *bean.UserAccessGrantAudit) error {
+	auditDb := &repository2.UserAccessGrantAudit{
+		GrantId:  audit.GrantId,
+		Action:   audit.Action,
+		Comment:  audit.Comment,
+		ActionBy: audit.ActionBy,
+		ActionOn: audit.ActionOn,
+	}
+	err := impl.userAuditRepository.SaveAccessGrantAudit(auditDb)
+	if err != nil {
+		impl.logger.Errorw("error while saving access grant audit", "grantId", audit.GrantId, "action", audit.Action, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (impl UserAuditServiceImpl) GetAccessGrantAudits(grantId int) ([]*bean.UserAccessGrantAudit, error) {
+	auditsDb, err := impl.userAuditRepository.GetAccessGrantAuditsByGrantId(grantId)
+	if err != nil {
+		impl.logger.Errorw("error while getting access grant audits", "grantId", grantId, "error", err)
+		return nil, err
+	}
+	audits := make([]*bean.UserAccessGrantAudit, 0, len(auditsDb))
+	for _, auditDb := range auditsDb {
+		audits = append(audits, &bean.UserAccessGrantAudit{
+			GrantId:  auditDb.GrantId,
+			Action:   auditDb.Action,
+			Comment:  auditDb.Comment,
+			ActionBy: auditDb.ActionBy,
+			ActionOn: auditDb.ActionOn,
+		})
+	}
+	return audits, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/caarlos0/env"
+	"time"
+)
+
+type AccessGrantSubjectType string
+
+const (
+	AccessGrantSubjectUser      AccessGrantSubjectType = "USER"
+	AccessGrantSubjectRoleGroup AccessGrantSubjectType = "ROLE_GROUP"
+)
+
+type AccessGrantStatus string
+
+const (
+	AccessGrantStatusPendingApproval AccessGrantStatus = "PENDING_APPROVAL"
+	// AccessGrantStatusApproved the grant is approved and its start time is yet to come
+	AccessGrantStatusApproved AccessGrantStatus = "APPROVED"
+	AccessGrantStatusActive   AccessGrantStatus = "ACTIVE"
+	AccessGrantStatusRejected AccessGrantStatus = "REJECTED"
+	AccessGrantStatusRevoked  AccessGrantStatus = "REVOKED"
+	AccessGrantStatusExpired  AccessGrantStatus = "EXPIRED"
+)
+
+func (status AccessGrantStatus) IsTerminal() bool {
+	return status == AccessGrantStatusRejected || status == AccessGrantStatusRevoked || status == AccessGrantStatusExpired
+}
+
+type AccessGrantAuditAction string
+
+const (
+	AccessGrantAuditRequested AccessGrantAuditAction = "REQUESTED"
+	AccessGrantAuditApproved  AccessGrantAuditAction = "APPROVED"
+	AccessGrantAuditRejected  AccessGrantAuditAction = "REJECTED"
+	AccessGrantAuditActivated AccessGrantAuditAction = "ACTIVATED"
+	AccessGrantAuditRevoked   AccessGrantAuditAction = "REVOKED"
+	AccessGrantAuditExpired   AccessGrantAuditAction = "EXPIRED"
+)
+
+// UserAccessGrantRequest requests the role filters for a user or a role group for a limited time. Exactly one of
+// UserId and RoleGroupId is set.
+type UserAccessGrantRequest struct {
+	UserId      int32        `json:"userId,omitempty"`
+	RoleGroupId int32        `json:"roleGroupId,omitempty"`
+	RoleFilters []RoleFilter `json:"roleFilters" validate:"required,min=1"`
+	// StartTime defaults to the time of the request, a grant approved after its start time is activated on approval
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   time.Time  `json:"endTime" validate:"required"`
+	Reason    string     `json:"reason" validate:"required,max=500"`
+	// RequestedBy is the logged in user
+	RequestedBy int32 `json:"-"`
+}
+
+type UserAccessGrant struct {
+	Id            int                     `json:"id"`
+	SubjectType   AccessGrantSubjectType  `json:"subjectType"`
+	UserId        int32                   `json:"userId,omitempty"`
+	EmailId       string                  `json:"emailId,omitempty"`
+	RoleGroupId   int32                   `json:"roleGroupId,omitempty"`
+	RoleGroupName string                  `json:"roleGroupName,omitempty"`
+	RoleFilters   []RoleFilter            `json:"roleFilters"`
+	StartTime     time.Time               `json:"startTime"`
+	EndTime       time.Time               `json:"endTime"`
+	Reason        string                  `json:"reason"`
+	Status        AccessGrantStatus       `json:"status"`
+	RequestedBy   int32                   `json:"requestedBy"`
+	ReviewedBy    int32                   `json:"reviewedBy,omitempty"`
+	ReviewComment string                  `json:"reviewComment,omitempty"`
+	Audits        []*UserAccessGrantAudit `json:"audits,omitempty"`
+}
+
+// UserAccessGrantActionRequest approves, rejects or revokes a grant
+type UserAccessGrantActionRequest struct {
+	Comment string `json:"comment" validate:"max=500"`
+	UserId  int32  `json:"-"`
+}
+
+type UserAccessGrantFilter struct {
+	UserId      int32               `schema:"userId"`
+	RoleGroupId int32               `schema:"roleGroupId"`
+	Statuses    []AccessGrantStatus `schema:"status"`
+}
+
+type UserAccessGrantAudit struct {
+	GrantId  int                    `json:"grantId"`
+	Action   AccessGrantAuditAction `json:"action"`
+	Comment  string                 `json:"comment,omitempty"`
+	ActionBy int32                  `json:"actionBy"`
+	ActionOn time.Time              `json:"actionOn"`
+}
+
+// CATEGORY=RBAC
+type UserAccessGrantConfig struct {
+	CronTime          string `env:"ACCESS_GRANT_CRON_TIME" envDefault:"@every 1m" description:"Cron schedule activating the approved time bound access grants and revoking the expired ones"`
+	MaxDurationInMins int    `env:"ACCESS_GRANT_MAX_DURATION_MINS" envDefault:"1440" description:"Maximum time (in minutes) for which a time bound access grant can be requested"`
+}
+
+func GetUserAccessGrantConfig() (*UserAccessGrantConfig, error) {
+	config := &UserAccessGrantConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
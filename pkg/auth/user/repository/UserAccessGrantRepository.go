@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type UserAccessGrant struct {
+	TableName   struct{}                    `sql:"user_access_grant" pg:",discard_unknown_columns"`
+	Id          int                         `sql:"id,pk"`
+	SubjectType bean.AccessGrantSubjectType `sql:"subject_type,notnull"`
+	UserId      int32                       `sql:"user_id"`
+	RoleGroupId int32                       `sql:"role_group_id"`
+	// RoleFilters is the json of the requested role filters
+	RoleFilters string `sql:"role_filters,notnull"`
+	// Roles is the json of the casbin roles added for the grant, set on activation
+	Roles         string                 `sql:"roles"`
+	StartTime     time.Time              `sql:"start_time,type:timestamptz"`
+	EndTime       time.Time              `sql:"end_time,type:timestamptz"`
+	Reason        string                 `sql:"reason"`
+	Status        bean.AccessGrantStatus `sql:"status,notnull"`
+	RequestedBy   int32                  `sql:"requested_by,notnull"`
+	ReviewedBy    int32                  `sql:"reviewed_by"`
+	ReviewComment string                 `sql:"review_comment"`
+	sql.AuditLog
+}
+
+type UserAccessGrantRepository interface {
+	Save(model *UserAccessGrant) error
+	Update(model *UserAccessGrant) error
+	FindById(id int) (*UserAccessGrant, error)
+	FindByFilter(filter *bean.UserAccessGrantFilter) ([]*UserAccessGrant, error)
+	FindByStatusAndStartTimeBefore(status bean.AccessGrantStatus, startTime time.Time) ([]*UserAccessGrant, error)
+	FindByStatusesAndEndTimeBefore(statuses []bean.AccessGrantStatus, endTime time.Time) ([]*UserAccessGrant, error)
+	FindActiveBySubject(subjectType bean.AccessGrantSubjectType, subjectId int32) ([]*UserAccessGrant, error)
+}
+
+type UserAccessGrantRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewUserAccessGrantRepositoryImpl(dbConnection *pg.DB) *UserAccessGrantRepositoryImpl {
+	return &UserAccessGrantRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl UserAccessGrantRepositoryImpl) Save(model *UserAccessGrant) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl UserAccessGrantRepositoryImpl) Update(model *UserAccessGrant) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindById(id int) (*UserAccessGrant, error) {
+	model := &UserAccessGrant{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Select()
+	return model, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindByFilter(filter *bean.UserAccessGrantFilter) ([]*UserAccessGrant, error) {
+	var models []*UserAccessGrant
+	query := impl.dbConnection.Model(&models)
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.RoleGroupId > 0 {
+		query = query.Where("role_group_id = ?", filter.RoleGroupId)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status in (?)", pg.In(filter.Statuses))
+	}
+	err := query.Order("id desc").Select()
+	return models, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindByStatusAndStartTimeBefore(status bean.AccessGrantStatus, startTime time.Time) ([]*UserAccessGrant, error) {
+	var models []*UserAccessGrant
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Where("start_time <= ?", startTime).
+		Select()
+	return models, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindByStatusesAndEndTimeBefore(statuses []bean.AccessGrantStatus, endTime time.Time) ([]*UserAccessGrant, error) {
+	var models []*UserAccessGrant
+	err := impl.dbConnection.Model(&models).
+		Where("status in (?)", pg.In(statuses)).
+		Where("end_time <= ?", endTime).
+		Select()
+	return models, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindActiveBySubject(subjectType bean.AccessGrantSubjectType, subjectId int32) ([]*UserAccessGrant, error) {
+	var models []*UserAccessGrant
+	query := impl.dbConnection.Model(&models).
+		Where("subject_type = ?", subjectType).
+		Where("status = ?", bean.AccessGrantStatusActive)
+	if subjectType == bean.AccessGrantSubjectUser {
+		query = query.Where("user_id = ?", subjectId)
+	} else {
+		query = query.Where("role_group_id = ?", subjectId)
+	}
+	err := query.Select()
+	return models, err
+}
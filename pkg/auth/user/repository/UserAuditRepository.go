@@ -22,6 +22,7 @@ package repository
 import (
 	"time"
 
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/go-pg/pg"
 )
 
@@ -34,12 +35,25 @@ type UserAudit struct {
 	UpdatedOn time.Time `sql:"updated_on,type:timestamptz"`
 }
 
+// UserAccessGrantAudit records every state change of a time bound access grant
+type UserAccessGrantAudit struct {
+	TableName struct{}                    `sql:"user_access_grant_audit"`
+	Id        int                         `sql:"id,pk"`
+	GrantId   int                         `sql:"grant_id,notnull"`
+	Action    bean.AccessGrantAuditAction `sql:"action,notnull"`
+	Comment   string                      `sql:"comment"`
+	ActionBy  int32                       `sql:"action_by,notnull"`
+	ActionOn  time.Time                   `sql:"action_on,type:timestamptz"`
+}
+
 type UserAuditRepository interface {
 	Save(userAudit *UserAudit) error
 	GetLatestByUserId(userId int32) (*UserAudit, error)
 	GetLatestUser() (*UserAudit, error)
 	Update(userAudit *UserAudit) error
 	GetActiveUsersCountInLast30Days() (int, error)
+	SaveAccessGrantAudit(audit *UserAccessGrantAudit) error
+	GetAccessGrantAuditsByGrantId(grantId int) ([]*UserAccessGrantAudit, error)
 }
 
 type UserAuditRepositoryImpl struct {
@@ -100,3 +114,16 @@ func (impl UserAuditRepositoryImpl) GetActiveUsersCountInLast30Days() (int, erro
 
 	return count, err
 }
+
+func (impl UserAuditRepositoryImpl) SaveAccessGrantAudit(audit *UserAccessGrantAudit) error {
+	return impl.dbConnection.Insert(audit)
+}
+
+func (impl UserAuditRepositoryImpl) GetAccessGrantAuditsByGrantId(grantId int) ([]*UserAccessGrantAudit, error) {
+	var audits []*UserAccessGrantAudit
+	err := impl.dbConnection.Model(&audits).
+		Where("grant_id = ?", grantId).
+		Order("id asc").
+		Select()
+	return audits, err
+}
@@ -31,6 +31,8 @@ import (
 	CiPipeline2 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	common2 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
 	"github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	autoRollbackBean "github.com/devtron-labs/devtron/pkg/deployment/autoRollback/bean"
+	canaryBean "github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -63,6 +65,66 @@ type CreateAppDTO struct {
 	GenericNote *bean2.GenericNoteResponseBean `json:"genericNote,omitempty"`
 	AppType     helper.AppType                 `json:"appType" validate:"gt=-1,lt=3"` //TODO: Change Validation if new AppType is introduced
 	DisplayName string                         `json:"-"`                             //not exposed to UI
+	// CloneOptions customise the clone of the template app, it is ignored if TemplateId is not set
+	CloneOptions *CloneOptions `json:"cloneOptions,omitempty"`
+}
+
+type CloneMode string
+
+const (
+	// CloneModeFull clones the build and the deployment configuration of the template app
+	CloneModeFull CloneMode = "FULL"
+	// CloneModeCiOnly clones the git materials, the build configuration and the ci pipelines only. The deployment
+	// template, config maps, secrets and cd pipelines are left out. Jobs are always cloned fully.
+	CloneModeCiOnly CloneMode = "CI_ONLY"
+)
+
+// CloneOptions remap the instance level resources used by the template app, so that the clone can target other
+// environments, registries and git repositories than the template. Resources without a mapping are used as they are.
+// The template app and the mapped resources are of the same instance, cloning across instances is not supported.
+type CloneOptions struct {
+	Mode CloneMode `json:"mode,omitempty" validate:"omitempty,oneof=FULL CI_ONLY"`
+	// Workflows are the names of the template workflows to clone, all are cloned if empty. The environment level
+	// configuration is cloned for the environments deployed by the selected workflows only.
+	Workflows              []string                 `json:"workflows,omitempty"`
+	EnvironmentMappings    []*EnvironmentMapping    `json:"environmentMappings,omitempty" validate:"dive"`
+	DockerRegistryMappings []*DockerRegistryMapping `json:"dockerRegistryMappings,omitempty" validate:"dive"`
+	GitProviderMappings    []*GitProviderMapping    `json:"gitProviderMappings,omitempty" validate:"dive"`
+	// GitUrlRewrites replace the prefix of the git material urls, the first matching rewrite is applied
+	GitUrlRewrites []*GitUrlRewrite `json:"gitUrlRewrites,omitempty" validate:"dive"`
+	// BranchRewrites rename the fixed branches of the ci pipelines
+	BranchRewrites []*BranchRewrite `json:"branchRewrites,omitempty" validate:"dive"`
+}
+
+func (options *CloneOptions) IsCiOnly() bool {
+	return options != nil && options.Mode == CloneModeCiOnly
+}
+
+// EnvironmentMapping clones the configuration and pipelines of the template environment into the target environment,
+// a target environment can not be the target of another mapping nor a template environment cloned without a mapping
+type EnvironmentMapping struct {
+	SourceEnvironmentId int `json:"sourceEnvironmentId" validate:"required,gt=0"`
+	TargetEnvironmentId int `json:"targetEnvironmentId" validate:"required,gt=0"`
+}
+
+type DockerRegistryMapping struct {
+	SourceRegistry string `json:"sourceRegistry" validate:"required"`
+	TargetRegistry string `json:"targetRegistry" validate:"required"`
+}
+
+type GitProviderMapping struct {
+	SourceGitProviderId int `json:"sourceGitProviderId" validate:"required,gt=0"`
+	TargetGitProviderId int `json:"targetGitProviderId" validate:"required,gt=0"`
+}
+
+type GitUrlRewrite struct {
+	SourcePrefix string `json:"sourcePrefix" validate:"required"`
+	TargetPrefix string `json:"targetPrefix" validate:"required"`
+}
+
+type BranchRewrite struct {
+	SourceBranch string `json:"sourceBranch" validate:"required"`
+	TargetBranch string `json:"targetBranch" validate:"required"`
 }
 
 type WorkflowCacheConfig struct {
@@ -151,6 +213,8 @@ type CiPipeline struct {
 	CustomTagObject          *CustomTagData         `json:"customTag,omitempty"`
 	DefaultTag               []string               `json:"defaultTag,omitempty"`
 	EnableCustomTag          bool                   `json:"enableCustomTag"`
+	// Schedules are left unchanged on update if not sent, an empty list removes all the schedules
+	Schedules []*CiPipeline2.CiPipelineSchedule `json:"schedules,omitempty" validate:"omitempty,dive"`
 }
 
 func (ciPipeline *CiPipeline) IsLinkedCi() bool {
@@ -655,6 +719,10 @@ type CDPipelineConfigObject struct {
 	ApplicationObjectNamespace    string                                 `json:"applicationObjectNamespace"` //ACDAppNamespace
 	DeploymentAppName             string                                 `json:"deploymentAppName"`
 	ReleaseMode                   string                                 `json:"releaseMode" validate:"omitempty,oneof=link create"`
+	// AutoRollbackConfig is left unchanged on update if not sent
+	AutoRollbackConfig *autoRollbackBean.AutoRollbackConfig `json:"autoRollbackConfig,omitempty"`
+	// CanaryAnalysisConfig is applied to the deployments with CANARY strategy, it is left unchanged on update if not sent
+	CanaryAnalysisConfig *canaryBean.CanaryAnalysisConfig `json:"canaryAnalysisConfig,omitempty"`
 }
 
 func (cdPipelineConfig *CDPipelineConfigObject) IsLinkedRelease() bool {
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+// CiPipelineSchedule is a cron schedule on which the ci pipeline is built
+type CiPipelineSchedule struct {
+	Id                int                           `json:"id"`
+	Name              string                        `json:"name" validate:"required,max=100"`
+	CronExpression    string                        `json:"cronExpression" validate:"required"`
+	Timezone          string                        `json:"timezone"`
+	SkipIfNoNewCommit bool                          `json:"skipIfNoNewCommit"`
+	Active            bool                          `json:"active"`
+	Materials         []*CiPipelineScheduleMaterial `json:"materials,omitempty" validate:"dive"`
+	NextRunAt         *time.Time                    `json:"nextRunAt,omitempty"`
+	LastRun           *CiPipelineScheduleRun        `json:"lastRun,omitempty"`
+}
+
+// CiPipelineScheduleMaterial selects the branch built by the schedule for a git material,
+// materials without an entry build the branch configured in the pipeline
+type CiPipelineScheduleMaterial struct {
+	GitMaterialId int    `json:"gitMaterialId" validate:"required,gt=0"`
+	BranchName    string `json:"branchName" validate:"required,max=250"`
+}
+
+type CiPipelineScheduleRun struct {
+	RunAt        time.Time `json:"runAt"`
+	Status       string    `json:"status"`
+	Message      string    `json:"message,omitempty"`
+	CiWorkflowId int       `json:"ciWorkflowId,omitempty"`
+}
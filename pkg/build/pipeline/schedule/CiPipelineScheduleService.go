@@ -0,0 +1,395 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule/helper"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type CiPipelineScheduleService interface {
+	// SaveSchedules replaces the schedules of the ci pipeline with the given schedules,
+	// schedules are matched by id and the ones not present in the request are deleted
+	SaveSchedules(ciPipelineId int, schedules []*bean.CiPipelineSchedule, userId int32) ([]*bean.CiPipelineSchedule, error)
+	DeleteSchedules(ciPipelineId int, userId int32) error
+	// ValidateScheduleExpressions validates the schedules without looking up the ci pipeline, so that
+	// invalid schedules can be rejected before the ci pipeline is created
+	ValidateScheduleExpressions(schedules []*bean.CiPipelineSchedule) error
+	GetSchedules(ciPipelineId int) ([]*bean.CiPipelineSchedule, error)
+	GetSchedulesByCiPipelineIds(ciPipelineIds []int) (map[int][]*bean.CiPipelineSchedule, error)
+
+	// GetDueSchedules returns active schedules whose next run is at or before the given time, along with
+	// the branch overrides of each schedule mapped by schedule id
+	GetDueSchedules(at time.Time) ([]*repository.CiPipelineSchedule, map[int][]*repository.CiPipelineScheduleMaterial, error)
+	// ClaimRun moves the schedule to its next run after the given time, returns false if the run is already claimed
+	ClaimRun(schedule *repository.CiPipelineSchedule, at time.Time) (bool, error)
+	// RecordRun saves the outcome of a schedule run, builtCommits are saved only for triggered runs
+	RecordRun(schedule *repository.CiPipelineSchedule, status repository.ScheduleRunStatus, message string, ciWorkflowId int, builtCommits map[int]string) error
+}
+
+type CiPipelineScheduleServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	ciPipelineScheduleRepository repository.CiPipelineScheduleRepository
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository
+	transactionManager           sql.TransactionWrapper
+}
+
+func NewCiPipelineScheduleServiceImpl(logger *zap.SugaredLogger,
+	ciPipelineScheduleRepository repository.CiPipelineScheduleRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	transactionManager sql.TransactionWrapper) *CiPipelineScheduleServiceImpl {
+	return &CiPipelineScheduleServiceImpl{
+		logger:                       logger,
+		ciPipelineScheduleRepository: ciPipelineScheduleRepository,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciPipelineMaterialRepository: ciPipelineMaterialRepository,
+		transactionManager:           transactionManager,
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) SaveSchedules(ciPipelineId int, schedules []*bean.CiPipelineSchedule, userId int32) ([]*bean.CiPipelineSchedule, error) {
+	existingSchedules, err := impl.ciPipelineScheduleRepository.FindByCiPipelineIds([]int{ciPipelineId})
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedules", "ciPipelineId", ciPipelineId, "err", err)
+		return nil, err
+	}
+	if len(schedules) > 0 {
+		err = impl.validateSchedules(ciPipelineId, schedules)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(existingSchedules) == 0 {
+		return schedules, nil
+	}
+	existingScheduleMap := make(map[int]*repository.CiPipelineSchedule, len(existingSchedules))
+	for _, existingSchedule := range existingSchedules {
+		existingScheduleMap[existingSchedule.Id] = existingSchedule
+	}
+
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	now := time.Now()
+	retainedScheduleIds := make(map[int]bool)
+	savedSchedules := make([]*repository.CiPipelineSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		model, ok := existingScheduleMap[schedule.Id]
+		if schedule.Id > 0 && !ok {
+			errMsg := fmt.Sprintf("schedule %d does not belong to ci pipeline %d", schedule.Id, ciPipelineId)
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		if !ok {
+			model = &repository.CiPipelineSchedule{
+				CiPipelineId: ciPipelineId,
+				AuditLog:     sql.NewDefaultAuditLog(userId),
+			}
+		}
+		model.Name = schedule.Name
+		model.CronExpression = schedule.CronExpression
+		model.Timezone = schedule.Timezone
+		model.SkipIfNoNewCommit = schedule.SkipIfNoNewCommit
+		model.Active = schedule.Active
+		// next run is computed afresh as schedule expression or timezone could have changed
+		model.NextRunAt, err = helper.GetNextRunAt(model.CronExpression, model.Timezone, now)
+		if err != nil {
+			impl.logger.Errorw("error in computing next run of schedule", "schedule", schedule, "err", err)
+			return nil, err
+		}
+		model.UpdateAuditLog(userId)
+		if model.Id > 0 {
+			retainedScheduleIds[model.Id] = true
+			err = impl.ciPipelineScheduleRepository.Update(tx, model)
+		} else {
+			err = impl.ciPipelineScheduleRepository.Save(tx, model)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in saving ci pipeline schedule", "schedule", model, "err", err)
+			return nil, err
+		}
+		savedSchedules = append(savedSchedules, model)
+	}
+	deletedScheduleIds := make([]int, 0)
+	for _, existingSchedule := range existingSchedules {
+		if retainedScheduleIds[existingSchedule.Id] {
+			continue
+		}
+		existingSchedule.Deleted = true
+		existingSchedule.Active = false
+		existingSchedule.UpdateAuditLog(userId)
+		err = impl.ciPipelineScheduleRepository.Update(tx, existingSchedule)
+		if err != nil {
+			impl.logger.Errorw("error in deleting ci pipeline schedule", "scheduleId", existingSchedule.Id, "err", err)
+			return nil, err
+		}
+		deletedScheduleIds = append(deletedScheduleIds, existingSchedule.Id)
+	}
+
+	// branch overrides of retained schedules are replaced along with those of deleted schedules
+	staleMaterialScheduleIds := deletedScheduleIds
+	for scheduleId := range retainedScheduleIds {
+		staleMaterialScheduleIds = append(staleMaterialScheduleIds, scheduleId)
+	}
+	err = impl.ciPipelineScheduleRepository.DeleteMaterialsByScheduleIds(tx, staleMaterialScheduleIds)
+	if err != nil {
+		impl.logger.Errorw("error in deleting ci pipeline schedule materials", "scheduleIds", staleMaterialScheduleIds, "err", err)
+		return nil, err
+	}
+	materials := make([]*repository.CiPipelineScheduleMaterial, 0)
+	for i, schedule := range schedules {
+		for _, material := range schedule.Materials {
+			materials = append(materials, &repository.CiPipelineScheduleMaterial{
+				CiPipelineScheduleId: savedSchedules[i].Id,
+				GitMaterialId:        material.GitMaterialId,
+				BranchName:           strings.TrimSpace(material.BranchName),
+				AuditLog:             sql.NewDefaultAuditLog(userId),
+			})
+		}
+	}
+	err = impl.ciPipelineScheduleRepository.SaveMaterials(tx, materials)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule materials", "ciPipelineId", ciPipelineId, "err", err)
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetSchedules(ciPipelineId)
+}
+
+func (impl *CiPipelineScheduleServiceImpl) DeleteSchedules(ciPipelineId int, userId int32) error {
+	_, err := impl.SaveSchedules(ciPipelineId, nil, userId)
+	return err
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetSchedules(ciPipelineId int) ([]*bean.CiPipelineSchedule, error) {
+	schedulesByPipeline, err := impl.GetSchedulesByCiPipelineIds([]int{ciPipelineId})
+	if err != nil {
+		return nil, err
+	}
+	return schedulesByPipeline[ciPipelineId], nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetSchedulesByCiPipelineIds(ciPipelineIds []int) (map[int][]*bean.CiPipelineSchedule, error) {
+	schedulesByPipeline := make(map[int][]*bean.CiPipelineSchedule)
+	models, err := impl.ciPipelineScheduleRepository.FindByCiPipelineIds(ciPipelineIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedules", "ciPipelineIds", ciPipelineIds, "err", err)
+		return nil, err
+	}
+	if len(models) == 0 {
+		return schedulesByPipeline, nil
+	}
+	materialsBySchedule, err := impl.getMaterialsByScheduleIds(models)
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range models {
+		schedulesByPipeline[model.CiPipelineId] = append(schedulesByPipeline[model.CiPipelineId], adaptScheduleModelToBean(model, materialsBySchedule[model.Id]))
+	}
+	return schedulesByPipeline, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetDueSchedules(at time.Time) ([]*repository.CiPipelineSchedule, map[int][]*repository.CiPipelineScheduleMaterial, error) {
+	models, err := impl.ciPipelineScheduleRepository.FindDue(at)
+	if err != nil {
+		impl.logger.Errorw("error in fetching due ci pipeline schedules", "at", at, "err", err)
+		return nil, nil, err
+	}
+	if len(models) == 0 {
+		return models, nil, nil
+	}
+	materialsBySchedule, err := impl.getMaterialsByScheduleIds(models)
+	if err != nil {
+		return nil, nil, err
+	}
+	return models, materialsBySchedule, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) ClaimRun(schedule *repository.CiPipelineSchedule, at time.Time) (bool, error) {
+	nextRunAt, err := helper.GetNextRunAt(schedule.CronExpression, schedule.Timezone, at)
+	if err != nil {
+		impl.logger.Errorw("error in computing next run of schedule", "scheduleId", schedule.Id, "err", err)
+		return false, err
+	}
+	claimed, err := impl.ciPipelineScheduleRepository.ClaimRun(schedule.Id, schedule.NextRunAt, nextRunAt)
+	if err != nil {
+		impl.logger.Errorw("error in claiming schedule run", "scheduleId", schedule.Id, "err", err)
+		return false, err
+	}
+	if claimed {
+		schedule.NextRunAt = nextRunAt
+	}
+	return claimed, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) RecordRun(schedule *repository.CiPipelineSchedule, status repository.ScheduleRunStatus, message string, ciWorkflowId int, builtCommits map[int]string) error {
+	schedule.LastRunAt = time.Now()
+	schedule.LastRunStatus = status
+	schedule.LastRunMessage = message
+	if status == repository.ScheduleRunStatusTriggered {
+		encodedCommits, err := helper.EncodeBuiltCommits(builtCommits)
+		if err != nil {
+			impl.logger.Errorw("error in encoding built commits", "scheduleId", schedule.Id, "err", err)
+			return err
+		}
+		schedule.LastCiWorkflowId = ciWorkflowId
+		schedule.LastBuiltCommits = encodedCommits
+	}
+	err := impl.ciPipelineScheduleRepository.UpdateRunResult(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in saving schedule run result", "scheduleId", schedule.Id, "status", status, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) validateSchedules(ciPipelineId int, schedules []*bean.CiPipelineSchedule) error {
+	ciPipeline, err := impl.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "ciPipelineId", ciPipelineId, "err", err)
+		return err
+	}
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 || ciPipeline.PipelineType == string(common.LINKED_CD) {
+		errMsg := "schedules are supported only on ci pipelines which build from source"
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	ciPipelineMaterials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(ciPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipeline materials", "ciPipelineId", ciPipelineId, "err", err)
+		return err
+	}
+	branchMaterials := make(map[int]bool)
+	for _, ciPipelineMaterial := range ciPipelineMaterials {
+		if ciPipelineMaterial.Type == constants.SOURCE_TYPE_WEBHOOK {
+			errMsg := "schedules are not supported on ci pipelines with webhook source"
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		branchMaterials[ciPipelineMaterial.GitMaterialId] = true
+	}
+
+	err = impl.ValidateScheduleExpressions(schedules)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		for _, material := range schedule.Materials {
+			if !branchMaterials[material.GitMaterialId] {
+				errMsg := fmt.Sprintf("git material %d of schedule %s is not a branch source of the ci pipeline", material.GitMaterialId, schedule.Name)
+				return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+			}
+		}
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) ValidateScheduleExpressions(schedules []*bean.CiPipelineSchedule) error {
+	scheduleNames := make(map[string]bool)
+	for _, schedule := range schedules {
+		schedule.Name = strings.TrimSpace(schedule.Name)
+		if len(schedule.Name) == 0 {
+			errMsg := "schedule name is required"
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		if len(schedule.Timezone) == 0 {
+			schedule.Timezone = helper.DefaultTimezone
+		}
+		if scheduleNames[schedule.Name] {
+			errMsg := fmt.Sprintf("duplicate schedule name %s", schedule.Name)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		scheduleNames[schedule.Name] = true
+		if err := helper.ValidateCronSchedule(schedule.CronExpression, schedule.Timezone); err != nil {
+			return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+		}
+		gitMaterialIds := make(map[int]bool)
+		for _, material := range schedule.Materials {
+			if gitMaterialIds[material.GitMaterialId] {
+				errMsg := fmt.Sprintf("multiple branches given for git material %d in schedule %s", material.GitMaterialId, schedule.Name)
+				return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+			}
+			gitMaterialIds[material.GitMaterialId] = true
+		}
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) getMaterialsByScheduleIds(schedules []*repository.CiPipelineSchedule) (map[int][]*repository.CiPipelineScheduleMaterial, error) {
+	scheduleIds := make([]int, 0, len(schedules))
+	for _, schedule := range schedules {
+		scheduleIds = append(scheduleIds, schedule.Id)
+	}
+	materials, err := impl.ciPipelineScheduleRepository.FindMaterialsByScheduleIds(scheduleIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule materials", "scheduleIds", scheduleIds, "err", err)
+		return nil, err
+	}
+	materialsBySchedule := make(map[int][]*repository.CiPipelineScheduleMaterial)
+	for _, material := range materials {
+		materialsBySchedule[material.CiPipelineScheduleId] = append(materialsBySchedule[material.CiPipelineScheduleId], material)
+	}
+	return materialsBySchedule, nil
+}
+
+func adaptScheduleModelToBean(model *repository.CiPipelineSchedule, materials []*repository.CiPipelineScheduleMaterial) *bean.CiPipelineSchedule {
+	schedule := &bean.CiPipelineSchedule{
+		Id:                model.Id,
+		Name:              model.Name,
+		CronExpression:    model.CronExpression,
+		Timezone:          model.Timezone,
+		SkipIfNoNewCommit: model.SkipIfNoNewCommit,
+		Active:            model.Active,
+		Materials:         make([]*bean.CiPipelineScheduleMaterial, 0, len(materials)),
+	}
+	for _, material := range materials {
+		schedule.Materials = append(schedule.Materials, &bean.CiPipelineScheduleMaterial{
+			GitMaterialId: material.GitMaterialId,
+			BranchName:    material.BranchName,
+		})
+	}
+	if model.Active && !model.NextRunAt.IsZero() {
+		nextRunAt := model.NextRunAt
+		schedule.NextRunAt = &nextRunAt
+	}
+	if !model.LastRunAt.IsZero() {
+		schedule.LastRun = &bean.CiPipelineScheduleRun{
+			RunAt:        model.LastRunAt,
+			Status:       string(model.LastRunStatus),
+			Message:      model.LastRunMessage,
+			CiWorkflowId: model.LastCiWorkflowId,
+		}
+	}
+	return schedule
+}
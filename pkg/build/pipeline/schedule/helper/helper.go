@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+const DefaultTimezone = "UTC"
+
+// ValidateCronSchedule checks that the cron expression is a standard 5 field expression and the timezone is known
+func ValidateCronSchedule(cronExpression, timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s", timezone)
+	}
+	if _, err := cron.ParseStandard(cronExpression); err != nil {
+		return fmt.Errorf("invalid cron expression %s: %s", cronExpression, err.Error())
+	}
+	return nil
+}
+
+// GetNextRunAt returns the first tick of the cron expression in the given timezone after the given time
+func GetNextRunAt(cronExpression, timezone string, after time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after.In(location)), nil
+}
+
+// HasNewCommits returns true if any material has a commit other than the one built last, a material
+// which was not built last (newly added or first run) is considered as having a new commit
+func HasNewCommits(lastBuiltCommits, latestCommits map[int]string) bool {
+	for ciPipelineMaterialId, commit := range latestCommits {
+		if lastBuiltCommits[ciPipelineMaterialId] != commit {
+			return true
+		}
+	}
+	return false
+}
+
+func EncodeBuiltCommits(commits map[int]string) (string, error) {
+	if len(commits) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(commits)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func DecodeBuiltCommits(encoded string) (map[int]string, error) {
+	commits := make(map[int]string)
+	if len(encoded) == 0 {
+		return commits, nil
+	}
+	err := json.Unmarshal([]byte(encoded), &commits)
+	return commits, err
+}
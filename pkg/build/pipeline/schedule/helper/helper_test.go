@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateCronSchedule(t *testing.T) {
+	assert.Nil(t, ValidateCronSchedule("0 2 * * *", "UTC"))
+	assert.Nil(t, ValidateCronSchedule("@weekly", "Asia/Kolkata"))
+	assert.NotNil(t, ValidateCronSchedule("0 2 * *", "UTC"))
+	assert.NotNil(t, ValidateCronSchedule("0 2 * * *", "Mars/Olympus"))
+}
+
+func TestGetNextRunAt(t *testing.T) {
+	// nightly at 02:00 in Asia/Kolkata (UTC+05:30)
+	nextRunAt, err := GetNextRunAt("0 2 * * *", "Asia/Kolkata", time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2024, 6, 3, 20, 30, 0, 0, time.UTC).Equal(nextRunAt), "got %s", nextRunAt)
+
+	// next run is strictly after the given time
+	nextRunAt, err = GetNextRunAt("0 2 * * *", "UTC", time.Date(2024, 6, 3, 2, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2024, 6, 4, 2, 0, 0, 0, time.UTC).Equal(nextRunAt), "got %s", nextRunAt)
+
+	_, err = GetNextRunAt("invalid", "UTC", time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestHasNewCommits(t *testing.T) {
+	tests := []struct {
+		name             string
+		lastBuiltCommits map[int]string
+		latestCommits    map[int]string
+		want             bool
+	}{
+		{
+			name:          "first run",
+			latestCommits: map[int]string{1: "a1"},
+			want:          true,
+		},
+		{
+			name:             "no new commit",
+			lastBuiltCommits: map[int]string{1: "a1", 2: "b1"},
+			latestCommits:    map[int]string{1: "a1", 2: "b1"},
+			want:             false,
+		},
+		{
+			name:             "new commit on one material",
+			lastBuiltCommits: map[int]string{1: "a1", 2: "b1"},
+			latestCommits:    map[int]string{1: "a1", 2: "b2"},
+			want:             true,
+		},
+		{
+			name:             "material added after last run",
+			lastBuiltCommits: map[int]string{1: "a1"},
+			latestCommits:    map[int]string{1: "a1", 2: "b1"},
+			want:             true,
+		},
+		{
+			name:             "material removed after last run",
+			lastBuiltCommits: map[int]string{1: "a1", 2: "b1"},
+			latestCommits:    map[int]string{1: "a1"},
+			want:             false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasNewCommits(tt.lastBuiltCommits, tt.latestCommits))
+		})
+	}
+}
+
+func TestBuiltCommitsEncoding(t *testing.T) {
+	commits := map[int]string{1: "a1", 2: "b1"}
+	encoded, err := EncodeBuiltCommits(commits)
+	assert.Nil(t, err)
+	decoded, err := DecodeBuiltCommits(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, commits, decoded)
+
+	encoded, err = EncodeBuiltCommits(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, encoded)
+	decoded, err = DecodeBuiltCommits(encoded)
+	assert.Nil(t, err)
+	assert.Empty(t, decoded)
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusTriggered ScheduleRunStatus = "TRIGGERED"
+	ScheduleRunStatusSkipped   ScheduleRunStatus = "SKIPPED"
+	ScheduleRunStatusFailed    ScheduleRunStatus = "FAILED"
+)
+
+// CiPipelineSchedule builds the ci pipeline on every CronExpression tick in Timezone,
+// NextRunAt is the upcoming tick and is moved ahead by the poller which claims the run
+type CiPipelineSchedule struct {
+	tableName         struct{}          `sql:"ci_pipeline_schedule" pg:",discard_unknown_columns"`
+	Id                int               `sql:"id,pk"`
+	CiPipelineId      int               `sql:"ci_pipeline_id,notnull"`
+	Name              string            `sql:"name,notnull"`
+	CronExpression    string            `sql:"cron_expression,notnull"`
+	Timezone          string            `sql:"timezone,notnull"`
+	SkipIfNoNewCommit bool              `sql:"skip_if_no_new_commit,notnull"`
+	Active            bool              `sql:"active,notnull"`
+	Deleted           bool              `sql:"deleted,notnull"`
+	NextRunAt         time.Time         `sql:"next_run_at"`
+	LastRunAt         time.Time         `sql:"last_run_at"`
+	LastRunStatus     ScheduleRunStatus `sql:"last_run_status"`
+	LastRunMessage    string            `sql:"last_run_message"`
+	LastCiWorkflowId  int               `sql:"last_ci_workflow_id"`
+	LastBuiltCommits  string            `sql:"last_built_commits"`
+	sql.AuditLog
+}
+
+// CiPipelineScheduleMaterial overrides the branch built by a schedule for a git material of the pipeline
+type CiPipelineScheduleMaterial struct {
+	tableName            struct{} `sql:"ci_pipeline_schedule_material" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	CiPipelineScheduleId int      `sql:"ci_pipeline_schedule_id,notnull"`
+	GitMaterialId        int      `sql:"git_material_id,notnull"`
+	BranchName           string   `sql:"branch_name,notnull"`
+	sql.AuditLog
+}
+
+type CiPipelineScheduleRepository interface {
+	Save(tx *pg.Tx, model *CiPipelineSchedule) error
+	Update(tx *pg.Tx, model *CiPipelineSchedule) error
+	FindByCiPipelineIds(ciPipelineIds []int) ([]*CiPipelineSchedule, error)
+	FindDue(at time.Time) ([]*CiPipelineSchedule, error)
+	// ClaimRun moves next_run_at of the schedule from expectedNextRunAt to nextRunAt, returns false if
+	// the run was already claimed (by another replica) or the schedule is no longer active
+	ClaimRun(id int, expectedNextRunAt, nextRunAt time.Time) (bool, error)
+	UpdateRunResult(model *CiPipelineSchedule) error
+
+	SaveMaterials(tx *pg.Tx, models []*CiPipelineScheduleMaterial) error
+	DeleteMaterialsByScheduleIds(tx *pg.Tx, scheduleIds []int) error
+	FindMaterialsByScheduleIds(scheduleIds []int) ([]*CiPipelineScheduleMaterial, error)
+}
+
+type CiPipelineScheduleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCiPipelineScheduleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CiPipelineScheduleRepositoryImpl {
+	return &CiPipelineScheduleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) Save(tx *pg.Tx, model *CiPipelineSchedule) error {
+	return tx.Insert(model)
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) Update(tx *pg.Tx, model *CiPipelineSchedule) error {
+	return tx.Update(model)
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindByCiPipelineIds(ciPipelineIds []int) ([]*CiPipelineSchedule, error) {
+	var models []*CiPipelineSchedule
+	if len(ciPipelineIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("ci_pipeline_id in (?)", pg.In(ciPipelineIds)).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindDue(at time.Time) ([]*CiPipelineSchedule, error) {
+	var models []*CiPipelineSchedule
+	err := impl.dbConnection.Model(&models).
+		Where("next_run_at <= ?", at).
+		Where("active = ?", true).
+		Where("deleted = ?", false).
+		Order("next_run_at ASC").
+		Select()
+	return models, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) ClaimRun(id int, expectedNextRunAt, nextRunAt time.Time) (bool, error) {
+	result, err := impl.dbConnection.Model((*CiPipelineSchedule)(nil)).
+		Set("next_run_at = ?", nextRunAt).
+		Where("id = ?", id).
+		Where("next_run_at = ?", expectedNextRunAt).
+		Where("active = ?", true).
+		Where("deleted = ?", false).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) UpdateRunResult(model *CiPipelineSchedule) error {
+	_, err := impl.dbConnection.Model(model).
+		Set("last_run_at = ?last_run_at").
+		Set("last_run_status = ?last_run_status").
+		Set("last_run_message = ?last_run_message").
+		Set("last_ci_workflow_id = ?last_ci_workflow_id").
+		Set("last_built_commits = ?last_built_commits").
+		WherePK().
+		Update()
+	return err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) SaveMaterials(tx *pg.Tx, models []*CiPipelineScheduleMaterial) error {
+	if len(models) == 0 {
+		return nil
+	}
+	return tx.Insert(&models)
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) DeleteMaterialsByScheduleIds(tx *pg.Tx, scheduleIds []int) error {
+	if len(scheduleIds) == 0 {
+		return nil
+	}
+	_, err := tx.Model((*CiPipelineScheduleMaterial)(nil)).
+		Where("ci_pipeline_schedule_id in (?)", pg.In(scheduleIds)).
+		Delete()
+	return err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindMaterialsByScheduleIds(scheduleIds []int) ([]*CiPipelineScheduleMaterial, error) {
+	var models []*CiPipelineScheduleMaterial
+	if len(scheduleIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("ci_pipeline_schedule_id in (?)", pg.In(scheduleIds)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
@@ -18,10 +18,12 @@ package pipeline
 
 import (
 	"github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule"
 	"github.com/google/wire"
 )
 
 var WireSet = wire.NewSet(
 	read.NewCiPipelineConfigReadServiceImpl,
 	wire.Bind(new(read.CiPipelineConfigReadService), new(*read.CiPipelineConfigReadServiceImpl)),
+	schedule.WireSet,
 )
@@ -75,6 +75,9 @@ type HandlerService interface {
 	CheckAndReTriggerCI(workflowStatus eventProcessorBean.CiCdStatus) error
 	HandleCIManual(ciTriggerRequest bean.CiTriggerRequest) (int, error)
 	HandleCIWebhook(gitCiTriggerRequest bean.GitCiTriggerRequest) (int, error)
+	// HandleCIScheduled triggers the ci pipeline on commits resolved by a ci pipeline schedule, commits are not
+	// resolved from pipeline materials here as a schedule can build branches other than the configured ones
+	HandleCIScheduled(ciPipelineId int, pipelineType string, commitHashes map[int]pipelineConfig.GitCommit) (int, error)
 
 	StartCiWorkflowAndPrepareWfRequest(trigger types.Trigger) (*pipelineConfig.CiPipeline, map[string]string, *pipelineConfig.CiWorkflow, *types.WorkflowRequest, error)
 
@@ -309,6 +312,37 @@ func (impl *HandlerServiceImpl) HandleCIManual(ciTriggerRequest bean.CiTriggerRe
 	return id, nil
 }
 
+func (impl *HandlerServiceImpl) HandleCIScheduled(ciPipelineId int, pipelineType string, commitHashes map[int]pipelineConfig.GitCommit) (int, error) {
+	impl.Logger.Debugw("HandleCIScheduled for pipeline ", "PipelineId", ciPipelineId)
+	ciArtifact, err := impl.ciArtifactRepository.GetLatestArtifactTimeByCiPipelineId(ciPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("Error in GetLatestArtifactTimeByCiPipelineId", "err", err, "pipelineId", ciPipelineId)
+		return 0, err
+	}
+	createdOn := time.Time{}
+	if err != pg.ErrNoRows {
+		createdOn = ciArtifact.CreatedOn
+	}
+	// updating runtime params
+	runtimeParams, err := impl.updateRuntimeParamsForAutoCI(ciPipelineId, common.NewRuntimeParameters())
+	if err != nil {
+		impl.Logger.Errorw("err, updateRuntimeParamsForAutoCI", "ciPipelineId", ciPipelineId,
+			"runtimeParameters", runtimeParams, "err", err)
+		return 0, err
+	}
+	trigger := types.Trigger{
+		PipelineId:          ciPipelineId,
+		CommitHashes:        commitHashes,
+		CiMaterials:         nil,
+		TriggeredBy:         bean6.SYSTEM_USER_ID,
+		InvalidateCache:     false,
+		RuntimeParameters:   runtimeParams,
+		PipelineType:        pipelineType,
+		CiArtifactLastFetch: createdOn,
+	}
+	return impl.triggerCiPipeline(trigger)
+}
+
 func (impl *HandlerServiceImpl) HandleCIWebhook(gitCiTriggerRequest bean.GitCiTriggerRequest) (int, error) {
 	impl.Logger.Debugw("HandleCIWebhook for material ", "material", gitCiTriggerRequest.CiPipelineMaterial)
 	ciPipeline, err := impl.GetCiPipeline(gitCiTriggerRequest.CiPipelineMaterial.Id)
@@ -902,6 +936,11 @@ func (impl *HandlerServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineCo
 			continue
 		}
 		commitHashForPipelineId := commitHashes[ciMaterial.Id]
+		sourceValue := ciMaterial.Value
+		if ciMaterial.Type == constants.SOURCE_TYPE_BRANCH_FIXED && len(commitHashForPipelineId.CiConfigureSourceValue) > 0 {
+			// scheduled builds can build a branch other than the configured one
+			sourceValue = commitHashForPipelineId.CiConfigureSourceValue
+		}
 		ciProjectDetail := pipelineConfigBean.CiProjectDetails{
 			GitRepository:   ciMaterial.GitMaterial.Url,
 			MaterialName:    ciMaterial.GitMaterial.Name,
@@ -910,7 +949,7 @@ func (impl *HandlerServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineCo
 			CommitHash:      commitHashForPipelineId.Commit,
 			Author:          commitHashForPipelineId.Author,
 			SourceType:      ciMaterial.Type,
-			SourceValue:     ciMaterial.Value,
+			SourceValue:     sourceValue,
 			GitTag:          ciMaterial.GitTag,
 			Message:         commitHashForPipelineId.Message,
 			Type:            string(ciMaterial.Type),
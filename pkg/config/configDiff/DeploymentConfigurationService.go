@@ -34,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/adapter"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	draftRead "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
@@ -54,6 +55,9 @@ type DeploymentConfigurationService interface {
 	GetAllConfigData(ctx context.Context, configDataQueryParams *bean2.ConfigDataQueryParams, userHasAdminAccess bool) (*bean2.DeploymentAndCmCsConfigDto, error)
 	CompareCategoryWiseConfigData(ctx context.Context, comparisonRequestDto bean2.ComparisonRequestDto, userHasAdminAccess bool) (*bean2.ComparisonResponseDto, error)
 	GetManifest(ctx context.Context, manifestRequest *bean2.ManifestRequest) (*bean2.ManifestResponse, error)
+	// GetDeploymentManifest renders all the resources of the app on the env with the chart and merged values of a
+	// deployment, for the kube version of the cluster of the env
+	GetDeploymentManifest(ctx context.Context, appId, envId, chartRefId int, k8sVersion, mergedValues string) (*bean2.ManifestResponse, error)
 }
 
 type DeploymentConfigurationServiceImpl struct {
@@ -83,6 +87,7 @@ type DeploymentConfigurationServiceImpl struct {
 	mergeUtil                            util.MergeUtil
 	HelmAppReadService                   read3.HelmAppReadService
 	chartReadService                     read4.ChartReadService
+	configDraftReadService               draftRead.ConfigDraftReadService
 }
 
 func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
@@ -111,6 +116,7 @@ func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
 	mergeUtil util.MergeUtil,
 	HelmAppReadService read3.HelmAppReadService,
 	chartReadService read4.ChartReadService,
+	configDraftReadService draftRead.ConfigDraftReadService,
 ) (*DeploymentConfigurationServiceImpl, error) {
 	deploymentConfigurationService := &DeploymentConfigurationServiceImpl{
 		logger:                               logger,
@@ -139,6 +145,7 @@ func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
 		mergeUtil:                            mergeUtil,
 		HelmAppReadService:                   HelmAppReadService,
 		chartReadService:                     chartReadService,
+		configDraftReadService:               configDraftReadService,
 	}
 
 	return deploymentConfigurationService, nil
@@ -287,13 +294,81 @@ func (impl *DeploymentConfigurationServiceImpl) GetManifest(ctx context.Context,
 		return nil, err
 	}
 
-	k8sServerVersion, err := impl.k8sUtil.GetKubeVersion()
+	mergedValuesYAML, err := impl.getMergedValuesForCMCSHelmTemplate(manifestRequest, resolvedTemplate, app, envId)
+	if err != nil {
+		impl.logger.Errorw("error in merging values for cm cs ", "err", err)
+		return nil, err
+	}
+
+	generatedManifest, err := impl.templateChart(ctx, app, refChart, chartInBytes, releaseName, "", "", string(mergedValuesYAML))
+	if err != nil {
+		return nil, err
+	}
+
+	yamlSplits, err := kube.SplitYAML([]byte(generatedManifest))
+	for _, yaml := range yamlSplits {
+		if (manifestRequest.ResourceType == bean.CM && yaml.GetKind() == "ConfigMap") || (manifestRequest.ResourceType == bean.CS && yaml.GetKind() == "Secret") {
+			name := yaml.GetName()
+			if name == manifestRequest.ResourceName || name == fmt.Sprintf("%s-%d", manifestRequest.ResourceName, appId) {
+				yamlJSON, err := yaml.MarshalJSON()
+				if err != nil {
+					return nil, err
+				}
+				return &bean2.ManifestResponse{Manifest: string(yamlJSON)}, nil
+			}
+		}
+	}
+
+	return &bean2.ManifestResponse{Manifest: ""}, nil
+}
+
+// GetDeploymentManifest renders all the resources of the deployed chart of the app on the env with the final merged
+// values of a deployment, the desired state of the resources of the helm release
+func (impl *DeploymentConfigurationServiceImpl) GetDeploymentManifest(ctx context.Context, appId, envId, chartRefId int, k8sVersion, mergedValues string) (*bean2.ManifestResponse, error) {
+	app, err := impl.appRepository.FindById(appId)
+	if err != nil {
+		impl.logger.Errorw("error in finding app by id", "appId", appId, "err", err)
+		return nil, err
+	}
+	environment, err := impl.environmentRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "envId", envId, "err", err)
+		return nil, err
+	}
+	releaseName := util2.BuildDeployedAppName(app.AppName, environment.Name)
+	pipelineModel, err := impl.pipelineRepository.FindOneByAppIdAndEnvId(appId, envId)
+	if err != nil && !errors2.Is(err, pg.ErrNoRows) {
+		impl.logger.Errorw("error in getting pipeline model", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	if pipelineModel != nil && len(pipelineModel.DeploymentAppName) != 0 {
+		releaseName = pipelineModel.DeploymentAppName
+	}
+	refChart, chartInBytes, err := impl.getChartBytes(ctx, chartRefId, app)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart bytes", "appId", appId, "envId", envId, "chartRefId", chartRefId, "err", err)
+		return nil, err
+	}
+	generatedManifest, err := impl.templateChart(ctx, app, refChart, chartInBytes, releaseName, environment.Namespace, k8sVersion, mergedValues)
 	if err != nil {
-		impl.logger.Errorw("exception caught in getting k8sServerVersion", "err", err)
 		return nil, err
 	}
+	return &bean2.ManifestResponse{Manifest: generatedManifest}, nil
+}
 
-	sanitizedK8sVersion := k8sServerVersion.String()
+// templateChart renders the chart with the values, namespace is left to the default of the release and k8sVersion
+// to the version of the default cluster if empty
+func (impl *DeploymentConfigurationServiceImpl) templateChart(ctx context.Context, app *appRepository.App, refChart *chartRefBean.ChartRefDto,
+	chartInBytes []byte, releaseName, namespace, k8sVersion, valuesYaml string) (string, error) {
+	sanitizedK8sVersion := k8sVersion
+	if len(sanitizedK8sVersion) == 0 {
+		k8sServerVersion, err := impl.k8sUtil.GetKubeVersion()
+		if err != nil {
+			impl.logger.Errorw("exception caught in getting k8sServerVersion", "err", err)
+			return "", err
+		}
+		sanitizedK8sVersion = k8sServerVersion.String()
+	}
 
 	installReleaseRequest := &gRPC.InstallReleaseRequest{
 		AppName:         app.AppName,
@@ -305,51 +380,30 @@ func (impl *DeploymentConfigurationServiceImpl) GetManifest(ctx context.Context,
 			Content: chartInBytes,
 		},
 		ReleaseIdentifier: &gRPC.ReleaseIdentifier{
-			ReleaseName: releaseName,
+			ReleaseName:      releaseName,
+			ReleaseNamespace: namespace,
 		},
+		ValuesYaml: valuesYaml,
 	}
 	config, err := impl.HelmAppReadService.GetClusterConf(clusterBean.DefaultClusterId)
 	if err != nil {
 		impl.logger.Errorw("error in fetching cluster detail", "clusterId", 1, "err", err)
-		return nil, err
+		return "", err
 	}
 	installReleaseRequest.ReleaseIdentifier.ClusterConfig = config
 
-	mergedValuesYAML, err := impl.getMergedValuesForCMCSHelmTemplate(manifestRequest, resolvedTemplate, app, envId)
-	if err != nil {
-		impl.logger.Errorw("error in merging values for cm cs ", "err", err)
-		return nil, err
-	}
-
-	installReleaseRequest.ValuesYaml = string(mergedValuesYAML)
-
 	templateChartResponse, err := impl.helmAppClient.TemplateChart(ctx, installReleaseRequest)
 	if err != nil {
 		impl.logger.Errorw("error in templating chart", "err", err)
 		clientErrCode, errMsg := util.GetClientDetailedError(err)
 		if clientErrCode.IsFailedPreconditionCode() {
-			return nil, &util.ApiError{HttpStatusCode: http.StatusUnprocessableEntity, Code: strconv.Itoa(http.StatusUnprocessableEntity), InternalMessage: errMsg, UserMessage: errMsg}
+			return "", &util.ApiError{HttpStatusCode: http.StatusUnprocessableEntity, Code: strconv.Itoa(http.StatusUnprocessableEntity), InternalMessage: errMsg, UserMessage: errMsg}
 		} else if clientErrCode.IsInvalidArgumentCode() {
-			return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, Code: strconv.Itoa(http.StatusConflict), InternalMessage: errMsg, UserMessage: errMsg}
+			return "", &util.ApiError{HttpStatusCode: http.StatusConflict, Code: strconv.Itoa(http.StatusConflict), InternalMessage: errMsg, UserMessage: errMsg}
 		}
-		return nil, err
+		return "", err
 	}
-
-	yamlSplits, err := kube.SplitYAML([]byte(templateChartResponse.GeneratedManifest))
-	for _, yaml := range yamlSplits {
-		if (manifestRequest.ResourceType == bean.CM && yaml.GetKind() == "ConfigMap") || (manifestRequest.ResourceType == bean.CS && yaml.GetKind() == "Secret") {
-			name := yaml.GetName()
-			if name == manifestRequest.ResourceName || name == fmt.Sprintf("%s-%d", manifestRequest.ResourceName, appId) {
-				yamlJSON, err := yaml.MarshalJSON()
-				if err != nil {
-					return nil, err
-				}
-				return &bean2.ManifestResponse{Manifest: string(yamlJSON)}, nil
-			}
-		}
-	}
-
-	return &bean2.ManifestResponse{Manifest: ""}, nil
+	return templateChartResponse.GeneratedManifest, nil
 }
 
 func (impl *DeploymentConfigurationServiceImpl) getMergedValuesForCMCSHelmTemplate(manifestRequest *bean2.ManifestRequest, resolvedTemplate string, app *appRepository.App, envId int) ([]byte, error) {
@@ -444,7 +498,10 @@ func (impl *DeploymentConfigurationServiceImpl) getRefChartBytes(ctx context.Con
 		impl.logger.Errorw("error in getting configured chart ref", "appId", appId, "envId", envId, "err", err)
 		return nil, nil, err
 	}
+	return impl.getChartBytes(ctx, chartRefId, app)
+}
 
+func (impl *DeploymentConfigurationServiceImpl) getChartBytes(ctx context.Context, chartRefId int, app *appRepository.App) (*chartRefBean.ChartRefDto, []byte, error) {
 	refChart, err := impl.chartRefService.FindById(chartRefId)
 	if err != nil {
 		impl.logger.Errorw("error in getting refChart", "err", err, "chartRefId", chartRefId)
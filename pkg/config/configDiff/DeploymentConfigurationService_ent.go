@@ -2,17 +2,57 @@ package configDiff
 
 import (
 	"context"
-	"github.com/devtron-labs/devtron/internal/util"
 	bean2 "github.com/devtron-labs/devtron/pkg/config/configDiff/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/helper"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
-	"net/http"
 )
 
+// getSecretDataForDraftOnly returns the secrets proposed by the drafts awaiting approval in the environment,
+// variables used in drafts are resolved once the drafts are published
 func (impl *DeploymentConfigurationServiceImpl) getSecretDataForDraftOnly(ctx context.Context, appEnvAndClusterMetadata *bean2.AppEnvAndClusterMetadata, userId int32) (*bean2.SecretConfigMetadata, error) {
-	return nil, util.NewApiError(http.StatusNotFound, "implementation for draft kind not found", "implementation for draft kind not found")
+	drafts, err := impl.configDraftReadService.GetAwaitingApprovalDrafts(appEnvAndClusterMetadata.AppId, appEnvAndClusterMetadata.EnvId, bean.CS)
+	if err != nil {
+		impl.logger.Errorw("error in getting secret drafts", "appEnvAndClusterMetadata", appEnvAndClusterMetadata, "err", err)
+		return nil, err
+	}
+	draftSecrets := make([]*bean.ConfigData, 0, len(drafts))
+	for _, draft := range drafts {
+		configData, err := helper.GetDraftConfigData(draft.LatestVersion)
+		if err != nil {
+			impl.logger.Errorw("error in getting secret of draft", "draftId", draft.Id, "err", err)
+			return nil, err
+		}
+		if configData != nil {
+			draftSecrets = append(draftSecrets, configData)
+		}
+	}
+	return &bean2.SecretConfigMetadata{
+		SecretsList:                 &bean.SecretsList{ConfigData: draftSecrets},
+		SecretScopeVariableMetadata: &bean2.CmCsScopeVariableMetadata{},
+	}, nil
 }
 
+// getSecretDataForPublishedWithDraft returns the published secrets of the environment with the drafts awaiting approval applied
 func (impl *DeploymentConfigurationServiceImpl) getSecretDataForPublishedWithDraft(ctx context.Context, appEnvAndClusterMetadata *bean2.AppEnvAndClusterMetadata,
 	systemMetadata *resourceQualifiers.SystemMetadata, userId int32) (*bean2.SecretConfigMetadata, error) {
-	return nil, util.NewApiError(http.StatusNotFound, "implementation for published with draft kind not found", "implementation for published with draft kind not found")
+	secretData, err := impl.getSecretConfigResponse("", 0, appEnvAndClusterMetadata.EnvId, appEnvAndClusterMetadata.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in getting secret config response by appId and envId", "appId", appEnvAndClusterMetadata.AppId, "envId", appEnvAndClusterMetadata.EnvId, "err", err)
+		return nil, err
+	}
+	drafts, err := impl.configDraftReadService.GetAwaitingApprovalDrafts(appEnvAndClusterMetadata.AppId, appEnvAndClusterMetadata.EnvId, bean.CS)
+	if err != nil {
+		impl.logger.Errorw("error in getting secret drafts", "appEnvAndClusterMetadata", appEnvAndClusterMetadata, "err", err)
+		return nil, err
+	}
+	secrets, err := helper.ApplyConfigDataDrafts(secretData.ConfigData, drafts)
+	if err != nil {
+		impl.logger.Errorw("error in applying secret drafts", "appEnvAndClusterMetadata", appEnvAndClusterMetadata, "err", err)
+		return nil, err
+	}
+	return &bean2.SecretConfigMetadata{
+		SecretsList:                 &bean.SecretsList{ConfigData: secrets},
+		SecretScopeVariableMetadata: &bean2.CmCsScopeVariableMetadata{},
+	}, nil
 }
@@ -0,0 +1,869 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configPromotion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	pkgBean "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/chart/read"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/config/configDiff"
+	configDiffBean "github.com/devtron-labs/devtron/pkg/config/configDiff/bean"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion/helper"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService"
+	draftBean "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	draftRead "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/read"
+	"go.uber.org/zap"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigPromotionService promotes the published config of an app from one environment to another, like from staging
+// to production. Only the changes selected from the diff are promoted, through the same save paths as the config
+// edits, so the history entries are created and the config protection of the target env is respected.
+type ConfigPromotionService interface {
+	GetPromotionDiff(ctx context.Context, request *bean.PromotionDiffRequest, userHasAdminAccess bool) (*bean.PromotionDiff, error)
+	// Promote applies the selected changes all or none, the changes applied before a failing one are reverted. The
+	// resources which could not be reverted are listed in the error, as these are left with the promoted changes.
+	Promote(ctx context.Context, request *bean.PromotionRequest, userMetadata *userBean.UserMetadata) (*bean.PromotionResponse, error)
+}
+
+type ConfigPromotionServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	deploymentConfigurationService configDiff.DeploymentConfigurationService
+	draftAwareConfigService        draftAwareConfigService.DraftAwareConfigService
+	configDraftService             draftAwareConfigService.ConfigDraftService
+	configDraftReadService         draftRead.ConfigDraftReadService
+	configMapService               pipeline.ConfigMapService
+	propertiesConfigService        pipeline.PropertiesConfigService
+	cdPipelineConfigService        pipeline.CdPipelineConfigService
+	chartReadService               read.ChartReadService
+	pipelineRepository             pipelineConfig.PipelineRepository
+	appRepository                  app.AppRepository
+	environmentRepository          repository.EnvironmentRepository
+}
+
+func NewConfigPromotionServiceImpl(logger *zap.SugaredLogger,
+	deploymentConfigurationService configDiff.DeploymentConfigurationService,
+	draftAwareConfigService draftAwareConfigService.DraftAwareConfigService,
+	configDraftService draftAwareConfigService.ConfigDraftService,
+	configDraftReadService draftRead.ConfigDraftReadService,
+	configMapService pipeline.ConfigMapService,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	cdPipelineConfigService pipeline.CdPipelineConfigService,
+	chartReadService read.ChartReadService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	appRepository app.AppRepository,
+	environmentRepository repository.EnvironmentRepository) *ConfigPromotionServiceImpl {
+	return &ConfigPromotionServiceImpl{
+		logger:                         logger,
+		deploymentConfigurationService: deploymentConfigurationService,
+		draftAwareConfigService:        draftAwareConfigService,
+		configDraftService:             configDraftService,
+		configDraftReadService:         configDraftReadService,
+		configMapService:               configMapService,
+		propertiesConfigService:        propertiesConfigService,
+		cdPipelineConfigService:        cdPipelineConfigService,
+		chartReadService:               chartReadService,
+		pipelineRepository:             pipelineRepository,
+		appRepository:                  appRepository,
+		environmentRepository:          environmentRepository,
+	}
+}
+
+// envConfig is the published config of the app on an env
+type envConfig struct {
+	env        *repository.Environment
+	pipeline   *pipelineConfig.Pipeline
+	values     json.RawMessage
+	appMetrics bool
+	chartRefId int
+	// properties is the latest env override of the deployment template, nil if never created
+	properties *pipelineBean.EnvironmentProperties
+	configMaps map[string]*pipelineBean.ConfigData
+	secrets    map[string]*pipelineBean.ConfigData
+	strategies []pkgBean.Strategy
+}
+
+type promotionState struct {
+	appId  int
+	source *envConfig
+	target *envConfig
+	diff   *bean.PromotionDiff
+}
+
+// promotionStep applies the promoted changes of one resource, undo reverts them if a later step fails
+type promotionStep struct {
+	resourceType pipelineBean.ResourceType
+	resourceName string
+	apply        func() error
+	undo         func() error
+}
+
+func (step *promotionStep) String() string {
+	if len(step.resourceName) == 0 {
+		return string(step.resourceType)
+	}
+	return fmt.Sprintf("%s %s", step.resourceType, step.resourceName)
+}
+
+func (impl *ConfigPromotionServiceImpl) GetPromotionDiff(ctx context.Context, request *bean.PromotionDiffRequest, userHasAdminAccess bool) (*bean.PromotionDiff, error) {
+	state, err := impl.getPromotionState(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !userHasAdminAccess {
+		for _, item := range state.diff.Items {
+			if item.ResourceType != pipelineBean.CS {
+				continue
+			}
+			if item.Source != nil {
+				item.Source = bean.SecretMaskedValue
+			}
+			if item.Target != nil {
+				item.Target = bean.SecretMaskedValue
+			}
+		}
+	}
+	return state.diff, nil
+}
+
+func (impl *ConfigPromotionServiceImpl) Promote(ctx context.Context, request *bean.PromotionRequest, userMetadata *userBean.UserMetadata) (*bean.PromotionResponse, error) {
+	state, err := impl.getPromotionState(ctx, &request.PromotionDiffRequest)
+	if err != nil {
+		return nil, err
+	}
+	// the selection is validated against the current diff as the config may have changed since the diff was fetched
+	diffItems := make(map[string]*bean.PromotionItem, len(state.diff.Items))
+	for _, item := range state.diff.Items {
+		diffItems[item.String()] = item
+	}
+	selected := make(map[string]*bean.PromotionItem, len(request.Items))
+	for _, identifier := range request.Items {
+		item, ok := diffItems[identifier.String()]
+		if !ok {
+			errMsg := fmt.Sprintf("%s has no change to promote, refresh the diff and retry", identifier.String())
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		selected[identifier.String()] = item
+	}
+	if state.diff.TargetConfigProtected {
+		err = impl.checkPendingDrafts(state, selected)
+		if err != nil {
+			return nil, err
+		}
+	}
+	steps, err := impl.getPromotionSteps(ctx, state, selected, request.UserId, userMetadata)
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range steps {
+		err = step.apply()
+		if err == nil {
+			continue
+		}
+		impl.logger.Errorw("error in promoting config, reverting the promoted changes", "appId", state.appId, "sourceEnvId", request.SourceEnvId,
+			"targetEnvId", request.TargetEnvId, "resourceType", step.resourceType, "resourceName", step.resourceName, "err", err)
+		notReverted := impl.revertSteps(steps[:i], state)
+		if len(notReverted) > 0 {
+			userMsg := fmt.Sprintf("error in promoting %s, the promoted changes of %s could not be reverted and are left on %s",
+				step.String(), strings.Join(notReverted, ", "), state.target.env.Name)
+			return nil, util.NewApiError(http.StatusInternalServerError, userMsg, fmt.Sprintf("%s: %s", userMsg, err.Error()))
+		}
+		return nil, err
+	}
+	promoted := make([]*bean.PromotionItemIdentifier, 0, len(selected))
+	for _, item := range state.diff.Items {
+		if _, ok := selected[item.String()]; ok {
+			identifier := item.PromotionItemIdentifier
+			promoted = append(promoted, &identifier)
+		}
+	}
+	return &bean.PromotionResponse{Promoted: promoted, SavedAsDraft: state.diff.TargetConfigProtected}, nil
+}
+
+// revertSteps reverts the applied steps latest first, the steps which could not be reverted are returned
+func (impl *ConfigPromotionServiceImpl) revertSteps(appliedSteps []*promotionStep, state *promotionState) []string {
+	var notReverted []string
+	for i := len(appliedSteps) - 1; i >= 0; i-- {
+		step := appliedSteps[i]
+		if err := step.undo(); err != nil {
+			impl.logger.Errorw("error in reverting promoted config", "appId", state.appId, "targetEnvId", state.target.env.Id,
+				"resourceType", step.resourceType, "resourceName", step.resourceName, "err", err)
+			notReverted = append(notReverted, step.String())
+		}
+	}
+	return notReverted
+}
+
+func (impl *ConfigPromotionServiceImpl) getPromotionState(ctx context.Context, request *bean.PromotionDiffRequest) (*promotionState, error) {
+	appModel, err := impl.appRepository.FindById(request.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	source, err := impl.getEnvConfig(ctx, appModel, request.SourceEnvId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := impl.getEnvConfig(ctx, appModel, request.TargetEnvId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setSecrets(ctx, appModel.AppName, source, target)
+	if err != nil {
+		return nil, err
+	}
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(request.AppId, request.TargetEnvId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", request.AppId, "envId", request.TargetEnvId, "err", err)
+		return nil, err
+	}
+	diff := &bean.PromotionDiff{
+		AppId:                 request.AppId,
+		SourceEnvId:           request.SourceEnvId,
+		TargetEnvId:           request.TargetEnvId,
+		TargetConfigProtected: isProtected,
+		Items:                 make([]*bean.PromotionItem, 0),
+	}
+	if source.chartRefId != target.chartRefId {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("deployment templates are not compared as %s and %s use different chart versions", source.env.Name, target.env.Name))
+	} else {
+		items, err := diffDeploymentTemplates(source, target)
+		if err != nil {
+			impl.logger.Errorw("error in comparing deployment templates", "appId", request.AppId, "err", err)
+			return nil, err
+		}
+		diff.Items = append(diff.Items, items...)
+	}
+	items, err := diffConfigData(pipelineBean.CM, source.configMaps, target.configMaps)
+	if err != nil {
+		impl.logger.Errorw("error in comparing config maps", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	diff.Items = append(diff.Items, items...)
+	items, err = diffConfigData(pipelineBean.CS, source.secrets, target.secrets)
+	if err != nil {
+		impl.logger.Errorw("error in comparing secrets", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	diff.Items = append(diff.Items, items...)
+	item, err := diffStrategies(source.strategies, target.strategies)
+	if err != nil {
+		impl.logger.Errorw("error in comparing pipeline strategies", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	if item != nil {
+		diff.Items = append(diff.Items, item)
+	}
+	return &promotionState{appId: request.AppId, source: source, target: target, diff: diff}, nil
+}
+
+func (impl *ConfigPromotionServiceImpl) getEnvConfig(ctx context.Context, appModel *app.App, envId int) (*envConfig, error) {
+	env, err := impl.environmentRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+		return nil, err
+	}
+	cdPipeline, err := impl.pipelineRepository.FindActiveByAppIdAndEnvId(appModel.Id, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cd pipeline", "appId", appModel.Id, "envId", envId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) || cdPipeline == nil {
+		errMsg := fmt.Sprintf("app has no deployment pipeline on environment %s", env.Name)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	configData, err := impl.deploymentConfigurationService.GetAllConfigData(ctx, &configDiffBean.ConfigDataQueryParams{
+		AppName:    appModel.AppName,
+		EnvName:    env.Name,
+		ConfigType: configDiffBean.PublishedConfigState.ToString(),
+		ConfigArea: configDiffBean.AppConfiguration.ToString(),
+	}, false)
+	if err != nil {
+		impl.logger.Errorw("error in fetching published config", "appId", appModel.Id, "envId", envId, "err", err)
+		return nil, err
+	}
+	config := &envConfig{env: env, pipeline: cdPipeline}
+	if configData.DeploymentTemplate != nil {
+		config.values = configData.DeploymentTemplate.Data
+		config.appMetrics = configData.DeploymentTemplate.IsAppMetricsEnabled
+	}
+	config.properties, err = impl.propertiesConfigService.GetLatestEnvironmentProperties(appModel.Id, envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env override of deployment template", "appId", appModel.Id, "envId", envId, "err", err)
+		return nil, err
+	}
+	if config.properties != nil && config.properties.IsOverride {
+		config.chartRefId = config.properties.ChartRefId
+	} else {
+		baseChart, err := impl.chartReadService.FindLatestChartForAppByAppId(appModel.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching base deployment template", "appId", appModel.Id, "err", err)
+			return nil, err
+		}
+		config.chartRefId = baseChart.ChartRefId
+	}
+	config.configMaps, err = getConfigDataByName(configData.ConfigMapsData)
+	if err != nil {
+		impl.logger.Errorw("error in reading config maps", "appId", appModel.Id, "envId", envId, "err", err)
+		return nil, err
+	}
+	config.strategies, err = impl.cdPipelineConfigService.GetPipelineStrategies(cdPipeline.Id)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// setSecrets compares the secrets of both the envs unmasked, the values are masked later in the diff if needed
+func (impl *ConfigPromotionServiceImpl) setSecrets(ctx context.Context, appName string, source, target *envConfig) error {
+	comparison, err := impl.deploymentConfigurationService.CompareCategoryWiseConfigData(ctx, configDiffBean.ComparisonRequestDto{
+		ComparisonItems: []*configDiffBean.ComparisonItemRequestDto{
+			{Index: 0, ConfigDataQueryParams: getPublishedConfigQueryParams(appName, source.env.Name)},
+			{Index: 1, ConfigDataQueryParams: getPublishedConfigQueryParams(appName, target.env.Name)},
+		},
+	}, true)
+	if err != nil {
+		impl.logger.Errorw("error in comparing secrets", "appName", appName, "sourceEnv", source.env.Name, "targetEnv", target.env.Name, "err", err)
+		return err
+	}
+	source.secrets = make(map[string]*pipelineBean.ConfigData)
+	target.secrets = make(map[string]*pipelineBean.ConfigData)
+	for _, item := range comparison.ComparisonItemResponse {
+		secrets, err := getConfigDataByName(item.SecretsData)
+		if err != nil {
+			impl.logger.Errorw("error in reading secrets", "appName", appName, "index", item.Index, "err", err)
+			return err
+		}
+		if item.Index == 0 {
+			source.secrets = secrets
+		} else {
+			target.secrets = secrets
+		}
+	}
+	return nil
+}
+
+func getPublishedConfigQueryParams(appName, envName string) *configDiffBean.ConfigDataQueryParams {
+	return &configDiffBean.ConfigDataQueryParams{
+		AppName:    appName,
+		EnvName:    envName,
+		ConfigType: configDiffBean.PublishedConfigState.ToString(),
+		ConfigArea: configDiffBean.AppConfiguration.ToString(),
+	}
+}
+
+func getConfigDataByName(config *configDiffBean.DeploymentAndCmCsConfig) (map[string]*pipelineBean.ConfigData, error) {
+	configDataByName := make(map[string]*pipelineBean.ConfigData)
+	if config == nil || len(config.Data) == 0 {
+		return configDataByName, nil
+	}
+	configDataRequest := &pipelineBean.ConfigDataRequest{}
+	err := json.Unmarshal(config.Data, configDataRequest)
+	if err != nil {
+		return nil, err
+	}
+	for _, configData := range configDataRequest.ConfigData {
+		configDataByName[configData.Name] = configData
+	}
+	return configDataByName, nil
+}
+
+func diffDeploymentTemplates(source, target *envConfig) ([]*bean.PromotionItem, error) {
+	sourceValues, err := unmarshalValues(source.values)
+	if err != nil {
+		return nil, err
+	}
+	targetValues, err := unmarshalValues(target.values)
+	if err != nil {
+		return nil, err
+	}
+	items := helper.DiffValues(sourceValues, targetValues)
+	for _, item := range items {
+		item.ResourceType = pipelineBean.DeploymentTemplate
+	}
+	return items, nil
+}
+
+// effectiveConfigData is the config data as applied on the env, either inherited from the base config or overridden
+func effectiveConfigData(configData *pipelineBean.ConfigData) *pipelineBean.ConfigData {
+	effective := *configData
+	if configData.Global && !configData.Overridden {
+		effective.Data = configData.DefaultData
+		effective.MountPath = configData.DefaultMountPath
+		effective.ESOSecretData = configData.DefaultESOSecretData
+		effective.ExternalSecret = configData.DefaultExternalSecret
+	}
+	effective.DefaultData = nil
+	effective.DefaultMountPath = ""
+	effective.DefaultESOSecretData = pipelineBean.ESOSecretData{}
+	effective.DefaultExternalSecret = nil
+	effective.PatchData = nil
+	effective.MergeStrategy = ""
+	effective.Global = false
+	effective.Overridden = false
+	return &effective
+}
+
+func diffConfigData(resourceType pipelineBean.ResourceType, source, target map[string]*pipelineBean.ConfigData) ([]*bean.PromotionItem, error) {
+	names := make([]string, 0, len(source)+len(target))
+	for name := range source {
+		names = append(names, name)
+	}
+	for name := range target {
+		if _, ok := source[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	items := make([]*bean.PromotionItem, 0)
+	for _, name := range names {
+		sourceConfig, inSource := source[name]
+		targetConfig, inTarget := target[name]
+		var resourceItems []*bean.PromotionItem
+		switch {
+		case !inTarget:
+			resourceItems = []*bean.PromotionItem{{ChangeType: bean.ChangeTypeAdded, Source: effectiveConfigData(sourceConfig)}}
+		case !inSource:
+			// the base config is inherited by all the envs, only the config created on the target env can be removed
+			if targetConfig.Global {
+				continue
+			}
+			resourceItems = []*bean.PromotionItem{{ChangeType: bean.ChangeTypeRemoved, Target: effectiveConfigData(targetConfig)}}
+		default:
+			var err error
+			resourceItems, err = diffConfigDataOfResource(effectiveConfigData(sourceConfig), effectiveConfigData(targetConfig))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range resourceItems {
+			item.ResourceType = resourceType
+			item.ResourceName = name
+		}
+		items = append(items, resourceItems...)
+	}
+	return items, nil
+}
+
+// diffConfigDataOfResource compares the data keys of the config, the configs differing in anything else are compared as a whole
+func diffConfigDataOfResource(source, target *pipelineBean.ConfigData) ([]*bean.PromotionItem, error) {
+	sourceData, err := unmarshalValues(source.Data)
+	if err != nil {
+		return nil, err
+	}
+	targetData, err := unmarshalValues(target.Data)
+	if err != nil {
+		return nil, err
+	}
+	sourceWithoutData, targetWithoutData := *source, *target
+	sourceWithoutData.Data, targetWithoutData.Data = nil, nil
+	if source.External || target.External || !reflect.DeepEqual(sourceWithoutData, targetWithoutData) {
+		if reflect.DeepEqual(sourceWithoutData, targetWithoutData) && reflect.DeepEqual(sourceData, targetData) {
+			return nil, nil
+		}
+		return []*bean.PromotionItem{{ChangeType: bean.ChangeTypeModified, Source: source, Target: target}}, nil
+	}
+	return helper.DiffKeys(sourceData, targetData), nil
+}
+
+func getDefaultStrategy(strategies []pkgBean.Strategy) *pkgBean.Strategy {
+	for i := range strategies {
+		if strategies[i].Default {
+			return &strategies[i]
+		}
+	}
+	return nil
+}
+
+func diffStrategies(source, target []pkgBean.Strategy) (*bean.PromotionItem, error) {
+	sourceStrategy := getDefaultStrategy(source)
+	if sourceStrategy == nil {
+		return nil, nil
+	}
+	sourceValue, err := getStrategyValue(sourceStrategy)
+	if err != nil {
+		return nil, err
+	}
+	item := &bean.PromotionItem{
+		PromotionItemIdentifier: bean.PromotionItemIdentifier{ResourceType: pipelineBean.PipelineStrategy},
+		ChangeType:              bean.ChangeTypeAdded,
+		Source:                  sourceValue,
+	}
+	if targetStrategy := getDefaultStrategy(target); targetStrategy != nil {
+		targetValue, err := getStrategyValue(targetStrategy)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(sourceValue, targetValue) {
+			return nil, nil
+		}
+		item.ChangeType = bean.ChangeTypeModified
+		item.Target = targetValue
+	}
+	return item, nil
+}
+
+func getStrategyValue(strategy *pkgBean.Strategy) (map[string]interface{}, error) {
+	var config interface{}
+	if len(strategy.Config) > 0 {
+		err := json.Unmarshal(strategy.Config, &config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{"strategy": string(strategy.DeploymentTemplate), "config": config}, nil
+}
+
+func unmarshalValues(data json.RawMessage) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if len(data) == 0 {
+		return values, nil
+	}
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
+}
+
+// checkPendingDrafts rejects the promotion to a config protected env if a promoted config already has a draft awaiting
+// approval, as the promotion would otherwise be added as a new version of someone else's draft
+func (impl *ConfigPromotionServiceImpl) checkPendingDrafts(state *promotionState, selected map[string]*bean.PromotionItem) error {
+	drafts, err := impl.configDraftService.GetDrafts(state.appId, state.target.env.Id, []draftBean.DraftState{draftBean.DraftStateAwaitingApproval})
+	if err != nil {
+		return err
+	}
+	for _, draft := range drafts {
+		for _, item := range selected {
+			if item.ResourceType == draft.ResourceType && getDraftResourceName(item.ResourceType, item.ResourceName) == draft.ResourceName {
+				errMsg := fmt.Sprintf("%s %s already has a draft awaiting approval on %s", item.ResourceType, draft.ResourceName, state.target.env.Name)
+				return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+			}
+		}
+	}
+	return nil
+}
+
+func getDraftResourceName(resourceType pipelineBean.ResourceType, resourceName string) string {
+	switch resourceType {
+	case pipelineBean.DeploymentTemplate:
+		return draftBean.DeploymentTemplateResourceName
+	case pipelineBean.PipelineStrategy:
+		return draftBean.PipelineStrategyResourceName
+	}
+	return resourceName
+}
+
+func (impl *ConfigPromotionServiceImpl) getPromotionSteps(ctx context.Context, state *promotionState, selected map[string]*bean.PromotionItem,
+	userId int32, userMetadata *userBean.UserMetadata) ([]*promotionStep, error) {
+	// the selected items are grouped by resource keeping the order of the diff, each resource is saved once
+	var deploymentTemplateKeys []string
+	var strategySelected bool
+	var resources []*bean.PromotionItemIdentifier
+	resourceItems := make(map[bean.PromotionItemIdentifier][]*bean.PromotionItem)
+	for _, item := range state.diff.Items {
+		if _, ok := selected[item.String()]; !ok {
+			continue
+		}
+		switch item.ResourceType {
+		case pipelineBean.DeploymentTemplate:
+			deploymentTemplateKeys = append(deploymentTemplateKeys, item.Key)
+		case pipelineBean.PipelineStrategy:
+			strategySelected = true
+		default:
+			resource := bean.PromotionItemIdentifier{ResourceType: item.ResourceType, ResourceName: item.ResourceName}
+			if _, ok := resourceItems[resource]; !ok {
+				resources = append(resources, &resource)
+			}
+			resourceItems[resource] = append(resourceItems[resource], item)
+		}
+	}
+	steps := make([]*promotionStep, 0, len(resources)+2)
+	if len(deploymentTemplateKeys) > 0 {
+		step, err := impl.getDeploymentTemplateStep(ctx, state, deploymentTemplateKeys, userId, userMetadata)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	for _, resource := range resources {
+		step, err := impl.getConfigDataStep(ctx, state, resource, resourceItems[*resource], userId, userMetadata)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	if strategySelected {
+		steps = append(steps, impl.getStrategyStep(ctx, state, userId))
+	}
+	if state.diff.TargetConfigProtected {
+		// the changes to a config protected env are drafts, reverting them is discarding the drafts
+		for _, step := range steps {
+			resourceType, resourceName := step.resourceType, getDraftResourceName(step.resourceType, step.resourceName)
+			step.undo = func() error {
+				return impl.discardDraft(state.appId, state.target.env.Id, resourceType, resourceName, userId)
+			}
+		}
+	}
+	return steps, nil
+}
+
+func (impl *ConfigPromotionServiceImpl) getDeploymentTemplateStep(ctx context.Context, state *promotionState, pointers []string,
+	userId int32, userMetadata *userBean.UserMetadata) (*promotionStep, error) {
+	sourceValues, err := unmarshalValues(state.source.values)
+	if err != nil {
+		return nil, err
+	}
+	values, err := unmarshalValues(state.target.values)
+	if err != nil {
+		return nil, err
+	}
+	err = helper.ApplyValues(values, sourceValues, pointers)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	valuesJson, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	target := state.target
+	appMetrics := target.appMetrics
+	step := &promotionStep{resourceType: pipelineBean.DeploymentTemplate}
+	if target.properties == nil {
+		var createdId int
+		step.apply = func() error {
+			resp, err := impl.draftAwareConfigService.CreateEnvironmentPropertiesAndBaseIfNeeded(ctx, &pipelineBean.EnvironmentProperties{
+				AppId:             state.appId,
+				EnvironmentId:     target.env.Id,
+				ChartRefId:        target.chartRefId,
+				EnvOverrideValues: valuesJson,
+				AppMetrics:        &appMetrics,
+				Namespace:         target.env.Namespace,
+				MergeStrategy:     models.MERGE_STRATEGY_REPLACE,
+				IsOverride:        true,
+				Active:            true,
+				UserId:            userId,
+			}, userMetadata)
+			if err != nil {
+				return err
+			}
+			createdId = resp.Id
+			return nil
+		}
+		step.undo = func() error {
+			return impl.resetEnvironmentProperties(ctx, state.appId, target.env.Id, createdId, userId, userMetadata)
+		}
+		return step, nil
+	}
+	getPropertiesRequest := func(values json.RawMessage, mergeStrategy models.MergeStrategy) *pipelineBean.EnvironmentProperties {
+		request := *target.properties
+		request.AppId = state.appId
+		request.EnvOverrideValues = values
+		request.MergeStrategy = mergeStrategy
+		request.AppMetrics = &appMetrics
+		request.UserId = userId
+		return &request
+	}
+	step.apply = func() error {
+		_, err := impl.draftAwareConfigService.UpdateEnvironmentProperties(ctx, getPropertiesRequest(valuesJson, models.MERGE_STRATEGY_REPLACE), "", userMetadata)
+		return err
+	}
+	step.undo = func() error {
+		if !target.properties.IsOverride {
+			return impl.resetEnvironmentProperties(ctx, state.appId, target.env.Id, target.properties.Id, userId, userMetadata)
+		}
+		_, err := impl.draftAwareConfigService.UpdateEnvironmentProperties(ctx, getPropertiesRequest(target.properties.EnvOverrideValues, target.properties.MergeStrategy), "", userMetadata)
+		return err
+	}
+	return step, nil
+}
+
+func (impl *ConfigPromotionServiceImpl) resetEnvironmentProperties(ctx context.Context, appId, envId, id int, userId int32, userMetadata *userBean.UserMetadata) error {
+	_, err := impl.draftAwareConfigService.ResetEnvironmentProperties(ctx, &pipelineBean.EnvironmentProperties{
+		Id:            id,
+		AppId:         appId,
+		EnvironmentId: envId,
+		UserId:        userId,
+	}, userMetadata)
+	return err
+}
+
+func (impl *ConfigPromotionServiceImpl) getConfigDataStep(ctx context.Context, state *promotionState, resource *bean.PromotionItemIdentifier,
+	items []*bean.PromotionItem, userId int32, userMetadata *userBean.UserMetadata) (*promotionStep, error) {
+	sourceConfigs, targetConfigs := state.source.configMaps, state.target.configMaps
+	addUpdate, deleteConfig := impl.draftAwareConfigService.CMEnvironmentAddUpdate, impl.draftAwareConfigService.CMEnvironmentDelete
+	if resource.ResourceType == pipelineBean.CS {
+		sourceConfigs, targetConfigs = state.source.secrets, state.target.secrets
+		addUpdate, deleteConfig = impl.draftAwareConfigService.CSEnvironmentAddUpdate, impl.draftAwareConfigService.CSEnvironmentDelete
+	}
+	name := resource.ResourceName
+	sourceConfig, targetConfig := sourceConfigs[name], targetConfigs[name]
+	envLevelId, err := impl.getEnvLevelConfigId(resource.ResourceType, state.appId, state.target.env.Id)
+	if err != nil {
+		return nil, err
+	}
+	getRequest := func(configData *pipelineBean.ConfigData) *pipelineBean.ConfigDataRequest {
+		request := &pipelineBean.ConfigDataRequest{
+			Id:            envLevelId,
+			AppId:         state.appId,
+			EnvironmentId: state.target.env.Id,
+			UserId:        userId,
+		}
+		if configData != nil {
+			request.ConfigData = []*pipelineBean.ConfigData{configData}
+		}
+		return request
+	}
+	step := &promotionStep{resourceType: resource.ResourceType, resourceName: name}
+	if items[0].Key == "" && items[0].ChangeType == bean.ChangeTypeRemoved {
+		step.apply = func() error {
+			_, err := deleteConfig(ctx, name, getRequest(nil), userMetadata)
+			return err
+		}
+	} else {
+		var configData *pipelineBean.ConfigData
+		if items[0].Key == "" {
+			configData = effectiveConfigData(sourceConfig)
+		} else {
+			configData = effectiveConfigData(targetConfig)
+			sourceData, err := unmarshalValues(effectiveConfigData(sourceConfig).Data)
+			if err != nil {
+				return nil, err
+			}
+			data, err := unmarshalValues(configData.Data)
+			if err != nil {
+				return nil, err
+			}
+			keys := make([]string, 0, len(items))
+			for _, item := range items {
+				keys = append(keys, item.Key)
+			}
+			helper.ApplyKeys(data, sourceData, keys)
+			configData.Data, err = json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if targetConfig != nil && targetConfig.Global {
+			// the env config overrides the base config as a whole
+			configData.MergeStrategy = models.MERGE_STRATEGY_REPLACE
+		}
+		step.apply = func() error {
+			resp, err := addUpdate(ctx, getRequest(configData), userMetadata)
+			if err != nil {
+				return err
+			}
+			envLevelId = resp.Id
+			return nil
+		}
+	}
+	step.undo = func() error {
+		if targetConfig == nil || (targetConfig.Global && !targetConfig.Overridden) {
+			_, err := deleteConfig(ctx, name, getRequest(nil), userMetadata)
+			return err
+		}
+		_, err := addUpdate(ctx, getRequest(targetConfig), userMetadata)
+		return err
+	}
+	return step, nil
+}
+
+// getEnvLevelConfigId returns the id of the config maps or secrets saved on the env, 0 if none is saved yet
+func (impl *ConfigPromotionServiceImpl) getEnvLevelConfigId(resourceType pipelineBean.ResourceType, appId, envId int) (int, error) {
+	fetch := impl.configMapService.CMEnvironmentFetch
+	if resourceType == pipelineBean.CS {
+		fetch = impl.configMapService.CSEnvironmentFetch
+	}
+	configDataRequest, err := fetch(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching env level config", "resourceType", resourceType, "appId", appId, "envId", envId, "err", err)
+		return 0, err
+	}
+	if configDataRequest == nil {
+		return 0, nil
+	}
+	return configDataRequest.Id, nil
+}
+
+// getStrategyStep makes the default strategy of the source the default strategy of the target, the strategies are
+// saved as a draft if the target env is config protected
+func (impl *ConfigPromotionServiceImpl) getStrategyStep(ctx context.Context, state *promotionState, userId int32) *promotionStep {
+	sourceStrategy := getDefaultStrategy(state.source.strategies)
+	strategies := make([]pkgBean.Strategy, 0, len(state.target.strategies)+1)
+	found := false
+	for _, strategy := range state.target.strategies {
+		strategy.Default = false
+		if strategy.DeploymentTemplate == sourceStrategy.DeploymentTemplate {
+			strategy.Config = sourceStrategy.Config
+			strategy.Default = true
+			found = true
+		}
+		strategies = append(strategies, strategy)
+	}
+	if !found {
+		strategies = append(strategies, *sourceStrategy)
+	}
+	pipelineId := state.target.pipeline.Id
+	updateStrategies := func(strategies []pkgBean.Strategy) error {
+		_, err := impl.draftAwareConfigService.UpdatePipelineStrategies(ctx, state.appId, state.target.env.Id, pipelineId, strategies, userId)
+		return err
+	}
+	return &promotionStep{
+		resourceType: pipelineBean.PipelineStrategy,
+		apply: func() error {
+			return updateStrategies(strategies)
+		},
+		undo: func() error {
+			return updateStrategies(state.target.strategies)
+		},
+	}
+}
+
+func (impl *ConfigPromotionServiceImpl) discardDraft(appId, envId int, resourceType pipelineBean.ResourceType, resourceName string, userId int32) error {
+	drafts, err := impl.configDraftService.GetDrafts(appId, envId, []draftBean.DraftState{draftBean.DraftStateAwaitingApproval})
+	if err != nil {
+		return err
+	}
+	for _, draft := range drafts {
+		if draft.ResourceType != resourceType || draft.ResourceName != resourceName || draft.LatestVersion == nil {
+			continue
+		}
+		_, err = impl.configDraftService.DiscardDraft(&draftBean.ConfigDraftActionRequest{
+			DraftId:        draft.Id,
+			DraftVersionId: draft.LatestVersion.Id,
+			UserId:         userId,
+		})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configPromotion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRevertSteps(t *testing.T) {
+	impl := &ConfigPromotionServiceImpl{logger: zap.NewNop().Sugar()}
+	state := &promotionState{appId: 1, target: &envConfig{env: &repository.Environment{Id: 2}}}
+	var reverted []string
+	getStep := func(resourceType pipelineBean.ResourceType, resourceName string, undoErr error) *promotionStep {
+		step := &promotionStep{resourceType: resourceType, resourceName: resourceName}
+		step.undo = func() error {
+			reverted = append(reverted, step.String())
+			return undoErr
+		}
+		return step
+	}
+	steps := []*promotionStep{
+		getStep(pipelineBean.DeploymentTemplate, "", nil),
+		getStep(pipelineBean.CM, "app-config", errors.New("db down")),
+		getStep(pipelineBean.PipelineStrategy, "", errors.New("db down")),
+	}
+	notReverted := impl.revertSteps(steps, state)
+	assert.Equal(t, []string{"Pipeline Strategy", "ConfigMap app-config", "Deployment Template"}, reverted, "steps are reverted latest first")
+	assert.Equal(t, []string{"Pipeline Strategy", "ConfigMap app-config"}, notReverted)
+	assert.Empty(t, impl.revertSteps(steps[:1], state))
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
+type ChangeType string
+
+const (
+	// ChangeTypeAdded the key is set in the source env only, promoting it adds it to the target env
+	ChangeTypeAdded ChangeType = "Added"
+	// ChangeTypeModified the key is set in both the envs with different values
+	ChangeTypeModified ChangeType = "Modified"
+	// ChangeTypeRemoved the key is set in the target env only, promoting it removes it from the target env
+	ChangeTypeRemoved ChangeType = "Removed"
+)
+
+// PromotionItemIdentifier identifies a promotable change. Key is the json pointer of the value in the deployment
+// template (like /resources/limits/cpu) or the data key of the config map or secret. It is empty for the changes
+// promoted as a whole, i.e. the pipeline strategy, the config maps and secrets present in one env only and the ones
+// differing in more than their data, like external ones or the ones with another mount path.
+type PromotionItemIdentifier struct {
+	ResourceType pipelineBean.ResourceType `json:"resourceType" validate:"required"`
+	ResourceName string                    `json:"resourceName,omitempty"`
+	Key          string                    `json:"key,omitempty"`
+}
+
+func (r *PromotionItemIdentifier) String() string {
+	return fmt.Sprintf("%s/%s/%s", r.ResourceType, r.ResourceName, r.Key)
+}
+
+type PromotionItem struct {
+	PromotionItemIdentifier
+	ChangeType ChangeType  `json:"changeType"`
+	Source     interface{} `json:"source,omitempty"`
+	Target     interface{} `json:"target,omitempty"`
+}
+
+type PromotionDiffRequest struct {
+	AppId       int   `json:"appId" validate:"required,number,gt=0"`
+	SourceEnvId int   `json:"sourceEnvId" validate:"required,number,gt=0"`
+	TargetEnvId int   `json:"targetEnvId" validate:"required,number,gt=0,nefield=SourceEnvId"`
+	UserId      int32 `json:"-"`
+}
+
+// PromotionDiff is the published config of the source env compared with the target env, secret values are masked
+// for the users without admin access
+type PromotionDiff struct {
+	AppId       int `json:"appId"`
+	SourceEnvId int `json:"sourceEnvId"`
+	TargetEnvId int `json:"targetEnvId"`
+	// TargetConfigProtected the promoted deployment template, config maps and secrets are saved as drafts to be approved
+	TargetConfigProtected bool             `json:"targetConfigProtected"`
+	Items                 []*PromotionItem `json:"items"`
+	// Warnings lists the configs which are not compared, like the deployment templates of different charts
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type PromotionRequest struct {
+	PromotionDiffRequest
+	Items []*PromotionItemIdentifier `json:"items" validate:"required,min=1,dive"`
+}
+
+type PromotionResponse struct {
+	Promoted []*PromotionItemIdentifier `json:"promoted"`
+	// SavedAsDraft is set if the target env is config protected, the changes are published once their drafts are approved
+	SavedAsDraft bool `json:"savedAsDraft"`
+}
+
+// SecretMaskedValue replaces the secret values in the diff for the users without admin access
+const SecretMaskedValue = "********"
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion/bean"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// DiffValues compares the deployment template values leaf by leaf, the items are keyed by the json pointer of the
+// leaf. Lists are compared as a whole.
+func DiffValues(source, target map[string]interface{}) []*bean.PromotionItem {
+	items := make([]*bean.PromotionItem, 0)
+	diffValues("", source, target, &items)
+	return items
+}
+
+func diffValues(pointer string, source, target map[string]interface{}, items *[]*bean.PromotionItem) {
+	for _, key := range unionKeys(source, target) {
+		keyPointer := pointer + "/" + pointerEscaper.Replace(key)
+		sourceValue, inSource := source[key]
+		targetValue, inTarget := target[key]
+		sourceMap, isSourceMap := sourceValue.(map[string]interface{})
+		targetMap, isTargetMap := targetValue.(map[string]interface{})
+		switch {
+		case isSourceMap && isTargetMap:
+			diffValues(keyPointer, sourceMap, targetMap, items)
+		case isSourceMap && !inTarget:
+			diffValues(keyPointer, sourceMap, nil, items)
+		case isTargetMap && !inSource:
+			diffValues(keyPointer, nil, targetMap, items)
+		default:
+			if item := diffValue(keyPointer, sourceValue, inSource, targetValue, inTarget); item != nil {
+				*items = append(*items, item)
+			}
+		}
+	}
+}
+
+// DiffKeys compares the top level keys only, like the data keys of config maps and secrets
+func DiffKeys(source, target map[string]interface{}) []*bean.PromotionItem {
+	items := make([]*bean.PromotionItem, 0)
+	for _, key := range unionKeys(source, target) {
+		sourceValue, inSource := source[key]
+		targetValue, inTarget := target[key]
+		if item := diffValue(key, sourceValue, inSource, targetValue, inTarget); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func diffValue(key string, sourceValue interface{}, inSource bool, targetValue interface{}, inTarget bool) *bean.PromotionItem {
+	item := &bean.PromotionItem{
+		PromotionItemIdentifier: bean.PromotionItemIdentifier{Key: key},
+		Source:                  sourceValue,
+		Target:                  targetValue,
+	}
+	switch {
+	case !inTarget:
+		item.ChangeType = bean.ChangeTypeAdded
+	case !inSource:
+		item.ChangeType = bean.ChangeTypeRemoved
+	case !reflect.DeepEqual(sourceValue, targetValue):
+		item.ChangeType = bean.ChangeTypeModified
+	default:
+		return nil
+	}
+	return item
+}
+
+// ApplyValues sets the leaves of target at the json pointers to their values in source, the leaves missing in source
+// are removed from target. The maps on the way to a leaf are created in target if missing.
+func ApplyValues(target, source map[string]interface{}, pointers []string) error {
+	for _, pointer := range pointers {
+		if !strings.HasPrefix(pointer, "/") {
+			return fmt.Errorf("invalid json pointer %q", pointer)
+		}
+		keys := strings.Split(pointer[1:], "/")
+		for i := range keys {
+			keys[i] = pointerUnescaper.Replace(keys[i])
+		}
+		sourceValue, inSource := lookup(source, keys)
+		if inSource {
+			set(target, keys, sourceValue)
+		} else {
+			remove(target, keys)
+		}
+	}
+	return nil
+}
+
+// ApplyKeys sets the top level keys of target to their values in source, the keys missing in source are removed
+func ApplyKeys(target, source map[string]interface{}, keys []string) {
+	for _, key := range keys {
+		if sourceValue, ok := source[key]; ok {
+			target[key] = sourceValue
+		} else {
+			delete(target, key)
+		}
+	}
+}
+
+func lookup(values map[string]interface{}, keys []string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range keys {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = currentMap[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func set(values map[string]interface{}, keys []string, value interface{}) {
+	current := values
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// remove deletes the leaf and the maps left empty on the way to it
+func remove(values map[string]interface{}, keys []string) {
+	if len(keys) == 1 {
+		delete(values, keys[0])
+		return
+	}
+	next, ok := values[keys[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	remove(next, keys[1:])
+	if len(next) == 0 {
+		delete(values, keys[0])
+	}
+}
+
+func unionKeys(source, target map[string]interface{}) []string {
+	keys := make([]string, 0, len(source)+len(target))
+	for key := range source {
+		keys = append(keys, key)
+	}
+	for key := range target {
+		if _, ok := source[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/config/configPromotion/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const sourceValues = `{
+  "replicaCount": 3,
+  "resources": {"limits": {"cpu": "1", "memory": "1Gi"}},
+  "ingress": {"enabled": true, "hosts": ["a.example.com"]},
+  "app/name": "payments"
+}`
+
+const targetValues = `{
+  "replicaCount": 1,
+  "resources": {"limits": {"cpu": "1"}},
+  "ingress": {"enabled": true, "hosts": ["b.example.com"]},
+  "debug": {"enabled": true}
+}`
+
+func unmarshal(t *testing.T, values string) map[string]interface{} {
+	result := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(values), &result))
+	return result
+}
+
+func TestDiffValues(t *testing.T) {
+	items := DiffValues(unmarshal(t, sourceValues), unmarshal(t, targetValues))
+	changes := make(map[string]bean.ChangeType)
+	for _, item := range items {
+		changes[item.Key] = item.ChangeType
+	}
+	assert.Equal(t, map[string]bean.ChangeType{
+		"/app~1name":               bean.ChangeTypeAdded,
+		"/debug/enabled":           bean.ChangeTypeRemoved,
+		"/ingress/hosts":           bean.ChangeTypeModified,
+		"/replicaCount":            bean.ChangeTypeModified,
+		"/resources/limits/memory": bean.ChangeTypeAdded,
+	}, changes)
+	assert.Equal(t, "/app~1name", items[0].Key, "items are sorted by key")
+}
+
+func TestApplyValues(t *testing.T) {
+	source := unmarshal(t, sourceValues)
+	target := unmarshal(t, targetValues)
+	err := ApplyValues(target, source, []string{"/app~1name", "/debug/enabled", "/resources/limits/memory"})
+	assert.NoError(t, err)
+	assert.Equal(t, unmarshal(t, `{
+  "replicaCount": 1,
+  "resources": {"limits": {"cpu": "1", "memory": "1Gi"}},
+  "ingress": {"enabled": true, "hosts": ["b.example.com"]},
+  "app/name": "payments"
+}`), target)
+	assert.Error(t, ApplyValues(target, source, []string{"replicaCount"}))
+}
+
+func TestDiffAndApplyKeys(t *testing.T) {
+	source := map[string]interface{}{"LOG_LEVEL": "info", "DB_HOST": "db"}
+	target := map[string]interface{}{"LOG_LEVEL": "debug", "FEATURE_X": "on"}
+	items := DiffKeys(source, target)
+	assert.Len(t, items, 3)
+	assert.Equal(t, "DB_HOST", items[0].Key)
+	assert.Equal(t, bean.ChangeTypeAdded, items[0].ChangeType)
+	assert.Equal(t, bean.ChangeTypeRemoved, items[1].ChangeType)
+	assert.Equal(t, bean.ChangeTypeModified, items[2].ChangeType)
+
+	ApplyKeys(target, source, []string{"FEATURE_X", "LOG_LEVEL"})
+	assert.Equal(t, map[string]interface{}{"LOG_LEVEL": "info"}, target)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoRollback
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type AutoRollbackConfigService interface {
+	// GetConfig returns nil if auto rollback is not configured for the pipeline
+	GetConfig(pipelineId int) (*bean.AutoRollbackConfig, error)
+	ValidateConfig(config *bean.AutoRollbackConfig) error
+	SaveConfig(pipelineId int, config *bean.AutoRollbackConfig, userId int32) error
+	DeleteConfig(pipelineId int, userId int32) error
+}
+
+type AutoRollbackConfigServiceImpl struct {
+	logger                 *zap.SugaredLogger
+	autoRollbackRepository repository.AutoRollbackRepository
+}
+
+func NewAutoRollbackConfigServiceImpl(logger *zap.SugaredLogger,
+	autoRollbackRepository repository.AutoRollbackRepository) *AutoRollbackConfigServiceImpl {
+	return &AutoRollbackConfigServiceImpl{
+		logger:                 logger,
+		autoRollbackRepository: autoRollbackRepository,
+	}
+}
+
+func (impl *AutoRollbackConfigServiceImpl) GetConfig(pipelineId int) (*bean.AutoRollbackConfig, error) {
+	model, err := impl.autoRollbackRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching auto rollback config", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	return &bean.AutoRollbackConfig{
+		Enabled:            model.Enabled,
+		WindowInMinutes:    model.WindowInMinutes,
+		OnDegraded:         model.OnDegraded,
+		OnPostStageFailure: model.OnPostStageFailure,
+	}, nil
+}
+
+func (impl *AutoRollbackConfigServiceImpl) ValidateConfig(config *bean.AutoRollbackConfig) error {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+	if config.WindowInMinutes < 0 || config.WindowInMinutes > bean.MaxWindowInMinutes {
+		errMsg := "auto rollback window must be between 1 and 1440 minutes"
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if !config.OnDegraded && !config.OnPostStageFailure {
+		errMsg := "auto rollback needs at least one of onDegraded or onPostStageFailure to be enabled"
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *AutoRollbackConfigServiceImpl) SaveConfig(pipelineId int, config *bean.AutoRollbackConfig, userId int32) error {
+	err := impl.ValidateConfig(config)
+	if err != nil {
+		return err
+	}
+	model, err := impl.autoRollbackRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching auto rollback config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	isNew := model == nil || model.Id == 0
+	if isNew {
+		model = &repository.CdPipelineAutoRollbackConfig{
+			PipelineId: pipelineId,
+			Active:     true,
+			AuditLog:   sql.NewDefaultAuditLog(userId),
+		}
+	}
+	model.Enabled = config.Enabled
+	model.WindowInMinutes = config.GetWindowInMinutes()
+	model.OnDegraded = config.OnDegraded
+	model.OnPostStageFailure = config.OnPostStageFailure
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	if isNew {
+		err = impl.autoRollbackRepository.SaveConfig(model)
+	} else {
+		err = impl.autoRollbackRepository.UpdateConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackConfigServiceImpl) DeleteConfig(pipelineId int, userId int32) error {
+	model, err := impl.autoRollbackRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching auto rollback config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.autoRollbackRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting auto rollback config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoRollback
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	impl := NewAutoRollbackConfigServiceImpl(zap.NewNop().Sugar(), nil)
+	assert.Nil(t, impl.ValidateConfig(nil))
+	// disabled config is not validated, it is saved to retain the selections
+	assert.Nil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: false}))
+	assert.Nil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: true, OnDegraded: true}))
+	assert.Nil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: true, WindowInMinutes: 30, OnPostStageFailure: true}))
+
+	assert.NotNil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: true, WindowInMinutes: 30}))
+	assert.NotNil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: true, WindowInMinutes: -1, OnDegraded: true}))
+	assert.NotNil(t, impl.ValidateConfig(&bean.AutoRollbackConfig{Enabled: true, WindowInMinutes: bean.MaxWindowInMinutes + 1, OnDegraded: true}))
+}
+
+func TestGetWindowInMinutes(t *testing.T) {
+	assert.Equal(t, bean.DefaultWindowInMinutes, (&bean.AutoRollbackConfig{}).GetWindowInMinutes())
+	assert.Equal(t, 45, (&bean.AutoRollbackConfig{WindowInMinutes: 45}).GetWindowInMinutes())
+}
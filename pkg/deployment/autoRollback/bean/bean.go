@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+const (
+	DefaultWindowInMinutes = 15
+	MaxWindowInMinutes     = 1440
+)
+
+// AutoRollbackConfig is the opt-in policy of a cd pipeline to redeploy the previous successful
+// deployment if a deployment goes bad within WindowInMinutes of being successful
+type AutoRollbackConfig struct {
+	Enabled bool `json:"enabled"`
+	// WindowInMinutes is the duration after a successful deployment for which the app is watched, DefaultWindowInMinutes is used if not set
+	WindowInMinutes int `json:"windowInMinutes" validate:"omitempty,min=1,max=1440"`
+	// OnDegraded rolls back if the app status becomes Degraded within the window
+	OnDegraded bool `json:"onDegraded"`
+	// OnPostStageFailure rolls back if the post-deployment stage fails within the window
+	OnPostStageFailure bool `json:"onPostStageFailure"`
+}
+
+func (config *AutoRollbackConfig) GetWindowInMinutes() int {
+	if config.WindowInMinutes <= 0 {
+		return DefaultWindowInMinutes
+	}
+	return config.WindowInMinutes
+}
+
+type RollbackReason string
+
+const (
+	RollbackReasonDegraded          RollbackReason = "application became Degraded"
+	RollbackReasonPostStageFailed   RollbackReason = "post-deployment stage failed"
+	RollbackReasonNoPreviousRelease string         = "no previous successful deployment found to roll back to"
+)
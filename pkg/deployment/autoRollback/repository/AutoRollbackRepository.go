@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type WatchStatus string
+
+const (
+	WatchStatusWatching       WatchStatus = "WATCHING"
+	WatchStatusRollingBack    WatchStatus = "ROLLING_BACK"
+	WatchStatusRolledBack     WatchStatus = "ROLLED_BACK"
+	WatchStatusRollbackFailed WatchStatus = "ROLLBACK_FAILED"
+	WatchStatusExpired        WatchStatus = "EXPIRED"
+	WatchStatusSuperseded     WatchStatus = "SUPERSEDED"
+	WatchStatusCancelled      WatchStatus = "CANCELLED"
+)
+
+type CdPipelineAutoRollbackConfig struct {
+	tableName          struct{} `sql:"cd_pipeline_auto_rollback_config" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	PipelineId         int      `sql:"pipeline_id,notnull"`
+	Enabled            bool     `sql:"enabled,notnull"`
+	WindowInMinutes    int      `sql:"window_in_minutes,notnull"`
+	OnDegraded         bool     `sql:"on_degraded,notnull"`
+	OnPostStageFailure bool     `sql:"on_post_stage_failure,notnull"`
+	Active             bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CdPipelineAutoRollbackWatch watches a successful deployment (CdWorkflowRunnerId) till WatchUntil,
+// TargetCdWorkflowRunnerId is the deployment redeployed as RollbackCdWorkflowRunnerId if it goes bad
+type CdPipelineAutoRollbackWatch struct {
+	tableName                  struct{}    `sql:"cd_pipeline_auto_rollback_watch" pg:",discard_unknown_columns"`
+	Id                         int         `sql:"id,pk"`
+	PipelineId                 int         `sql:"pipeline_id,notnull"`
+	CdWorkflowId               int         `sql:"cd_workflow_id,notnull"`
+	CdWorkflowRunnerId         int         `sql:"cd_workflow_runner_id,notnull"`
+	WatchUntil                 time.Time   `sql:"watch_until,notnull"`
+	Status                     WatchStatus `sql:"status,notnull"`
+	Reason                     string      `sql:"reason"`
+	TargetCdWorkflowRunnerId   int         `sql:"target_cd_workflow_runner_id"`
+	RollbackCdWorkflowRunnerId int         `sql:"rollback_cd_workflow_runner_id"`
+	Message                    string      `sql:"message"`
+	sql.AuditLog
+}
+
+type AutoRollbackRepository interface {
+	FindConfigByPipelineId(pipelineId int) (*CdPipelineAutoRollbackConfig, error)
+	SaveConfig(model *CdPipelineAutoRollbackConfig) error
+	UpdateConfig(model *CdPipelineAutoRollbackConfig) error
+
+	SaveWatch(model *CdPipelineAutoRollbackWatch) error
+	UpdateWatch(model *CdPipelineAutoRollbackWatch) error
+	FindWatchesByStatus(status WatchStatus) ([]*CdPipelineAutoRollbackWatch, error)
+	// ClaimWatch moves the watch from status expected to status claimed, returns false if the watch
+	// was already moved (by another replica)
+	ClaimWatch(id int, expected, claimed WatchStatus) (bool, error)
+	// SupersedeWatches marks the running watches of the pipeline superseded by a newer deployment
+	SupersedeWatches(pipelineId int, userId int32) error
+	ExistsByCdWorkflowRunnerId(cdWorkflowRunnerId int) (bool, error)
+	ExistsByRollbackCdWorkflowRunnerId(cdWorkflowRunnerId int) (bool, error)
+}
+
+type AutoRollbackRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAutoRollbackRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AutoRollbackRepositoryImpl {
+	return &AutoRollbackRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *AutoRollbackRepositoryImpl) FindConfigByPipelineId(pipelineId int) (*CdPipelineAutoRollbackConfig, error) {
+	model := &CdPipelineAutoRollbackConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *AutoRollbackRepositoryImpl) SaveConfig(model *CdPipelineAutoRollbackConfig) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *AutoRollbackRepositoryImpl) UpdateConfig(model *CdPipelineAutoRollbackConfig) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *AutoRollbackRepositoryImpl) SaveWatch(model *CdPipelineAutoRollbackWatch) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *AutoRollbackRepositoryImpl) UpdateWatch(model *CdPipelineAutoRollbackWatch) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *AutoRollbackRepositoryImpl) FindWatchesByStatus(status WatchStatus) ([]*CdPipelineAutoRollbackWatch, error) {
+	var models []*CdPipelineAutoRollbackWatch
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *AutoRollbackRepositoryImpl) ClaimWatch(id int, expected, claimed WatchStatus) (bool, error) {
+	res, err := impl.dbConnection.Model(&CdPipelineAutoRollbackWatch{}).
+		Set("status = ?", claimed).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", expected).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) SupersedeWatches(pipelineId int, userId int32) error {
+	_, err := impl.dbConnection.Model(&CdPipelineAutoRollbackWatch{}).
+		Set("status = ?", WatchStatusSuperseded).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", WatchStatusWatching).
+		Update()
+	return err
+}
+
+func (impl *AutoRollbackRepositoryImpl) ExistsByCdWorkflowRunnerId(cdWorkflowRunnerId int) (bool, error) {
+	return impl.dbConnection.Model(&CdPipelineAutoRollbackWatch{}).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Exists()
+}
+
+func (impl *AutoRollbackRepositoryImpl) ExistsByRollbackCdWorkflowRunnerId(cdWorkflowRunnerId int) (bool, error) {
+	return impl.dbConnection.Model(&CdPipelineAutoRollbackWatch{}).
+		Where("rollback_cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Exists()
+}
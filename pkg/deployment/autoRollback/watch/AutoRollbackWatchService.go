@@ -0,0 +1,297 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	argoApplication "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/timelineStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+type AutoRollbackWatchService interface {
+	// StartWatch starts watching the successful deployment cdWorkflowRunnerId of the pipeline if auto rollback
+	// is enabled for it, watches of the older deployments of the pipeline are superseded
+	StartWatch(pipelineId, cdWorkflowId, cdWorkflowRunnerId int) error
+	// ProcessWatches rolls back the watched deployments which went bad and closes the watches past their window
+	ProcessWatches()
+	// RollbackToLastSuccessfulDeployment redeploys the artifact and configuration of the last successful deployment of
+	// the pipeline before cdWorkflowRunnerId, returns the redeployed runner and the id of the rollback deployment runner
+	RollbackToLastSuccessfulDeployment(pipelineId, appId, cdWorkflowRunnerId int) (*pipelineConfig.CdWorkflowRunner, int, error)
+}
+
+type AutoRollbackWatchServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	autoRollbackRepository        repository.AutoRollbackRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	appStatusRepository           appStatus.AppStatusRepository
+	pipelineStatusTimelineService status.PipelineStatusTimelineService
+	cdHandlerService              devtronApps.HandlerService
+}
+
+func NewAutoRollbackWatchServiceImpl(logger *zap.SugaredLogger,
+	autoRollbackRepository repository.AutoRollbackRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	appStatusRepository appStatus.AppStatusRepository,
+	pipelineStatusTimelineService status.PipelineStatusTimelineService,
+	cdHandlerService devtronApps.HandlerService) *AutoRollbackWatchServiceImpl {
+	return &AutoRollbackWatchServiceImpl{
+		logger:                        logger,
+		autoRollbackRepository:        autoRollbackRepository,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		appStatusRepository:           appStatusRepository,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+		cdHandlerService:              cdHandlerService,
+	}
+}
+
+// succeededDeployStatuses are the statuses of the deployments which can be rolled back to
+var succeededDeployStatuses = []string{argoApplication.Healthy, argoApplication.SUCCEEDED}
+
+var failedStageStatuses = []string{cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowTimedOut, string(v1alpha1.WorkflowError)}
+
+func (impl *AutoRollbackWatchServiceImpl) StartWatch(pipelineId, cdWorkflowId, cdWorkflowRunnerId int) error {
+	config, err := impl.autoRollbackRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching auto rollback config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	// older watches are closed irrespective of the config as the watched deployments are no longer live
+	err = impl.autoRollbackRepository.SupersedeWatches(pipelineId, userBean.SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in superseding auto rollback watches", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	if config == nil || config.Id == 0 || !config.Enabled {
+		return nil
+	}
+	// a rollback deployment is not watched, else a bad rollback would roll back to the deployment it replaced
+	isRollback, err := impl.autoRollbackRepository.ExistsByRollbackCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in checking auto rollback deployment", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return err
+	}
+	if isRollback {
+		return nil
+	}
+	alreadyWatched, err := impl.autoRollbackRepository.ExistsByCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in checking auto rollback watch", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return err
+	}
+	if alreadyWatched {
+		return nil
+	}
+	watch := &repository.CdPipelineAutoRollbackWatch{
+		PipelineId:         pipelineId,
+		CdWorkflowId:       cdWorkflowId,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		WatchUntil:         time.Now().Add(time.Duration(config.WindowInMinutes) * time.Minute),
+		Status:             repository.WatchStatusWatching,
+		AuditLog:           sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+	}
+	err = impl.autoRollbackRepository.SaveWatch(watch)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback watch", "pipelineId", pipelineId, "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackWatchServiceImpl) ProcessWatches() {
+	watches, err := impl.autoRollbackRepository.FindWatchesByStatus(repository.WatchStatusWatching)
+	if err != nil {
+		impl.logger.Errorw("error in fetching auto rollback watches", "err", err)
+		return
+	}
+	for _, watch := range watches {
+		err = impl.processWatch(watch)
+		if err != nil {
+			impl.logger.Errorw("error in processing auto rollback watch", "watchId", watch.Id, "pipelineId", watch.PipelineId, "err", err)
+		}
+	}
+}
+
+func (impl *AutoRollbackWatchServiceImpl) processWatch(watch *repository.CdPipelineAutoRollbackWatch) error {
+	config, err := impl.autoRollbackRepository.FindConfigByPipelineId(watch.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	if config == nil || config.Id == 0 || !config.Enabled {
+		return impl.closeWatch(watch, repository.WatchStatusCancelled, "auto rollback disabled for the pipeline")
+	}
+	latestRunner, err := impl.cdWorkflowRepository.FindLatestByPipelineIdAndRunnerType(watch.PipelineId, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			// pipeline deleted
+			return impl.closeWatch(watch, repository.WatchStatusCancelled, "deployment not found")
+		}
+		return err
+	}
+	if latestRunner.Id != watch.CdWorkflowRunnerId {
+		return impl.closeWatch(watch, repository.WatchStatusSuperseded, "superseded by a newer deployment")
+	}
+	reason, err := impl.getRollbackReason(watch, config, &latestRunner)
+	if err != nil {
+		return err
+	}
+	if len(reason) == 0 {
+		if time.Now().After(watch.WatchUntil) {
+			return impl.closeWatch(watch, repository.WatchStatusExpired, "")
+		}
+		return nil
+	}
+	claimed, err := impl.autoRollbackRepository.ClaimWatch(watch.Id, repository.WatchStatusWatching, repository.WatchStatusRollingBack)
+	if err != nil || !claimed {
+		return err
+	}
+	watch.Status = repository.WatchStatusRollingBack
+	watch.Reason = string(reason)
+	return impl.rollback(watch, &latestRunner)
+}
+
+func (impl *AutoRollbackWatchServiceImpl) getRollbackReason(watch *repository.CdPipelineAutoRollbackWatch, config *repository.CdPipelineAutoRollbackConfig,
+	deployRunner *pipelineConfig.CdWorkflowRunner) (bean.RollbackReason, error) {
+	if config.OnPostStageFailure {
+		postRunner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(context.Background(), watch.CdWorkflowId, apiBean.CD_WORKFLOW_TYPE_POST)
+		if err != nil && !util.IsErrNoRows(err) {
+			return "", err
+		}
+		if postRunner.Id > 0 && slices.Contains(failedStageStatuses, postRunner.Status) {
+			return bean.RollbackReasonPostStageFailed, nil
+		}
+	}
+	if config.OnDegraded && deployRunner.CdWorkflow != nil && deployRunner.CdWorkflow.Pipeline != nil {
+		pipeline := deployRunner.CdWorkflow.Pipeline
+		appStatusContainer, err := impl.appStatusRepository.Get(pipeline.AppId, pipeline.EnvironmentId)
+		if err != nil && !util.IsErrNoRows(err) {
+			return "", err
+		}
+		// status updated before the watch started belongs to the previous deployment
+		if appStatusContainer.Status == string(health.HealthStatusDegraded) && appStatusContainer.UpdatedOn.After(watch.CreatedOn) {
+			return bean.RollbackReasonDegraded, nil
+		}
+	}
+	return "", nil
+}
+
+func (impl *AutoRollbackWatchServiceImpl) rollback(watch *repository.CdPipelineAutoRollbackWatch, deployRunner *pipelineConfig.CdWorkflowRunner) error {
+	target, rollbackWfrId, err := impl.RollbackToLastSuccessfulDeployment(watch.PipelineId, deployRunner.CdWorkflow.Pipeline.AppId, watch.CdWorkflowRunnerId)
+	if target != nil {
+		watch.TargetCdWorkflowRunnerId = target.Id
+	}
+	// the failed rollback deployment (if created) is marked so that it is not watched
+	watch.RollbackCdWorkflowRunnerId = rollbackWfrId
+	if err != nil {
+		return impl.failRollback(watch, err.Error())
+	}
+	watch.Status = repository.WatchStatusRolledBack
+	watch.Message = fmt.Sprintf("Auto rollback triggered as %s, redeployed the artifact and configuration of deployment %d.", watch.Reason, target.Id)
+	if target.CdWorkflow.CiArtifact != nil {
+		watch.Message = fmt.Sprintf("Auto rollback triggered as %s, redeployed image %s with the configuration of deployment %d.", watch.Reason, target.CdWorkflow.CiArtifact.Image, target.Id)
+	}
+	impl.saveTimeline(watch, timelineStatus.TIMELINE_STATUS_AUTO_ROLLBACK_TRIGGERED)
+	return impl.updateWatch(watch)
+}
+
+func (impl *AutoRollbackWatchServiceImpl) RollbackToLastSuccessfulDeployment(pipelineId, appId, cdWorkflowRunnerId int) (*pipelineConfig.CdWorkflowRunner, int, error) {
+	target, err := impl.cdWorkflowRepository.FindLastDeployRunnerBeforeByStatus(pipelineId, cdWorkflowRunnerId, succeededDeployStatuses)
+	if err != nil {
+		if !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching previous successful deployment", "pipelineId", pipelineId, "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		}
+		return nil, 0, errors.New(bean.RollbackReasonNoPreviousRelease)
+	}
+	overrideRequest := &apiBean.ValuesOverrideRequest{
+		PipelineId:                            pipelineId,
+		AppId:                                 appId,
+		CiArtifactId:                          target.CdWorkflow.CiArtifactId,
+		CdWorkflowType:                        apiBean.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentType:                        models.DEPLOYMENTTYPE_DEPLOY,
+		DeploymentWithConfig:                  apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER,
+		WfrIdForDeploymentWithSpecificTrigger: target.Id,
+		IsRollbackDeployment:                  true,
+		UserId:                                userBean.SYSTEM_USER_ID,
+	}
+	userMetadata := &userBean.UserMetadata{
+		UserId:           userBean.SYSTEM_USER_ID,
+		IsUserSuperAdmin: true,
+	}
+	triggerContext := triggerBean.TriggerContext{Context: context.Background()}
+	_, _, _, err = impl.cdHandlerService.ManualCdTrigger(triggerContext, overrideRequest, userMetadata)
+	if err != nil {
+		impl.logger.Errorw("error in triggering rollback", "pipelineId", pipelineId, "targetCdWorkflowRunnerId", target.Id, "err", err)
+		return target, overrideRequest.WfrId, err
+	}
+	return target, overrideRequest.WfrId, nil
+}
+
+func (impl *AutoRollbackWatchServiceImpl) failRollback(watch *repository.CdPipelineAutoRollbackWatch, message string) error {
+	watch.Status = repository.WatchStatusRollbackFailed
+	watch.Message = fmt.Sprintf("Auto rollback (%s) failed: %s.", watch.Reason, message)
+	impl.saveTimeline(watch, timelineStatus.TIMELINE_STATUS_AUTO_ROLLBACK_FAILED)
+	return impl.updateWatch(watch)
+}
+
+func (impl *AutoRollbackWatchServiceImpl) closeWatch(watch *repository.CdPipelineAutoRollbackWatch, watchStatus repository.WatchStatus, message string) error {
+	claimed, err := impl.autoRollbackRepository.ClaimWatch(watch.Id, repository.WatchStatusWatching, watchStatus)
+	if err != nil || !claimed || len(message) == 0 {
+		return err
+	}
+	watch.Status = watchStatus
+	watch.Message = message
+	return impl.updateWatch(watch)
+}
+
+func (impl *AutoRollbackWatchServiceImpl) updateWatch(watch *repository.CdPipelineAutoRollbackWatch) error {
+	watch.UpdatedOn = time.Now()
+	watch.UpdatedBy = userBean.SYSTEM_USER_ID
+	err := impl.autoRollbackRepository.UpdateWatch(watch)
+	if err != nil {
+		impl.logger.Errorw("error in updating auto rollback watch", "watchId", watch.Id, "status", watch.Status, "err", err)
+		return err
+	}
+	return nil
+}
+
+// saveTimeline records the rollback on the timeline of the watched deployment
+func (impl *AutoRollbackWatchServiceImpl) saveTimeline(watch *repository.CdPipelineAutoRollbackWatch, timelineStatus timelineStatus.TimelineStatus) {
+	timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(watch.CdWorkflowRunnerId, timelineStatus, watch.Message, userBean.SYSTEM_USER_ID)
+	err := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback timeline", "cdWorkflowRunnerId", watch.CdWorkflowRunnerId, "status", timelineStatus, "err", err)
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"go.uber.org/zap"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+const (
+	canaryStrategyPath      = "deployment.strategy.canary"
+	canaryStrategyStepsPath = "deployment.strategy.canary.steps"
+)
+
+type CanaryAnalysisService interface {
+	// GetConfig returns nil if canary analysis is not configured for the pipeline
+	GetConfig(pipelineId int) (*bean.CanaryAnalysisConfig, error)
+	ValidateConfig(config *bean.CanaryAnalysisConfig) error
+	SaveConfig(pipelineId int, config *bean.CanaryAnalysisConfig, userId int32) error
+	DeleteConfig(pipelineId int, userId int32) error
+	// ApplyCanarySteps replaces the canary steps of the merged values of deployment cdWorkflowRunnerId with the
+	// steps of the canary analysis config of the pipeline and starts the analysis of the deployment,
+	// merged values are returned as is if analysis is not enabled or the chart has no canary strategy
+	ApplyCanarySteps(pipelineId, cdWorkflowRunnerId int, mergedValues []byte) ([]byte, error)
+}
+
+type CanaryAnalysisServiceImpl struct {
+	logger                   *zap.SugaredLogger
+	canaryAnalysisRepository repository.CanaryAnalysisRepository
+}
+
+func NewCanaryAnalysisServiceImpl(logger *zap.SugaredLogger,
+	canaryAnalysisRepository repository.CanaryAnalysisRepository) *CanaryAnalysisServiceImpl {
+	return &CanaryAnalysisServiceImpl{
+		logger:                   logger,
+		canaryAnalysisRepository: canaryAnalysisRepository,
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetConfig(pipelineId int) (*bean.CanaryAnalysisConfig, error) {
+	model, err := impl.canaryAnalysisRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching canary analysis config", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	config := &bean.CanaryAnalysisConfig{}
+	err = json.Unmarshal([]byte(model.Config), config)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling canary analysis config", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	config.Enabled = model.Enabled
+	return config, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) ValidateConfig(config *bean.CanaryAnalysisConfig) error {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+	errMsg := validateConfig(config)
+	if len(errMsg) > 0 {
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func validateConfig(config *bean.CanaryAnalysisConfig) string {
+	if len(config.Steps) == 0 || len(config.Steps) > bean.MaxCanarySteps {
+		return fmt.Sprintf("canary analysis needs between 1 and %d steps", bean.MaxCanarySteps)
+	}
+	previousWeight := 0
+	for _, step := range config.Steps {
+		if step == nil || step.Weight <= previousWeight || step.Weight > 100 {
+			return "canary step weights must be increasing and between 1 and 100"
+		}
+		if step.AnalysisIntervalSeconds != 0 &&
+			(step.AnalysisIntervalSeconds < bean.MinAnalysisIntervalSeconds || step.AnalysisIntervalSeconds > bean.MaxAnalysisIntervalSeconds) {
+			return fmt.Sprintf("canary step analysis interval must be between %d and %d seconds", bean.MinAnalysisIntervalSeconds, bean.MaxAnalysisIntervalSeconds)
+		}
+		previousWeight = step.Weight
+	}
+	if len(config.Metrics) == 0 {
+		return "canary analysis needs at least one metric"
+	}
+	for _, metric := range config.Metrics {
+		if metric == nil || len(metric.Name) == 0 || len(metric.Query) == 0 {
+			return "canary metric name and query are required"
+		}
+		if metric.Min == nil && metric.Max == nil {
+			return fmt.Sprintf("canary metric %s needs a min or a max threshold", metric.Name)
+		}
+		if metric.Min != nil && metric.Max != nil && *metric.Min > *metric.Max {
+			return fmt.Sprintf("min of canary metric %s is greater than its max", metric.Name)
+		}
+		_, err := template.New(metric.Name).Option("missingkey=error").Parse(metric.Query)
+		if err != nil {
+			return fmt.Sprintf("invalid query of canary metric %s: %s", metric.Name, err.Error())
+		}
+	}
+	return ""
+}
+
+func (impl *CanaryAnalysisServiceImpl) SaveConfig(pipelineId int, config *bean.CanaryAnalysisConfig, userId int32) error {
+	err := impl.ValidateConfig(config)
+	if err != nil {
+		return err
+	}
+	configJson, err := json.Marshal(config)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling canary analysis config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	model, err := impl.canaryAnalysisRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching canary analysis config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	isNew := model == nil || model.Id == 0
+	if isNew {
+		model = &repository.CanaryAnalysisConfig{
+			PipelineId: pipelineId,
+			Active:     true,
+			AuditLog:   sql.NewDefaultAuditLog(userId),
+		}
+	}
+	model.Enabled = config.Enabled
+	model.Config = string(configJson)
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	if isNew {
+		err = impl.canaryAnalysisRepository.SaveConfig(model)
+	} else {
+		err = impl.canaryAnalysisRepository.UpdateConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) DeleteConfig(pipelineId int, userId int32) error {
+	model, err := impl.canaryAnalysisRepository.FindConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching canary analysis config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.canaryAnalysisRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting canary analysis config", "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) ApplyCanarySteps(pipelineId, cdWorkflowRunnerId int, mergedValues []byte) ([]byte, error) {
+	config, err := impl.GetConfig(pipelineId)
+	if err != nil || config == nil || !config.Enabled {
+		return mergedValues, err
+	}
+	if !gjson.GetBytes(mergedValues, canaryStrategyPath).Exists() {
+		impl.logger.Infow("skipping canary analysis as deployment template has no canary strategy", "pipelineId", pipelineId)
+		return mergedValues, nil
+	}
+	mergedValues, err = sjson.SetBytes(mergedValues, canaryStrategyStepsPath, GetRolloutSteps(config.Steps))
+	if err != nil {
+		impl.logger.Errorw("error in setting canary steps", "pipelineId", pipelineId, "err", err)
+		return mergedValues, err
+	}
+	configJson, err := json.Marshal(config)
+	if err != nil {
+		return mergedValues, err
+	}
+	err = impl.canaryAnalysisRepository.SupersedeRuns(pipelineId, userBean.SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in superseding canary analysis runs", "pipelineId", pipelineId, "err", err)
+		return mergedValues, err
+	}
+	run := &repository.CanaryAnalysisRun{
+		PipelineId:         pipelineId,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		Status:             repository.RunStatusRunning,
+		Config:             string(configJson),
+		AuditLog:           sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+	}
+	err = impl.canaryAnalysisRepository.SaveRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis run", "pipelineId", pipelineId, "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return mergedValues, err
+	}
+	return mergedValues, nil
+}
+
+// GetRolloutSteps converts the canary steps to argo rollout steps, every weight is followed by an indefinite
+// pause which is promoted by the canary analysis, so step i of the config is paused at rollout step index 2i+1
+func GetRolloutSteps(steps []*bean.CanaryStep) []map[string]interface{} {
+	rolloutSteps := make([]map[string]interface{}, 0, 2*len(steps))
+	for _, step := range steps {
+		rolloutSteps = append(rolloutSteps,
+			map[string]interface{}{"setWeight": step.Weight},
+			map[string]interface{}{"pause": map[string]interface{}{}})
+	}
+	return rolloutSteps
+}
+
+// GetPauseStepIndex is the index of the rollout step at which the canary step stepIndex is analysed
+func GetPauseStepIndex(stepIndex int) int {
+	return 2*stepIndex + 1
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func getValidConfig() *bean.CanaryAnalysisConfig {
+	minSuccessRate := 0.99
+	return &bean.CanaryAnalysisConfig{
+		Enabled: true,
+		Steps:   []*bean.CanaryStep{{Weight: 10}, {Weight: 50, AnalysisIntervalSeconds: 120}},
+		Metrics: []*bean.CanaryMetric{{
+			Name:  "success-rate",
+			Query: `sum(rate(http_requests_total{namespace="{{.Namespace}}",code!~"5.."}[1m])) / sum(rate(http_requests_total{namespace="{{.Namespace}}"}[1m]))`,
+			Min:   &minSuccessRate,
+		}},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	impl := NewCanaryAnalysisServiceImpl(zap.NewNop().Sugar(), nil)
+	assert.Nil(t, impl.ValidateConfig(nil))
+	assert.Nil(t, impl.ValidateConfig(&bean.CanaryAnalysisConfig{Enabled: false}))
+	assert.Nil(t, impl.ValidateConfig(getValidConfig()))
+
+	config := getValidConfig()
+	config.Steps = nil
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Steps = []*bean.CanaryStep{{Weight: 50}, {Weight: 20}}
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Steps = []*bean.CanaryStep{{Weight: 101}}
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Steps[0].AnalysisIntervalSeconds = 5
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Metrics = nil
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Metrics[0].Min = nil
+	assert.NotNil(t, impl.ValidateConfig(config))
+
+	config = getValidConfig()
+	config.Metrics[0].Query = `up{namespace="{{.Namespace}"}`
+	assert.NotNil(t, impl.ValidateConfig(config))
+}
+
+func TestGetRolloutSteps(t *testing.T) {
+	steps := GetRolloutSteps(getValidConfig().Steps)
+	assert.Equal(t, []map[string]interface{}{
+		{"setWeight": 10}, {"pause": map[string]interface{}{}},
+		{"setWeight": 50}, {"pause": map[string]interface{}{}},
+	}, steps)
+	// pause of step 1 (50%) is rollout step 3
+	assert.Equal(t, 3, GetPauseStepIndex(1))
+	assert.Equal(t, bean.DefaultAnalysisIntervalSeconds, (&bean.CanaryStep{Weight: 10}).GetAnalysisIntervalSeconds())
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+const (
+	DefaultAnalysisIntervalSeconds = 300
+	MinAnalysisIntervalSeconds     = 30
+	MaxAnalysisIntervalSeconds     = 86400
+	MaxCanarySteps                 = 20
+)
+
+// CanaryAnalysisConfig is the opt-in policy of a cd pipeline with CANARY strategy to shift traffic to the new release
+// in Steps, the canary is promoted to the next step only if all the Metrics are within their thresholds
+type CanaryAnalysisConfig struct {
+	Enabled bool          `json:"enabled"`
+	Steps   []*CanaryStep `json:"steps"`
+	// Metrics are queried from the prometheus endpoint of the cluster of the deployment environment
+	Metrics []*CanaryMetric `json:"metrics"`
+}
+
+type CanaryStep struct {
+	// Weight is the percentage of the traffic sent to the canary in this step, weights must be increasing
+	Weight int `json:"weight" validate:"min=1,max=100"`
+	// AnalysisIntervalSeconds is the time the canary runs at Weight before the metrics are analysed, DefaultAnalysisIntervalSeconds is used if not set
+	AnalysisIntervalSeconds int `json:"analysisIntervalSeconds" validate:"omitempty,min=30,max=86400"`
+}
+
+func (step *CanaryStep) GetAnalysisIntervalSeconds() int {
+	if step.AnalysisIntervalSeconds <= 0 {
+		return DefaultAnalysisIntervalSeconds
+	}
+	return step.AnalysisIntervalSeconds
+}
+
+// CanaryMetric is a PromQL query resolving to a single value, the query is a go template
+// with the fields of MetricQueryParams, e.g.
+//
+//	sum(rate(http_requests_total{namespace="{{.Namespace}}",rollouts_pod_template_hash="{{.CanaryPodHash}}",code!~"5.."}[1m]))
+//	/ sum(rate(http_requests_total{namespace="{{.Namespace}}",rollouts_pod_template_hash="{{.CanaryPodHash}}"}[1m]))
+type CanaryMetric struct {
+	Name  string `json:"name" validate:"required"`
+	Query string `json:"query" validate:"required"`
+	// Min is the lowest value of the metric for the analysis to pass, e.g. success rate
+	Min *float64 `json:"min,omitempty"`
+	// Max is the highest value of the metric for the analysis to pass, e.g. latency
+	Max *float64 `json:"max,omitempty"`
+}
+
+// MetricQueryParams are the values available to CanaryMetric.Query
+type MetricQueryParams struct {
+	AppName       string
+	EnvName       string
+	Namespace     string
+	ReleaseName   string
+	CanaryPodHash string
+	StablePodHash string
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	argoApplication "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/timelineStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/watch"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/repository"
+	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CATEGORY=CD
+type CanaryAnalysisControllerConfig struct {
+	PrometheusTimeoutSecs int `env:"CANARY_ANALYSIS_PROMETHEUS_TIMEOUT_SECS" envDefault:"10" description:"timeout in seconds of the prometheus queries of canary analysis"`
+	MaxInconclusive       int `env:"CANARY_ANALYSIS_MAX_INCONCLUSIVE" envDefault:"3" description:"consecutive inconclusive analysis (no data or prometheus unreachable) of a canary step after which the canary is aborted"`
+}
+
+type CanaryAnalysisController interface {
+	// ProcessRuns analyses the canary deployments whose rollout is paused at a canary step, the rollout is promoted
+	// to the next step if all the metrics are within their thresholds, else it is aborted and rolled back
+	ProcessRuns()
+}
+
+type CanaryAnalysisControllerImpl struct {
+	logger                        *zap.SugaredLogger
+	config                        *CanaryAnalysisControllerConfig
+	canaryAnalysisRepository      repository.CanaryAnalysisRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	k8sCommonService              k8s2.K8sCommonService
+	k8sUtil                       *k8s.K8sServiceImpl
+	pipelineStatusTimelineService status.PipelineStatusTimelineService
+	autoRollbackWatchService      watch.AutoRollbackWatchService
+}
+
+func NewCanaryAnalysisControllerImpl(logger *zap.SugaredLogger,
+	canaryAnalysisRepository repository.CanaryAnalysisRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	k8sCommonService k8s2.K8sCommonService,
+	k8sUtil *k8s.K8sServiceImpl,
+	pipelineStatusTimelineService status.PipelineStatusTimelineService,
+	autoRollbackWatchService watch.AutoRollbackWatchService) (*CanaryAnalysisControllerImpl, error) {
+	config := &CanaryAnalysisControllerConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing canary analysis controller config", "err", err)
+		return nil, err
+	}
+	return &CanaryAnalysisControllerImpl{
+		logger:                        logger,
+		config:                        config,
+		canaryAnalysisRepository:      canaryAnalysisRepository,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		k8sCommonService:              k8sCommonService,
+		k8sUtil:                       k8sUtil,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+		autoRollbackWatchService:      autoRollbackWatchService,
+	}, nil
+}
+
+var rolloutGvk = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+var failedDeployStatuses = []string{cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowAborted, cdWorkflow.WorkflowTimedOut}
+
+var succeededDeployStatuses = []string{argoApplication.Healthy, argoApplication.SUCCEEDED}
+
+const (
+	promotePatch = `{"status":{"pauseConditions":null}}`
+	abortPatch   = `{"status":{"abort":true}}`
+)
+
+// rolloutState is the part of the argo rollout status used by the analysis
+type rolloutState struct {
+	name             string
+	currentStepIndex int64
+	isPaused         bool
+	isAborted        bool
+	canaryPodHash    string
+	stablePodHash    string
+}
+
+func (impl *CanaryAnalysisControllerImpl) ProcessRuns() {
+	runs, err := impl.canaryAnalysisRepository.FindRunsByStatus(repository.RunStatusRunning)
+	if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis runs", "err", err)
+		return
+	}
+	for _, run := range runs {
+		err = impl.processRun(run)
+		if err != nil {
+			impl.logger.Errorw("error in processing canary analysis run", "runId", run.Id, "pipelineId", run.PipelineId, "err", err)
+		}
+	}
+}
+
+func (impl *CanaryAnalysisControllerImpl) processRun(run *repository.CanaryAnalysisRun) error {
+	deployRunner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(run.CdWorkflowRunnerId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return impl.closeRun(run, repository.RunStatusFailed, "deployment not found", "")
+		}
+		return err
+	}
+	latestRunner, err := impl.cdWorkflowRepository.FindLatestByPipelineIdAndRunnerType(run.PipelineId, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	if latestRunner.Id != run.CdWorkflowRunnerId {
+		return impl.closeRun(run, repository.RunStatusSuperseded, "superseded by a newer deployment", "")
+	}
+	if slices.Contains(failedDeployStatuses, deployRunner.Status) {
+		return impl.closeRun(run, repository.RunStatusFailed, fmt.Sprintf("deployment %s", strings.ToLower(deployRunner.Status)), "")
+	}
+	config := &bean.CanaryAnalysisConfig{}
+	err = json.Unmarshal([]byte(run.Config), config)
+	if err != nil {
+		return err
+	}
+	pipeline := deployRunner.CdWorkflow.Pipeline
+	ctx := context.Background()
+	restConfig, err, cluster := impl.k8sCommonService.GetRestConfigByClusterId(ctx, pipeline.Environment.ClusterId)
+	if err != nil {
+		return err
+	}
+	rolloutIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, rolloutGvk)
+	if err != nil {
+		return err
+	}
+	rollout, err := impl.getRolloutState(ctx, rolloutIf.Namespace(pipeline.Environment.Namespace), pipeline.DeploymentAppName)
+	if err != nil || rollout == nil {
+		// rollout not synced yet
+		return err
+	}
+	if rollout.isAborted {
+		return impl.closeRun(run, repository.RunStatusAborted, "Canary was aborted outside of the canary analysis.", timelineStatus.TIMELINE_STATUS_CANARY_ABORTED)
+	}
+	if rollout.canaryPodHash == rollout.stablePodHash {
+		// a deployment without pod template changes completes without canary steps
+		if slices.Contains(succeededDeployStatuses, deployRunner.Status) {
+			return impl.closeRun(run, repository.RunStatusPromoted, "Deployment completed without canary steps.", "")
+		}
+		return nil
+	}
+	if !rollout.isPaused || rollout.currentStepIndex != int64(canary.GetPauseStepIndex(run.CurrentStep)) || run.CurrentStep >= len(config.Steps) {
+		return nil
+	}
+	step := config.Steps[run.CurrentStep]
+	if run.StepStartedOn.IsZero() {
+		run.StepStartedOn = time.Now()
+		return impl.updateRun(run)
+	}
+	if time.Since(run.StepStartedOn) < time.Duration(step.GetAnalysisIntervalSeconds())*time.Second {
+		return nil
+	}
+	queryParams := &bean.MetricQueryParams{
+		AppName:       pipeline.App.AppName,
+		EnvName:       pipeline.Environment.Name,
+		Namespace:     pipeline.Environment.Namespace,
+		ReleaseName:   pipeline.DeploymentAppName,
+		CanaryPodHash: rollout.canaryPodHash,
+		StablePodHash: rollout.stablePodHash,
+	}
+	breaches, inconclusive, results := impl.analyse(ctx, config, cluster, queryParams)
+	stepDescription := fmt.Sprintf("Canary step %d/%d at %d%% traffic", run.CurrentStep+1, len(config.Steps), step.Weight)
+	if len(breaches) > 0 {
+		return impl.abort(run, deployRunner, rolloutIf.Namespace(pipeline.Environment.Namespace), rollout.name,
+			fmt.Sprintf("%s failed analysis, %s.", stepDescription, strings.Join(breaches, ", ")))
+	}
+	if len(inconclusive) > 0 {
+		run.InconclusiveCount++
+		if run.InconclusiveCount >= impl.config.MaxInconclusive {
+			return impl.abort(run, deployRunner, rolloutIf.Namespace(pipeline.Environment.Namespace), rollout.name,
+				fmt.Sprintf("%s analysis was inconclusive %d times, %s.", stepDescription, run.InconclusiveCount, strings.Join(inconclusive, ", ")))
+		}
+		run.Message = fmt.Sprintf("%s analysis inconclusive, %s.", stepDescription, strings.Join(inconclusive, ", "))
+		return impl.updateRun(run)
+	}
+	return impl.promote(run, config, rolloutIf.Namespace(pipeline.Environment.Namespace), rollout.name,
+		fmt.Sprintf("%s passed analysis (%s)", stepDescription, strings.Join(results, ", ")))
+}
+
+func (impl *CanaryAnalysisControllerImpl) getRolloutState(ctx context.Context, rolloutIf dynamic.ResourceInterface, releaseName string) (*rolloutState, error) {
+	rollouts, err := rolloutIf.List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("release=%s", releaseName)})
+	if err != nil {
+		impl.logger.Errorw("error in listing rollouts", "releaseName", releaseName, "err", err)
+		return nil, err
+	}
+	if rollouts == nil || len(rollouts.Items) == 0 {
+		return nil, nil
+	}
+	rollout := rollouts.Items[0].Object
+	state := &rolloutState{name: rollouts.Items[0].GetName()}
+	state.currentStepIndex, _, _ = unstructured.NestedInt64(rollout, "status", "currentStepIndex")
+	state.isAborted, _, _ = unstructured.NestedBool(rollout, "status", "abort")
+	state.canaryPodHash, _, _ = unstructured.NestedString(rollout, "status", "currentPodHash")
+	state.stablePodHash, _, _ = unstructured.NestedString(rollout, "status", "stableRS")
+	pauseConditions, _, _ := unstructured.NestedSlice(rollout, "status", "pauseConditions")
+	state.isPaused = len(pauseConditions) > 0
+	return state, nil
+}
+
+// analyse queries all the metrics, returns the metrics out of their thresholds, the metrics which could not
+// be evaluated and the values of all the evaluated metrics
+func (impl *CanaryAnalysisControllerImpl) analyse(ctx context.Context, config *bean.CanaryAnalysisConfig, cluster *clusterBean.ClusterBean,
+	queryParams *bean.MetricQueryParams) (breaches []string, inconclusive []string, results []string) {
+	prometheusApi, err := newPrometheusApi(cluster)
+	if err != nil {
+		impl.logger.Errorw("error in creating prometheus client for canary analysis", "releaseName", queryParams.ReleaseName, "err", err)
+		return nil, []string{err.Error()}, nil
+	}
+	timeout := time.Duration(impl.config.PrometheusTimeoutSecs) * time.Second
+	for _, metric := range config.Metrics {
+		query, err := renderQuery(metric, queryParams)
+		if err != nil {
+			inconclusive = append(inconclusive, fmt.Sprintf("%s: %s", metric.Name, err.Error()))
+			continue
+		}
+		value, err := queryMetric(ctx, prometheusApi, query, timeout)
+		if err != nil {
+			impl.logger.Warnw("canary metric could not be evaluated", "metric", metric.Name, "query", query, "err", err)
+			inconclusive = append(inconclusive, fmt.Sprintf("%s: %s", metric.Name, err.Error()))
+			continue
+		}
+		if !isWithinThresholds(metric, value) {
+			breaches = append(breaches, fmt.Sprintf("%s is %s (%s)", metric.Name, formatValue(value), getThresholdDescription(metric)))
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s: %s", metric.Name, formatValue(value)))
+	}
+	return breaches, inconclusive, results
+}
+
+func (impl *CanaryAnalysisControllerImpl) promote(run *repository.CanaryAnalysisRun, config *bean.CanaryAnalysisConfig,
+	rolloutIf dynamic.ResourceInterface, rolloutName string, message string) error {
+	// the step is advanced first so that only one replica promotes it, it is reverted if the rollout is not
+	// promoted so that the step is analysed and promoted again
+	advanced, err := impl.canaryAnalysisRepository.AdvanceStep(run.Id, run.CurrentStep)
+	if err != nil || !advanced {
+		return err
+	}
+	_, err = rolloutIf.Patch(context.Background(), rolloutName, types.MergePatchType, []byte(promotePatch), metav1.PatchOptions{}, "status")
+	if err != nil {
+		impl.logger.Errorw("error in promoting canary", "rolloutName", rolloutName, "runId", run.Id, "err", err)
+		if revertErr := impl.canaryAnalysisRepository.RevertStep(run); revertErr != nil {
+			impl.logger.Errorw("error in reverting canary step", "runId", run.Id, "step", run.CurrentStep, "err", revertErr)
+		}
+		return err
+	}
+	run.CurrentStep++
+	run.StepStartedOn = time.Time{}
+	run.InconclusiveCount = 0
+	impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus.TIMELINE_STATUS_CANARY_STEP_PROMOTED, message+", promoted.")
+	if run.CurrentStep < len(config.Steps) {
+		return nil
+	}
+	return impl.closeRun(run, repository.RunStatusPromoted, "Canary promoted to full traffic after all the steps passed analysis.", timelineStatus.TIMELINE_STATUS_CANARY_PROMOTED)
+}
+
+// abort aborts the rollout, which shifts all the traffic back to the stable release, and rolls back the
+// deployment to the last successful deployment of the pipeline
+func (impl *CanaryAnalysisControllerImpl) abort(run *repository.CanaryAnalysisRun, deployRunner *pipelineConfig.CdWorkflowRunner,
+	rolloutIf dynamic.ResourceInterface, rolloutName string, message string) error {
+	claimed, err := impl.canaryAnalysisRepository.ClaimRun(run.Id, repository.RunStatusRunning, repository.RunStatusAborted)
+	if err != nil || !claimed {
+		return err
+	}
+	run.Status = repository.RunStatusAborted
+	_, err = rolloutIf.Patch(context.Background(), rolloutName, types.MergePatchType, []byte(abortPatch), metav1.PatchOptions{}, "status")
+	if err != nil {
+		impl.logger.Errorw("error in aborting canary", "rolloutName", rolloutName, "runId", run.Id, "err", err)
+		message = fmt.Sprintf("%s Canary could not be aborted: %s.", message, err.Error())
+	}
+	target, _, err := impl.autoRollbackWatchService.RollbackToLastSuccessfulDeployment(run.PipelineId, deployRunner.CdWorkflow.Pipeline.AppId, run.CdWorkflowRunnerId)
+	if err != nil {
+		message = fmt.Sprintf("%s Rollback failed: %s.", message, err.Error())
+	} else {
+		message = fmt.Sprintf("%s Canary aborted and rolled back to deployment %d.", message, target.Id)
+	}
+	run.Message = message
+	impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus.TIMELINE_STATUS_CANARY_ABORTED, message)
+	return impl.updateRun(run)
+}
+
+func (impl *CanaryAnalysisControllerImpl) closeRun(run *repository.CanaryAnalysisRun, runStatus repository.RunStatus, message string,
+	timeline timelineStatus.TimelineStatus) error {
+	claimed, err := impl.canaryAnalysisRepository.ClaimRun(run.Id, repository.RunStatusRunning, runStatus)
+	if err != nil || !claimed {
+		return err
+	}
+	if len(timeline) > 0 {
+		impl.saveTimeline(run.CdWorkflowRunnerId, timeline, message)
+	}
+	run.Status = runStatus
+	run.Message = message
+	return impl.updateRun(run)
+}
+
+func (impl *CanaryAnalysisControllerImpl) updateRun(run *repository.CanaryAnalysisRun) error {
+	run.UpdatedOn = time.Now()
+	run.UpdatedBy = userBean.SYSTEM_USER_ID
+	err := impl.canaryAnalysisRepository.UpdateRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in updating canary analysis run", "runId", run.Id, "status", run.Status, "err", err)
+		return err
+	}
+	return nil
+}
+
+// saveTimeline records the canary progress on the timeline of the deployment
+func (impl *CanaryAnalysisControllerImpl) saveTimeline(cdWorkflowRunnerId int, timelineStatus timelineStatus.TimelineStatus, message string) {
+	timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(cdWorkflowRunnerId, timelineStatus, message, userBean.SYSTEM_USER_ID)
+	err := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis timeline", "cdWorkflowRunnerId", cdWorkflowRunnerId, "status", timelineStatus, "err", err)
+	}
+}
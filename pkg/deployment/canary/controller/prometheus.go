@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
+	"github.com/prometheus/client_golang/api"
+	prometheusV1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// errNoData is returned for the queries which resolve to no value, e.g. no traffic reached the canary yet
+var errNoData = errors.New("query returned no data")
+
+type basicAuthRoundTripper struct {
+	userName string
+	password string
+	next     http.RoundTripper
+}
+
+func (rt *basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(rt.userName, rt.password)
+	return rt.next.RoundTrip(req)
+}
+
+// newPrometheusApi returns the client of the prometheus endpoint of the cluster, the same endpoint and auth
+// used for the app metrics of the cluster
+func newPrometheusApi(cluster *clusterBean.ClusterBean) (prometheusV1.API, error) {
+	if cluster == nil || len(cluster.PrometheusUrl) == 0 {
+		return nil, errors.New("prometheus endpoint is not configured for the cluster")
+	}
+	transport := api.DefaultRoundTripper.(*http.Transport).Clone()
+	var roundTripper http.RoundTripper = transport
+	if auth := cluster.PrometheusAuth; auth != nil {
+		if len(auth.TlsClientCert) > 0 && len(auth.TlsClientKey) > 0 {
+			certificate, err := tls.X509KeyPair([]byte(auth.TlsClientCert), []byte(auth.TlsClientKey))
+			if err != nil {
+				return nil, fmt.Errorf("invalid prometheus tls client certificate: %w", err)
+			}
+			transport.TLSClientConfig = &tls.Config{Certificates: []tls.Certificate{certificate}}
+		}
+		if len(auth.UserName) > 0 {
+			roundTripper = &basicAuthRoundTripper{userName: auth.UserName, password: auth.Password, next: transport}
+		}
+	}
+	client, err := api.NewClient(api.Config{Address: cluster.PrometheusUrl, RoundTripper: roundTripper})
+	if err != nil {
+		return nil, err
+	}
+	return prometheusV1.NewAPI(client), nil
+}
+
+func renderQuery(metric *bean.CanaryMetric, params *bean.MetricQueryParams) (string, error) {
+	queryTemplate, err := template.New(metric.Name).Option("missingkey=error").Parse(metric.Query)
+	if err != nil {
+		return "", err
+	}
+	var query bytes.Buffer
+	err = queryTemplate.Execute(&query, params)
+	if err != nil {
+		return "", err
+	}
+	return query.String(), nil
+}
+
+func queryMetric(ctx context.Context, prometheusApi prometheusV1.API, query string, timeout time.Duration) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	value, _, err := prometheusApi.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return getSampleValue(value)
+}
+
+// getSampleValue returns the value of a scalar or of the first sample of a vector
+func getSampleValue(value model.Value) (float64, error) {
+	var sample float64
+	switch result := value.(type) {
+	case *model.Scalar:
+		sample = float64(result.Value)
+	case model.Vector:
+		if len(result) == 0 {
+			return 0, errNoData
+		}
+		sample = float64(result[0].Value)
+	default:
+		return 0, fmt.Errorf("unsupported query result type %s, query must resolve to a scalar or an instant vector", value.Type())
+	}
+	if math.IsNaN(sample) || math.IsInf(sample, 0) {
+		return 0, errNoData
+	}
+	return sample, nil
+}
+
+// isWithinThresholds returns false if the value is below the min or above the max of the metric
+func isWithinThresholds(metric *bean.CanaryMetric, value float64) bool {
+	if metric.Min != nil && value < *metric.Min {
+		return false
+	}
+	if metric.Max != nil && value > *metric.Max {
+		return false
+	}
+	return true
+}
+
+func getThresholdDescription(metric *bean.CanaryMetric) string {
+	var thresholds []string
+	if metric.Min != nil {
+		thresholds = append(thresholds, "min "+formatValue(*metric.Min))
+	}
+	if metric.Max != nil {
+		thresholds = append(thresholds, "max "+formatValue(*metric.Max))
+	}
+	return strings.Join(thresholds, ", ")
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/canary/bean"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestGetSampleValue(t *testing.T) {
+	value, err := getSampleValue(&model.Scalar{Value: 0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, value)
+
+	value, err = getSampleValue(model.Vector{{Value: 0.98}, {Value: 0.2}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.98, value)
+
+	_, err = getSampleValue(model.Vector{})
+	assert.Equal(t, errNoData, err)
+	_, err = getSampleValue(model.Vector{{Value: model.SampleValue(math.NaN())}})
+	assert.Equal(t, errNoData, err)
+	_, err = getSampleValue(model.Matrix{})
+	assert.NotNil(t, err)
+}
+
+func TestIsWithinThresholds(t *testing.T) {
+	minValue, maxValue := 0.95, 500.0
+	assert.True(t, isWithinThresholds(&bean.CanaryMetric{Min: &minValue}, 0.95))
+	assert.False(t, isWithinThresholds(&bean.CanaryMetric{Min: &minValue}, 0.9))
+	assert.True(t, isWithinThresholds(&bean.CanaryMetric{Max: &maxValue}, 250))
+	assert.False(t, isWithinThresholds(&bean.CanaryMetric{Max: &maxValue}, 501))
+	assert.Equal(t, "min 0.95, max 500", getThresholdDescription(&bean.CanaryMetric{Min: &minValue, Max: &maxValue}))
+}
+
+func TestRenderQuery(t *testing.T) {
+	params := &bean.MetricQueryParams{Namespace: "prod", CanaryPodHash: "6f7b9"}
+	query, err := renderQuery(&bean.CanaryMetric{Name: "latency", Query: `p95{namespace="{{.Namespace}}",hash="{{.CanaryPodHash}}"}`}, params)
+	assert.Nil(t, err)
+	assert.Equal(t, `p95{namespace="prod",hash="6f7b9"}`, query)
+
+	_, err = renderQuery(&bean.CanaryMetric{Name: "latency", Query: `p95{pod="{{.PodName}}"}`}, params)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning    RunStatus = "RUNNING"
+	RunStatusPromoted   RunStatus = "PROMOTED"
+	RunStatusAborted    RunStatus = "ABORTED"
+	RunStatusFailed     RunStatus = "FAILED"
+	RunStatusSuperseded RunStatus = "SUPERSEDED"
+)
+
+type CanaryAnalysisConfig struct {
+	tableName  struct{} `sql:"canary_analysis_config" pg:",discard_unknown_columns"`
+	Id         int      `sql:"id,pk"`
+	PipelineId int      `sql:"pipeline_id,notnull"`
+	Enabled    bool     `sql:"enabled,notnull"`
+	// Config is the json of bean.CanaryAnalysisConfig
+	Config string `sql:"config,notnull"`
+	Active bool   `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CanaryAnalysisRun is the analysis of the canary deployment CdWorkflowRunnerId, CurrentStep is the index of the
+// step being analysed since StepStartedOn, Config is the snapshot of the config the deployment was rendered with
+type CanaryAnalysisRun struct {
+	tableName          struct{}  `sql:"canary_analysis_run" pg:",discard_unknown_columns"`
+	Id                 int       `sql:"id,pk"`
+	PipelineId         int       `sql:"pipeline_id,notnull"`
+	CdWorkflowRunnerId int       `sql:"cd_workflow_runner_id,notnull"`
+	Status             RunStatus `sql:"status,notnull"`
+	CurrentStep        int       `sql:"current_step,notnull"`
+	StepStartedOn      time.Time `sql:"step_started_on"`
+	InconclusiveCount  int       `sql:"inconclusive_count,notnull"`
+	Config             string    `sql:"config,notnull"`
+	Message            string    `sql:"message"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisRepository interface {
+	FindConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error)
+	SaveConfig(model *CanaryAnalysisConfig) error
+	UpdateConfig(model *CanaryAnalysisConfig) error
+
+	SaveRun(model *CanaryAnalysisRun) error
+	UpdateRun(model *CanaryAnalysisRun) error
+	FindRunsByStatus(status RunStatus) ([]*CanaryAnalysisRun, error)
+	FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error)
+	// ClaimRun moves the run from status expected to status claimed, returns false if the run
+	// was already moved (by another replica)
+	ClaimRun(id int, expected, claimed RunStatus) (bool, error)
+	// AdvanceStep moves the running analysis from step currentStep to the next step, returns false if the
+	// step was already advanced (by another replica)
+	AdvanceStep(id int, currentStep int) (bool, error)
+	// RevertStep moves the running analysis back from the next step to the step of the run, for a promotion
+	// of the step that could not be applied on the rollout
+	RevertStep(run *CanaryAnalysisRun) error
+	// SupersedeRuns marks the running analysis of the pipeline superseded by a newer deployment
+	SupersedeRuns(pipelineId int, userId int32) error
+}
+
+type CanaryAnalysisRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCanaryAnalysisRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CanaryAnalysisRepositoryImpl {
+	return &CanaryAnalysisRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error) {
+	model := &CanaryAnalysisConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveConfig(model *CanaryAnalysisConfig) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdateConfig(model *CanaryAnalysisConfig) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveRun(model *CanaryAnalysisRun) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdateRun(model *CanaryAnalysisRun) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunsByStatus(status RunStatus) ([]*CanaryAnalysisRun, error) {
+	var models []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error) {
+	model := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(model).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) ClaimRun(id int, expected, claimed RunStatus) (bool, error) {
+	res, err := impl.dbConnection.Model(&CanaryAnalysisRun{}).
+		Set("status = ?", claimed).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", expected).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) AdvanceStep(id int, currentStep int) (bool, error) {
+	res, err := impl.dbConnection.Model(&CanaryAnalysisRun{}).
+		Set("current_step = ?", currentStep+1).
+		Set("step_started_on = NULL").
+		Set("inconclusive_count = 0").
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", RunStatusRunning).
+		Where("current_step = ?", currentStep).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) RevertStep(run *CanaryAnalysisRun) error {
+	var stepStartedOn *time.Time
+	if !run.StepStartedOn.IsZero() {
+		stepStartedOn = &run.StepStartedOn
+	}
+	_, err := impl.dbConnection.Model(&CanaryAnalysisRun{}).
+		Set("current_step = ?", run.CurrentStep).
+		Set("step_started_on = ?", stepStartedOn).
+		Set("inconclusive_count = ?", run.InconclusiveCount).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", run.Id).
+		Where("status = ?", RunStatusRunning).
+		Where("current_step = ?", run.CurrentStep+1).
+		Update()
+	return err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SupersedeRuns(pipelineId int, userId int32) error {
+	_, err := impl.dbConnection.Model(&CanaryAnalysisRun{}).
+		Set("status = ?", RunStatusSuperseded).
+		Set("message = ?", "superseded by a newer deployment").
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", RunStatusRunning).
+		Update()
+	return err
+}
@@ -0,0 +1,500 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	argoApplication "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/config/configDiff"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
+	k8sBean "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DeploymentDriftService interface {
+	// ScanAll scans the helm deployed cd pipelines of the devtron apps for drift, run by the drift scan cron
+	ScanAll()
+	// Scan scans the cd pipeline of the app on the env for drift
+	Scan(ctx context.Context, appId, envId int) (*bean.DriftDetail, error)
+	// GetDriftSummary returns nil if the cd pipeline of the app on the env was never scanned or configured
+	GetDriftSummary(appId, envId int) (*bean.DriftSummary, error)
+	GetDriftDetail(appId, envId int) (*bean.DriftDetail, error)
+	UpdateConfig(request *bean.DriftConfigRequest) (*bean.DriftSummary, error)
+}
+
+type DeploymentDriftServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	config                         *bean.DeploymentDriftConfig
+	deploymentDriftRepository      repository.DeploymentDriftRepository
+	cdWorkflowRepository           pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository     chartConfig.PipelineOverrideRepository
+	deploymentConfigurationService configDiff.DeploymentConfigurationService
+	k8sCommonService               k8s2.K8sCommonService
+	eventClient                    client.EventClient
+	eventFactory                   client.EventFactory
+	cdHandlerService               devtronApps.HandlerService
+}
+
+func NewDeploymentDriftServiceImpl(logger *zap.SugaredLogger,
+	config *bean.DeploymentDriftConfig,
+	deploymentDriftRepository repository.DeploymentDriftRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	deploymentConfigurationService configDiff.DeploymentConfigurationService,
+	k8sCommonService k8s2.K8sCommonService,
+	eventClient client.EventClient,
+	eventFactory client.EventFactory,
+	cdHandlerService devtronApps.HandlerService) *DeploymentDriftServiceImpl {
+	return &DeploymentDriftServiceImpl{
+		logger:                         logger,
+		config:                         config,
+		deploymentDriftRepository:      deploymentDriftRepository,
+		cdWorkflowRepository:           cdWorkflowRepository,
+		pipelineOverrideRepository:     pipelineOverrideRepository,
+		deploymentConfigurationService: deploymentConfigurationService,
+		k8sCommonService:               k8sCommonService,
+		eventClient:                    eventClient,
+		eventFactory:                   eventFactory,
+		cdHandlerService:               cdHandlerService,
+	}
+}
+
+// succeededDeployStatuses are the statuses of the deployments whose manifests were applied, live resources of the
+// other deployments can be partially applied and are not compared
+var succeededDeployStatuses = []string{argoApplication.Healthy, argoApplication.SUCCEEDED, argoApplication.Degraded}
+
+const (
+	notHelmPipelineMessage = "drift is scanned for the helm deployed pipelines of devtron apps only"
+	// maxResourcesInNotification is the number of drifted resources listed in the drift notification
+	maxResourcesInNotification = 5
+)
+
+func (impl *DeploymentDriftServiceImpl) ScanAll() {
+	pipelines, err := impl.deploymentDriftRepository.FindPipelinesToScan()
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipelines for drift scan", "err", err)
+		return
+	}
+	for _, pipeline := range pipelines {
+		_, err = impl.scanPipeline(context.Background(), pipeline)
+		if err != nil {
+			impl.logger.Errorw("error in scanning pipeline for drift", "pipelineId", pipeline.PipelineId, "err", err)
+		}
+	}
+}
+
+func (impl *DeploymentDriftServiceImpl) Scan(ctx context.Context, appId, envId int) (*bean.DriftDetail, error) {
+	pipeline, err := impl.getPipelineToScan(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.scanPipeline(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		errMsg := "no deployment found to scan for drift"
+		return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	return impl.GetDriftDetail(appId, envId)
+}
+
+func (impl *DeploymentDriftServiceImpl) GetDriftSummary(appId, envId int) (*bean.DriftSummary, error) {
+	model, err := impl.deploymentDriftRepository.FindByAppIdAndEnvId(appId, envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching deployment drift", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	resources, err := getDriftedResources(model)
+	if err != nil {
+		impl.logger.Errorw("error in parsing drifted resources", "pipelineId", model.PipelineId, "err", err)
+		return nil, err
+	}
+	return adaptDriftSummary(model, len(resources)), nil
+}
+
+func (impl *DeploymentDriftServiceImpl) GetDriftDetail(appId, envId int) (*bean.DriftDetail, error) {
+	model, err := impl.deploymentDriftRepository.FindByAppIdAndEnvId(appId, envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			errMsg := "pipeline not scanned for drift yet"
+			return nil, util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+		}
+		impl.logger.Errorw("error in fetching deployment drift", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	resources, err := getDriftedResources(model)
+	if err != nil {
+		impl.logger.Errorw("error in parsing drifted resources", "pipelineId", model.PipelineId, "err", err)
+		return nil, err
+	}
+	return &bean.DriftDetail{
+		DriftSummary: adaptDriftSummary(model, len(resources)),
+		Resources:    resources,
+	}, nil
+}
+
+func (impl *DeploymentDriftServiceImpl) UpdateConfig(request *bean.DriftConfigRequest) (*bean.DriftSummary, error) {
+	pipeline, err := impl.getPipelineToScan(request.AppId, request.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.deploymentDriftRepository.FindByPipelineId(pipeline.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment drift", "pipelineId", pipeline.PipelineId, "err", err)
+		return nil, err
+	}
+	isNew := model == nil || model.Id == 0
+	if isNew {
+		model = newDeploymentDrift(pipeline, request.UserId)
+	}
+	model.Notify = request.Notify
+	model.AutoReconcile = request.AutoReconcile
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = request.UserId
+	if isNew {
+		err = impl.deploymentDriftRepository.Save(model)
+	} else {
+		err = impl.deploymentDriftRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment drift config", "pipelineId", pipeline.PipelineId, "err", err)
+		return nil, err
+	}
+	return impl.GetDriftSummary(request.AppId, request.EnvId)
+}
+
+func (impl *DeploymentDriftServiceImpl) getPipelineToScan(appId, envId int) (*repository.DriftScanPipeline, error) {
+	pipeline, err := impl.deploymentDriftRepository.FindPipelineToScan(appId, envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusBadRequest, notHelmPipelineMessage, notHelmPipelineMessage)
+		}
+		impl.logger.Errorw("error in fetching pipeline for drift scan", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// scanPipeline compares the live resources of the pipeline against its latest deployment, returns nil if the
+// pipeline was never deployed or its scan was claimed by another replica
+func (impl *DeploymentDriftServiceImpl) scanPipeline(ctx context.Context, pipeline *repository.DriftScanPipeline) (*repository.DeploymentDrift, error) {
+	latestRunner, err := impl.cdWorkflowRepository.FindLatestByPipelineIdAndRunnerType(pipeline.PipelineId, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	model, err := impl.claimScan(pipeline)
+	if err != nil || model == nil {
+		return nil, err
+	}
+	// a running deployment is changing the live resources, the last scan result is retained
+	if !util2.IsTerminalRunnerStatus(latestRunner.Status) {
+		return model, nil
+	}
+	wasDrifted := model.DriftDetectedOn != nil
+	lastDriftedResources := model.DriftedResources
+	model.CdWorkflowRunnerId = latestRunner.Id
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userBean.SYSTEM_USER_ID
+	var resources []*bean.ResourceDrift
+	if !slices.Contains(succeededDeployStatuses, latestRunner.Status) {
+		model.Status = string(bean.DriftStatusUnknown)
+		model.Message = fmt.Sprintf("latest deployment is %s, drift is scanned for successful deployments only", latestRunner.Status)
+	} else {
+		resources, err = impl.getDrift(ctx, pipeline, &latestRunner)
+		if err != nil {
+			impl.logger.Errorw("error in scanning deployment drift", "pipelineId", pipeline.PipelineId, "err", err)
+			model.Status = string(bean.DriftStatusError)
+			model.Message = err.Error()
+		} else if len(resources) == 0 {
+			model.Status = string(bean.DriftStatusInSync)
+			model.Message = ""
+		} else {
+			model.Status = string(bean.DriftStatusDrifted)
+			model.Message = getDriftMessage(resources)
+		}
+	}
+	switch bean.DriftStatus(model.Status) {
+	case bean.DriftStatusDrifted:
+	case bean.DriftStatusError:
+		// the last detected drift is retained so that a failing scan does not start a new drift
+		return model, impl.updateDrift(model)
+	default:
+		model.DriftedResources = ""
+		model.DriftDetectedOn = nil
+		return model, impl.updateDrift(model)
+	}
+	driftedResources, err := json.Marshal(resources)
+	if err != nil {
+		return nil, err
+	}
+	model.DriftedResources = string(driftedResources)
+	if !wasDrifted {
+		driftDetectedOn := time.Now()
+		model.DriftDetectedOn = &driftDetectedOn
+	}
+	if model.Notify && model.DriftedResources != lastDriftedResources {
+		impl.notify(pipeline, latestRunner.Id, resources)
+		notifiedOn := time.Now()
+		model.NotifiedOn = &notifiedOn
+	}
+	if model.AutoReconcile && (model.ReconciledOn == nil || model.ReconciledOn.Before(*model.DriftDetectedOn)) {
+		err = impl.reconcile(pipeline, &latestRunner)
+		if err != nil {
+			// reconciled_on is not moved so that the drift is reconciled again on the next scan
+			model.Message = fmt.Sprintf("%s, auto reconcile failed: %s", model.Message, err.Error())
+		} else {
+			reconciledOn := time.Now()
+			model.ReconciledOn = &reconciledOn
+			model.Message = fmt.Sprintf("%s, auto reconciled by redeploying deployment %d", model.Message, latestRunner.Id)
+		}
+	}
+	return model, impl.updateDrift(model)
+}
+
+// claimScan returns the drift of the pipeline with its scanned_on moved to now, nil if it is being scanned by another replica
+func (impl *DeploymentDriftServiceImpl) claimScan(pipeline *repository.DriftScanPipeline) (*repository.DeploymentDrift, error) {
+	scannedOn := time.Now()
+	model, err := impl.deploymentDriftRepository.FindByPipelineId(pipeline.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	if model == nil || model.Id == 0 {
+		model = newDeploymentDrift(pipeline, userBean.SYSTEM_USER_ID)
+		model.ScannedOn = &scannedOn
+		// saved by the replica which scans the pipeline first, pipeline_id is unique
+		err = impl.deploymentDriftRepository.Save(model)
+		if err != nil {
+			impl.logger.Warnw("deployment drift not saved, pipeline scanned by another replica", "pipelineId", pipeline.PipelineId, "err", err)
+			return nil, nil
+		}
+		return model, nil
+	}
+	claimed, err := impl.deploymentDriftRepository.ClaimScan(model.Id, model.ScannedOn, scannedOn)
+	if err != nil || !claimed {
+		return nil, err
+	}
+	model.ScannedOn = &scannedOn
+	return model, nil
+}
+
+func (impl *DeploymentDriftServiceImpl) updateDrift(model *repository.DeploymentDrift) error {
+	err := impl.deploymentDriftRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment drift", "pipelineId", model.PipelineId, "err", err)
+		return err
+	}
+	return nil
+}
+
+// getDrift renders the deployed chart with the merged values of the deployment for the kube version of the target
+// cluster and compares the resources with their live state
+func (impl *DeploymentDriftServiceImpl) getDrift(ctx context.Context, pipeline *repository.DriftScanPipeline, runner *pipelineConfig.CdWorkflowRunner) ([]*bean.ResourceDrift, error) {
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(runner.CdWorkflowId)
+	if err != nil {
+		return nil, fmt.Errorf("error in fetching values of deployment %d: %w", runner.Id, err)
+	}
+	chartRefId, err := impl.deploymentDriftRepository.FindChartRefIdByPipelineOverrideId(pipelineOverride.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error in fetching chart of deployment %d: %w", runner.Id, err)
+	}
+	k8sVersion, err := impl.k8sCommonService.GetK8sServerVersion(pipeline.ClusterId)
+	if err != nil {
+		return nil, fmt.Errorf("error in fetching kube version of cluster %d: %w", pipeline.ClusterId, err)
+	}
+	manifest, err := impl.deploymentConfigurationService.GetDeploymentManifest(ctx, pipeline.AppId, pipeline.EnvId, chartRefId, k8sVersion.String(), pipelineOverride.PipelineMergedValues)
+	if err != nil {
+		return nil, fmt.Errorf("error in rendering manifest of deployment %d: %w", runner.Id, err)
+	}
+	desiredObjects, err := kube.SplitYAML([]byte(manifest.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing manifest of deployment %d: %w", runner.Id, err)
+	}
+	ignoredManagers := impl.config.GetIgnoredManagers()
+	resources := make([]*bean.ResourceDrift, 0)
+	for _, desired := range desiredObjects {
+		if IsHelmHook(desired) {
+			continue
+		}
+		gvk := desired.GroupVersionKind()
+		namespace := desired.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = pipeline.Namespace
+		}
+		resource := &bean.ResourceDrift{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Name:      desired.GetName(),
+			Namespace: namespace,
+		}
+		request := &k8sBean.ResourceRequestBean{
+			ClusterId: pipeline.ClusterId,
+			K8sRequest: &k8s.K8sRequestBean{
+				ResourceIdentifier: k8s.ResourceIdentifier{
+					Name:             desired.GetName(),
+					Namespace:        namespace,
+					GroupVersionKind: gvk,
+				},
+			},
+		}
+		live, err := impl.k8sCommonService.GetResource(ctx, request)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				resource.Type = bean.ResourceDriftMissing
+				resources = append(resources, resource)
+				continue
+			}
+			return nil, fmt.Errorf("error in fetching %s %s: %w", gvk.Kind, desired.GetName(), err)
+		}
+		fields := DiffResource(desired, &live.ManifestResponse.Manifest, ignoredManagers)
+		if len(fields) == 0 {
+			continue
+		}
+		resource.Type = bean.ResourceDriftModified
+		if maxFields := impl.config.MaxFieldsPerResource; maxFields > 0 && len(fields) > maxFields {
+			resource.TruncatedFields = len(fields) - maxFields
+			fields = fields[:maxFields]
+		}
+		resource.Fields = fields
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func (impl *DeploymentDriftServiceImpl) notify(pipeline *repository.DriftScanPipeline, cdWorkflowRunnerId int, resources []*bean.ResourceDrift) {
+	event, err := impl.eventFactory.Build(eventUtil.ConfigDrift, &pipeline.PipelineId, pipeline.AppId, &pipeline.EnvId, eventUtil.CD)
+	if err != nil {
+		impl.logger.Errorw("error in building drift notification event", "pipelineId", pipeline.PipelineId, "err", err)
+		return
+	}
+	event.CdWorkflowRunnerId = cdWorkflowRunnerId
+	event.Payload = &client.Payload{DriftSummary: getDriftMessage(resources)}
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending drift notification", "pipelineId", pipeline.PipelineId, "err", err)
+	}
+}
+
+// reconcile redeploys the artifact and configuration of the deployment the live resources drifted from
+func (impl *DeploymentDriftServiceImpl) reconcile(pipeline *repository.DriftScanPipeline, runner *pipelineConfig.CdWorkflowRunner) error {
+	overrideRequest := &apiBean.ValuesOverrideRequest{
+		PipelineId:                            pipeline.PipelineId,
+		AppId:                                 pipeline.AppId,
+		CiArtifactId:                          runner.CdWorkflow.CiArtifactId,
+		CdWorkflowType:                        apiBean.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentType:                        models.DEPLOYMENTTYPE_DEPLOY,
+		DeploymentWithConfig:                  apiBean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER,
+		WfrIdForDeploymentWithSpecificTrigger: runner.Id,
+		UserId:                                userBean.SYSTEM_USER_ID,
+	}
+	userMetadata := &userBean.UserMetadata{
+		UserId:           userBean.SYSTEM_USER_ID,
+		IsUserSuperAdmin: true,
+	}
+	triggerContext := triggerBean.TriggerContext{Context: context.Background()}
+	_, _, _, err := impl.cdHandlerService.ManualCdTrigger(triggerContext, overrideRequest, userMetadata)
+	if err != nil {
+		impl.logger.Errorw("error in reconciling deployment drift", "pipelineId", pipeline.PipelineId, "cdWorkflowRunnerId", runner.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func newDeploymentDrift(pipeline *repository.DriftScanPipeline, userId int32) *repository.DeploymentDrift {
+	return &repository.DeploymentDrift{
+		PipelineId: pipeline.PipelineId,
+		AppId:      pipeline.AppId,
+		EnvId:      pipeline.EnvId,
+		Status:     string(bean.DriftStatusUnknown),
+		Message:    "not scanned yet",
+		AuditLog:   sql.NewDefaultAuditLog(userId),
+	}
+}
+
+func getDriftedResources(model *repository.DeploymentDrift) ([]*bean.ResourceDrift, error) {
+	resources := make([]*bean.ResourceDrift, 0)
+	if len(model.DriftedResources) == 0 {
+		return resources, nil
+	}
+	err := json.Unmarshal([]byte(model.DriftedResources), &resources)
+	return resources, err
+}
+
+// getDriftMessage lists the drifted resources, like "2 resources drifted: Deployment/payments (3 fields), ConfigMap/payments-cm (missing)"
+func getDriftMessage(resources []*bean.ResourceDrift) string {
+	descriptions := make([]string, 0, maxResourcesInNotification)
+	for i, resource := range resources {
+		if i == maxResourcesInNotification {
+			descriptions = append(descriptions, fmt.Sprintf("and %d more", len(resources)-maxResourcesInNotification))
+			break
+		}
+		if resource.Type == bean.ResourceDriftMissing {
+			descriptions = append(descriptions, fmt.Sprintf("%s/%s (missing)", resource.Kind, resource.Name))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("%s/%s (%d fields)", resource.Kind, resource.Name, len(resource.Fields)+resource.TruncatedFields))
+		}
+	}
+	return fmt.Sprintf("%d resources drifted: %s", len(resources), strings.Join(descriptions, ", "))
+}
+
+func adaptDriftSummary(model *repository.DeploymentDrift, driftedResourceCount int) *bean.DriftSummary {
+	return &bean.DriftSummary{
+		PipelineId:           model.PipelineId,
+		Status:               bean.DriftStatus(model.Status),
+		DriftedResourceCount: driftedResourceCount,
+		Message:              model.Message,
+		CdWorkflowRunnerId:   model.CdWorkflowRunnerId,
+		ScannedOn:            model.ScannedOn,
+		DriftDetectedOn:      model.DriftDetectedOn,
+		ReconciledOn:         model.ReconciledOn,
+		Config: &bean.DriftConfig{
+			Notify:        model.Notify,
+			AutoReconcile: model.AutoReconcile,
+		},
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/caarlos0/env"
+	"strings"
+	"time"
+)
+
+// CATEGORY=CD
+type DeploymentDriftConfig struct {
+	ScanEnabled          bool   `env:"DEPLOYMENT_DRIFT_SCAN_ENABLED" envDefault:"false" description:"enables the periodic drift scan of the helm deployed devtron apps against their live cluster resources"`
+	ScanCronTime         string `env:"DEPLOYMENT_DRIFT_SCAN_CRON_TIME" envDefault:"@every 30m" description:"cron schedule of the deployment drift scan"`
+	IgnoredManagers      string `env:"DEPLOYMENT_DRIFT_IGNORED_MANAGERS" envDefault:"kube-controller-manager,kube-scheduler,kubelet,cluster-autoscaler,vpa-updater,vpa-admission-controller,keda-operator" description:"comma separated field managers whose changes to the live resources are not reported as drift"`
+	MaxFieldsPerResource int    `env:"DEPLOYMENT_DRIFT_MAX_FIELDS_PER_RESOURCE" envDefault:"20" description:"maximum drifted fields reported per resource"`
+}
+
+func (config *DeploymentDriftConfig) GetIgnoredManagers() []string {
+	managers := make([]string, 0)
+	for _, manager := range strings.Split(config.IgnoredManagers, ",") {
+		manager = strings.TrimSpace(manager)
+		if len(manager) > 0 {
+			managers = append(managers, manager)
+		}
+	}
+	return managers
+}
+
+type DriftStatus string
+
+const (
+	// DriftStatusInSync live resources match the last successful deployment
+	DriftStatusInSync DriftStatus = "InSync"
+	// DriftStatusDrifted live resources were changed outside devtron
+	DriftStatusDrifted DriftStatus = "Drifted"
+	// DriftStatusUnknown the pipeline could not be compared, e.g. its latest deployment failed
+	DriftStatusUnknown DriftStatus = "Unknown"
+	// DriftStatusError the scan failed
+	DriftStatusError DriftStatus = "Error"
+)
+
+type ResourceDriftType string
+
+const (
+	ResourceDriftModified ResourceDriftType = "Modified"
+	ResourceDriftMissing  ResourceDriftType = "Missing"
+)
+
+// MaskedValue replaces the values of the drifted fields of secrets
+const MaskedValue = "********"
+
+// FieldDrift is a field of the desired manifest whose live value differs, Path is like spec.template.spec.containers[0].image
+type FieldDrift struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired"`
+	Live    interface{} `json:"live"`
+}
+
+type ResourceDrift struct {
+	Group     string            `json:"group"`
+	Version   string            `json:"version"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      ResourceDriftType `json:"type"`
+	Fields    []*FieldDrift     `json:"fields,omitempty"`
+	// TruncatedFields is the count of the drifted fields not listed in Fields
+	TruncatedFields int `json:"truncatedFields,omitempty"`
+}
+
+// DriftConfig is the drift handling of a cd pipeline, drift is always reported in the app details
+type DriftConfig struct {
+	// Notify sends a CONFIG DRIFT notification when drift is detected or changes
+	Notify bool `json:"notify"`
+	// AutoReconcile redeploys the last successful deployment once per drift
+	AutoReconcile bool `json:"autoReconcile"`
+}
+
+type DriftConfigRequest struct {
+	AppId  int   `json:"appId" validate:"required,number,gt=0"`
+	EnvId  int   `json:"envId" validate:"required,number,gt=0"`
+	UserId int32 `json:"-"`
+	DriftConfig
+}
+
+// DriftSummary is the drift of a pipeline as shown in the app details
+type DriftSummary struct {
+	PipelineId           int         `json:"pipelineId"`
+	Status               DriftStatus `json:"status"`
+	DriftedResourceCount int         `json:"driftedResourceCount"`
+	Message              string      `json:"message,omitempty"`
+	// CdWorkflowRunnerId is the deployment the live resources were compared against
+	CdWorkflowRunnerId int          `json:"cdWorkflowRunnerId,omitempty"`
+	ScannedOn          *time.Time   `json:"scannedOn,omitempty"`
+	DriftDetectedOn    *time.Time   `json:"driftDetectedOn,omitempty"`
+	ReconciledOn       *time.Time   `json:"reconciledOn,omitempty"`
+	Config             *DriftConfig `json:"config"`
+}
+
+type DriftDetail struct {
+	*DriftSummary
+	Resources []*ResourceDrift `json:"resources"`
+}
+
+func GetDeploymentDriftConfig() (*DeploymentDriftConfig, error) {
+	config := &DeploymentDriftConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
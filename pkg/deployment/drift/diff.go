@@ -0,0 +1,374 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	kindSecret            = "Secret"
+	secretDataField       = "data"
+	secretStringDataField = "stringData"
+	helmHookAnnotation    = "helm.sh/hook"
+)
+
+var plainPathKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DiffResource returns the fields of the desired manifest whose live values differ. Only the fields set in the desired
+// manifest are compared, so the defaults and the fields added to the live resource by the cluster are not drift, and
+// the fields last written by one of the ignoredManagers (like the replicas scaled by an hpa) are skipped
+func DiffResource(desired, live *unstructured.Unstructured, ignoredManagers []string) []*bean.FieldDrift {
+	owned := getManagedFields(live, ignoredManagers)
+	desiredObj := normaliseDesiredObject(desired)
+	d := &differ{drifts: make([]*bean.FieldDrift, 0)}
+	for _, key := range sortedKeys(desiredObj) {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			// the rest of the metadata is owned by the cluster or by helm
+			desiredMetadata, _ := desiredObj[key].(map[string]interface{})
+			liveMetadata, _ := live.Object[key].(map[string]interface{})
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				d.compare(joinPath(key, metadataKey), desiredMetadata[metadataKey], liveMetadata[metadataKey], owned.field(key).field(metadataKey))
+			}
+		default:
+			d.compare(key, desiredObj[key], live.Object[key], owned.field(key))
+		}
+	}
+	if desired.GetKind() == kindSecret {
+		maskSecretValues(d.drifts)
+	}
+	return d.drifts
+}
+
+// IsHelmHook hooks are created and deleted by helm around the release, they are not a part of the live release
+func IsHelmHook(obj *unstructured.Unstructured) bool {
+	_, ok := obj.GetAnnotations()[helmHookAnnotation]
+	return ok
+}
+
+type differ struct {
+	drifts []*bean.FieldDrift
+}
+
+func (d *differ) add(path string, desired, live interface{}) {
+	d.drifts = append(d.drifts, &bean.FieldDrift{Path: path, Desired: desired, Live: live})
+}
+
+func (d *differ) compare(path string, desired, live interface{}, owned managedFields) {
+	if desired == nil || owned.ownsValue() {
+		return
+	}
+	if live == nil {
+		// the api server drops the empty and zero valued optional fields
+		if !isZero(desired) {
+			d.add(path, desired, nil)
+		}
+		return
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			d.add(path, desired, live)
+			return
+		}
+		for _, key := range sortedKeys(desiredValue) {
+			d.compare(joinPath(path, key), desiredValue[key], liveValue[key], owned.field(key))
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			d.add(path, desired, live)
+			return
+		}
+		// elements added by the ignored managers are not a part of the desired list
+		liveElements := make([]listElement, 0, len(liveValue))
+		for i, element := range liveValue {
+			elementOwned := owned.element(i, element)
+			if !elementOwned.ownsElement() {
+				liveElements = append(liveElements, listElement{value: element, owned: elementOwned})
+			}
+		}
+		if len(desiredValue) != len(liveElements) {
+			d.add(path, desired, live)
+			return
+		}
+		for i := range desiredValue {
+			d.compare(fmt.Sprintf("%s[%d]", path, i), desiredValue[i], liveElements[i].value, liveElements[i].owned)
+		}
+	default:
+		if !valuesEqual(desired, live) {
+			d.add(path, desired, live)
+		}
+	}
+}
+
+type listElement struct {
+	value interface{}
+	owned managedFields
+}
+
+// managedFields are the FieldsV1 trees (like {"f:spec":{"f:replicas":{}}}) of the fields of a resource written by
+// a set of managers, walked along with the compared fields
+type managedFields []map[string]interface{}
+
+func getManagedFields(live *unstructured.Unstructured, managers []string) managedFields {
+	fields := make(managedFields, 0)
+	for _, entry := range live.GetManagedFields() {
+		if entry.FieldsV1 == nil || !slices.Contains(managers, entry.Manager) {
+			continue
+		}
+		tree := make(map[string]interface{})
+		err := json.Unmarshal(entry.FieldsV1.Raw, &tree)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, tree)
+	}
+	return fields
+}
+
+func (fields managedFields) child(key string) managedFields {
+	var children managedFields
+	for _, tree := range fields {
+		if child, ok := tree[key].(map[string]interface{}); ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func (fields managedFields) field(name string) managedFields {
+	return fields.child("f:" + name)
+}
+
+// element returns the fields of the list element, elements are identified by their keys (k:), value (v:) or index (i:)
+func (fields managedFields) element(index int, value interface{}) managedFields {
+	var children managedFields
+	for _, tree := range fields {
+		for key, child := range tree {
+			childTree, ok := child.(map[string]interface{})
+			if ok && matchesListElement(key, index, value) {
+				children = append(children, childTree)
+			}
+		}
+	}
+	return children
+}
+
+// ownsValue a leaf of the tree owns the whole value of the field
+func (fields managedFields) ownsValue() bool {
+	for _, tree := range fields {
+		if len(tree) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsElement the element of a list (or a map) was added by the manager
+func (fields managedFields) ownsElement() bool {
+	if fields.ownsValue() {
+		return true
+	}
+	for _, tree := range fields {
+		if _, ok := tree["."]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesListElement(key string, index int, value interface{}) bool {
+	switch {
+	case strings.HasPrefix(key, "i:"):
+		return key == fmt.Sprintf("i:%d", index)
+	case strings.HasPrefix(key, "v:"):
+		var keyValue interface{}
+		if json.Unmarshal([]byte(strings.TrimPrefix(key, "v:")), &keyValue) != nil {
+			return false
+		}
+		return valuesEqual(keyValue, value)
+	case strings.HasPrefix(key, "k:"):
+		keyFields := make(map[string]interface{})
+		if json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &keyFields) != nil || len(keyFields) == 0 {
+			return false
+		}
+		element, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for name, keyValue := range keyFields {
+			if !valuesEqual(keyValue, element[name]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// valuesEqual compares the scalars of the rendered and the live manifests, the numbers and quantities are compared
+// by value as the api server stores them in their canonical form (like 1000m as 1)
+func valuesEqual(desired, live interface{}) bool {
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+	if desired == nil || live == nil {
+		return false
+	}
+	desiredNumber, isDesiredNumber := toFloat(desired)
+	liveNumber, isLiveNumber := toFloat(live)
+	if isDesiredNumber && isLiveNumber {
+		return desiredNumber == liveNumber
+	}
+	if isComposite(desired) || isComposite(live) {
+		return false
+	}
+	desiredString, liveString := fmt.Sprint(desired), fmt.Sprint(live)
+	if desiredString == liveString {
+		return true
+	}
+	desiredQuantity, err := resource.ParseQuantity(desiredString)
+	if err != nil {
+		return false
+	}
+	liveQuantity, err := resource.ParseQuantity(liveString)
+	if err != nil {
+		return false
+	}
+	return desiredQuantity.Cmp(liveQuantity) == 0
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float32:
+		return float64(number), true
+	case float64:
+		return number, true
+	case json.Number:
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isComposite(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(v) == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, child := range v {
+			if !isZero(child) {
+				return false
+			}
+		}
+		return true
+	}
+	number, ok := toFloat(value)
+	return ok && number == 0
+}
+
+// normaliseDesiredObject returns a copy of the desired object as stored by the api server
+func normaliseDesiredObject(desired *unstructured.Unstructured) map[string]interface{} {
+	obj := runtime.DeepCopyJSON(desired.Object)
+	if desired.GetKind() != kindSecret {
+		return obj
+	}
+	// stringData is write only, it is merged into data
+	stringData, ok := obj[secretStringDataField].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+	data, ok := obj[secretDataField].(map[string]interface{})
+	if !ok {
+		data = make(map[string]interface{})
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+	obj[secretDataField] = data
+	delete(obj, secretStringDataField)
+	return obj
+}
+
+func maskSecretValues(drifts []*bean.FieldDrift) {
+	for _, drift := range drifts {
+		if !strings.HasPrefix(drift.Path, secretDataField) {
+			continue
+		}
+		if drift.Desired != nil {
+			drift.Desired = bean.MaskedValue
+		}
+		if drift.Live != nil {
+			drift.Live = bean.MaskedValue
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if !plainPathKeyRegex.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/drift/bean"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+var ignoredManagers = []string{"kube-controller-manager"}
+
+const desiredDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: payments
+  labels:
+    app: payments
+    app.kubernetes.io/managed-by: Helm
+spec:
+  replicas: 2
+  template:
+    spec:
+      hostNetwork: false
+      containers:
+        - name: payments
+          image: payments:v1
+          ports:
+            - containerPort: 8080
+          resources:
+            limits:
+              cpu: 1000m
+              memory: 1Gi
+          env: []
+`
+
+const liveDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: payments
+  namespace: prod
+  uid: 9d0b2d1c
+  labels:
+    app: payments
+    app.kubernetes.io/managed-by: Helm
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+  managedFields:
+    - manager: helm
+      operation: Update
+      fieldsType: FieldsV1
+      fieldsV1: {"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"payments\"}":{".":{},"f:image":{}}}}}}}
+    - manager: kube-controller-manager
+      operation: Update
+      subresource: scale
+      fieldsType: FieldsV1
+      fieldsV1: {"f:spec":{"f:replicas":{}}}
+spec:
+  replicas: 5
+  revisionHistoryLimit: 10
+  template:
+    spec:
+      dnsPolicy: ClusterFirst
+      containers:
+        - name: payments
+          image: payments:v1
+          imagePullPolicy: IfNotPresent
+          ports:
+            - containerPort: 8080
+              protocol: TCP
+          resources:
+            limits:
+              cpu: "1"
+              memory: 1024Mi
+status:
+  replicas: 5
+`
+
+func toUnstructured(t *testing.T, manifest string) *unstructured.Unstructured {
+	obj := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(manifest), &obj)
+	assert.Nil(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestDiffResource(t *testing.T) {
+	t.Run("defaults, canonical quantities and ignored managers are not drift", func(t *testing.T) {
+		drifts := DiffResource(toUnstructured(t, desiredDeployment), toUnstructured(t, liveDeployment), ignoredManagers)
+		assert.Empty(t, drifts)
+	})
+	t.Run("changes by other managers are drift", func(t *testing.T) {
+		live := toUnstructured(t, liveDeployment)
+		containers, _, _ := unstructured.NestedSlice(live.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]interface{})["image"] = "payments:hotfix"
+		assert.Nil(t, unstructured.SetNestedSlice(live.Object, containers, "spec", "template", "spec", "containers"))
+		live.SetLabels(map[string]string{"app": "payments", "app.kubernetes.io/managed-by": "kubectl"})
+
+		drifts := DiffResource(toUnstructured(t, desiredDeployment), live, nil)
+		assert.Equal(t, []*bean.FieldDrift{
+			{Path: `metadata.labels["app.kubernetes.io/managed-by"]`, Desired: "Helm", Live: "kubectl"},
+			{Path: "spec.replicas", Desired: float64(2), Live: float64(5)},
+			{Path: "spec.template.spec.containers[0].image", Desired: "payments:v1", Live: "payments:hotfix"},
+		}, drifts)
+	})
+	t.Run("list elements added by ignored managers are skipped", func(t *testing.T) {
+		desired := toUnstructured(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: payments
+spec:
+  ports:
+    - port: 80
+      targetPort: http
+`)
+		live := toUnstructured(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: payments
+  managedFields:
+    - manager: kube-controller-manager
+      operation: Update
+      fieldsType: FieldsV1
+      fieldsV1: {"f:spec":{"f:ports":{"k:{\"port\":9090}":{".":{},"f:port":{}}}}}
+spec:
+  ports:
+    - port: 80
+      targetPort: http
+      protocol: TCP
+    - port: 9090
+`)
+		assert.Empty(t, DiffResource(desired, live, ignoredManagers))
+		drifts := DiffResource(desired, live, nil)
+		assert.Len(t, drifts, 1)
+		assert.Equal(t, "spec.ports", drifts[0].Path)
+	})
+	t.Run("secret values are compared encoded and masked", func(t *testing.T) {
+		desired := toUnstructured(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: payments
+stringData:
+  password: admin
+data:
+  user: YWRtaW4=
+`)
+		live := toUnstructured(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: payments
+data:
+  password: YWRtaW4=
+  user: cm9vdA==
+`)
+		assert.Equal(t, []*bean.FieldDrift{
+			{Path: "data.user", Desired: bean.MaskedValue, Live: bean.MaskedValue},
+		}, DiffResource(desired, live, nil))
+	})
+}
+
+func TestValuesEqual(t *testing.T) {
+	assert.True(t, valuesEqual(int64(80), float64(80)))
+	assert.True(t, valuesEqual("80", int64(80)))
+	assert.True(t, valuesEqual("500m", "0.5"))
+	assert.True(t, valuesEqual("1Gi", "1024Mi"))
+	assert.False(t, valuesEqual("1Gi", "1G"))
+	assert.False(t, valuesEqual("http", "https"))
+	assert.False(t, valuesEqual(map[string]interface{}{"a": "1"}, "1"))
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DeploymentDrift is the last drift scan of a helm deployed cd pipeline along with its drift handling config
+type DeploymentDrift struct {
+	tableName          struct{}   `sql:"deployment_drift" pg:",discard_unknown_columns"`
+	Id                 int        `sql:"id,pk"`
+	PipelineId         int        `sql:"pipeline_id,notnull"`
+	AppId              int        `sql:"app_id,notnull"`
+	EnvId              int        `sql:"env_id,notnull"`
+	Status             string     `sql:"status"`
+	DriftedResources   string     `sql:"drifted_resources"`
+	Message            string     `sql:"message"`
+	CdWorkflowRunnerId int        `sql:"cd_workflow_runner_id"`
+	Notify             bool       `sql:"notify,notnull"`
+	AutoReconcile      bool       `sql:"auto_reconcile,notnull"`
+	ScannedOn          *time.Time `sql:"scanned_on"`
+	DriftDetectedOn    *time.Time `sql:"drift_detected_on"`
+	NotifiedOn         *time.Time `sql:"notified_on"`
+	ReconciledOn       *time.Time `sql:"reconciled_on"`
+	sql.AuditLog
+}
+
+// DriftScanPipeline is a helm deployed cd pipeline of an active devtron app
+type DriftScanPipeline struct {
+	PipelineId int    `sql:"pipeline_id"`
+	AppId      int    `sql:"app_id"`
+	EnvId      int    `sql:"env_id"`
+	ClusterId  int    `sql:"cluster_id"`
+	Namespace  string `sql:"namespace"`
+}
+
+type DeploymentDriftRepository interface {
+	FindByPipelineId(pipelineId int) (*DeploymentDrift, error)
+	FindByAppIdAndEnvId(appId, envId int) (*DeploymentDrift, error)
+	Save(model *DeploymentDrift) error
+	Update(model *DeploymentDrift) error
+	// ClaimScan moves scanned_on of the drift from lastScannedOn to scannedOn, returns false if the drift was
+	// already claimed for a scan (by another replica)
+	ClaimScan(id int, lastScannedOn *time.Time, scannedOn time.Time) (bool, error)
+	FindPipelinesToScan() ([]*DriftScanPipeline, error)
+	FindPipelineToScan(appId, envId int) (*DriftScanPipeline, error)
+	// FindChartRefIdByPipelineOverrideId returns the chart ref of the chart the pipeline override was deployed with
+	FindChartRefIdByPipelineOverrideId(pipelineOverrideId int) (int, error)
+}
+
+type DeploymentDriftRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentDriftRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentDriftRepositoryImpl {
+	return &DeploymentDriftRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentDriftRepositoryImpl) FindByPipelineId(pipelineId int) (*DeploymentDrift, error) {
+	model := &DeploymentDrift{}
+	err := impl.dbConnection.Model(model).
+		Where("pipeline_id = ?", pipelineId).
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentDriftRepositoryImpl) FindByAppIdAndEnvId(appId, envId int) (*DeploymentDrift, error) {
+	model := &DeploymentDrift{}
+	err := impl.dbConnection.Model(model).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentDriftRepositoryImpl) Save(model *DeploymentDrift) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeploymentDriftRepositoryImpl) Update(model *DeploymentDrift) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *DeploymentDriftRepositoryImpl) ClaimScan(id int, lastScannedOn *time.Time, scannedOn time.Time) (bool, error) {
+	res, err := impl.dbConnection.Model(&DeploymentDrift{}).
+		Set("scanned_on = ?", scannedOn).
+		Where("id = ?", id).
+		Where("scanned_on IS NOT DISTINCT FROM ?", lastScannedOn).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// driftScanPipelineQuery selects the created helm pipelines, deployment_config overrides the deployment app type of the pipeline
+const driftScanPipelineQuery = `SELECT p.id AS pipeline_id, p.app_id, p.environment_id AS env_id, e.cluster_id, e.namespace
+	FROM pipeline p
+	INNER JOIN app a ON a.id = p.app_id
+	INNER JOIN environment e ON e.id = p.environment_id
+	LEFT JOIN deployment_config dc ON dc.active = true AND dc.app_id = p.app_id AND dc.environment_id = p.environment_id
+	WHERE p.deleted = false AND p.deployment_app_created = true AND a.active = true AND e.is_virtual_environment = false
+	AND COALESCE(dc.deployment_app_type, p.deployment_app_type) = 'helm'`
+
+func (impl *DeploymentDriftRepositoryImpl) FindPipelinesToScan() ([]*DriftScanPipeline, error) {
+	var pipelines []*DriftScanPipeline
+	_, err := impl.dbConnection.Query(&pipelines, driftScanPipelineQuery+" ORDER BY p.id;")
+	return pipelines, err
+}
+
+func (impl *DeploymentDriftRepositoryImpl) FindPipelineToScan(appId, envId int) (*DriftScanPipeline, error) {
+	pipeline := &DriftScanPipeline{}
+	_, err := impl.dbConnection.QueryOne(pipeline, driftScanPipelineQuery+" AND p.app_id = ? AND p.environment_id = ? LIMIT 1;", appId, envId)
+	return pipeline, err
+}
+
+func (impl *DeploymentDriftRepositoryImpl) FindChartRefIdByPipelineOverrideId(pipelineOverrideId int) (int, error) {
+	var chartRefId int
+	query := `SELECT c.chart_ref_id
+		FROM pipeline_config_override pco
+		INNER JOIN chart_env_config_override ceco ON ceco.id = pco.env_config_override_id
+		INNER JOIN charts c ON c.id = ceco.chart_id
+		WHERE pco.id = ?;`
+	_, err := impl.dbConnection.QueryOne(pg.Scan(&chartRefId), query, pipelineOverrideId)
+	return chartRefId, err
+}
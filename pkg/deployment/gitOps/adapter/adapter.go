@@ -36,6 +36,7 @@ func GetGitOpsConfigBean(model *repository.GitOpsConfig) *apiGitOpsBean.GitOpsCo
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgId:            model.GiteaOrgId,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: true,
 		TLSConfig: &apiBean.TLSConfig{
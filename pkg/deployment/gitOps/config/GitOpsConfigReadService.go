@@ -224,6 +224,7 @@ func (impl *GitOpsConfigReadServiceImpl) GetGitOpsById(id int) (*bean2.GitOpsCon
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgId:            model.GiteaOrgId,
 		AllowCustomRepository: model.AllowCustomRepository,
 		TLSConfig: &bean3.TLSConfig{
 			CaData:      model.CaCert,
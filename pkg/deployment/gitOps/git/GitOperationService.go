@@ -438,6 +438,17 @@ func (impl *GitOperationServiceImpl) UpdateGitHostUrlByProvider(request *apiBean
 		}
 	case BITBUCKET_PROVIDER:
 		request.Host = BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
+	case GITEA_PROVIDER:
+		// repos are created under the configured organisation, or under the user when no organisation is given
+		owner := request.GiteaOrgId
+		if len(owner) == 0 {
+			owner = request.Username
+		}
+		orgUrl, err := buildGithubOrgUrl(request.Host, owner)
+		if err != nil {
+			return err
+		}
+		request.Host = orgUrl
 	}
 	return nil
 }
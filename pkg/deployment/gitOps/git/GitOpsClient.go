@@ -62,6 +62,7 @@ func GetGitConfig(gitOpsConfigReadService config.GitOpsConfigReadService) (*bean
 		AzureProject:          gitOpsConfig.AzureProjectName,
 		BitbucketWorkspaceId:  gitOpsConfig.BitBucketWorkspaceId,
 		BitbucketProjectKey:   gitOpsConfig.BitBucketProjectKey,
+		GiteaOrganization:     gitOpsConfig.GiteaOrgId,
 		EnableTLSVerification: gitOpsConfig.EnableTLSVerification,
 		TLSCert:               gitOpsConfig.TLSConfig.TLSCertData,
 		TLSKey:                gitOpsConfig.TLSConfig.TLSKeyData,
@@ -94,6 +95,9 @@ func NewGitOpsClient(config *bean.GitConfig, logger *zap.SugaredLogger, gitOpsHe
 	} else if config.GitProvider == BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitOpsHelper, tlsConfig)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GitUserName, config.GiteaOrganization, logger, gitOpsHelper, tlsConfig)
+		return gitGiteaClient, err
 	} else {
 		logger.Errorw("no gitops config provided, gitops will not work ")
 		return nil, nil
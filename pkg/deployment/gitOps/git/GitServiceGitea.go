@@ -0,0 +1,422 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	bean2 "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/retryFunc"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	GITEA_API_V1 = "api/v1"
+)
+
+// GiteaErrorResponse is returned for every non 2xx response of the gitea api
+type GiteaErrorResponse struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+}
+
+func (e *GiteaErrorResponse) Error() string {
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+}
+
+func IsGiteaRepoNotFound(err error) bool {
+	var responseErr *GiteaErrorResponse
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+type giteaRepository struct {
+	Name          string `json:"name"`
+	CloneUrl      string `json:"clone_url"`
+	Empty         bool   `json:"empty"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaCreateRepoOption struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	AutoInit      bool   `json:"auto_init"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+type giteaContents struct {
+	Sha string `json:"sha"`
+}
+
+type giteaIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type giteaFileOption struct {
+	Content   string         `json:"content"`
+	Message   string         `json:"message"`
+	Branch    string         `json:"branch"`
+	Sha       string         `json:"sha,omitempty"`
+	Author    *giteaIdentity `json:"author,omitempty"`
+	Committer *giteaIdentity `json:"committer,omitempty"`
+}
+
+type giteaFileResponse struct {
+	Commit *struct {
+		Sha    string `json:"sha"`
+		Author *struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// GitGiteaClient talks to the gitea (or forgejo) rest api v1, repositories are created under
+// the configured organisation or under the authenticated user if no organisation is configured
+type GitGiteaClient struct {
+	client       *http.Client
+	baseUrl      *url.URL
+	token        string
+	owner        string
+	isOrg        bool
+	logger       *zap.SugaredLogger
+	gitOpsHelper *GitOpsHelper
+}
+
+func NewGitGiteaClient(host, token, username, org string, logger *zap.SugaredLogger, gitOpsHelper *GitOpsHelper, tlsConfig *tls.Config) (GitGiteaClient, error) {
+	if !strings.HasPrefix(host, HTTP_URL_PROTOCOL) && !strings.HasPrefix(host, HTTPS_URL_PROTOCOL) {
+		return GitGiteaClient{}, fmt.Errorf("invalid host url '%s'", host)
+	}
+	baseUrl, err := url.Parse(host)
+	if err != nil {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GitGiteaClient{}, err
+	}
+	baseUrl.Path = path.Join(baseUrl.Path, GITEA_API_V1)
+	owner := org
+	if len(owner) == 0 {
+		owner = username
+	}
+	logger.Infow("gitea client created", "host", host, "owner", owner)
+	return GitGiteaClient{
+		client:       util.GetHTTPClientWithTLSConfig(tlsConfig),
+		baseUrl:      baseUrl,
+		token:        token,
+		owner:        owner,
+		isOrg:        len(org) > 0,
+		logger:       logger,
+		gitOpsHelper: gitOpsHelper,
+	}, nil
+}
+
+func (impl GitGiteaClient) DeleteRepository(config *bean2.GitOpsConfigDto) (err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("DeleteRepository", "GitGiteaClient", start, err)
+	}()
+	err = impl.doRequest(context.Background(), http.MethodDelete, nil, nil, nil, "repos", impl.owner, config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return err
+}
+
+func (impl GitGiteaClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, isRepoEmpty bool, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("GetRepoUrl", "GitGiteaClient", start, err)
+	}()
+	repo, err := impl.getRepository(context.Background(), config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("error in getting repo url by repo name", "owner", impl.owner, "gitRepoName", config.GitRepoName, "err", err)
+		return "", false, err
+	}
+	return repo.CloneUrl, repo.Empty, nil
+}
+
+func (impl GitGiteaClient) CreateRepository(ctx context.Context, config *bean2.GitOpsConfigDto) (url string, isNew bool, isEmpty bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	var err error
+	start := time.Now()
+
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repo, err := impl.getRepository(ctx, config.GitRepoName)
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, nil)
+		return repo.CloneUrl, false, repo.Empty, detailedErrorGitOpsConfigActions
+	} else if !IsGiteaRepoNotFound(err) {
+		impl.logger.Errorw("error in creating gitea repo", "repo", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+
+	branch := config.TargetRevision
+	if len(branch) == 0 {
+		branch = util.GetDefaultTargetRevision()
+	}
+	createOptions := &giteaCreateRepoOption{
+		Name:          config.GitRepoName,
+		Description:   config.Description,
+		Private:       true,
+		AutoInit:      true,
+		DefaultBranch: branch,
+	}
+	repo = &giteaRepository{}
+	if impl.isOrg {
+		err = impl.doRequest(ctx, http.MethodPost, nil, createOptions, repo, "orgs", impl.owner, "repos")
+	} else {
+		err = impl.doRequest(ctx, http.MethodPost, nil, createOptions, repo, "user", "repos")
+	}
+	if err != nil {
+		impl.logger.Errorw("error in creating gitea repo", "repo", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateRepoStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created", "repoUrl", repo.CloneUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneHttpStage)
+
+	_, err = impl.CreateReadme(ctx, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repo.CloneUrl, config.TargetRevision)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneSshStage)
+	util.TriggerGitOpsMetrics("CreateRepository", "GitGiteaClient", start, nil)
+	return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitGiteaClient) CreateReadme(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("CreateReadme", "GitGiteaClient", start, err)
+	}()
+
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  config.GitRepoName,
+		TargetRevision: config.TargetRevision,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(ctx, cfg, config, true)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+func (impl GitGiteaClient) CommitValues(ctx context.Context, config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto, publishStatusConflictErrorMetrics bool) (commitHash string, commitTime time.Time, err error) {
+	start := time.Now()
+
+	branch := config.TargetRevision
+	if len(branch) == 0 {
+		branch = impl.getDefaultBranch(ctx, config.ChartRepoName)
+	}
+	filePath := filepath.Join(config.ChartLocation, config.FileName)
+	currentSHA := ""
+	contents := &giteaContents{}
+	err = impl.doRequest(ctx, http.MethodGet, url.Values{"ref": []string{branch}}, nil, contents, "repos", impl.owner, config.ChartRepoName, "contents", filePath)
+	if err == nil {
+		currentSHA = contents.Sha
+	} else if !IsGiteaRepoNotFound(err) {
+		impl.logger.Errorw("error in getting file contents gitea", "repo", config.ChartRepoName, "filePath", filePath, "err", err)
+		util.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		return "", time.Time{}, err
+	}
+	author := &giteaIdentity{Name: config.UserName, Email: config.UserEmailId}
+	fileOptions := &giteaFileOption{
+		Content:   base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		Message:   config.ReleaseMessage,
+		Branch:    branch,
+		Sha:       currentSHA,
+		Author:    author,
+		Committer: author,
+	}
+	// new files are created with POST, existing files are updated with PUT along with their current sha
+	method := http.MethodPost
+	if len(currentSHA) > 0 {
+		method = http.MethodPut
+	}
+	fileResponse := &giteaFileResponse{}
+	err = impl.doRequest(ctx, method, nil, fileOptions, fileResponse, "repos", impl.owner, config.ChartRepoName, "contents", filePath)
+	var responseErr *GiteaErrorResponse
+	if err != nil && errors.As(err, &responseErr) &&
+		(responseErr.StatusCode == http.StatusConflict || responseErr.StatusCode == http.StatusUnprocessableEntity) {
+		// sha mismatch or file already created by a concurrent commit
+		impl.logger.Warnw("conflict found in commit gitea", "err", err, "config", config)
+		if publishStatusConflictErrorMetrics {
+			util.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		}
+		return "", time.Time{}, retryFunc.NewRetryableError(err)
+	} else if err != nil {
+		impl.logger.Errorw("error in commit gitea", "err", err, "config", config)
+		util.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		return "", time.Time{}, err
+	}
+	if fileResponse.Commit == nil {
+		err = fmt.Errorf("no commit found in gitea response for file %s", filePath)
+		util.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, err)
+		return "", time.Time{}, err
+	}
+	commitTime = time.Now() // default is current time, if found then will get updated accordingly
+	if fileResponse.Commit.Author != nil && !fileResponse.Commit.Author.Date.IsZero() {
+		commitTime = fileResponse.Commit.Author.Date
+	}
+	util.TriggerGitOpsMetrics("CommitValues", "GitGiteaClient", start, nil)
+	return fileResponse.Commit.Sha, commitTime, nil
+}
+
+// getDefaultBranch returns the default branch of the repo, falls back to devtron's default target revision
+func (impl GitGiteaClient) getDefaultBranch(ctx context.Context, repoName string) string {
+	repo, err := impl.getRepository(ctx, repoName)
+	if err != nil || len(repo.DefaultBranch) == 0 {
+		impl.logger.Warnw("unable to detect default branch of gitea repo, using default target revision", "repo", repoName, "err", err)
+		return util.GetDefaultTargetRevision()
+	}
+	return repo.DefaultBranch
+}
+
+func (impl GitGiteaClient) getRepository(ctx context.Context, repoName string) (*giteaRepository, error) {
+	repo := &giteaRepository{}
+	err := impl.doRequest(ctx, http.MethodGet, nil, nil, repo, "repos", impl.owner, repoName)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnHttp(config *bean2.GitOpsConfigDto) (bool, error) {
+	for count := 0; count < 3; count++ {
+		_, _, err := impl.GetRepoUrl(config)
+		if err == nil {
+			return true, nil
+		} else if !IsGiteaRepoNotFound(err) {
+			impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+			return false, err
+		}
+		impl.logger.Errorw("repo not available on http gitea", "project", config.GitRepoName)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnSsh(projectName string, repoUrl, targetRevision string) (bool, error) {
+	for count := 0; count < 3; count++ {
+		_, err := impl.gitOpsHelper.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", projectName), targetRevision)
+		if err == nil {
+			impl.logger.Infow("gitea ensureProjectAvailability clone passed", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		}
+		impl.logger.Errorw("gitea ensureProjectAvailability clone failed", "try count", count, "err", err)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) doRequest(ctx context.Context, method string, query url.Values, body interface{}, result interface{}, pathElements ...string) error {
+	requestUrl := *impl.baseUrl
+	requestUrl.Path = path.Join(append([]string{requestUrl.Path}, pathElements...)...)
+	if query != nil {
+		requestUrl.RawQuery = query.Encode()
+	}
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewBuffer(bodyBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl.String(), requestBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+impl.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		responseErr := &GiteaErrorResponse{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(responseErr)
+		if len(responseErr.Message) == 0 {
+			responseErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return responseErr
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
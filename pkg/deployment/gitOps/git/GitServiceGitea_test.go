@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/util/retryFunc"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func getTestGiteaClient(t *testing.T, host, org string) GitGiteaClient {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	client, err := NewGitGiteaClient(host, "token", "devtron-user", org, logger, nil, nil)
+	assert.Nil(t, err)
+	return client
+}
+
+func TestNewGitGiteaClient(t *testing.T) {
+	logger, _ := util.NewSugardLogger()
+	_, err := NewGitGiteaClient("gitea.internal", "token", "user", "org", logger, nil, nil)
+	assert.NotNil(t, err)
+
+	client, err := NewGitGiteaClient("https://gitea.internal/sub-path", "token", "user", "", logger, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "/sub-path/api/v1", client.baseUrl.Path)
+	assert.Equal(t, "user", client.owner)
+	assert.False(t, client.isOrg)
+}
+
+func TestGitGiteaClient_GetRepoUrl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/repos/devtron/app-one":
+			_ = json.NewEncoder(w).Encode(giteaRepository{Name: "app-one", CloneUrl: "https://gitea.internal/devtron/app-one.git", Empty: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"The target couldn't be found."}`))
+		}
+	}))
+	defer server.Close()
+	client := getTestGiteaClient(t, server.URL, "devtron")
+
+	repoUrl, isEmpty, err := client.GetRepoUrl(&gitOps.GitOpsConfigDto{GitRepoName: "app-one"})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://gitea.internal/devtron/app-one.git", repoUrl)
+	assert.True(t, isEmpty)
+
+	_, _, err = client.GetRepoUrl(&gitOps.GitOpsConfigDto{GitRepoName: "missing"})
+	assert.True(t, IsGiteaRepoNotFound(err))
+	assert.Equal(t, "The target couldn't be found.", err.(*GiteaErrorResponse).Message)
+}
+
+func TestGitGiteaClient_CommitValues(t *testing.T) {
+	var receivedMethod string
+	var receivedOptions giteaFileOption
+	fileExists := false
+	conflict := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/repos/devtron/app-one" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(giteaRepository{Name: "app-one", DefaultBranch: "main"})
+		case r.URL.Path == "/api/v1/repos/devtron/app-one/contents/charts/values.yaml" && r.Method == http.MethodGet:
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			if !fileExists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(giteaContents{Sha: "old-sha"})
+		case r.URL.Path == "/api/v1/repos/devtron/app-one/contents/charts/values.yaml":
+			receivedMethod = r.Method
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&receivedOptions))
+			if conflict {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"message":"sha does not match"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"commit":{"sha":"new-sha","author":{"date":"2024-01-02T03:04:05Z"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := getTestGiteaClient(t, server.URL, "devtron")
+	chartConfig := &ChartConfig{
+		ChartLocation:  "charts",
+		FileName:       "values.yaml",
+		FileContent:    "replicaCount: 1",
+		ReleaseMessage: "update values",
+		ChartRepoName:  "app-one",
+		UserName:       "admin",
+		UserEmailId:    "admin@devtron.ai",
+	}
+
+	t.Run("new file on default branch", func(t *testing.T) {
+		commitHash, commitTime, err := client.CommitValues(context.Background(), chartConfig, &gitOps.GitOpsConfigDto{}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, "new-sha", commitHash)
+		assert.Equal(t, 2024, commitTime.Year())
+		assert.Equal(t, http.MethodPost, receivedMethod)
+		assert.Equal(t, "main", receivedOptions.Branch)
+		assert.Empty(t, receivedOptions.Sha)
+		content, _ := base64.StdEncoding.DecodeString(receivedOptions.Content)
+		assert.Equal(t, "replicaCount: 1", string(content))
+	})
+
+	t.Run("existing file is updated with its sha", func(t *testing.T) {
+		fileExists = true
+		_, _, err := client.CommitValues(context.Background(), chartConfig, &gitOps.GitOpsConfigDto{}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, http.MethodPut, receivedMethod)
+		assert.Equal(t, "old-sha", receivedOptions.Sha)
+	})
+
+	t.Run("sha conflict is retryable", func(t *testing.T) {
+		conflict = true
+		_, _, err := client.CommitValues(context.Background(), chartConfig, &gitOps.GitOpsConfigDto{}, true)
+		_, ok := err.(*retryFunc.RetryableError)
+		assert.True(t, ok)
+	})
+}
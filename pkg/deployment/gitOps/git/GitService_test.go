@@ -30,9 +30,9 @@ func getTestGithubClient() GitHubClient {
 		&git.BasicAuth{
 			Username: "nishant",
 			Password: "",
-		}, logger)
+		}, logger, nil, false)
 
-	githubClient, err := NewGithubClient("", "", "test-org", logger, gitService)
+	githubClient, err := NewGithubClient("", "", "test-org", logger, gitService, nil)
 	if err != nil {
 		panic(err)
 	}
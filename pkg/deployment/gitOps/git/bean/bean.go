@@ -33,6 +33,7 @@ type GitConfig struct {
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrganization    string
 
 	EnableTLSVerification bool
 	CaCert                string
@@ -27,6 +27,7 @@ const (
 	GITHUB_PROVIDER       = "GITHUB"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
+	GITEA_PROVIDER        = "GITEA"
 	GITHUB_API_V3         = "api/v3"
 	GITHUB_HOST           = "github.com"
 	GIT_TLS_DIR           = "/tmp/gitops/tls"
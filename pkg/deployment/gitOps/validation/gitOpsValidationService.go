@@ -310,6 +310,11 @@ func (impl *GitOpsValidationServiceImpl) extractErrorMessageByProvider(err error
 		return fmt.Errorf("bitbucket client error: %s", err.Error())
 	case git.GITHUB_PROVIDER:
 		return fmt.Errorf("github client error: %s", err.Error())
+	case git.GITEA_PROVIDER:
+		if errorResponse, ok := err.(*git.GiteaErrorResponse); ok {
+			return fmt.Errorf("gitea client error: %s", errorResponse.Message)
+		}
+		return fmt.Errorf("gitea client error: %s", err.Error())
 	}
 	return err
 }
@@ -343,6 +348,10 @@ func (impl *GitOpsValidationServiceImpl) getValidationErrorForNonOrganisationalU
 	case git.AZURE_DEVOPS_PROVIDER:
 		errorMessageKey = "The repository must belong to Azure DevOps Project"
 		errorMessage = fmt.Sprintf("%s as configured in global configurations > GitOps", activeGitOpsConfig.AzureProjectName)
+
+	case git.GITEA_PROVIDER:
+		errorMessageKey = "The repository must belong to Gitea organization"
+		errorMessage = fmt.Sprintf("%s as configured in global configurations > GitOps", activeGitOpsConfig.GiteaOrgId)
 	}
 	apiErrorMsg := fmt.Sprintf("%s: %s", errorMessageKey, errorMessage)
 	return util.NewApiError(http.StatusBadRequest, apiErrorMsg, apiErrorMsg).
@@ -34,6 +34,7 @@ import (
 	appBean "github.com/devtron-labs/devtron/pkg/bean"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	deploymentBean "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/manifest/bean"
@@ -100,6 +101,7 @@ type ManifestCreationServiceImpl struct {
 	deploymentTemplateHistoryRepository repository3.DeploymentTemplateHistoryRepository
 	deploymentConfigService             common.DeploymentConfigService
 	envConfigOverrideReadService        read.EnvConfigOverrideService
+	canaryAnalysisService               canary.CanaryAnalysisService
 }
 
 func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
@@ -125,7 +127,8 @@ func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
 	pipelineConfigRepository chartConfig.PipelineConfigRepository,
 	deploymentTemplateHistoryRepository repository3.DeploymentTemplateHistoryRepository,
 	deploymentConfigService common.DeploymentConfigService,
-	envConfigOverrideService read.EnvConfigOverrideService) *ManifestCreationServiceImpl {
+	envConfigOverrideService read.EnvConfigOverrideService,
+	canaryAnalysisService canary.CanaryAnalysisService) *ManifestCreationServiceImpl {
 	return &ManifestCreationServiceImpl{
 		logger:                              logger,
 		dockerRegistryIpsConfigService:      dockerRegistryIpsConfigService,
@@ -151,6 +154,7 @@ func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
 		deploymentTemplateHistoryRepository: deploymentTemplateHistoryRepository,
 		deploymentConfigService:             deploymentConfigService,
 		envConfigOverrideReadService:        envConfigOverrideService,
+		canaryAnalysisService:               canaryAnalysisService,
 	}
 }
 
@@ -289,6 +293,13 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 				impl.logger.Errorw("error in autoscaling check before trigger", "pipelineId", overrideRequest.PipelineId, "err", err)
 				return valuesOverrideResponse, err
 			}
+			if isCanaryAnalysisApplicable(overrideRequest, strategy) {
+				mergedValues, err = impl.canaryAnalysisService.ApplyCanarySteps(overrideRequest.PipelineId, overrideRequest.WfrId, mergedValues)
+				if err != nil {
+					impl.logger.Errorw("error in applying canary analysis steps", "pipelineId", overrideRequest.PipelineId, "err", err)
+					return valuesOverrideResponse, err
+				}
+			}
 		}
 		// handle image pull secret if access given
 		mergedValues, err = impl.dockerRegistryIpsConfigService.HandleImagePullSecretOnApplicationDeployment(newCtx, envOverride.Environment, artifact, pipeline.CiPipelineId, mergedValues)
@@ -310,6 +321,14 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 	return valuesOverrideResponse, err
 }
 
+// isCanaryAnalysisApplicable returns true for the canary deployments of a new release, rollbacks are
+// deployed with the canary steps of the deployment template
+func isCanaryAnalysisApplicable(overrideRequest *bean.ValuesOverrideRequest, strategy *chartConfig.PipelineStrategy) bool {
+	return strategy != nil && strategy.Strategy == chartRepoRepository.DEPLOYMENT_STRATEGY_CANARY &&
+		overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_DEPLOY &&
+		!overrideRequest.IsRollbackDeployment && overrideRequest.WfrId > 0
+}
+
 func (impl *ManifestCreationServiceImpl) getDeploymentStrategyByTriggerType(overrideRequest *bean.ValuesOverrideRequest, ctx context.Context) (*chartConfig.PipelineStrategy, error) {
 	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "ManifestCreationServiceImpl.getDeploymentStrategyByTriggerType")
 	defer span.End()
@@ -65,8 +65,11 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"github.com/devtron-labs/devtron/pkg/plugin"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
 	security2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
 	read2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/read"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
@@ -170,6 +173,12 @@ type HandlerServiceImpl struct {
 	blobConfigStorageService            pipeline.BlobStorageConfigService
 	deploymentEventHandler              app.DeploymentEventHandler
 	asyncRunnable                       *async.Runnable
+	policyService                       security2.PolicyService
+	imageScanResultReadService          read2.ImageScanResultReadService
+	vulnerabilityOverrideService        security2.VulnerabilityOverrideService
+	deploymentAdmissionPolicyService    deploymentAdmission.DeploymentAdmissionPolicyService
+	deploymentWindowService             deploymentWindow.DeploymentWindowService
+	imageSigningService                 imageSigning.ImageSigningService
 }
 
 func NewHandlerServiceImpl(logger *zap.SugaredLogger,
@@ -232,7 +241,13 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 	workflowService executor.WorkflowService,
 	blobConfigStorageService pipeline.BlobStorageConfigService,
 	deploymentEventHandler app.DeploymentEventHandler,
-	asyncRunnable *async.Runnable) (*HandlerServiceImpl, error) {
+	asyncRunnable *async.Runnable,
+	policyService security2.PolicyService,
+	imageScanResultReadService read2.ImageScanResultReadService,
+	vulnerabilityOverrideService security2.VulnerabilityOverrideService,
+	deploymentAdmissionPolicyService deploymentAdmission.DeploymentAdmissionPolicyService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	imageSigningService imageSigning.ImageSigningService) (*HandlerServiceImpl, error) {
 	impl := &HandlerServiceImpl{
 		logger:                              logger,
 		cdWorkflowCommonService:             cdWorkflowCommonService,
@@ -300,6 +315,13 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 		blobConfigStorageService: blobConfigStorageService,
 		deploymentEventHandler:   deploymentEventHandler,
 		asyncRunnable:            asyncRunnable,
+
+		policyService:                    policyService,
+		imageScanResultReadService:       imageScanResultReadService,
+		vulnerabilityOverrideService:     vulnerabilityOverrideService,
+		deploymentAdmissionPolicyService: deploymentAdmissionPolicyService,
+		deploymentWindowService:          deploymentWindowService,
+		imageSigningService:              imageSigningService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -19,6 +19,7 @@ package adapter
 import (
 	apiBean "github.com/devtron-labs/devtron/api/bean"
 	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
@@ -39,10 +40,14 @@ func SetPipelineFieldsInOverrideRequest(overrideRequest *apiBean.ValuesOverrideR
 	overrideRequest.ReleaseName = pipeline.DeploymentAppName
 }
 
-func GetVulnerabilityCheckRequest(cdPipeline *pipelineConfig.Pipeline, imageDigest string) *bean.VulnerabilityCheckRequest {
-	return &bean.VulnerabilityCheckRequest{
-		CdPipeline:  cdPipeline,
-		ImageDigest: imageDigest,
+func NewTriggerRequirementRequest(cdPipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, cdWorkflowRunnerId int, triggeredBy int32) *bean.TriggerRequirementRequestDto {
+	return &bean.TriggerRequirementRequestDto{
+		TriggerRequest: bean.TriggerRequest{
+			Pipeline:           cdPipeline,
+			Artifact:           artifact,
+			CdWorkflowRunnerId: cdWorkflowRunnerId,
+			TriggeredBy:        triggeredBy,
+		},
 	}
 }
 
@@ -62,14 +67,15 @@ func NewAppIdentifierFromOverrideRequest(overrideRequest *apiBean.ValuesOverride
 	}
 }
 
-func NewValidateDeploymentTriggerObj(runner *pipelineConfig.CdWorkflowRunner, cdPipeline *pipelineConfig.Pipeline, imageDigest string,
-	deploymentConfig *bean2.DeploymentConfig, userId int32, isRollbackDeployment bool) *bean.ValidateDeploymentTriggerObj {
+func NewValidateDeploymentTriggerObj(runner *pipelineConfig.CdWorkflowRunner, cdPipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact,
+	deploymentConfig *bean2.DeploymentConfig, userId int32, triggerType pipelineConfig.TriggerType, isRollbackDeployment bool) *bean.ValidateDeploymentTriggerObj {
 	return &bean.ValidateDeploymentTriggerObj{
 		Runner:               runner,
 		CdPipeline:           cdPipeline,
-		ImageDigest:          imageDigest,
+		Artifact:             artifact,
 		DeploymentConfig:     deploymentConfig,
 		TriggeredBy:          userId,
+		TriggerType:          triggerType,
 		IsRollbackDeployment: isRollbackDeployment,
 	}
 }
@@ -18,10 +18,15 @@ package bean
 
 import (
 	"context"
+	"fmt"
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -85,9 +90,30 @@ type TriggerRequirementRequestDto struct {
 	TriggerRequest TriggerRequest
 }
 
-type VulnerabilityCheckRequest struct {
-	ImageDigest string
-	CdPipeline  *pipelineConfig.Pipeline
+// VulnerableArtifactError is returned by FeasibilityManager when the artifact has CVEs blocked by the applicable cve policy
+type VulnerableArtifactError struct {
+	ImageDigest string                    `json:"imageDigest"`
+	BlockedCves []*bean3.BlockedCveDetail `json:"blockedCves"`
+}
+
+func NewVulnerableArtifactError(imageDigest string, blockedCves []*bean3.BlockedCveDetail) *VulnerableArtifactError {
+	return &VulnerableArtifactError{
+		ImageDigest: imageDigest,
+		BlockedCves: blockedCves,
+	}
+}
+
+func (e *VulnerableArtifactError) Error() string {
+	cveNames := make([]string, 0, len(e.BlockedCves))
+	for _, cve := range e.BlockedCves {
+		cveNames = append(cveNames, cve.Name)
+	}
+	return fmt.Sprintf("found vulnerability for image digest %s, blocked cves: %s", e.ImageDigest, strings.Join(cveNames, ", "))
+}
+
+// ToApiError keeps the blocked cve list in user message so that it can be shown in the trigger response
+func (e *VulnerableArtifactError) ToApiError() *util.ApiError {
+	return util.NewApiError(http.StatusUnprocessableEntity, e.Error(), e.Error()).WithUserMessage(e)
 }
 
 const (
@@ -107,9 +133,10 @@ const (
 type ValidateDeploymentTriggerObj struct {
 	Runner               *pipelineConfig.CdWorkflowRunner
 	CdPipeline           *pipelineConfig.Pipeline
-	ImageDigest          string
+	Artifact             *repository.CiArtifact
 	DeploymentConfig     *bean2.DeploymentConfig
 	TriggeredBy          int32
+	TriggerType          pipelineConfig.TriggerType
 	IsRollbackDeployment bool
 }
 
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"errors"
+	"fmt"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"net/http"
+	"testing"
+)
+
+func TestVulnerableArtifactError(t *testing.T) {
+	vulnerableErr := NewVulnerableArtifactError("sha256:abc", []*bean3.BlockedCveDetail{
+		{Name: "CVE-2024-0001", Severity: "critical"},
+		{Name: "CVE-2024-0002", Severity: "high"},
+	})
+	wantMsg := "found vulnerability for image digest sha256:abc, blocked cves: CVE-2024-0001, CVE-2024-0002"
+	if vulnerableErr.Error() != wantMsg {
+		t.Errorf("Error() = %q, want %q", vulnerableErr.Error(), wantMsg)
+	}
+	var target *VulnerableArtifactError
+	if !errors.As(fmt.Errorf("trigger failed: %w", vulnerableErr), &target) {
+		t.Fatalf("errors.As() could not unwrap VulnerableArtifactError")
+	}
+	apiErr := target.ToApiError()
+	if apiErr.HttpStatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("ToApiError().HttpStatusCode = %d, want %d", apiErr.HttpStatusCode, http.StatusUnprocessableEntity)
+	}
+	if apiErr.UserMessage != target {
+		t.Errorf("ToApiError().UserMessage should carry the blocked cve details")
+	}
+}
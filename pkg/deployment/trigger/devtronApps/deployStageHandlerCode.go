@@ -57,7 +57,10 @@ import (
 	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
 	bean8 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	admissionBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/bean"
+	windowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
 	repository6 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	signingBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.opentelemetry.io/otel"
@@ -122,36 +125,80 @@ func (impl *HandlerServiceImpl) getCdPipelineForManualCdTrigger(ctx context.Cont
 	return cdPipeline, nil
 }
 
-func (impl *HandlerServiceImpl) validateDeploymentTriggerRequest(ctx context.Context, validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) error {
+// validateDeploymentTriggerRequest returns the warnings raised by deployment admission policies if the request is valid
+func (impl *HandlerServiceImpl) validateDeploymentTriggerRequest(ctx context.Context, validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) ([]string, error) {
 	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "HandlerServiceImpl.validateDeploymentTriggerRequest")
 	defer span.End()
 	// custom GitOps repo url validation --> Start
 	err := impl.handleCustomGitOpsRepoValidation(validateDeploymentTriggerObj.Runner, validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.DeploymentConfig, validateDeploymentTriggerObj.TriggeredBy)
 	if err != nil {
 		impl.logger.Errorw("custom GitOps repository validation error, TriggerStage", "err", err)
-		return err
+		return nil, err
 	}
 	// custom GitOps repo url validation --> Ends
-	var isVulnerable bool
-	// if request is for rollback then bypass vulnerability validation
-	if !validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
-		// checking vulnerability for deploying image
-		vulnerabilityCheckRequest := adapter.GetVulnerabilityCheckRequest(validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.ImageDigest)
-		isVulnerable, err = impl.imageScanService.GetArtifactVulnerabilityStatus(newCtx, vulnerabilityCheckRequest)
-		if err != nil {
-			impl.logger.Errorw("error in getting Artifact vulnerability status, ManualCdTrigger", "err", err)
-			return err
+	// deployment windows are enforced for every deployment type, blocked deployments need an approved exception
+	_, windowSpan := otel.Tracer("orchestrator").Start(newCtx, "HandlerServiceImpl.checkDeploymentWindow")
+	err = impl.checkDeploymentWindow(validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.Runner, validateDeploymentTriggerObj.TriggeredBy)
+	windowSpan.End()
+	var windowBlockedErr *windowBean.DeploymentWindowBlockedError
+	if errors.As(err, &windowBlockedErr) {
+		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, windowBlockedErr, validateDeploymentTriggerObj.TriggeredBy); err != nil {
+			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
 		}
+		return nil, windowBlockedErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in checking deployment windows", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		return nil, err
 	}
-
-	if isVulnerable == true {
+	// deployment admission policies are evaluated for rollback deployments as well, policies can use isRollbackDeployment param for exemption
+	_, admissionSpan := otel.Tracer("orchestrator").Start(newCtx, "HandlerServiceImpl.checkDeploymentAdmission")
+	admissionWarnings, err := impl.checkDeploymentAdmission(validateDeploymentTriggerObj)
+	admissionSpan.End()
+	var deploymentDeniedErr *admissionBean.DeploymentDeniedError
+	if errors.As(err, &deploymentDeniedErr) {
+		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, deploymentDeniedErr, validateDeploymentTriggerObj.TriggeredBy); err != nil {
+			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		}
+		return nil, deploymentDeniedErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in checking deployment admission policies", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		return nil, err
+	}
+	// image signatures are verified for rollback deployments as well, a rolled back image must still be trusted
+	_, signatureSpan := otel.Tracer("orchestrator").Start(newCtx, "HandlerServiceImpl.checkImageSignature")
+	err = impl.imageSigningService.VerifyArtifact(validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.Artifact)
+	signatureSpan.End()
+	var unverifiedImageErr *signingBean.UnverifiedImageError
+	if errors.As(err, &unverifiedImageErr) {
+		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, unverifiedImageErr, validateDeploymentTriggerObj.TriggeredBy); err != nil {
+			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		}
+		return nil, unverifiedImageErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in verifying image signature", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		return nil, err
+	}
+	// if request is for rollback then bypass vulnerability validation
+	if validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
+		return admissionWarnings, nil
+	}
+	// checking vulnerability for deploying image
+	_, feasibilitySpan := otel.Tracer("orchestrator").Start(newCtx, "HandlerServiceImpl.CheckFeasibility")
+	err = impl.CheckFeasibility(adapter.NewTriggerRequirementRequest(validateDeploymentTriggerObj.CdPipeline, validateDeploymentTriggerObj.Artifact,
+		validateDeploymentTriggerObj.Runner.Id, validateDeploymentTriggerObj.TriggeredBy))
+	feasibilitySpan.End()
+	var vulnerableArtifactErr *bean.VulnerableArtifactError
+	if errors.As(err, &vulnerableArtifactErr) {
 		// if image vulnerable, update timeline status and return
 		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, errors.New(cdWorkflow.FOUND_VULNERABILITY), validateDeploymentTriggerObj.TriggeredBy); err != nil {
 			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
 		}
-		return fmt.Errorf("found vulnerability for image digest %s", validateDeploymentTriggerObj.ImageDigest)
+		return nil, vulnerableArtifactErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in checking deployment feasibility", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		return nil, err
 	}
-	return nil
+	return admissionWarnings, nil
 }
 
 // TODO: write a wrapper to handle auto and manual trigger
@@ -302,12 +349,13 @@ func (impl *HandlerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerConte
 			impl.logger.Errorw("error in creating timeline status for deployment initiation, ManualCdTrigger", "err", err, "timeline", timeline)
 		}
 		if isNotHibernateRequest(overrideRequest.DeploymentType) {
-			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact.ImageDigest, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment)
-			validationErr := impl.validateDeploymentTriggerRequest(ctx, validateReqObj)
+			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact, envDeploymentConfig, overrideRequest.UserId, pipelineConfig.TRIGGER_TYPE_MANUAL, overrideRequest.IsRollbackDeployment)
+			admissionWarnings, validationErr := impl.validateDeploymentTriggerRequest(ctx, validateReqObj)
 			if validationErr != nil {
 				impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
 				return 0, "", nil, validationErr
 			}
+			overrideRequest.AdmissionPolicyWarnings = admissionWarnings
 		}
 		// Deploy the release
 		var releaseErr error
@@ -443,7 +491,7 @@ func (impl *HandlerServiceImpl) TriggerAutomaticDeployment(request bean.TriggerR
 		impl.logger.Errorw("error in fetching environment deployment config by appId and envId", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
 		return err
 	}
-	validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact.ImageDigest, envDeploymentConfig, triggeredBy, false))
+	_, validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact, envDeploymentConfig, triggeredBy, pipelineConfig.TRIGGER_TYPE_AUTOMATIC, false))
 	if validationErr != nil {
 		impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
 		return validationErr
@@ -17,14 +17,144 @@
 package devtronApps
 
 import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	admissionBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/bean"
+	windowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	repository6 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
 )
 
 type FeasibilityManager interface {
+	// CheckFeasibility returns *bean.VulnerableArtifactError if the artifact has CVEs blocked by the cve policy
+	// applicable on the target cluster/env/app and no active vulnerability override exists for the pipeline and artifact
 	CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error
 }
 
 func (impl *HandlerServiceImpl) CheckFeasibility(triggerRequirementRequest *bean.TriggerRequirementRequestDto) error {
-	// have not implemented right now, will be implemented in future for security vulnerability
+	triggerRequest := triggerRequirementRequest.TriggerRequest
+	cdPipeline, artifact := triggerRequest.Pipeline, triggerRequest.Artifact
+	if artifact == nil || len(artifact.ImageDigest) == 0 {
+		return nil
+	}
+	blockedCves, err := impl.getBlockedCvesForImageDigest(cdPipeline, artifact.Id, artifact.ImageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in getting blocked cves for artifact", "cdPipelineId", cdPipeline.Id, "artifactId", artifact.Id, "err", err)
+		return err
+	}
+	if len(blockedCves) == 0 {
+		return nil
+	}
+	override, err := impl.vulnerabilityOverrideService.GetActiveOverride(cdPipeline.Id, artifact.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting active vulnerability override", "cdPipelineId", cdPipeline.Id, "artifactId", artifact.Id, "err", err)
+		return err
+	}
+	if override == nil {
+		return bean.NewVulnerableArtifactError(artifact.ImageDigest, blockedCves)
+	}
+	impl.logger.Infow("deploying vulnerable artifact through override", "overrideId", override.Id, "cdPipelineId", cdPipeline.Id, "artifactId", artifact.Id, "expiresOn", override.ExpiresOn)
+	err = impl.vulnerabilityOverrideService.RecordOverrideApplied(override.Id, triggerRequest.CdWorkflowRunnerId, blockedCves, triggerRequest.TriggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error in recording applied vulnerability override", "overrideId", override.Id, "err", err)
+		return err
+	}
 	return nil
 }
+
+func (impl *HandlerServiceImpl) getBlockedCvesForImageDigest(cdPipeline *pipelineConfig.Pipeline, ciArtifactId int, imageDigest string) ([]*bean3.BlockedCveDetail, error) {
+	scanResults, err := impl.imageScanResultReadService.FindByImageDigest(imageDigest)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching scan results by image digest", "imageDigest", imageDigest, "err", err)
+		return nil, err
+	}
+	if len(scanResults) == 0 {
+		return nil, nil
+	}
+	// same cve can be reported by multiple scan executions, results are ordered by latest execution first
+	cveNameToResultMap := make(map[string]*repository6.ImageScanExecutionResult, len(scanResults))
+	cveStores := make([]*repository6.CveStore, 0, len(scanResults))
+	for _, scanResult := range scanResults {
+		if _, ok := cveNameToResultMap[scanResult.CveStoreName]; ok {
+			continue
+		}
+		cveNameToResultMap[scanResult.CveStoreName] = scanResult
+		cveStores = append(cveStores, &scanResult.CveStore)
+	}
+	clusterId := cdPipeline.Environment.ClusterId
+	if clusterId == 0 {
+		env, err := impl.envRepository.FindById(cdPipeline.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", cdPipeline.EnvironmentId, "err", err)
+			return nil, err
+		}
+		clusterId = env.ClusterId
+	}
+	cvePolicy, severityPolicy, err := impl.policyService.GetApplicablePolicy(clusterId, cdPipeline.EnvironmentId, cdPipeline.AppId, false)
+	if err != nil {
+		impl.logger.Errorw("error in getting applicable cve policy", "clusterId", clusterId, "envId", cdPipeline.EnvironmentId, "appId", cdPipeline.AppId, "err", err)
+		return nil, err
+	}
+	cveExceptions, err := impl.policyService.GetCveExceptions(clusterId, cdPipeline.EnvironmentId, cdPipeline.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cve exceptions", "clusterId", clusterId, "envId", cdPipeline.EnvironmentId, "appId", cdPipeline.AppId, "err", err)
+		return nil, err
+	}
+	exceptedCves := cveExceptions.GetExceptedCves(&securityBean.ImageIdentifier{ImageDigest: imageDigest, CiArtifactId: ciArtifactId})
+	if !impl.policyService.HasBlockedCVE(cveStores, cvePolicy, severityPolicy, exceptedCves) {
+		return nil, nil
+	}
+	blockedCveStores := repository6.EnforceCvePolicy(repository6.RemoveExceptedCves(cveStores, exceptedCves), cvePolicy, severityPolicy)
+	blockedCves := make([]*bean3.BlockedCveDetail, 0, len(blockedCveStores))
+	for _, cveStore := range blockedCveStores {
+		scanResult := cveNameToResultMap[cveStore.Name]
+		blockedCves = append(blockedCves, &bean3.BlockedCveDetail{
+			Name:         cveStore.Name,
+			Severity:     cveStore.GetSeverity().String(),
+			Package:      scanResult.Package,
+			Version:      scanResult.Version,
+			FixedVersion: scanResult.FixedVersion,
+		})
+	}
+	return blockedCves, nil
+}
+
+// checkDeploymentAdmission evaluates deployment admission policies for the trigger request,
+// returns *admissionBean.DeploymentDeniedError if the deployment is denied else the warnings raised by policies
+func (impl *HandlerServiceImpl) checkDeploymentAdmission(validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) ([]string, error) {
+	if validateDeploymentTriggerObj.Artifact == nil {
+		return nil, nil
+	}
+	evaluationResponse, err := impl.deploymentAdmissionPolicyService.EvaluatePolicies(&admissionBean.EvaluationRequest{
+		Pipeline:    validateDeploymentTriggerObj.CdPipeline,
+		Artifact:    validateDeploymentTriggerObj.Artifact,
+		TriggeredAt: validateDeploymentTriggerObj.Runner.StartedOn,
+		TriggerType: validateDeploymentTriggerObj.TriggerType,
+		IsRollback:  validateDeploymentTriggerObj.IsDeploymentTypeRollback(),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment admission policies", "cdPipelineId", validateDeploymentTriggerObj.CdPipeline.Id, "err", err)
+		return nil, err
+	}
+	if evaluationResponse.IsDenied() {
+		return nil, admissionBean.NewDeploymentDeniedError(evaluationResponse)
+	}
+	warnings := evaluationResponse.GetWarnings()
+	if len(warnings) > 0 {
+		impl.logger.Infow("deployment admission policies raised warnings", "cdPipelineId", validateDeploymentTriggerObj.CdPipeline.Id, "wfrId", validateDeploymentTriggerObj.Runner.Id, "warnings", warnings)
+	}
+	return warnings, nil
+}
+
+// checkDeploymentWindow returns *windowBean.DeploymentWindowBlockedError if deployment windows applicable on the pipeline
+// block the deployment at trigger time and no approved exception is valid for the pipeline
+func (impl *HandlerServiceImpl) checkDeploymentWindow(cdPipeline *pipelineConfig.Pipeline, runner *pipelineConfig.CdWorkflowRunner, triggeredBy int32) error {
+	return impl.deploymentWindowService.CheckDeploymentAllowed(&windowBean.CheckDeploymentRequest{
+		Pipeline:           cdPipeline,
+		TriggeredAt:        runner.StartedOn,
+		TriggeredBy:        triggeredBy,
+		CdWorkflowRunnerId: runner.Id,
+	})
+}
@@ -19,6 +19,7 @@ package devtronApps
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
@@ -47,6 +48,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	bean3 "github.com/devtron-labs/devtron/pkg/plugin/bean"
+	windowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
@@ -117,6 +119,12 @@ func (impl *HandlerServiceImpl) TriggerPreStage(request bean.TriggerRequest) (*b
 	}
 	// custom GitOps repo url validation --> Ends
 
+	err = impl.checkDeploymentWindowAndFailWfIfNeeded(ctx, pipeline, runner, triggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error, checkDeploymentWindowAndFailWfIfNeeded", "err", err, "runner", runner)
+		return nil, err
+	}
+
 	//checking vulnerability for the selected image
 	err = impl.checkVulnerabilityStatusAndFailWfIfNeeded(ctx, artifact, pipeline, runner, triggeredBy)
 	if err != nil {
@@ -300,13 +308,11 @@ func (impl *HandlerServiceImpl) getEnvAndNsIfRunStageInEnv(ctx context.Context,
 func (impl *HandlerServiceImpl) checkVulnerabilityStatusAndFailWfIfNeeded(ctx context.Context, artifact *repository.CiArtifact,
 	cdPipeline *pipelineConfig.Pipeline, runner *pipelineConfig.CdWorkflowRunner, triggeredBy int32) error {
 	//checking vulnerability for the selected image
-	vulnerabilityCheckRequest := adapter2.GetVulnerabilityCheckRequest(cdPipeline, artifact.ImageDigest)
-	isVulnerable, err := impl.imageScanService.GetArtifactVulnerabilityStatus(ctx, vulnerabilityCheckRequest)
-	if err != nil {
-		impl.logger.Errorw("error in getting Artifact vulnerability status, TriggerPreStage", "err", err)
-		return err
-	}
-	if isVulnerable {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "HandlerServiceImpl.CheckFeasibility")
+	err := impl.CheckFeasibility(adapter2.NewTriggerRequirementRequest(cdPipeline, artifact, runner.Id, triggeredBy))
+	span.End()
+	var vulnerableArtifactErr *bean.VulnerableArtifactError
+	if errors.As(err, &vulnerableArtifactErr) {
 		// if image vulnerable, update timeline status and return
 		runner.Status = cdWorkflow.WorkflowFailed
 		runner.Message = cdWorkflow.FOUND_VULNERABILITY
@@ -318,7 +324,35 @@ func (impl *HandlerServiceImpl) checkVulnerabilityStatusAndFailWfIfNeeded(ctx co
 			impl.logger.Errorw("error in updating wfr status due to vulnerable image", "err", err)
 			return err
 		}
-		return fmt.Errorf("found vulnerability for image digest %s", artifact.ImageDigest)
+		return vulnerableArtifactErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in checking artifact feasibility, TriggerPreStage", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *HandlerServiceImpl) checkDeploymentWindowAndFailWfIfNeeded(ctx context.Context, cdPipeline *pipelineConfig.Pipeline,
+	runner *pipelineConfig.CdWorkflowRunner, triggeredBy int32) error {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "HandlerServiceImpl.checkDeploymentWindow")
+	err := impl.checkDeploymentWindow(cdPipeline, runner, triggeredBy)
+	span.End()
+	var windowBlockedErr *windowBean.DeploymentWindowBlockedError
+	if errors.As(err, &windowBlockedErr) {
+		runner.Status = cdWorkflow.WorkflowFailed
+		runner.Message = windowBlockedErr.Error()
+		runner.FinishedOn = time.Now()
+		runner.UpdatedOn = time.Now()
+		runner.UpdatedBy = triggeredBy
+		err = impl.cdWorkflowRunnerService.UpdateCdWorkflowRunnerWithStage(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating wfr status due to deployment window", "err", err)
+			return err
+		}
+		return windowBlockedErr.ToApiError()
+	} else if err != nil {
+		impl.logger.Errorw("error in checking deployment windows, TriggerPreStage", "err", err)
+		return err
 	}
 	return nil
 }
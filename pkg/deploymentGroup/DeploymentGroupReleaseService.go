@@ -0,0 +1,844 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentGroup
+
+import (
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	argoApplication "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	bean2 "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultHealthTimeoutMinutes = 30
+	releaseListLimit            = 20
+)
+
+type DeploymentGroupWaveDto struct {
+	Name   string `json:"name" validate:"required"`
+	AppIds []int  `json:"appIds"`
+	// DependsOn are the names of the waves to be released before this wave
+	DependsOn        []string `json:"dependsOn"`
+	RequiresApproval bool     `json:"requiresApproval"`
+	// HealthGate waits for the apps of the wave to be healthy before the dependent waves are released
+	HealthGate           bool `json:"healthGate"`
+	HealthTimeoutMinutes int  `json:"healthTimeoutMinutes"`
+}
+
+type ReleaseAction string
+
+const (
+	ReleaseActionApprove ReleaseAction = "APPROVE"
+	ReleaseActionPause   ReleaseAction = "PAUSE"
+	ReleaseActionResume  ReleaseAction = "RESUME"
+	ReleaseActionAbort   ReleaseAction = "ABORT"
+)
+
+type DeploymentGroupReleaseActionRequest struct {
+	ReleaseId int           `json:"releaseId" validate:"required"`
+	Action    ReleaseAction `json:"action" validate:"required,oneof=APPROVE PAUSE RESUME ABORT"`
+	// WaveName is the wave to approve
+	WaveName string `json:"waveName"`
+	UserId   int32  `json:"-"`
+}
+
+// ReleaseAppRequest is the deployment of the artifact CiArtifactId on the cd pipeline of an app of the deployment group
+type ReleaseAppRequest struct {
+	AppId        int
+	CdPipelineId int
+	CiArtifactId int
+}
+
+type DeploymentGroupReleaseDto struct {
+	Id                int                              `json:"id"`
+	DeploymentGroupId int                              `json:"deploymentGroupId"`
+	CiArtifactId      int                              `json:"ciArtifactId"`
+	Status            repository.ReleaseStatus         `json:"status"`
+	Message           string                           `json:"message,omitempty"`
+	TriggeredBy       int32                            `json:"triggeredBy"`
+	TriggeredOn       time.Time                        `json:"triggeredOn"`
+	FinishedOn        *time.Time                       `json:"finishedOn,omitempty"`
+	Waves             []*DeploymentGroupReleaseWaveDto `json:"waves,omitempty"`
+}
+
+type DeploymentGroupReleaseWaveDto struct {
+	Name             string                          `json:"name"`
+	DependsOn        []string                        `json:"dependsOn"`
+	RequiresApproval bool                            `json:"requiresApproval"`
+	HealthGate       bool                            `json:"healthGate"`
+	Status           repository.ReleaseWaveStatus    `json:"status"`
+	ApprovedBy       int32                           `json:"approvedBy,omitempty"`
+	ApprovedOn       *time.Time                      `json:"approvedOn,omitempty"`
+	StartedOn        *time.Time                      `json:"startedOn,omitempty"`
+	FinishedOn       *time.Time                      `json:"finishedOn,omitempty"`
+	Message          string                          `json:"message,omitempty"`
+	Apps             []*DeploymentGroupReleaseAppDto `json:"apps"`
+}
+
+type DeploymentGroupReleaseAppDto struct {
+	AppId        int                         `json:"appId"`
+	AppName      string                      `json:"appName"`
+	CdPipelineId int                         `json:"cdPipelineId"`
+	CiArtifactId int                         `json:"ciArtifactId"`
+	CdWorkflowId int                         `json:"cdWorkflowId,omitempty"`
+	Status       repository.ReleaseAppStatus `json:"status"`
+	Message      string                      `json:"message,omitempty"`
+}
+
+type DeploymentGroupReleaseService interface {
+	GetWaves(deploymentGroupId int) ([]*DeploymentGroupWaveDto, error)
+	ValidateWaves(appIds []int, waves []*DeploymentGroupWaveDto) error
+	// SaveWaves replaces the waves of the deployment group, the group is released all at once if it has no waves
+	SaveWaves(deploymentGroupId int, appIds []int, waves []*DeploymentGroupWaveDto, userId int32) error
+	// StartRelease creates the release of the artifact ciArtifactId of the deployment group and starts
+	// releasing the waves without dependencies
+	StartRelease(deploymentGroupId, ciArtifactId int, apps []*ReleaseAppRequest, userId int32) (*DeploymentGroupReleaseDto, error)
+	GetRelease(releaseId int) (*DeploymentGroupReleaseDto, error)
+	GetReleases(deploymentGroupId int) ([]*DeploymentGroupReleaseDto, error)
+	HandleReleaseAction(request *DeploymentGroupReleaseActionRequest) (*DeploymentGroupReleaseDto, error)
+	// ProcessReleases tracks the deployments of the running waves and starts the waves whose dependencies are released
+	ProcessReleases()
+}
+
+type DeploymentGroupReleaseServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	deploymentGroupRepository        repository.DeploymentGroupRepository
+	deploymentGroupWaveRepository    repository.DeploymentGroupWaveRepository
+	deploymentGroupReleaseRepository repository.DeploymentGroupReleaseRepository
+	appRepository                    app.AppRepository
+	cdWorkflowRepository             pipelineConfig.CdWorkflowRepository
+	appStatusRepository              appStatus.AppStatusRepository
+	workflowEventPublishService      out.WorkflowEventPublishService
+}
+
+func NewDeploymentGroupReleaseServiceImpl(logger *zap.SugaredLogger,
+	deploymentGroupRepository repository.DeploymentGroupRepository,
+	deploymentGroupWaveRepository repository.DeploymentGroupWaveRepository,
+	deploymentGroupReleaseRepository repository.DeploymentGroupReleaseRepository,
+	appRepository app.AppRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	appStatusRepository appStatus.AppStatusRepository,
+	workflowEventPublishService out.WorkflowEventPublishService) *DeploymentGroupReleaseServiceImpl {
+	return &DeploymentGroupReleaseServiceImpl{
+		logger:                           logger,
+		deploymentGroupRepository:        deploymentGroupRepository,
+		deploymentGroupWaveRepository:    deploymentGroupWaveRepository,
+		deploymentGroupReleaseRepository: deploymentGroupReleaseRepository,
+		appRepository:                    appRepository,
+		cdWorkflowRepository:             cdWorkflowRepository,
+		appStatusRepository:              appStatusRepository,
+		workflowEventPublishService:      workflowEventPublishService,
+	}
+}
+
+var succeededDeployStatuses = []string{argoApplication.Healthy, argoApplication.SUCCEEDED}
+
+var failedRunnerStatuses = []string{cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowAborted, cdWorkflow.WorkflowTimedOut,
+	cdWorkflow.WorkflowCancel, string(health.HealthStatusDegraded)}
+
+var failedWorkflowStatuses = []cdWorkflow.WorkflowStatus{cdWorkflow.QUE_ERROR, cdWorkflow.DROPPED_STALE, cdWorkflow.DEQUE_ERROR, cdWorkflow.TRIGGER_ERROR}
+
+func (impl *DeploymentGroupReleaseServiceImpl) GetWaves(deploymentGroupId int) ([]*DeploymentGroupWaveDto, error) {
+	models, err := impl.deploymentGroupWaveRepository.FindActiveByDeploymentGroupId(deploymentGroupId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment group waves", "deploymentGroupId", deploymentGroupId, "err", err)
+		return nil, err
+	}
+	waves := make([]*DeploymentGroupWaveDto, 0, len(models))
+	for _, model := range models {
+		waves = append(waves, &DeploymentGroupWaveDto{
+			Name:                 model.Name,
+			AppIds:               model.AppIds,
+			DependsOn:            model.DependsOn,
+			RequiresApproval:     model.RequiresApproval,
+			HealthGate:           model.HealthGate,
+			HealthTimeoutMinutes: model.HealthTimeoutMinutes,
+		})
+	}
+	return waves, nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) ValidateWaves(appIds []int, waves []*DeploymentGroupWaveDto) error {
+	errMsg := validateWaves(appIds, waves)
+	if len(errMsg) > 0 {
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) SaveWaves(deploymentGroupId int, appIds []int, waves []*DeploymentGroupWaveDto, userId int32) error {
+	err := impl.ValidateWaves(appIds, waves)
+	if err != nil {
+		return err
+	}
+	models := make([]*repository.DeploymentGroupWave, 0, len(waves))
+	for i, wave := range waves {
+		healthTimeoutMinutes := wave.HealthTimeoutMinutes
+		if healthTimeoutMinutes <= 0 {
+			healthTimeoutMinutes = DefaultHealthTimeoutMinutes
+		}
+		models = append(models, &repository.DeploymentGroupWave{
+			DeploymentGroupId:    deploymentGroupId,
+			Name:                 wave.Name,
+			WaveOrder:            i,
+			AppIds:               wave.AppIds,
+			DependsOn:            wave.DependsOn,
+			RequiresApproval:     wave.RequiresApproval,
+			HealthGate:           wave.HealthGate,
+			HealthTimeoutMinutes: healthTimeoutMinutes,
+			Active:               true,
+			AuditLog:             sql.NewDefaultAuditLog(userId),
+		})
+	}
+	err = impl.deploymentGroupWaveRepository.ReplaceWaves(deploymentGroupId, models, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment group waves", "deploymentGroupId", deploymentGroupId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) StartRelease(deploymentGroupId, ciArtifactId int, apps []*ReleaseAppRequest, userId int32) (*DeploymentGroupReleaseDto, error) {
+	waves, err := impl.deploymentGroupWaveRepository.FindActiveByDeploymentGroupId(deploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group waves", "deploymentGroupId", deploymentGroupId, "err", err)
+		return nil, err
+	}
+	latestReleases, err := impl.deploymentGroupReleaseRepository.FindReleasesByDeploymentGroupId(deploymentGroupId, 1)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment group releases", "deploymentGroupId", deploymentGroupId, "err", err)
+		return nil, err
+	}
+	if len(latestReleases) > 0 && isReleaseActive(latestReleases[0].Status) {
+		errMsg := fmt.Sprintf("release %d of the deployment group is in progress, abort it to start a new release", latestReleases[0].Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	release := &repository.DeploymentGroupRelease{
+		DeploymentGroupId: deploymentGroupId,
+		CiArtifactId:      ciArtifactId,
+		Status:            repository.ReleaseStatusRunning,
+		AuditLog:          sql.NewDefaultAuditLog(userId),
+	}
+	appsById := make(map[int]*ReleaseAppRequest, len(apps))
+	for _, appRequest := range apps {
+		appsById[appRequest.AppId] = appRequest
+	}
+	for _, wave := range waves {
+		releaseWave := &repository.DeploymentGroupReleaseWave{
+			Name:                 wave.Name,
+			WaveOrder:            wave.WaveOrder,
+			DependsOn:            wave.DependsOn,
+			RequiresApproval:     wave.RequiresApproval,
+			HealthGate:           wave.HealthGate,
+			HealthTimeoutMinutes: wave.HealthTimeoutMinutes,
+			Status:               repository.ReleaseWaveStatusPending,
+			AuditLog:             sql.NewDefaultAuditLog(userId),
+		}
+		for _, appId := range wave.AppIds {
+			appRequest, ok := appsById[appId]
+			if !ok {
+				// app without a cd pipeline on the environment or an artifact of the release
+				impl.logger.Warnw("skipping app of wave without deployment", "deploymentGroupId", deploymentGroupId, "wave", wave.Name, "appId", appId)
+				continue
+			}
+			releaseWave.Apps = append(releaseWave.Apps, &repository.DeploymentGroupReleaseApp{
+				AppId:        appId,
+				CdPipelineId: appRequest.CdPipelineId,
+				CiArtifactId: appRequest.CiArtifactId,
+				Status:       repository.ReleaseAppStatusPending,
+				AuditLog:     sql.NewDefaultAuditLog(userId),
+			})
+			delete(appsById, appId)
+		}
+		release.Waves = append(release.Waves, releaseWave)
+	}
+	if len(appsById) > 0 {
+		unassignedAppIds := make([]string, 0, len(appsById))
+		for appId := range appsById {
+			unassignedAppIds = append(unassignedAppIds, fmt.Sprint(appId))
+		}
+		slices.Sort(unassignedAppIds)
+		errMsg := fmt.Sprintf("apps %s of the deployment group are not assigned to any wave", strings.Join(unassignedAppIds, ", "))
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	err = impl.deploymentGroupReleaseRepository.SaveRelease(release)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment group release", "deploymentGroupId", deploymentGroupId, "err", err)
+		return nil, err
+	}
+	impl.processRelease(release.Id)
+	return impl.GetRelease(release.Id)
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) GetRelease(releaseId int) (*DeploymentGroupReleaseDto, error) {
+	release, err := impl.deploymentGroupReleaseRepository.FindReleaseById(releaseId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group release", "releaseId", releaseId, "err", err)
+		return nil, err
+	}
+	appIds := make([]int, 0)
+	for _, wave := range release.Waves {
+		for _, releaseApp := range wave.Apps {
+			appIds = append(appIds, releaseApp.AppId)
+		}
+	}
+	appNames := make(map[int]string, len(appIds))
+	if len(appIds) > 0 {
+		apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching apps of deployment group release", "releaseId", releaseId, "err", err)
+			return nil, err
+		}
+		for _, appModel := range apps {
+			appNames[appModel.Id] = appModel.AppName
+		}
+	}
+	releaseDto := getReleaseDto(release)
+	for _, wave := range release.Waves {
+		waveDto := &DeploymentGroupReleaseWaveDto{
+			Name:             wave.Name,
+			DependsOn:        wave.DependsOn,
+			RequiresApproval: wave.RequiresApproval,
+			HealthGate:       wave.HealthGate,
+			Status:           wave.Status,
+			ApprovedBy:       wave.ApprovedBy,
+			ApprovedOn:       getTimePtr(wave.ApprovedOn),
+			StartedOn:        getTimePtr(wave.StartedOn),
+			FinishedOn:       getTimePtr(wave.FinishedOn),
+			Message:          wave.Message,
+			Apps:             make([]*DeploymentGroupReleaseAppDto, 0, len(wave.Apps)),
+		}
+		for _, releaseApp := range wave.Apps {
+			waveDto.Apps = append(waveDto.Apps, &DeploymentGroupReleaseAppDto{
+				AppId:        releaseApp.AppId,
+				AppName:      appNames[releaseApp.AppId],
+				CdPipelineId: releaseApp.CdPipelineId,
+				CiArtifactId: releaseApp.CiArtifactId,
+				CdWorkflowId: releaseApp.CdWorkflowId,
+				Status:       releaseApp.Status,
+				Message:      releaseApp.Message,
+			})
+		}
+		releaseDto.Waves = append(releaseDto.Waves, waveDto)
+	}
+	return releaseDto, nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) GetReleases(deploymentGroupId int) ([]*DeploymentGroupReleaseDto, error) {
+	releases, err := impl.deploymentGroupReleaseRepository.FindReleasesByDeploymentGroupId(deploymentGroupId, releaseListLimit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment group releases", "deploymentGroupId", deploymentGroupId, "err", err)
+		return nil, err
+	}
+	releaseDtos := make([]*DeploymentGroupReleaseDto, 0, len(releases))
+	for _, release := range releases {
+		releaseDtos = append(releaseDtos, getReleaseDto(release))
+	}
+	return releaseDtos, nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) HandleReleaseAction(request *DeploymentGroupReleaseActionRequest) (*DeploymentGroupReleaseDto, error) {
+	release, err := impl.deploymentGroupReleaseRepository.FindReleaseById(request.ReleaseId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group release", "releaseId", request.ReleaseId, "err", err)
+		return nil, err
+	}
+	switch request.Action {
+	case ReleaseActionApprove:
+		err = impl.approveWave(release, request.WaveName, request.UserId)
+	case ReleaseActionPause:
+		err = impl.updateReleaseStatus(release, repository.ReleaseStatusRunning, repository.ReleaseStatusPaused, request.UserId)
+	case ReleaseActionResume:
+		err = impl.updateReleaseStatus(release, repository.ReleaseStatusPaused, repository.ReleaseStatusRunning, request.UserId)
+	case ReleaseActionAbort:
+		err = impl.abortRelease(release, request.UserId)
+	default:
+		errMsg := fmt.Sprintf("invalid release action %s", request.Action)
+		err = util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	impl.processRelease(release.Id)
+	return impl.GetRelease(release.Id)
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) approveWave(release *repository.DeploymentGroupRelease, waveName string, userId int32) error {
+	if !isReleaseActive(release.Status) {
+		errMsg := fmt.Sprintf("release is %s", strings.ToLower(string(release.Status)))
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	for _, wave := range release.Waves {
+		if wave.Name != waveName {
+			continue
+		}
+		if !wave.RequiresApproval || wave.ApprovedBy > 0 ||
+			(wave.Status != repository.ReleaseWaveStatusPending && wave.Status != repository.ReleaseWaveStatusAwaitingApproval) {
+			errMsg := fmt.Sprintf("wave %s is not awaiting approval", waveName)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		wave.ApprovedBy = userId
+		wave.ApprovedOn = time.Now()
+		return impl.updateWave(wave)
+	}
+	errMsg := fmt.Sprintf("wave %s not found in the release", waveName)
+	return util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) updateReleaseStatus(release *repository.DeploymentGroupRelease,
+	expected, updated repository.ReleaseStatus, userId int32) error {
+	updatedOk, err := impl.deploymentGroupReleaseRepository.UpdateReleaseStatus(release.Id, expected, updated, userId)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment group release status", "releaseId", release.Id, "status", updated, "err", err)
+		return err
+	}
+	if !updatedOk {
+		errMsg := fmt.Sprintf("release is %s", strings.ToLower(string(release.Status)))
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	release.Status = updated
+	return nil
+}
+
+// abortRelease skips the waves not started yet, deployments already triggered are not stopped
+func (impl *DeploymentGroupReleaseServiceImpl) abortRelease(release *repository.DeploymentGroupRelease, userId int32) error {
+	if !isReleaseActive(release.Status) {
+		errMsg := fmt.Sprintf("release is %s", strings.ToLower(string(release.Status)))
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	err := impl.updateReleaseStatus(release, release.Status, repository.ReleaseStatusAborted, userId)
+	if err != nil {
+		return err
+	}
+	impl.skipPendingWaves(release, fmt.Sprintf("release aborted by user %d", userId))
+	release.Message = "release aborted, deployments already triggered are not stopped"
+	release.FinishedOn = time.Now()
+	return impl.updateRelease(release, userId)
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) ProcessReleases() {
+	releases, err := impl.deploymentGroupReleaseRepository.FindReleasesByStatus([]repository.ReleaseStatus{repository.ReleaseStatusRunning, repository.ReleaseStatusPaused})
+	if err != nil {
+		impl.logger.Errorw("error in fetching active deployment group releases", "err", err)
+		return
+	}
+	for _, release := range releases {
+		impl.processRelease(release.Id)
+	}
+}
+
+// processRelease refreshes the status of the waves being deployed, starts the waves whose dependencies are
+// released (unless the release is paused) and closes the release once all the waves are done or a wave fails
+func (impl *DeploymentGroupReleaseServiceImpl) processRelease(releaseId int) {
+	release, err := impl.deploymentGroupReleaseRepository.FindReleaseById(releaseId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group release", "releaseId", releaseId, "err", err)
+		return
+	}
+	if !isReleaseActive(release.Status) {
+		return
+	}
+	group, err := impl.deploymentGroupRepository.GetById(release.DeploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group", "deploymentGroupId", release.DeploymentGroupId, "err", err)
+		return
+	}
+	for _, wave := range release.Waves {
+		if wave.Status == repository.ReleaseWaveStatusDeploying || wave.Status == repository.ReleaseWaveStatusVerifying {
+			err = impl.refreshWave(wave, group.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in refreshing deployment group release wave", "releaseId", releaseId, "wave", wave.Name, "err", err)
+			}
+		}
+	}
+	if failedWave := getWaveByStatus(release.Waves, repository.ReleaseWaveStatusFailed); failedWave != nil {
+		impl.closeRelease(release, repository.ReleaseStatusFailed, fmt.Sprintf("wave %s failed", failedWave.Name))
+		return
+	}
+	if release.Status == repository.ReleaseStatusRunning {
+		for _, wave := range getReadyWaves(release.Waves) {
+			err = impl.startWave(release, wave)
+			if err != nil {
+				impl.logger.Errorw("error in starting deployment group release wave", "releaseId", releaseId, "wave", wave.Name, "err", err)
+			}
+		}
+	}
+	if failedWave := getWaveByStatus(release.Waves, repository.ReleaseWaveStatusFailed); failedWave != nil {
+		impl.closeRelease(release, repository.ReleaseStatusFailed, fmt.Sprintf("wave %s failed", failedWave.Name))
+		return
+	}
+	for _, wave := range release.Waves {
+		if wave.Status != repository.ReleaseWaveStatusSucceeded {
+			return
+		}
+	}
+	impl.closeRelease(release, repository.ReleaseStatusSucceeded, "")
+}
+
+// startWave triggers the deployments of the apps of the wave, waves requiring approval are marked awaiting approval till approved
+func (impl *DeploymentGroupReleaseServiceImpl) startWave(release *repository.DeploymentGroupRelease, wave *repository.DeploymentGroupReleaseWave) error {
+	if wave.RequiresApproval && wave.ApprovedBy == 0 {
+		if wave.Status == repository.ReleaseWaveStatusAwaitingApproval {
+			return nil
+		}
+		_, err := impl.deploymentGroupReleaseRepository.UpdateWaveStatus(wave.Id, wave.Status, repository.ReleaseWaveStatusAwaitingApproval)
+		return err
+	}
+	claimed, err := impl.deploymentGroupReleaseRepository.UpdateWaveStatus(wave.Id, wave.Status, repository.ReleaseWaveStatusDeploying)
+	if err != nil || !claimed {
+		return err
+	}
+	wave.Status = repository.ReleaseWaveStatusDeploying
+	wave.StartedOn = time.Now()
+	// deployments are triggered on behalf of the approver of the wave, else the user who triggered the release
+	triggeredBy := release.CreatedBy
+	if wave.ApprovedBy > 0 {
+		triggeredBy = wave.ApprovedBy
+	}
+	requests := make([]*bean2.BulkTriggerRequest, 0, len(wave.Apps))
+	for _, releaseApp := range wave.Apps {
+		requests = append(requests, &bean2.BulkTriggerRequest{
+			CiArtifactId: releaseApp.CiArtifactId,
+			PipelineId:   releaseApp.CdPipelineId,
+		})
+	}
+	if len(requests) == 0 {
+		wave.Status = repository.ReleaseWaveStatusSucceeded
+		wave.FinishedOn = time.Now()
+		wave.Message = "no app of the wave has a deployment in the release"
+		return impl.updateWave(wave)
+	}
+	cdWorkflows, err := impl.workflowEventPublishService.TriggerBulkDeploymentAsync(requests, triggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error in triggering deployments of wave", "releaseId", release.Id, "wave", wave.Name, "err", err)
+		wave.Status = repository.ReleaseWaveStatusFailed
+		wave.FinishedOn = time.Now()
+		wave.Message = fmt.Sprintf("error in triggering deployments: %s", err.Error())
+		return impl.updateWave(wave)
+	}
+	for i, releaseApp := range wave.Apps {
+		if i < len(cdWorkflows) {
+			releaseApp.CdWorkflowId = cdWorkflows[i].Id
+		}
+		releaseApp.Status = repository.ReleaseAppStatusDeploying
+		err = impl.updateApp(releaseApp)
+		if err != nil {
+			return err
+		}
+	}
+	return impl.updateWave(wave)
+}
+
+// refreshWave updates the status of the apps of the wave from their deployments, a wave with health gate
+// succeeds once all of its apps are reported healthy after it started, and fails if they are not healthy
+// within its health timeout
+func (impl *DeploymentGroupReleaseServiceImpl) refreshWave(wave *repository.DeploymentGroupReleaseWave, environmentId int) error {
+	failedApps := make([]string, 0)
+	pendingApps := 0
+	for _, releaseApp := range wave.Apps {
+		if releaseApp.Status == repository.ReleaseAppStatusDeploying {
+			cdWf, err := impl.cdWorkflowRepository.FindById(releaseApp.CdWorkflowId)
+			if err != nil && !util.IsErrNoRows(err) {
+				return err
+			}
+			appStatus, message := getAppDeploymentStatus(cdWf)
+			if appStatus != releaseApp.Status {
+				releaseApp.Status = appStatus
+				releaseApp.Message = message
+				err = impl.updateApp(releaseApp)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if releaseApp.Status == repository.ReleaseAppStatusDeployed && wave.HealthGate {
+			appStatusContainer, err := impl.appStatusRepository.Get(releaseApp.AppId, environmentId)
+			if err != nil && !util.IsErrNoRows(err) {
+				return err
+			}
+			// the status is only taken once updated after the wave started, an earlier one is of the previous release
+			if appStatusContainer.Status == string(health.HealthStatusHealthy) && appStatusContainer.UpdatedOn.After(wave.StartedOn) {
+				releaseApp.Status = repository.ReleaseAppStatusHealthy
+				err = impl.updateApp(releaseApp)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		switch releaseApp.Status {
+		case repository.ReleaseAppStatusFailed:
+			failedApps = append(failedApps, fmt.Sprint(releaseApp.AppId))
+		case repository.ReleaseAppStatusHealthy:
+		case repository.ReleaseAppStatusDeployed:
+			if wave.HealthGate {
+				pendingApps++
+			}
+		default:
+			pendingApps++
+		}
+	}
+	switch {
+	case len(failedApps) > 0:
+		wave.Status = repository.ReleaseWaveStatusFailed
+		wave.Message = fmt.Sprintf("deployment failed for apps %s", strings.Join(failedApps, ", "))
+	case pendingApps == 0:
+		wave.Status = repository.ReleaseWaveStatusSucceeded
+	case wave.HealthGate && time.Since(wave.StartedOn) > time.Duration(wave.HealthTimeoutMinutes)*time.Minute:
+		wave.Status = repository.ReleaseWaveStatusFailed
+		wave.Message = fmt.Sprintf("apps not healthy within %d minutes", wave.HealthTimeoutMinutes)
+	case wave.HealthGate && wave.Status == repository.ReleaseWaveStatusDeploying && isWaveDeployed(wave):
+		wave.Status = repository.ReleaseWaveStatusVerifying
+		return impl.updateWave(wave)
+	default:
+		return nil
+	}
+	wave.FinishedOn = time.Now()
+	return impl.updateWave(wave)
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) closeRelease(release *repository.DeploymentGroupRelease, status repository.ReleaseStatus, message string) {
+	updated, err := impl.deploymentGroupReleaseRepository.UpdateReleaseStatus(release.Id, release.Status, status, userBean.SYSTEM_USER_ID)
+	if err != nil || !updated {
+		if err != nil {
+			impl.logger.Errorw("error in closing deployment group release", "releaseId", release.Id, "status", status, "err", err)
+		}
+		return
+	}
+	release.Status = status
+	if status == repository.ReleaseStatusFailed {
+		impl.skipPendingWaves(release, message)
+	}
+	release.Message = message
+	release.FinishedOn = time.Now()
+	err = impl.updateRelease(release, userBean.SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in closing deployment group release", "releaseId", release.Id, "status", status, "err", err)
+	}
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) skipPendingWaves(release *repository.DeploymentGroupRelease, message string) {
+	for _, wave := range release.Waves {
+		if wave.Status != repository.ReleaseWaveStatusPending && wave.Status != repository.ReleaseWaveStatusAwaitingApproval {
+			continue
+		}
+		wave.Status = repository.ReleaseWaveStatusSkipped
+		wave.Message = message
+		err := impl.updateWave(wave)
+		if err != nil {
+			impl.logger.Errorw("error in skipping deployment group release wave", "releaseId", release.Id, "wave", wave.Name, "err", err)
+		}
+	}
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) updateRelease(release *repository.DeploymentGroupRelease, userId int32) error {
+	release.UpdatedOn = time.Now()
+	release.UpdatedBy = userId
+	err := impl.deploymentGroupReleaseRepository.UpdateRelease(release)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment group release", "releaseId", release.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) updateWave(wave *repository.DeploymentGroupReleaseWave) error {
+	wave.UpdatedOn = time.Now()
+	err := impl.deploymentGroupReleaseRepository.UpdateWave(wave)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment group release wave", "waveId", wave.Id, "status", wave.Status, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentGroupReleaseServiceImpl) updateApp(releaseApp *repository.DeploymentGroupReleaseApp) error {
+	releaseApp.UpdatedOn = time.Now()
+	err := impl.deploymentGroupReleaseRepository.UpdateApp(releaseApp)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment group release app", "releaseAppId", releaseApp.Id, "status", releaseApp.Status, "err", err)
+		return err
+	}
+	return nil
+}
+
+// validateWaves returns the error message if the waves do not form a valid release plan of the apps of the
+// deployment group, i.e. unique wave names, every app in exactly one wave and dependencies without cycles
+func validateWaves(appIds []int, waves []*DeploymentGroupWaveDto) string {
+	if len(waves) == 0 {
+		return ""
+	}
+	waveByName := make(map[string]*DeploymentGroupWaveDto, len(waves))
+	for _, wave := range waves {
+		if wave == nil || len(strings.TrimSpace(wave.Name)) == 0 {
+			return "wave name is required"
+		}
+		if _, ok := waveByName[wave.Name]; ok {
+			return fmt.Sprintf("wave name %s is used more than once", wave.Name)
+		}
+		waveByName[wave.Name] = wave
+	}
+	waveOfApp := make(map[int]string, len(appIds))
+	for _, wave := range waves {
+		for _, appId := range wave.AppIds {
+			if !slices.Contains(appIds, appId) {
+				return fmt.Sprintf("app %d of wave %s is not in the deployment group", appId, wave.Name)
+			}
+			if otherWave, ok := waveOfApp[appId]; ok {
+				return fmt.Sprintf("app %d is in both waves %s and %s", appId, otherWave, wave.Name)
+			}
+			waveOfApp[appId] = wave.Name
+		}
+		for _, dependency := range wave.DependsOn {
+			if _, ok := waveByName[dependency]; !ok {
+				return fmt.Sprintf("wave %s depends on unknown wave %s", wave.Name, dependency)
+			}
+		}
+	}
+	for _, appId := range appIds {
+		if _, ok := waveOfApp[appId]; !ok {
+			return fmt.Sprintf("app %d of the deployment group is not in any wave", appId)
+		}
+	}
+	// waves are visited in dependency order, the waves left unvisited are in a cycle
+	released := make(map[string]bool, len(waves))
+	for len(released) < len(waves) {
+		progressed := false
+		for _, wave := range waves {
+			if released[wave.Name] {
+				continue
+			}
+			ready := true
+			for _, dependency := range wave.DependsOn {
+				ready = ready && released[dependency]
+			}
+			if ready {
+				released[wave.Name] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return "wave dependencies have a cycle"
+		}
+	}
+	return ""
+}
+
+// getReadyWaves returns the waves not yet started whose dependencies are released
+func getReadyWaves(waves []*repository.DeploymentGroupReleaseWave) []*repository.DeploymentGroupReleaseWave {
+	statusByName := make(map[string]repository.ReleaseWaveStatus, len(waves))
+	for _, wave := range waves {
+		statusByName[wave.Name] = wave.Status
+	}
+	readyWaves := make([]*repository.DeploymentGroupReleaseWave, 0)
+	for _, wave := range waves {
+		if wave.Status != repository.ReleaseWaveStatusPending && wave.Status != repository.ReleaseWaveStatusAwaitingApproval {
+			continue
+		}
+		ready := true
+		for _, dependency := range wave.DependsOn {
+			ready = ready && statusByName[dependency] == repository.ReleaseWaveStatusSucceeded
+		}
+		if ready {
+			readyWaves = append(readyWaves, wave)
+		}
+	}
+	return readyWaves
+}
+
+// getAppDeploymentStatus returns the status of the app in the release from its cd workflow, i.e. its pre-deployment
+// stage and deployment
+func getAppDeploymentStatus(cdWf *pipelineConfig.CdWorkflow) (repository.ReleaseAppStatus, string) {
+	if cdWf == nil || cdWf.Id == 0 {
+		return repository.ReleaseAppStatusFailed, "deployment not found"
+	}
+	if slices.Contains(failedWorkflowStatuses, cdWf.WorkflowStatus) {
+		return repository.ReleaseAppStatusFailed, fmt.Sprintf("deployment could not be triggered (%s)", cdWf.WorkflowStatus.String())
+	}
+	for _, runner := range cdWf.CdWorkflowRunner {
+		switch runner.WorkflowType {
+		case apiBean.CD_WORKFLOW_TYPE_PRE:
+			if slices.Contains(failedRunnerStatuses, runner.Status) {
+				return repository.ReleaseAppStatusFailed, fmt.Sprintf("pre-deployment stage %s", strings.ToLower(runner.Status))
+			}
+		case apiBean.CD_WORKFLOW_TYPE_DEPLOY:
+			if slices.Contains(succeededDeployStatuses, runner.Status) {
+				return repository.ReleaseAppStatusDeployed, ""
+			}
+			if slices.Contains(failedRunnerStatuses, runner.Status) {
+				return repository.ReleaseAppStatusFailed, fmt.Sprintf("deployment %s", strings.ToLower(runner.Status))
+			}
+		}
+	}
+	return repository.ReleaseAppStatusDeploying, ""
+}
+
+func getWaveByStatus(waves []*repository.DeploymentGroupReleaseWave, status repository.ReleaseWaveStatus) *repository.DeploymentGroupReleaseWave {
+	for _, wave := range waves {
+		if wave.Status == status {
+			return wave
+		}
+	}
+	return nil
+}
+
+func isWaveDeployed(wave *repository.DeploymentGroupReleaseWave) bool {
+	for _, releaseApp := range wave.Apps {
+		if releaseApp.Status != repository.ReleaseAppStatusDeployed && releaseApp.Status != repository.ReleaseAppStatusHealthy {
+			return false
+		}
+	}
+	return true
+}
+
+func isReleaseActive(status repository.ReleaseStatus) bool {
+	return status == repository.ReleaseStatusRunning || status == repository.ReleaseStatusPaused
+}
+
+func getReleaseDto(release *repository.DeploymentGroupRelease) *DeploymentGroupReleaseDto {
+	return &DeploymentGroupReleaseDto{
+		Id:                release.Id,
+		DeploymentGroupId: release.DeploymentGroupId,
+		CiArtifactId:      release.CiArtifactId,
+		Status:            release.Status,
+		Message:           release.Message,
+		TriggeredBy:       release.CreatedBy,
+		TriggeredOn:       release.CreatedOn,
+		FinishedOn:        getTimePtr(release.FinishedOn),
+	}
+}
+
+func getTimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentGroup
+
+import (
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateWaves(t *testing.T) {
+	appIds := []int{1, 2, 3}
+	tests := []struct {
+		name   string
+		waves  []*DeploymentGroupWaveDto
+		errMsg string
+	}{
+		{
+			name:  "no waves",
+			waves: nil,
+		},
+		{
+			name: "ordered waves",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1}},
+				{Name: "backend", AppIds: []int{2}, DependsOn: []string{"db"}},
+				{Name: "frontend", AppIds: []int{3}, DependsOn: []string{"backend", "db"}},
+			},
+		},
+		{
+			name: "duplicate wave name",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1, 2}},
+				{Name: "db", AppIds: []int{3}},
+			},
+			errMsg: "wave name db is used more than once",
+		},
+		{
+			name: "app outside deployment group",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1, 2, 3, 4}},
+			},
+			errMsg: "app 4 of wave db is not in the deployment group",
+		},
+		{
+			name: "app in two waves",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1, 2}},
+				{Name: "backend", AppIds: []int{2, 3}},
+			},
+			errMsg: "app 2 is in both waves db and backend",
+		},
+		{
+			name: "app not in any wave",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1, 2}},
+			},
+			errMsg: "app 3 of the deployment group is not in any wave",
+		},
+		{
+			name: "unknown dependency",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1, 2, 3}, DependsOn: []string{"infra"}},
+			},
+			errMsg: "wave db depends on unknown wave infra",
+		},
+		{
+			name: "dependency cycle",
+			waves: []*DeploymentGroupWaveDto{
+				{Name: "db", AppIds: []int{1}},
+				{Name: "backend", AppIds: []int{2}, DependsOn: []string{"db", "frontend"}},
+				{Name: "frontend", AppIds: []int{3}, DependsOn: []string{"backend"}},
+			},
+			errMsg: "wave dependencies have a cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.errMsg, validateWaves(appIds, tt.waves))
+		})
+	}
+}
+
+func TestGetReadyWaves(t *testing.T) {
+	waves := []*repository.DeploymentGroupReleaseWave{
+		{Name: "db", Status: repository.ReleaseWaveStatusSucceeded},
+		{Name: "backend", Status: repository.ReleaseWaveStatusPending, DependsOn: []string{"db"}},
+		{Name: "worker", Status: repository.ReleaseWaveStatusAwaitingApproval, DependsOn: []string{"db"}},
+		{Name: "frontend", Status: repository.ReleaseWaveStatusPending, DependsOn: []string{"backend"}},
+	}
+	readyWaves := getReadyWaves(waves)
+	readyWaveNames := make([]string, 0, len(readyWaves))
+	for _, wave := range readyWaves {
+		readyWaveNames = append(readyWaveNames, wave.Name)
+	}
+	assert.Equal(t, []string{"backend", "worker"}, readyWaveNames)
+}
+
+func TestGetAppDeploymentStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		cdWf   *pipelineConfig.CdWorkflow
+		status repository.ReleaseAppStatus
+	}{
+		{
+			name:   "workflow not found",
+			cdWf:   &pipelineConfig.CdWorkflow{},
+			status: repository.ReleaseAppStatusFailed,
+		},
+		{
+			name:   "trigger error",
+			cdWf:   &pipelineConfig.CdWorkflow{Id: 1, WorkflowStatus: cdWorkflow.TRIGGER_ERROR},
+			status: repository.ReleaseAppStatusFailed,
+		},
+		{
+			name: "pre-deployment stage running",
+			cdWf: &pipelineConfig.CdWorkflow{Id: 1, CdWorkflowRunner: []pipelineConfig.CdWorkflowRunner{
+				{WorkflowType: apiBean.CD_WORKFLOW_TYPE_PRE, Status: cdWorkflow.WorkflowInProgress},
+			}},
+			status: repository.ReleaseAppStatusDeploying,
+		},
+		{
+			name: "pre-deployment stage failed",
+			cdWf: &pipelineConfig.CdWorkflow{Id: 1, CdWorkflowRunner: []pipelineConfig.CdWorkflowRunner{
+				{WorkflowType: apiBean.CD_WORKFLOW_TYPE_PRE, Status: cdWorkflow.WorkflowFailed},
+			}},
+			status: repository.ReleaseAppStatusFailed,
+		},
+		{
+			name: "deployment healthy",
+			cdWf: &pipelineConfig.CdWorkflow{Id: 1, CdWorkflowRunner: []pipelineConfig.CdWorkflowRunner{
+				{WorkflowType: apiBean.CD_WORKFLOW_TYPE_PRE, Status: cdWorkflow.WorkflowSucceeded},
+				{WorkflowType: apiBean.CD_WORKFLOW_TYPE_DEPLOY, Status: "Healthy"},
+			}},
+			status: repository.ReleaseAppStatusDeployed,
+		},
+		{
+			name: "deployment timed out",
+			cdWf: &pipelineConfig.CdWorkflow{Id: 1, CdWorkflowRunner: []pipelineConfig.CdWorkflowRunner{
+				{WorkflowType: apiBean.CD_WORKFLOW_TYPE_DEPLOY, Status: cdWorkflow.WorkflowTimedOut},
+			}},
+			status: repository.ReleaseAppStatusFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := getAppDeploymentStatus(tt.cdWf)
+			assert.Equal(t, tt.status, status)
+		})
+	}
+}
@@ -23,9 +23,13 @@ import (
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	bean2 "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
+	windowBean "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"net/http"
 	"strings"
 	"time"
 
+	"github.com/devtron-labs/devtron/internal/constants"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
@@ -41,7 +45,10 @@ type DeploymentGroupRequest struct {
 	CiPipelineId  int    `json:"ciPipelineId"`
 	EnvironmentId int    `json:"environmentId"`
 	AppIds        []int  `json:"appIds"`
-	UserId        int32  `json:"-"`
+	// Waves release the apps in order, the apps are released all at once if the group has no waves.
+	// Waves are left as is on update if not set and removed if empty.
+	Waves  []*DeploymentGroupWaveDto `json:"waves,omitempty"`
+	UserId int32                     `json:"-"`
 }
 
 type CiPipelineResponseForDG struct {
@@ -102,16 +109,18 @@ type CiMaterialDTO struct {
 }
 
 type DeploymentGroupServiceImpl struct {
-	appRepository                app.AppRepository
-	logger                       *zap.SugaredLogger
-	pipelineRepository           pipelineConfig.PipelineRepository
-	ciPipelineRepository         pipelineConfig.CiPipelineRepository
-	deploymentGroupRepository    repository.DeploymentGroupRepository
-	environmentRepository        repository2.EnvironmentRepository
-	deploymentGroupAppRepository repository.DeploymentGroupAppRepository
-	ciArtifactRepository         repository.CiArtifactRepository
-	appWorkflowRepository        appWorkflow.AppWorkflowRepository
-	workflowEventPublishService  out.WorkflowEventPublishService
+	appRepository                 app.AppRepository
+	logger                        *zap.SugaredLogger
+	pipelineRepository            pipelineConfig.PipelineRepository
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	deploymentGroupRepository     repository.DeploymentGroupRepository
+	environmentRepository         repository2.EnvironmentRepository
+	deploymentGroupAppRepository  repository.DeploymentGroupAppRepository
+	ciArtifactRepository          repository.CiArtifactRepository
+	appWorkflowRepository         appWorkflow.AppWorkflowRepository
+	workflowEventPublishService   out.WorkflowEventPublishService
+	deploymentWindowService       deploymentWindow.DeploymentWindowService
+	deploymentGroupReleaseService DeploymentGroupReleaseService
 }
 
 func NewDeploymentGroupServiceImpl(appRepository app.AppRepository, logger *zap.SugaredLogger,
@@ -120,18 +129,22 @@ func NewDeploymentGroupServiceImpl(appRepository app.AppRepository, logger *zap.
 	deploymentGroupAppRepository repository.DeploymentGroupAppRepository,
 	ciArtifactRepository repository.CiArtifactRepository,
 	appWorkflowRepository appWorkflow.AppWorkflowRepository,
-	workflowEventPublishService out.WorkflowEventPublishService) *DeploymentGroupServiceImpl {
+	workflowEventPublishService out.WorkflowEventPublishService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	deploymentGroupReleaseService DeploymentGroupReleaseService) *DeploymentGroupServiceImpl {
 	return &DeploymentGroupServiceImpl{
-		appRepository:                appRepository,
-		logger:                       logger,
-		pipelineRepository:           pipelineRepository,
-		ciPipelineRepository:         ciPipelineRepository,
-		deploymentGroupRepository:    deploymentGroupRepository,
-		environmentRepository:        environmentRepository,
-		deploymentGroupAppRepository: deploymentGroupAppRepository,
-		ciArtifactRepository:         ciArtifactRepository,
-		appWorkflowRepository:        appWorkflowRepository,
-		workflowEventPublishService:  workflowEventPublishService,
+		appRepository:                 appRepository,
+		logger:                        logger,
+		pipelineRepository:            pipelineRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+		deploymentGroupRepository:     deploymentGroupRepository,
+		environmentRepository:         environmentRepository,
+		deploymentGroupAppRepository:  deploymentGroupAppRepository,
+		ciArtifactRepository:          ciArtifactRepository,
+		appWorkflowRepository:         appWorkflowRepository,
+		workflowEventPublishService:   workflowEventPublishService,
+		deploymentWindowService:       deploymentWindowService,
+		deploymentGroupReleaseService: deploymentGroupReleaseService,
 	}
 }
 
@@ -182,6 +195,10 @@ func (impl *DeploymentGroupServiceImpl) FindById(id int) (*DeploymentGroupDTO, e
 func (impl *DeploymentGroupServiceImpl) CreateDeploymentGroup(deploymentGroupRequest *DeploymentGroupRequest) (*DeploymentGroupRequest, error) {
 
 	//TODO - WIRING
+	err := impl.deploymentGroupReleaseService.ValidateWaves(deploymentGroupRequest.AppIds, deploymentGroupRequest.Waves)
+	if err != nil {
+		return nil, err
+	}
 	model := &repository.DeploymentGroup{}
 	model.Name = deploymentGroupRequest.Name
 	model.EnvironmentId = deploymentGroupRequest.EnvironmentId
@@ -192,7 +209,7 @@ func (impl *DeploymentGroupServiceImpl) CreateDeploymentGroup(deploymentGroupReq
 	model.CreatedOn = time.Now()
 	model.UpdatedOn = time.Now()
 	model.AppCount = len(deploymentGroupRequest.AppIds)
-	model, err := impl.deploymentGroupRepository.Create(model)
+	model, err = impl.deploymentGroupRepository.Create(model)
 	if err != nil {
 		impl.logger.Errorw("error in creating DG", "error", err)
 		return nil, err
@@ -213,6 +230,12 @@ func (impl *DeploymentGroupServiceImpl) CreateDeploymentGroup(deploymentGroupReq
 			return nil, err
 		}
 	}
+	if len(deploymentGroupRequest.Waves) > 0 {
+		err = impl.deploymentGroupReleaseService.SaveWaves(model.Id, deploymentGroupRequest.AppIds, deploymentGroupRequest.Waves, deploymentGroupRequest.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
 	deploymentGroupRequest.Id = model.Id
 	return deploymentGroupRequest, nil
 }
@@ -482,7 +505,16 @@ func (impl *DeploymentGroupServiceImpl) TriggerReleaseForDeploymentGroup(trigger
 		impl.logger.Errorw("no cdPipelines found", "req", triggerRequest)
 		return nil, fmt.Errorf("no cdPipelines found corresponding to deployment group %d", triggerRequest.DeploymentGroupId)
 	}
+	err = impl.checkDeploymentWindows(cdPipelines)
+	if err != nil {
+		return nil, err
+	}
+	waves, err := impl.deploymentGroupReleaseService.GetWaves(group.Id)
+	if err != nil {
+		return nil, err
+	}
 	var requests []*bean2.BulkTriggerRequest
+	var releaseApps []*ReleaseAppRequest
 	ciArtefactMapping := make(map[int]*repository.CiArtifact)
 	for _, ciArtefact := range ciArtifacts {
 		ciArtefactMapping[ciArtefact.PipelineId] = ciArtefact
@@ -495,10 +527,19 @@ func (impl *DeploymentGroupServiceImpl) TriggerReleaseForDeploymentGroup(trigger
 				PipelineId:   cdPipeline.Id,
 			}
 			requests = append(requests, req)
+			releaseApps = append(releaseApps, &ReleaseAppRequest{
+				AppId:        cdPipeline.AppId,
+				CdPipelineId: cdPipeline.Id,
+				CiArtifactId: val.Id,
+			})
 		} else {
 			impl.logger.Warnw("no artifact found", "cdPipeline", cdPipeline)
 		}
 	}
+	if len(waves) > 0 {
+		// waves are triggered one after the other as their dependencies are released
+		return impl.deploymentGroupReleaseService.StartRelease(group.Id, triggerRequest.CiArtifactId, releaseApps, triggerRequest.UserId)
+	}
 	//trigger
 	// apply mapping
 	_, err = impl.workflowEventPublishService.TriggerBulkDeploymentAsync(requests, triggerRequest.UserId)
@@ -508,8 +549,40 @@ func (impl *DeploymentGroupServiceImpl) TriggerReleaseForDeploymentGroup(trigger
 	return nil, nil
 }
 
+// checkDeploymentWindows fails the release if deployment windows block any pipeline of the group, so that
+// the group is not released partially. Windows are enforced again when each pipeline is triggered.
+func (impl *DeploymentGroupServiceImpl) checkDeploymentWindows(cdPipelines []*pipelineConfig.Pipeline) error {
+	now := time.Now()
+	blockedPipelineNames := make([]string, 0)
+	blockedErrs := make([]*windowBean.DeploymentWindowBlockedError, 0)
+	for _, cdPipeline := range cdPipelines {
+		response, err := impl.deploymentWindowService.EvaluateWindows(cdPipeline, now)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment windows", "cdPipelineId", cdPipeline.Id, "err", err)
+			return err
+		}
+		if !response.Allowed {
+			blockedPipelineNames = append(blockedPipelineNames, cdPipeline.Name)
+			blockedErrs = append(blockedErrs, windowBean.NewDeploymentWindowBlockedError(response))
+		}
+	}
+	if len(blockedErrs) == 0 {
+		return nil
+	}
+	errMsg := fmt.Sprintf("deployment blocked by deployment windows for pipelines: %s", strings.Join(blockedPipelineNames, ", "))
+	return util.NewApiError(http.StatusUnprocessableEntity, errMsg, errMsg).
+		WithCode(constants.DeploymentWindowFail).
+		WithUserMessage(blockedErrs)
+}
+
 func (impl *DeploymentGroupServiceImpl) UpdateDeploymentGroup(deploymentGroupRequest *DeploymentGroupRequest) (*DeploymentGroupRequest, error) {
 
+	if deploymentGroupRequest.Waves != nil {
+		err := impl.deploymentGroupReleaseService.ValidateWaves(deploymentGroupRequest.AppIds, deploymentGroupRequest.Waves)
+		if err != nil {
+			return nil, err
+		}
+	}
 	model, err := impl.deploymentGroupRepository.GetById(deploymentGroupRequest.Id)
 	if err != nil {
 		impl.logger.Errorw("error in updating DG", "error", err)
@@ -576,6 +649,12 @@ func (impl *DeploymentGroupServiceImpl) UpdateDeploymentGroup(deploymentGroupReq
 			}
 		}
 	}
+	if deploymentGroupRequest.Waves != nil {
+		err = impl.deploymentGroupReleaseService.SaveWaves(model.Id, deploymentGroupRequest.AppIds, deploymentGroupRequest.Waves, deploymentGroupRequest.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return deploymentGroupRequest, nil
 }
@@ -684,5 +763,9 @@ func (impl *DeploymentGroupServiceImpl) GetDeploymentGroupById(deploymentGroupId
 		appIds = append(appIds, item.AppId)
 	}
 	deploymentGroupRequest.AppIds = appIds
+	deploymentGroupRequest.Waves, err = impl.deploymentGroupReleaseService.GetWaves(model.Id)
+	if err != nil {
+		return nil, err
+	}
 	return deploymentGroupRequest, err
 }
@@ -88,6 +88,7 @@ type CiCompleteEvent struct {
 	TargetPlatforms               []string                 `json:"targetPlatforms"`
 	pluginImageDetails            *registry.ImageDetailsFromCR
 	PluginArtifacts               *PluginArtifacts `json:"pluginArtifacts"`
+	Sbom                          json.RawMessage  `json:"sbom"` // SPDX or CycloneDX json sbom of the image, if generated by the build
 }
 
 func (c *CiCompleteEvent) GetPluginImageDetails() *registry.ImageDetailsFromCR {
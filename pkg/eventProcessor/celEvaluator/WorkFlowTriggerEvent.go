@@ -3,6 +3,7 @@ package celEvaluator
 import (
 	"errors"
 	"github.com/devtron-labs/devtron/cel"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
 	repository "github.com/devtron-labs/devtron/internal/sql/repository/imageTagging"
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/attributes"
@@ -14,6 +15,9 @@ import (
 
 type TriggerEventEvaluator interface {
 	IsPriorityDeployment(valuesOverrideResponse *app.ValuesOverrideResponse) (isPriorityEvent bool, err error)
+	// GetDeploymentAdmissionParams returns the params available to deployment admission policy expressions,
+	// refer GetDeploymentAdmissionParamDeclarations for the complete list
+	GetDeploymentAdmissionParams(request *DeploymentAdmissionParamsRequest) ([]cel.ExpressionParam, error)
 }
 
 type TriggerEventEvaluatorImpl struct {
@@ -69,87 +73,100 @@ func (impl *TriggerEventEvaluatorImpl) getPriorityDeploymentExpression() (string
 }
 
 func (impl *TriggerEventEvaluatorImpl) getParamsForPriorityDeployment(valuesOverrideResponse *app.ValuesOverrideResponse) ([]cel.ExpressionParam, error) {
-	imageReleaseTags, err := impl.imageTagRepository.GetTagsByArtifactId(valuesOverrideResponse.Artifact.Id)
-	if err != nil && !errors.Is(err, pg.ErrNoRows) {
-		impl.logger.Errorw("error in fetching image tags using artifactId", "err", err, "artifactId", valuesOverrideResponse.Artifact.Id)
+	imageLabels, err := impl.getImageLabels(valuesOverrideResponse.Artifact.Id)
+	if err != nil {
 		return nil, err
 	}
-	imageLabels := make([]string, 0, len(imageReleaseTags))
-	for _, imageTag := range imageReleaseTags {
-		imageLabels = append(imageLabels, imageTag.TagName)
-	}
 	project, err := impl.teamReadService.FindOne(valuesOverrideResponse.Pipeline.App.TeamId)
 	if err != nil {
 		impl.logger.Errorw("error while getting project", "projectId", valuesOverrideResponse.Pipeline.App.TeamId, "err", err)
 		return nil, err
 	}
-	containerRepository, containerImageTag, err := valuesOverrideResponse.Artifact.ExtractImageRepoAndTag()
+	metadata := &deploymentParamsMetadata{
+		appName:               valuesOverrideResponse.Pipeline.App.AppName,
+		projectName:           project.Name,
+		envName:               valuesOverrideResponse.EnvOverride.Environment.Name,
+		cdPipelineName:        valuesOverrideResponse.Pipeline.Name,
+		cdPipelineTriggerType: valuesOverrideResponse.Pipeline.TriggerType.ToString(),
+		isProdEnv:             valuesOverrideResponse.EnvOverride.Environment.Default,
+		clusterName:           valuesOverrideResponse.EnvOverride.Environment.Cluster.ClusterName,
+		chartRefId:            valuesOverrideResponse.EnvOverride.Chart.ChartRefId,
+		imageLabels:           imageLabels,
+	}
+	impl.setContainerImageMetadata(metadata, valuesOverrideResponse.Artifact)
+	return getDeploymentParams(metadata), nil
+}
+
+func (impl *TriggerEventEvaluatorImpl) GetDeploymentAdmissionParams(request *DeploymentAdmissionParamsRequest) ([]cel.ExpressionParam, error) {
+	imageLabels, err := impl.getImageLabels(request.Artifact.Id)
+	if err != nil {
+		return nil, err
+	}
+	project, err := impl.teamReadService.FindOne(request.Pipeline.App.TeamId)
+	if err != nil {
+		impl.logger.Errorw("error while getting project", "projectId", request.Pipeline.App.TeamId, "err", err)
+		return nil, err
+	}
+	metadata := &deploymentParamsMetadata{
+		appName:               request.Pipeline.App.AppName,
+		projectName:           project.Name,
+		envName:               request.Environment.Name,
+		cdPipelineName:        request.Pipeline.Name,
+		cdPipelineTriggerType: request.Pipeline.TriggerType.ToString(),
+		isProdEnv:             request.Environment.Default,
+		clusterName:           request.Environment.Cluster.ClusterName,
+		chartRefId:            request.ChartRefId,
+		imageLabels:           imageLabels,
+		triggerTime:           request.TriggeredAt,
+		deploymentTriggerType: request.TriggerType.ToString(),
+		isRollbackDeployment:  request.IsRollback,
+	}
+	impl.setContainerImageMetadata(metadata, request.Artifact)
+	impl.setCommitMetadata(metadata, request.Artifact)
+	return getDeploymentAdmissionParams(metadata), nil
+}
+
+func (impl *TriggerEventEvaluatorImpl) getImageLabels(artifactId int) ([]string, error) {
+	imageReleaseTags, err := impl.imageTagRepository.GetTagsByArtifactId(artifactId)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		impl.logger.Errorw("error in fetching image tags using artifactId", "err", err, "artifactId", artifactId)
+		return nil, err
+	}
+	imageLabels := make([]string, 0, len(imageReleaseTags))
+	for _, imageTag := range imageReleaseTags {
+		imageLabels = append(imageLabels, imageTag.TagName)
+	}
+	return imageLabels, nil
+}
+
+func (impl *TriggerEventEvaluatorImpl) setContainerImageMetadata(metadata *deploymentParamsMetadata, artifact *repository2.CiArtifact) {
+	containerRepository, containerImageTag, err := artifact.ExtractImageRepoAndTag()
 	if err != nil {
 		impl.logger.Errorw("error in getting image tag and repo", "err", err)
 	}
+	metadata.containerRepository = containerRepository
+	metadata.containerImage = artifact.Image
+	metadata.containerImageTag = containerImageTag
+}
 
-	containerImage := valuesOverrideResponse.Artifact.Image
-	params := []cel.ExpressionParam{
-		{
-			ParamName: cel.AppName,
-			Value:     valuesOverrideResponse.Pipeline.App.AppName,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.ProjectName,
-			Value:     project.Name,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.EnvName,
-			Value:     valuesOverrideResponse.EnvOverride.Environment.Name,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.CdPipelineName,
-			Value:     valuesOverrideResponse.Pipeline.Name,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.CdPipelineTriggerType,
-			Value:     valuesOverrideResponse.Pipeline.TriggerType.ToString(),
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.IsProdEnv,
-			Value:     valuesOverrideResponse.EnvOverride.Environment.Default,
-			Type:      cel.ParamTypeBool,
-		},
-		{
-			ParamName: cel.ClusterName,
-			Value:     valuesOverrideResponse.EnvOverride.Environment.Cluster.ClusterName,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.ChartRefId,
-			Value:     valuesOverrideResponse.EnvOverride.Chart.ChartRefId,
-			Type:      cel.ParamTypeInteger,
-		},
-		{
-			ParamName: cel.ContainerRepo,
-			Value:     containerRepository,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.ContainerImage,
-			Value:     containerImage,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.ContainerImageTag,
-			Value:     containerImageTag,
-			Type:      cel.ParamTypeString,
-		},
-		{
-			ParamName: cel.ImageLabels,
-			Value:     imageLabels,
-			Type:      cel.ParamTypeList,
-		},
+func (impl *TriggerEventEvaluatorImpl) setCommitMetadata(metadata *deploymentParamsMetadata, artifact *repository2.CiArtifact) {
+	metadata.commitHashes, metadata.commitMessages = make([]string, 0), make([]string, 0)
+	metadata.commitAuthors, metadata.sourceBranches = make([]string, 0), make([]string, 0)
+	if len(artifact.MaterialInfo) == 0 {
+		return
+	}
+	ciMaterials, err := repository2.GetCiMaterialInfo(artifact.MaterialInfo, artifact.DataSource)
+	if err != nil {
+		// commit info is not available for all data sources, expressions will get empty lists in that case
+		impl.logger.Warnw("error in parsing material info of artifact", "artifactId", artifact.Id, "err", err)
+		return
+	}
+	for _, ciMaterial := range ciMaterials {
+		for _, modification := range ciMaterial.Modifications {
+			metadata.commitHashes = append(metadata.commitHashes, modification.Revision)
+			metadata.commitMessages = append(metadata.commitMessages, modification.Message)
+			metadata.commitAuthors = append(metadata.commitAuthors, modification.Author)
+			metadata.sourceBranches = append(metadata.sourceBranches, modification.Branch)
+		}
 	}
-	return params, nil
 }
@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package celEvaluator
+
+import (
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"time"
+)
+
+type DeploymentAdmissionParamsRequest struct {
+	// Pipeline should have App loaded
+	Pipeline *pipelineConfig.Pipeline
+	// Environment should have Cluster loaded
+	Environment *repository2.Environment
+	Artifact    *repository.CiArtifact
+	ChartRefId  int
+	TriggeredAt time.Time
+	TriggerType pipelineConfig.TriggerType
+	IsRollback  bool
+}
+
+type deploymentParamsMetadata struct {
+	appName               string
+	projectName           string
+	envName               string
+	cdPipelineName        string
+	cdPipelineTriggerType string
+	isProdEnv             bool
+	clusterName           string
+	chartRefId            int
+	containerRepository   string
+	containerImage        string
+	containerImageTag     string
+	imageLabels           []string
+	// below fields are only used for deployment admission policies
+	commitHashes          []string
+	commitMessages        []string
+	commitAuthors         []string
+	sourceBranches        []string
+	triggerTime           time.Time
+	deploymentTriggerType string
+	isRollbackDeployment  bool
+}
+
+func getDeploymentParams(metadata *deploymentParamsMetadata) []cel.ExpressionParam {
+	return []cel.ExpressionParam{
+		{
+			ParamName: cel.AppName,
+			Value:     metadata.appName,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.ProjectName,
+			Value:     metadata.projectName,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.EnvName,
+			Value:     metadata.envName,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.CdPipelineName,
+			Value:     metadata.cdPipelineName,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.CdPipelineTriggerType,
+			Value:     metadata.cdPipelineTriggerType,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.IsProdEnv,
+			Value:     metadata.isProdEnv,
+			Type:      cel.ParamTypeBool,
+		},
+		{
+			ParamName: cel.ClusterName,
+			Value:     metadata.clusterName,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.ChartRefId,
+			Value:     metadata.chartRefId,
+			Type:      cel.ParamTypeInteger,
+		},
+		{
+			ParamName: cel.ContainerRepo,
+			Value:     metadata.containerRepository,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.ContainerImage,
+			Value:     metadata.containerImage,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.ContainerImageTag,
+			Value:     metadata.containerImageTag,
+			Type:      cel.ParamTypeString,
+		},
+		{
+			ParamName: cel.ImageLabels,
+			Value:     metadata.imageLabels,
+			Type:      cel.ParamTypeList,
+		},
+	}
+}
+
+func getDeploymentAdmissionParams(metadata *deploymentParamsMetadata) []cel.ExpressionParam {
+	return append(getDeploymentParams(metadata),
+		cel.ExpressionParam{
+			ParamName: cel.CommitHashes,
+			Value:     metadata.commitHashes,
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.CommitMessages,
+			Value:     metadata.commitMessages,
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.CommitAuthors,
+			Value:     metadata.commitAuthors,
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.SourceBranches,
+			Value:     metadata.sourceBranches,
+			Type:      cel.ParamTypeList,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.TriggerTime,
+			Value:     metadata.triggerTime,
+			Type:      cel.ParamTypeTimestamp,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.DeploymentTriggerType,
+			Value:     metadata.deploymentTriggerType,
+			Type:      cel.ParamTypeString,
+		},
+		cel.ExpressionParam{
+			ParamName: cel.IsRollbackDeployment,
+			Value:     metadata.isRollbackDeployment,
+			Type:      cel.ParamTypeBool,
+		},
+	)
+}
+
+// GetDeploymentAdmissionParamDeclarations returns the params (with zero values) which can be used in
+// deployment admission policy expressions, used for validating the expressions on save
+func GetDeploymentAdmissionParamDeclarations() []cel.ExpressionParam {
+	return getDeploymentAdmissionParams(&deploymentParamsMetadata{})
+}
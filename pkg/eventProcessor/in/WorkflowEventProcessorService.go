@@ -756,6 +756,7 @@ func (impl *WorkflowEventProcessorImpl) BuildCiArtifactRequest(event bean.CiComp
 		PluginArtifactStage:           event.PluginArtifactStage,
 		IsScanEnabled:                 event.IsScanEnabled,
 		TargetPlatforms:               event.TargetPlatforms,
+		Sbom:                          event.Sbom,
 	}
 	// if DataSource is empty, repository.WEBHOOK is considered as default
 	if request.DataSource == "" {
@@ -57,6 +57,7 @@ type NotificationConfigServiceImpl struct {
 	pipelineRepository             pipelineConfig.PipelineRepository
 	slackRepository                repository.SlackNotificationRepository
 	webhookRepository              repository.WebhookNotificationRepository
+	teamsRepository                repository.TeamsNotificationRepository
 	sesRepository                  repository.SESNotificationRepository
 	smtpRepository                 repository.SMTPNotificationRepository
 	environmentRepository          repository3.EnvironmentRepository
@@ -73,7 +74,7 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 	teamRepository repository2.TeamRepository,
 	environmentRepository repository3.EnvironmentRepository, appRepository app.AppRepository, clusterService clusterService.ClusterService,
 	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
-	teamReadService read.TeamReadService) *NotificationConfigServiceImpl {
+	teamReadService read.TeamReadService, teamsRepository repository.TeamsNotificationRepository) *NotificationConfigServiceImpl {
 	return &NotificationConfigServiceImpl{
 		logger:                         logger,
 		notificationSettingsRepository: notificationSettingsRepository,
@@ -83,6 +84,7 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 		sesRepository:                  sesRepository,
 		slackRepository:                slackRepository,
 		webhookRepository:              webhookRepository,
+		teamsRepository:                teamsRepository,
 		smtpRepository:                 smtpRepository,
 		environmentRepository:          environmentRepository,
 		appRepository:                  appRepository,
@@ -294,12 +296,15 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 		if config.Providers != nil && len(config.Providers) > 0 {
 			var slackIds []*int
 			var webhookIds []*int
+			var teamsIds []*int
 			var providerConfigs []*beans.ProvidersConfig
 			for _, item := range config.Providers {
 				if item.Destination == util.Slack {
 					slackIds = append(slackIds, &item.ConfigId)
 				} else if item.Destination == util.Webhook {
 					webhookIds = append(webhookIds, &item.ConfigId)
+				} else if item.Destination == util.Teams {
+					teamsIds = append(teamsIds, &item.ConfigId)
 				} else {
 					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Dest: string(item.Destination), Recipient: item.Recipient, Id: item.ConfigId})
 				}
@@ -324,6 +329,16 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: string(util.Webhook)})
 				}
 			}
+			if len(teamsIds) > 0 {
+				teamsConfigs, err := impl.teamsRepository.FindByIds(teamsIds)
+				if err != nil && err != pg.ErrNoRows {
+					impl.logger.Errorw("error in fetching teams config", "teamsIds", teamsIds, "err", err)
+					return notificationSettingsResponses, deletedItemCount, err
+				}
+				for _, item := range teamsConfigs {
+					providerConfigs = append(providerConfigs, &beans.ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: string(util.Teams)})
+				}
+			}
 			notificationSettingsResponse.ProvidersConfig = providerConfigs
 		}
 
@@ -382,6 +397,7 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 		sesConfigNamesMap := map[int]string{}
 		slackConfigNameMap := map[int]string{}
 		smtpConfigNamesMap := map[int]string{}
+		teamsConfigNameMap := map[int]string{}
 		for _, c := range config.Providers {
 			if util.Slack == c.Destination {
 				if _, ok := slackConfigNameMap[c.ConfigId]; ok {
@@ -398,12 +414,18 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 					continue
 				}
 				smtpConfigNamesMap[c.ConfigId] = ""
+			} else if util.Teams == c.Destination {
+				if _, ok := teamsConfigNameMap[c.ConfigId]; ok {
+					continue
+				}
+				teamsConfigNameMap[c.ConfigId] = ""
 			}
 		}
 
 		slackIds := make([]int, 0, len(slackConfigNameMap))
 		sesIds := make([]int, 0, len(sesConfigNamesMap))
 		smtpIds := make([]int, 0, len(smtpConfigNamesMap))
+		teamsIds := make([]int, 0, len(teamsConfigNameMap))
 
 		for k := range slackConfigNameMap {
 			slackIds = append(slackIds, k)
@@ -414,6 +436,9 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 		for k := range smtpConfigNamesMap {
 			smtpIds = append(smtpIds, k)
 		}
+		for k := range teamsConfigNameMap {
+			teamsIds = append(teamsIds, k)
+		}
 
 		if len(slackIds) > 0 {
 			slackConfigs, err := impl.slackRepository.FindByIdsIn(slackIds)
@@ -445,6 +470,16 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 				smtpConfigNamesMap[s.Id] = s.ConfigName
 			}
 		}
+		if len(teamsIds) > 0 {
+			teamsConfigs, err := impl.teamsRepository.FindByIdsIn(teamsIds)
+			if err != nil {
+				impl.logger.Errorw("error on fetch teams configs", "err", err)
+				return []beans.ProvidersConfig{}, err
+			}
+			for _, s := range teamsConfigs {
+				teamsConfigNameMap[s.Id] = s.ConfigName
+			}
+		}
 		for _, c := range config.Providers {
 			var configName string
 			if c.Destination == util.Slack {
@@ -453,6 +488,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config beans.Con
 				configName = sesConfigNamesMap[c.ConfigId]
 			} else if c.Destination == util.SMTP {
 				configName = smtpConfigNamesMap[c.ConfigId]
+			} else if c.Destination == util.Teams {
+				configName = teamsConfigNameMap[c.ConfigId]
 			}
 			providerConfig := beans.ProvidersConfig{
 				Id:         c.ConfigId,
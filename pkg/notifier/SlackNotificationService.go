@@ -201,6 +201,8 @@ func (impl *SlackNotificationServiceImpl) RecipientListingSuggestion(value strin
 								}
 								if strings.Contains(v.(string), beans.SLACK_URL) {
 									result.Dest = eventUtil.Slack
+								} else if strings.Contains(v.(string), beans.TEAMS_URL) {
+									result.Dest = eventUtil.Teams
 								} else if strings.Contains(v.(string), beans.WEBHOOK_URL) {
 									result.Dest = eventUtil.Webhook
 								} else {
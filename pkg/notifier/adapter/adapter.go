@@ -115,6 +115,119 @@ func BuildConfigUpdateModelForSlack(slackConfig *repository.SlackConfig, model *
 	model.UpdatedBy = userId
 }
 
+func AdaptTeamsConfig(teamsConfig repository.TeamsConfig) beans.TeamsConfigDto {
+	teamsConfigDto := beans.TeamsConfigDto{
+		OwnerId:     teamsConfig.OwnerId,
+		TeamId:      teamsConfig.TeamId,
+		WebhookUrl:  teamsConfig.WebHookUrl,
+		ConfigName:  teamsConfig.ConfigName,
+		Description: teamsConfig.Description,
+		Id:          teamsConfig.Id,
+	}
+	return teamsConfigDto
+}
+
+func BuildTeamsNewConfigs(teamsReq []beans.TeamsConfigDto, userId int32) []*repository.TeamsConfig {
+	var teamsConfigs []*repository.TeamsConfig
+	for _, c := range teamsReq {
+		teamsConfig := &repository.TeamsConfig{
+			Id:          c.Id,
+			ConfigName:  c.ConfigName,
+			WebHookUrl:  c.WebhookUrl,
+			Description: c.Description,
+			AuditLog: sql.AuditLog{
+				CreatedBy: userId,
+				CreatedOn: time.Now(),
+				UpdatedOn: time.Now(),
+				UpdatedBy: userId,
+			},
+		}
+		if c.TeamId != 0 {
+			teamsConfig.TeamId = c.TeamId
+		} else {
+			teamsConfig.OwnerId = userId
+		}
+		teamsConfigs = append(teamsConfigs, teamsConfig)
+	}
+	return teamsConfigs
+}
+
+func BuildConfigUpdateModelForTeams(teamsConfig *repository.TeamsConfig, model *repository.TeamsConfig, userId int32) {
+	model.WebHookUrl = teamsConfig.WebHookUrl
+	model.ConfigName = teamsConfig.ConfigName
+	model.Description = teamsConfig.Description
+	if teamsConfig.TeamId != 0 {
+		model.TeamId = teamsConfig.TeamId
+	} else {
+		model.OwnerId = teamsConfig.OwnerId
+	}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+}
+
+// BuildTeamsAdaptiveCardMessage wraps the notification content in an adaptive card attachment accepted by Teams webhooks
+func BuildTeamsAdaptiveCardMessage(content *beans.TeamsCardContent) *beans.TeamsMessage {
+	body := []*beans.AdaptiveCardElement{
+		{
+			Type:   beans.AdaptiveCardTextBlock,
+			Text:   content.Title,
+			Weight: beans.AdaptiveCardWeightBolder,
+			Size:   beans.AdaptiveCardSizeLarge,
+			Color:  content.Color,
+			Wrap:   true,
+		},
+	}
+	if len(content.Subtitle) > 0 {
+		body = append(body, &beans.AdaptiveCardElement{
+			Type:     beans.AdaptiveCardTextBlock,
+			Text:     content.Subtitle,
+			IsSubtle: true,
+			Wrap:     true,
+		})
+	}
+	facts := make([]beans.AdaptiveCardFact, 0, len(content.Facts))
+	for _, fact := range content.Facts {
+		if len(fact.Value) == 0 {
+			continue
+		}
+		if value := []rune(fact.Value); len(value) > beans.AdaptiveCardMaxFactValueLength {
+			fact.Value = string(value[:beans.AdaptiveCardMaxFactValueLength]) + "..."
+		}
+		facts = append(facts, fact)
+	}
+	if len(facts) > 0 {
+		body = append(body, &beans.AdaptiveCardElement{
+			Type:    beans.AdaptiveCardFactSet,
+			Spacing: beans.AdaptiveCardSpacingMedium,
+			Facts:   facts,
+		})
+	}
+	actions := make([]beans.AdaptiveCardAction, 0, len(content.Actions))
+	for _, action := range content.Actions {
+		if len(action.Url) == 0 {
+			continue
+		}
+		action.Type = beans.AdaptiveCardActionOpenUrl
+		actions = append(actions, action)
+	}
+	return &beans.TeamsMessage{
+		Type: beans.TeamsMessageType,
+		Attachments: []*beans.TeamsMessageAttachment{
+			{
+				ContentType: beans.AdaptiveCardContentType,
+				Content: &beans.AdaptiveCard{
+					Schema:  beans.AdaptiveCardSchema,
+					Type:    beans.AdaptiveCardType,
+					Version: beans.AdaptiveCardVersion,
+					Body:    body,
+					Actions: actions,
+					MsTeams: &beans.AdaptiveCardMsTeams{Width: beans.AdaptiveCardFullWidth},
+				},
+			},
+		},
+	}
+}
+
 func AdaptSMTPConfig(smtpConfig *repository.SMTPConfig) *beans.SMTPConfigDto {
 	smtpConfigDto := &beans.SMTPConfigDto{
 		OwnerId:      smtpConfig.OwnerId,
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package beans
+
+// Microsoft Teams renders incoming webhook messages carrying adaptive card attachments,
+// see https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using
+
+const (
+	TeamsMessageType               = "message"
+	AdaptiveCardContentType        = "application/vnd.microsoft.card.adaptive"
+	AdaptiveCardSchema             = "http://adaptivecards.io/schemas/adaptive-card.json"
+	AdaptiveCardType               = "AdaptiveCard"
+	AdaptiveCardVersion            = "1.4"
+	AdaptiveCardTextBlock          = "TextBlock"
+	AdaptiveCardFactSet            = "FactSet"
+	AdaptiveCardActionOpenUrl      = "Action.OpenUrl"
+	AdaptiveCardFullWidth          = "Full"
+	AdaptiveCardWeightBolder       = "Bolder"
+	AdaptiveCardSizeLarge          = "Large"
+	AdaptiveCardSpacingMedium      = "Medium"
+	AdaptiveCardMaxFactValueLength = 500
+)
+
+type AdaptiveCardColor string
+
+const (
+	AdaptiveCardColorDefault   AdaptiveCardColor = "Default"
+	AdaptiveCardColorAccent    AdaptiveCardColor = "Accent"
+	AdaptiveCardColorGood      AdaptiveCardColor = "Good"
+	AdaptiveCardColorWarning   AdaptiveCardColor = "Warning"
+	AdaptiveCardColorAttention AdaptiveCardColor = "Attention"
+)
+
+// TeamsCardContent is the channel agnostic content of a notification, rendered as an adaptive card
+type TeamsCardContent struct {
+	Title    string
+	Subtitle string
+	Color    AdaptiveCardColor
+	Facts    []AdaptiveCardFact
+	Actions  []AdaptiveCardAction
+}
+
+type TeamsMessage struct {
+	Type        string                    `json:"type"`
+	Attachments []*TeamsMessageAttachment `json:"attachments"`
+}
+
+type TeamsMessageAttachment struct {
+	ContentType string        `json:"contentType"`
+	ContentUrl  *string       `json:"contentUrl"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+type AdaptiveCard struct {
+	Schema  string                 `json:"$schema"`
+	Type    string                 `json:"type"`
+	Version string                 `json:"version"`
+	Body    []*AdaptiveCardElement `json:"body"`
+	Actions []AdaptiveCardAction   `json:"actions,omitempty"`
+	MsTeams *AdaptiveCardMsTeams   `json:"msteams,omitempty"`
+}
+
+type AdaptiveCardMsTeams struct {
+	Width string `json:"width"`
+}
+
+type AdaptiveCardElement struct {
+	Type     string             `json:"type"`
+	Text     string             `json:"text,omitempty"`
+	Weight   string             `json:"weight,omitempty"`
+	Size     string             `json:"size,omitempty"`
+	Color    AdaptiveCardColor  `json:"color,omitempty"`
+	Spacing  string             `json:"spacing,omitempty"`
+	Wrap     bool               `json:"wrap,omitempty"`
+	IsSubtle bool               `json:"isSubtle,omitempty"`
+	Facts    []AdaptiveCardFact `json:"facts,omitempty"`
+}
+
+type AdaptiveCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type AdaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
@@ -7,6 +7,7 @@ import (
 
 const (
 	SLACK_URL   = "https://hooks.slack.com/"
+	TEAMS_URL   = "webhook.office.com/"
 	WEBHOOK_URL = "https://"
 )
 
@@ -359,6 +360,22 @@ type SlackConfigDto struct {
 	Id          int    `json:"id" validate:"number"`
 }
 
+//Teams
+
+type TeamsChannelConfig struct {
+	Channel         util.Channel     `json:"channel" validate:"required"`
+	TeamsConfigDtos []TeamsConfigDto `json:"configs"`
+}
+
+type TeamsConfigDto struct {
+	OwnerId     int32  `json:"userId" validate:"number"`
+	TeamId      int    `json:"teamId" validate:"required"`
+	WebhookUrl  string `json:"webhookUrl" validate:"required,url"`
+	ConfigName  string `json:"configName" validate:"required"`
+	Description string `json:"description"`
+	Id          int    `json:"id" validate:"number"`
+}
+
 //SMTP
 
 type SMTPChannelConfig struct {
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/bean"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/repository"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// IncidentProviderClient opens and resolves incidents with an incident management provider,
+// incidents are identified with the provider by their dedup key
+type IncidentProviderClient interface {
+	Trigger(config *repository.IncidentIntegrationConfig, dedupKey string, detail *bean.IncidentDetail) error
+	Resolve(config *repository.IncidentIntegrationConfig, dedupKey string) error
+}
+
+func newIncidentProviderClient(provider repository.IncidentProvider, client *http.Client) (IncidentProviderClient, error) {
+	switch provider {
+	case repository.PagerDuty:
+		return &pagerDutyClient{client: client}, nil
+	case repository.Opsgenie:
+		return &opsgenieClient{client: client}, nil
+	}
+	return nil, fmt.Errorf("unsupported incident provider %q", provider)
+}
+
+// pagerDutyClient uses PagerDuty Events API v2
+type pagerDutyClient struct {
+	client *http.Client
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+func (impl *pagerDutyClient) Trigger(config *repository.IncidentIntegrationConfig, dedupKey string, detail *bean.IncidentDetail) error {
+	event := &pagerDutyEvent{
+		RoutingKey:  config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: &pagerDutyPayload{
+			Summary:       detail.Summary,
+			Source:        bean.IncidentSource,
+			Severity:      detail.Severity,
+			Component:     detail.AppName,
+			Group:         detail.EnvName,
+			CustomDetails: detail.Details,
+		},
+	}
+	if len(detail.Link) > 0 {
+		event.Links = []pagerDutyLink{{Href: detail.Link, Text: "View in Devtron"}}
+	}
+	return impl.send(config, event)
+}
+
+func (impl *pagerDutyClient) Resolve(config *repository.IncidentIntegrationConfig, dedupKey string) error {
+	return impl.send(config, &pagerDutyEvent{
+		RoutingKey:  config.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey,
+	})
+}
+
+func (impl *pagerDutyClient) send(config *repository.IncidentIntegrationConfig, event *pagerDutyEvent) error {
+	eventsUrl := bean.PagerDutyEventsUrl
+	if len(config.ApiUrl) > 0 {
+		eventsUrl = config.ApiUrl
+	}
+	return postJson(impl.client, eventsUrl, nil, event)
+}
+
+// opsgenieClient uses Opsgenie Alert API, the dedup key is used as the alert alias
+type opsgenieClient struct {
+	client *http.Client
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieCloseRequest struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// opsgenie rejects alert messages longer than 130 characters
+const opsgenieMaxMessageLength = 130
+
+func (impl *opsgenieClient) Trigger(config *repository.IncidentIntegrationConfig, dedupKey string, detail *bean.IncidentDetail) error {
+	message := detail.Summary
+	if len(message) > opsgenieMaxMessageLength {
+		message = message[:opsgenieMaxMessageLength]
+	}
+	alert := &opsgenieAlert{
+		Message:     message,
+		Alias:       dedupKey,
+		Description: detail.Summary,
+		Priority:    bean.SupportedSeverities[detail.Severity],
+		Source:      bean.IncidentSource,
+		Entity:      detail.AppName,
+		Tags:        []string{bean.IncidentSource, detail.AppName, detail.EnvName},
+		Details:     detail.Details,
+	}
+	if len(detail.Link) > 0 {
+		alert.Description = fmt.Sprintf("%s\n%s", detail.Summary, detail.Link)
+	}
+	return postJson(impl.client, impl.getApiUrl(config)+"/v2/alerts", impl.getHeaders(config), alert)
+}
+
+func (impl *opsgenieClient) Resolve(config *repository.IncidentIntegrationConfig, dedupKey string) error {
+	closeUrl := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", impl.getApiUrl(config), url.PathEscape(dedupKey))
+	return postJson(impl.client, closeUrl, impl.getHeaders(config), &opsgenieCloseRequest{Source: bean.IncidentSource, Note: "resolved by a subsequent healthy deployment"})
+}
+
+func (impl *opsgenieClient) getApiUrl(config *repository.IncidentIntegrationConfig) string {
+	if len(config.ApiUrl) > 0 {
+		return strings.TrimSuffix(config.ApiUrl, "/")
+	}
+	return bean.OpsgenieApiUrl
+}
+
+func (impl *opsgenieClient) getHeaders(config *repository.IncidentIntegrationConfig) map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + config.RoutingKey}
+}
+
+func postJson(client *http.Client, requestUrl string, headers map[string]string, body interface{}) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, requestUrl, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response code %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incident
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/bean"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type capturedRequest struct {
+	path          string
+	authorization string
+	body          map[string]interface{}
+}
+
+func newCaptureServer(t *testing.T, captured *capturedRequest, statusCode int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured.path = r.URL.RequestURI()
+		captured.authorization = r.Header.Get("Authorization")
+		captured.body = make(map[string]interface{})
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&captured.body))
+		w.WriteHeader(statusCode)
+	}))
+}
+
+func TestPagerDutyClient(t *testing.T) {
+	captured := &capturedRequest{}
+	server := newCaptureServer(t, captured, http.StatusAccepted)
+	defer server.Close()
+	config := &repository.IncidentIntegrationConfig{Provider: repository.PagerDuty, RoutingKey: "routing-key", ApiUrl: server.URL}
+	client, err := newIncidentProviderClient(repository.PagerDuty, server.Client())
+	assert.Nil(t, err)
+
+	t.Run("trigger", func(t *testing.T) {
+		detail := &bean.IncidentDetail{Summary: "deployment failed", Severity: "critical", AppName: "app", EnvName: "prod", Link: "https://devtron.example.com"}
+		assert.Nil(t, client.Trigger(config, "dedup", detail))
+		assert.Equal(t, "routing-key", captured.body["routing_key"])
+		assert.Equal(t, "trigger", captured.body["event_action"])
+		assert.Equal(t, "dedup", captured.body["dedup_key"])
+		payload := captured.body["payload"].(map[string]interface{})
+		assert.Equal(t, "deployment failed", payload["summary"])
+		assert.Equal(t, "critical", payload["severity"])
+		assert.Len(t, captured.body["links"], 1)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		assert.Nil(t, client.Resolve(config, "dedup"))
+		assert.Equal(t, "resolve", captured.body["event_action"])
+		assert.Equal(t, "dedup", captured.body["dedup_key"])
+		assert.Nil(t, captured.body["payload"])
+	})
+}
+
+func TestOpsgenieClient(t *testing.T) {
+	captured := &capturedRequest{}
+	server := newCaptureServer(t, captured, http.StatusAccepted)
+	defer server.Close()
+	config := &repository.IncidentIntegrationConfig{Provider: repository.Opsgenie, RoutingKey: "api-key", ApiUrl: server.URL + "/"}
+	client, err := newIncidentProviderClient(repository.Opsgenie, server.Client())
+	assert.Nil(t, err)
+
+	t.Run("trigger truncates message", func(t *testing.T) {
+		detail := &bean.IncidentDetail{Summary: strings.Repeat("a", 200), Severity: "warning", AppName: "app", EnvName: "prod"}
+		assert.Nil(t, client.Trigger(config, "dedup", detail))
+		assert.Equal(t, "/v2/alerts", captured.path)
+		assert.Equal(t, "GenieKey api-key", captured.authorization)
+		assert.Equal(t, "dedup", captured.body["alias"])
+		assert.Equal(t, "P3", captured.body["priority"])
+		assert.Len(t, captured.body["message"], opsgenieMaxMessageLength)
+	})
+
+	t.Run("resolve closes by alias", func(t *testing.T) {
+		assert.Nil(t, client.Resolve(config, "dedup"))
+		assert.Equal(t, "/v2/alerts/dedup/close?identifierType=alias", captured.path)
+	})
+}
+
+func TestIncidentProviderClientErrors(t *testing.T) {
+	_, err := newIncidentProviderClient("UNKNOWN", http.DefaultClient)
+	assert.NotNil(t, err)
+
+	server := newCaptureServer(t, &capturedRequest{}, http.StatusBadRequest)
+	defer server.Close()
+	client, _ := newIncidentProviderClient(repository.PagerDuty, server.Client())
+	err = client.Resolve(&repository.IncidentIntegrationConfig{ApiUrl: server.URL}, "dedup")
+	assert.NotNil(t, err)
+}
+
+func TestMaskRoutingKey(t *testing.T) {
+	assert.Equal(t, bean.MaskedRoutingKey, bean.MaskRoutingKey("abc"))
+	assert.Equal(t, bean.MaskedRoutingKey+"6789", bean.MaskRoutingKey("0123456789"))
+}
@@ -0,0 +1,482 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incident
+
+import (
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/async"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	attributesBean "github.com/devtron-labs/devtron/pkg/attributes/bean"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/bean"
+	incidentRepository "github.com/devtron-labs/devtron/pkg/notifier/incident/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+type IncidentService interface {
+	SaveConfig(request *bean.IncidentIntegrationConfigDto) (*bean.IncidentIntegrationConfigDto, error)
+	GetAllConfigs() ([]*bean.IncidentIntegrationConfigDto, error)
+	DeleteConfig(id int, userId int32) error
+	GetIncidents(appId, envId int) ([]*bean.DeploymentIncidentDto, error)
+
+	// HandleDeploymentFailure opens an incident for a failed deployment on a production environment, it is processed asynchronously
+	HandleDeploymentFailure(event *bean.DeploymentFailureEvent)
+	// HandleAppStatusUpdate opens an incident for an app going Degraded shortly after a deployment on a production environment
+	// and resolves the open incidents of the app once a subsequent deployment is Healthy, it is processed asynchronously
+	HandleAppStatusUpdate(appId, envId int, status string, statusChanged bool)
+}
+
+type IncidentServiceImpl struct {
+	logger                *zap.SugaredLogger
+	incidentRepository    incidentRepository.IncidentRepository
+	pipelineRepository    pipelineConfig.PipelineRepository
+	cdWorkflowRepository  pipelineConfig.CdWorkflowRepository
+	environmentRepository environmentRepository.EnvironmentRepository
+	attributesRepository  repository.AttributesRepository
+	asyncRunnable         *async.Runnable
+	config                *bean.IncidentConfig
+	client                *http.Client
+}
+
+func NewIncidentServiceImpl(logger *zap.SugaredLogger,
+	incidentRepository incidentRepository.IncidentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	attributesRepository repository.AttributesRepository,
+	asyncRunnable *async.Runnable) (*IncidentServiceImpl, error) {
+	config := &bean.IncidentConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing incident config", "err", err)
+		return nil, err
+	}
+	return &IncidentServiceImpl{
+		logger:                logger,
+		incidentRepository:    incidentRepository,
+		pipelineRepository:    pipelineRepository,
+		cdWorkflowRepository:  cdWorkflowRepository,
+		environmentRepository: environmentRepository,
+		attributesRepository:  attributesRepository,
+		asyncRunnable:         asyncRunnable,
+		config:                config,
+		client:                &http.Client{Timeout: time.Duration(config.ProviderTimeoutInSeconds) * time.Second},
+	}, nil
+}
+
+func (impl *IncidentServiceImpl) SaveConfig(request *bean.IncidentIntegrationConfigDto) (*bean.IncidentIntegrationConfigDto, error) {
+	if len(request.Severity) == 0 {
+		request.Severity = bean.DefaultSeverity
+	}
+	if _, ok := bean.SupportedSeverities[request.Severity]; !ok {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("unsupported severity %q", request.Severity), "unsupported severity")
+	}
+	environment, err := impl.environmentRepository.FindById(request.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", request.EnvironmentId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusBadRequest, "environment not found", "environment not found")
+		}
+		return nil, err
+	}
+	existing, err := impl.incidentRepository.FindConfigByEnvId(request.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching incident config", "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 && existing.Id != request.Id {
+		errMsg := fmt.Sprintf("incident integration is already configured for environment %s", environment.Name)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	routingKeyUpdated := len(request.RoutingKey) > 0 && !strings.HasPrefix(request.RoutingKey, bean.MaskedRoutingKey)
+	var model *incidentRepository.IncidentIntegrationConfig
+	if request.Id > 0 {
+		model, err = impl.incidentRepository.FindConfigById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching incident config", "id", request.Id, "err", err)
+			if util.IsErrNoRows(err) {
+				return nil, util.NewApiError(http.StatusNotFound, "incident integration config not found", "incident integration config not found")
+			}
+			return nil, err
+		}
+	} else {
+		if !routingKeyUpdated {
+			return nil, util.NewApiError(http.StatusBadRequest, "routing key is required", "routing key is required")
+		}
+		model = &incidentRepository.IncidentIntegrationConfig{AuditLog: sql.NewDefaultAuditLog(request.UserId)}
+	}
+	model.EnvironmentId = request.EnvironmentId
+	model.Provider = request.Provider
+	model.ApiUrl = request.ApiUrl
+	model.Severity = request.Severity
+	model.Active = request.Active
+	if routingKeyUpdated {
+		model.RoutingKey = request.RoutingKey
+	}
+	model.UpdateAuditLog(request.UserId)
+	if model.Id > 0 {
+		err = impl.incidentRepository.UpdateConfig(model)
+	} else {
+		err = impl.incidentRepository.SaveConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving incident config", "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	return adaptConfig(model, environment.Name), nil
+}
+
+func (impl *IncidentServiceImpl) GetAllConfigs() ([]*bean.IncidentIntegrationConfigDto, error) {
+	models, err := impl.incidentRepository.FindAllConfigs()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching incident configs", "err", err)
+		return nil, err
+	}
+	envIds := make([]*int, 0, len(models))
+	for _, model := range models {
+		envIds = append(envIds, &model.EnvironmentId)
+	}
+	envNames := make(map[int]string)
+	if len(envIds) > 0 {
+		environments, err := impl.environmentRepository.FindByIds(envIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environments", "envIds", envIds, "err", err)
+			return nil, err
+		}
+		for _, environment := range environments {
+			envNames[environment.Id] = environment.Name
+		}
+	}
+	configs := make([]*bean.IncidentIntegrationConfigDto, 0, len(models))
+	for _, model := range models {
+		configs = append(configs, adaptConfig(model, envNames[model.EnvironmentId]))
+	}
+	return configs, nil
+}
+
+func (impl *IncidentServiceImpl) DeleteConfig(id int, userId int32) error {
+	model, err := impl.incidentRepository.FindConfigById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching incident config", "id", id, "err", err)
+		if util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "incident integration config not found", "incident integration config not found")
+		}
+		return err
+	}
+	model.Deleted = true
+	model.UpdateAuditLog(userId)
+	err = impl.incidentRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting incident config", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *IncidentServiceImpl) GetIncidents(appId, envId int) ([]*bean.DeploymentIncidentDto, error) {
+	models, err := impl.incidentRepository.FindIncidents(appId, envId, bean.IncidentListLimit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching incidents", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	incidents := make([]*bean.DeploymentIncidentDto, 0, len(models))
+	for _, model := range models {
+		incident := &bean.DeploymentIncidentDto{
+			Id:                 model.Id,
+			AppId:              model.AppId,
+			EnvironmentId:      model.EnvironmentId,
+			PipelineId:         model.PipelineId,
+			CdWorkflowRunnerId: model.CdWorkflowRunnerId,
+			Provider:           model.Provider,
+			DedupKey:           model.DedupKey,
+			TriggerReason:      model.TriggerReason,
+			Summary:            model.Summary,
+			Status:             model.Status,
+			TriggeredOn:        model.CreatedOn,
+		}
+		if model.Status == incidentRepository.IncidentResolved {
+			resolvedOn := model.ResolvedOn
+			incident.ResolvedOn = &resolvedOn
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+func (impl *IncidentServiceImpl) HandleDeploymentFailure(event *bean.DeploymentFailureEvent) {
+	impl.asyncRunnable.Execute(func() {
+		err := impl.processDeploymentFailure(event)
+		if err != nil {
+			impl.logger.Errorw("error in processing deployment failure for incident", "event", event, "err", err)
+		}
+	})
+}
+
+func (impl *IncidentServiceImpl) HandleAppStatusUpdate(appId, envId int, status string, statusChanged bool) {
+	isHealthy := status == string(health.HealthStatusHealthy)
+	isDegraded := status == string(health.HealthStatusDegraded) && statusChanged
+	if !isHealthy && !isDegraded {
+		return
+	}
+	// every status sync of a healthy app reaches here, the incidents are resolved only if the app has any open
+	if isHealthy {
+		hasOpenIncident, err := impl.incidentRepository.ExistsOpenIncident(appId, envId)
+		if err != nil {
+			impl.logger.Errorw("error in checking open incidents", "appId", appId, "envId", envId, "err", err)
+			return
+		}
+		if !hasOpenIncident {
+			return
+		}
+	}
+	impl.asyncRunnable.Execute(func() {
+		var err error
+		if isHealthy {
+			err = impl.resolveIncidents(appId, envId)
+		} else {
+			err = impl.processAppDegraded(appId, envId)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in processing app status update for incident", "appId", appId, "envId", envId, "status", status, "err", err)
+		}
+	})
+}
+
+func (impl *IncidentServiceImpl) processDeploymentFailure(event *bean.DeploymentFailureEvent) error {
+	config, err := impl.getActiveConfig(event.EnvId)
+	if err != nil || config == nil {
+		return err
+	}
+	pipeline, err := impl.pipelineRepository.FindById(event.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", event.PipelineId, "err", err)
+		return err
+	}
+	if !pipeline.Environment.Default {
+		return nil
+	}
+	detail := impl.buildIncidentDetail(config, pipeline, fmt.Sprintf("%s failed for %s on %s", getStageDisplayName(event.Stage), pipeline.App.AppName, pipeline.Environment.Name))
+	if len(event.Stage) > 0 {
+		detail.Details["stage"] = event.Stage
+	}
+	if len(event.FailureReason) > 0 {
+		detail.Details["failureReason"] = event.FailureReason
+	}
+	if baseUrl := impl.getHostUrl(); len(baseUrl) > 0 && event.CdWorkflowRunnerId > 0 {
+		detail.Link = fmt.Sprintf("%s/dashboard/app/%d/cd-details/%d/%d/%d", baseUrl, pipeline.AppId, pipeline.EnvironmentId, pipeline.Id, event.CdWorkflowRunnerId)
+	}
+	return impl.openIncident(config, pipeline, event.CdWorkflowRunnerId, incidentRepository.DeploymentFailed, detail)
+}
+
+func (impl *IncidentServiceImpl) processAppDegraded(appId, envId int) error {
+	config, err := impl.getActiveConfig(envId)
+	if err != nil || config == nil {
+		return err
+	}
+	latestWfr, err := impl.cdWorkflowRepository.FindLatestWfrByAppIdAndEnvironmentId(appId, envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching latest deployment", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	window := time.Duration(impl.config.DegradedAfterDeployWindowInMinutes) * time.Minute
+	if latestWfr.CdWorkflow == nil || time.Since(latestWfr.StartedOn) > window {
+		return nil
+	}
+	pipeline, err := impl.pipelineRepository.FindById(latestWfr.CdWorkflow.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", latestWfr.CdWorkflow.PipelineId, "err", err)
+		return err
+	}
+	if !pipeline.Environment.Default {
+		return nil
+	}
+	detail := impl.buildIncidentDetail(config, pipeline, fmt.Sprintf("%s is Degraded on %s after deployment", pipeline.App.AppName, pipeline.Environment.Name))
+	if baseUrl := impl.getHostUrl(); len(baseUrl) > 0 {
+		detail.Link = fmt.Sprintf("%s/dashboard/app/%d/details/%d", baseUrl, appId, envId)
+	}
+	return impl.openIncident(config, pipeline, latestWfr.Id, incidentRepository.AppDegraded, detail)
+}
+
+// resolveIncidents resolves open incidents once a deployment subsequent to the one which caused the incident is Healthy,
+// incidents opened for a Degraded app are resolved as soon as the app is Healthy again
+func (impl *IncidentServiceImpl) resolveIncidents(appId, envId int) error {
+	incidents, err := impl.incidentRepository.FindOpenIncidents(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching open incidents", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	if len(incidents) == 0 {
+		return nil
+	}
+	latestWfr, err := impl.cdWorkflowRepository.FindLatestWfrByAppIdAndEnvironmentId(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching latest deployment", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	isLatestDeploymentHealthy := latestWfr != nil && slices.Contains([]string{cdWorkflow.WorkflowSucceeded, string(health.HealthStatusHealthy)}, latestWfr.Status)
+	for _, incident := range incidents {
+		if incident.TriggerReason != incidentRepository.AppDegraded &&
+			(!isLatestDeploymentHealthy || latestWfr.Id <= incident.CdWorkflowRunnerId) {
+			continue
+		}
+		config, err := impl.incidentRepository.FindConfigById(incident.IncidentIntegrationConfigId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching incident config", "id", incident.IncidentIntegrationConfigId, "err", err)
+			return err
+		}
+		// incident is closed locally if its integration was deleted, it can not be resolved with the provider anymore
+		if err == nil {
+			providerClient, err := newIncidentProviderClient(config.Provider, impl.client)
+			if err != nil {
+				return err
+			}
+			err = providerClient.Resolve(config, incident.DedupKey)
+			if err != nil {
+				impl.logger.Errorw("error in resolving incident with provider", "incidentId", incident.Id, "provider", config.Provider, "err", err)
+				return err
+			}
+		}
+		incident.Status = incidentRepository.IncidentResolved
+		incident.ResolvedOn = time.Now()
+		incident.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+		err = impl.incidentRepository.UpdateIncident(incident)
+		if err != nil {
+			impl.logger.Errorw("error in updating incident", "incidentId", incident.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// openIncident triggers an incident with the provider unless the app already has an open incident on the environment
+func (impl *IncidentServiceImpl) openIncident(config *incidentRepository.IncidentIntegrationConfig, pipeline *pipelineConfig.Pipeline,
+	cdWorkflowRunnerId int, reason incidentRepository.IncidentTriggerReason, detail *bean.IncidentDetail) error {
+	openIncidents, err := impl.incidentRepository.FindOpenIncidents(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching open incidents", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
+		return err
+	}
+	if len(openIncidents) > 0 {
+		impl.logger.Debugw("incident already open, skipping", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "incidentId", openIncidents[0].Id)
+		return nil
+	}
+	providerClient, err := newIncidentProviderClient(config.Provider, impl.client)
+	if err != nil {
+		return err
+	}
+	dedupKey := fmt.Sprintf("%s-%d-%d-%d", bean.IncidentSource, pipeline.AppId, pipeline.EnvironmentId, cdWorkflowRunnerId)
+	err = providerClient.Trigger(config, dedupKey, detail)
+	if err != nil {
+		impl.logger.Errorw("error in triggering incident with provider", "provider", config.Provider, "dedupKey", dedupKey, "err", err)
+		return err
+	}
+	incident := &incidentRepository.DeploymentIncident{
+		IncidentIntegrationConfigId: config.Id,
+		AppId:                       pipeline.AppId,
+		EnvironmentId:               pipeline.EnvironmentId,
+		PipelineId:                  pipeline.Id,
+		CdWorkflowRunnerId:          cdWorkflowRunnerId,
+		Provider:                    config.Provider,
+		DedupKey:                    dedupKey,
+		TriggerReason:               reason,
+		Summary:                     detail.Summary,
+		Status:                      incidentRepository.IncidentOpen,
+		AuditLog:                    sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+	}
+	err = impl.incidentRepository.SaveIncident(incident)
+	if err != nil {
+		impl.logger.Errorw("error in saving incident", "dedupKey", dedupKey, "err", err)
+		return err
+	}
+	return nil
+}
+
+// getActiveConfig returns nil if incidents are not configured for the environment
+func (impl *IncidentServiceImpl) getActiveConfig(envId int) (*incidentRepository.IncidentIntegrationConfig, error) {
+	config, err := impl.incidentRepository.FindConfigByEnvId(envId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching incident config", "envId", envId, "err", err)
+		return nil, err
+	}
+	if !config.Active {
+		return nil, nil
+	}
+	return config, nil
+}
+
+func (impl *IncidentServiceImpl) buildIncidentDetail(config *incidentRepository.IncidentIntegrationConfig, pipeline *pipelineConfig.Pipeline, summary string) *bean.IncidentDetail {
+	return &bean.IncidentDetail{
+		Summary:  summary,
+		Severity: config.Severity,
+		AppName:  pipeline.App.AppName,
+		EnvName:  pipeline.Environment.Name,
+		Details: map[string]string{
+			"application": pipeline.App.AppName,
+			"environment": pipeline.Environment.Name,
+			"pipeline":    pipeline.Name,
+		},
+	}
+}
+
+func (impl *IncidentServiceImpl) getHostUrl() string {
+	attribute, err := impl.attributesRepository.FindByKey(attributesBean.HostUrlKey)
+	if err != nil || attribute == nil {
+		return ""
+	}
+	return strings.TrimSuffix(attribute.Value, "/")
+}
+
+func getStageDisplayName(stage string) string {
+	switch stage {
+	case string(apiBean.CD_WORKFLOW_TYPE_PRE):
+		return "Pre-deployment stage"
+	case string(apiBean.CD_WORKFLOW_TYPE_POST):
+		return "Post-deployment stage"
+	}
+	return "Deployment"
+}
+
+func adaptConfig(model *incidentRepository.IncidentIntegrationConfig, envName string) *bean.IncidentIntegrationConfigDto {
+	return &bean.IncidentIntegrationConfigDto{
+		Id:            model.Id,
+		EnvironmentId: model.EnvironmentId,
+		Environment:   envName,
+		Provider:      model.Provider,
+		RoutingKey:    bean.MaskRoutingKey(model.RoutingKey),
+		ApiUrl:        model.ApiUrl,
+		Severity:      model.Severity,
+		Active:        model.Active,
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/notifier/incident/repository"
+	"time"
+)
+
+// CATEGORY=CD
+type IncidentConfig struct {
+	DegradedAfterDeployWindowInMinutes int `env:"INCIDENT_DEGRADED_AFTER_DEPLOY_WINDOW_MINS" envDefault:"30" description:"An incident is opened for an app going Degraded on a production environment only if it was deployed within these many minutes"`
+	ProviderTimeoutInSeconds           int `env:"INCIDENT_PROVIDER_TIMEOUT_SECS" envDefault:"10" description:"Timeout (in seconds) of requests made to PagerDuty or Opsgenie"`
+}
+
+const (
+	PagerDutyEventsUrl     = "https://events.pagerduty.com/v2/enqueue"
+	OpsgenieApiUrl         = "https://api.opsgenie.com"
+	DefaultSeverity        = "critical"
+	MaskedRoutingKey       = "********"
+	IncidentSource         = "devtron"
+	IncidentListLimit      = 100
+	maxRoutingKeyMaskChars = 4
+)
+
+// SupportedSeverities are the PagerDuty severities, mapped to priorities for Opsgenie
+var SupportedSeverities = map[string]string{
+	"critical": "P1",
+	"error":    "P2",
+	"warning":  "P3",
+	"info":     "P5",
+}
+
+type IncidentIntegrationConfigDto struct {
+	Id            int                         `json:"id"`
+	EnvironmentId int                         `json:"environmentId" validate:"required,min=1"`
+	Environment   string                      `json:"environment,omitempty"`
+	Provider      repository.IncidentProvider `json:"provider" validate:"required,oneof=PAGERDUTY OPSGENIE"`
+	// RoutingKey is the PagerDuty integration key or the Opsgenie API key, it is masked in responses
+	// and the existing key is retained if it is left empty or masked on update
+	RoutingKey string `json:"routingKey"`
+	ApiUrl     string `json:"apiUrl,omitempty" validate:"omitempty,url"`
+	Severity   string `json:"severity"`
+	Active     bool   `json:"active"`
+	UserId     int32  `json:"-"`
+}
+
+type DeploymentIncidentDto struct {
+	Id                 int                              `json:"id"`
+	AppId              int                              `json:"appId"`
+	EnvironmentId      int                              `json:"environmentId"`
+	PipelineId         int                              `json:"pipelineId"`
+	CdWorkflowRunnerId int                              `json:"cdWorkflowRunnerId"`
+	Provider           repository.IncidentProvider      `json:"provider"`
+	DedupKey           string                           `json:"dedupKey"`
+	TriggerReason      repository.IncidentTriggerReason `json:"triggerReason"`
+	Summary            string                           `json:"summary"`
+	Status             repository.IncidentStatus        `json:"status"`
+	TriggeredOn        time.Time                        `json:"triggeredOn"`
+	ResolvedOn         *time.Time                       `json:"resolvedOn,omitempty"`
+}
+
+// DeploymentFailureEvent is a failed stage of a deployment on a cd pipeline
+type DeploymentFailureEvent struct {
+	AppId              int
+	EnvId              int
+	PipelineId         int
+	CdWorkflowRunnerId int
+	Stage              string
+	FailureReason      string
+}
+
+// IncidentDetail is the provider agnostic content of an incident
+type IncidentDetail struct {
+	Summary  string
+	Severity string
+	AppName  string
+	EnvName  string
+	Details  map[string]string
+	Link     string
+}
+
+func MaskRoutingKey(routingKey string) string {
+	if len(routingKey) <= maxRoutingKeyMaskChars {
+		return MaskedRoutingKey
+	}
+	return MaskedRoutingKey + routingKey[len(routingKey)-maxRoutingKeyMaskChars:]
+}
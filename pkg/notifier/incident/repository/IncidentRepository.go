@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type IncidentProvider string
+
+const (
+	PagerDuty IncidentProvider = "PAGERDUTY"
+	Opsgenie  IncidentProvider = "OPSGENIE"
+)
+
+type IncidentStatus string
+
+const (
+	IncidentOpen     IncidentStatus = "OPEN"
+	IncidentResolved IncidentStatus = "RESOLVED"
+)
+
+type IncidentTriggerReason string
+
+const (
+	DeploymentFailed IncidentTriggerReason = "DEPLOYMENT_FAILED"
+	AppDegraded      IncidentTriggerReason = "APP_DEGRADED"
+)
+
+// IncidentIntegrationConfig routes incidents of an environment to a PagerDuty service or an Opsgenie team
+type IncidentIntegrationConfig struct {
+	tableName     struct{}         `sql:"incident_integration_config" pg:",discard_unknown_columns"`
+	Id            int              `sql:"id,pk"`
+	EnvironmentId int              `sql:"environment_id,notnull"`
+	Provider      IncidentProvider `sql:"provider,notnull"`
+	RoutingKey    string           `sql:"routing_key,notnull"`
+	ApiUrl        string           `sql:"api_url"`
+	Severity      string           `sql:"severity,notnull"`
+	Active        bool             `sql:"active,notnull"`
+	Deleted       bool             `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+// DeploymentIncident is an incident opened with the provider for an app on an environment
+type DeploymentIncident struct {
+	tableName                   struct{}              `sql:"deployment_incident" pg:",discard_unknown_columns"`
+	Id                          int                   `sql:"id,pk"`
+	IncidentIntegrationConfigId int                   `sql:"incident_integration_config_id,notnull"`
+	AppId                       int                   `sql:"app_id,notnull"`
+	EnvironmentId               int                   `sql:"environment_id,notnull"`
+	PipelineId                  int                   `sql:"pipeline_id,notnull"`
+	CdWorkflowRunnerId          int                   `sql:"cd_workflow_runner_id"`
+	Provider                    IncidentProvider      `sql:"provider,notnull"`
+	DedupKey                    string                `sql:"dedup_key,notnull"`
+	TriggerReason               IncidentTriggerReason `sql:"trigger_reason,notnull"`
+	Summary                     string                `sql:"summary"`
+	Status                      IncidentStatus        `sql:"status,notnull"`
+	ResolvedOn                  time.Time             `sql:"resolved_on"`
+	sql.AuditLog
+}
+
+type IncidentRepository interface {
+	SaveConfig(model *IncidentIntegrationConfig) error
+	UpdateConfig(model *IncidentIntegrationConfig) error
+	FindConfigById(id int) (*IncidentIntegrationConfig, error)
+	// FindConfigByEnvId returns the config of the environment, pg.ErrNoRows if none exists
+	FindConfigByEnvId(envId int) (*IncidentIntegrationConfig, error)
+	FindAllConfigs() ([]*IncidentIntegrationConfig, error)
+
+	SaveIncident(model *DeploymentIncident) error
+	UpdateIncident(model *DeploymentIncident) error
+	FindOpenIncidents(appId, envId int) ([]*DeploymentIncident, error)
+	ExistsOpenIncident(appId, envId int) (bool, error)
+	FindIncidents(appId, envId int, limit int) ([]*DeploymentIncident, error)
+}
+
+type IncidentRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewIncidentRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *IncidentRepositoryImpl {
+	return &IncidentRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *IncidentRepositoryImpl) SaveConfig(model *IncidentIntegrationConfig) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *IncidentRepositoryImpl) UpdateConfig(model *IncidentIntegrationConfig) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *IncidentRepositoryImpl) FindConfigById(id int) (*IncidentIntegrationConfig, error) {
+	model := &IncidentIntegrationConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *IncidentRepositoryImpl) FindConfigByEnvId(envId int) (*IncidentIntegrationConfig, error) {
+	model := &IncidentIntegrationConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("environment_id = ?", envId).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *IncidentRepositoryImpl) FindAllConfigs() ([]*IncidentIntegrationConfig, error) {
+	var models []*IncidentIntegrationConfig
+	err := impl.dbConnection.Model(&models).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *IncidentRepositoryImpl) SaveIncident(model *DeploymentIncident) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *IncidentRepositoryImpl) UpdateIncident(model *DeploymentIncident) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *IncidentRepositoryImpl) FindOpenIncidents(appId, envId int) ([]*DeploymentIncident, error) {
+	var models []*DeploymentIncident
+	err := impl.dbConnection.Model(&models).
+		Where("app_id = ?", appId).
+		Where("environment_id = ?", envId).
+		Where("status = ?", IncidentOpen).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *IncidentRepositoryImpl) ExistsOpenIncident(appId, envId int) (bool, error) {
+	return impl.dbConnection.Model(&DeploymentIncident{}).
+		Where("app_id = ?", appId).
+		Where("environment_id = ?", envId).
+		Where("status = ?", IncidentOpen).
+		Exists()
+}
+
+func (impl *IncidentRepositoryImpl) FindIncidents(appId, envId int, limit int) ([]*DeploymentIncident, error) {
+	var models []*DeploymentIncident
+	query := impl.dbConnection.Model(&models)
+	if appId > 0 {
+		query = query.Where("app_id = ?", appId)
+	}
+	if envId > 0 {
+		query = query.Where("environment_id = ?", envId)
+	}
+	err := query.Order("id DESC").Limit(limit).Select()
+	return models, err
+}
@@ -38,6 +38,7 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/build/pipeline/bean/common"
 	"github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/schedule"
 	pipelineConfigBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/history"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -152,6 +153,7 @@ type CiPipelineConfigServiceImpl struct {
 	pipelineStageRepository       repository.PipelineStageRepository
 	globalPluginRepository        repository2.GlobalPluginRepository
 	appListingService             app.AppListingService
+	ciPipelineScheduleService     schedule.CiPipelineScheduleService
 }
 
 func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
@@ -180,7 +182,8 @@ func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
 	buildPipelineSwitchService BuildPipelineSwitchService,
 	pipelineStageRepository repository.PipelineStageRepository,
 	globalPluginRepository repository2.GlobalPluginRepository,
-	appListingService app.AppListingService) *CiPipelineConfigServiceImpl {
+	appListingService app.AppListingService,
+	ciPipelineScheduleService schedule.CiPipelineScheduleService) *CiPipelineConfigServiceImpl {
 	securityConfig := &SecurityConfig{}
 	err := env.Parse(securityConfig)
 	if err != nil {
@@ -215,6 +218,7 @@ func NewCiPipelineConfigServiceImpl(logger *zap.SugaredLogger,
 		pipelineStageRepository:       pipelineStageRepository,
 		globalPluginRepository:        globalPluginRepository,
 		appListingService:             appListingService,
+		ciPipelineScheduleService:     ciPipelineScheduleService,
 	}
 }
 
@@ -691,6 +695,10 @@ func (impl *CiPipelineConfigServiceImpl) GetCiPipeline(appId int) (ciConfig *bea
 		ciPipeline.LinkedCount = len(linkedCis)
 		ciPipelineResp = append(ciPipelineResp, ciPipeline)
 	}
+	err = impl.updateCiPipelineSchedules(ciPipelineResp)
+	if err != nil {
+		return nil, err
+	}
 	ciConfig.CiPipelines = ciPipelineResp
 	//--------pipeline population end
 	return ciConfig, err
@@ -856,9 +864,47 @@ func (impl *CiPipelineConfigServiceImpl) GetCiPipelineById(pipelineId int) (ciPi
 	}
 	ciPipeline.PreBuildStage = preStageDetail
 	ciPipeline.PostBuildStage = postStageDetail
+	ciPipeline.Schedules, err = impl.ciPipelineScheduleService.GetSchedules(ciPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting schedules by ciPipelineId", "err", err, "ciPipelineId", ciPipeline.Id)
+		return nil, err
+	}
 	return ciPipeline, err
 }
 
+func (impl *CiPipelineConfigServiceImpl) updateCiPipelineSchedules(ciPipelines []*bean.CiPipeline) error {
+	if len(ciPipelines) == 0 {
+		return nil
+	}
+	ciPipelineIds := make([]int, 0, len(ciPipelines))
+	for _, ciPipeline := range ciPipelines {
+		ciPipelineIds = append(ciPipelineIds, ciPipeline.Id)
+	}
+	schedulesByPipeline, err := impl.ciPipelineScheduleService.GetSchedulesByCiPipelineIds(ciPipelineIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting schedules by ciPipelineIds", "err", err, "ciPipelineIds", ciPipelineIds)
+		return err
+	}
+	for _, ciPipeline := range ciPipelines {
+		ciPipeline.Schedules = schedulesByPipeline[ciPipeline.Id]
+	}
+	return nil
+}
+
+// saveCiPipelineSchedules saves the schedules sent with the ci pipeline, schedules are not touched if not sent
+func (impl *CiPipelineConfigServiceImpl) saveCiPipelineSchedules(ciPipeline *bean.CiPipeline, userId int32) error {
+	if ciPipeline == nil || ciPipeline.Schedules == nil || ciPipeline.Id == 0 {
+		return nil
+	}
+	schedules, err := impl.ciPipelineScheduleService.SaveSchedules(ciPipeline.Id, ciPipeline.Schedules, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedules", "ciPipelineId", ciPipeline.Id, "err", err)
+		return err
+	}
+	ciPipeline.Schedules = schedules
+	return nil
+}
+
 func (impl *CiPipelineConfigServiceImpl) GetTriggerViewCiPipeline(appId int) (*bean.TriggerViewCiConfig, error) {
 
 	triggerViewCiConfig := &bean.TriggerViewCiConfig{}
@@ -1352,15 +1398,28 @@ func (impl *CiPipelineConfigServiceImpl) PatchCiPipeline(request *bean.CiPatchRe
 	ciConfig.IsJob = request.IsJob
 	// Check for clone job to not create env override again
 	ciConfig.IsCloneJob = request.IsCloneJob
+	if request.CiPipeline != nil && request.Action != bean.DELETE {
+		err = impl.ciPipelineScheduleService.ValidateScheduleExpressions(request.CiPipeline.Schedules)
+		if err != nil {
+			return nil, err
+		}
+	}
 	switch request.Action {
 	case bean.CREATE:
 		res, err := impl.handlePipelineCreate(request, ciConfig)
 		if err != nil {
 			impl.logger.Errorw("error in creating ci pipeline", "err", err, "request", request, "ciConfig", ciConfig)
+			return res, err
 		}
+		err = impl.saveCiPipelineSchedules(request.CiPipeline, request.UserId)
 		return res, err
 	case bean.UPDATE_SOURCE:
-		return impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		res, err := impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		if err != nil {
+			return res, err
+		}
+		err = impl.saveCiPipelineSchedules(request.CiPipeline, request.UserId)
+		return res, err
 	case bean.DELETE:
 		pipeline, err := impl.DeleteCiPipeline(request)
 		if err != nil {
@@ -2129,6 +2188,11 @@ func (impl *CiPipelineConfigServiceImpl) DeleteCiPipeline(request *bean.CiPatchR
 	if err != nil {
 		return nil, err
 	}
+	err = impl.ciPipelineScheduleService.DeleteSchedules(pipeline.Id, request.UserId)
+	if err != nil {
+		// not failing the request as pipeline is deleted, runs of schedules on deleted pipelines are discarded
+		impl.logger.Errorw("error in deleting schedules of ci pipeline", "ciPipelineId", pipeline.Id, "err", err)
+	}
 	request.CiPipeline.Deleted = true
 	request.CiPipeline.Name = pipeline.Name
 	return request.CiPipeline, nil
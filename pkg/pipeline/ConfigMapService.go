@@ -82,7 +82,9 @@ type ConfigMapService interface {
 	ConfigSecretEnvironmentCreate(createJobEnvOverrideRequest *bean.CreateJobEnvOverridePayload) (*bean.CreateJobEnvOverridePayload, error)
 	ConfigSecretEnvironmentDelete(createJobEnvOverrideRequest *bean.CreateJobEnvOverridePayload) (*bean.CreateJobEnvOverridePayload, error)
 	ConfigSecretEnvironmentGet(appId int) ([]bean.JobEnvOverrideResponse, error)
-	ConfigSecretEnvironmentClone(appId int, cloneAppId int, userId int32) ([]chartConfig.ConfigMapEnvModel, error)
+	// ConfigSecretEnvironmentClone copies the environment level config maps and secrets of the app, environmentIdMapping
+	// maps the environments of the app to the ones of the clone, unmapped environments are kept as they are
+	ConfigSecretEnvironmentClone(appId int, cloneAppId int, environmentIdMapping map[int]int, userId int32) ([]chartConfig.ConfigMapEnvModel, error)
 
 	FetchCmCsNamesAppAndEnvLevel(appId int, envId int) ([]bean.ConfigNameAndType, []bean.ConfigNameAndType, error)
 }
@@ -1930,7 +1932,7 @@ func (impl ConfigMapServiceImpl) ConfigSecretEnvironmentGet(appId int) ([]bean.J
 	return jobEnvOverrideResponse, nil
 }
 
-func (impl ConfigMapServiceImpl) ConfigSecretEnvironmentClone(appId int, cloneAppId int, userId int32) ([]chartConfig.ConfigMapEnvModel, error) {
+func (impl ConfigMapServiceImpl) ConfigSecretEnvironmentClone(appId int, cloneAppId int, environmentIdMapping map[int]int, userId int32) ([]chartConfig.ConfigMapEnvModel, error) {
 	configMap, err := impl.configMapRepository.GetEnvLevelByAppId(appId)
 	if err != nil {
 		impl.logger.Errorw("error while fetching envConfig from db", "error", err)
@@ -1944,9 +1946,13 @@ func (impl ConfigMapServiceImpl) ConfigSecretEnvironmentClone(appId int, cloneAp
 	}
 
 	for _, cm := range configMap {
+		environmentId := cm.EnvironmentId
+		if mappedEnvironmentId, ok := environmentIdMapping[cm.EnvironmentId]; ok {
+			environmentId = mappedEnvironmentId
+		}
 		model := &chartConfig.ConfigMapEnvModel{
 			AppId:         cloneAppId,
-			EnvironmentId: cm.EnvironmentId,
+			EnvironmentId: environmentId,
 			ConfigMapData: cm.ConfigMapData,
 			SecretData:    cm.SecretData,
 			Deleted:       cm.Deleted,
@@ -53,6 +53,8 @@ import (
 	repository6 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	read2 "github.com/devtron-labs/devtron/pkg/cluster/read"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback"
+	"github.com/devtron-labs/devtron/pkg/deployment/canary"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	bean4 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	errors4 "github.com/devtron-labs/devtron/pkg/deployment/common/errors"
@@ -148,6 +150,11 @@ type CdPipelineConfigService interface {
 	RegisterInACD(ctx context.Context, chartGitAttr *commonBean.ChartGitAttribute, userId int32) error
 	// DeleteHelmTypePipelineDeploymentApp : Deletes helm release for a pipeline with force flag
 	DeleteHelmTypePipelineDeploymentApp(ctx context.Context, forceDelete bool, pipeline *pipelineConfig.Pipeline) error
+	// GetPipelineStrategies : Retrieve the deployment strategies of the given cdPipelineId
+	GetPipelineStrategies(pipelineId int) ([]bean.Strategy, error)
+	// UpdatePipelineStrategies : Replaces the deployment strategies of the given cdPipelineId, the strategies not in the list are deleted.
+	// Pipeline strategy history entries are created for the saved strategies.
+	UpdatePipelineStrategies(pipelineId int, strategies []bean.Strategy, userId int32) error
 }
 
 type CdPipelineConfigServiceImpl struct {
@@ -195,6 +202,8 @@ type CdPipelineConfigServiceImpl struct {
 	installedAppReadService           installedAppReader.InstalledAppReadService
 	chartReadService                  read3.ChartReadService
 	helmAppReadService                read4.HelmAppReadService
+	autoRollbackConfigService         autoRollback.AutoRollbackConfigService
+	canaryAnalysisService             canary.CanaryAnalysisService
 }
 
 func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -228,7 +237,9 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 	clusterReadService read2.ClusterReadService,
 	installedAppReadService installedAppReader.InstalledAppReadService,
 	chartReadService read3.ChartReadService,
-	helmAppReadService read4.HelmAppReadService) *CdPipelineConfigServiceImpl {
+	helmAppReadService read4.HelmAppReadService,
+	autoRollbackConfigService autoRollback.AutoRollbackConfigService,
+	canaryAnalysisService canary.CanaryAnalysisService) *CdPipelineConfigServiceImpl {
 	return &CdPipelineConfigServiceImpl{
 		logger:                            logger,
 		pipelineRepository:                pipelineRepository,
@@ -274,6 +285,8 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 		installedAppReadService:           installedAppReadService,
 		chartReadService:                  chartReadService,
 		helmAppReadService:                helmAppReadService,
+		autoRollbackConfigService:         autoRollbackConfigService,
+		canaryAnalysisService:             canaryAnalysisService,
 	}
 }
 
@@ -441,6 +454,16 @@ func (impl *CdPipelineConfigServiceImpl) GetCdPipelineById(pipelineId int) (cdPi
 	}
 	cdPipeline.PreDeployStage = preDeployStage
 	cdPipeline.PostDeployStage = postDeployStage
+	cdPipeline.AutoRollbackConfig, err = impl.autoRollbackConfigService.GetConfig(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting auto rollback config", "err", err, "cdPipelineId", pipelineId)
+		return nil, err
+	}
+	cdPipeline.CanaryAnalysisConfig, err = impl.canaryAnalysisService.GetConfig(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting canary analysis config", "err", err, "cdPipelineId", pipelineId)
+		return nil, err
+	}
 
 	return cdPipeline, err
 }
@@ -455,6 +478,14 @@ func (impl *CdPipelineConfigServiceImpl) CreateCdPipelines(pipelineCreateRequest
 	}
 	envIds := make([]*int, 0)
 	for _, pipeline := range pipelineCreateRequest.Pipelines {
+		err = impl.autoRollbackConfigService.ValidateConfig(pipeline.AutoRollbackConfig)
+		if err != nil {
+			return nil, err
+		}
+		err = impl.canaryAnalysisService.ValidateConfig(pipeline.CanaryAnalysisConfig)
+		if err != nil {
+			return nil, err
+		}
 		// skip creation of pipeline if envId is not set
 		if pipeline.EnvironmentId <= 0 || pipeline.IsLinkedRelease() {
 			continue
@@ -630,11 +661,45 @@ func (impl *CdPipelineConfigServiceImpl) CreateCdPipelines(pipelineCreateRequest
 					return nil, err
 				}
 			}
+			err = impl.saveAutoRollbackConfig(pipeline, pipelineCreateRequest.UserId)
+			if err != nil {
+				return nil, err
+			}
+			err = impl.saveCanaryAnalysisConfig(pipeline, pipelineCreateRequest.UserId)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return pipelineCreateRequest, nil
 }
 
+// saveAutoRollbackConfig saves the auto rollback config sent with the cd pipeline, config is not touched if not sent
+func (impl *CdPipelineConfigServiceImpl) saveAutoRollbackConfig(pipeline *bean.CDPipelineConfigObject, userId int32) error {
+	if pipeline.AutoRollbackConfig == nil || pipeline.Id == 0 {
+		return nil
+	}
+	err := impl.autoRollbackConfigService.SaveConfig(pipeline.Id, pipeline.AutoRollbackConfig, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback config", "cdPipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+// saveCanaryAnalysisConfig saves the canary analysis config sent with the cd pipeline, config is not touched if not sent
+func (impl *CdPipelineConfigServiceImpl) saveCanaryAnalysisConfig(pipeline *bean.CDPipelineConfigObject, userId int32) error {
+	if pipeline.CanaryAnalysisConfig == nil || pipeline.Id == 0 {
+		return nil
+	}
+	err := impl.canaryAnalysisService.SaveConfig(pipeline.Id, pipeline.CanaryAnalysisConfig, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis config", "cdPipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
 func (impl *CdPipelineConfigServiceImpl) parseReleaseConfigForACDApp(app *app2.App, AppDeploymentConfig *bean4.DeploymentConfig, env *repository6.Environment) (*bean4.ReleaseConfiguration, error) {
 
 	envOverride, err := impl.envConfigOverrideService.FindLatestChartForAppByAppIdAndEnvId(app.Id, env.Id)
@@ -1425,6 +1490,16 @@ func (impl *CdPipelineConfigServiceImpl) DeleteCdPipeline(pipeline *pipelineConf
 		impl.logger.Errorw("error in deleting imageDigestPolicy for pipeline", "err", err, "pipelineId", pipeline.Id)
 		return nil, err
 	}
+	err = impl.autoRollbackConfigService.DeleteConfig(pipeline.Id, userId)
+	if err != nil {
+		// not failing the request, watches of pipelines without active config are cancelled
+		impl.logger.Errorw("error in deleting auto rollback config for pipeline", "err", err, "pipelineId", pipeline.Id)
+	}
+	err = impl.canaryAnalysisService.DeleteConfig(pipeline.Id, userId)
+	if err != nil {
+		// not failing the request, canary deployments of deleted pipelines are not analysed
+		impl.logger.Errorw("error in deleting canary analysis config for pipeline", "err", err, "pipelineId", pipeline.Id)
+	}
 	envDeploymentConfig, err := impl.deploymentConfigService.GetAndMigrateConfigIfAbsentForDevtronApps(pipeline.AppId, pipeline.EnvironmentId)
 	if err != nil {
 		impl.logger.Errorw("error in fetching environment deployment config by appId and envId", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
@@ -2583,6 +2658,14 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 		}
 		return err
 	}
+	err = impl.autoRollbackConfigService.ValidateConfig(pipeline.AutoRollbackConfig)
+	if err != nil {
+		return err
+	}
+	err = impl.canaryAnalysisService.ValidateConfig(pipeline.CanaryAnalysisConfig)
+	if err != nil {
+		return err
+	}
 	dbConnection := impl.pipelineRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -2596,15 +2679,44 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 		return err
 	}
 
+	err = impl.savePipelineStrategies(pipeline.Id, pipeline.TriggerType, pipeline.Strategies, userID, tx)
+	if err != nil {
+		return err
+	}
+	// update custom tag data
+	pipeline.Id = dbPipelineObj.Id // pipeline object is request received from FE
+	err = impl.CDPipelineCustomTagDBOperations(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in updating custom tag data for pipeline", "err", err)
+		return err
+	}
+
+	_, err = impl.handleDigestPolicyOperations(tx, pipeline.Id, pipeline.Name, pipeline.IsDigestEnforcedForPipeline, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	err = impl.saveAutoRollbackConfig(pipeline, userID)
+	if err != nil {
+		return err
+	}
+	return impl.saveCanaryAnalysisConfig(pipeline, userID)
+}
+
+func (impl *CdPipelineConfigServiceImpl) savePipelineStrategies(pipelineId int, triggerType pipelineConfig.TriggerType, strategies []bean.Strategy, userId int32, tx *pg.Tx) error {
 	// strategies for pipeline ids, there is only one is default
-	existingStrategies, err := impl.pipelineConfigRepository.GetAllStrategyByPipelineId(pipeline.Id)
+	existingStrategies, err := impl.pipelineConfigRepository.GetAllStrategyByPipelineId(pipelineId)
 	if err != nil && !errors2.IsNotFound(err) {
 		impl.logger.Errorw("error in getting pipeline strategies", "err", err)
 		return err
 	}
 	for _, oldItem := range existingStrategies {
 		notFound := true
-		for _, newItem := range pipeline.Strategies {
+		for _, newItem := range strategies {
 			if newItem.DeploymentTemplate == oldItem.Strategy {
 				notFound = false
 			}
@@ -2612,7 +2724,7 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 
 		if notFound {
 			//delete from db
-			err := impl.pipelineConfigRepository.MarkAsDeleted(oldItem, userID, tx)
+			err := impl.pipelineConfigRepository.MarkAsDeleted(oldItem, userId, tx)
 			if err != nil {
 				impl.logger.Errorw("error in delete pipeline strategies", "err", err)
 				return fmt.Errorf("error in delete pipeline strategies")
@@ -2621,7 +2733,7 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 	}
 
 	defaultCount := 0
-	for _, item := range pipeline.Strategies {
+	for _, item := range strategies {
 		if item.Default {
 			defaultCount = defaultCount + 1
 			if defaultCount > 1 {
@@ -2629,7 +2741,7 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 				continue
 			}
 		}
-		strategy, err := impl.pipelineConfigRepository.FindByStrategyAndPipelineId(item.DeploymentTemplate, pipeline.Id)
+		strategy, err := impl.pipelineConfigRepository.FindByStrategyAndPipelineId(item.DeploymentTemplate, pipelineId)
 		if err != nil && pg.ErrNoRows != err {
 			impl.logger.Errorw("error in getting strategy", "err", err)
 			return err
@@ -2637,7 +2749,7 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 		if strategy.Id > 0 {
 			strategy.Config = string(item.Config)
 			strategy.Default = item.Default
-			strategy.UpdatedBy = userID
+			strategy.UpdatedBy = userId
 			strategy.UpdatedOn = time.Now()
 			err = impl.pipelineConfigRepository.Update(strategy, tx)
 			if err != nil {
@@ -2645,19 +2757,19 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 				return fmt.Errorf("pipeline updated but failed to update one strategy")
 			}
 			//creating history entry for strategy
-			_, err = impl.pipelineStrategyHistoryService.CreatePipelineStrategyHistory(strategy, pipeline.TriggerType, tx)
+			_, err = impl.pipelineStrategyHistoryService.CreatePipelineStrategyHistory(strategy, triggerType, tx)
 			if err != nil {
 				impl.logger.Errorw("error in creating strategy history entry", "err", err)
 				return err
 			}
 		} else {
 			strategy := &chartConfig.PipelineStrategy{
-				PipelineId: pipeline.Id,
+				PipelineId: pipelineId,
 				Strategy:   item.DeploymentTemplate,
 				Config:     string(item.Config),
 				Default:    item.Default,
 				Deleted:    false,
-				AuditLog:   sql.AuditLog{UpdatedBy: userID, CreatedBy: userID, UpdatedOn: time.Now(), CreatedOn: time.Now()},
+				AuditLog:   sql.AuditLog{UpdatedBy: userId, CreatedBy: userId, UpdatedOn: time.Now(), CreatedOn: time.Now()},
 			}
 			err = impl.pipelineConfigRepository.Save(strategy, tx)
 			if err != nil {
@@ -2665,31 +2777,51 @@ func (impl *CdPipelineConfigServiceImpl) updateCdPipeline(ctx context.Context, p
 				return fmt.Errorf("pipeline created but failed to add strategy")
 			}
 			//creating history entry for strategy
-			_, err = impl.pipelineStrategyHistoryService.CreatePipelineStrategyHistory(strategy, pipeline.TriggerType, tx)
+			_, err = impl.pipelineStrategyHistoryService.CreatePipelineStrategyHistory(strategy, triggerType, tx)
 			if err != nil {
 				impl.logger.Errorw("error in creating strategy history entry", "err", err)
 				return err
 			}
 		}
 	}
-	// update custom tag data
-	pipeline.Id = dbPipelineObj.Id // pipeline object is request received from FE
-	err = impl.CDPipelineCustomTagDBOperations(pipeline)
+	return nil
+}
+
+func (impl *CdPipelineConfigServiceImpl) GetPipelineStrategies(pipelineId int) ([]bean.Strategy, error) {
+	dbStrategies, err := impl.pipelineConfigRepository.GetAllStrategyByPipelineId(pipelineId)
+	if err != nil && !errors2.IsNotFound(err) {
+		impl.logger.Errorw("error in getting pipeline strategies", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	strategies := make([]bean.Strategy, 0, len(dbStrategies))
+	for _, item := range dbStrategies {
+		strategies = append(strategies, bean.Strategy{
+			DeploymentTemplate: item.Strategy,
+			Config:             []byte(item.Config),
+			Default:            item.Default,
+		})
+	}
+	return strategies, nil
+}
+
+func (impl *CdPipelineConfigServiceImpl) UpdatePipelineStrategies(pipelineId int, strategies []bean.Strategy, userId int32) error {
+	pipeline, err := impl.pipelineRepository.FindById(pipelineId)
 	if err != nil {
-		impl.logger.Errorw("error in updating custom tag data for pipeline", "err", err)
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", pipelineId, "err", err)
 		return err
 	}
-
-	_, err = impl.handleDigestPolicyOperations(tx, pipeline.Id, pipeline.Name, pipeline.IsDigestEnforcedForPipeline, userID)
+	dbConnection := impl.pipelineRepository.GetConnection()
+	tx, err := dbConnection.Begin()
 	if err != nil {
 		return err
 	}
-
-	err = tx.Commit()
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.savePipelineStrategies(pipeline.Id, pipeline.TriggerType, strategies, userId, tx)
 	if err != nil {
 		return err
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (impl *CdPipelineConfigServiceImpl) handleDigestPolicyOperations(tx *pg.Tx, pipelineId int, pipelineName string, isDigestEnforcedForPipeline bool, userId int32) (resourceQualifierId int, err error) {
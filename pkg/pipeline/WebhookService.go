@@ -54,6 +54,7 @@ type ExternalCiWebhookDto struct {
 	ImageDetailsFromCR            json.RawMessage         `json:"imageDetailsFromCR"`
 	PluginRegistryArtifactDetails map[string][]string     `json:"PluginRegistryArtifactDetails"`
 	PluginArtifactStage           string                  `json:"pluginArtifactStage"`
+	Sbom                          json.RawMessage         `json:"sbom"` // SPDX or CycloneDX json sbom of the image
 }
 
 type CiArtifactWebhookRequest struct {
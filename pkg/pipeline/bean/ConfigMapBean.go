@@ -29,6 +29,7 @@ type ConfigDataRequest struct {
 	EnvironmentId int           `json:"environmentId,omitempty"`
 	ConfigData    []*ConfigData `json:"configData"`
 	Deletable     bool          `json:"isDeletable"`
+	DraftId       int           `json:"draftId,omitempty"` // set if the change is saved as a draft as the environment is config protected
 	UserId        int32         `json:"-"`
 }
 
@@ -44,6 +44,7 @@ type EnvironmentProperties struct {
 	MergeStrategy     models.MergeStrategy        `json:"mergeStrategy"`
 	MigratedFrom      *bean.ExternalReleaseType   `json:"migratedFrom,omitempty"`
 	AppId             int                         `json:"appId"`
+	DraftId           int                         `json:"draftId,omitempty"` // set if the change is saved as a draft as the environment is config protected
 }
 
 type EnvironmentOverrideCreateInternalDTO struct {
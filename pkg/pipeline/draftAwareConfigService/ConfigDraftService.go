@@ -0,0 +1,602 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package draftAwareConfigService
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/config/configDiff"
+	configDiffBean "github.com/devtron-labs/devtron/pkg/config/configDiff/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/adapter"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/helper"
+	draftRepository "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ConfigDraftService interface {
+	// SaveDraft saves the change as a new version of the draft of the config awaiting approval, else as a new draft
+	SaveDraft(request *bean.ConfigDraftRequest) (*bean.ConfigDraftDto, error)
+	GetDraft(draftId int) (*bean.ConfigDraftDto, error)
+	GetDrafts(appId, envId int, states []bean.DraftState) ([]*bean.ConfigDraftDto, error)
+	// GetDraftDiff returns the published config against the config proposed by the latest version of the draft
+	GetDraftDiff(ctx context.Context, draftId int, userId int32, userHasAdminAccess bool) (*bean.ConfigDraftDiffDto, error)
+	AddComment(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftCommentDto, error)
+	// ApproveDraft publishes the latest version of the draft, drafts can not be approved by their authors
+	ApproveDraft(ctx context.Context, request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error)
+	RejectDraft(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error)
+	// DiscardDraft withdraws the draft, only by its authors
+	DiscardDraft(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error)
+
+	// GetConfigProtections returns the config protections of the environments of the app, of all apps if appId is 0
+	GetConfigProtections(appId int) ([]*bean.ConfigProtectionDto, error)
+	SaveConfigProtection(request *bean.ConfigProtectionDto) error
+}
+
+type ConfigDraftServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	draftRepository                draftRepository.DraftRepository
+	resourceProtectionRepository   draftRepository.ResourceProtectionRepository
+	configMapService               pipeline.ConfigMapService
+	propertiesConfigService        pipeline.PropertiesConfigService
+	cdPipelineConfigService        pipeline.CdPipelineConfigService
+	deploymentConfigurationService configDiff.DeploymentConfigurationService
+	appRepository                  app.AppRepository
+	environmentRepository          repository.EnvironmentRepository
+}
+
+func NewConfigDraftServiceImpl(logger *zap.SugaredLogger,
+	draftRepository draftRepository.DraftRepository,
+	resourceProtectionRepository draftRepository.ResourceProtectionRepository,
+	configMapService pipeline.ConfigMapService,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	cdPipelineConfigService pipeline.CdPipelineConfigService,
+	deploymentConfigurationService configDiff.DeploymentConfigurationService,
+	appRepository app.AppRepository,
+	environmentRepository repository.EnvironmentRepository) *ConfigDraftServiceImpl {
+	return &ConfigDraftServiceImpl{
+		logger:                         logger,
+		draftRepository:                draftRepository,
+		resourceProtectionRepository:   resourceProtectionRepository,
+		configMapService:               configMapService,
+		propertiesConfigService:        propertiesConfigService,
+		cdPipelineConfigService:        cdPipelineConfigService,
+		deploymentConfigurationService: deploymentConfigurationService,
+		appRepository:                  appRepository,
+		environmentRepository:          environmentRepository,
+	}
+}
+
+func (impl *ConfigDraftServiceImpl) SaveDraft(request *bean.ConfigDraftRequest) (*bean.ConfigDraftDto, error) {
+	resource, ok := adapter.GetDraftResourceType(request.ResourceType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported draft resource type %s", request.ResourceType)
+	}
+	draft, err := impl.draftRepository.FindAwaitingApprovalDraft(request.AppId, request.EnvironmentId, resource, request.ResourceName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching draft awaiting approval", "request", request, "err", err)
+		return nil, err
+	}
+	version := &draftRepository.DraftVersion{
+		Action:    request.Action,
+		Data:      string(request.Data),
+		UserId:    request.UserId,
+		CreatedOn: time.Now(),
+	}
+	versionNumber := 1
+	if util.IsErrNoRows(err) {
+		draft = &draftRepository.Draft{
+			AppId:        request.AppId,
+			EnvId:        request.EnvironmentId,
+			Resource:     resource,
+			ResourceName: request.ResourceName,
+			DraftState:   bean.DraftStateAwaitingApproval,
+			AuditLog:     sql.NewDefaultAuditLog(request.UserId),
+		}
+		err = impl.draftRepository.SaveDraft(draft, version)
+		if err != nil {
+			impl.logger.Errorw("error in saving draft", "request", request, "err", err)
+			return nil, err
+		}
+	} else {
+		versions, err := impl.draftRepository.FindVersionsByDraftId(draft.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching draft versions", "draftId", draft.Id, "err", err)
+			return nil, err
+		}
+		version.DraftId = draft.Id
+		versionNumber = len(versions) + 1
+		err = impl.draftRepository.SaveVersion(version)
+		if err != nil {
+			impl.logger.Errorw("error in saving draft version", "draftId", draft.Id, "err", err)
+			return nil, err
+		}
+	}
+	draftDto := adapter.GetConfigDraftDto(draft)
+	draftDto.LatestVersion = adapter.GetConfigDraftVersionDto(version, versionNumber)
+	return draftDto, nil
+}
+
+func (impl *ConfigDraftServiceImpl) GetDraft(draftId int) (*bean.ConfigDraftDto, error) {
+	draft, err := impl.draftRepository.FindById(draftId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "draft not found", "draft not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching draft", "draftId", draftId, "err", err)
+		return nil, err
+	}
+	versions, err := impl.draftRepository.FindVersionsByDraftId(draftId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching draft versions", "draftId", draftId, "err", err)
+		return nil, err
+	}
+	comments, err := impl.draftRepository.FindActiveCommentsByDraftId(draftId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching draft comments", "draftId", draftId, "err", err)
+		return nil, err
+	}
+	draftDto := adapter.GetConfigDraftDto(draft)
+	draftDto.Versions = make([]*bean.ConfigDraftVersionDto, 0, len(versions))
+	for index, version := range versions {
+		draftDto.Versions = append(draftDto.Versions, adapter.GetConfigDraftVersionDto(version, index+1))
+	}
+	if len(draftDto.Versions) > 0 {
+		draftDto.LatestVersion = draftDto.Versions[len(draftDto.Versions)-1]
+	}
+	draftDto.Comments = make([]*bean.ConfigDraftCommentDto, 0, len(comments))
+	for _, comment := range comments {
+		draftDto.Comments = append(draftDto.Comments, adapter.GetConfigDraftCommentDto(comment))
+	}
+	if draft.DraftState == bean.DraftStateRejected && len(draftDto.Comments) > 0 {
+		// the rejection reason is saved as the last comment of the draft by the reviewer
+		draftDto.RejectionReason = draftDto.Comments[len(draftDto.Comments)-1].Comment
+	}
+	return draftDto, nil
+}
+
+func (impl *ConfigDraftServiceImpl) GetDrafts(appId, envId int, states []bean.DraftState) ([]*bean.ConfigDraftDto, error) {
+	drafts, err := impl.draftRepository.FindDrafts(appId, envId, states)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching drafts", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	draftIds := make([]int, 0, len(drafts))
+	for _, draft := range drafts {
+		draftIds = append(draftIds, draft.Id)
+	}
+	versions, err := impl.draftRepository.FindVersionsByDraftIds(draftIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching draft versions", "draftIds", draftIds, "err", err)
+		return nil, err
+	}
+	return adapter.GetConfigDraftDtos(drafts, versions), nil
+}
+
+func (impl *ConfigDraftServiceImpl) GetDraftDiff(ctx context.Context, draftId int, userId int32, userHasAdminAccess bool) (*bean.ConfigDraftDiffDto, error) {
+	draft, err := impl.GetDraft(draftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.LatestVersion == nil {
+		return nil, util.NewApiError(http.StatusNotFound, "draft version not found", "draft version not found")
+	}
+	diff := &bean.ConfigDraftDiffDto{
+		DraftId:        draft.Id,
+		DraftVersionId: draft.LatestVersion.Id,
+		ResourceType:   draft.ResourceType,
+		ResourceName:   draft.ResourceName,
+		Action:         draft.LatestVersion.Action,
+	}
+	switch draft.ResourceType {
+	case pipelineBean.CM:
+		published, err := impl.configMapService.CMEnvironmentFetch(draft.AppId, draft.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching config maps of environment", "appId", draft.AppId, "envId", draft.EnvironmentId, "err", err)
+			return nil, err
+		}
+		diff.Published, diff.Draft, err = getConfigDataDiff(published.ConfigData, draft)
+		if err != nil {
+			impl.logger.Errorw("error in getting config map diff of draft", "draftId", draftId, "err", err)
+			return nil, err
+		}
+	case pipelineBean.CS:
+		// secrets are compared by the config diff service so that they are masked for non admins
+		diff.SecretComparison, err = impl.getSecretComparison(ctx, draft, userId, userHasAdminAccess)
+		if err != nil {
+			return nil, err
+		}
+	case pipelineBean.DeploymentTemplate:
+		diff.Published, diff.Draft, err = impl.getDeploymentTemplateDiff(draft)
+		if err != nil {
+			return nil, err
+		}
+	case pipelineBean.PipelineStrategy:
+		diff.Published, diff.Draft, err = impl.getPipelineStrategyDiff(draft)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diff, nil
+}
+
+func (impl *ConfigDraftServiceImpl) getSecretComparison(ctx context.Context, draft *bean.ConfigDraftDto, userId int32, userHasAdminAccess bool) (*configDiffBean.ComparisonResponseDto, error) {
+	appModel, err := impl.appRepository.FindById(draft.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app", "appId", draft.AppId, "err", err)
+		return nil, err
+	}
+	env, err := impl.environmentRepository.FindById(draft.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", draft.EnvironmentId, "err", err)
+		return nil, err
+	}
+	comparisonRequest := configDiffBean.ComparisonRequestDto{
+		ComparisonItems: []*configDiffBean.ComparisonItemRequestDto{
+			{Index: 0, ConfigDataQueryParams: &configDiffBean.ConfigDataQueryParams{AppName: appModel.AppName, EnvName: env.Name,
+				ConfigType: configDiffBean.PublishedConfigState.ToString(), ConfigArea: configDiffBean.AppConfiguration.ToString()}},
+			{Index: 1, ConfigDataQueryParams: &configDiffBean.ConfigDataQueryParams{AppName: appModel.AppName, EnvName: env.Name,
+				ConfigType: configDiffBean.PublishedWithDraft.ToString(), ConfigArea: configDiffBean.AppConfiguration.ToString()}},
+		},
+	}
+	comparisonRequest.UpdateUserIdInComparisonItems(userId)
+	comparison, err := impl.deploymentConfigurationService.CompareCategoryWiseConfigData(ctx, comparisonRequest, userHasAdminAccess)
+	if err != nil {
+		impl.logger.Errorw("error in comparing secrets of draft", "draftId", draft.Id, "err", err)
+		return nil, err
+	}
+	return comparison, nil
+}
+
+func (impl *ConfigDraftServiceImpl) getDeploymentTemplateDiff(draft *bean.ConfigDraftDto) (json.RawMessage, json.RawMessage, error) {
+	properties := &pipelineBean.EnvironmentProperties{}
+	err := json.Unmarshal(draft.LatestVersion.Data, properties)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling deployment template draft", "draftId", draft.Id, "err", err)
+		return nil, nil, err
+	}
+	chartRefId := properties.ChartRefId
+	if chartRefId == 0 && properties.Id > 0 {
+		envConfigOverride, err := impl.propertiesConfigService.GetAppIdByChartEnvId(properties.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching env config override", "chartEnvConfigOverrideId", properties.Id, "err", err)
+			return nil, nil, err
+		}
+		chartRefId = envConfigOverride.Chart.ChartRefId
+	}
+	published, err := impl.propertiesConfigService.GetEnvironmentProperties(draft.AppId, draft.EnvironmentId, chartRefId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env deployment template", "appId", draft.AppId, "envId", draft.EnvironmentId, "err", err)
+		return nil, nil, err
+	}
+	publishedValues := published.GlobalConfig
+	if published.IsOverride {
+		publishedValues = published.EnvironmentConfig.EnvOverrideValues
+	}
+	draftValues := properties.EnvOverrideValues
+	if draft.LatestVersion.Action == bean.DraftActionDelete {
+		// resetting the override inherits the base deployment template
+		draftValues = published.GlobalConfig
+	}
+	return publishedValues, draftValues, nil
+}
+
+func (impl *ConfigDraftServiceImpl) getPipelineStrategyDiff(draft *bean.ConfigDraftDto) (json.RawMessage, json.RawMessage, error) {
+	strategyDraft := &bean.PipelineStrategyDraft{}
+	err := json.Unmarshal(draft.LatestVersion.Data, strategyDraft)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling pipeline strategy draft", "draftId", draft.Id, "err", err)
+		return nil, nil, err
+	}
+	published, err := impl.cdPipelineConfigService.GetPipelineStrategies(strategyDraft.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline strategies", "pipelineId", strategyDraft.PipelineId, "err", err)
+		return nil, nil, err
+	}
+	publishedStrategies, err := json.Marshal(published)
+	if err != nil {
+		return nil, nil, err
+	}
+	draftStrategies, err := json.Marshal(strategyDraft.Strategies)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publishedStrategies, draftStrategies, nil
+}
+
+func (impl *ConfigDraftServiceImpl) AddComment(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftCommentDto, error) {
+	if len(strings.TrimSpace(request.Comment)) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "comment is required", "comment is required")
+	}
+	draft, err := impl.GetDraft(request.DraftId)
+	if err != nil {
+		return nil, err
+	}
+	if !hasVersion(draft, request.DraftVersionId) {
+		return nil, util.NewApiError(http.StatusBadRequest, "version not found in the draft", "version not found in the draft")
+	}
+	comment := &draftRepository.DraftVersionComment{
+		DraftId:        draft.Id,
+		DraftVersionId: request.DraftVersionId,
+		Comment:        request.Comment,
+		Active:         true,
+		AuditLog:       sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.draftRepository.SaveComment(comment)
+	if err != nil {
+		impl.logger.Errorw("error in saving draft comment", "draftId", draft.Id, "err", err)
+		return nil, err
+	}
+	return adapter.GetConfigDraftCommentDto(comment), nil
+}
+
+func (impl *ConfigDraftServiceImpl) ApproveDraft(ctx context.Context, request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error) {
+	draft, err := impl.getDraftForReview(request)
+	if err != nil {
+		return nil, err
+	}
+	if isDraftAuthor(draft, request.UserId) {
+		errMsg := "drafts can not be approved by their authors"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	claimed, err := impl.draftRepository.UpdateDraftState(draft.Id, bean.DraftStateAwaitingApproval, bean.DraftStatePublished, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in updating draft state", "draftId", draft.Id, "err", err)
+		return nil, err
+	} else if !claimed {
+		return nil, util.NewApiError(http.StatusConflict, "draft is already reviewed", "draft is already reviewed")
+	}
+	err = impl.publishDraft(ctx, draft)
+	if err != nil {
+		impl.logger.Errorw("error in publishing draft", "draftId", draft.Id, "err", err)
+		_, revertErr := impl.draftRepository.UpdateDraftState(draft.Id, bean.DraftStatePublished, bean.DraftStateAwaitingApproval, request.UserId)
+		if revertErr != nil {
+			impl.logger.Errorw("error in reverting draft state", "draftId", draft.Id, "err", revertErr)
+		}
+		return nil, err
+	}
+	return impl.reviewDraft(draft.Id, bean.DraftStatePublished, request)
+}
+
+func (impl *ConfigDraftServiceImpl) RejectDraft(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error) {
+	if len(strings.TrimSpace(request.Comment)) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "rejection reason is required", "rejection reason is required")
+	}
+	draft, err := impl.getDraftForReview(request)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := impl.draftRepository.UpdateDraftState(draft.Id, bean.DraftStateAwaitingApproval, bean.DraftStateRejected, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in updating draft state", "draftId", draft.Id, "err", err)
+		return nil, err
+	} else if !claimed {
+		return nil, util.NewApiError(http.StatusConflict, "draft is already reviewed", "draft is already reviewed")
+	}
+	return impl.reviewDraft(draft.Id, bean.DraftStateRejected, request)
+}
+
+func (impl *ConfigDraftServiceImpl) DiscardDraft(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error) {
+	draft, err := impl.getDraftForReview(request)
+	if err != nil {
+		return nil, err
+	}
+	if !isDraftAuthor(draft, request.UserId) {
+		errMsg := "drafts can only be discarded by their authors"
+		return nil, util.NewApiError(http.StatusForbidden, errMsg, errMsg)
+	}
+	claimed, err := impl.draftRepository.UpdateDraftState(draft.Id, bean.DraftStateAwaitingApproval, bean.DraftStateDiscarded, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in updating draft state", "draftId", draft.Id, "err", err)
+		return nil, err
+	} else if !claimed {
+		return nil, util.NewApiError(http.StatusConflict, "draft is already reviewed", "draft is already reviewed")
+	}
+	if len(request.Comment) > 0 {
+		_, err = impl.AddComment(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetDraft(draft.Id)
+}
+
+// getDraftForReview returns the draft if it is awaiting approval and DraftVersionId is its latest version
+func (impl *ConfigDraftServiceImpl) getDraftForReview(request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error) {
+	draft, err := impl.GetDraft(request.DraftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.State != bean.DraftStateAwaitingApproval {
+		errMsg := fmt.Sprintf("draft is %s", draft.State)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if draft.LatestVersion == nil || draft.LatestVersion.Id != request.DraftVersionId {
+		errMsg := "draft is updated since, review the latest version"
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return draft, nil
+}
+
+// reviewDraft saves the comment of the reviewer on the reviewed version, the comment of a rejection is the rejection reason
+func (impl *ConfigDraftServiceImpl) reviewDraft(draftId int, state bean.DraftState, request *bean.ConfigDraftActionRequest) (*bean.ConfigDraftDto, error) {
+	if len(request.Comment) > 0 {
+		_, err := impl.AddComment(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetDraft(draftId)
+}
+
+// publishDraft applies the latest version of the draft on behalf of its author
+func (impl *ConfigDraftServiceImpl) publishDraft(ctx context.Context, draft *bean.ConfigDraftDto) error {
+	version := draft.LatestVersion
+	userId := version.CreatedBy
+	switch draft.ResourceType {
+	case pipelineBean.CM, pipelineBean.CS:
+		if version.Action == bean.DraftActionDelete {
+			deleteDraft := &bean.ConfigDataDeleteDraft{}
+			err := json.Unmarshal(version.Data, deleteDraft)
+			if err != nil {
+				return err
+			}
+			if draft.ResourceType == pipelineBean.CM {
+				_, err = impl.configMapService.CMEnvironmentDelete(deleteDraft.Name, deleteDraft.Request.Id, userId)
+			} else {
+				_, err = impl.configMapService.CSEnvironmentDelete(deleteDraft.Name, deleteDraft.Request.Id, userId)
+			}
+			return err
+		}
+		request := &pipelineBean.ConfigDataRequest{}
+		err := json.Unmarshal(version.Data, request)
+		if err != nil {
+			return err
+		}
+		request.UserId = userId
+		if draft.ResourceType == pipelineBean.CM {
+			_, err = impl.configMapService.CMEnvironmentAddUpdate(request)
+		} else {
+			_, err = impl.configMapService.CSEnvironmentAddUpdate(request)
+		}
+		return err
+	case pipelineBean.DeploymentTemplate:
+		properties := &pipelineBean.EnvironmentProperties{}
+		err := json.Unmarshal(version.Data, properties)
+		if err != nil {
+			return err
+		}
+		properties.UserId = userId
+		switch version.Action {
+		case bean.DraftActionCreate:
+			_, err = impl.propertiesConfigService.CreateEnvironmentPropertiesAndBaseIfNeeded(ctx, properties.AppId, properties)
+		case bean.DraftActionDelete:
+			_, err = impl.propertiesConfigService.ResetEnvironmentProperties(properties.Id, userId)
+		default:
+			_, err = impl.propertiesConfigService.UpdateEnvironmentProperties(properties.AppId, properties, userId)
+		}
+		return err
+	case pipelineBean.PipelineStrategy:
+		strategyDraft := &bean.PipelineStrategyDraft{}
+		err := json.Unmarshal(version.Data, strategyDraft)
+		if err != nil {
+			return err
+		}
+		return impl.cdPipelineConfigService.UpdatePipelineStrategies(strategyDraft.PipelineId, strategyDraft.Strategies, userId)
+	}
+	return fmt.Errorf("unsupported draft resource type %s", draft.ResourceType)
+}
+
+func (impl *ConfigDraftServiceImpl) GetConfigProtections(appId int) ([]*bean.ConfigProtectionDto, error) {
+	protections, err := impl.resourceProtectionRepository.FindByAppId(appId, bean.ProtectionResourceTypeConfig)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching config protections", "appId", appId, "err", err)
+		return nil, err
+	}
+	protectionDtos := make([]*bean.ConfigProtectionDto, 0, len(protections))
+	for _, protection := range protections {
+		protectionDtos = append(protectionDtos, adapter.GetConfigProtectionDto(protection))
+	}
+	return protectionDtos, nil
+}
+
+// SaveConfigProtection enables or disables the config protection of the app in the environment, drafts awaiting
+// approval can still be reviewed once the protection is disabled
+func (impl *ConfigDraftServiceImpl) SaveConfigProtection(request *bean.ConfigProtectionDto) error {
+	protection, err := impl.resourceProtectionRepository.FindByAppIdAndEnvId(request.AppId, request.EnvironmentId, bean.ProtectionResourceTypeConfig)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching config protection", "appId", request.AppId, "envId", request.EnvironmentId, "err", err)
+		return err
+	}
+	protectionState := bean.ProtectionStateDisabled
+	if request.Enabled {
+		protectionState = bean.ProtectionStateEnabled
+	}
+	if util.IsErrNoRows(err) {
+		protection = &draftRepository.ResourceProtection{
+			AppId:    request.AppId,
+			EnvId:    request.EnvironmentId,
+			Resource: bean.ProtectionResourceTypeConfig,
+			AuditLog: sql.NewDefaultAuditLog(request.UserId),
+		}
+	} else {
+		protection.UpdateAuditLog(request.UserId)
+	}
+	protection.ProtectionState = protectionState
+	err = impl.resourceProtectionRepository.Save(protection)
+	if err != nil {
+		impl.logger.Errorw("error in saving config protection", "appId", request.AppId, "envId", request.EnvironmentId, "err", err)
+		return err
+	}
+	return nil
+}
+
+// getConfigDataDiff returns the published config map of the draft and the config map as it would be once the draft is published
+func getConfigDataDiff(published []*pipelineBean.ConfigData, draft *bean.ConfigDraftDto) (json.RawMessage, json.RawMessage, error) {
+	withDraft, err := helper.ApplyConfigDataDrafts(published, []*bean.ConfigDraftDto{draft})
+	if err != nil {
+		return nil, nil, err
+	}
+	publishedData, err := marshalConfigData(published, draft.ResourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	draftData, err := marshalConfigData(withDraft, draft.ResourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publishedData, draftData, nil
+}
+
+func marshalConfigData(configData []*pipelineBean.ConfigData, name string) (json.RawMessage, error) {
+	for _, item := range configData {
+		if item.Name == name {
+			return json.Marshal(item)
+		}
+	}
+	return nil, nil
+}
+
+func isDraftAuthor(draft *bean.ConfigDraftDto, userId int32) bool {
+	if draft.CreatedBy == userId {
+		return true
+	}
+	for _, version := range draft.Versions {
+		if version.CreatedBy == userId {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVersion(draft *bean.ConfigDraftDto, draftVersionId int) bool {
+	for _, version := range draft.Versions {
+		if version.Id == draftVersionId {
+			return true
+		}
+	}
+	return false
+}
@@ -2,12 +2,18 @@ package draftAwareConfigService
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/util"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	pkgBean "github.com/devtron-labs/devtron/pkg/bean"
 	chartService "github.com/devtron-labs/devtron/pkg/chart"
 	bean3 "github.com/devtron-labs/devtron/pkg/chart/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	draftBean "github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/read"
 	"go.uber.org/zap"
+	"net/http"
 )
 
 type DraftAwareConfigMapService interface {
@@ -47,28 +53,47 @@ type DraftAwareDeploymentTemplateService interface {
 	CreateEnvironmentPropertiesAndBaseIfNeeded(ctx context.Context, environmentProperties *bean.EnvironmentProperties, userMetadata *userBean.UserMetadata) (*bean.EnvironmentProperties, error)
 }
 
+type DraftAwarePipelineStrategyService interface {
+	// UpdatePipelineStrategies replaces the deployment strategies of the cd pipeline, the change is saved as a draft
+	// if the config of the environment is protected, savedAsDraft is set then
+	UpdatePipelineStrategies(ctx context.Context, appId, envId, pipelineId int, strategies []pkgBean.Strategy, userId int32) (savedAsDraft bool, err error)
+}
+
 type DraftAwareConfigService interface {
 	DraftAwareConfigMapService
 	DraftAwareSecretService
 	DraftAwareDeploymentTemplateService
+	DraftAwarePipelineStrategyService
 }
+
+// DraftAwareConfigServiceImpl saves the env level config changes of config protected environments as drafts,
+// which are published only once approved. Base configurations are not protected.
 type DraftAwareConfigServiceImpl struct {
 	logger                  *zap.SugaredLogger
 	configMapService        pipeline.ConfigMapService
 	chartService            chartService.ChartService
 	propertiesConfigService pipeline.PropertiesConfigService
+	cdPipelineConfigService pipeline.CdPipelineConfigService
+	configDraftService      ConfigDraftService
+	configDraftReadService  read.ConfigDraftReadService
 }
 
 func NewDraftAwareResourceServiceImpl(logger *zap.SugaredLogger,
 	configMapService pipeline.ConfigMapService,
 	chartService chartService.ChartService,
 	propertiesConfigService pipeline.PropertiesConfigService,
+	cdPipelineConfigService pipeline.CdPipelineConfigService,
+	configDraftService ConfigDraftService,
+	configDraftReadService read.ConfigDraftReadService,
 ) *DraftAwareConfigServiceImpl {
 	return &DraftAwareConfigServiceImpl{
 		logger:                  logger,
 		configMapService:        configMapService,
 		chartService:            chartService,
 		propertiesConfigService: propertiesConfigService,
+		cdPipelineConfigService: cdPipelineConfigService,
+		configDraftService:      configDraftService,
+		configDraftReadService:  configDraftReadService,
 	}
 }
 
@@ -83,6 +108,13 @@ func (impl *DraftAwareConfigServiceImpl) CMGlobalAddUpdate(ctx context.Context,
 }
 
 func (impl *DraftAwareConfigServiceImpl) CMEnvironmentAddUpdate(ctx context.Context, configMapRequest *bean.ConfigDataRequest, userMetadata *userBean.UserMetadata) (*bean.ConfigDataRequest, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(configMapRequest.AppId, configMapRequest.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId, "err", err)
+		return nil, err
+	} else if isProtected {
+		return impl.saveConfigDataDraft(bean.CM, configMapRequest)
+	}
 	resp, err := impl.configMapService.CMEnvironmentAddUpdate(configMapRequest)
 	if err != nil {
 		impl.logger.Errorw("error in CMEnvironmentAddUpdate", "configMapRequest", configMapRequest, "err", err)
@@ -103,6 +135,13 @@ func (impl *DraftAwareConfigServiceImpl) CSGlobalAddUpdate(ctx context.Context,
 }
 
 func (impl *DraftAwareConfigServiceImpl) CSEnvironmentAddUpdate(ctx context.Context, configMapRequest *bean.ConfigDataRequest, userMetadata *userBean.UserMetadata) (*bean.ConfigDataRequest, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(configMapRequest.AppId, configMapRequest.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId, "err", err)
+		return nil, err
+	} else if isProtected {
+		return impl.saveConfigDataDraft(bean.CS, configMapRequest)
+	}
 	resp, err := impl.configMapService.CSEnvironmentAddUpdate(configMapRequest)
 	if err != nil {
 		impl.logger.Errorw("error in CSGlobalAddUpdate", "err", err)
@@ -124,6 +163,13 @@ func (impl *DraftAwareConfigServiceImpl) CMGlobalDelete(ctx context.Context, nam
 }
 
 func (impl *DraftAwareConfigServiceImpl) CMEnvironmentDelete(ctx context.Context, name string, deleteReq *bean.ConfigDataRequest, userMetadata *userBean.UserMetadata) (bool, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(deleteReq.AppId, deleteReq.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", deleteReq.AppId, "envId", deleteReq.EnvironmentId, "err", err)
+		return false, err
+	} else if isProtected {
+		return impl.saveConfigDataDeleteDraft(bean.CM, name, deleteReq)
+	}
 	resp, err := impl.configMapService.CMEnvironmentDelete(name, deleteReq.Id, deleteReq.UserId)
 	if err != nil {
 		impl.logger.Errorw("service err, CMEnvironmentDelete", "appId", deleteReq.AppId, "envId", deleteReq.EnvironmentId, "id", deleteReq.Id, "err", err)
@@ -144,6 +190,13 @@ func (impl *DraftAwareConfigServiceImpl) CSGlobalDelete(ctx context.Context, nam
 }
 
 func (impl *DraftAwareConfigServiceImpl) CSEnvironmentDelete(ctx context.Context, name string, deleteReq *bean.ConfigDataRequest, userMetadata *userBean.UserMetadata) (bool, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(deleteReq.AppId, deleteReq.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", deleteReq.AppId, "envId", deleteReq.EnvironmentId, "err", err)
+		return false, err
+	} else if isProtected {
+		return impl.saveConfigDataDeleteDraft(bean.CS, name, deleteReq)
+	}
 	resp, err := impl.configMapService.CSEnvironmentDelete(name, deleteReq.Id, deleteReq.UserId)
 	if err != nil {
 		impl.logger.Errorw("service err, CSEnvironmentDelete", "appId", deleteReq.AppId, "id", deleteReq.Id, "name", name, "err", err)
@@ -174,6 +227,12 @@ func (impl *DraftAwareConfigServiceImpl) UpdateAppOverride(ctx context.Context,
 }
 
 func (impl *DraftAwareConfigServiceImpl) UpdateEnvironmentProperties(ctx context.Context, propertiesRequest *bean.EnvironmentProperties, token string, userMetadata *userBean.UserMetadata) (*bean.EnvironmentProperties, error) {
+	isProtected, err := impl.isDeploymentTemplateProtected(propertiesRequest)
+	if err != nil {
+		return nil, err
+	} else if isProtected {
+		return impl.saveDeploymentTemplateDraft(draftBean.DraftActionUpdate, propertiesRequest)
+	}
 	resp, err := impl.propertiesConfigService.UpdateEnvironmentProperties(propertiesRequest.AppId, propertiesRequest, propertiesRequest.UserId)
 	if err != nil {
 		impl.logger.Errorw("error in creating/updating env level deployment template", "appId", propertiesRequest.AppId, "envId", propertiesRequest.EnvironmentId, "err", err)
@@ -184,6 +243,16 @@ func (impl *DraftAwareConfigServiceImpl) UpdateEnvironmentProperties(ctx context
 }
 
 func (impl *DraftAwareConfigServiceImpl) ResetEnvironmentProperties(ctx context.Context, propertiesRequest *bean.EnvironmentProperties, userMetadata *userBean.UserMetadata) (bool, error) {
+	isProtected, err := impl.isDeploymentTemplateProtected(propertiesRequest)
+	if err != nil {
+		return false, err
+	} else if isProtected {
+		_, err = impl.saveDeploymentTemplateDraft(draftBean.DraftActionDelete, propertiesRequest)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 	isSuccess, err := impl.propertiesConfigService.ResetEnvironmentProperties(propertiesRequest.Id, propertiesRequest.UserId)
 	if err != nil {
 		impl.logger.Errorw("service err, ResetEnvironmentProperties", "chartEnvConfigOverrideId", propertiesRequest.Id, "userId", propertiesRequest.UserId, "err", err)
@@ -194,6 +263,13 @@ func (impl *DraftAwareConfigServiceImpl) ResetEnvironmentProperties(ctx context.
 }
 
 func (impl *DraftAwareConfigServiceImpl) CreateEnvironmentPropertiesAndBaseIfNeeded(ctx context.Context, environmentProperties *bean.EnvironmentProperties, userMetadata *userBean.UserMetadata) (*bean.EnvironmentProperties, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(environmentProperties.AppId, environmentProperties.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", environmentProperties.AppId, "envId", environmentProperties.EnvironmentId, "err", err)
+		return nil, err
+	} else if isProtected {
+		return impl.saveDeploymentTemplateDraft(draftBean.DraftActionCreate, environmentProperties)
+	}
 	resp, err := impl.propertiesConfigService.CreateEnvironmentPropertiesAndBaseIfNeeded(ctx, environmentProperties.AppId, environmentProperties)
 	if err != nil {
 		impl.logger.Errorw("error, CreateEnvironmentPropertiesAndBaseIfNeeded", "appId", environmentProperties.AppId, "req", environmentProperties, "err", err)
@@ -202,3 +278,131 @@ func (impl *DraftAwareConfigServiceImpl) CreateEnvironmentPropertiesAndBaseIfNee
 
 	return resp, nil
 }
+
+func (impl *DraftAwareConfigServiceImpl) saveConfigDataDraft(resourceType bean.ResourceType, request *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error) {
+	if len(request.ConfigData) != 1 {
+		errMsg := "config protected environments accept one config at a time"
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := impl.configDraftService.SaveDraft(&draftBean.ConfigDraftRequest{
+		AppId:         request.AppId,
+		EnvironmentId: request.EnvironmentId,
+		ResourceType:  resourceType,
+		ResourceName:  request.ConfigData[0].Name,
+		Action:        draftBean.DraftActionUpdate,
+		Data:          data,
+		UserId:        request.UserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving config draft", "resourceType", resourceType, "appId", request.AppId, "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	request.DraftId = draft.Id
+	return request, nil
+}
+
+func (impl *DraftAwareConfigServiceImpl) saveConfigDataDeleteDraft(resourceType bean.ResourceType, name string, deleteReq *bean.ConfigDataRequest) (bool, error) {
+	data, err := json.Marshal(&draftBean.ConfigDataDeleteDraft{Name: name, Request: deleteReq})
+	if err != nil {
+		return false, err
+	}
+	_, err = impl.configDraftService.SaveDraft(&draftBean.ConfigDraftRequest{
+		AppId:         deleteReq.AppId,
+		EnvironmentId: deleteReq.EnvironmentId,
+		ResourceType:  resourceType,
+		ResourceName:  name,
+		Action:        draftBean.DraftActionDelete,
+		Data:          data,
+		UserId:        deleteReq.UserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving config delete draft", "resourceType", resourceType, "appId", deleteReq.AppId, "envId", deleteReq.EnvironmentId, "name", name, "err", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// isDeploymentTemplateProtected resolves the app and environment of the env override when missing in the request,
+// as the update request only carries the id of the env override
+func (impl *DraftAwareConfigServiceImpl) isDeploymentTemplateProtected(propertiesRequest *bean.EnvironmentProperties) (bool, error) {
+	if propertiesRequest.EnvironmentId == 0 || propertiesRequest.AppId == 0 {
+		envConfigOverride, err := impl.propertiesConfigService.GetAppIdByChartEnvId(propertiesRequest.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching env config override", "chartEnvConfigOverrideId", propertiesRequest.Id, "err", err)
+			return false, err
+		}
+		propertiesRequest.EnvironmentId = envConfigOverride.TargetEnvironment
+		if propertiesRequest.AppId == 0 && envConfigOverride.Chart != nil {
+			propertiesRequest.AppId = envConfigOverride.Chart.AppId
+		}
+	}
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(propertiesRequest.AppId, propertiesRequest.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", propertiesRequest.AppId, "envId", propertiesRequest.EnvironmentId, "err", err)
+		return false, err
+	}
+	return isProtected, nil
+}
+
+func (impl *DraftAwareConfigServiceImpl) saveDeploymentTemplateDraft(action draftBean.DraftAction, propertiesRequest *bean.EnvironmentProperties) (*bean.EnvironmentProperties, error) {
+	data, err := json.Marshal(propertiesRequest)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := impl.configDraftService.SaveDraft(&draftBean.ConfigDraftRequest{
+		AppId:         propertiesRequest.AppId,
+		EnvironmentId: propertiesRequest.EnvironmentId,
+		ResourceType:  bean.DeploymentTemplate,
+		ResourceName:  draftBean.DeploymentTemplateResourceName,
+		Action:        action,
+		Data:          data,
+		UserId:        propertiesRequest.UserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment template draft", "appId", propertiesRequest.AppId, "envId", propertiesRequest.EnvironmentId, "err", err)
+		return nil, err
+	}
+	propertiesRequest.DraftId = draft.Id
+	return propertiesRequest, nil
+}
+
+func (impl *DraftAwareConfigServiceImpl) UpdatePipelineStrategies(ctx context.Context, appId, envId, pipelineId int, strategies []pkgBean.Strategy, userId int32) (bool, error) {
+	isProtected, err := impl.configDraftReadService.IsConfigProtected(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("error in checking config protection", "appId", appId, "envId", envId, "err", err)
+		return false, err
+	} else if isProtected {
+		return true, impl.savePipelineStrategyDraft(appId, envId, pipelineId, strategies, userId)
+	}
+	err = impl.cdPipelineConfigService.UpdatePipelineStrategies(pipelineId, strategies, userId)
+	if err != nil {
+		impl.logger.Errorw("error in UpdatePipelineStrategies", "pipelineId", pipelineId, "err", err)
+		return false, err
+	}
+	return false, nil
+}
+
+func (impl *DraftAwareConfigServiceImpl) savePipelineStrategyDraft(appId, envId, pipelineId int, strategies []pkgBean.Strategy, userId int32) error {
+	data, err := json.Marshal(&draftBean.PipelineStrategyDraft{PipelineId: pipelineId, Strategies: strategies})
+	if err != nil {
+		return err
+	}
+	_, err = impl.configDraftService.SaveDraft(&draftBean.ConfigDraftRequest{
+		AppId:         appId,
+		EnvironmentId: envId,
+		ResourceType:  bean.PipelineStrategy,
+		ResourceName:  draftBean.PipelineStrategyResourceName,
+		Action:        draftBean.DraftActionUpdate,
+		Data:          data,
+		UserId:        userId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving pipeline strategy draft", "appId", appId, "envId", envId, "pipelineId", pipelineId, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/repository"
+)
+
+var draftResourceTypes = map[pipelineBean.ResourceType]bean.DraftResourceType{
+	pipelineBean.CM:                 bean.DraftResourceTypeCM,
+	pipelineBean.CS:                 bean.DraftResourceTypeCS,
+	pipelineBean.DeploymentTemplate: bean.DraftResourceTypeDeploymentTemplate,
+	pipelineBean.PipelineStrategy:   bean.DraftResourceTypePipelineStrategy,
+}
+
+// GetDraftResourceType returns the persisted resource type of the drafts of configs of resourceType
+func GetDraftResourceType(resourceType pipelineBean.ResourceType) (bean.DraftResourceType, bool) {
+	draftResourceType, ok := draftResourceTypes[resourceType]
+	return draftResourceType, ok
+}
+
+func getResourceType(draftResourceType bean.DraftResourceType) pipelineBean.ResourceType {
+	for resourceType, value := range draftResourceTypes {
+		if value == draftResourceType {
+			return resourceType
+		}
+	}
+	return ""
+}
+
+// GetConfigDraftDto returns the draft, the reviewer of a published or rejected draft is the last user updating it
+func GetConfigDraftDto(draft *repository.Draft) *bean.ConfigDraftDto {
+	draftDto := &bean.ConfigDraftDto{
+		Id:            draft.Id,
+		AppId:         draft.AppId,
+		EnvironmentId: draft.EnvId,
+		ResourceType:  getResourceType(draft.Resource),
+		ResourceName:  draft.ResourceName,
+		State:         draft.DraftState,
+		CreatedBy:     draft.CreatedBy,
+		CreatedOn:     draft.CreatedOn,
+	}
+	if draft.DraftState.IsReviewed() {
+		reviewedOn := draft.UpdatedOn
+		draftDto.ReviewedBy = draft.UpdatedBy
+		draftDto.ReviewedOn = &reviewedOn
+	}
+	return draftDto
+}
+
+// GetConfigDraftVersionDto returns the draft version, version is the 1 based index of the version in the draft
+func GetConfigDraftVersionDto(version *repository.DraftVersion, versionNumber int) *bean.ConfigDraftVersionDto {
+	return &bean.ConfigDraftVersionDto{
+		Id:        version.Id,
+		Version:   versionNumber,
+		Action:    version.Action,
+		Data:      []byte(version.Data),
+		CreatedBy: version.UserId,
+		CreatedOn: version.CreatedOn,
+	}
+}
+
+// GetConfigDraftDtos returns the drafts along with their latest versions, versions are ordered oldest first
+func GetConfigDraftDtos(drafts []*repository.Draft, versions []*repository.DraftVersion) []*bean.ConfigDraftDto {
+	versionsByDraftId := make(map[int][]*repository.DraftVersion, len(drafts))
+	for _, version := range versions {
+		versionsByDraftId[version.DraftId] = append(versionsByDraftId[version.DraftId], version)
+	}
+	draftDtos := make([]*bean.ConfigDraftDto, 0, len(drafts))
+	for _, draft := range drafts {
+		draftDto := GetConfigDraftDto(draft)
+		if draftVersions := versionsByDraftId[draft.Id]; len(draftVersions) > 0 {
+			draftDto.LatestVersion = GetConfigDraftVersionDto(draftVersions[len(draftVersions)-1], len(draftVersions))
+		}
+		draftDtos = append(draftDtos, draftDto)
+	}
+	return draftDtos
+}
+
+func GetConfigDraftCommentDto(comment *repository.DraftVersionComment) *bean.ConfigDraftCommentDto {
+	return &bean.ConfigDraftCommentDto{
+		Id:             comment.Id,
+		DraftVersionId: comment.DraftVersionId,
+		Comment:        comment.Comment,
+		CreatedBy:      comment.CreatedBy,
+		CreatedOn:      comment.CreatedOn,
+	}
+}
+
+func GetConfigProtectionDto(protection *repository.ResourceProtection) *bean.ConfigProtectionDto {
+	return &bean.ConfigProtectionDto{
+		AppId:         protection.AppId,
+		EnvironmentId: protection.EnvId,
+		Enabled:       protection.ProtectionState == bean.ProtectionStateEnabled,
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	pkgBean "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/config/configDiff/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"time"
+)
+
+// DraftState is the state of a draft, values are persisted in the draft table
+type DraftState int
+
+const (
+	DraftStateInit             DraftState = 1
+	DraftStateDiscarded        DraftState = 2
+	DraftStatePublished        DraftState = 3
+	DraftStateAwaitingApproval DraftState = 4
+	DraftStateRejected         DraftState = 5
+)
+
+func (state DraftState) String() string {
+	switch state {
+	case DraftStateInit:
+		return "init"
+	case DraftStateDiscarded:
+		return "discarded"
+	case DraftStatePublished:
+		return "published"
+	case DraftStateAwaitingApproval:
+		return "awaiting approval"
+	case DraftStateRejected:
+		return "rejected"
+	}
+	return "unknown"
+}
+
+// IsReviewed returns true if the draft is approved or rejected by a reviewer
+func (state DraftState) IsReviewed() bool {
+	return state == DraftStatePublished || state == DraftStateRejected
+}
+
+// DraftAction is the change proposed by a draft version, it decides the service call made on publishing the draft
+type DraftAction int
+
+const (
+	// DraftActionCreate creates the env override of the deployment template
+	DraftActionCreate DraftAction = 1
+	DraftActionUpdate DraftAction = 2
+	// DraftActionDelete deletes the env override of the config map or secret, or resets the deployment template to the base
+	DraftActionDelete DraftAction = 3
+)
+
+// DraftResourceType is the type of the config of a draft, values are persisted in the draft table
+type DraftResourceType int
+
+const (
+	DraftResourceTypeCM                 DraftResourceType = 1
+	DraftResourceTypeCS                 DraftResourceType = 2
+	DraftResourceTypeDeploymentTemplate DraftResourceType = 3
+	DraftResourceTypePipelineStrategy   DraftResourceType = 4
+)
+
+// ProtectionResourceType is the resource protected in the resource_protection table
+type ProtectionResourceType int
+
+const (
+	// ProtectionResourceTypeConfig protects the env level config maps, secrets, deployment template and deployment
+	// strategies of the app
+	ProtectionResourceTypeConfig ProtectionResourceType = 1
+)
+
+type ProtectionState int
+
+const (
+	ProtectionStateEnabled  ProtectionState = 1
+	ProtectionStateDisabled ProtectionState = 2
+)
+
+// DeploymentTemplateResourceName is the resource name of the drafts of env level deployment templates
+const DeploymentTemplateResourceName = "deployment-template"
+
+// PipelineStrategyResourceName is the resource name of the drafts of the deployment strategies of the cd pipeline
+const PipelineStrategyResourceName = "pipeline-strategy"
+
+type ConfigDraftRequest struct {
+	AppId         int
+	EnvironmentId int
+	ResourceType  pipelineBean.ResourceType
+	ResourceName  string
+	Action        DraftAction
+	// Data is the request of the config change, published as is once the draft is approved
+	Data   json.RawMessage
+	UserId int32
+}
+
+// ConfigDataDeleteDraft is the data of the draft deleting the env override of a config map or secret
+type ConfigDataDeleteDraft struct {
+	Name    string                          `json:"name"`
+	Request *pipelineBean.ConfigDataRequest `json:"request"`
+}
+
+// PipelineStrategyDraft is the data of the draft replacing the deployment strategies of the cd pipeline
+type PipelineStrategyDraft struct {
+	PipelineId int                `json:"pipelineId"`
+	Strategies []pkgBean.Strategy `json:"strategies"`
+}
+
+type ConfigDraftDto struct {
+	Id              int                       `json:"id"`
+	AppId           int                       `json:"appId"`
+	EnvironmentId   int                       `json:"environmentId"`
+	ResourceType    pipelineBean.ResourceType `json:"resourceType"`
+	ResourceName    string                    `json:"resourceName"`
+	State           DraftState                `json:"state"`
+	ReviewedBy      int32                     `json:"reviewedBy,omitempty"`
+	ReviewedOn      *time.Time                `json:"reviewedOn,omitempty"`
+	RejectionReason string                    `json:"rejectionReason,omitempty"`
+	CreatedBy       int32                     `json:"createdBy"`
+	CreatedOn       time.Time                 `json:"createdOn"`
+	LatestVersion   *ConfigDraftVersionDto    `json:"latestVersion,omitempty"`
+	Versions        []*ConfigDraftVersionDto  `json:"versions,omitempty"`
+	Comments        []*ConfigDraftCommentDto  `json:"comments,omitempty"`
+}
+
+type ConfigDraftVersionDto struct {
+	Id        int             `json:"id"`
+	Version   int             `json:"version"`
+	Action    DraftAction     `json:"action"`
+	Data      json.RawMessage `json:"data"`
+	CreatedBy int32           `json:"createdBy"`
+	CreatedOn time.Time       `json:"createdOn"`
+}
+
+type ConfigDraftCommentDto struct {
+	Id             int       `json:"id"`
+	DraftVersionId int       `json:"draftVersionId"`
+	Comment        string    `json:"comment"`
+	CreatedBy      int32     `json:"createdBy"`
+	CreatedOn      time.Time `json:"createdOn"`
+}
+
+// ConfigDraftActionRequest approves, rejects, discards or comments on the version DraftVersionId of a draft,
+// actions on a version other than the latest one fail as the draft changed since it was reviewed
+type ConfigDraftActionRequest struct {
+	DraftId        int    `json:"draftId" validate:"required"`
+	DraftVersionId int    `json:"draftVersionId" validate:"required"`
+	Comment        string `json:"comment" validate:"max=1000"`
+	UserId         int32  `json:"-"`
+}
+
+type ConfigDraftDiffDto struct {
+	DraftId        int                       `json:"draftId"`
+	DraftVersionId int                       `json:"draftVersionId"`
+	ResourceType   pipelineBean.ResourceType `json:"resourceType"`
+	ResourceName   string                    `json:"resourceName"`
+	Action         DraftAction               `json:"action"`
+	Published      json.RawMessage           `json:"published,omitempty"`
+	Draft          json.RawMessage           `json:"draft,omitempty"`
+	// SecretComparison is the published secrets against the secrets with the draft applied, values are masked for non admins
+	SecretComparison *bean.ComparisonResponseDto `json:"secretComparison,omitempty"`
+}
+
+// ConfigProtectionDto enables or disables the approval of the env level config changes of the app in the environment
+type ConfigProtectionDto struct {
+	AppId         int   `json:"appId" validate:"required"`
+	EnvironmentId int   `json:"environmentId" validate:"required"`
+	Enabled       bool  `json:"enabled"`
+	UserId        int32 `json:"-"`
+}
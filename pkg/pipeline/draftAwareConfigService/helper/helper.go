@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+)
+
+// GetDraftConfigData returns the config map or secret proposed by the draft version, nil if the version deletes the env override
+func GetDraftConfigData(version *bean.ConfigDraftVersionDto) (*pipelineBean.ConfigData, error) {
+	if version == nil || version.Action == bean.DraftActionDelete {
+		return nil, nil
+	}
+	request := &pipelineBean.ConfigDataRequest{}
+	err := json.Unmarshal(version.Data, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.ConfigData) == 0 {
+		return nil, fmt.Errorf("draft version %d has no config data", version.Id)
+	}
+	return request.ConfigData[0], nil
+}
+
+// ApplyConfigDataDrafts returns the config maps or secrets of an environment as they would be once the drafts are
+// published. Deleting the env override of a config inherited from the base configuration reverts it to the base data.
+func ApplyConfigDataDrafts(configData []*pipelineBean.ConfigData, drafts []*bean.ConfigDraftDto) ([]*pipelineBean.ConfigData, error) {
+	draftsByName := make(map[string]*bean.ConfigDraftDto, len(drafts))
+	for _, draft := range drafts {
+		draftsByName[draft.ResourceName] = draft
+	}
+	result := make([]*pipelineBean.ConfigData, 0, len(configData)+len(drafts))
+	for _, item := range configData {
+		draft, ok := draftsByName[item.Name]
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		delete(draftsByName, item.Name)
+		draftItem, err := GetDraftConfigData(draft.LatestVersion)
+		if err != nil {
+			return nil, err
+		}
+		if draftItem != nil {
+			result = append(result, draftItem)
+		} else if len(item.DefaultData) > 0 {
+			inherited := *item
+			inherited.Data = item.DefaultData
+			inherited.Global = true
+			result = append(result, &inherited)
+		}
+	}
+	// drafts adding configs not present in the environment yet
+	for _, draft := range drafts {
+		if _, ok := draftsByName[draft.ResourceName]; !ok {
+			continue
+		}
+		draftItem, err := GetDraftConfigData(draft.LatestVersion)
+		if err != nil {
+			return nil, err
+		}
+		if draftItem != nil {
+			result = append(result, draftItem)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getDraft(t *testing.T, name string, action bean.DraftAction, item *pipelineBean.ConfigData) *bean.ConfigDraftDto {
+	var data json.RawMessage
+	if item != nil {
+		var err error
+		data, err = json.Marshal(&pipelineBean.ConfigDataRequest{ConfigData: []*pipelineBean.ConfigData{item}})
+		assert.Nil(t, err)
+	}
+	return &bean.ConfigDraftDto{
+		ResourceName:  name,
+		State:         bean.DraftStateAwaitingApproval,
+		LatestVersion: &bean.ConfigDraftVersionDto{Id: 1, Version: 1, Action: action, Data: data},
+	}
+}
+
+func TestApplyConfigDataDrafts(t *testing.T) {
+	t.Run("draft updates existing config", func(t *testing.T) {
+		configData := []*pipelineBean.ConfigData{
+			{Name: "cm-1", Data: json.RawMessage(`{"a":"1"}`)},
+			{Name: "cm-2", Data: json.RawMessage(`{"b":"1"}`)},
+		}
+		drafts := []*bean.ConfigDraftDto{
+			getDraft(t, "cm-2", bean.DraftActionUpdate, &pipelineBean.ConfigData{Name: "cm-2", Data: json.RawMessage(`{"b":"2"}`)}),
+		}
+		result, err := ApplyConfigDataDrafts(configData, drafts)
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "cm-1", result[0].Name)
+		assert.JSONEq(t, `{"b":"2"}`, string(result[1].Data))
+	})
+	t.Run("draft adds new config", func(t *testing.T) {
+		configData := []*pipelineBean.ConfigData{
+			{Name: "cm-1", Data: json.RawMessage(`{"a":"1"}`)},
+		}
+		drafts := []*bean.ConfigDraftDto{
+			getDraft(t, "cm-new", bean.DraftActionUpdate, &pipelineBean.ConfigData{Name: "cm-new", Data: json.RawMessage(`{"c":"1"}`)}),
+		}
+		result, err := ApplyConfigDataDrafts(configData, drafts)
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "cm-new", result[1].Name)
+	})
+	t.Run("delete draft reverts override to base data", func(t *testing.T) {
+		configData := []*pipelineBean.ConfigData{
+			{Name: "cm-1", Data: json.RawMessage(`{"a":"2"}`), DefaultData: json.RawMessage(`{"a":"1"}`)},
+		}
+		drafts := []*bean.ConfigDraftDto{getDraft(t, "cm-1", bean.DraftActionDelete, nil)}
+		result, err := ApplyConfigDataDrafts(configData, drafts)
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.True(t, result[0].Global)
+		assert.JSONEq(t, `{"a":"1"}`, string(result[0].Data))
+		// the published config is left untouched
+		assert.JSONEq(t, `{"a":"2"}`, string(configData[0].Data))
+	})
+	t.Run("delete draft removes env only config", func(t *testing.T) {
+		configData := []*pipelineBean.ConfigData{
+			{Name: "cm-1", Data: json.RawMessage(`{"a":"1"}`)},
+			{Name: "cm-2", Data: json.RawMessage(`{"b":"1"}`)},
+		}
+		drafts := []*bean.ConfigDraftDto{getDraft(t, "cm-1", bean.DraftActionDelete, nil)}
+		result, err := ApplyConfigDataDrafts(configData, drafts)
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "cm-2", result[0].Name)
+	})
+}
+
+func TestGetDraftConfigData(t *testing.T) {
+	item, err := GetDraftConfigData(&bean.ConfigDraftVersionDto{Action: bean.DraftActionDelete})
+	assert.Nil(t, err)
+	assert.Nil(t, item)
+
+	_, err = GetDraftConfigData(&bean.ConfigDraftVersionDto{Action: bean.DraftActionUpdate, Data: json.RawMessage(`{"configData":[]}`)})
+	assert.NotNil(t, err)
+}
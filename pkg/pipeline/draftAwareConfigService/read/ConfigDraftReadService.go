@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package read
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/adapter"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/repository"
+	"go.uber.org/zap"
+)
+
+type ConfigDraftReadService interface {
+	// IsConfigProtected returns true if env level config changes of the app in the environment need approval
+	IsConfigProtected(appId, envId int) (bool, error)
+	// GetAwaitingApprovalDrafts returns the drafts awaiting approval of the configs of type resourceType
+	// of the app in the environment, along with their latest versions
+	GetAwaitingApprovalDrafts(appId, envId int, resourceType pipelineBean.ResourceType) ([]*bean.ConfigDraftDto, error)
+}
+
+type ConfigDraftReadServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	draftRepository              repository.DraftRepository
+	resourceProtectionRepository repository.ResourceProtectionRepository
+}
+
+func NewConfigDraftReadServiceImpl(logger *zap.SugaredLogger,
+	draftRepository repository.DraftRepository,
+	resourceProtectionRepository repository.ResourceProtectionRepository) *ConfigDraftReadServiceImpl {
+	return &ConfigDraftReadServiceImpl{
+		logger:                       logger,
+		draftRepository:              draftRepository,
+		resourceProtectionRepository: resourceProtectionRepository,
+	}
+}
+
+func (impl *ConfigDraftReadServiceImpl) IsConfigProtected(appId, envId int) (bool, error) {
+	if envId <= 0 {
+		// base configurations are not protected
+		return false, nil
+	}
+	protection, err := impl.resourceProtectionRepository.FindByAppIdAndEnvId(appId, envId, bean.ProtectionResourceTypeConfig)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching config protection", "appId", appId, "envId", envId, "err", err)
+		return false, err
+	}
+	return err == nil && protection.ProtectionState == bean.ProtectionStateEnabled, nil
+}
+
+func (impl *ConfigDraftReadServiceImpl) GetAwaitingApprovalDrafts(appId, envId int, resourceType pipelineBean.ResourceType) ([]*bean.ConfigDraftDto, error) {
+	draftResourceType, ok := adapter.GetDraftResourceType(resourceType)
+	if !ok {
+		return nil, nil
+	}
+	drafts, err := impl.draftRepository.FindAwaitingApprovalDraftsByResource(appId, envId, draftResourceType)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching drafts awaiting approval", "appId", appId, "envId", envId, "resourceType", resourceType, "err", err)
+		return nil, err
+	}
+	draftIds := make([]int, 0, len(drafts))
+	for _, draft := range drafts {
+		draftIds = append(draftIds, draft.Id)
+	}
+	versions, err := impl.draftRepository.FindVersionsByDraftIds(draftIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching draft versions", "draftIds", draftIds, "err", err)
+		return nil, err
+	}
+	return adapter.GetConfigDraftDtos(drafts, versions), nil
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Draft is the proposed change of the config ResourceName of the app in a protected environment,
+// there is at most one draft awaiting approval per config
+type Draft struct {
+	tableName    struct{}               `sql:"draft" pg:",discard_unknown_columns"`
+	Id           int                    `sql:"id,pk"`
+	AppId        int                    `sql:"app_id,notnull"`
+	EnvId        int                    `sql:"env_id,notnull"`
+	Resource     bean.DraftResourceType `sql:"resource,notnull"`
+	ResourceName string                 `sql:"resource_name,notnull"`
+	DraftState   bean.DraftState        `sql:"draft_state"`
+	sql.AuditLog
+}
+
+// DraftVersion is a revision of the draft, the latest version is the one reviewed and published
+type DraftVersion struct {
+	tableName struct{}         `sql:"draft_version" pg:",discard_unknown_columns"`
+	Id        int              `sql:"id,pk"`
+	DraftId   int              `sql:"draft_id,notnull"`
+	Data      string           `sql:"data,notnull"`
+	Action    bean.DraftAction `sql:"action,notnull"`
+	UserId    int32            `sql:"user_id,notnull"`
+	CreatedOn time.Time        `sql:"created_on"`
+}
+
+type DraftVersionComment struct {
+	tableName      struct{} `sql:"draft_version_comment" pg:",discard_unknown_columns"`
+	Id             int      `sql:"id,pk"`
+	DraftId        int      `sql:"draft_id,notnull"`
+	DraftVersionId int      `sql:"draft_version_id,notnull"`
+	Comment        string   `sql:"comment"`
+	Active         bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DraftRepository interface {
+	// SaveDraft saves the draft with its first version
+	SaveDraft(draft *Draft, version *DraftVersion) error
+	// UpdateDraftState moves the draft from state expected to state updated, returns false if the draft
+	// was already moved (by another request)
+	UpdateDraftState(id int, expected, updated bean.DraftState, userId int32) (bool, error)
+	FindById(id int) (*Draft, error)
+	FindAwaitingApprovalDraft(appId, envId int, resource bean.DraftResourceType, resourceName string) (*Draft, error)
+	FindAwaitingApprovalDraftsByResource(appId, envId int, resource bean.DraftResourceType) ([]*Draft, error)
+	FindDrafts(appId, envId int, states []bean.DraftState) ([]*Draft, error)
+
+	SaveVersion(version *DraftVersion) error
+	// FindVersionsByDraftId returns the versions of the draft, oldest first
+	FindVersionsByDraftId(draftId int) ([]*DraftVersion, error)
+	FindVersionsByDraftIds(draftIds []int) ([]*DraftVersion, error)
+
+	SaveComment(comment *DraftVersionComment) error
+	FindActiveCommentsByDraftId(draftId int) ([]*DraftVersionComment, error)
+}
+
+type DraftRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDraftRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DraftRepositoryImpl {
+	return &DraftRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DraftRepositoryImpl) SaveDraft(draft *Draft, version *DraftVersion) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		err := tx.Insert(draft)
+		if err != nil {
+			return err
+		}
+		version.DraftId = draft.Id
+		return tx.Insert(version)
+	})
+}
+
+func (impl *DraftRepositoryImpl) UpdateDraftState(id int, expected, updated bean.DraftState, userId int32) (bool, error) {
+	res, err := impl.dbConnection.Model(&Draft{}).
+		Set("draft_state = ?", updated).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id = ?", id).
+		Where("draft_state = ?", expected).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *DraftRepositoryImpl) FindById(id int) (*Draft, error) {
+	draft := &Draft{}
+	err := impl.dbConnection.Model(draft).
+		Where("id = ?", id).
+		Select()
+	return draft, err
+}
+
+func (impl *DraftRepositoryImpl) FindAwaitingApprovalDraft(appId, envId int, resource bean.DraftResourceType, resourceName string) (*Draft, error) {
+	draft := &Draft{}
+	err := impl.dbConnection.Model(draft).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("resource = ?", resource).
+		Where("resource_name = ?", resourceName).
+		Where("draft_state = ?", bean.DraftStateAwaitingApproval).
+		Limit(1).
+		Select()
+	return draft, err
+}
+
+func (impl *DraftRepositoryImpl) FindAwaitingApprovalDraftsByResource(appId, envId int, resource bean.DraftResourceType) ([]*Draft, error) {
+	var drafts []*Draft
+	err := impl.dbConnection.Model(&drafts).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("resource = ?", resource).
+		Where("draft_state = ?", bean.DraftStateAwaitingApproval).
+		Order("id ASC").
+		Select()
+	return drafts, err
+}
+
+func (impl *DraftRepositoryImpl) FindDrafts(appId, envId int, states []bean.DraftState) ([]*Draft, error) {
+	var drafts []*Draft
+	query := impl.dbConnection.Model(&drafts).
+		Where("app_id = ?", appId)
+	if envId > 0 {
+		query = query.Where("env_id = ?", envId)
+	}
+	if len(states) > 0 {
+		query = query.Where("draft_state in (?)", pg.In(states))
+	}
+	err := query.Order("id DESC").Select()
+	return drafts, err
+}
+
+func (impl *DraftRepositoryImpl) SaveVersion(version *DraftVersion) error {
+	return impl.dbConnection.Insert(version)
+}
+
+func (impl *DraftRepositoryImpl) FindVersionsByDraftId(draftId int) ([]*DraftVersion, error) {
+	var versions []*DraftVersion
+	err := impl.dbConnection.Model(&versions).
+		Where("draft_id = ?", draftId).
+		Order("id ASC").
+		Select()
+	return versions, err
+}
+
+func (impl *DraftRepositoryImpl) FindVersionsByDraftIds(draftIds []int) ([]*DraftVersion, error) {
+	var versions []*DraftVersion
+	if len(draftIds) == 0 {
+		return versions, nil
+	}
+	err := impl.dbConnection.Model(&versions).
+		Where("draft_id in (?)", pg.In(draftIds)).
+		Order("id ASC").
+		Select()
+	return versions, err
+}
+
+func (impl *DraftRepositoryImpl) SaveComment(comment *DraftVersionComment) error {
+	return impl.dbConnection.Insert(comment)
+}
+
+func (impl *DraftRepositoryImpl) FindActiveCommentsByDraftId(draftId int) ([]*DraftVersionComment, error) {
+	var comments []*DraftVersionComment
+	err := impl.dbConnection.Model(&comments).
+		Where("draft_id = ?", draftId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return comments, err
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ResourceProtection marks the resource of the app in the environment protected, config changes of a protected
+// app environment are saved as drafts and published only once approved
+type ResourceProtection struct {
+	tableName       struct{}                    `sql:"resource_protection" pg:",discard_unknown_columns"`
+	Id              int                         `sql:"id,pk"`
+	AppId           int                         `sql:"app_id,notnull"`
+	EnvId           int                         `sql:"env_id,notnull"`
+	Resource        bean.ProtectionResourceType `sql:"resource,notnull"`
+	ProtectionState bean.ProtectionState        `sql:"protection_state,notnull"`
+	sql.AuditLog
+}
+
+type ResourceProtectionHistory struct {
+	tableName       struct{}                    `sql:"resource_protection_history" pg:",discard_unknown_columns"`
+	Id              int                         `sql:"id,pk"`
+	AppId           int                         `sql:"app_id,notnull"`
+	EnvId           int                         `sql:"env_id,notnull"`
+	Resource        bean.ProtectionResourceType `sql:"resource,notnull"`
+	ProtectionState bean.ProtectionState        `sql:"protection_state,notnull"`
+	UpdatedOn       time.Time                   `sql:"updated_on"`
+	UpdatedBy       int32                       `sql:"updated_by"`
+}
+
+type ResourceProtectionRepository interface {
+	FindByAppIdAndEnvId(appId, envId int, resource bean.ProtectionResourceType) (*ResourceProtection, error)
+	// FindByAppId returns the protections of all the environments of the app, all apps if appId is 0
+	FindByAppId(appId int, resource bean.ProtectionResourceType) ([]*ResourceProtection, error)
+	// Save saves or updates the protection along with its history
+	Save(model *ResourceProtection) error
+}
+
+type ResourceProtectionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewResourceProtectionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ResourceProtectionRepositoryImpl {
+	return &ResourceProtectionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ResourceProtectionRepositoryImpl) FindByAppIdAndEnvId(appId, envId int, resource bean.ProtectionResourceType) (*ResourceProtection, error) {
+	model := &ResourceProtection{}
+	err := impl.dbConnection.Model(model).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("resource = ?", resource).
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *ResourceProtectionRepositoryImpl) FindByAppId(appId int, resource bean.ProtectionResourceType) ([]*ResourceProtection, error) {
+	var models []*ResourceProtection
+	query := impl.dbConnection.Model(&models).
+		Where("resource = ?", resource)
+	if appId > 0 {
+		query = query.Where("app_id = ?", appId)
+	}
+	err := query.Order("app_id ASC", "env_id ASC").Select()
+	return models, err
+}
+
+func (impl *ResourceProtectionRepositoryImpl) Save(model *ResourceProtection) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		var err error
+		if model.Id > 0 {
+			err = tx.Update(model)
+		} else {
+			err = tx.Insert(model)
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Insert(&ResourceProtectionHistory{
+			AppId:           model.AppId,
+			EnvId:           model.EnvId,
+			Resource:        model.Resource,
+			ProtectionState: model.ProtectionState,
+			UpdatedOn:       model.UpdatedOn,
+			UpdatedBy:       model.UpdatedBy,
+		})
+	})
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package draftAwareConfigService
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/read"
+	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService/repository"
+	"github.com/google/wire"
+)
+
+var DraftAwareConfigWireSet = wire.NewSet(
+	repository.NewDraftRepositoryImpl,
+	wire.Bind(new(repository.DraftRepository), new(*repository.DraftRepositoryImpl)),
+	repository.NewResourceProtectionRepositoryImpl,
+	wire.Bind(new(repository.ResourceProtectionRepository), new(*repository.ResourceProtectionRepositoryImpl)),
+	read.NewConfigDraftReadServiceImpl,
+	wire.Bind(new(read.ConfigDraftReadService), new(*read.ConfigDraftReadServiceImpl)),
+	NewConfigDraftServiceImpl,
+	wire.Bind(new(ConfigDraftService), new(*ConfigDraftServiceImpl)),
+	NewDraftAwareResourceServiceImpl,
+	wire.Bind(new(DraftAwareConfigService), new(*DraftAwareConfigServiceImpl)),
+)
@@ -0,0 +1,505 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentAdmission
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	devtronResourceBean "github.com/devtron-labs/devtron/pkg/devtronResource/bean"
+	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/celEvaluator"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/repository"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	celGo "github.com/google/cel-go/cel"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// DeploymentAdmissionPolicyService manages CEL based policies which are evaluated before every cd deployment trigger
+type DeploymentAdmissionPolicyService interface {
+	CreatePolicy(request *bean.DeploymentAdmissionPolicyDto) (*bean.DeploymentAdmissionPolicyDto, error)
+	UpdatePolicy(request *bean.DeploymentAdmissionPolicyDto) (*bean.DeploymentAdmissionPolicyDto, error)
+	DeletePolicy(policyId int, userId int32) error
+	GetPolicyById(policyId int) (*bean.DeploymentAdmissionPolicyDto, error)
+	GetAllPolicies() ([]*bean.DeploymentAdmissionPolicyDto, error)
+	// ValidateExpression checks that the expression compiles against the admission params and evaluates to a bool
+	ValidateExpression(expression string) error
+	// GetExpressionParams returns the params which can be used in policy expressions
+	GetExpressionParams() []cel.ExpressionParam
+	// EvaluatePolicies evaluates all enabled policies applicable on the app, env, cluster and project of the pipeline
+	EvaluatePolicies(request *bean.EvaluationRequest) (*bean.EvaluationResponse, error)
+}
+
+type DeploymentAdmissionPolicyServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	deploymentAdmissionPolicyRepository repository.DeploymentAdmissionPolicyRepository
+	qualifierMappingService             resourceQualifiers.QualifierMappingService
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService
+	celEvaluatorService                 cel.EvaluatorService
+	triggerEventEvaluator               celEvaluator.TriggerEventEvaluator
+	envRepository                       repository3.EnvironmentRepository
+	chartRepository                     chartRepoRepository.ChartRepository
+	transactionManager                  sql.TransactionWrapper
+	config                              *bean.DeploymentAdmissionConfig
+}
+
+func NewDeploymentAdmissionPolicyServiceImpl(logger *zap.SugaredLogger,
+	deploymentAdmissionPolicyRepository repository.DeploymentAdmissionPolicyRepository,
+	qualifierMappingService resourceQualifiers.QualifierMappingService,
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService,
+	celEvaluatorService cel.EvaluatorService,
+	triggerEventEvaluator celEvaluator.TriggerEventEvaluator,
+	envRepository repository3.EnvironmentRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	transactionManager sql.TransactionWrapper) (*DeploymentAdmissionPolicyServiceImpl, error) {
+	config := &bean.DeploymentAdmissionConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing deployment admission config", "err", err)
+		return nil, err
+	}
+	return &DeploymentAdmissionPolicyServiceImpl{
+		logger:                              logger,
+		deploymentAdmissionPolicyRepository: deploymentAdmissionPolicyRepository,
+		qualifierMappingService:             qualifierMappingService,
+		devtronResourceSearchableKeyService: devtronResourceSearchableKeyService,
+		celEvaluatorService:                 celEvaluatorService,
+		triggerEventEvaluator:               triggerEventEvaluator,
+		envRepository:                       envRepository,
+		chartRepository:                     chartRepository,
+		transactionManager:                  transactionManager,
+		config:                              config,
+	}, nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) CreatePolicy(request *bean.DeploymentAdmissionPolicyDto) (*bean.DeploymentAdmissionPolicyDto, error) {
+	err := impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	policy := &repository.DeploymentAdmissionPolicy{
+		AuditLog: sql.NewDefaultAuditLog(request.UserId),
+	}
+	setPolicyFields(policy, request)
+	err = impl.deploymentAdmissionPolicyRepository.Save(tx, policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment admission policy", "policy", policy, "err", err)
+		return nil, err
+	}
+	err = impl.createScopeMappings(tx, policy.Id, request.Scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) UpdatePolicy(request *bean.DeploymentAdmissionPolicyDto) (*bean.DeploymentAdmissionPolicyDto, error) {
+	policy, err := impl.deploymentAdmissionPolicyRepository.FindById(request.Id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "policy not found", "policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment admission policy", "policyId", request.Id, "err", err)
+		return nil, err
+	}
+	err = impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	setPolicyFields(policy, request)
+	policy.UpdateAuditLog(request.UserId)
+	err = impl.deploymentAdmissionPolicyRepository.Update(tx, policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment admission policy", "policy", policy, "err", err)
+		return nil, err
+	}
+	err = impl.deleteScopeMappings(tx, policy.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.createScopeMappings(tx, policy.Id, request.Scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) DeletePolicy(policyId int, userId int32) error {
+	policy, err := impl.deploymentAdmissionPolicyRepository.FindById(policyId)
+	if util.IsErrNoRows(err) {
+		return util.NewApiError(http.StatusNotFound, "policy not found", "policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment admission policy", "policyId", policyId, "err", err)
+		return err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	policy.Deleted = true
+	policy.UpdateAuditLog(userId)
+	err = impl.deploymentAdmissionPolicyRepository.Update(tx, policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment admission policy", "policyId", policyId, "err", err)
+		return err
+	}
+	err = impl.deleteScopeMappings(tx, policy.Id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.transactionManager.CommitTx(tx)
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) GetPolicyById(policyId int) (*bean.DeploymentAdmissionPolicyDto, error) {
+	policy, err := impl.deploymentAdmissionPolicyRepository.FindById(policyId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "policy not found", "policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment admission policy", "policyId", policyId, "err", err)
+		return nil, err
+	}
+	policyDtos, err := impl.buildPolicyDtos([]*repository.DeploymentAdmissionPolicy{policy})
+	if err != nil {
+		return nil, err
+	}
+	return policyDtos[0], nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) GetAllPolicies() ([]*bean.DeploymentAdmissionPolicyDto, error) {
+	policies, err := impl.deploymentAdmissionPolicyRepository.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment admission policies", "err", err)
+		return nil, err
+	}
+	return impl.buildPolicyDtos(policies)
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) ValidateExpression(expression string) error {
+	ast, _, err := impl.celEvaluatorService.Validate(cel.Request{
+		Expression: expression,
+		ExpressionMetadata: cel.ExpressionMetadata{
+			Params: celEvaluator.GetDeploymentAdmissionParamDeclarations(),
+		},
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("invalid expression: %s", err.Error())
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if !ast.OutputType().IsExactType(celGo.BoolType) {
+		errMsg := fmt.Sprintf("invalid expression: expression should evaluate to bool, found %s", ast.OutputType().String())
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) GetExpressionParams() []cel.ExpressionParam {
+	return celEvaluator.GetDeploymentAdmissionParamDeclarations()
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) EvaluatePolicies(request *bean.EvaluationRequest) (*bean.EvaluationResponse, error) {
+	response := &bean.EvaluationResponse{Results: make([]*bean.PolicyEvaluationResult, 0)}
+	cdPipeline := request.Pipeline
+	policies, err := impl.deploymentAdmissionPolicyRepository.FindAllEnabled()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching enabled deployment admission policies", "err", err)
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return response, nil
+	}
+	environment, err := impl.envRepository.FindById(cdPipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", cdPipeline.EnvironmentId, "err", err)
+		return nil, err
+	}
+	applicablePolicies, err := impl.getApplicablePolicies(policies, cdPipeline.AppId, cdPipeline.EnvironmentId, environment.ClusterId, cdPipeline.App.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	if len(applicablePolicies) == 0 {
+		return response, nil
+	}
+	chartRefId := 0
+	chart, err := impl.chartRepository.FindLatestChartForAppByAppId(cdPipeline.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching latest chart for app", "appId", cdPipeline.AppId, "err", err)
+		return nil, err
+	} else if chart != nil {
+		chartRefId = chart.ChartRefId
+	}
+	params, err := impl.triggerEventEvaluator.GetDeploymentAdmissionParams(&celEvaluator.DeploymentAdmissionParamsRequest{
+		Pipeline:    cdPipeline,
+		Environment: environment,
+		Artifact:    request.Artifact,
+		ChartRefId:  chartRefId,
+		TriggeredAt: request.TriggeredAt,
+		TriggerType: request.TriggerType,
+		IsRollback:  request.IsRollback,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment admission params", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	for _, policy := range applicablePolicies {
+		matched, err := impl.celEvaluatorService.EvaluateCELRequest(cel.Request{
+			Expression:         policy.Expression,
+			ExpressionMetadata: cel.ExpressionMetadata{Params: params},
+		})
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment admission policy", "policyId", policy.Id, "cdPipelineId", cdPipeline.Id, "err", err)
+			response.Results = append(response.Results, impl.getEvaluationErrorResult(policy, err))
+			continue
+		}
+		if matched {
+			response.Results = append(response.Results, &bean.PolicyEvaluationResult{
+				PolicyId:   policy.Id,
+				PolicyName: policy.Name,
+				Action:     policy.Action,
+				Message:    getPolicyMessage(policy),
+			})
+		}
+	}
+	return response, nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) getEvaluationErrorResult(policy *repository.DeploymentAdmissionPolicy, err error) *bean.PolicyEvaluationResult {
+	action := repository.PolicyActionWarn
+	if impl.config.FailClosedOnEvaluationError {
+		action = repository.PolicyActionDeny
+	}
+	return &bean.PolicyEvaluationResult{
+		PolicyId:   policy.Id,
+		PolicyName: policy.Name,
+		Action:     action,
+		Message:    fmt.Sprintf("policy %s could not be evaluated: %s", policy.Name, err.Error()),
+	}
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) getApplicablePolicies(policies []*repository.DeploymentAdmissionPolicy, appId, envId, clusterId, projectId int) ([]*repository.DeploymentAdmissionPolicy, error) {
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentAdmission, nil, policyIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment admission policy mappings", "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	qualifierToValue := map[resourceQualifiers.Qualifier]int{
+		resourceQualifiers.APP_QUALIFIER:     appId,
+		resourceQualifiers.ENV_QUALIFIER:     envId,
+		resourceQualifiers.CLUSTER_QUALIFIER: clusterId,
+		resourceQualifiers.PROJECT_QUALIFIER: projectId,
+	}
+	applicablePolicyIds := make(map[int]bool)
+	for _, mapping := range mappings {
+		qualifier := resourceQualifiers.Qualifier(mapping.QualifierId)
+		if qualifier == resourceQualifiers.GLOBAL_QUALIFIER {
+			applicablePolicyIds[mapping.ResourceId] = true
+		} else if value, ok := qualifierToValue[qualifier]; ok && value == mapping.IdentifierValueInt {
+			applicablePolicyIds[mapping.ResourceId] = true
+		}
+	}
+	applicablePolicies := make([]*repository.DeploymentAdmissionPolicy, 0, len(applicablePolicyIds))
+	for _, policy := range policies {
+		if applicablePolicyIds[policy.Id] {
+			applicablePolicies = append(applicablePolicies, policy)
+		}
+	}
+	return applicablePolicies, nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) validatePolicy(request *bean.DeploymentAdmissionPolicyDto) error {
+	request.Name = strings.TrimSpace(request.Name)
+	if !request.Action.IsValid() {
+		errMsg := fmt.Sprintf("invalid action %s, allowed actions are %s, %s and %s", request.Action,
+			repository.PolicyActionAllow, repository.PolicyActionDeny, repository.PolicyActionWarn)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if request.Scope == nil || request.Scope.IsEmpty() {
+		return util.NewApiError(http.StatusBadRequest, "policy scope cannot be empty", "policy scope cannot be empty")
+	}
+	existingPolicy, err := impl.deploymentAdmissionPolicyRepository.FindByName(request.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment admission policy by name", "name", request.Name, "err", err)
+		return err
+	} else if err == nil && existingPolicy.Id != request.Id {
+		errMsg := fmt.Sprintf("policy with name %s already exists", request.Name)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return impl.ValidateExpression(request.Expression)
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) createScopeMappings(tx *pg.Tx, policyId int, scope *bean.PolicyScope, userId int32) error {
+	searchableKeyNameIdMap := impl.devtronResourceSearchableKeyService.GetAllSearchableKeyNameIdMap()
+	mappings := make([]*resourceQualifiers.QualifierMapping, 0)
+	newMapping := func(qualifier resourceQualifiers.Qualifier, identifierKey int, identifierValue int) *resourceQualifiers.QualifierMapping {
+		return &resourceQualifiers.QualifierMapping{
+			ResourceId:         policyId,
+			ResourceType:       resourceQualifiers.DeploymentAdmission,
+			QualifierId:        int(qualifier),
+			IdentifierKey:      identifierKey,
+			IdentifierValueInt: identifierValue,
+			Active:             true,
+			AuditLog:           sql.NewDefaultAuditLog(userId),
+		}
+	}
+	if scope.Global {
+		mappings = append(mappings, newMapping(resourceQualifiers.GLOBAL_QUALIFIER, 0, 0))
+	}
+	for _, appId := range scope.AppIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.APP_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_APP_ID], appId))
+	}
+	for _, envId := range scope.EnvIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.ENV_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID], envId))
+	}
+	for _, clusterId := range scope.ClusterIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.CLUSTER_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID], clusterId))
+	}
+	for _, projectId := range scope.ProjectIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.PROJECT_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID], projectId))
+	}
+	_, err := impl.qualifierMappingService.CreateQualifierMappings(mappings, tx)
+	if err != nil {
+		impl.logger.Errorw("error in creating deployment admission policy scope mappings", "policyId", policyId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) deleteScopeMappings(tx *pg.Tx, policyId int, userId int32) error {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentAdmission, nil, []int{policyId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment admission policy scope mappings", "policyId", policyId, "err", err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	mappingIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingIds = append(mappingIds, mapping.Id)
+	}
+	err = impl.qualifierMappingService.DeleteAllByIds(mappingIds, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment admission policy scope mappings", "policyId", policyId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentAdmissionPolicyServiceImpl) buildPolicyDtos(policies []*repository.DeploymentAdmissionPolicy) ([]*bean.DeploymentAdmissionPolicyDto, error) {
+	policyDtos := make([]*bean.DeploymentAdmissionPolicyDto, 0, len(policies))
+	if len(policies) == 0 {
+		return policyDtos, nil
+	}
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentAdmission, nil, policyIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment admission policy scope mappings", "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	policyIdToScope := make(map[int]*bean.PolicyScope, len(policies))
+	for _, policy := range policies {
+		policyIdToScope[policy.Id] = &bean.PolicyScope{}
+	}
+	for _, mapping := range mappings {
+		scope, ok := policyIdToScope[mapping.ResourceId]
+		if !ok {
+			continue
+		}
+		switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+		case resourceQualifiers.GLOBAL_QUALIFIER:
+			scope.Global = true
+		case resourceQualifiers.APP_QUALIFIER:
+			scope.AppIds = append(scope.AppIds, mapping.IdentifierValueInt)
+		case resourceQualifiers.ENV_QUALIFIER:
+			scope.EnvIds = append(scope.EnvIds, mapping.IdentifierValueInt)
+		case resourceQualifiers.CLUSTER_QUALIFIER:
+			scope.ClusterIds = append(scope.ClusterIds, mapping.IdentifierValueInt)
+		case resourceQualifiers.PROJECT_QUALIFIER:
+			scope.ProjectIds = append(scope.ProjectIds, mapping.IdentifierValueInt)
+		}
+	}
+	for _, policy := range policies {
+		policyDtos = append(policyDtos, &bean.DeploymentAdmissionPolicyDto{
+			Id:          policy.Id,
+			Name:        policy.Name,
+			Description: policy.Description,
+			Expression:  policy.Expression,
+			Action:      policy.Action,
+			Message:     policy.Message,
+			Enabled:     policy.Enabled,
+			Scope:       policyIdToScope[policy.Id],
+		})
+	}
+	return policyDtos, nil
+}
+
+func setPolicyFields(policy *repository.DeploymentAdmissionPolicy, request *bean.DeploymentAdmissionPolicyDto) {
+	policy.Name = request.Name
+	policy.Description = request.Description
+	policy.Expression = request.Expression
+	policy.Action = request.Action
+	policy.Message = request.Message
+	policy.Enabled = request.Enabled
+}
+
+func getPolicyMessage(policy *repository.DeploymentAdmissionPolicy) string {
+	if len(policy.Message) > 0 {
+		return policy.Message
+	}
+	return fmt.Sprintf("matched deployment admission policy %s", policy.Name)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CATEGORY=CD
+type DeploymentAdmissionConfig struct {
+	FailClosedOnEvaluationError bool `env:"DEPLOYMENT_ADMISSION_FAIL_CLOSED" envDefault:"false" description:"If enabled, a deployment admission policy which fails to evaluate blocks the deployment, else it is reported as a warning"`
+}
+
+// PolicyScope defines where a policy is applicable, policy applies if any of the given app, env, cluster or project matches
+type PolicyScope struct {
+	Global     bool  `json:"global"`
+	AppIds     []int `json:"appIds,omitempty"`
+	EnvIds     []int `json:"envIds,omitempty"`
+	ClusterIds []int `json:"clusterIds,omitempty"`
+	ProjectIds []int `json:"projectIds,omitempty"`
+}
+
+func (scope *PolicyScope) IsEmpty() bool {
+	return !scope.Global && len(scope.AppIds) == 0 && len(scope.EnvIds) == 0 && len(scope.ClusterIds) == 0 && len(scope.ProjectIds) == 0
+}
+
+type DeploymentAdmissionPolicyDto struct {
+	Id          int                      `json:"id"`
+	Name        string                   `json:"name" validate:"required,max=250"`
+	Description string                   `json:"description"`
+	Expression  string                   `json:"expression" validate:"required"`
+	Action      repository2.PolicyAction `json:"action" validate:"required"`
+	Message     string                   `json:"message"`
+	Enabled     bool                     `json:"enabled"`
+	Scope       *PolicyScope             `json:"scope" validate:"required"`
+	UserId      int32                    `json:"-"`
+}
+
+type ValidateExpressionRequest struct {
+	Expression string `json:"expression" validate:"required"`
+}
+
+type EvaluationRequest struct {
+	// Pipeline should have App and Environment loaded
+	Pipeline    *pipelineConfig.Pipeline
+	Artifact    *repository.CiArtifact
+	TriggeredAt time.Time
+	TriggerType pipelineConfig.TriggerType
+	IsRollback  bool
+}
+
+type PolicyEvaluationResult struct {
+	PolicyId   int                      `json:"policyId"`
+	PolicyName string                   `json:"policyName"`
+	Action     repository2.PolicyAction `json:"action"`
+	Message    string                   `json:"message"`
+}
+
+// EvaluationResponse contains results of the policies whose expressions evaluated to true.
+// ALLOW results act as exceptions, a deployment is denied only if some DENY policy matched and no ALLOW policy matched.
+type EvaluationResponse struct {
+	Results []*PolicyEvaluationResult `json:"results"`
+}
+
+func (response *EvaluationResponse) IsDenied() bool {
+	denied := false
+	for _, result := range response.Results {
+		switch result.Action {
+		case repository2.PolicyActionAllow:
+			return false
+		case repository2.PolicyActionDeny:
+			denied = true
+		}
+	}
+	return denied
+}
+
+func (response *EvaluationResponse) GetWarnings() []string {
+	return response.getMessages(repository2.PolicyActionWarn)
+}
+
+func (response *EvaluationResponse) GetDenyMessages() []string {
+	return response.getMessages(repository2.PolicyActionDeny)
+}
+
+func (response *EvaluationResponse) getMessages(action repository2.PolicyAction) []string {
+	messages := make([]string, 0)
+	for _, result := range response.Results {
+		if result.Action == action {
+			messages = append(messages, result.Message)
+		}
+	}
+	return messages
+}
+
+// DeploymentDeniedError is returned when a deployment is denied by deployment admission policies
+type DeploymentDeniedError struct {
+	Results []*PolicyEvaluationResult `json:"results"`
+}
+
+func NewDeploymentDeniedError(response *EvaluationResponse) *DeploymentDeniedError {
+	return &DeploymentDeniedError{
+		Results: response.Results,
+	}
+}
+
+func (e *DeploymentDeniedError) Error() string {
+	messages := (&EvaluationResponse{Results: e.Results}).GetDenyMessages()
+	return fmt.Sprintf("deployment denied by admission policy: %s", strings.Join(messages, "; "))
+}
+
+// ToApiError keeps the evaluation results in user message so that it can be shown in the trigger response
+func (e *DeploymentDeniedError) ToApiError() *util.ApiError {
+	return util.NewApiError(http.StatusUnprocessableEntity, e.Error(), e.Error()).WithUserMessage(e)
+}
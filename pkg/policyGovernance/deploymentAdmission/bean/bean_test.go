@@ -0,0 +1,44 @@
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestEvaluationResponse_IsDenied(t *testing.T) {
+	allow := &PolicyEvaluationResult{PolicyId: 1, Action: repository.PolicyActionAllow, Message: "hotfix"}
+	deny := &PolicyEvaluationResult{PolicyId: 2, Action: repository.PolicyActionDeny, Message: "freeze"}
+	warn := &PolicyEvaluationResult{PolicyId: 3, Action: repository.PolicyActionWarn, Message: "after hours"}
+	tests := []struct {
+		name    string
+		results []*PolicyEvaluationResult
+		want    bool
+	}{
+		{name: "no matched policies", results: nil, want: false},
+		{name: "only warnings", results: []*PolicyEvaluationResult{warn}, want: false},
+		{name: "deny matched", results: []*PolicyEvaluationResult{warn, deny}, want: true},
+		{name: "allow overrides deny", results: []*PolicyEvaluationResult{deny, allow}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &EvaluationResponse{Results: tt.results}
+			assert.Equal(t, tt.want, response.IsDenied())
+		})
+	}
+}
+
+func TestDeploymentDeniedError(t *testing.T) {
+	response := &EvaluationResponse{Results: []*PolicyEvaluationResult{
+		{PolicyId: 1, Action: repository.PolicyActionDeny, Message: "prod freeze"},
+		{PolicyId: 2, Action: repository.PolicyActionWarn, Message: "after hours"},
+		{PolicyId: 3, Action: repository.PolicyActionDeny, Message: "unsigned image"},
+	}}
+	err := NewDeploymentDeniedError(response)
+	assert.Equal(t, "deployment denied by admission policy: prod freeze; unsigned image", err.Error())
+	assert.Equal(t, []string{"after hours"}, response.GetWarnings())
+	apiErr := err.ToApiError()
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.HttpStatusCode)
+	assert.Equal(t, err, apiErr.UserMessage)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type PolicyAction string
+
+const (
+	PolicyActionAllow PolicyAction = "ALLOW"
+	PolicyActionDeny  PolicyAction = "DENY"
+	PolicyActionWarn  PolicyAction = "WARN"
+)
+
+func (action PolicyAction) IsValid() bool {
+	return action == PolicyActionAllow || action == PolicyActionDeny || action == PolicyActionWarn
+}
+
+// DeploymentAdmissionPolicy is a named CEL rule evaluated before cd triggers, scope of the policy is
+// maintained in resource_qualifier_mapping with resource type resourceQualifiers.DeploymentAdmission
+type DeploymentAdmissionPolicy struct {
+	tableName   struct{}     `sql:"deployment_admission_policy" pg:",discard_unknown_columns"`
+	Id          int          `sql:"id,pk"`
+	Name        string       `sql:"name,notnull"`
+	Description string       `sql:"description"`
+	Expression  string       `sql:"expression,notnull"`
+	Action      PolicyAction `sql:"action,notnull"`
+	Message     string       `sql:"message"`
+	Enabled     bool         `sql:"enabled,notnull"`
+	Deleted     bool         `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentAdmissionPolicyRepository interface {
+	Save(tx *pg.Tx, model *DeploymentAdmissionPolicy) error
+	Update(tx *pg.Tx, model *DeploymentAdmissionPolicy) error
+	FindById(id int) (*DeploymentAdmissionPolicy, error)
+	FindByName(name string) (*DeploymentAdmissionPolicy, error)
+	FindAll() ([]*DeploymentAdmissionPolicy, error)
+	FindAllEnabled() ([]*DeploymentAdmissionPolicy, error)
+}
+
+type DeploymentAdmissionPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentAdmissionPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentAdmissionPolicyRepositoryImpl {
+	return &DeploymentAdmissionPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) Save(tx *pg.Tx, model *DeploymentAdmissionPolicy) error {
+	return tx.Insert(model)
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) Update(tx *pg.Tx, model *DeploymentAdmissionPolicy) error {
+	return tx.Update(model)
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) FindById(id int) (*DeploymentAdmissionPolicy, error) {
+	model := &DeploymentAdmissionPolicy{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) FindByName(name string) (*DeploymentAdmissionPolicy, error) {
+	model := &DeploymentAdmissionPolicy{}
+	err := impl.dbConnection.Model(model).
+		Where("name = ?", name).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) FindAll() ([]*DeploymentAdmissionPolicy, error) {
+	var models []*DeploymentAdmissionPolicy
+	err := impl.dbConnection.Model(&models).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentAdmissionPolicyRepositoryImpl) FindAllEnabled() ([]*DeploymentAdmissionPolicy, error) {
+	var models []*DeploymentAdmissionPolicy
+	err := impl.dbConnection.Model(&models).
+		Where("enabled = ?", true).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return models, err
+}
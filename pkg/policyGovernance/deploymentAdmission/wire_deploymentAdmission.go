@@ -0,0 +1,13 @@
+package deploymentAdmission
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission/repository"
+	"github.com/google/wire"
+)
+
+var DeploymentAdmissionWireSet = wire.NewSet(
+	NewDeploymentAdmissionPolicyServiceImpl,
+	wire.Bind(new(DeploymentAdmissionPolicyService), new(*DeploymentAdmissionPolicyServiceImpl)),
+	repository.NewDeploymentAdmissionPolicyRepositoryImpl,
+	wire.Bind(new(repository.DeploymentAdmissionPolicyRepository), new(*repository.DeploymentAdmissionPolicyRepositoryImpl)),
+)
@@ -0,0 +1,362 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeploymentWindowExceptionService manages requests to deploy on a cd pipeline while its deployment windows block deployments.
+// Every action on an exception is recorded in the exception audit trail.
+type DeploymentWindowExceptionService interface {
+	RequestException(request *bean.ExceptionRequest) (*bean.ExceptionDto, error)
+	ApproveException(request *bean.ExceptionReviewRequest) (*bean.ExceptionDto, error)
+	RejectException(request *bean.ExceptionReviewRequest) (*bean.ExceptionDto, error)
+	RevokeException(exceptionId int, userId int32) error
+	GetExceptionById(exceptionId int) (*repository.DeploymentWindowException, error)
+	GetExceptionsByPipelineId(cdPipelineId int) ([]*bean.ExceptionDto, error)
+	GetPendingExceptions() ([]*bean.ExceptionDto, error)
+	// GetActiveException returns nil if no approved exception is valid for the pipeline at the given time
+	GetActiveException(cdPipelineId int, at time.Time) (*repository.DeploymentWindowException, error)
+	RecordExceptionApplied(exceptionId, cdWorkflowRunnerId int, userId int32) error
+}
+
+type DeploymentWindowExceptionServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	deploymentWindowExceptionRepository repository.DeploymentWindowExceptionRepository
+	pipelineRepository                  pipelineConfig.PipelineRepository
+	userRepository                      userRepository.UserRepository
+	transactionManager                  sql.TransactionWrapper
+	config                              *bean.DeploymentWindowConfig
+}
+
+func NewDeploymentWindowExceptionServiceImpl(logger *zap.SugaredLogger,
+	deploymentWindowExceptionRepository repository.DeploymentWindowExceptionRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	userRepository userRepository.UserRepository,
+	transactionManager sql.TransactionWrapper) (*DeploymentWindowExceptionServiceImpl, error) {
+	config := &bean.DeploymentWindowConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing deployment window config", "err", err)
+		return nil, err
+	}
+	return &DeploymentWindowExceptionServiceImpl{
+		logger:                              logger,
+		deploymentWindowExceptionRepository: deploymentWindowExceptionRepository,
+		pipelineRepository:                  pipelineRepository,
+		userRepository:                      userRepository,
+		transactionManager:                  transactionManager,
+		config:                              config,
+	}, nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) RequestException(request *bean.ExceptionRequest) (*bean.ExceptionDto, error) {
+	if len(strings.TrimSpace(request.Reason)) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "reason is required for requesting deployment window exception", "reason is required for requesting deployment window exception")
+	}
+	if request.DurationInMinutes <= 0 || request.DurationInMinutes > impl.config.MaxExceptionDurationInMinutes {
+		errMsg := fmt.Sprintf("exception duration should be between 1 and %d minutes", impl.config.MaxExceptionDurationInMinutes)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(request.CdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "cdPipelineId", request.CdPipelineId, "err", err)
+		return nil, err
+	}
+	if cdPipeline.AppId != request.AppId {
+		errMsg := fmt.Sprintf("cd pipeline %d does not belong to app %d", request.CdPipelineId, request.AppId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	now := time.Now()
+	validFrom := now
+	if request.ValidFrom != nil && request.ValidFrom.After(now) {
+		validFrom = *request.ValidFrom
+	}
+
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	exception := &repository.DeploymentWindowException{
+		CdPipelineId: cdPipeline.Id,
+		Reason:       request.Reason,
+		Status:       repository.ExceptionStatusPending,
+		ValidFrom:    validFrom,
+		ValidTill:    validFrom.Add(time.Duration(request.DurationInMinutes) * time.Minute),
+		AuditLog:     sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.deploymentWindowExceptionRepository.Save(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window exception", "exception", exception, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(tx, exception.Id, repository.ExceptionRequested, 0, request.Reason, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.buildExceptionDto(exception)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) ApproveException(request *bean.ExceptionReviewRequest) (*bean.ExceptionDto, error) {
+	exception, err := impl.getPendingException(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if exception.CreatedBy == request.UserId {
+		return nil, util.NewApiError(http.StatusForbidden, "exception cannot be approved by the requester", "exception cannot be approved by the requester")
+	}
+	if exception.IsExpired() {
+		errMsg := fmt.Sprintf("exception %d has already expired", exception.Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return impl.reviewException(exception, repository.ExceptionStatusApproved, repository.ExceptionApproved, request)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) RejectException(request *bean.ExceptionReviewRequest) (*bean.ExceptionDto, error) {
+	exception, err := impl.getPendingException(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.reviewException(exception, repository.ExceptionStatusRejected, repository.ExceptionRejected, request)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) RevokeException(exceptionId int, userId int32) error {
+	exception, err := impl.GetExceptionById(exceptionId)
+	if err != nil {
+		return err
+	}
+	if exception.Status != repository.ExceptionStatusPending && exception.Status != repository.ExceptionStatusApproved {
+		errMsg := fmt.Sprintf("exception %d is already %s", exceptionId, strings.ToLower(string(exception.Status)))
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	exception.Status = repository.ExceptionStatusRevoked
+	exception.UpdateAuditLog(userId)
+	err = impl.deploymentWindowExceptionRepository.Update(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in revoking deployment window exception", "exceptionId", exceptionId, "err", err)
+		return err
+	}
+	err = impl.saveAudit(tx, exception.Id, repository.ExceptionRevoked, 0, "", userId)
+	if err != nil {
+		return err
+	}
+	return impl.transactionManager.CommitTx(tx)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) GetExceptionById(exceptionId int) (*repository.DeploymentWindowException, error) {
+	exception, err := impl.deploymentWindowExceptionRepository.FindById(exceptionId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "exception not found", "exception not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment window exception", "exceptionId", exceptionId, "err", err)
+		return nil, err
+	}
+	return exception, nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) GetExceptionsByPipelineId(cdPipelineId int) ([]*bean.ExceptionDto, error) {
+	exceptions, err := impl.deploymentWindowExceptionRepository.FindAllByPipelineId(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window exceptions", "cdPipelineId", cdPipelineId, "err", err)
+		return nil, err
+	}
+	return impl.buildExceptionDtos(exceptions)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) GetPendingExceptions() ([]*bean.ExceptionDto, error) {
+	exceptions, err := impl.deploymentWindowExceptionRepository.FindAllByStatus(repository.ExceptionStatusPending)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pending deployment window exceptions", "err", err)
+		return nil, err
+	}
+	return impl.buildExceptionDtos(exceptions)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) GetActiveException(cdPipelineId int, at time.Time) (*repository.DeploymentWindowException, error) {
+	exception, err := impl.deploymentWindowExceptionRepository.FindActiveByPipelineId(cdPipelineId, at)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching active deployment window exception", "cdPipelineId", cdPipelineId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) {
+		return nil, nil
+	}
+	return exception, nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) RecordExceptionApplied(exceptionId, cdWorkflowRunnerId int, userId int32) error {
+	return impl.saveAudit(nil, exceptionId, repository.ExceptionApplied, cdWorkflowRunnerId, "", userId)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) getPendingException(exceptionId int) (*repository.DeploymentWindowException, error) {
+	exception, err := impl.GetExceptionById(exceptionId)
+	if err != nil {
+		return nil, err
+	}
+	if exception.Status != repository.ExceptionStatusPending {
+		errMsg := fmt.Sprintf("exception %d is already %s", exceptionId, strings.ToLower(string(exception.Status)))
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return exception, nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) reviewException(exception *repository.DeploymentWindowException, status repository.ExceptionStatus,
+	action repository.ExceptionAuditAction, request *bean.ExceptionReviewRequest) (*bean.ExceptionDto, error) {
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	exception.Status = status
+	exception.ReviewedBy = request.UserId
+	exception.ReviewedOn = time.Now()
+	exception.ReviewComment = request.Comment
+	exception.UpdateAuditLog(request.UserId)
+	err = impl.deploymentWindowExceptionRepository.Update(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window exception", "exceptionId", exception.Id, "status", status, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(tx, exception.Id, action, 0, request.Comment, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.buildExceptionDto(exception)
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) saveAudit(tx *pg.Tx, exceptionId int, action repository.ExceptionAuditAction,
+	cdWorkflowRunnerId int, comment string, userId int32) error {
+	audit := &repository.DeploymentWindowExceptionAudit{
+		ExceptionId:        exceptionId,
+		Action:             action,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		Comment:            comment,
+		AuditLog:           sql.NewDefaultAuditLog(userId),
+	}
+	err := impl.deploymentWindowExceptionRepository.SaveAudit(tx, audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window exception audit", "exceptionId", exceptionId, "action", action, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) buildExceptionDto(exception *repository.DeploymentWindowException) (*bean.ExceptionDto, error) {
+	dtos, err := impl.buildExceptionDtos([]*repository.DeploymentWindowException{exception})
+	if err != nil {
+		return nil, err
+	}
+	return dtos[0], nil
+}
+
+func (impl *DeploymentWindowExceptionServiceImpl) buildExceptionDtos(exceptions []*repository.DeploymentWindowException) ([]*bean.ExceptionDto, error) {
+	dtos := make([]*bean.ExceptionDto, 0, len(exceptions))
+	if len(exceptions) == 0 {
+		return dtos, nil
+	}
+	exceptionIds := make([]int, 0, len(exceptions))
+	userIds := make([]int32, 0, len(exceptions))
+	for _, exception := range exceptions {
+		exceptionIds = append(exceptionIds, exception.Id)
+		userIds = append(userIds, exception.CreatedBy)
+		if exception.ReviewedBy > 0 {
+			userIds = append(userIds, exception.ReviewedBy)
+		}
+	}
+	audits, err := impl.deploymentWindowExceptionRepository.FindAuditsByExceptionIds(exceptionIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window exception audits", "exceptionIds", exceptionIds, "err", err)
+		return nil, err
+	}
+	for _, audit := range audits {
+		userIds = append(userIds, audit.CreatedBy)
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users by ids", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	userEmailMap := make(map[int32]string, len(users))
+	for _, user := range users {
+		userEmailMap[user.Id] = user.EmailId
+	}
+	exceptionIdToAuditsMap := make(map[int][]*bean.ExceptionAuditDto)
+	for _, audit := range audits {
+		exceptionIdToAuditsMap[audit.ExceptionId] = append(exceptionIdToAuditsMap[audit.ExceptionId], &bean.ExceptionAuditDto{
+			Action:             audit.Action,
+			CdWorkflowRunnerId: audit.CdWorkflowRunnerId,
+			Comment:            audit.Comment,
+			ActionBy:           userEmailMap[audit.CreatedBy],
+			ActionOn:           audit.CreatedOn,
+		})
+	}
+	for _, exception := range exceptions {
+		dto := &bean.ExceptionDto{
+			Id:            exception.Id,
+			CdPipelineId:  exception.CdPipelineId,
+			Reason:        exception.Reason,
+			Status:        exception.Status,
+			ValidFrom:     exception.ValidFrom,
+			ValidTill:     exception.ValidTill,
+			RequestedBy:   userEmailMap[exception.CreatedBy],
+			RequestedOn:   exception.CreatedOn,
+			ReviewComment: exception.ReviewComment,
+			AuditTrail:    exceptionIdToAuditsMap[exception.Id],
+		}
+		if exception.ReviewedBy > 0 {
+			reviewedOn := exception.ReviewedOn
+			dto.ReviewedBy = userEmailMap[exception.ReviewedBy]
+			dto.ReviewedOn = &reviewedOn
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
@@ -0,0 +1,502 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	devtronResourceBean "github.com/devtron-labs/devtron/pkg/devtronResource/bean"
+	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/helper"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeploymentWindowService manages allowed windows and blackout periods attached to environments and projects,
+// and evaluates them for cd deployment triggers
+type DeploymentWindowService interface {
+	CreateWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	UpdateWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	DeleteWindow(windowId int, userId int32) error
+	GetWindowById(windowId int) (*bean.DeploymentWindowDto, error)
+	GetAllWindows() ([]*bean.DeploymentWindowDto, error)
+	// EvaluateWindows evaluates the windows applicable on the pipeline at the given time, considering approved exceptions
+	EvaluateWindows(cdPipeline *pipelineConfig.Pipeline, at time.Time) (*bean.WindowEvaluationResponse, error)
+	// CheckDeploymentAllowed returns *bean.DeploymentWindowBlockedError if the deployment is blocked by deployment windows,
+	// usage of an exception for allowing the deployment is recorded in the exception audit trail
+	CheckDeploymentAllowed(request *bean.CheckDeploymentRequest) error
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	deploymentWindowRepository          repository.DeploymentWindowRepository
+	deploymentWindowExceptionService    DeploymentWindowExceptionService
+	qualifierMappingService             resourceQualifiers.QualifierMappingService
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService
+	appRepository                       app.AppRepository
+	transactionManager                  sql.TransactionWrapper
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger,
+	deploymentWindowRepository repository.DeploymentWindowRepository,
+	deploymentWindowExceptionService DeploymentWindowExceptionService,
+	qualifierMappingService resourceQualifiers.QualifierMappingService,
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService,
+	appRepository app.AppRepository,
+	transactionManager sql.TransactionWrapper) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                              logger,
+		deploymentWindowRepository:          deploymentWindowRepository,
+		deploymentWindowExceptionService:    deploymentWindowExceptionService,
+		qualifierMappingService:             qualifierMappingService,
+		devtronResourceSearchableKeyService: devtronResourceSearchableKeyService,
+		appRepository:                       appRepository,
+		transactionManager:                  transactionManager,
+	}
+}
+
+func (impl *DeploymentWindowServiceImpl) CreateWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	window := &repository.DeploymentWindow{
+		AuditLog: sql.NewDefaultAuditLog(request.UserId),
+	}
+	setWindowFields(window, request)
+	err := impl.validateWindow(window, request.Scope)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	err = impl.deploymentWindowRepository.Save(tx, window)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window", "window", window, "err", err)
+		return nil, err
+	}
+	err = impl.createScopeMappings(tx, window.Id, request.Scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Id = window.Id
+	request.Timezone = window.Timezone
+	return request, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) UpdateWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	window, err := impl.deploymentWindowRepository.FindById(request.Id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "deployment window not found", "deployment window not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "windowId", request.Id, "err", err)
+		return nil, err
+	}
+	setWindowFields(window, request)
+	err = impl.validateWindow(window, request.Scope)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	window.UpdateAuditLog(request.UserId)
+	err = impl.deploymentWindowRepository.Update(tx, window)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window", "window", window, "err", err)
+		return nil, err
+	}
+	err = impl.deleteScopeMappings(tx, window.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.createScopeMappings(tx, window.Id, request.Scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Timezone = window.Timezone
+	return request, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) DeleteWindow(windowId int, userId int32) error {
+	window, err := impl.deploymentWindowRepository.FindById(windowId)
+	if util.IsErrNoRows(err) {
+		return util.NewApiError(http.StatusNotFound, "deployment window not found", "deployment window not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "windowId", windowId, "err", err)
+		return err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	window.Deleted = true
+	window.UpdateAuditLog(userId)
+	err = impl.deploymentWindowRepository.Update(tx, window)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window", "windowId", windowId, "err", err)
+		return err
+	}
+	err = impl.deleteScopeMappings(tx, window.Id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.transactionManager.CommitTx(tx)
+}
+
+func (impl *DeploymentWindowServiceImpl) GetWindowById(windowId int) (*bean.DeploymentWindowDto, error) {
+	window, err := impl.deploymentWindowRepository.FindById(windowId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "deployment window not found", "deployment window not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "windowId", windowId, "err", err)
+		return nil, err
+	}
+	windowDtos, err := impl.buildWindowDtos([]*repository.DeploymentWindow{window})
+	if err != nil {
+		return nil, err
+	}
+	return windowDtos[0], nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetAllWindows() ([]*bean.DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err)
+		return nil, err
+	}
+	return impl.buildWindowDtos(windows)
+}
+
+func (impl *DeploymentWindowServiceImpl) EvaluateWindows(cdPipeline *pipelineConfig.Pipeline, at time.Time) (*bean.WindowEvaluationResponse, error) {
+	response := &bean.WindowEvaluationResponse{
+		CdPipelineId: cdPipeline.Id,
+		EvaluatedAt:  at,
+		Allowed:      true,
+	}
+	windows, err := impl.getApplicableWindows(cdPipeline)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return response, nil
+	}
+	blockingWindows, err := getBlockingWindows(windows, at)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment windows", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	if len(blockingWindows) == 0 {
+		return response, nil
+	}
+	exception, err := impl.deploymentWindowExceptionService.GetActiveException(cdPipeline.Id, at)
+	if err != nil {
+		return nil, err
+	}
+	if exception != nil {
+		response.ExceptionId = exception.Id
+		return response, nil
+	}
+	response.Allowed = false
+	response.BlockingWindows = blockingWindows
+	return response, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) CheckDeploymentAllowed(request *bean.CheckDeploymentRequest) error {
+	response, err := impl.EvaluateWindows(request.Pipeline, request.TriggeredAt)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment windows", "cdPipelineId", request.Pipeline.Id, "err", err)
+		return err
+	}
+	if !response.Allowed {
+		return bean.NewDeploymentWindowBlockedError(response)
+	}
+	if response.IsAllowedThroughException() {
+		impl.logger.Infow("deploying through deployment window exception", "exceptionId", response.ExceptionId, "cdPipelineId", request.Pipeline.Id, "wfrId", request.CdWorkflowRunnerId)
+		err = impl.deploymentWindowExceptionService.RecordExceptionApplied(response.ExceptionId, request.CdWorkflowRunnerId, request.TriggeredBy)
+		if err != nil {
+			impl.logger.Errorw("error in recording applied deployment window exception", "exceptionId", response.ExceptionId, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getBlockingWindows returns open blackout windows, or all allowed windows if none of them is open
+func getBlockingWindows(windows []*repository.DeploymentWindow, at time.Time) ([]*bean.WindowDetail, error) {
+	blackouts := make([]*bean.WindowDetail, 0)
+	closedAllowedWindows := make([]*bean.WindowDetail, 0)
+	isAnyAllowedWindowOpen := false
+	for _, window := range windows {
+		isOpen, closesAt, err := helper.IsWindowOpen(window, at)
+		if err != nil {
+			return nil, fmt.Errorf("error in evaluating deployment window %s: %s", window.Name, err.Error())
+		}
+		switch window.WindowType {
+		case repository.WindowTypeBlackout:
+			if isOpen {
+				blackouts = append(blackouts, &bean.WindowDetail{
+					Id:       window.Id,
+					Name:     window.Name,
+					Type:     window.WindowType,
+					ClosesAt: &closesAt,
+				})
+			}
+		case repository.WindowTypeAllowed:
+			if isOpen {
+				isAnyAllowedWindowOpen = true
+				continue
+			}
+			windowDetail := &bean.WindowDetail{
+				Id:   window.Id,
+				Name: window.Name,
+				Type: window.WindowType,
+			}
+			nextOpensAt, err := helper.GetNextOpenTime(window, at)
+			if err != nil {
+				return nil, fmt.Errorf("error in evaluating deployment window %s: %s", window.Name, err.Error())
+			}
+			if !nextOpensAt.IsZero() {
+				windowDetail.NextOpensAt = &nextOpensAt
+			}
+			closedAllowedWindows = append(closedAllowedWindows, windowDetail)
+		}
+	}
+	if len(blackouts) > 0 {
+		return blackouts, nil
+	}
+	if len(closedAllowedWindows) > 0 && !isAnyAllowedWindowOpen {
+		return closedAllowedWindows, nil
+	}
+	return nil, nil
+}
+
+// getApplicableWindows returns the enabled windows scoped to the environment or the project of the cd pipeline,
+// windows can be scoped to environments and projects only
+func (impl *DeploymentWindowServiceImpl) getApplicableWindows(cdPipeline *pipelineConfig.Pipeline) ([]*repository.DeploymentWindow, error) {
+	projectId := cdPipeline.App.TeamId
+	if cdPipeline.App.Id == 0 {
+		pipelineApp, err := impl.appRepository.FindById(cdPipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app", "appId", cdPipeline.AppId, "err", err)
+			return nil, err
+		}
+		projectId = pipelineApp.TeamId
+	}
+	qualifierValues := map[resourceQualifiers.Qualifier][]int{
+		resourceQualifiers.ENV_QUALIFIER:     {cdPipeline.EnvironmentId},
+		resourceQualifiers.PROJECT_QUALIFIER: {projectId},
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappingsByQualifierValues(resourceQualifiers.DeploymentWindow, qualifierValues)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window mappings", "cdPipelineId", cdPipeline.Id, "err", err)
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	windowIds := make([]int, 0)
+	for _, mapping := range mappings {
+		switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+		case resourceQualifiers.ENV_QUALIFIER:
+			if mapping.IdentifierValueInt == cdPipeline.EnvironmentId {
+				windowIds = append(windowIds, mapping.ResourceId)
+			}
+		case resourceQualifiers.PROJECT_QUALIFIER:
+			if mapping.IdentifierValueInt == projectId {
+				windowIds = append(windowIds, mapping.ResourceId)
+			}
+		}
+	}
+	windows, err := impl.deploymentWindowRepository.FindAllEnabledByIds(windowIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment windows", "windowIds", windowIds, "err", err)
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) validateWindow(window *repository.DeploymentWindow, scope *bean.WindowScope) error {
+	if !window.WindowType.IsValid() {
+		errMsg := fmt.Sprintf("invalid window type %s, allowed types are %s and %s", window.WindowType, repository.WindowTypeAllowed, repository.WindowTypeBlackout)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if scope == nil || scope.IsEmpty() {
+		return util.NewApiError(http.StatusBadRequest, "deployment window should be attached to at least one environment or project", "deployment window scope cannot be empty")
+	}
+	err := helper.ValidateSchedule(window)
+	if err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	existingWindow, err := impl.deploymentWindowRepository.FindByName(window.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window by name", "name", window.Name, "err", err)
+		return err
+	} else if err == nil && existingWindow.Id != window.Id {
+		errMsg := fmt.Sprintf("deployment window with name %s already exists", window.Name)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) createScopeMappings(tx *pg.Tx, windowId int, scope *bean.WindowScope, userId int32) error {
+	searchableKeyNameIdMap := impl.devtronResourceSearchableKeyService.GetAllSearchableKeyNameIdMap()
+	mappings := make([]*resourceQualifiers.QualifierMapping, 0, len(scope.EnvIds)+len(scope.ProjectIds))
+	newMapping := func(qualifier resourceQualifiers.Qualifier, identifierKey int, identifierValue int) *resourceQualifiers.QualifierMapping {
+		return &resourceQualifiers.QualifierMapping{
+			ResourceId:         windowId,
+			ResourceType:       resourceQualifiers.DeploymentWindow,
+			QualifierId:        int(qualifier),
+			IdentifierKey:      identifierKey,
+			IdentifierValueInt: identifierValue,
+			Active:             true,
+			AuditLog:           sql.NewDefaultAuditLog(userId),
+		}
+	}
+	for _, envId := range scope.EnvIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.ENV_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID], envId))
+	}
+	for _, projectId := range scope.ProjectIds {
+		mappings = append(mappings, newMapping(resourceQualifiers.PROJECT_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID], projectId))
+	}
+	_, err := impl.qualifierMappingService.CreateQualifierMappings(mappings, tx)
+	if err != nil {
+		impl.logger.Errorw("error in creating deployment window scope mappings", "windowId", windowId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) deleteScopeMappings(tx *pg.Tx, windowId int, userId int32) error {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentWindow, nil, []int{windowId})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window scope mappings", "windowId", windowId, "err", err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	mappingIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingIds = append(mappingIds, mapping.Id)
+	}
+	err = impl.qualifierMappingService.DeleteAllByIds(mappingIds, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window scope mappings", "windowId", windowId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) buildWindowDtos(windows []*repository.DeploymentWindow) ([]*bean.DeploymentWindowDto, error) {
+	windowDtos := make([]*bean.DeploymentWindowDto, 0, len(windows))
+	if len(windows) == 0 {
+		return windowDtos, nil
+	}
+	windowIds := make([]int, 0, len(windows))
+	for _, window := range windows {
+		windowIds = append(windowIds, window.Id)
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentWindow, nil, windowIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment window scope mappings", "windowIds", windowIds, "err", err)
+		return nil, err
+	}
+	windowIdToScope := make(map[int]*bean.WindowScope, len(windows))
+	for _, window := range windows {
+		windowIdToScope[window.Id] = &bean.WindowScope{}
+	}
+	for _, mapping := range mappings {
+		scope, ok := windowIdToScope[mapping.ResourceId]
+		if !ok {
+			continue
+		}
+		switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+		case resourceQualifiers.ENV_QUALIFIER:
+			scope.EnvIds = append(scope.EnvIds, mapping.IdentifierValueInt)
+		case resourceQualifiers.PROJECT_QUALIFIER:
+			scope.ProjectIds = append(scope.ProjectIds, mapping.IdentifierValueInt)
+		}
+	}
+	for _, window := range windows {
+		windowDto := &bean.DeploymentWindowDto{
+			Id:                window.Id,
+			Name:              window.Name,
+			Description:       window.Description,
+			Type:              window.WindowType,
+			Timezone:          window.Timezone,
+			CronExpression:    window.CronExpression,
+			DurationInMinutes: window.DurationInMinutes,
+			Enabled:           window.Enabled,
+			Scope:             windowIdToScope[window.Id],
+		}
+		if !window.IsRecurring() {
+			startTime, endTime := window.StartTime, window.EndTime
+			windowDto.StartTime = &startTime
+			windowDto.EndTime = &endTime
+		}
+		windowDtos = append(windowDtos, windowDto)
+	}
+	return windowDtos, nil
+}
+
+func setWindowFields(window *repository.DeploymentWindow, request *bean.DeploymentWindowDto) {
+	window.Name = strings.TrimSpace(request.Name)
+	window.Description = request.Description
+	window.WindowType = request.Type
+	window.Timezone = request.Timezone
+	if len(window.Timezone) == 0 {
+		window.Timezone = time.UTC.String()
+	}
+	window.CronExpression = strings.TrimSpace(request.CronExpression)
+	window.DurationInMinutes = request.DurationInMinutes
+	window.StartTime, window.EndTime = time.Time{}, time.Time{}
+	if request.StartTime != nil {
+		window.StartTime = *request.StartTime
+	}
+	if request.EndTime != nil {
+		window.EndTime = *request.EndTime
+	}
+	window.Enabled = request.Enabled
+}
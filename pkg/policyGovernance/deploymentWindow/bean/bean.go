@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CATEGORY=CD
+type DeploymentWindowConfig struct {
+	MaxExceptionDurationInMinutes int `env:"DEPLOYMENT_WINDOW_EXCEPTION_MAX_DURATION_MINS" envDefault:"1440" description:"Maximum time (in minutes) for which deployments can be allowed on a cd pipeline through a deployment window exception"`
+}
+
+// WindowScope defines where a deployment window is applicable, window applies if either the environment or the project matches
+type WindowScope struct {
+	EnvIds     []int `json:"envIds,omitempty"`
+	ProjectIds []int `json:"projectIds,omitempty"`
+}
+
+func (scope *WindowScope) IsEmpty() bool {
+	return len(scope.EnvIds) == 0 && len(scope.ProjectIds) == 0
+}
+
+type DeploymentWindowDto struct {
+	Id                int                   `json:"id"`
+	Name              string                `json:"name" validate:"required,max=250"`
+	Description       string                `json:"description"`
+	Type              repository.WindowType `json:"type" validate:"required"`
+	Timezone          string                `json:"timezone"`
+	CronExpression    string                `json:"cronExpression,omitempty"`
+	DurationInMinutes int                   `json:"durationInMinutes,omitempty"`
+	StartTime         *time.Time            `json:"startTime,omitempty"`
+	EndTime           *time.Time            `json:"endTime,omitempty"`
+	Enabled           bool                  `json:"enabled"`
+	Scope             *WindowScope          `json:"scope" validate:"required"`
+	UserId            int32                 `json:"-"`
+}
+
+// WindowDetail is a deployment window which affects a deployment at the evaluated time
+type WindowDetail struct {
+	Id          int                   `json:"id"`
+	Name        string                `json:"name"`
+	Type        repository.WindowType `json:"type"`
+	ClosesAt    *time.Time            `json:"closesAt,omitempty"`
+	NextOpensAt *time.Time            `json:"nextOpensAt,omitempty"`
+}
+
+// WindowEvaluationResponse is the result of evaluating deployment windows applicable on a cd pipeline.
+// A deployment is blocked if any applicable blackout is open, or allowed windows are applicable and none of them is open.
+// An approved exception valid at the evaluated time allows the deployment regardless of the windows.
+type WindowEvaluationResponse struct {
+	CdPipelineId    int             `json:"cdPipelineId"`
+	EvaluatedAt     time.Time       `json:"evaluatedAt"`
+	Allowed         bool            `json:"allowed"`
+	BlockingWindows []*WindowDetail `json:"blockingWindows,omitempty"`
+	ExceptionId     int             `json:"exceptionId,omitempty"`
+}
+
+func (response *WindowEvaluationResponse) IsAllowedThroughException() bool {
+	return response.Allowed && response.ExceptionId > 0
+}
+
+type CheckDeploymentRequest struct {
+	Pipeline           *pipelineConfig.Pipeline
+	TriggeredAt        time.Time
+	TriggeredBy        int32
+	CdWorkflowRunnerId int
+}
+
+// DeploymentWindowBlockedError is returned when a deployment is blocked by deployment windows
+type DeploymentWindowBlockedError struct {
+	CdPipelineId    int             `json:"cdPipelineId"`
+	BlockingWindows []*WindowDetail `json:"blockingWindows"`
+}
+
+func NewDeploymentWindowBlockedError(response *WindowEvaluationResponse) *DeploymentWindowBlockedError {
+	return &DeploymentWindowBlockedError{
+		CdPipelineId:    response.CdPipelineId,
+		BlockingWindows: response.BlockingWindows,
+	}
+}
+
+func (e *DeploymentWindowBlockedError) Error() string {
+	windowNames := make([]string, 0, len(e.BlockingWindows))
+	for _, window := range e.BlockingWindows {
+		windowNames = append(windowNames, window.Name)
+	}
+	return fmt.Sprintf("deployment blocked by deployment window: %s", strings.Join(windowNames, ", "))
+}
+
+// ToApiError keeps the blocking windows in user message so that it can be shown in the trigger response
+func (e *DeploymentWindowBlockedError) ToApiError() *util.ApiError {
+	return util.NewApiError(http.StatusUnprocessableEntity, e.Error(), e.Error()).
+		WithCode(constants.DeploymentWindowFail).
+		WithUserMessage(e)
+}
+
+type ExceptionRequest struct {
+	AppId             int        `json:"appId" validate:"required,number,gt=0"`
+	CdPipelineId      int        `json:"cdPipelineId" validate:"required,number,gt=0"`
+	Reason            string     `json:"reason" validate:"required,min=1"`
+	ValidFrom         *time.Time `json:"validFrom,omitempty"`
+	DurationInMinutes int        `json:"durationInMinutes" validate:"required,number,gt=0"`
+	UserId            int32      `json:"-"`
+}
+
+type ExceptionReviewRequest struct {
+	Id      int    `json:"-"`
+	Comment string `json:"comment"`
+	UserId  int32  `json:"-"`
+}
+
+type ExceptionDto struct {
+	Id            int                        `json:"id"`
+	CdPipelineId  int                        `json:"cdPipelineId"`
+	Reason        string                     `json:"reason"`
+	Status        repository.ExceptionStatus `json:"status"`
+	ValidFrom     time.Time                  `json:"validFrom"`
+	ValidTill     time.Time                  `json:"validTill"`
+	RequestedBy   string                     `json:"requestedBy"`
+	RequestedOn   time.Time                  `json:"requestedOn"`
+	ReviewedBy    string                     `json:"reviewedBy,omitempty"`
+	ReviewedOn    *time.Time                 `json:"reviewedOn,omitempty"`
+	ReviewComment string                     `json:"reviewComment,omitempty"`
+	AuditTrail    []*ExceptionAuditDto       `json:"auditTrail"`
+}
+
+type ExceptionAuditDto struct {
+	Action             repository.ExceptionAuditAction `json:"action"`
+	CdWorkflowRunnerId int                             `json:"cdWorkflowRunnerId,omitempty"`
+	Comment            string                          `json:"comment,omitempty"`
+	ActionBy           string                          `json:"actionBy"`
+	ActionOn           time.Time                       `json:"actionOn"`
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+// ValidateSchedule checks that the window has either a valid recurring schedule or a valid one-off time range
+func ValidateSchedule(window *repository.DeploymentWindow) error {
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s", window.Timezone)
+	}
+	if window.IsRecurring() {
+		if !window.StartTime.IsZero() || !window.EndTime.IsZero() {
+			return fmt.Errorf("start and end time cannot be given for a recurring window")
+		}
+		if _, err := cron.ParseStandard(window.CronExpression); err != nil {
+			return fmt.Errorf("invalid cron expression %s: %s", window.CronExpression, err.Error())
+		}
+		if window.DurationInMinutes <= 0 {
+			return fmt.Errorf("duration should be greater than 0 minutes for a recurring window")
+		}
+		return nil
+	}
+	if window.StartTime.IsZero() || window.EndTime.IsZero() {
+		return fmt.Errorf("either cron expression and duration or start and end time are required")
+	}
+	if !window.EndTime.After(window.StartTime) {
+		return fmt.Errorf("end time should be after start time")
+	}
+	return nil
+}
+
+// IsWindowOpen returns whether the window is open at the given time and the time at which the open window closes
+func IsWindowOpen(window *repository.DeploymentWindow, at time.Time) (bool, time.Time, error) {
+	if !window.IsRecurring() {
+		isOpen := !at.Before(window.StartTime) && at.Before(window.EndTime)
+		return isOpen, window.EndTime, nil
+	}
+	schedule, location, err := parseSchedule(window)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	duration := time.Duration(window.DurationInMinutes) * time.Minute
+	// window is open if it was started by any schedule tick in (at - duration, at],
+	// latest such tick is considered as occurrences can overlap if duration is longer than the schedule interval
+	var openedAt time.Time
+	for start := schedule.Next(at.In(location).Add(-duration)); !start.After(at); start = schedule.Next(start) {
+		openedAt = start
+	}
+	if openedAt.IsZero() {
+		return false, time.Time{}, nil
+	}
+	return true, openedAt.Add(duration), nil
+}
+
+// GetNextOpenTime returns the next time after the given time at which the window opens, zero time if it never opens again
+func GetNextOpenTime(window *repository.DeploymentWindow, after time.Time) (time.Time, error) {
+	if !window.IsRecurring() {
+		if window.StartTime.After(after) {
+			return window.StartTime, nil
+		}
+		return time.Time{}, nil
+	}
+	schedule, location, err := parseSchedule(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after.In(location)), nil
+}
+
+func parseSchedule(window *repository.DeploymentWindow) (cron.Schedule, *time.Location, error) {
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+	schedule, err := cron.ParseStandard(window.CronExpression)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, location, nil
+}
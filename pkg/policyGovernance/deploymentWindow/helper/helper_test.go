@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	start := time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		window  *repository.DeploymentWindow
+		wantErr bool
+	}{
+		{
+			name:   "valid recurring window",
+			window: &repository.DeploymentWindow{Timezone: "UTC", CronExpression: "0 9 * * 1-5", DurationInMinutes: 480},
+		},
+		{
+			name:    "invalid cron expression",
+			window:  &repository.DeploymentWindow{Timezone: "UTC", CronExpression: "0 9 * *", DurationInMinutes: 480},
+			wantErr: true,
+		},
+		{
+			name:    "recurring window without duration",
+			window:  &repository.DeploymentWindow{Timezone: "UTC", CronExpression: "0 9 * * *"},
+			wantErr: true,
+		},
+		{
+			name:    "recurring window with time range",
+			window:  &repository.DeploymentWindow{Timezone: "UTC", CronExpression: "0 9 * * *", DurationInMinutes: 60, StartTime: start, EndTime: start.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			window:  &repository.DeploymentWindow{Timezone: "Mars/Olympus", CronExpression: "0 9 * * *", DurationInMinutes: 60},
+			wantErr: true,
+		},
+		{
+			name:   "valid one-off window",
+			window: &repository.DeploymentWindow{Timezone: "UTC", StartTime: start, EndTime: start.Add(72 * time.Hour)},
+		},
+		{
+			name:    "one-off window ending before start",
+			window:  &repository.DeploymentWindow{Timezone: "UTC", StartTime: start, EndTime: start.Add(-time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "window without schedule",
+			window:  &repository.DeploymentWindow{Timezone: "UTC"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchedule(tt.window)
+			assert.Equal(t, tt.wantErr, err != nil, "err: %v", err)
+		})
+	}
+}
+
+func TestIsWindowOpen(t *testing.T) {
+	// weekdays 09:00 to 17:00 in Asia/Kolkata (UTC+05:30)
+	businessHours := &repository.DeploymentWindow{Timezone: "Asia/Kolkata", CronExpression: "0 9 * * 1-5", DurationInMinutes: 480}
+	// every hour for 90 minutes, consecutive occurrences overlap
+	overlapping := &repository.DeploymentWindow{Timezone: "UTC", CronExpression: "0 * * * *", DurationInMinutes: 90}
+	freezeStart := time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)
+	freeze := &repository.DeploymentWindow{Timezone: "UTC", StartTime: freezeStart, EndTime: freezeStart.Add(48 * time.Hour)}
+
+	tests := []struct {
+		name         string
+		window       *repository.DeploymentWindow
+		at           time.Time
+		wantOpen     bool
+		wantClosesAt time.Time
+	}{
+		{
+			name:         "recurring window open in its timezone",
+			window:       businessHours,
+			at:           time.Date(2024, 6, 3, 4, 0, 0, 0, time.UTC), // Monday 09:30 IST
+			wantOpen:     true,
+			wantClosesAt: time.Date(2024, 6, 3, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "recurring window closed before opening in its timezone",
+			window:   businessHours,
+			at:       time.Date(2024, 6, 3, 3, 0, 0, 0, time.UTC), // Monday 08:30 IST
+			wantOpen: false,
+		},
+		{
+			name:     "recurring window closed at end of duration",
+			window:   businessHours,
+			at:       time.Date(2024, 6, 3, 11, 30, 0, 0, time.UTC), // Monday 17:00 IST
+			wantOpen: false,
+		},
+		{
+			name:     "recurring window closed on weekend",
+			window:   businessHours,
+			at:       time.Date(2024, 6, 8, 6, 0, 0, 0, time.UTC), // Saturday 11:30 IST
+			wantOpen: false,
+		},
+		{
+			name:         "overlapping occurrences close with the latest occurrence",
+			window:       overlapping,
+			at:           time.Date(2024, 6, 3, 10, 15, 0, 0, time.UTC),
+			wantOpen:     true,
+			wantClosesAt: time.Date(2024, 6, 3, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "one-off window open at start",
+			window:       freeze,
+			at:           freezeStart,
+			wantOpen:     true,
+			wantClosesAt: freezeStart.Add(48 * time.Hour),
+		},
+		{
+			name:     "one-off window closed at end",
+			window:   freeze,
+			at:       freezeStart.Add(48 * time.Hour),
+			wantOpen: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isOpen, closesAt, err := IsWindowOpen(tt.window, tt.at)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantOpen, isOpen)
+			if tt.wantOpen {
+				assert.True(t, tt.wantClosesAt.Equal(closesAt), "expected closesAt %s, got %s", tt.wantClosesAt, closesAt)
+			}
+		})
+	}
+}
+
+func TestGetNextOpenTime(t *testing.T) {
+	businessHours := &repository.DeploymentWindow{Timezone: "Asia/Kolkata", CronExpression: "0 9 * * 1-5", DurationInMinutes: 480}
+	// Friday 18:00 IST, next opening is Monday 09:00 IST
+	nextOpen, err := GetNextOpenTime(businessHours, time.Date(2024, 6, 7, 12, 30, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2024, 6, 10, 3, 30, 0, 0, time.UTC).Equal(nextOpen), "got %s", nextOpen)
+
+	freezeStart := time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)
+	freeze := &repository.DeploymentWindow{Timezone: "UTC", StartTime: freezeStart, EndTime: freezeStart.Add(48 * time.Hour)}
+	nextOpen, err = GetNextOpenTime(freeze, freezeStart.Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, freezeStart.Equal(nextOpen))
+	nextOpen, err = GetNextOpenTime(freeze, freezeStart.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, nextOpen.IsZero())
+}
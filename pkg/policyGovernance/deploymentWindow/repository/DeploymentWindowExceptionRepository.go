@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ExceptionStatus string
+
+const (
+	ExceptionStatusPending  ExceptionStatus = "PENDING"
+	ExceptionStatusApproved ExceptionStatus = "APPROVED"
+	ExceptionStatusRejected ExceptionStatus = "REJECTED"
+	ExceptionStatusRevoked  ExceptionStatus = "REVOKED"
+)
+
+// DeploymentWindowException allows deployments on a cd pipeline between ValidFrom and ValidTill even if
+// the deployment windows applicable on the pipeline block it, exception is effective only once approved
+type DeploymentWindowException struct {
+	tableName     struct{}        `sql:"deployment_window_exception" pg:",discard_unknown_columns"`
+	Id            int             `sql:"id,pk"`
+	CdPipelineId  int             `sql:"cd_pipeline_id,notnull"`
+	Reason        string          `sql:"reason,notnull"`
+	Status        ExceptionStatus `sql:"status,notnull"`
+	ValidFrom     time.Time       `sql:"valid_from,notnull"`
+	ValidTill     time.Time       `sql:"valid_till,notnull"`
+	ReviewedBy    int32           `sql:"reviewed_by"`
+	ReviewedOn    time.Time       `sql:"reviewed_on"`
+	ReviewComment string          `sql:"review_comment"`
+	sql.AuditLog
+}
+
+func (exception *DeploymentWindowException) IsExpired() bool {
+	return !exception.ValidTill.After(time.Now())
+}
+
+type ExceptionAuditAction string
+
+const (
+	ExceptionRequested ExceptionAuditAction = "REQUESTED"
+	ExceptionApproved  ExceptionAuditAction = "APPROVED"
+	ExceptionRejected  ExceptionAuditAction = "REJECTED"
+	ExceptionRevoked   ExceptionAuditAction = "REVOKED"
+	ExceptionApplied   ExceptionAuditAction = "APPLIED"
+)
+
+// DeploymentWindowExceptionAudit keeps track of every action taken on a DeploymentWindowException
+type DeploymentWindowExceptionAudit struct {
+	tableName          struct{}             `sql:"deployment_window_exception_audit" pg:",discard_unknown_columns"`
+	Id                 int                  `sql:"id,pk"`
+	ExceptionId        int                  `sql:"exception_id,notnull"`
+	Action             ExceptionAuditAction `sql:"action,notnull"`
+	CdWorkflowRunnerId int                  `sql:"cd_workflow_runner_id"`
+	Comment            string               `sql:"comment"`
+	sql.AuditLog
+}
+
+type DeploymentWindowExceptionRepository interface {
+	Save(tx *pg.Tx, model *DeploymentWindowException) error
+	Update(tx *pg.Tx, model *DeploymentWindowException) error
+	FindById(id int) (*DeploymentWindowException, error)
+	// FindActiveByPipelineId returns the latest approved exception valid at the given time, pg.ErrNoRows if none exists
+	FindActiveByPipelineId(cdPipelineId int, at time.Time) (*DeploymentWindowException, error)
+	FindAllByPipelineId(cdPipelineId int) ([]*DeploymentWindowException, error)
+	FindAllByStatus(status ExceptionStatus) ([]*DeploymentWindowException, error)
+	SaveAudit(tx *pg.Tx, model *DeploymentWindowExceptionAudit) error
+	FindAuditsByExceptionIds(exceptionIds []int) ([]*DeploymentWindowExceptionAudit, error)
+}
+
+type DeploymentWindowExceptionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentWindowExceptionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentWindowExceptionRepositoryImpl {
+	return &DeploymentWindowExceptionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) Save(tx *pg.Tx, model *DeploymentWindowException) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) Update(tx *pg.Tx, model *DeploymentWindowException) error {
+	if tx != nil {
+		return tx.Update(model)
+	}
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) FindById(id int) (*DeploymentWindowException, error) {
+	model := &DeploymentWindowException{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) FindActiveByPipelineId(cdPipelineId int, at time.Time) (*DeploymentWindowException, error) {
+	model := &DeploymentWindowException{}
+	err := impl.dbConnection.Model(model).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Where("status = ?", ExceptionStatusApproved).
+		Where("valid_from <= ?", at).
+		Where("valid_till > ?", at).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) FindAllByPipelineId(cdPipelineId int) ([]*DeploymentWindowException, error) {
+	var models []*DeploymentWindowException
+	err := impl.dbConnection.Model(&models).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) FindAllByStatus(status ExceptionStatus) ([]*DeploymentWindowException, error) {
+	var models []*DeploymentWindowException
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) SaveAudit(tx *pg.Tx, model *DeploymentWindowExceptionAudit) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeploymentWindowExceptionRepositoryImpl) FindAuditsByExceptionIds(exceptionIds []int) ([]*DeploymentWindowExceptionAudit, error) {
+	var models []*DeploymentWindowExceptionAudit
+	if len(exceptionIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("exception_id in (?)", pg.In(exceptionIds)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type WindowType string
+
+const (
+	// WindowTypeAllowed deployments are allowed only while one of the applicable allowed windows is open
+	WindowTypeAllowed WindowType = "ALLOWED"
+	// WindowTypeBlackout deployments are blocked while any of the applicable blackout windows is open
+	WindowTypeBlackout WindowType = "BLACKOUT"
+)
+
+func (windowType WindowType) IsValid() bool {
+	return windowType == WindowTypeAllowed || windowType == WindowTypeBlackout
+}
+
+// DeploymentWindow is either a recurring window which opens on every CronExpression tick for DurationInMinutes,
+// or a one-off calendar entry from StartTime to EndTime. Environments and projects on which the window is applicable
+// are maintained in resource_qualifier_mapping with resource type resourceQualifiers.DeploymentWindow
+type DeploymentWindow struct {
+	tableName         struct{}   `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id                int        `sql:"id,pk"`
+	Name              string     `sql:"name,notnull"`
+	Description       string     `sql:"description"`
+	WindowType        WindowType `sql:"window_type,notnull"`
+	Timezone          string     `sql:"timezone,notnull"`
+	CronExpression    string     `sql:"cron_expression"`
+	DurationInMinutes int        `sql:"duration_in_minutes"`
+	StartTime         time.Time  `sql:"start_time"`
+	EndTime           time.Time  `sql:"end_time"`
+	Enabled           bool       `sql:"enabled,notnull"`
+	Deleted           bool       `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (window *DeploymentWindow) IsRecurring() bool {
+	return len(window.CronExpression) > 0
+}
+
+type DeploymentWindowRepository interface {
+	Save(tx *pg.Tx, model *DeploymentWindow) error
+	Update(tx *pg.Tx, model *DeploymentWindow) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindByName(name string) (*DeploymentWindow, error)
+	FindAll() ([]*DeploymentWindow, error)
+	FindAllEnabledByIds(ids []int) ([]*DeploymentWindow, error)
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentWindowRepositoryImpl) Save(tx *pg.Tx, model *DeploymentWindow) error {
+	return tx.Insert(model)
+}
+
+func (impl *DeploymentWindowRepositoryImpl) Update(tx *pg.Tx, model *DeploymentWindow) error {
+	return tx.Update(model)
+}
+
+func (impl *DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	model := &DeploymentWindow{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentWindowRepositoryImpl) FindByName(name string) (*DeploymentWindow, error) {
+	model := &DeploymentWindow{}
+	err := impl.dbConnection.Model(model).
+		Where("name = ?", name).
+		Where("deleted = ?", false).
+		Select()
+	return model, err
+}
+
+func (impl *DeploymentWindowRepositoryImpl) FindAll() ([]*DeploymentWindow, error) {
+	var models []*DeploymentWindow
+	err := impl.dbConnection.Model(&models).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *DeploymentWindowRepositoryImpl) FindAllEnabledByIds(ids []int) ([]*DeploymentWindow, error) {
+	var models []*DeploymentWindow
+	if len(ids) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("id in (?)", pg.In(ids)).
+		Where("enabled = ?", true).
+		Where("deleted = ?", false).
+		Select()
+	return models, err
+}
@@ -0,0 +1,547 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageScanning
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	repository1 "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	repository4 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/adapter"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CveExceptionService manages VEX style exceptions which exclude a cve from cve policy enforcement till their expiry
+type CveExceptionService interface {
+	CreateException(request *bean3.CveExceptionRequest) (*bean3.CveExceptionDto, error)
+	RenewException(request *bean3.CveExceptionRenewRequest) (*bean3.CveExceptionDto, error)
+	RevokeException(request *bean3.CveExceptionRevokeRequest) error
+	GetExceptionById(id int) (*repository3.CveException, error)
+	// GetException returns the exception along with its audit trail
+	GetException(id int) (*bean3.CveExceptionDto, error)
+	GetExceptionReport(request *bean3.CveExceptionReportRequest) ([]*bean3.CveExceptionDto, error)
+	// NotifyExpiringExceptions notifies once for every active exception expiring within the configured notice period, run by the cve exception cron
+	NotifyExpiringExceptions()
+}
+
+type CveExceptionServiceImpl struct {
+	logger                 *zap.SugaredLogger
+	cveExceptionRepository repository3.CveExceptionRepository
+	appRepository          repository1.AppRepository
+	envRepository          repository4.EnvironmentRepository
+	clusterRepository      clusterRepository.ClusterRepository
+	ciArtifactRepository   repository.CiArtifactRepository
+	ciPipelineRepository   pipelineConfig.CiPipelineRepository
+	userRepository         userRepository.UserRepository
+	eventClient            client.EventClient
+	eventFactory           client.EventFactory
+	transactionManager     sql.TransactionWrapper
+	config                 *bean3.CveExceptionConfig
+	cron                   *cron.Cron
+}
+
+func NewCveExceptionServiceImpl(logger *zap.SugaredLogger,
+	cveExceptionRepository repository3.CveExceptionRepository,
+	appRepository repository1.AppRepository,
+	envRepository repository4.EnvironmentRepository,
+	clusterRepository clusterRepository.ClusterRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	userRepository userRepository.UserRepository,
+	eventClient client.EventClient,
+	eventFactory client.EventFactory,
+	transactionManager sql.TransactionWrapper,
+	cronLogger *cron2.CronLoggerImpl) (*CveExceptionServiceImpl, error) {
+	config := &bean3.CveExceptionConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing cve exception config", "err", err)
+		return nil, err
+	}
+	impl := &CveExceptionServiceImpl{
+		logger:                 logger,
+		cveExceptionRepository: cveExceptionRepository,
+		appRepository:          appRepository,
+		envRepository:          envRepository,
+		clusterRepository:      clusterRepository,
+		ciArtifactRepository:   ciArtifactRepository,
+		ciPipelineRepository:   ciPipelineRepository,
+		userRepository:         userRepository,
+		eventClient:            eventClient,
+		eventFactory:           eventFactory,
+		transactionManager:     transactionManager,
+		config:                 config,
+	}
+	impl.cron = cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	_, err = impl.cron.AddFunc(config.CronTime, impl.NotifyExpiringExceptions)
+	if err != nil {
+		logger.Errorw("error in adding cve exception cron", "cronTime", config.CronTime, "err", err)
+		return nil, err
+	}
+	impl.cron.Start()
+	return impl, nil
+}
+
+func (impl *CveExceptionServiceImpl) CreateException(request *bean3.CveExceptionRequest) (*bean3.CveExceptionDto, error) {
+	request.CveName = strings.TrimSpace(request.CveName)
+	if len(request.CveName) == 0 || len(strings.TrimSpace(request.Justification)) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "cve name and justification are required", "cve name and justification are required")
+	}
+	err := validateExceptionType(request.ExceptionType, request.VexJustification)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validateExpiry(request.ExpiresOn)
+	if err != nil {
+		return nil, err
+	}
+	if request.OwnerId == 0 {
+		request.OwnerId = request.UserId
+	}
+	if _, err = impl.userRepository.GetById(request.OwnerId); util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusBadRequest, "owner of the exception should be an active user", "owner of the exception should be an active user")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching exception owner", "ownerId", request.OwnerId, "err", err)
+		return nil, err
+	}
+	exception := &repository3.CveException{
+		CveName:       request.CveName,
+		ExceptionType: request.ExceptionType,
+		Justification: request.Justification,
+		OwnerId:       request.OwnerId,
+		ClusterId:     request.ClusterId,
+		EnvId:         request.EnvId,
+		AppId:         request.AppId,
+		ImageDigest:   strings.TrimSpace(request.ImageDigest),
+		CiArtifactId:  request.CiArtifactId,
+		ExpiresOn:     request.ExpiresOn,
+		Active:        true,
+		AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+	}
+	if request.ExceptionType == repository3.CveExceptionNotAffected {
+		exception.VexJustification = request.VexJustification
+	}
+	err = impl.resolveScope(exception)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := impl.cveExceptionRepository.FindActiveWithSameScope(exception)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cve exceptions with same scope", "cveName", exception.CveName, "err", err)
+		return nil, err
+	}
+	if len(existing) > 0 {
+		errMsg := fmt.Sprintf("an active exception %d already exists for %s with the same scope, renew it instead", existing[0].Id, exception.CveName)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+	err = impl.cveExceptionRepository.Save(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in saving cve exception", "exception", exception, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(tx, exception, repository3.CveExceptionCreated, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetException(exception.Id)
+}
+
+func validateExceptionType(exceptionType repository3.CveExceptionType, vexJustification repository3.VexJustification) error {
+	if !exceptionType.IsValid() {
+		errMsg := fmt.Sprintf("invalid exception type %q, supported types are %s and %s", exceptionType, repository3.CveExceptionNotAffected, repository3.CveExceptionAcceptedRisk)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if exceptionType == repository3.CveExceptionNotAffected && !vexJustification.IsValid() {
+		errMsg := fmt.Sprintf("a valid vex justification is required for %s exceptions", repository3.CveExceptionNotAffected)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func (impl *CveExceptionServiceImpl) validateExpiry(expiresOn time.Time) error {
+	now := time.Now()
+	maxExpiry := now.AddDate(0, 0, impl.config.MaxValidityDays)
+	if !expiresOn.After(now) || expiresOn.After(maxExpiry) {
+		errMsg := fmt.Sprintf("expiry of the exception should be within the next %d days", impl.config.MaxValidityDays)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+// resolveScope validates the scope of the exception, cluster is derived from the environment and image digest from the artifact
+func (impl *CveExceptionServiceImpl) resolveScope(exception *repository3.CveException) error {
+	if exception.EnvId > 0 {
+		environment, err := impl.envRepository.FindById(exception.EnvId)
+		if util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "environment not found", "environment not found")
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", exception.EnvId, "err", err)
+			return err
+		}
+		if exception.ClusterId > 0 && exception.ClusterId != environment.ClusterId {
+			return util.NewApiError(http.StatusBadRequest, "environment does not belong to the cluster", "environment does not belong to the cluster")
+		}
+		exception.ClusterId = environment.ClusterId
+	} else if exception.ClusterId > 0 {
+		if _, err := impl.clusterRepository.FindById(exception.ClusterId); util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "cluster not found", "cluster not found")
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching cluster", "clusterId", exception.ClusterId, "err", err)
+			return err
+		}
+	}
+	if exception.AppId > 0 {
+		if _, err := impl.appRepository.FindById(exception.AppId); util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "app not found", "app not found")
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching app", "appId", exception.AppId, "err", err)
+			return err
+		}
+	}
+	if !exception.IsImageScoped() {
+		return nil
+	}
+	if exception.AppId == 0 {
+		return util.NewApiError(http.StatusBadRequest, "app is required for image scoped exceptions", "app is required for image scoped exceptions")
+	}
+	if exception.CiArtifactId == 0 {
+		return nil
+	}
+	artifact, err := impl.ciArtifactRepository.Get(exception.CiArtifactId)
+	if util.IsErrNoRows(err) {
+		return util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "ciArtifactId", exception.CiArtifactId, "err", err)
+		return err
+	}
+	artifactAppId, err := impl.getArtifactAppId(artifact)
+	if err != nil {
+		return err
+	}
+	if artifactAppId != exception.AppId {
+		return util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	}
+	if len(exception.ImageDigest) > 0 && len(artifact.ImageDigest) > 0 && exception.ImageDigest != artifact.ImageDigest {
+		return util.NewApiError(http.StatusBadRequest, "image digest does not match the artifact", "image digest does not match the artifact")
+	}
+	if len(artifact.ImageDigest) > 0 {
+		exception.ImageDigest = artifact.ImageDigest
+	}
+	return nil
+}
+
+func (impl *CveExceptionServiceImpl) getArtifactAppId(artifact *repository.CiArtifact) (int, error) {
+	if artifact.ExternalCiPipelineId > 0 {
+		externalCiPipeline, err := impl.ciPipelineRepository.FindExternalCiById(artifact.ExternalCiPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching external ci pipeline of artifact", "externalCiPipelineId", artifact.ExternalCiPipelineId, "err", err)
+			return 0, err
+		} else if externalCiPipeline == nil {
+			return 0, nil
+		}
+		return externalCiPipeline.AppId, nil
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipeline of artifact", "ciPipelineId", artifact.PipelineId, "err", err)
+		return 0, err
+	} else if ciPipeline == nil {
+		return 0, nil
+	}
+	return ciPipeline.AppId, nil
+}
+
+func (impl *CveExceptionServiceImpl) RenewException(request *bean3.CveExceptionRenewRequest) (*bean3.CveExceptionDto, error) {
+	exception, err := impl.GetExceptionById(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !exception.Active {
+		errMsg := fmt.Sprintf("exception %d is revoked", exception.Id)
+		return nil, util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	err = impl.validateExpiry(request.ExpiresOn)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	exception.ExpiresOn = request.ExpiresOn
+	exception.ExpiryNotified = false
+	if len(strings.TrimSpace(request.Justification)) > 0 {
+		exception.Justification = request.Justification
+	}
+	exception.UpdateAuditLog(request.UserId)
+	err = impl.cveExceptionRepository.Update(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in renewing cve exception", "exceptionId", exception.Id, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(tx, exception, repository3.CveExceptionRenewed, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.GetException(exception.Id)
+}
+
+func (impl *CveExceptionServiceImpl) RevokeException(request *bean3.CveExceptionRevokeRequest) error {
+	exception, err := impl.GetExceptionById(request.Id)
+	if err != nil {
+		return err
+	}
+	if !exception.Active {
+		errMsg := fmt.Sprintf("exception %d is already revoked", exception.Id)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	exception.Active = false
+	exception.RevokedBy = request.UserId
+	exception.RevokedOn = time.Now()
+	exception.RevokeReason = request.Reason
+	exception.UpdateAuditLog(request.UserId)
+	err = impl.cveExceptionRepository.Update(tx, exception)
+	if err != nil {
+		impl.logger.Errorw("error in revoking cve exception", "exceptionId", exception.Id, "err", err)
+		return err
+	}
+	err = impl.saveAudit(tx, exception, repository3.CveExceptionRevoked, request.UserId)
+	if err != nil {
+		return err
+	}
+	return impl.transactionManager.CommitTx(tx)
+}
+
+func (impl *CveExceptionServiceImpl) GetExceptionById(id int) (*repository3.CveException, error) {
+	exception, err := impl.cveExceptionRepository.FindById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "cve exception not found", "cve exception not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching cve exception", "id", id, "err", err)
+		return nil, err
+	}
+	return exception, nil
+}
+
+func (impl *CveExceptionServiceImpl) GetException(id int) (*bean3.CveExceptionDto, error) {
+	rows, err := impl.cveExceptionRepository.FindForReport(&repository3.CveExceptionReportFilter{Id: id, IncludeInactive: true})
+	if err != nil {
+		impl.logger.Errorw("error in fetching cve exception", "id", id, "err", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, util.NewApiError(http.StatusNotFound, "cve exception not found", "cve exception not found")
+	}
+	dto := adapter.BuildCveExceptionDto(rows[0])
+	audits, err := impl.cveExceptionRepository.FindAuditsByExceptionId(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cve exception audits", "id", id, "err", err)
+		return nil, err
+	}
+	userIds := make([]int32, 0, len(audits))
+	for _, audit := range audits {
+		userIds = append(userIds, audit.CreatedBy)
+	}
+	userEmailMap := make(map[int32]string, len(userIds))
+	if len(userIds) > 0 {
+		users, err := impl.userRepository.GetByIds(userIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching users by ids", "userIds", userIds, "err", err)
+			return nil, err
+		}
+		for _, user := range users {
+			userEmailMap[user.Id] = user.EmailId
+		}
+	}
+	for _, audit := range audits {
+		dto.AuditTrail = append(dto.AuditTrail, &bean3.CveExceptionAuditDto{
+			Action:        audit.Action,
+			ExpiresOn:     audit.ExpiresOn,
+			Justification: audit.Justification,
+			ActionBy:      userEmailMap[audit.CreatedBy],
+			ActionOn:      audit.CreatedOn,
+		})
+	}
+	return dto, nil
+}
+
+func (impl *CveExceptionServiceImpl) GetExceptionReport(request *bean3.CveExceptionReportRequest) ([]*bean3.CveExceptionDto, error) {
+	filter := &repository3.CveExceptionReportFilter{
+		CveName:         strings.TrimSpace(request.CveName),
+		ClusterId:       request.ClusterId,
+		EnvId:           request.EnvId,
+		AppId:           request.AppId,
+		ExceptionType:   request.ExceptionType,
+		IncludeInactive: request.IncludeInactive,
+	}
+	if request.ExpiringInDays > 0 {
+		expiringBefore := time.Now().AddDate(0, 0, request.ExpiringInDays)
+		filter.ExpiringBefore = &expiringBefore
+	}
+	rows, err := impl.cveExceptionRepository.FindForReport(filter)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cve exception report", "filter", filter, "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean3.CveExceptionDto, 0, len(rows))
+	for _, row := range rows {
+		dtos = append(dtos, adapter.BuildCveExceptionDto(row))
+	}
+	return dtos, nil
+}
+
+func (impl *CveExceptionServiceImpl) NotifyExpiringExceptions() {
+	expiringBefore := time.Now().Add(time.Duration(impl.config.ExpiryNoticeHours) * time.Hour)
+	exceptions, err := impl.cveExceptionRepository.FindExpiringAndNotNotified(expiringBefore)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching expiring cve exceptions", "expiringBefore", expiringBefore, "err", err)
+		return
+	}
+	for _, exception := range exceptions {
+		err = impl.notifyExpiry(exception)
+		if err != nil {
+			// retried in the next run as the exception is not marked notified
+			impl.logger.Errorw("error in notifying cve exception expiry", "exceptionId", exception.Id, "err", err)
+			continue
+		}
+		exception.ExpiryNotified = true
+		exception.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+		err = impl.cveExceptionRepository.Update(nil, exception)
+		if err != nil {
+			impl.logger.Errorw("error in marking cve exception expiry notified", "exceptionId", exception.Id, "err", err)
+			continue
+		}
+		_ = impl.saveAudit(nil, exception, repository3.CveExceptionExpiryNotified, userBean.SYSTEM_USER_ID)
+	}
+}
+
+func (impl *CveExceptionServiceImpl) notifyExpiry(exception *repository3.CveException) error {
+	rows, err := impl.cveExceptionRepository.FindForReport(&repository3.CveExceptionReportFilter{Id: exception.Id})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	dto := adapter.BuildCveExceptionDto(rows[0])
+	event, err := impl.eventFactory.Build(eventUtil.CveExceptionExpiry, nil, exception.AppId, &exception.EnvId, eventUtil.CD)
+	if err != nil {
+		return err
+	}
+	if exception.AppId > 0 {
+		app, err := impl.appRepository.FindById(exception.AppId)
+		if err != nil {
+			return err
+		}
+		event.TeamId = app.TeamId
+	}
+	event.UserId = int(exception.OwnerId)
+	event.Payload = &client.Payload{
+		AppName:             dto.AppName,
+		EnvName:             dto.EnvName,
+		CveExceptionSummary: getCveExceptionExpiryMessage(dto),
+	}
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	return err
+}
+
+func getCveExceptionExpiryMessage(exception *bean3.CveExceptionDto) string {
+	scopes := make([]string, 0, 3)
+	if len(exception.AppName) > 0 {
+		scopes = append(scopes, fmt.Sprintf("app %s", exception.AppName))
+	}
+	if len(exception.EnvName) > 0 {
+		scopes = append(scopes, fmt.Sprintf("environment %s", exception.EnvName))
+	} else if len(exception.ClusterName) > 0 {
+		scopes = append(scopes, fmt.Sprintf("cluster %s", exception.ClusterName))
+	}
+	if len(exception.ImageDigest) > 0 {
+		scopes = append(scopes, fmt.Sprintf("image %s", exception.ImageDigest))
+	} else if exception.CiArtifactId > 0 {
+		scopes = append(scopes, fmt.Sprintf("artifact %d", exception.CiArtifactId))
+	}
+	scope := "all applications"
+	if len(scopes) > 0 {
+		scope = strings.Join(scopes, ", ")
+	}
+	return fmt.Sprintf("%s exception for %s on %s owned by %s expires on %s", exception.ExceptionType, exception.CveName, scope,
+		exception.Owner, exception.ExpiresOn.Format(time.RFC1123))
+}
+
+func (impl *CveExceptionServiceImpl) saveAudit(tx *pg.Tx, exception *repository3.CveException, action repository3.CveExceptionAuditAction, userId int32) error {
+	audit := &repository3.CveExceptionAudit{
+		ExceptionId:   exception.Id,
+		Action:        action,
+		ExpiresOn:     exception.ExpiresOn,
+		Justification: exception.Justification,
+		AuditLog:      sql.NewDefaultAuditLog(userId),
+	}
+	if action == repository3.CveExceptionRevoked {
+		audit.Justification = exception.RevokeReason
+	}
+	err := impl.cveExceptionRepository.SaveAudit(tx, audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving cve exception audit", "exceptionId", exception.Id, "action", action, "err", err)
+		return err
+	}
+	return nil
+}
@@ -50,11 +50,16 @@ type PolicyService interface {
 	UpdatePolicy(updatePolicyParams bean.UpdatePolicyParams, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
 	DeletePolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
 	GetPolicies(policyLevel securityBean.PolicyLevel, clusterId, environmentId, appId int) (*bean.GetVulnerabilityPolicyResult, error)
-	GetBlockedCVEList(cves []*repository3.CveStore, clusterId, envId, appId int, isAppstore bool) ([]*repository3.CveStore, error)
+	// GetBlockedCVEList returns the cves blocked by the applicable policy, cves covered by an active exception are never blocked.
+	// image scoped exceptions are ignored when image is nil
+	GetBlockedCVEList(cves []*repository3.CveStore, clusterId, envId, appId int, isAppstore bool, image *securityBean.ImageIdentifier) ([]*repository3.CveStore, error)
 	VerifyImage(verifyImageRequest *VerifyImageRequest) (map[string][]*VerifyImageResponse, error)
 	GetCvePolicy(id int, userId int32) (*repository3.CvePolicy, error)
 	GetApplicablePolicy(clusterId, envId, appId int, isAppstore bool) (map[string]*repository3.CvePolicy, map[securityBean.Severity]*repository3.CvePolicy, error)
-	HasBlockedCVE(cves []*repository3.CveStore, cvePolicy map[string]*repository3.CvePolicy, severityPolicy map[securityBean.Severity]*repository3.CvePolicy) bool
+	HasBlockedCVE(cves []*repository3.CveStore, cvePolicy map[string]*repository3.CvePolicy, severityPolicy map[securityBean.Severity]*repository3.CvePolicy, exceptedCves map[string]*repository3.CveException) bool
+	// GetCveExceptions returns the active cve exceptions applicable on the scope, use CveExceptions.GetExceptedCves to match them against an image
+	GetCveExceptions(clusterId, envId, appId int) (repository3.CveExceptions, error)
+	SendEventToClairUtility(event *bean2.ImageScanEvent) error
 }
 type PolicyServiceImpl struct {
 	environmentService            environment.EnvironmentService
@@ -75,6 +80,7 @@ type PolicyServiceImpl struct {
 	ciTemplateRepository          pipelineConfig.CiTemplateRepository
 	ClusterReadService            read2.ClusterReadService
 	transactionManager            sql.TransactionWrapper
+	cveExceptionRepository        repository3.CveExceptionRepository
 }
 
 func NewPolicyServiceImpl(environmentService environment.EnvironmentService,
@@ -92,7 +98,8 @@ func NewPolicyServiceImpl(environmentService environment.EnvironmentService,
 	cveStoreRepository repository3.CveStoreRepository,
 	ciTemplateRepository pipelineConfig.CiTemplateRepository,
 	ClusterReadService read2.ClusterReadService,
-	transactionManager sql.TransactionWrapper) *PolicyServiceImpl {
+	transactionManager sql.TransactionWrapper,
+	cveExceptionRepository repository3.CveExceptionRepository) *PolicyServiceImpl {
 	return &PolicyServiceImpl{
 		environmentService:            environmentService,
 		logger:                        logger,
@@ -112,6 +119,7 @@ func NewPolicyServiceImpl(environmentService environment.EnvironmentService,
 		ciTemplateRepository:          ciTemplateRepository,
 		ClusterReadService:            ClusterReadService,
 		transactionManager:            transactionManager,
+		cveExceptionRepository:        cveExceptionRepository,
 	}
 }
 
@@ -199,6 +207,10 @@ func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageReques
 	if err != nil {
 		impl.logger.Errorw("error in generating applicable policy", "err", err)
 	}
+	cveExceptions, err := impl.GetCveExceptions(clusterId, envId, appId)
+	if err != nil {
+		return nil, err
+	}
 
 	var objectType string
 	var typeId int
@@ -251,6 +263,11 @@ func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageReques
 				scanResultsIdMap[scanResult.ImageScanExecutionHistoryId] = scanResult.ImageScanExecutionHistoryId
 			}
 		}
+		var imageIdentifier *securityBean.ImageIdentifier
+		if scanHistory != nil && len(scanHistory.ImageHash) > 0 {
+			imageIdentifier = &securityBean.ImageIdentifier{ImageDigest: scanHistory.ImageHash}
+		}
+		cveStores = repository3.RemoveExceptedCves(cveStores, cveExceptions.GetExceptedCves(imageIdentifier))
 		blockedCves := repository3.EnforceCvePolicy(cveStores, cvePolicy, severityPolicy)
 		impl.logger.Debugw("blocked cve for image", "image", image, "blocked", blockedCves)
 		for _, cve := range blockedCves {
@@ -720,18 +737,26 @@ func (impl *PolicyServiceImpl) getPolicies(policyLevel securityBean.PolicyLevel,
 	return cvePolicy, severityPolicy, nil
 }
 
-func (impl *PolicyServiceImpl) GetBlockedCVEList(cves []*repository3.CveStore, clusterId, envId, appId int, isAppstore bool) ([]*repository3.CveStore, error) {
+func (impl *PolicyServiceImpl) GetBlockedCVEList(cves []*repository3.CveStore, clusterId, envId, appId int, isAppstore bool, image *securityBean.ImageIdentifier) ([]*repository3.CveStore, error) {
 
 	cvePolicy, severityPolicy, err := impl.GetApplicablePolicy(clusterId, envId, appId, isAppstore)
 	if err != nil {
 		return nil, err
 	}
+	cveExceptions, err := impl.GetCveExceptions(clusterId, envId, appId)
+	if err != nil {
+		return nil, err
+	}
+	cves = repository3.RemoveExceptedCves(cves, cveExceptions.GetExceptedCves(image))
 	blockedCve := repository3.EnforceCvePolicy(cves, cvePolicy, severityPolicy)
 	return blockedCve, nil
 }
 
-func (impl *PolicyServiceImpl) HasBlockedCVE(cves []*repository3.CveStore, cvePolicy map[string]*repository3.CvePolicy, severityPolicy map[securityBean.Severity]*repository3.CvePolicy) bool {
+func (impl *PolicyServiceImpl) HasBlockedCVE(cves []*repository3.CveStore, cvePolicy map[string]*repository3.CvePolicy, severityPolicy map[securityBean.Severity]*repository3.CvePolicy, exceptedCves map[string]*repository3.CveException) bool {
 	for _, cve := range cves {
+		if _, ok := exceptedCves[cve.Name]; ok {
+			continue
+		}
 		if policy, ok := cvePolicy[cve.Name]; ok {
 			if policy.Action == securityBean.Allow {
 				continue
@@ -749,6 +774,15 @@ func (impl *PolicyServiceImpl) HasBlockedCVE(cves []*repository3.CveStore, cvePo
 	return false
 }
 
+func (impl *PolicyServiceImpl) GetCveExceptions(clusterId, envId, appId int) (repository3.CveExceptions, error) {
+	cveExceptions, err := impl.cveExceptionRepository.FindActiveByScope(clusterId, envId, appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching cve exceptions", "clusterId", clusterId, "envId", envId, "appId", appId, "err", err)
+		return nil, err
+	}
+	return cveExceptions, nil
+}
+
 func (impl *PolicyServiceImpl) GetCvePolicy(id int, userId int32) (*repository3.CvePolicy, error) {
 	policy, err := impl.cvePolicyRepository.GetById(id)
 	if err != nil {
@@ -166,7 +166,7 @@ func TestPolicyServiceImpl_HasBlockedCVE(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			impl := &PolicyServiceImpl{}
-			if got := impl.HasBlockedCVE(tt.args.cves, tt.args.cvePolicy, tt.args.severityPolicy); got != tt.want {
+			if got := impl.HasBlockedCVE(tt.args.cves, tt.args.cvePolicy, tt.args.severityPolicy, nil); got != tt.want {
 				t.Errorf("HasBlockedCVE() = %v, want %v", got, tt.want)
 			}
 		})
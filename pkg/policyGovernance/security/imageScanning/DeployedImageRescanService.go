@@ -0,0 +1,408 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageScanning
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	bean2 "github.com/devtron-labs/common-lib/imageScan/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	repository1 "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	repository4 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	scanToolBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool/bean"
+	scanToolRepository "github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"github.com/juju/errors"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DeployedImageRescanService periodically re-scans the images running in environments (as per image_scan_deploy_info)
+// so that cves published after the last scan are found, owning teams are notified of new critical cves
+type DeployedImageRescanService interface {
+	// RescanDeployedImages processes the completed re-scans and triggers new ones, run by the deployed image rescan cron
+	RescanDeployedImages()
+}
+
+type DeployedImageRescanServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	deployedImageRescanRepository repository3.DeployedImageRescanRepository
+	imageScanDeployInfoRepository repository3.ImageScanDeployInfoRepository
+	imageScanHistoryRepository    repository3.ImageScanHistoryRepository
+	imageScanResultRepository     repository3.ImageScanResultRepository
+	scanToolMetadataRepository    scanToolRepository.ScanToolMetadataRepository
+	ciArtifactRepository          repository.CiArtifactRepository
+	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	ciPipelineConfigReadService   read.CiPipelineConfigReadService
+	appRepository                 repository1.AppRepository
+	envRepository                 repository4.EnvironmentRepository
+	policyService                 PolicyService
+	eventClient                   client.EventClient
+	eventFactory                  client.EventFactory
+	config                        *bean3.DeployedImageRescanConfig
+	cron                          *cron.Cron
+}
+
+func NewDeployedImageRescanServiceImpl(logger *zap.SugaredLogger,
+	deployedImageRescanRepository repository3.DeployedImageRescanRepository,
+	imageScanDeployInfoRepository repository3.ImageScanDeployInfoRepository,
+	imageScanHistoryRepository repository3.ImageScanHistoryRepository,
+	imageScanResultRepository repository3.ImageScanResultRepository,
+	scanToolMetadataRepository scanToolRepository.ScanToolMetadataRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	ciTemplateRepository pipelineConfig.CiTemplateRepository,
+	ciPipelineConfigReadService read.CiPipelineConfigReadService,
+	appRepository repository1.AppRepository,
+	envRepository repository4.EnvironmentRepository,
+	policyService PolicyService,
+	eventClient client.EventClient,
+	eventFactory client.EventFactory,
+	cronLogger *cron2.CronLoggerImpl) (*DeployedImageRescanServiceImpl, error) {
+	config := &bean3.DeployedImageRescanConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing deployed image rescan config", "err", err)
+		return nil, err
+	}
+	impl := &DeployedImageRescanServiceImpl{
+		logger:                        logger,
+		deployedImageRescanRepository: deployedImageRescanRepository,
+		imageScanDeployInfoRepository: imageScanDeployInfoRepository,
+		imageScanHistoryRepository:    imageScanHistoryRepository,
+		imageScanResultRepository:     imageScanResultRepository,
+		scanToolMetadataRepository:    scanToolMetadataRepository,
+		ciArtifactRepository:          ciArtifactRepository,
+		ciTemplateRepository:          ciTemplateRepository,
+		ciPipelineConfigReadService:   ciPipelineConfigReadService,
+		appRepository:                 appRepository,
+		envRepository:                 envRepository,
+		policyService:                 policyService,
+		eventClient:                   eventClient,
+		eventFactory:                  eventFactory,
+		config:                        config,
+	}
+	if !config.Enabled {
+		return impl, nil
+	}
+	impl.cron = cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	_, err = impl.cron.AddFunc(config.CronTime, impl.RescanDeployedImages)
+	if err != nil {
+		logger.Errorw("error in adding deployed image rescan cron", "cronTime", config.CronTime, "err", err)
+		return nil, err
+	}
+	impl.cron.Start()
+	return impl, nil
+}
+
+func (impl *DeployedImageRescanServiceImpl) RescanDeployedImages() {
+	impl.processPendingRescans()
+	_, err := impl.scanToolMetadataRepository.FindActiveToolByScanTarget(scanToolBean.ScanTargetTypeImage)
+	if util.IsErrNoRows(err) {
+		impl.logger.Debugw("skipping deployed image rescan as no image scan tool is active")
+		return
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching active image scan tool", "err", err)
+		return
+	}
+	impl.triggerRescans()
+}
+
+// processPendingRescans diffs the re-scans completed by the image scanner against the previous scan of the image,
+// the deployed objects are moved to the new scan and their owners are notified of new critical cves
+func (impl *DeployedImageRescanServiceImpl) processPendingRescans() {
+	rescans, err := impl.deployedImageRescanRepository.FindByStatus(repository3.DeployedImageRescanPending)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching pending deployed image rescans", "err", err)
+		return
+	}
+	for _, rescan := range rescans {
+		err = impl.processPendingRescan(rescan)
+		if err != nil {
+			// retried in the next run as the rescan is still pending
+			impl.logger.Errorw("error in processing deployed image rescan", "rescanId", rescan.Id, "err", err)
+		}
+	}
+}
+
+func (impl *DeployedImageRescanServiceImpl) processPendingRescan(rescan *repository3.DeployedImageRescan) error {
+	history, err := impl.imageScanHistoryRepository.FindByImageAndDigestWithHistoryMapping(rescan.ImageDigest, rescan.Image)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	isNewScan := history != nil && history.Id > rescan.PreviousExecutionHistoryId
+	if !isNewScan || history.ScanToolExecutionHistoryMapping == nil ||
+		history.ScanToolExecutionHistoryMapping.State == repository3.ScanExecutionProcessStateRunning {
+		if time.Since(rescan.TriggeredOn) < impl.config.GetTimeout() {
+			return nil
+		}
+		if isNewScan {
+			rescan.ExecutionHistoryId = history.Id
+		}
+		return impl.markRescanDone(rescan, repository3.DeployedImageRescanTimedOut)
+	}
+	rescan.ExecutionHistoryId = history.Id
+	if history.ScanToolExecutionHistoryMapping.State == repository3.ScanExecutionProcessStateFailed {
+		return impl.markRescanDone(rescan, repository3.DeployedImageRescanFailed)
+	}
+
+	previousResults, err := impl.imageScanResultRepository.FetchByScanExecutionId(rescan.PreviousExecutionHistoryId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	currentResults, err := impl.imageScanResultRepository.FetchByScanExecutionId(history.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	rescan.NewCriticalCves = repository3.GetNewCriticalCves(previousResults, currentResults)
+	deployInfos, err := impl.imageScanDeployInfoRepository.FindByExecutionHistoryId(rescan.PreviousExecutionHistoryId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	// the rescan is completed only after all the deployed objects are moved to the new scan, the objects left on the
+	// previous scan are picked again in the next run and the ones already moved are not notified twice
+	var updateErr error
+	for _, deployInfo := range deployInfos {
+		// security listing and policy checks of the deployed object are served from the latest scan
+		for i, executionHistoryId := range deployInfo.ImageScanExecutionHistoryId {
+			if executionHistoryId == rescan.PreviousExecutionHistoryId {
+				deployInfo.ImageScanExecutionHistoryId[i] = history.Id
+			}
+		}
+		deployInfo.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+		err = impl.imageScanDeployInfoRepository.Update(deployInfo)
+		if err != nil {
+			impl.logger.Errorw("error in updating deploy info with rescan result", "deployInfoId", deployInfo.Id, "executionHistoryId", history.Id, "err", err)
+			updateErr = err
+			continue
+		}
+		if len(rescan.NewCriticalCves) == 0 {
+			continue
+		}
+		err = impl.notifyNewCriticalCves(rescan, deployInfo)
+		if err != nil {
+			impl.logger.Errorw("error in notifying new critical cves of deployed image", "rescanId", rescan.Id, "deployInfoId", deployInfo.Id, "err", err)
+		}
+	}
+	if updateErr != nil {
+		return updateErr
+	}
+	return impl.markRescanDone(rescan, repository3.DeployedImageRescanCompleted)
+}
+
+func (impl *DeployedImageRescanServiceImpl) markRescanDone(rescan *repository3.DeployedImageRescan, status repository3.DeployedImageRescanStatus) error {
+	rescan.Status = status
+	rescan.CompletedOn = time.Now()
+	rescan.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+	err := impl.deployedImageRescanRepository.Update(rescan)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployed image rescan", "rescanId", rescan.Id, "status", status, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeployedImageRescanServiceImpl) notifyNewCriticalCves(rescan *repository3.DeployedImageRescan, deployInfo *repository3.ImageScanDeployInfo) error {
+	var appId int
+	if deployInfo.ObjectType == repository3.ScanObjectType_APP || deployInfo.ObjectType == repository3.ScanObjectType_CHART {
+		appId = deployInfo.ScanObjectMetaId
+	}
+	event, err := impl.eventFactory.Build(eventUtil.NewCriticalCves, nil, appId, &deployInfo.EnvId, eventUtil.CD)
+	if err != nil {
+		return err
+	}
+	payload := &client.Payload{
+		DockerImageUrl:        rescan.Image,
+		NewCriticalCveSummary: getNewCriticalCvesMessage(rescan.NewCriticalCves),
+	}
+	if appId > 0 {
+		app, err := impl.appRepository.FindById(appId)
+		if err != nil {
+			return err
+		}
+		event.TeamId = app.TeamId
+		payload.AppName = app.AppName
+	}
+	if deployInfo.EnvId > 0 {
+		environment, err := impl.envRepository.FindById(deployInfo.EnvId)
+		if err != nil {
+			return err
+		}
+		payload.EnvName = environment.Name
+	}
+	event.UserId = int(userBean.SYSTEM_USER_ID)
+	event.Payload = payload
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	return err
+}
+
+func getNewCriticalCvesMessage(cveNames []string) string {
+	return fmt.Sprintf("%d new critical cve(s) found on re-scan: %s", len(cveNames), strings.Join(cveNames, ", "))
+}
+
+// triggerRescans sends the deployed images not scanned within the configured interval to the image scanner,
+// least recently scanned images are picked first
+func (impl *DeployedImageRescanServiceImpl) triggerRescans() {
+	deployInfos, err := impl.imageScanDeployInfoRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image scan deploy infos", "err", err)
+		return
+	}
+	// any deployed object running the image is used as the source of the scan event
+	executionHistoryIdToDeployInfo := make(map[int]*repository3.ImageScanDeployInfo)
+	executionHistoryIds := make([]int, 0, len(deployInfos))
+	for _, deployInfo := range deployInfos {
+		for _, executionHistoryId := range deployInfo.ImageScanExecutionHistoryId {
+			// -1 is saved for objects deployed with scanning disabled
+			if executionHistoryId <= 0 {
+				continue
+			}
+			if _, ok := executionHistoryIdToDeployInfo[executionHistoryId]; !ok {
+				executionHistoryIdToDeployInfo[executionHistoryId] = deployInfo
+				executionHistoryIds = append(executionHistoryIds, executionHistoryId)
+			}
+		}
+	}
+	if len(executionHistoryIds) == 0 {
+		return
+	}
+	histories, err := impl.imageScanHistoryRepository.FindByIds(executionHistoryIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image scan histories", "executionHistoryIds", executionHistoryIds, "err", err)
+		return
+	}
+	rescanAfter := time.Now().Add(-impl.config.GetInterval())
+	recentRescans, err := impl.deployedImageRescanRepository.FindTriggeredAfter(rescanAfter)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching recent deployed image rescans", "err", err)
+		return
+	}
+	recentlyRescanned := make(map[string]bool, len(recentRescans))
+	for _, rescan := range recentRescans {
+		recentlyRescanned[getImageKey(rescan.Image, rescan.ImageDigest)] = true
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].ExecutionTime.Before(histories[j].ExecutionTime)
+	})
+	triggered := 0
+	for _, history := range histories {
+		if triggered >= impl.config.MaxImagesPerRun {
+			break
+		}
+		imageKey := getImageKey(history.Image, history.ImageHash)
+		if recentlyRescanned[imageKey] || history.ExecutionTime.After(rescanAfter) {
+			continue
+		}
+		// the same image can be deployed with more than one scan execution
+		recentlyRescanned[imageKey] = true
+		err = impl.triggerRescan(history, executionHistoryIdToDeployInfo[history.Id])
+		if err != nil {
+			impl.logger.Errorw("error in triggering deployed image rescan", "image", history.Image, "executionHistoryId", history.Id, "err", err)
+			continue
+		}
+		triggered++
+	}
+}
+
+func getImageKey(image, imageDigest string) string {
+	return fmt.Sprintf("%s@%s", image, imageDigest)
+}
+
+func (impl *DeployedImageRescanServiceImpl) triggerRescan(history *repository3.ImageScanExecutionHistory, deployInfo *repository3.ImageScanDeployInfo) error {
+	scanEvent := &bean2.ImageScanEvent{
+		Image:       history.Image,
+		ImageDigest: history.ImageHash,
+		EnvId:       deployInfo.EnvId,
+		UserId:      int(userBean.SYSTEM_USER_ID),
+		ReScan:      true,
+	}
+	if deployInfo.ObjectType == repository3.ScanObjectType_APP || deployInfo.ObjectType == repository3.ScanObjectType_CHART {
+		scanEvent.AppId = deployInfo.ScanObjectMetaId
+	}
+	var artifact *repository.CiArtifact
+	if len(history.ImageHash) > 0 {
+		ciArtifact, err := impl.ciArtifactRepository.GetByImageDigest(history.ImageHash)
+		if err != nil && !util.IsErrNoRows(err) {
+			return err
+		}
+		if err == nil && ciArtifact.Image == history.Image {
+			artifact = ciArtifact
+			scanEvent.CiArtifactId = artifact.Id
+		}
+	}
+	dockerRegistryId, err := impl.getDockerRegistryId(artifact, scanEvent.AppId)
+	if err != nil {
+		return err
+	}
+	scanEvent.DockerRegistryId = dockerRegistryId
+	err = impl.policyService.SendEventToClairUtility(scanEvent)
+	if err != nil {
+		return err
+	}
+	rescan := &repository3.DeployedImageRescan{
+		Image:                      history.Image,
+		ImageDigest:                history.ImageHash,
+		CiArtifactId:               scanEvent.CiArtifactId,
+		PreviousExecutionHistoryId: history.Id,
+		Status:                     repository3.DeployedImageRescanPending,
+		TriggeredOn:                time.Now(),
+		AuditLog:                   sql.NewDefaultAuditLog(userBean.SYSTEM_USER_ID),
+	}
+	return impl.deployedImageRescanRepository.Save(rescan)
+}
+
+// getDockerRegistryId returns the registry the image is pulled from by the image scanner, images not built in devtron
+// fall back to the build registry of the app
+func (impl *DeployedImageRescanServiceImpl) getDockerRegistryId(artifact *repository.CiArtifact, appId int) (string, error) {
+	if artifact != nil && artifact.IsRegistryCredentialMapped() {
+		return artifact.CredentialSourceValue, nil
+	}
+	if artifact != nil && artifact.PipelineId > 0 {
+		dockerRegistryId, err := impl.ciPipelineConfigReadService.GetDockerRegistryIdForCiPipeline(artifact.PipelineId, artifact)
+		if err != nil && !util.IsErrNoRows(err) {
+			return "", err
+		}
+		if dockerRegistryId != nil && len(*dockerRegistryId) > 0 {
+			return *dockerRegistryId, nil
+		}
+	}
+	if appId == 0 {
+		return "", nil
+	}
+	ciTemplate, err := impl.ciTemplateRepository.FindByAppId(appId)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if ciTemplate.DockerRegistry == nil {
+		return "", nil
+	}
+	return ciTemplate.DockerRegistry.Id, nil
+}
@@ -17,19 +17,16 @@
 package imageScanning
 
 import (
-	"context"
 	bean4 "github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
-	bean2 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/helper/parser"
 	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
 	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool/repository"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd/read"
-	"go.opentelemetry.io/otel"
 	"time"
 
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -47,7 +44,6 @@ type ImageScanService interface {
 	FetchExecutionDetailResult(request *bean3.ImageScanRequest) (*bean3.ImageScanExecutionDetail, error)
 	FetchMinScanResultByAppIdAndEnvId(request *bean3.ImageScanRequest) (*bean3.ImageScanExecutionDetail, error)
 	VulnerabilityExposure(request *repository3.VulnerabilityRequest) (*repository3.VulnerabilityExposureListingResponse, error)
-	GetArtifactVulnerabilityStatus(ctx context.Context, request *bean2.VulnerabilityCheckRequest) (bool, error)
 	IsImageScanExecutionCompleted(image, imageDigest string) (bool, error)
 	// resource scanning functions below
 	GetScanResults(resourceScanQueryParams *bean3.ResourceScanQueryParams) (parser.ResourceScanResponseDto, error)
@@ -453,7 +449,17 @@ func (impl ImageScanServiceImpl) FetchExecutionDetailResult(request *bean3.Image
 		imageScanResponse.EnvId = request.EnvId
 		imageScanResponse.EnvName = env.Environment
 
-		blockCveList, err := impl.policyService.GetBlockedCVEList(cveStores, env.ClusterId, env.Id, request.AppId, app.AppType == helper.ChartStoreApp)
+		// image scoped cve exceptions are applicable only when the result belongs to a single image
+		var imageIdentifier *securityBean.ImageIdentifier
+		if len(imageDigests) == 1 || request.ArtifactId > 0 {
+			imageIdentifier = &securityBean.ImageIdentifier{CiArtifactId: request.ArtifactId}
+			if len(imageDigests) == 1 {
+				for imageDigest := range imageDigests {
+					imageIdentifier.ImageDigest = imageDigest
+				}
+			}
+		}
+		blockCveList, err := impl.policyService.GetBlockedCVEList(cveStores, env.ClusterId, env.Id, request.AppId, app.AppType == helper.ChartStoreApp, imageIdentifier)
 		if err != nil {
 			impl.Logger.Errorw("error while fetching env", "err", err)
 			//return nil, err
@@ -620,7 +626,7 @@ func (impl *ImageScanServiceImpl) VulnerabilityExposure(request *repository3.Vul
 		item.EnvName = env.Environment
 		var appStore bool
 		appStore = item.AppType == helper.ChartStoreApp
-		blockCveList, err := impl.policyService.GetBlockedCVEList(cveStores, env.ClusterId, envId, item.AppId, appStore)
+		blockCveList, err := impl.policyService.GetBlockedCVEList(cveStores, env.ClusterId, envId, item.AppId, appStore, nil)
 		if err != nil {
 			impl.Logger.Errorw("error while fetching blocked list", "err", err)
 			return nil, err
@@ -651,42 +657,6 @@ func (impl *ImageScanServiceImpl) CalculateSeverityCountInfo(vulnerabilities []*
 	return &diff
 }
 
-func (impl *ImageScanServiceImpl) GetArtifactVulnerabilityStatus(ctx context.Context, request *bean2.VulnerabilityCheckRequest) (bool, error) {
-	isVulnerable := false
-	if len(request.ImageDigest) > 0 {
-		var cveStores []*repository3.CveStore
-		_, span := otel.Tracer("orchestrator").Start(ctx, "scanResultRepository.FindByImageDigest")
-		imageScanResult, err := impl.scanResultRepository.FindByImageDigest(request.ImageDigest)
-		span.End()
-		if err != nil && err != pg.ErrNoRows {
-			impl.Logger.Errorw("error fetching image digest", "digest", request.ImageDigest, "err", err)
-			return false, err
-		}
-		for _, item := range imageScanResult {
-			cveStores = append(cveStores, &item.CveStore)
-		}
-		_, span = otel.Tracer("orchestrator").Start(ctx, "cvePolicyRepository.GetBlockedCVEList")
-		if request.CdPipeline.Environment.ClusterId == 0 {
-			envDetails, err := impl.envService.GetDetailsById(request.CdPipeline.EnvironmentId)
-			if err != nil {
-				impl.Logger.Errorw("error fetching cluster details by env, GetArtifactVulnerabilityStatus", "envId", request.CdPipeline.EnvironmentId, "err", err)
-				return false, err
-			}
-			request.CdPipeline.Environment = *envDetails
-		}
-		blockCveList, err := impl.cvePolicyRepository.GetBlockedCVEList(cveStores, request.CdPipeline.Environment.ClusterId, request.CdPipeline.EnvironmentId, request.CdPipeline.AppId, false)
-		span.End()
-		if err != nil {
-			impl.Logger.Errorw("error encountered in GetArtifactVulnerabilityStatus", "clusterId", request.CdPipeline.Environment.ClusterId, "envId", request.CdPipeline.EnvironmentId, "appId", request.CdPipeline.AppId, "err", err)
-			return false, err
-		}
-		if len(blockCveList) > 0 {
-			isVulnerable = true
-		}
-	}
-	return isVulnerable, nil
-}
-
 func (impl ImageScanServiceImpl) updateCount(severity securityBean.Severity, criticalCount int, highCount int, moderateCount int, lowCount int, unkownCount int) (int, int, int, int, int) {
 	if severity == securityBean.Critical {
 		criticalCount += 1
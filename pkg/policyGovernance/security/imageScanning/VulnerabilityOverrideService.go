@@ -0,0 +1,337 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageScanning
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VulnerabilityOverrideService manages time-boxed overrides which allow a vulnerable artifact to be deployed on a cd pipeline
+type VulnerabilityOverrideService interface {
+	CreateOverride(request *bean3.VulnerabilityOverrideRequest) (*bean3.VulnerabilityOverrideDto, error)
+	RevokeOverride(overrideId int, userId int32) error
+	GetOverridesByPipelineId(cdPipelineId int) ([]*bean3.VulnerabilityOverrideDto, error)
+	GetOverrideById(overrideId int) (*repository3.DeploymentVulnerabilityOverride, error)
+	// GetActiveOverride returns nil if no active override exists for the given pipeline and artifact
+	GetActiveOverride(cdPipelineId, ciArtifactId int) (*repository3.DeploymentVulnerabilityOverride, error)
+	RecordOverrideApplied(overrideId, cdWorkflowRunnerId int, blockedCves []*bean3.BlockedCveDetail, userId int32) error
+}
+
+type VulnerabilityOverrideServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	vulnerabilityOverrideRepository repository3.VulnerabilityOverrideRepository
+	pipelineRepository              pipelineConfig.PipelineRepository
+	ciArtifactRepository            repository.CiArtifactRepository
+	ciPipelineRepository            pipelineConfig.CiPipelineRepository
+	userRepository                  userRepository.UserRepository
+	transactionManager              sql.TransactionWrapper
+	config                          *bean3.VulnerabilityOverrideConfig
+}
+
+func NewVulnerabilityOverrideServiceImpl(logger *zap.SugaredLogger,
+	vulnerabilityOverrideRepository repository3.VulnerabilityOverrideRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	userRepository userRepository.UserRepository,
+	transactionManager sql.TransactionWrapper) (*VulnerabilityOverrideServiceImpl, error) {
+	config := &bean3.VulnerabilityOverrideConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing vulnerability override config", "err", err)
+		return nil, err
+	}
+	return &VulnerabilityOverrideServiceImpl{
+		logger:                          logger,
+		vulnerabilityOverrideRepository: vulnerabilityOverrideRepository,
+		pipelineRepository:              pipelineRepository,
+		ciArtifactRepository:            ciArtifactRepository,
+		ciPipelineRepository:            ciPipelineRepository,
+		userRepository:                  userRepository,
+		transactionManager:              transactionManager,
+		config:                          config,
+	}, nil
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) CreateOverride(request *bean3.VulnerabilityOverrideRequest) (*bean3.VulnerabilityOverrideDto, error) {
+	if len(strings.TrimSpace(request.Reason)) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "reason is required for overriding vulnerability check", "reason is required for overriding vulnerability check")
+	}
+	if request.DurationInMinutes <= 0 || request.DurationInMinutes > impl.config.MaxOverrideDurationInMinutes {
+		errMsg := fmt.Sprintf("override duration should be between 1 and %d minutes", impl.config.MaxOverrideDurationInMinutes)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(request.CdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "cdPipelineId", request.CdPipelineId, "err", err)
+		return nil, err
+	}
+	if cdPipeline.AppId != request.AppId {
+		errMsg := fmt.Sprintf("cd pipeline %d does not belong to app %d", request.CdPipelineId, request.AppId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	artifact, err := impl.ciArtifactRepository.Get(request.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "ciArtifactId", request.CiArtifactId, "err", err)
+		return nil, err
+	}
+	artifactAppId, err := impl.getArtifactAppId(artifact)
+	if err != nil {
+		return nil, err
+	}
+	if artifactAppId != cdPipeline.AppId {
+		errMsg := fmt.Sprintf("artifact %d is not built by the ci pipelines of app %d", artifact.Id, cdPipeline.AppId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	now := time.Now()
+	override := &repository3.DeploymentVulnerabilityOverride{
+		CdPipelineId: cdPipeline.Id,
+		CiArtifactId: artifact.Id,
+		ImageDigest:  artifact.ImageDigest,
+		Reason:       request.Reason,
+		ExpiresOn:    now.Add(time.Duration(request.DurationInMinutes) * time.Minute),
+		Active:       true,
+		AuditLog:     sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.vulnerabilityOverrideRepository.Save(tx, override)
+	if err != nil {
+		impl.logger.Errorw("error in saving vulnerability override", "override", override, "err", err)
+		return nil, err
+	}
+	err = impl.saveAudit(tx, override.Id, repository3.VulnerabilityOverrideCreated, 0, nil, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.transactionManager.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	dtos, err := impl.buildOverrideDtos([]*repository3.DeploymentVulnerabilityOverride{override})
+	if err != nil {
+		return nil, err
+	}
+	return dtos[0], nil
+}
+
+// getArtifactAppId returns the app of the ci or external ci pipeline which created the artifact, artifacts created by
+// the pre/post stages are resolved to their parent artifact, 0 if the artifact is not created by a pipeline of an app
+func (impl *VulnerabilityOverrideServiceImpl) getArtifactAppId(artifact *repository.CiArtifact) (int, error) {
+	if artifact.PipelineId == 0 && artifact.ExternalCiPipelineId == 0 && artifact.ParentCiArtifact > 0 {
+		parentArtifact, err := impl.ciArtifactRepository.Get(artifact.ParentCiArtifact)
+		if err != nil {
+			impl.logger.Errorw("error in fetching parent ci artifact", "ciArtifactId", artifact.Id, "parentCiArtifactId", artifact.ParentCiArtifact, "err", err)
+			return 0, err
+		}
+		artifact = parentArtifact
+	}
+	if artifact.PipelineId > 0 {
+		ciPipeline, err := impl.ciPipelineRepository.FindByIdIncludingInActive(artifact.PipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci pipeline of artifact", "ciArtifactId", artifact.Id, "ciPipelineId", artifact.PipelineId, "err", err)
+			return 0, err
+		}
+		return ciPipeline.AppId, nil
+	} else if artifact.ExternalCiPipelineId > 0 {
+		externalCiPipeline, err := impl.ciPipelineRepository.FindExternalCiById(artifact.ExternalCiPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching external ci pipeline of artifact", "ciArtifactId", artifact.Id, "externalCiPipelineId", artifact.ExternalCiPipelineId, "err", err)
+			return 0, err
+		} else if util.IsErrNoRows(err) {
+			return 0, nil
+		}
+		return externalCiPipeline.AppId, nil
+	}
+	return 0, nil
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) RevokeOverride(overrideId int, userId int32) error {
+	override, err := impl.vulnerabilityOverrideRepository.FindById(overrideId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching vulnerability override", "overrideId", overrideId, "err", err)
+		return err
+	}
+	if !override.Active {
+		errMsg := fmt.Sprintf("override %d is already revoked", overrideId)
+		return util.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	tx, err := impl.transactionManager.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.transactionManager.RollbackTx(tx)
+
+	override.Active = false
+	override.RevokedBy = userId
+	override.RevokedOn = time.Now()
+	override.UpdateAuditLog(userId)
+	err = impl.vulnerabilityOverrideRepository.Update(tx, override)
+	if err != nil {
+		impl.logger.Errorw("error in revoking vulnerability override", "overrideId", overrideId, "err", err)
+		return err
+	}
+	err = impl.saveAudit(tx, override.Id, repository3.VulnerabilityOverrideRevoked, 0, nil, userId)
+	if err != nil {
+		return err
+	}
+	return impl.transactionManager.CommitTx(tx)
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) GetOverridesByPipelineId(cdPipelineId int) ([]*bean3.VulnerabilityOverrideDto, error) {
+	overrides, err := impl.vulnerabilityOverrideRepository.FindAllByPipelineId(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching vulnerability overrides", "cdPipelineId", cdPipelineId, "err", err)
+		return nil, err
+	}
+	return impl.buildOverrideDtos(overrides)
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) GetOverrideById(overrideId int) (*repository3.DeploymentVulnerabilityOverride, error) {
+	return impl.vulnerabilityOverrideRepository.FindById(overrideId)
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) GetActiveOverride(cdPipelineId, ciArtifactId int) (*repository3.DeploymentVulnerabilityOverride, error) {
+	override, err := impl.vulnerabilityOverrideRepository.FindActiveByPipelineIdAndArtifactId(cdPipelineId, ciArtifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching active vulnerability override", "cdPipelineId", cdPipelineId, "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) {
+		return nil, nil
+	}
+	return override, nil
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) RecordOverrideApplied(overrideId, cdWorkflowRunnerId int, blockedCves []*bean3.BlockedCveDetail, userId int32) error {
+	return impl.saveAudit(nil, overrideId, repository3.VulnerabilityOverrideApplied, cdWorkflowRunnerId, blockedCves, userId)
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) saveAudit(tx *pg.Tx, overrideId int, action repository3.VulnerabilityOverrideAuditAction,
+	cdWorkflowRunnerId int, blockedCves []*bean3.BlockedCveDetail, userId int32) error {
+	audit := &repository3.DeploymentVulnerabilityOverrideAudit{
+		OverrideId:         overrideId,
+		Action:             action,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		AuditLog:           sql.NewDefaultAuditLog(userId),
+	}
+	if len(blockedCves) > 0 {
+		blockedCvesJson, err := json.Marshal(blockedCves)
+		if err != nil {
+			impl.logger.Errorw("error in marshalling blocked cves", "blockedCves", blockedCves, "err", err)
+			return err
+		}
+		audit.BlockedCves = string(blockedCvesJson)
+	}
+	err := impl.vulnerabilityOverrideRepository.SaveAudit(tx, audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving vulnerability override audit", "overrideId", overrideId, "action", action, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *VulnerabilityOverrideServiceImpl) buildOverrideDtos(overrides []*repository3.DeploymentVulnerabilityOverride) ([]*bean3.VulnerabilityOverrideDto, error) {
+	dtos := make([]*bean3.VulnerabilityOverrideDto, 0, len(overrides))
+	if len(overrides) == 0 {
+		return dtos, nil
+	}
+	overrideIds := make([]int, 0, len(overrides))
+	userIds := make([]int32, 0, len(overrides))
+	for _, override := range overrides {
+		overrideIds = append(overrideIds, override.Id)
+		userIds = append(userIds, override.CreatedBy)
+		if override.RevokedBy > 0 {
+			userIds = append(userIds, override.RevokedBy)
+		}
+	}
+	audits, err := impl.vulnerabilityOverrideRepository.FindAuditsByOverrideIds(overrideIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching vulnerability override audits", "overrideIds", overrideIds, "err", err)
+		return nil, err
+	}
+	for _, audit := range audits {
+		userIds = append(userIds, audit.CreatedBy)
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users by ids", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	userEmailMap := make(map[int32]string, len(users))
+	for _, user := range users {
+		userEmailMap[user.Id] = user.EmailId
+	}
+	overrideIdToAuditsMap := make(map[int][]*bean3.VulnerabilityOverrideAuditDto)
+	for _, audit := range audits {
+		auditDto := &bean3.VulnerabilityOverrideAuditDto{
+			Action:             audit.Action,
+			CdWorkflowRunnerId: audit.CdWorkflowRunnerId,
+			ActionBy:           userEmailMap[audit.CreatedBy],
+			ActionOn:           audit.CreatedOn,
+		}
+		if len(audit.BlockedCves) > 0 {
+			err = json.Unmarshal([]byte(audit.BlockedCves), &auditDto.BlockedCves)
+			if err != nil {
+				impl.logger.Errorw("error in unmarshalling blocked cves of override audit", "auditId", audit.Id, "err", err)
+			}
+		}
+		overrideIdToAuditsMap[audit.OverrideId] = append(overrideIdToAuditsMap[audit.OverrideId], auditDto)
+	}
+	for _, override := range overrides {
+		dto := &bean3.VulnerabilityOverrideDto{
+			Id:           override.Id,
+			CdPipelineId: override.CdPipelineId,
+			CiArtifactId: override.CiArtifactId,
+			ImageDigest:  override.ImageDigest,
+			Reason:       override.Reason,
+			ExpiresOn:    override.ExpiresOn,
+			Active:       override.Active && !override.IsExpired(),
+			CreatedBy:    userEmailMap[override.CreatedBy],
+			CreatedOn:    override.CreatedOn,
+			AuditTrail:   overrideIdToAuditsMap[override.Id],
+		}
+		if override.RevokedBy > 0 {
+			revokedOn := override.RevokedOn
+			dto.RevokedBy = userEmailMap[override.RevokedBy]
+			dto.RevokedOn = &revokedOn
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageScanning
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s/health"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/helper"
+	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// VulnerabilityReportService reports the trend of open cves, mean time to remediate and sla breaches of the devtron apps
+// from their deployment history and the scan execution history of the deployed images
+type VulnerabilityReportService interface {
+	// GetVulnerabilityReport computes the report for the deployments the user has access to
+	GetVulnerabilityReport(request *bean3.VulnerabilityReportRequest, token string) (*bean3.VulnerabilityReport, error)
+}
+
+type VulnerabilityReportServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	vulnerabilityReportRepository repository3.VulnerabilityReportRepository
+	enforcerUtil                  rbac.EnforcerUtil
+	config                        *bean3.VulnerabilityReportConfig
+}
+
+func NewVulnerabilityReportServiceImpl(logger *zap.SugaredLogger,
+	vulnerabilityReportRepository repository3.VulnerabilityReportRepository,
+	enforcerUtil rbac.EnforcerUtil) (*VulnerabilityReportServiceImpl, error) {
+	config := &bean3.VulnerabilityReportConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing vulnerability report config", "err", err)
+		return nil, err
+	}
+	return &VulnerabilityReportServiceImpl{
+		logger:                        logger,
+		vulnerabilityReportRepository: vulnerabilityReportRepository,
+		enforcerUtil:                  enforcerUtil,
+		config:                        config,
+	}, nil
+}
+
+const defaultVulnerabilityReportDays = 30
+
+func (impl *VulnerabilityReportServiceImpl) GetVulnerabilityReport(request *bean3.VulnerabilityReportRequest, token string) (*bean3.VulnerabilityReport, error) {
+	err := impl.validateReportRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	filter := &repository3.VulnerabilityReportFilter{
+		From:               request.From,
+		To:                 request.To,
+		TeamIds:            request.TeamIds,
+		EnvIds:             request.EnvIds,
+		AppIds:             request.AppIds,
+		ProdOnly:           request.ProdOnly,
+		DeploymentStatuses: []string{cdWorkflow.WorkflowSucceeded, string(health.HealthStatusHealthy), string(health.HealthStatusDegraded)},
+	}
+	deployments, err := impl.vulnerabilityReportRepository.FindDeployments(filter)
+	if err != nil {
+		return nil, err
+	}
+	deployments = impl.filterAuthorisedDeployments(deployments, request, token)
+
+	images := make([]string, 0)
+	imageAdded := make(map[string]bool)
+	for _, deployment := range deployments {
+		if !imageAdded[deployment.Image] {
+			imageAdded[deployment.Image] = true
+			images = append(images, deployment.Image)
+		}
+	}
+	scanFindings, err := impl.vulnerabilityReportRepository.FindCompletedScanFindings(images, request.To)
+	if err != nil {
+		return nil, err
+	}
+	findings := helper.BuildVulnerabilityFindings(deployments, helper.GroupScansByImage(scanFindings), request.To)
+	return &bean3.VulnerabilityReport{
+		From:        request.From,
+		To:          request.To,
+		Interval:    request.Interval,
+		Trend:       helper.BuildVulnerabilityTrend(findings, request.From, request.To, request.Interval),
+		Remediation: helper.BuildVulnerabilityRemediation(findings, request.From, request.To),
+		SlaBreaches: helper.BuildVulnerabilitySlaBreaches(findings, request.From, request.To, impl.config),
+	}, nil
+}
+
+func (impl *VulnerabilityReportServiceImpl) validateReportRequest(request *bean3.VulnerabilityReportRequest) error {
+	if request.To.IsZero() {
+		request.To = time.Now()
+	}
+	if request.From.IsZero() {
+		request.From = request.To.AddDate(0, 0, -defaultVulnerabilityReportDays)
+	}
+	if !request.From.Before(request.To) {
+		return util.NewApiError(http.StatusBadRequest, "from should be before to", "from should be before to")
+	}
+	if request.To.Sub(request.From) > time.Duration(impl.config.MaxDays)*24*time.Hour {
+		errMsg := fmt.Sprintf("time range of the report cannot be more than %d days", impl.config.MaxDays)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	if len(request.Interval) == 0 {
+		request.Interval = bean3.VulnerabilityTrendIntervalDay
+	} else if request.Interval != bean3.VulnerabilityTrendIntervalDay && request.Interval != bean3.VulnerabilityTrendIntervalWeek {
+		errMsg := fmt.Sprintf("invalid interval %s, supported intervals are %s and %s", request.Interval,
+			bean3.VulnerabilityTrendIntervalDay, bean3.VulnerabilityTrendIntervalWeek)
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+// filterAuthorisedDeployments drops the deployments of the pipelines whose app or environment the user cannot view
+func (impl *VulnerabilityReportServiceImpl) filterAuthorisedDeployments(deployments []*repository3.VulnerabilityReportDeployment,
+	request *bean3.VulnerabilityReportRequest, token string) []*repository3.VulnerabilityReportDeployment {
+	pipelineIds := make([]int, 0)
+	pipelineAdded := make(map[int]bool)
+	for _, deployment := range deployments {
+		if !pipelineAdded[deployment.PipelineId] {
+			pipelineAdded[deployment.PipelineId] = true
+			pipelineIds = append(pipelineIds, deployment.PipelineId)
+		}
+	}
+	if len(pipelineIds) == 0 {
+		return deployments
+	}
+	//authorization block starts here
+	var appObjectArr []string
+	var envObjectArr []string
+	objects := impl.enforcerUtil.GetAppAndEnvObjectByPipelineIds(pipelineIds)
+	for _, object := range objects {
+		appObjectArr = append(appObjectArr, object[0])
+		envObjectArr = append(envObjectArr, object[1])
+	}
+	appResults, envResults := request.CheckAuthBatch(token, appObjectArr, envObjectArr)
+	//authorization block ends here
+	authorisedDeployments := make([]*repository3.VulnerabilityReportDeployment, 0, len(deployments))
+	for _, deployment := range deployments {
+		object, ok := objects[deployment.PipelineId]
+		if !ok || !(appResults[object[0]] && envResults[object[1]]) {
+			continue
+		}
+		authorisedDeployments = append(authorisedDeployments, deployment)
+	}
+	return authorisedDeployments
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/csv"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"io"
+	"strconv"
+	"time"
+)
+
+func BuildCveExceptionDto(row *repository.CveExceptionReportRow) *bean.CveExceptionDto {
+	dto := &bean.CveExceptionDto{
+		Id:               row.Id,
+		CveName:          row.CveName,
+		ExceptionType:    row.ExceptionType,
+		VexJustification: row.VexJustification,
+		Justification:    row.Justification,
+		Owner:            row.OwnerEmail,
+		ClusterId:        row.ClusterId,
+		ClusterName:      row.ClusterName,
+		EnvId:            row.EnvId,
+		EnvName:          row.EnvName,
+		AppId:            row.AppId,
+		AppName:          row.AppName,
+		CiArtifactId:     row.CiArtifactId,
+		ImageDigest:      row.ImageDigest,
+		ExpiresOn:        row.ExpiresOn,
+		CreatedBy:        row.CreatedByEmail,
+		CreatedOn:        row.CreatedOn,
+	}
+	if row.Severity != nil {
+		dto.Severity = row.Severity.String()
+	}
+	if !row.Active {
+		dto.Status = bean.CveExceptionStatusRevoked
+		revokedOn := row.RevokedOn
+		dto.RevokedOn = &revokedOn
+		dto.RevokeReason = row.RevokeReason
+	} else if !row.ExpiresOn.After(time.Now()) {
+		dto.Status = bean.CveExceptionStatusExpired
+	} else {
+		dto.Status = bean.CveExceptionStatusActive
+	}
+	return dto
+}
+
+var cveExceptionReportCsvHeader = []string{"Id", "CVE", "Severity", "Exception type", "VEX justification", "Justification", "Owner",
+	"Cluster", "Environment", "Application", "Artifact id", "Image digest", "Status", "Expires on", "Created by", "Created on"}
+
+// WriteCveExceptionReportCsv writes the cve exception report as csv, scope columns are empty for unscoped exceptions
+func WriteCveExceptionReportCsv(writer io.Writer, exceptions []*bean.CveExceptionDto) error {
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write(cveExceptionReportCsvHeader)
+	if err != nil {
+		return err
+	}
+	for _, exception := range exceptions {
+		artifactId := ""
+		if exception.CiArtifactId > 0 {
+			artifactId = strconv.Itoa(exception.CiArtifactId)
+		}
+		err = csvWriter.Write([]string{
+			strconv.Itoa(exception.Id),
+			exception.CveName,
+			exception.Severity,
+			string(exception.ExceptionType),
+			string(exception.VexJustification),
+			exception.Justification,
+			exception.Owner,
+			exception.ClusterName,
+			exception.EnvName,
+			exception.AppName,
+			artifactId,
+			exception.ImageDigest,
+			string(exception.Status),
+			exception.ExpiresOn.Format(time.RFC3339),
+			exception.CreatedBy,
+			exception.CreatedOn.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"io"
+	"strconv"
+	"time"
+)
+
+var vulnerabilityTrendCsvHeader = []string{"Time", "Project", "Environment", "Critical", "High", "Medium", "Low", "Unknown", "Total"}
+
+var vulnerabilityRemediationCsvHeader = []string{"Project", "Environment", "Severity", "Remediated", "Mean time to remediate (hours)"}
+
+var vulnerabilitySlaBreachCsvHeader = []string{"Project", "Application", "Environment", "Production", "CVE", "Severity", "Image",
+	"Opened on", "Remediated on", "Open days", "SLA days"}
+
+// WriteVulnerabilityReportCsv writes a section of the vulnerability report as csv
+func WriteVulnerabilityReportCsv(writer io.Writer, report *bean.VulnerabilityReport, section bean.VulnerabilityReportSection) error {
+	var rows [][]string
+	switch section {
+	case bean.VulnerabilityReportSectionTrend:
+		rows = append(rows, vulnerabilityTrendCsvHeader)
+		for _, point := range report.Trend {
+			rows = append(rows, []string{
+				point.Time.Format(time.RFC3339),
+				point.TeamName,
+				point.EnvName,
+				strconv.Itoa(point.Critical),
+				strconv.Itoa(point.High),
+				strconv.Itoa(point.Medium),
+				strconv.Itoa(point.Low),
+				strconv.Itoa(point.Unknown),
+				strconv.Itoa(point.Total),
+			})
+		}
+	case bean.VulnerabilityReportSectionRemediation:
+		rows = append(rows, vulnerabilityRemediationCsvHeader)
+		for _, remediation := range report.Remediation {
+			rows = append(rows, []string{
+				remediation.TeamName,
+				remediation.EnvName,
+				remediation.Severity,
+				strconv.Itoa(remediation.RemediatedCount),
+				strconv.FormatFloat(remediation.MeanTimeToRemediateHours, 'f', 2, 64),
+			})
+		}
+	case bean.VulnerabilityReportSectionSla:
+		rows = append(rows, vulnerabilitySlaBreachCsvHeader)
+		for _, breach := range report.SlaBreaches {
+			remediatedOn := ""
+			if breach.RemediatedOn != nil {
+				remediatedOn = breach.RemediatedOn.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{
+				breach.TeamName,
+				breach.AppName,
+				breach.EnvName,
+				strconv.FormatBool(breach.IsProd),
+				breach.CveName,
+				breach.Severity,
+				breach.Image,
+				breach.OpenedOn.Format(time.RFC3339),
+				remediatedOn,
+				strconv.FormatFloat(breach.OpenDays, 'f', 2, 64),
+				strconv.Itoa(breach.SlaDays),
+			})
+		}
+	default:
+		return fmt.Errorf("unsupported vulnerability report section %s", section)
+	}
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
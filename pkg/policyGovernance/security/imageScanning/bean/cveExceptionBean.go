@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"time"
+)
+
+// CATEGORY=CD
+type CveExceptionConfig struct {
+	CronTime          string `env:"CVE_EXCEPTION_CRON_TIME" envDefault:"@every 1h" description:"Cron schedule notifying the owners of cve exceptions nearing expiry"`
+	ExpiryNoticeHours int    `env:"CVE_EXCEPTION_EXPIRY_NOTICE_HOURS" envDefault:"72" description:"Owners of a cve exception are notified when the exception is going to expire within these many hours"`
+	MaxValidityDays   int    `env:"CVE_EXCEPTION_MAX_VALIDITY_DAYS" envDefault:"365" description:"Maximum time (in days) for which a cve exception can be created or renewed"`
+}
+
+type CveExceptionStatus string
+
+const (
+	CveExceptionStatusActive  CveExceptionStatus = "ACTIVE"
+	CveExceptionStatusExpired CveExceptionStatus = "EXPIRED"
+	CveExceptionStatusRevoked CveExceptionStatus = "REVOKED"
+)
+
+// CveExceptionRequest creates a cve exception, unset scope fields widen the exception.
+// CiArtifactId or ImageDigest restrict the exception to an image and require AppId.
+type CveExceptionRequest struct {
+	CveName          string                      `json:"cveName" validate:"required,min=1"`
+	ExceptionType    repository.CveExceptionType `json:"exceptionType" validate:"required"`
+	VexJustification repository.VexJustification `json:"vexJustification,omitempty"`
+	Justification    string                      `json:"justification" validate:"required,min=1"`
+	OwnerId          int32                       `json:"ownerId,omitempty"`
+	ClusterId        int                         `json:"clusterId,omitempty"`
+	EnvId            int                         `json:"envId,omitempty"`
+	AppId            int                         `json:"appId,omitempty"`
+	CiArtifactId     int                         `json:"ciArtifactId,omitempty"`
+	ImageDigest      string                      `json:"imageDigest,omitempty"`
+	ExpiresOn        time.Time                   `json:"expiresOn" validate:"required"`
+	UserId           int32                       `json:"-"`
+}
+
+// CveExceptionRenewRequest extends the expiry of an active exception, the justification is replaced if provided
+type CveExceptionRenewRequest struct {
+	Id            int       `json:"-"`
+	ExpiresOn     time.Time `json:"expiresOn" validate:"required"`
+	Justification string    `json:"justification,omitempty"`
+	UserId        int32     `json:"-"`
+}
+
+type CveExceptionRevokeRequest struct {
+	Id     int    `json:"-"`
+	Reason string `json:"reason" validate:"required,min=1"`
+	UserId int32  `json:"-"`
+}
+
+type CveExceptionReportRequest struct {
+	CveName         string
+	ClusterId       int
+	EnvId           int
+	AppId           int
+	ExceptionType   repository.CveExceptionType
+	ExpiringInDays  int
+	IncludeInactive bool
+}
+
+type CveExceptionDto struct {
+	Id               int                         `json:"id"`
+	CveName          string                      `json:"cveName"`
+	Severity         string                      `json:"severity,omitempty"`
+	ExceptionType    repository.CveExceptionType `json:"exceptionType"`
+	VexJustification repository.VexJustification `json:"vexJustification,omitempty"`
+	Justification    string                      `json:"justification"`
+	Owner            string                      `json:"owner"`
+	ClusterId        int                         `json:"clusterId,omitempty"`
+	ClusterName      string                      `json:"clusterName,omitempty"`
+	EnvId            int                         `json:"envId,omitempty"`
+	EnvName          string                      `json:"envName,omitempty"`
+	AppId            int                         `json:"appId,omitempty"`
+	AppName          string                      `json:"appName,omitempty"`
+	CiArtifactId     int                         `json:"ciArtifactId,omitempty"`
+	ImageDigest      string                      `json:"imageDigest,omitempty"`
+	ExpiresOn        time.Time                   `json:"expiresOn"`
+	Status           CveExceptionStatus          `json:"status"`
+	RevokedOn        *time.Time                  `json:"revokedOn,omitempty"`
+	RevokeReason     string                      `json:"revokeReason,omitempty"`
+	CreatedBy        string                      `json:"createdBy"`
+	CreatedOn        time.Time                   `json:"createdOn"`
+	AuditTrail       []*CveExceptionAuditDto     `json:"auditTrail,omitempty"`
+}
+
+type CveExceptionAuditDto struct {
+	Action        repository.CveExceptionAuditAction `json:"action"`
+	ExpiresOn     time.Time                          `json:"expiresOn"`
+	Justification string                             `json:"justification,omitempty"`
+	ActionBy      string                             `json:"actionBy"`
+	ActionOn      time.Time                          `json:"actionOn"`
+}
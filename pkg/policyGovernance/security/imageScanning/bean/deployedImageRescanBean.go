@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+// CATEGORY=CD
+type DeployedImageRescanConfig struct {
+	Enabled         bool   `env:"DEPLOYED_IMAGE_RESCAN_ENABLED" envDefault:"true" description:"Periodically re-scan the images running in environments to find cves published after their last scan"`
+	CronTime        string `env:"DEPLOYED_IMAGE_RESCAN_CRON_TIME" envDefault:"@every 30m" description:"Cron schedule triggering re-scans of deployed images and processing the completed ones"`
+	IntervalHours   int    `env:"DEPLOYED_IMAGE_RESCAN_INTERVAL_HOURS" envDefault:"24" description:"A deployed image is re-scanned if it was not scanned in these many hours"`
+	MaxImagesPerRun int    `env:"DEPLOYED_IMAGE_RESCAN_MAX_IMAGES_PER_RUN" envDefault:"20" description:"Maximum number of deployed images sent to the image scanner in a single cron run, least recently scanned images are picked first"`
+	TimeoutMins     int    `env:"DEPLOYED_IMAGE_RESCAN_TIMEOUT_MINS" envDefault:"120" description:"A re-scan not completed by the image scanner within these many minutes is marked timed out"`
+}
+
+func (config *DeployedImageRescanConfig) GetInterval() time.Duration {
+	return time.Duration(config.IntervalHours) * time.Hour
+}
+
+func (config *DeployedImageRescanConfig) GetTimeout() time.Duration {
+	return time.Duration(config.TimeoutMins) * time.Minute
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"time"
+)
+
+// CATEGORY=CD
+type VulnerabilityOverrideConfig struct {
+	MaxOverrideDurationInMinutes int `env:"VULNERABILITY_OVERRIDE_MAX_DURATION_MINS" envDefault:"1440" description:"Maximum time (in minutes) for which a vulnerable artifact can be allowed to deploy on a cd pipeline through an override"`
+}
+
+// BlockedCveDetail is a CVE found on an artifact which is blocked by the applicable cve policy
+type BlockedCveDetail struct {
+	Name         string `json:"name"`
+	Severity     string `json:"severity"`
+	Package      string `json:"package,omitempty"`
+	Version      string `json:"currentVersion,omitempty"`
+	FixedVersion string `json:"fixedVersion,omitempty"`
+}
+
+type VulnerabilityOverrideRequest struct {
+	AppId             int    `json:"appId" validate:"required,number,gt=0"`
+	CdPipelineId      int    `json:"cdPipelineId" validate:"required,number,gt=0"`
+	CiArtifactId      int    `json:"ciArtifactId" validate:"required,number,gt=0"`
+	Reason            string `json:"reason" validate:"required,min=1"`
+	DurationInMinutes int    `json:"durationInMinutes" validate:"required,number,gt=0"`
+	UserId            int32  `json:"-"`
+}
+
+type VulnerabilityOverrideDto struct {
+	Id           int                              `json:"id"`
+	CdPipelineId int                              `json:"cdPipelineId"`
+	CiArtifactId int                              `json:"ciArtifactId"`
+	ImageDigest  string                           `json:"imageDigest,omitempty"`
+	Reason       string                           `json:"reason"`
+	ExpiresOn    time.Time                        `json:"expiresOn"`
+	Active       bool                             `json:"active"`
+	CreatedBy    string                           `json:"createdBy"`
+	CreatedOn    time.Time                        `json:"createdOn"`
+	RevokedBy    string                           `json:"revokedBy,omitempty"`
+	RevokedOn    *time.Time                       `json:"revokedOn,omitempty"`
+	AuditTrail   []*VulnerabilityOverrideAuditDto `json:"auditTrail"`
+}
+
+type VulnerabilityOverrideAuditDto struct {
+	Action             repository.VulnerabilityOverrideAuditAction `json:"action"`
+	CdWorkflowRunnerId int                                         `json:"cdWorkflowRunnerId,omitempty"`
+	BlockedCves        []*BlockedCveDetail                         `json:"blockedCves,omitempty"`
+	ActionBy           string                                      `json:"actionBy"`
+	ActionOn           time.Time                                   `json:"actionOn"`
+}
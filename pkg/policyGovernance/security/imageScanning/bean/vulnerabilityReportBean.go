@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"time"
+)
+
+// CATEGORY=CD
+type VulnerabilityReportConfig struct {
+	MaxDays           int  `env:"VULNERABILITY_REPORT_MAX_DAYS" envDefault:"366" description:"Maximum time range (in days) of the vulnerability trend and sla report"`
+	CriticalSlaDays   int  `env:"VULNERABILITY_SLA_CRITICAL_DAYS" envDefault:"7" description:"Days within which a critical cve running in an environment should be remediated, 0 disables the sla"`
+	HighSlaDays       int  `env:"VULNERABILITY_SLA_HIGH_DAYS" envDefault:"30" description:"Days within which a high severity cve running in an environment should be remediated, 0 disables the sla"`
+	MediumSlaDays     int  `env:"VULNERABILITY_SLA_MEDIUM_DAYS" envDefault:"90" description:"Days within which a medium severity cve running in an environment should be remediated, 0 disables the sla"`
+	LowSlaDays        int  `env:"VULNERABILITY_SLA_LOW_DAYS" envDefault:"0" description:"Days within which a low severity cve running in an environment should be remediated, 0 disables the sla"`
+	SlaOnlyForProdEnv bool `env:"VULNERABILITY_SLA_ONLY_FOR_PROD_ENV" envDefault:"true" description:"Report sla breaches only for the cves running in production environments"`
+}
+
+// GetSlaDays returns the remediation sla of the severity, 0 if sla is not applicable
+func (config *VulnerabilityReportConfig) GetSlaDays(severity securityBean.Severity) int {
+	switch severity {
+	case securityBean.Critical:
+		return config.CriticalSlaDays
+	case securityBean.High:
+		return config.HighSlaDays
+	case securityBean.Medium:
+		return config.MediumSlaDays
+	case securityBean.Low:
+		return config.LowSlaDays
+	}
+	return 0
+}
+
+type VulnerabilityTrendInterval string
+
+const (
+	VulnerabilityTrendIntervalDay  VulnerabilityTrendInterval = "day"
+	VulnerabilityTrendIntervalWeek VulnerabilityTrendInterval = "week"
+)
+
+func (interval VulnerabilityTrendInterval) GetDuration() time.Duration {
+	if interval == VulnerabilityTrendIntervalWeek {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+type VulnerabilityReportSection string
+
+const (
+	VulnerabilityReportSectionTrend       VulnerabilityReportSection = "trend"
+	VulnerabilityReportSectionRemediation VulnerabilityReportSection = "remediation"
+	VulnerabilityReportSectionSla         VulnerabilityReportSection = "sla"
+)
+
+func (section VulnerabilityReportSection) IsValid() bool {
+	return section == VulnerabilityReportSectionTrend || section == VulnerabilityReportSectionRemediation || section == VulnerabilityReportSectionSla
+}
+
+// VulnerabilityReportRequest filters the devtron app deployments considered for the vulnerability report,
+// empty filters match everything
+type VulnerabilityReportRequest struct {
+	From           time.Time                                                                                     `json:"from"`
+	To             time.Time                                                                                     `json:"to"`
+	Interval       VulnerabilityTrendInterval                                                                    `json:"interval"`
+	TeamIds        []int                                                                                         `json:"teamIds"`
+	EnvIds         []int                                                                                         `json:"envIds"`
+	AppIds         []int                                                                                         `json:"appIds"`
+	ProdOnly       bool                                                                                          `json:"prodOnly"`
+	CheckAuthBatch func(token string, appObject []string, envObject []string) (map[string]bool, map[string]bool) `json:"-"`
+}
+
+// VulnerabilityReport is computed from the deployment history of the cd pipelines and the completed scans of the deployed images.
+// A cve is open in an environment from the first scan of a running image reporting it till a later scan of the running image
+// (re-scan or scan of a newly deployed image) no longer reports it.
+type VulnerabilityReport struct {
+	From        time.Time                   `json:"from"`
+	To          time.Time                   `json:"to"`
+	Interval    VulnerabilityTrendInterval  `json:"interval"`
+	Trend       []*VulnerabilityTrendPoint  `json:"trend"`
+	Remediation []*VulnerabilityRemediation `json:"remediation"`
+	SlaBreaches []*VulnerabilitySlaBreach   `json:"slaBreaches"`
+}
+
+// VulnerabilityTrendPoint is the count of open cves of a project in an environment at Time, a cve open in two apps is counted twice
+type VulnerabilityTrendPoint struct {
+	Time     time.Time `json:"time"`
+	TeamId   int       `json:"teamId"`
+	TeamName string    `json:"teamName"`
+	EnvId    int       `json:"envId"`
+	EnvName  string    `json:"envName"`
+	Critical int       `json:"critical"`
+	High     int       `json:"high"`
+	Medium   int       `json:"medium"`
+	Low      int       `json:"low"`
+	Unknown  int       `json:"unknown"`
+	Total    int       `json:"total"`
+}
+
+// VulnerabilityRemediation is the mean time to remediate the cves of a severity remediated within the report time range
+type VulnerabilityRemediation struct {
+	TeamId                   int     `json:"teamId"`
+	TeamName                 string  `json:"teamName"`
+	EnvId                    int     `json:"envId"`
+	EnvName                  string  `json:"envName"`
+	Severity                 string  `json:"severity"`
+	RemediatedCount          int     `json:"remediatedCount"`
+	MeanTimeToRemediateHours float64 `json:"meanTimeToRemediateHours"`
+}
+
+// VulnerabilitySlaBreach is a cve which was open beyond the sla of its severity within the report time range
+type VulnerabilitySlaBreach struct {
+	TeamId       int        `json:"teamId"`
+	TeamName     string     `json:"teamName"`
+	AppId        int        `json:"appId"`
+	AppName      string     `json:"appName"`
+	EnvId        int        `json:"envId"`
+	EnvName      string     `json:"envName"`
+	IsProd       bool       `json:"isProd"`
+	CveName      string     `json:"cveName"`
+	Severity     string     `json:"severity"`
+	Image        string     `json:"image"`
+	OpenedOn     time.Time  `json:"openedOn"`
+	RemediatedOn *time.Time `json:"remediatedOn,omitempty"`
+	OpenDays     float64    `json:"openDays"`
+	SlaDays      int        `json:"slaDays"`
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"math"
+	"sort"
+	"time"
+)
+
+// ImageScan is a completed scan of an image with the severities of the cves found
+type ImageScan struct {
+	ExecutionTime time.Time
+	Cves          map[string]securityBean.Severity
+}
+
+// VulnerabilityFinding is a cve open in the environment of a cd pipeline, RemediatedOn is zero for the cves still open.
+// Deployment is the latest deployment of the pipeline running the cve.
+type VulnerabilityFinding struct {
+	Deployment   *repository.VulnerabilityReportDeployment
+	CveName      string
+	Severity     securityBean.Severity
+	OpenedOn     time.Time
+	RemediatedOn time.Time
+}
+
+func (finding *VulnerabilityFinding) IsOpenAt(t time.Time) bool {
+	return !finding.OpenedOn.After(t) && (finding.RemediatedOn.IsZero() || finding.RemediatedOn.After(t))
+}
+
+func GetImageKey(image, imageDigest string) string {
+	return fmt.Sprintf("%s@%s", image, imageDigest)
+}
+
+// GroupScansByImage groups the scan findings (ordered by execution time) into scans of every image
+func GroupScansByImage(findings []*repository.VulnerabilityReportScanFinding) map[string][]*ImageScan {
+	scansByImage := make(map[string][]*ImageScan)
+	scanById := make(map[int]*ImageScan)
+	for _, finding := range findings {
+		scan, ok := scanById[finding.ExecutionHistoryId]
+		if !ok {
+			scan = &ImageScan{ExecutionTime: finding.ExecutionTime, Cves: make(map[string]securityBean.Severity)}
+			scanById[finding.ExecutionHistoryId] = scan
+			imageKey := GetImageKey(finding.Image, finding.ImageDigest)
+			scansByImage[imageKey] = append(scansByImage[imageKey], scan)
+		}
+		if len(finding.CveName) > 0 && finding.Severity != securityBean.Safe {
+			scan.Cves[finding.CveName] = finding.Severity
+		}
+	}
+	return scansByImage
+}
+
+// BuildVulnerabilityFindings replays the scans of the images running in every cd pipeline till the report end.
+// The running image is represented by its latest scan on deployment and later by every re-scan till it is replaced,
+// cves are opened and remediated as the scans change. Images never scanned do not change the open cves.
+func BuildVulnerabilityFindings(deployments []*repository.VulnerabilityReportDeployment, scansByImage map[string][]*ImageScan, to time.Time) []*VulnerabilityFinding {
+	findings := make([]*VulnerabilityFinding, 0)
+	openFindings := make(map[int]map[string]*VulnerabilityFinding)
+	for _, deployment := range deployments {
+		pipelineFindings, ok := openFindings[deployment.PipelineId]
+		if !ok {
+			pipelineFindings = make(map[string]*VulnerabilityFinding)
+			openFindings[deployment.PipelineId] = pipelineFindings
+		}
+		scans := scansByImage[GetImageKey(deployment.Image, deployment.ImageDigest)]
+		for i, scan := range scans {
+			isReplaced := !deployment.ReplacedOn.IsZero() && !scan.ExecutionTime.Before(deployment.ReplacedOn)
+			if isReplaced || scan.ExecutionTime.After(to) {
+				break
+			}
+			isLatestOnDeployment := !scan.ExecutionTime.After(deployment.DeployedOn) &&
+				(i == len(scans)-1 || scans[i+1].ExecutionTime.After(deployment.DeployedOn))
+			if isLatestOnDeployment {
+				findings = applyImageScan(pipelineFindings, findings, deployment, scan.Cves, deployment.DeployedOn)
+			} else if scan.ExecutionTime.After(deployment.DeployedOn) {
+				findings = applyImageScan(pipelineFindings, findings, deployment, scan.Cves, scan.ExecutionTime)
+			}
+		}
+	}
+	for _, pipelineFindings := range openFindings {
+		for _, finding := range pipelineFindings {
+			findings = append(findings, finding)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if !findings[i].OpenedOn.Equal(findings[j].OpenedOn) {
+			return findings[i].OpenedOn.Before(findings[j].OpenedOn)
+		}
+		if findings[i].Deployment.PipelineId != findings[j].Deployment.PipelineId {
+			return findings[i].Deployment.PipelineId < findings[j].Deployment.PipelineId
+		}
+		return findings[i].CveName < findings[j].CveName
+	})
+	return findings
+}
+
+// applyImageScan remediates the open findings of the pipeline not found in the scan and opens the new ones,
+// remediated findings are appended to findings
+func applyImageScan(openFindings map[string]*VulnerabilityFinding, findings []*VulnerabilityFinding,
+	deployment *repository.VulnerabilityReportDeployment, cves map[string]securityBean.Severity, scannedOn time.Time) []*VulnerabilityFinding {
+	for cveName, finding := range openFindings {
+		if _, ok := cves[cveName]; !ok {
+			finding.RemediatedOn = scannedOn
+			findings = append(findings, finding)
+			delete(openFindings, cveName)
+		}
+	}
+	for cveName, severity := range cves {
+		finding, ok := openFindings[cveName]
+		if !ok {
+			finding = &VulnerabilityFinding{CveName: cveName, OpenedOn: scannedOn}
+			openFindings[cveName] = finding
+		}
+		finding.Severity = severity
+		finding.Deployment = deployment
+	}
+	return findings
+}
+
+type teamEnvKey struct {
+	teamId int
+	envId  int
+}
+
+// BuildVulnerabilityTrend counts the open findings of every project and environment at every interval from the report start,
+// the report end is always the last point of the trend
+func BuildVulnerabilityTrend(findings []*VulnerabilityFinding, from, to time.Time, interval bean.VulnerabilityTrendInterval) []*bean.VulnerabilityTrendPoint {
+	pointTimes := make([]time.Time, 0)
+	for pointTime := from; pointTime.Before(to); pointTime = pointTime.Add(interval.GetDuration()) {
+		pointTimes = append(pointTimes, pointTime)
+	}
+	pointTimes = append(pointTimes, to)
+
+	// projects and environments having a finding open in the report time range are reported at every point
+	groups := make(map[teamEnvKey]*repository.VulnerabilityReportDeployment)
+	for _, finding := range findings {
+		if finding.OpenedOn.After(to) || (!finding.RemediatedOn.IsZero() && finding.RemediatedOn.Before(from)) {
+			continue
+		}
+		groups[teamEnvKey{teamId: finding.Deployment.TeamId, envId: finding.Deployment.EnvId}] = finding.Deployment
+	}
+	groupKeys := make([]teamEnvKey, 0, len(groups))
+	for key := range groups {
+		groupKeys = append(groupKeys, key)
+	}
+	sort.Slice(groupKeys, func(i, j int) bool {
+		if groups[groupKeys[i]].TeamName != groups[groupKeys[j]].TeamName {
+			return groups[groupKeys[i]].TeamName < groups[groupKeys[j]].TeamName
+		}
+		return groups[groupKeys[i]].EnvName < groups[groupKeys[j]].EnvName
+	})
+
+	trend := make([]*bean.VulnerabilityTrendPoint, 0, len(pointTimes)*len(groupKeys))
+	for _, pointTime := range pointTimes {
+		points := make(map[teamEnvKey]*bean.VulnerabilityTrendPoint, len(groupKeys))
+		for _, key := range groupKeys {
+			deployment := groups[key]
+			point := &bean.VulnerabilityTrendPoint{
+				Time:     pointTime,
+				TeamId:   deployment.TeamId,
+				TeamName: deployment.TeamName,
+				EnvId:    deployment.EnvId,
+				EnvName:  deployment.EnvName,
+			}
+			points[key] = point
+			trend = append(trend, point)
+		}
+		for _, finding := range findings {
+			if !finding.IsOpenAt(pointTime) {
+				continue
+			}
+			point, ok := points[teamEnvKey{teamId: finding.Deployment.TeamId, envId: finding.Deployment.EnvId}]
+			if !ok {
+				continue
+			}
+			switch finding.Severity {
+			case securityBean.Critical:
+				point.Critical++
+			case securityBean.High:
+				point.High++
+			case securityBean.Medium:
+				point.Medium++
+			case securityBean.Low:
+				point.Low++
+			default:
+				point.Unknown++
+			}
+			point.Total++
+		}
+	}
+	return trend
+}
+
+type teamEnvSeverityKey struct {
+	teamEnvKey
+	severity securityBean.Severity
+}
+
+// BuildVulnerabilityRemediation computes the mean time to remediate the findings remediated within the report time range
+func BuildVulnerabilityRemediation(findings []*VulnerabilityFinding, from, to time.Time) []*bean.VulnerabilityRemediation {
+	remediations := make([]*bean.VulnerabilityRemediation, 0)
+	remediationByKey := make(map[teamEnvSeverityKey]*bean.VulnerabilityRemediation)
+	totalHoursByKey := make(map[teamEnvSeverityKey]float64)
+	for _, finding := range findings {
+		if finding.RemediatedOn.IsZero() || finding.RemediatedOn.Before(from) || finding.RemediatedOn.After(to) {
+			continue
+		}
+		key := teamEnvSeverityKey{
+			teamEnvKey: teamEnvKey{teamId: finding.Deployment.TeamId, envId: finding.Deployment.EnvId},
+			severity:   finding.Severity,
+		}
+		remediation, ok := remediationByKey[key]
+		if !ok {
+			remediation = &bean.VulnerabilityRemediation{
+				TeamId:   finding.Deployment.TeamId,
+				TeamName: finding.Deployment.TeamName,
+				EnvId:    finding.Deployment.EnvId,
+				EnvName:  finding.Deployment.EnvName,
+				Severity: finding.Severity.String(),
+			}
+			remediationByKey[key] = remediation
+			remediations = append(remediations, remediation)
+		}
+		remediation.RemediatedCount++
+		totalHoursByKey[key] += finding.RemediatedOn.Sub(finding.OpenedOn).Hours()
+	}
+	for key, remediation := range remediationByKey {
+		remediation.MeanTimeToRemediateHours = roundOff(totalHoursByKey[key] / float64(remediation.RemediatedCount))
+	}
+	sort.Slice(remediations, func(i, j int) bool {
+		if remediations[i].TeamName != remediations[j].TeamName {
+			return remediations[i].TeamName < remediations[j].TeamName
+		}
+		if remediations[i].EnvName != remediations[j].EnvName {
+			return remediations[i].EnvName < remediations[j].EnvName
+		}
+		return remediations[i].Severity < remediations[j].Severity
+	})
+	return remediations
+}
+
+// BuildVulnerabilitySlaBreaches returns the findings open within the report time range for longer than the sla of their severity,
+// findings still open are aged till the report end
+func BuildVulnerabilitySlaBreaches(findings []*VulnerabilityFinding, from, to time.Time, config *bean.VulnerabilityReportConfig) []*bean.VulnerabilitySlaBreach {
+	breaches := make([]*bean.VulnerabilitySlaBreach, 0)
+	for _, finding := range findings {
+		slaDays := config.GetSlaDays(finding.Severity)
+		if slaDays <= 0 || (config.SlaOnlyForProdEnv && !finding.Deployment.IsProd) {
+			continue
+		}
+		if finding.OpenedOn.After(to) || (!finding.RemediatedOn.IsZero() && finding.RemediatedOn.Before(from)) {
+			continue
+		}
+		openTill := to
+		if !finding.RemediatedOn.IsZero() && finding.RemediatedOn.Before(to) {
+			openTill = finding.RemediatedOn
+		}
+		openDays := openTill.Sub(finding.OpenedOn).Hours() / 24
+		if openDays <= float64(slaDays) {
+			continue
+		}
+		breach := &bean.VulnerabilitySlaBreach{
+			TeamId:   finding.Deployment.TeamId,
+			TeamName: finding.Deployment.TeamName,
+			AppId:    finding.Deployment.AppId,
+			AppName:  finding.Deployment.AppName,
+			EnvId:    finding.Deployment.EnvId,
+			EnvName:  finding.Deployment.EnvName,
+			IsProd:   finding.Deployment.IsProd,
+			CveName:  finding.CveName,
+			Severity: finding.Severity.String(),
+			Image:    finding.Deployment.Image,
+			OpenedOn: finding.OpenedOn,
+			OpenDays: roundOff(openDays),
+			SlaDays:  slaDays,
+		}
+		if !finding.RemediatedOn.IsZero() && !finding.RemediatedOn.After(to) {
+			remediatedOn := finding.RemediatedOn
+			breach.RemediatedOn = &remediatedOn
+		}
+		breaches = append(breaches, breach)
+	}
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].OpenDays > breaches[j].OpenDays
+	})
+	return breaches
+}
+
+func roundOff(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVulnerabilityReport(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time {
+		return start.AddDate(0, 0, days)
+	}
+	prodDeployment := &repository.VulnerabilityReportDeployment{PipelineId: 1, AppId: 1, AppName: "app", TeamId: 1, TeamName: "team",
+		EnvId: 1, EnvName: "prod", IsProd: true, Image: "app:v1", ImageDigest: "sha1", DeployedOn: day(0), ReplacedOn: day(10)}
+	prodRedeployment := &repository.VulnerabilityReportDeployment{PipelineId: 1, AppId: 1, AppName: "app", TeamId: 1, TeamName: "team",
+		EnvId: 1, EnvName: "prod", IsProd: true, Image: "app:v2", ImageDigest: "sha2", DeployedOn: day(10)}
+	devDeployment := &repository.VulnerabilityReportDeployment{PipelineId: 2, AppId: 1, AppName: "app", TeamId: 1, TeamName: "team",
+		EnvId: 2, EnvName: "dev", Image: "app:v1", ImageDigest: "sha1", DeployedOn: day(2)}
+	scanFindings := []*repository.VulnerabilityReportScanFinding{
+		{ExecutionHistoryId: 1, Image: "app:v1", ImageDigest: "sha1", ExecutionTime: day(-1), CveName: "CVE-1", Severity: securityBean.Critical},
+		{ExecutionHistoryId: 1, Image: "app:v1", ImageDigest: "sha1", ExecutionTime: day(-1), CveName: "CVE-2", Severity: securityBean.High},
+		{ExecutionHistoryId: 2, Image: "app:v1", ImageDigest: "sha1", ExecutionTime: day(5), CveName: "CVE-1", Severity: securityBean.Critical},
+		{ExecutionHistoryId: 2, Image: "app:v1", ImageDigest: "sha1", ExecutionTime: day(5), CveName: "CVE-2", Severity: securityBean.High},
+		{ExecutionHistoryId: 2, Image: "app:v1", ImageDigest: "sha1", ExecutionTime: day(5), CveName: "CVE-3", Severity: securityBean.Critical},
+		{ExecutionHistoryId: 3, Image: "app:v2", ImageDigest: "sha2", ExecutionTime: day(9), CveName: "CVE-2", Severity: securityBean.High},
+	}
+	to := day(20)
+	scansByImage := GroupScansByImage(scanFindings)
+	findings := BuildVulnerabilityFindings([]*repository.VulnerabilityReportDeployment{prodDeployment, prodRedeployment, devDeployment}, scansByImage, to)
+
+	t.Run("findings are opened and remediated as the scans of the running image change", func(t *testing.T) {
+		prodFindings := make(map[string]*VulnerabilityFinding)
+		for _, finding := range findings {
+			if finding.Deployment.PipelineId == 1 {
+				prodFindings[finding.CveName] = finding
+			}
+		}
+		assert.Len(t, prodFindings, 3)
+		assert.Equal(t, day(0), prodFindings["CVE-1"].OpenedOn)
+		assert.Equal(t, day(10), prodFindings["CVE-1"].RemediatedOn)
+		assert.Equal(t, day(5), prodFindings["CVE-3"].OpenedOn)
+		assert.Equal(t, day(10), prodFindings["CVE-3"].RemediatedOn)
+		assert.Equal(t, day(0), prodFindings["CVE-2"].OpenedOn)
+		assert.True(t, prodFindings["CVE-2"].RemediatedOn.IsZero())
+		assert.Equal(t, prodRedeployment, prodFindings["CVE-2"].Deployment)
+	})
+
+	t.Run("open findings are counted by severity at every interval", func(t *testing.T) {
+		trend := BuildVulnerabilityTrend(findings, day(0), to, bean.VulnerabilityTrendIntervalWeek)
+		// points at day 0, 7, 14 and 20 for dev and prod
+		assert.Len(t, trend, 8)
+		pointOf := func(pointTime time.Time, envName string) *bean.VulnerabilityTrendPoint {
+			for _, point := range trend {
+				if point.Time.Equal(pointTime) && point.EnvName == envName {
+					return point
+				}
+			}
+			return nil
+		}
+		assert.Equal(t, 0, pointOf(day(0), "dev").Total)
+		assert.Equal(t, 1, pointOf(day(0), "prod").Critical)
+		assert.Equal(t, 1, pointOf(day(0), "prod").High)
+		assert.Equal(t, 2, pointOf(day(7), "prod").Critical)
+		assert.Equal(t, 3, pointOf(day(7), "dev").Total)
+		assert.Equal(t, 0, pointOf(day(20), "prod").Critical)
+		assert.Equal(t, 1, pointOf(day(20), "prod").Total)
+	})
+
+	t.Run("mean time to remediate is computed for findings remediated in the time range", func(t *testing.T) {
+		remediation := BuildVulnerabilityRemediation(findings, day(0), to)
+		assert.Len(t, remediation, 1)
+		assert.Equal(t, "prod", remediation[0].EnvName)
+		assert.Equal(t, securityBean.Critical.String(), remediation[0].Severity)
+		assert.Equal(t, 2, remediation[0].RemediatedCount)
+		assert.Equal(t, float64(180), remediation[0].MeanTimeToRemediateHours)
+		assert.Empty(t, BuildVulnerabilityRemediation(findings, day(11), to))
+	})
+
+	t.Run("sla breaches", func(t *testing.T) {
+		config := &bean.VulnerabilityReportConfig{CriticalSlaDays: 7, HighSlaDays: 30, SlaOnlyForProdEnv: true}
+		breaches := BuildVulnerabilitySlaBreaches(findings, day(0), to, config)
+		assert.Len(t, breaches, 1)
+		assert.Equal(t, "CVE-1", breaches[0].CveName)
+		assert.Equal(t, float64(10), breaches[0].OpenDays)
+		assert.Equal(t, day(10), *breaches[0].RemediatedOn)
+
+		config.SlaOnlyForProdEnv = false
+		breaches = BuildVulnerabilitySlaBreaches(findings, day(0), to, config)
+		assert.Len(t, breaches, 3)
+		assert.Equal(t, "dev", breaches[0].EnvName)
+		assert.Nil(t, breaches[0].RemediatedOn)
+	})
+
+	t.Run("deployment of an image never scanned does not change the open findings", func(t *testing.T) {
+		unscannedDeployment := *prodRedeployment
+		unscannedDeployment.Image = "app:v3"
+		findings := BuildVulnerabilityFindings([]*repository.VulnerabilityReportDeployment{prodDeployment, &unscannedDeployment}, scansByImage, to)
+		assert.Len(t, findings, 3)
+		for _, finding := range findings {
+			assert.True(t, finding.RemediatedOn.IsZero())
+		}
+	})
+}
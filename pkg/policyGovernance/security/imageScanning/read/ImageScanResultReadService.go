@@ -7,6 +7,7 @@ import (
 
 type ImageScanResultReadService interface {
 	FindByImageDigests(digest []string) ([]*repository.ImageScanExecutionResult, error)
+	FindByImageDigest(imageDigest string) ([]*repository.ImageScanExecutionResult, error)
 }
 type ImageScanResultReadServiceImpl struct {
 	logger                    *zap.SugaredLogger
@@ -24,3 +25,7 @@ func NewImageScanResultReadServiceImpl(logger *zap.SugaredLogger,
 func (impl *ImageScanResultReadServiceImpl) FindByImageDigests(digest []string) ([]*repository.ImageScanExecutionResult, error) {
 	return impl.ImageScanResultRepository.FindByImageDigests(digest)
 }
+
+func (impl *ImageScanResultReadServiceImpl) FindByImageDigest(imageDigest string) ([]*repository.ImageScanExecutionResult, error) {
+	return impl.ImageScanResultRepository.FindByImageDigest(imageDigest)
+}
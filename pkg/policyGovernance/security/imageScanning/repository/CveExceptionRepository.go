@@ -0,0 +1,371 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type CveExceptionType string
+
+const (
+	CveExceptionNotAffected  CveExceptionType = "NOT_AFFECTED"
+	CveExceptionAcceptedRisk CveExceptionType = "ACCEPTED_RISK"
+)
+
+func (exceptionType CveExceptionType) IsValid() bool {
+	return exceptionType == CveExceptionNotAffected || exceptionType == CveExceptionAcceptedRisk
+}
+
+// VexJustification is the VEX status justification, mandatory for NOT_AFFECTED exceptions
+type VexJustification string
+
+const (
+	VexComponentNotPresent                         VexJustification = "component_not_present"
+	VexVulnerableCodeNotPresent                    VexJustification = "vulnerable_code_not_present"
+	VexVulnerableCodeNotInExecutePath              VexJustification = "vulnerable_code_not_in_execute_path"
+	VexVulnerableCodeCannotBeControlledByAdversary VexJustification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	VexInlineMitigationsAlreadyExist               VexJustification = "inline_mitigations_already_exist"
+)
+
+func (justification VexJustification) IsValid() bool {
+	switch justification {
+	case VexComponentNotPresent, VexVulnerableCodeNotPresent, VexVulnerableCodeNotInExecutePath,
+		VexVulnerableCodeCannotBeControlledByAdversary, VexInlineMitigationsAlreadyExist:
+		return true
+	}
+	return false
+}
+
+// CveException excludes a cve from cve policy enforcement till ExpiresOn.
+// ClusterId, EnvId, AppId, ImageDigest and CiArtifactId narrow down the scope of the exception, unset fields match everything.
+type CveException struct {
+	tableName        struct{}         `sql:"cve_exception" pg:",discard_unknown_columns"`
+	Id               int              `sql:"id,pk"`
+	CveName          string           `sql:"cve_name,notnull"`
+	ExceptionType    CveExceptionType `sql:"exception_type,notnull"`
+	VexJustification VexJustification `sql:"vex_justification"`
+	Justification    string           `sql:"justification,notnull"`
+	OwnerId          int32            `sql:"owner_id,notnull"`
+	ClusterId        int              `sql:"cluster_id"`
+	EnvId            int              `sql:"env_id"`
+	AppId            int              `sql:"app_id"`
+	ImageDigest      string           `sql:"image_digest"`
+	CiArtifactId     int              `sql:"ci_artifact_id"`
+	ExpiresOn        time.Time        `sql:"expires_on,notnull"`
+	ExpiryNotified   bool             `sql:"expiry_notified,notnull"`
+	Active           bool             `sql:"active,notnull"`
+	RevokedBy        int32            `sql:"revoked_by"`
+	RevokedOn        time.Time        `sql:"revoked_on"`
+	RevokeReason     string           `sql:"revoke_reason"`
+	sql.AuditLog
+}
+
+func (exception *CveException) IsExpired() bool {
+	return !exception.ExpiresOn.After(time.Now())
+}
+
+func (exception *CveException) IsImageScoped() bool {
+	return len(exception.ImageDigest) > 0 || exception.CiArtifactId > 0
+}
+
+// AppliesToImage matches the image scope of the exception, the digest is preferred over the artifact
+// as the same image can be shared by multiple artifacts (linked ci, ci plugins).
+// Image scoped exceptions never apply when image is nil.
+func (exception *CveException) AppliesToImage(image *securityBean.ImageIdentifier) bool {
+	if !exception.IsImageScoped() {
+		return true
+	}
+	if image == nil {
+		return false
+	}
+	if len(exception.ImageDigest) > 0 && len(image.ImageDigest) > 0 {
+		return exception.ImageDigest == image.ImageDigest
+	}
+	return exception.CiArtifactId > 0 && exception.CiArtifactId == image.CiArtifactId
+}
+
+type CveExceptions []*CveException
+
+// GetExceptedCves returns cve name to exception mapping for the exceptions applicable on the image
+func (exceptions CveExceptions) GetExceptedCves(image *securityBean.ImageIdentifier) map[string]*CveException {
+	exceptedCves := make(map[string]*CveException)
+	for _, exception := range exceptions {
+		if !exception.Active || exception.IsExpired() || !exception.AppliesToImage(image) {
+			continue
+		}
+		if _, ok := exceptedCves[exception.CveName]; !ok {
+			exceptedCves[exception.CveName] = exception
+		}
+	}
+	return exceptedCves
+}
+
+// RemoveExceptedCves filters out the cves covered by an exception
+func RemoveExceptedCves(cves []*CveStore, exceptedCves map[string]*CveException) []*CveStore {
+	if len(exceptedCves) == 0 {
+		return cves
+	}
+	filteredCves := make([]*CveStore, 0, len(cves))
+	for _, cve := range cves {
+		if _, ok := exceptedCves[cve.Name]; ok {
+			continue
+		}
+		filteredCves = append(filteredCves, cve)
+	}
+	return filteredCves
+}
+
+type CveExceptionAuditAction string
+
+const (
+	CveExceptionCreated        CveExceptionAuditAction = "CREATED"
+	CveExceptionRenewed        CveExceptionAuditAction = "RENEWED"
+	CveExceptionRevoked        CveExceptionAuditAction = "REVOKED"
+	CveExceptionExpiryNotified CveExceptionAuditAction = "EXPIRY_NOTIFIED"
+)
+
+// CveExceptionAudit keeps track of every action taken on a CveException
+type CveExceptionAudit struct {
+	tableName     struct{}                `sql:"cve_exception_audit" pg:",discard_unknown_columns"`
+	Id            int                     `sql:"id,pk"`
+	ExceptionId   int                     `sql:"exception_id,notnull"`
+	Action        CveExceptionAuditAction `sql:"action,notnull"`
+	ExpiresOn     time.Time               `sql:"expires_on,notnull"`
+	Justification string                  `sql:"justification"`
+	sql.AuditLog
+}
+
+type CveExceptionReportFilter struct {
+	Id              int
+	CveName         string
+	ClusterId       int
+	EnvId           int
+	AppId           int
+	ExceptionType   CveExceptionType
+	ExpiringBefore  *time.Time
+	IncludeInactive bool
+}
+
+// CveExceptionReportRow is a cve exception along with the names of its scope, owner and the cve severity
+type CveExceptionReportRow struct {
+	Id               int                    `sql:"id"`
+	CveName          string                 `sql:"cve_name"`
+	Severity         *securityBean.Severity `sql:"severity"`
+	ExceptionType    CveExceptionType       `sql:"exception_type"`
+	VexJustification VexJustification       `sql:"vex_justification"`
+	Justification    string                 `sql:"justification"`
+	OwnerId          int32                  `sql:"owner_id"`
+	OwnerEmail       string                 `sql:"owner_email"`
+	ClusterId        int                    `sql:"cluster_id"`
+	ClusterName      string                 `sql:"cluster_name"`
+	EnvId            int                    `sql:"env_id"`
+	EnvName          string                 `sql:"env_name"`
+	AppId            int                    `sql:"app_id"`
+	AppName          string                 `sql:"app_name"`
+	ImageDigest      string                 `sql:"image_digest"`
+	CiArtifactId     int                    `sql:"ci_artifact_id"`
+	ExpiresOn        time.Time              `sql:"expires_on"`
+	Active           bool                   `sql:"active"`
+	RevokedOn        time.Time              `sql:"revoked_on"`
+	RevokeReason     string                 `sql:"revoke_reason"`
+	CreatedByEmail   string                 `sql:"created_by_email"`
+	CreatedOn        time.Time              `sql:"created_on"`
+}
+
+type CveExceptionRepository interface {
+	Save(tx *pg.Tx, model *CveException) error
+	Update(tx *pg.Tx, model *CveException) error
+	FindById(id int) (*CveException, error)
+	FindActiveByScope(clusterId, envId, appId int) (CveExceptions, error)
+	FindActiveWithSameScope(model *CveException) (CveExceptions, error)
+	FindExpiringAndNotNotified(expiringBefore time.Time) (CveExceptions, error)
+	FindForReport(filter *CveExceptionReportFilter) ([]*CveExceptionReportRow, error)
+	SaveAudit(tx *pg.Tx, model *CveExceptionAudit) error
+	FindAuditsByExceptionId(exceptionId int) ([]*CveExceptionAudit, error)
+}
+
+type CveExceptionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCveExceptionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CveExceptionRepositoryImpl {
+	return &CveExceptionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CveExceptionRepositoryImpl) Save(tx *pg.Tx, model *CveException) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *CveExceptionRepositoryImpl) Update(tx *pg.Tx, model *CveException) error {
+	if tx != nil {
+		return tx.Update(model)
+	}
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *CveExceptionRepositoryImpl) FindById(id int) (*CveException, error) {
+	model := &CveException{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+// FindActiveByScope returns all non-revoked and non-expired exceptions applicable on the cluster, env and app,
+// image scoped exceptions are also returned and should be matched using CveExceptions.GetExceptedCves
+func (impl *CveExceptionRepositoryImpl) FindActiveByScope(clusterId, envId, appId int) (CveExceptions, error) {
+	var models CveExceptions
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Where("expires_on > ?", time.Now()).
+		Where("(cluster_id IS NULL OR cluster_id = ?)", clusterId).
+		Where("(env_id IS NULL OR env_id = ?)", envId).
+		Where("(app_id IS NULL OR app_id = ?)", appId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+// FindActiveWithSameScope returns the active exceptions of the cve having exactly the same scope as model
+func (impl *CveExceptionRepositoryImpl) FindActiveWithSameScope(model *CveException) (CveExceptions, error) {
+	var models CveExceptions
+	query := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Where("expires_on > ?", time.Now()).
+		Where("cve_name = ?", model.CveName)
+	query = whereIntScope(query, "cluster_id", model.ClusterId)
+	query = whereIntScope(query, "env_id", model.EnvId)
+	query = whereIntScope(query, "app_id", model.AppId)
+	query = whereIntScope(query, "ci_artifact_id", model.CiArtifactId)
+	if len(model.ImageDigest) > 0 {
+		query = query.Where("image_digest = ?", model.ImageDigest)
+	} else {
+		query = query.Where("image_digest IS NULL")
+	}
+	err := query.Select()
+	return models, err
+}
+
+func whereIntScope(query *orm.Query, column string, value int) *orm.Query {
+	if value > 0 {
+		return query.Where(column+" = ?", value)
+	}
+	return query.Where(column + " IS NULL")
+}
+
+// FindExpiringAndNotNotified returns the active exceptions expiring before expiringBefore whose owners are not notified yet
+func (impl *CveExceptionRepositoryImpl) FindExpiringAndNotNotified(expiringBefore time.Time) (CveExceptions, error) {
+	var models CveExceptions
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Where("expiry_notified = ?", false).
+		Where("expires_on > ?", time.Now()).
+		Where("expires_on <= ?", expiringBefore).
+		Order("expires_on ASC").
+		Select()
+	return models, err
+}
+
+func (impl *CveExceptionRepositoryImpl) FindForReport(filter *CveExceptionReportFilter) ([]*CveExceptionReportRow, error) {
+	var rows []*CveExceptionReportRow
+	query, queryParams := buildCveExceptionReportQuery(filter)
+	_, err := impl.dbConnection.Query(&rows, query, queryParams...)
+	return rows, err
+}
+
+func buildCveExceptionReportQuery(filter *CveExceptionReportFilter) (string, []interface{}) {
+	query := "SELECT ce.id, ce.cve_name, COALESCE(cs.standard_severity, cs.severity) AS severity, ce.exception_type, ce.vex_justification, " +
+		"ce.justification, ce.owner_id, ou.email_id AS owner_email, ce.cluster_id, c.cluster_name, ce.env_id, e.environment_name AS env_name, " +
+		"ce.app_id, a.app_name, ce.image_digest, ce.ci_artifact_id, ce.expires_on, ce.active, ce.revoked_on, ce.revoke_reason, " +
+		"cu.email_id AS created_by_email, ce.created_on " +
+		"FROM cve_exception ce " +
+		"LEFT JOIN cve_store cs ON cs.name = ce.cve_name " +
+		"LEFT JOIN users ou ON ou.id = ce.owner_id " +
+		"LEFT JOIN users cu ON cu.id = ce.created_by " +
+		"LEFT JOIN cluster c ON c.id = ce.cluster_id " +
+		"LEFT JOIN environment e ON e.id = ce.env_id " +
+		"LEFT JOIN app a ON a.id = ce.app_id "
+	var conditions []string
+	var queryParams []interface{}
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "ce.active = true", "ce.expires_on > ?")
+		queryParams = append(queryParams, time.Now())
+	}
+	if filter.Id > 0 {
+		conditions = append(conditions, "ce.id = ?")
+		queryParams = append(queryParams, filter.Id)
+	}
+	if len(filter.CveName) > 0 {
+		conditions = append(conditions, "ce.cve_name = ?")
+		queryParams = append(queryParams, filter.CveName)
+	}
+	if filter.ClusterId > 0 {
+		conditions = append(conditions, "ce.cluster_id = ?")
+		queryParams = append(queryParams, filter.ClusterId)
+	}
+	if filter.EnvId > 0 {
+		conditions = append(conditions, "ce.env_id = ?")
+		queryParams = append(queryParams, filter.EnvId)
+	}
+	if filter.AppId > 0 {
+		conditions = append(conditions, "ce.app_id = ?")
+		queryParams = append(queryParams, filter.AppId)
+	}
+	if len(filter.ExceptionType) > 0 {
+		conditions = append(conditions, "ce.exception_type = ?")
+		queryParams = append(queryParams, filter.ExceptionType)
+	}
+	if filter.ExpiringBefore != nil {
+		conditions = append(conditions, "ce.expires_on <= ?")
+		queryParams = append(queryParams, *filter.ExpiringBefore)
+	}
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + " "
+	}
+	query += "ORDER BY ce.expires_on ASC, ce.id ASC;"
+	return query, queryParams
+}
+
+func (impl *CveExceptionRepositoryImpl) SaveAudit(tx *pg.Tx, model *CveExceptionAudit) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *CveExceptionRepositoryImpl) FindAuditsByExceptionId(exceptionId int) ([]*CveExceptionAudit, error) {
+	var models []*CveExceptionAudit
+	err := impl.dbConnection.Model(&models).
+		Where("exception_id = ?", exceptionId).
+		Order("id ASC").
+		Select()
+	return models, err
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCveExceptions_GetExceptedCves(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	exceptions := CveExceptions{
+		{Id: 1, CveName: "CVE-1", Active: true, ExpiresOn: future},
+		{Id: 2, CveName: "CVE-2", Active: true, ExpiresOn: future, AppId: 1, ImageDigest: "sha256:a", CiArtifactId: 10},
+		{Id: 3, CveName: "CVE-3", Active: true, ExpiresOn: future, AppId: 1, CiArtifactId: 11},
+		{Id: 4, CveName: "CVE-4", Active: true, ExpiresOn: time.Now().Add(-time.Minute)},
+		{Id: 5, CveName: "CVE-5", Active: false, ExpiresOn: future},
+	}
+	t.Run("image scoped exceptions are ignored without image", func(t *testing.T) {
+		exceptedCves := exceptions.GetExceptedCves(nil)
+		assert.Len(t, exceptedCves, 1)
+		assert.Contains(t, exceptedCves, "CVE-1")
+	})
+	t.Run("digest is preferred over artifact", func(t *testing.T) {
+		exceptedCves := exceptions.GetExceptedCves(&securityBean.ImageIdentifier{ImageDigest: "sha256:a", CiArtifactId: 12})
+		assert.Len(t, exceptedCves, 2)
+		assert.Contains(t, exceptedCves, "CVE-2")
+		exceptedCves = exceptions.GetExceptedCves(&securityBean.ImageIdentifier{ImageDigest: "sha256:b", CiArtifactId: 10})
+		assert.NotContains(t, exceptedCves, "CVE-2")
+	})
+	t.Run("artifact is matched when digest is not known", func(t *testing.T) {
+		exceptedCves := exceptions.GetExceptedCves(&securityBean.ImageIdentifier{CiArtifactId: 11})
+		assert.Len(t, exceptedCves, 2)
+		assert.Contains(t, exceptedCves, "CVE-3")
+	})
+}
+
+func TestRemoveExceptedCves(t *testing.T) {
+	cves := []*CveStore{{Name: "CVE-1"}, {Name: "CVE-2"}}
+	assert.Equal(t, cves, RemoveExceptedCves(cves, nil))
+	filteredCves := RemoveExceptedCves(cves, map[string]*CveException{"CVE-1": {CveName: "CVE-1"}})
+	assert.Equal(t, []*CveStore{{Name: "CVE-2"}}, filteredCves)
+}
+
+func TestBuildCveExceptionReportQuery(t *testing.T) {
+	query, params := buildCveExceptionReportQuery(&CveExceptionReportFilter{})
+	assert.Contains(t, query, "WHERE ce.active = true AND ce.expires_on > ?")
+	assert.Len(t, params, 1)
+
+	expiringBefore := time.Now()
+	query, params = buildCveExceptionReportQuery(&CveExceptionReportFilter{CveName: "CVE-1", AppId: 2, ExpiringBefore: &expiringBefore, IncludeInactive: true})
+	assert.False(t, strings.Contains(query, "ce.active = true"))
+	assert.Contains(t, query, "WHERE ce.cve_name = ? AND ce.app_id = ? AND ce.expires_on <= ?")
+	assert.Equal(t, []interface{}{"CVE-1", 2, expiringBefore}, params)
+}
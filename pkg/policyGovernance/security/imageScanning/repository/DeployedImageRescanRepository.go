@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+type DeployedImageRescanStatus string
+
+const (
+	DeployedImageRescanPending   DeployedImageRescanStatus = "PENDING"
+	DeployedImageRescanCompleted DeployedImageRescanStatus = "COMPLETED"
+	DeployedImageRescanFailed    DeployedImageRescanStatus = "FAILED"
+	DeployedImageRescanTimedOut  DeployedImageRescanStatus = "TIMED_OUT"
+)
+
+// DeployedImageRescan tracks a periodic re-scan of a deployed image, the scan itself is recorded by the image scanner
+// as a new image_scan_execution_history which is diffed against PreviousExecutionHistoryId on completion
+type DeployedImageRescan struct {
+	tableName                  struct{}                  `sql:"deployed_image_rescan" pg:",discard_unknown_columns"`
+	Id                         int                       `sql:"id,pk"`
+	Image                      string                    `sql:"image,notnull"`
+	ImageDigest                string                    `sql:"image_digest,notnull"`
+	CiArtifactId               int                       `sql:"ci_artifact_id"`
+	PreviousExecutionHistoryId int                       `sql:"previous_execution_history_id,notnull"`
+	ExecutionHistoryId         int                       `sql:"execution_history_id"`
+	Status                     DeployedImageRescanStatus `sql:"status,notnull"`
+	NewCriticalCves            []string                  `sql:"new_critical_cves" pg:",array"`
+	TriggeredOn                time.Time                 `sql:"triggered_on,notnull"`
+	CompletedOn                time.Time                 `sql:"completed_on"`
+	sql.AuditLog
+}
+
+// GetNewCriticalCves returns the sorted names of critical cves found in current which were not present in previous
+func GetNewCriticalCves(previous, current []*ImageScanExecutionResult) []string {
+	previousCves := make(map[string]bool, len(previous))
+	for _, result := range previous {
+		previousCves[result.CveStoreName] = true
+	}
+	newCves := make(map[string]bool)
+	for _, result := range current {
+		if result.CveStore.GetSeverity() != securityBean.Critical || previousCves[result.CveStoreName] {
+			continue
+		}
+		newCves[result.CveStoreName] = true
+	}
+	cveNames := make([]string, 0, len(newCves))
+	for cveName := range newCves {
+		cveNames = append(cveNames, cveName)
+	}
+	sort.Strings(cveNames)
+	return cveNames
+}
+
+type DeployedImageRescanRepository interface {
+	Save(model *DeployedImageRescan) error
+	Update(model *DeployedImageRescan) error
+	FindByStatus(status DeployedImageRescanStatus) ([]*DeployedImageRescan, error)
+	// FindTriggeredAfter returns the re-scans triggered after triggeredAfter along with all the pending ones
+	FindTriggeredAfter(triggeredAfter time.Time) ([]*DeployedImageRescan, error)
+}
+
+type DeployedImageRescanRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeployedImageRescanRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeployedImageRescanRepositoryImpl {
+	return &DeployedImageRescanRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeployedImageRescanRepositoryImpl) Save(model *DeployedImageRescan) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *DeployedImageRescanRepositoryImpl) Update(model *DeployedImageRescan) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *DeployedImageRescanRepositoryImpl) FindByStatus(status DeployedImageRescanStatus) ([]*DeployedImageRescan, error) {
+	var models []*DeployedImageRescan
+	err := impl.dbConnection.Model(&models).
+		Where("status = ?", status).
+		Order("triggered_on ASC").
+		Select()
+	return models, err
+}
+
+func (impl *DeployedImageRescanRepositoryImpl) FindTriggeredAfter(triggeredAfter time.Time) ([]*DeployedImageRescan, error) {
+	var models []*DeployedImageRescan
+	err := impl.dbConnection.Model(&models).
+		WhereOr("triggered_on > ?", triggeredAfter).
+		WhereOr("status = ?", DeployedImageRescanPending).
+		Select()
+	return models, err
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNewCriticalCves(t *testing.T) {
+	critical := securityBean.Critical
+	high := securityBean.High
+	result := func(cveName string, severity *securityBean.Severity, legacySeverity securityBean.Severity) *ImageScanExecutionResult {
+		return &ImageScanExecutionResult{
+			CveStoreName: cveName,
+			CveStore:     CveStore{Name: cveName, Severity: legacySeverity, StandardSeverity: severity},
+		}
+	}
+	previous := []*ImageScanExecutionResult{
+		result("CVE-1", &critical, critical),
+		result("CVE-2", &high, critical),
+	}
+
+	t.Run("only critical cves absent in previous scan are returned", func(t *testing.T) {
+		current := []*ImageScanExecutionResult{
+			result("CVE-1", &critical, critical),
+			result("CVE-2", &critical, critical),
+			result("CVE-4", &critical, critical),
+			result("CVE-3", &critical, critical),
+			result("CVE-5", &high, critical),
+		}
+		assert.Equal(t, []string{"CVE-3", "CVE-4"}, GetNewCriticalCves(previous, current))
+	})
+
+	t.Run("legacy severity is used when standard severity is not set", func(t *testing.T) {
+		current := []*ImageScanExecutionResult{result("CVE-6", nil, critical)}
+		assert.Equal(t, []string{"CVE-6"}, GetNewCriticalCves(previous, current))
+	})
+
+	t.Run("same cve found in multiple packages is reported once", func(t *testing.T) {
+		current := []*ImageScanExecutionResult{
+			result("CVE-7", &critical, critical),
+			result("CVE-7", &critical, critical),
+		}
+		assert.Equal(t, []string{"CVE-7"}, GetNewCriticalCves(nil, current))
+	})
+
+	t.Run("no new critical cves", func(t *testing.T) {
+		assert.Empty(t, GetNewCriticalCves(previous, previous))
+	})
+}
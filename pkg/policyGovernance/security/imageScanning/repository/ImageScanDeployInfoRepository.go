@@ -73,6 +73,7 @@ type ImageScanDeployInfoRepository interface {
 	FetchListingGroupByObject(size int, offset int) ([]*ImageScanDeployInfo, error)
 	FetchByAppIdAndEnvId(appId int, envId int, objectType []string) (*ImageScanDeployInfo, error)
 	FindByTypeMetaAndTypeId(scanObjectMetaId int, objectType string) (*ImageScanDeployInfo, error)
+	FindByExecutionHistoryId(executionHistoryId int) ([]*ImageScanDeployInfo, error)
 	ScanListingWithFilter(request *repoBean.ImageScanFilter, size int, offset int, deployInfoIds []int) ([]*ImageScanListingResponse, error)
 }
 
@@ -148,6 +149,15 @@ func (impl ImageScanDeployInfoRepositoryImpl) FindByTypeMetaAndTypeId(scanObject
 	return &model, err
 }
 
+// FindByExecutionHistoryId returns the deployed objects currently running the image scanned in executionHistoryId
+func (impl ImageScanDeployInfoRepositoryImpl) FindByExecutionHistoryId(executionHistoryId int) ([]*ImageScanDeployInfo, error) {
+	var models []*ImageScanDeployInfo
+	err := impl.dbConnection.Model(&models).
+		Where("? = ANY(image_scan_execution_history_id)", executionHistoryId).
+		Select()
+	return models, err
+}
+
 func (impl ImageScanDeployInfoRepositoryImpl) ScanListingWithFilter(request *repoBean.ImageScanFilter, size int, offset int, deployInfoIds []int) ([]*ImageScanListingResponse, error) {
 	var models []*ImageScanListingResponse
 	query, queryParams := impl.scanListingQueryBuilder(request, size, offset, deployInfoIds)
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DeploymentVulnerabilityOverride allows deployment of a vulnerable artifact on a cd pipeline till ExpiresOn
+type DeploymentVulnerabilityOverride struct {
+	tableName    struct{}  `sql:"deployment_vulnerability_override" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	CdPipelineId int       `sql:"cd_pipeline_id,notnull"`
+	CiArtifactId int       `sql:"ci_artifact_id,notnull"`
+	ImageDigest  string    `sql:"image_digest"`
+	Reason       string    `sql:"reason,notnull"`
+	ExpiresOn    time.Time `sql:"expires_on,notnull"`
+	Active       bool      `sql:"active,notnull"`
+	RevokedBy    int32     `sql:"revoked_by"`
+	RevokedOn    time.Time `sql:"revoked_on"`
+	sql.AuditLog
+}
+
+func (override *DeploymentVulnerabilityOverride) IsExpired() bool {
+	return !override.ExpiresOn.After(time.Now())
+}
+
+type VulnerabilityOverrideAuditAction string
+
+const (
+	VulnerabilityOverrideCreated VulnerabilityOverrideAuditAction = "CREATED"
+	VulnerabilityOverrideRevoked VulnerabilityOverrideAuditAction = "REVOKED"
+	VulnerabilityOverrideApplied VulnerabilityOverrideAuditAction = "APPLIED"
+)
+
+// DeploymentVulnerabilityOverrideAudit keeps track of every action taken on a DeploymentVulnerabilityOverride
+type DeploymentVulnerabilityOverrideAudit struct {
+	tableName          struct{}                         `sql:"deployment_vulnerability_override_audit" pg:",discard_unknown_columns"`
+	Id                 int                              `sql:"id,pk"`
+	OverrideId         int                              `sql:"override_id,notnull"`
+	Action             VulnerabilityOverrideAuditAction `sql:"action,notnull"`
+	CdWorkflowRunnerId int                              `sql:"cd_workflow_runner_id"`
+	BlockedCves        string                           `sql:"blocked_cves"`
+	sql.AuditLog
+}
+
+type VulnerabilityOverrideRepository interface {
+	Save(tx *pg.Tx, model *DeploymentVulnerabilityOverride) error
+	Update(tx *pg.Tx, model *DeploymentVulnerabilityOverride) error
+	FindById(id int) (*DeploymentVulnerabilityOverride, error)
+	FindActiveByPipelineIdAndArtifactId(cdPipelineId, ciArtifactId int) (*DeploymentVulnerabilityOverride, error)
+	FindAllByPipelineId(cdPipelineId int) ([]*DeploymentVulnerabilityOverride, error)
+	SaveAudit(tx *pg.Tx, model *DeploymentVulnerabilityOverrideAudit) error
+	FindAuditsByOverrideIds(overrideIds []int) ([]*DeploymentVulnerabilityOverrideAudit, error)
+}
+
+type VulnerabilityOverrideRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewVulnerabilityOverrideRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *VulnerabilityOverrideRepositoryImpl {
+	return &VulnerabilityOverrideRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) Save(tx *pg.Tx, model *DeploymentVulnerabilityOverride) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) Update(tx *pg.Tx, model *DeploymentVulnerabilityOverride) error {
+	if tx != nil {
+		return tx.Update(model)
+	}
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) FindById(id int) (*DeploymentVulnerabilityOverride, error) {
+	model := &DeploymentVulnerabilityOverride{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Select()
+	return model, err
+}
+
+// FindActiveByPipelineIdAndArtifactId returns the latest non-revoked and non-expired override, pg.ErrNoRows if none exists
+func (impl *VulnerabilityOverrideRepositoryImpl) FindActiveByPipelineIdAndArtifactId(cdPipelineId, ciArtifactId int) (*DeploymentVulnerabilityOverride, error) {
+	model := &DeploymentVulnerabilityOverride{}
+	err := impl.dbConnection.Model(model).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("active = ?", true).
+		Where("expires_on > ?", time.Now()).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return model, err
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) FindAllByPipelineId(cdPipelineId int) ([]*DeploymentVulnerabilityOverride, error) {
+	var models []*DeploymentVulnerabilityOverride
+	err := impl.dbConnection.Model(&models).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) SaveAudit(tx *pg.Tx, model *DeploymentVulnerabilityOverrideAudit) error {
+	if tx != nil {
+		return tx.Insert(model)
+	}
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *VulnerabilityOverrideRepositoryImpl) FindAuditsByOverrideIds(overrideIds []int) ([]*DeploymentVulnerabilityOverrideAudit, error) {
+	var models []*DeploymentVulnerabilityOverrideAudit
+	if len(overrideIds) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("override_id in (?)", pg.In(overrideIds)).
+		Order("id ASC").
+		Select()
+	return models, err
+}
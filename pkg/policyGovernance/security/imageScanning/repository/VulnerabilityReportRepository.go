@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"fmt"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type VulnerabilityReportFilter struct {
+	From     time.Time
+	To       time.Time
+	TeamIds  []int
+	EnvIds   []int
+	AppIds   []int
+	ProdOnly bool
+	// DeploymentStatuses are the cd workflow runner statuses of a deployment after which the image is running in the environment
+	DeploymentStatuses []string
+}
+
+// VulnerabilityReportDeployment is a deployment of a cd pipeline, the image runs in the environment from DeployedOn till ReplacedOn
+type VulnerabilityReportDeployment struct {
+	PipelineId  int       `sql:"pipeline_id"`
+	AppId       int       `sql:"app_id"`
+	AppName     string    `sql:"app_name"`
+	TeamId      int       `sql:"team_id"`
+	TeamName    string    `sql:"team_name"`
+	EnvId       int       `sql:"env_id"`
+	EnvName     string    `sql:"env_name"`
+	IsProd      bool      `sql:"is_prod"`
+	Image       string    `sql:"image"`
+	ImageDigest string    `sql:"image_digest"`
+	DeployedOn  time.Time `sql:"deployed_on"`
+	// ReplacedOn is zero for the deployment currently running
+	ReplacedOn time.Time `sql:"replaced_on"`
+}
+
+// VulnerabilityReportScanFinding is a cve found in a completed scan, CveName is empty for scans without any finding
+type VulnerabilityReportScanFinding struct {
+	ExecutionHistoryId int                   `sql:"execution_history_id"`
+	Image              string                `sql:"image"`
+	ImageDigest        string                `sql:"image_digest"`
+	ExecutionTime      time.Time             `sql:"execution_time"`
+	CveName            string                `sql:"cve_name"`
+	Severity           securityBean.Severity `sql:"severity"`
+}
+
+type VulnerabilityReportRepository interface {
+	// FindDeployments returns the deployments of active cd pipelines whose image was running at any time within the filter time range
+	FindDeployments(filter *VulnerabilityReportFilter) ([]*VulnerabilityReportDeployment, error)
+	// FindCompletedScanFindings returns the findings of the completed scans of the images executed till scannedBefore, ordered by execution time
+	FindCompletedScanFindings(images []string, scannedBefore time.Time) ([]*VulnerabilityReportScanFinding, error)
+}
+
+type VulnerabilityReportRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewVulnerabilityReportRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *VulnerabilityReportRepositoryImpl {
+	return &VulnerabilityReportRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *VulnerabilityReportRepositoryImpl) FindDeployments(filter *VulnerabilityReportFilter) ([]*VulnerabilityReportDeployment, error) {
+	var deployments []*VulnerabilityReportDeployment
+	query, queryParams := buildVulnerabilityReportDeploymentQuery(filter)
+	_, err := impl.dbConnection.Query(&deployments, query, queryParams...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployments for vulnerability report", "filter", filter, "err", err)
+		return nil, err
+	}
+	return deployments, nil
+}
+
+func buildVulnerabilityReportDeploymentQuery(filter *VulnerabilityReportFilter) (string, []interface{}) {
+	queryParams := []interface{}{pg.In(filter.DeploymentStatuses), filter.To, filter.From}
+	conditions := make([]string, 0, 4)
+	if len(filter.TeamIds) > 0 {
+		conditions = append(conditions, "a.team_id IN (?)")
+		queryParams = append(queryParams, pg.In(filter.TeamIds))
+	}
+	if len(filter.EnvIds) > 0 {
+		conditions = append(conditions, "p.environment_id IN (?)")
+		queryParams = append(queryParams, pg.In(filter.EnvIds))
+	}
+	if len(filter.AppIds) > 0 {
+		conditions = append(conditions, "p.app_id IN (?)")
+		queryParams = append(queryParams, pg.In(filter.AppIds))
+	}
+	if filter.ProdOnly {
+		conditions = append(conditions, "e.default = true")
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " AND " + strings.Join(conditions, " AND ")
+	}
+	// deployment is replaced by the next successful deployment of the pipeline
+	query := fmt.Sprintf(`SELECT d.pipeline_id, p.app_id, a.app_name, a.team_id, t.name AS team_name,
+		p.environment_id AS env_id, e.environment_name AS env_name, e.default AS is_prod,
+		ca.image, ca.image_digest, d.deployed_on, d.replaced_on
+		FROM (
+			SELECT cw.pipeline_id, cw.ci_artifact_id, cwr.started_on AS deployed_on,
+				LEAD(cwr.started_on) OVER (PARTITION BY cw.pipeline_id ORDER BY cwr.started_on) AS replaced_on
+			FROM cd_workflow_runner cwr
+			INNER JOIN cd_workflow cw ON cw.id = cwr.cd_workflow_id
+			WHERE cwr.workflow_type = 'DEPLOY' AND cwr.status IN (?) AND cwr.started_on <= ?
+		) d
+		INNER JOIN pipeline p ON p.id = d.pipeline_id AND p.deleted = false
+		INNER JOIN app a ON a.id = p.app_id AND a.active = true
+		INNER JOIN team t ON t.id = a.team_id
+		INNER JOIN environment e ON e.id = p.environment_id
+		INNER JOIN ci_artifact ca ON ca.id = d.ci_artifact_id
+		WHERE (d.replaced_on IS NULL OR d.replaced_on > ?)%s
+		ORDER BY d.pipeline_id, d.deployed_on;`, whereClause)
+	return query, queryParams
+}
+
+func (impl *VulnerabilityReportRepositoryImpl) FindCompletedScanFindings(images []string, scannedBefore time.Time) ([]*VulnerabilityReportScanFinding, error) {
+	var findings []*VulnerabilityReportScanFinding
+	if len(images) == 0 {
+		return findings, nil
+	}
+	query := `SELECT h.id AS execution_history_id, h.image, h.image_hash AS image_digest, h.execution_time,
+		r.cve_store_name AS cve_name, COALESCE(cs.standard_severity, cs.severity) AS severity
+		FROM image_scan_execution_history h
+		LEFT JOIN image_scan_execution_result r ON r.image_scan_execution_history_id = h.id
+		LEFT JOIN cve_store cs ON cs.name = r.cve_store_name
+		WHERE h.image IN (?) AND h.execution_time <= ?
+		AND EXISTS (SELECT 1 FROM scan_tool_execution_history_mapping m
+			WHERE m.image_scan_execution_history_id = h.id AND m.state = ?)
+		ORDER BY h.execution_time, h.id;`
+	_, err := impl.dbConnection.Query(&findings, query, pg.In(images), scannedBefore, ScanExecutionProcessStateCompleted)
+	if err != nil {
+		impl.logger.Errorw("error in fetching scan findings for vulnerability report", "err", err)
+		return nil, err
+	}
+	return findings, nil
+}
@@ -127,3 +127,10 @@ const (
 	Asc  SortOrder = "ASC"
 	Desc SortOrder = "DESC"
 )
+
+// ImageIdentifier identifies the image on which a cve policy is evaluated,
+// image scoped cve exceptions are matched against it
+type ImageIdentifier struct {
+	ImageDigest  string
+	CiArtifactId int
+}
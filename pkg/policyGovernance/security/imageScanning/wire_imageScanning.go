@@ -23,6 +23,18 @@ var ImageScanningWireSet = wire.NewSet(
 	NewImageScanDeployInfoService,
 	wire.Bind(new(ImageScanDeployInfoService), new(*ImageScanDeployInfoServiceImpl)),
 
+	NewVulnerabilityOverrideServiceImpl,
+	wire.Bind(new(VulnerabilityOverrideService), new(*VulnerabilityOverrideServiceImpl)),
+
+	NewCveExceptionServiceImpl,
+	wire.Bind(new(CveExceptionService), new(*CveExceptionServiceImpl)),
+
+	NewDeployedImageRescanServiceImpl,
+	wire.Bind(new(DeployedImageRescanService), new(*DeployedImageRescanServiceImpl)),
+
+	NewVulnerabilityReportServiceImpl,
+	wire.Bind(new(VulnerabilityReportService), new(*VulnerabilityReportServiceImpl)),
+
 	read.NewImageScanResultReadServiceImpl,
 	wire.Bind(new(read.ImageScanResultReadService), new(*read.ImageScanResultReadServiceImpl)),
 
@@ -43,4 +55,12 @@ var ImageScanningWireSet = wire.NewSet(
 	wire.Bind(new(repository.CvePolicyRepository), new(*repository.CvePolicyRepositoryImpl)),
 	repository.NewScanToolExecutionHistoryMappingRepositoryImpl,
 	wire.Bind(new(repository.ScanToolExecutionHistoryMappingRepository), new(*repository.ScanToolExecutionHistoryMappingRepositoryImpl)),
+	repository.NewVulnerabilityOverrideRepositoryImpl,
+	wire.Bind(new(repository.VulnerabilityOverrideRepository), new(*repository.VulnerabilityOverrideRepositoryImpl)),
+	repository.NewCveExceptionRepositoryImpl,
+	wire.Bind(new(repository.CveExceptionRepository), new(*repository.CveExceptionRepositoryImpl)),
+	repository.NewDeployedImageRescanRepositoryImpl,
+	wire.Bind(new(repository.DeployedImageRescanRepository), new(*repository.DeployedImageRescanRepositoryImpl)),
+	repository.NewVulnerabilityReportRepositoryImpl,
+	wire.Bind(new(repository.VulnerabilityReportRepository), new(*repository.VulnerabilityReportRepositoryImpl)),
 )
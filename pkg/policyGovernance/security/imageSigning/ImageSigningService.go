@@ -0,0 +1,734 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/helper"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/opencontainers/go-digest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"net/http"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"strconv"
+	"time"
+)
+
+const (
+	registryConnectionInsecure       = "insecure"
+	registryConnectionSecureWithCert = "secure-with-cert"
+	defaultImageTag                  = "latest"
+)
+
+// ImageSigningService signs the images built by ci pipelines with cosign compatible signatures and verifies
+// them against the signature policy of the environment before deployment
+type ImageSigningService interface {
+	CreateKey(request *bean.SigningKeyRequest) (*bean.SigningKeyDto, error)
+	GetAllKeys() ([]*bean.SigningKeyDto, error)
+	GetKeyById(keyId int) (*bean.SigningKeyDto, error)
+	DeleteKey(keyId int, userId int32) error
+
+	SaveAppSigningConfig(request *bean.AppSigningConfigDto) (*bean.AppSigningConfigDto, error)
+	GetAppSigningConfig(appId int) (*bean.AppSigningConfigDto, error)
+	SaveSignaturePolicy(request *bean.SignaturePolicyDto) (*bean.SignaturePolicyDto, error)
+	GetSignaturePolicy(envId int) (*bean.SignaturePolicyDto, error)
+
+	// SignArtifacts signs the images of the artifacts if signing is enabled for the app, failures are recorded
+	// against the artifact and not returned
+	SignArtifacts(appId int, artifacts []*repository2.CiArtifact, userId int32)
+	// SignArtifact signs the image of an artifact of the app again
+	SignArtifact(appId, ciArtifactId int, userId int32) (*bean.ArtifactSignatureDto, error)
+	GetArtifactSignatures(appId, ciArtifactId int) ([]*bean.ArtifactSignatureDto, error)
+	// VerifyArtifact returns *bean.UnverifiedImageError if the signature policy of the pipeline environment is enabled
+	// and the image of the artifact is not signed by a trusted key
+	VerifyArtifact(cdPipeline *pipelineConfig.Pipeline, artifact *repository2.CiArtifact) error
+}
+
+type ImageSigningServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	imageSigningKeyRepository     repository.ImageSigningKeyRepository
+	imageSigningPolicyRepository  repository.ImageSigningPolicyRepository
+	artifactSignatureRepository   repository.ArtifactSignatureRepository
+	ciArtifactRepository          repository2.CiArtifactRepository
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	ciPipelineConfigReadService   read.CiPipelineConfigReadService
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	k8sUtil                       *k8s.K8sServiceImpl
+	config                        *bean.ImageSigningConfig
+}
+
+func NewImageSigningServiceImpl(logger *zap.SugaredLogger,
+	imageSigningKeyRepository repository.ImageSigningKeyRepository,
+	imageSigningPolicyRepository repository.ImageSigningPolicyRepository,
+	artifactSignatureRepository repository.ArtifactSignatureRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciPipelineConfigReadService read.CiPipelineConfigReadService,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	k8sUtil *k8s.K8sServiceImpl) (*ImageSigningServiceImpl, error) {
+	config, err := bean.GetImageSigningConfig()
+	if err != nil {
+		logger.Errorw("error in parsing image signing config", "err", err)
+		return nil, err
+	}
+	return &ImageSigningServiceImpl{
+		logger:                        logger,
+		imageSigningKeyRepository:     imageSigningKeyRepository,
+		imageSigningPolicyRepository:  imageSigningPolicyRepository,
+		artifactSignatureRepository:   artifactSignatureRepository,
+		ciArtifactRepository:          ciArtifactRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+		ciPipelineConfigReadService:   ciPipelineConfigReadService,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		k8sUtil:                       k8sUtil,
+		config:                        config,
+	}, nil
+}
+
+func (impl *ImageSigningServiceImpl) CreateKey(request *bean.SigningKeyRequest) (*bean.SigningKeyDto, error) {
+	existingKey, err := impl.imageSigningKeyRepository.FindActiveByName(request.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching signing key by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if existingKey != nil && existingKey.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, "signing key with the same name already exists", "signing key with the same name already exists")
+	}
+	secretData, publicKey, err := getKeySecretData(request)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	// key is activated once its private key is saved in the secret
+	signingKey := &repository.ImageSigningKey{
+		Name:        request.Name,
+		Description: request.Description,
+		PublicKey:   string(publicKey),
+		AuditLog:    sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.imageSigningKeyRepository.Save(signingKey)
+	if err != nil {
+		impl.logger.Errorw("error in saving signing key", "name", request.Name, "err", err)
+		return nil, err
+	}
+	secretName := bean.SigningKeySecretPrefix + strconv.Itoa(signingKey.Id)
+	err = impl.createKeySecret(secretName, signingKey.Id, secretData)
+	if err != nil {
+		return nil, err
+	}
+	signingKey.SecretName = secretName
+	signingKey.Active = true
+	signingKey.UpdateAuditLog(request.UserId)
+	err = impl.imageSigningKeyRepository.Update(signingKey)
+	if err != nil {
+		impl.logger.Errorw("error in activating signing key", "keyId", signingKey.Id, "err", err)
+		return nil, err
+	}
+	return adaptSigningKey(signingKey), nil
+}
+
+func (impl *ImageSigningServiceImpl) GetAllKeys() ([]*bean.SigningKeyDto, error) {
+	signingKeys, err := impl.imageSigningKeyRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching signing keys", "err", err)
+		return nil, err
+	}
+	keys := make([]*bean.SigningKeyDto, 0, len(signingKeys))
+	for _, signingKey := range signingKeys {
+		keys = append(keys, adaptSigningKey(signingKey))
+	}
+	return keys, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetKeyById(keyId int) (*bean.SigningKeyDto, error) {
+	signingKey, err := impl.getSigningKey(keyId)
+	if err != nil {
+		return nil, err
+	}
+	return adaptSigningKey(signingKey), nil
+}
+
+func (impl *ImageSigningServiceImpl) DeleteKey(keyId int, userId int32) error {
+	signingKey, err := impl.getSigningKey(keyId)
+	if err != nil {
+		return err
+	}
+	signingConfigs, err := impl.imageSigningPolicyRepository.FindEnabledSigningConfigsByKeyId(keyId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching signing configs of key", "keyId", keyId, "err", err)
+		return err
+	}
+	if len(signingConfigs) > 0 {
+		return util.NewApiError(http.StatusConflict, "signing key is used by apps for signing images", "signing key is used by apps for signing images")
+	}
+	signingKey.Active = false
+	signingKey.UpdateAuditLog(userId)
+	err = impl.imageSigningKeyRepository.Update(signingKey)
+	if err != nil {
+		impl.logger.Errorw("error in deleting signing key", "keyId", keyId, "err", err)
+		return err
+	}
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return err
+	}
+	err = impl.k8sUtil.DeleteSecret(impl.config.KeyNamespace, signingKey.SecretName, k8sClient)
+	if err != nil && !k8sError.IsNotFound(err) {
+		impl.logger.Errorw("error in deleting signing key secret", "secretName", signingKey.SecretName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ImageSigningServiceImpl) SaveAppSigningConfig(request *bean.AppSigningConfigDto) (*bean.AppSigningConfigDto, error) {
+	_, err := impl.getSigningKey(request.SigningKeyId)
+	if err != nil {
+		return nil, err
+	}
+	signingConfig, err := impl.imageSigningPolicyRepository.FindSigningConfigByAppId(request.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing config", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		signingConfig = &repository.ImageSigningConfig{
+			AppId:        request.AppId,
+			SigningKeyId: request.SigningKeyId,
+			Enabled:      request.Enabled,
+			AuditLog:     sql.NewDefaultAuditLog(request.UserId),
+		}
+		err = impl.imageSigningPolicyRepository.SaveSigningConfig(signingConfig)
+	} else {
+		signingConfig.SigningKeyId = request.SigningKeyId
+		signingConfig.Enabled = request.Enabled
+		signingConfig.UpdateAuditLog(request.UserId)
+		err = impl.imageSigningPolicyRepository.UpdateSigningConfig(signingConfig)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving image signing config", "appId", request.AppId, "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetAppSigningConfig(appId int) (*bean.AppSigningConfigDto, error) {
+	signingConfig, err := impl.imageSigningPolicyRepository.FindSigningConfigByAppId(appId)
+	if util.IsErrNoRows(err) {
+		return &bean.AppSigningConfigDto{AppId: appId}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching image signing config", "appId", appId, "err", err)
+		return nil, err
+	}
+	return &bean.AppSigningConfigDto{
+		AppId:        signingConfig.AppId,
+		SigningKeyId: signingConfig.SigningKeyId,
+		Enabled:      signingConfig.Enabled,
+	}, nil
+}
+
+func (impl *ImageSigningServiceImpl) SaveSignaturePolicy(request *bean.SignaturePolicyDto) (*bean.SignaturePolicyDto, error) {
+	if len(request.TrustedKeyIds) > 0 {
+		trustedKeys, err := impl.imageSigningKeyRepository.FindActiveByIds(request.TrustedKeyIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching trusted signing keys", "keyIds", request.TrustedKeyIds, "err", err)
+			return nil, err
+		}
+		if len(trustedKeys) != len(request.TrustedKeyIds) {
+			return nil, util.NewApiError(http.StatusBadRequest, "trusted signing keys not found", "trusted signing keys not found")
+		}
+	}
+	policy, err := impl.imageSigningPolicyRepository.FindPolicyByEnvId(request.EnvId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signature policy", "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) {
+		policy = &repository.ImageSignaturePolicy{
+			EnvId:         request.EnvId,
+			Enabled:       request.Enabled,
+			TrustedKeyIds: request.TrustedKeyIds,
+			AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+		}
+		err = impl.imageSigningPolicyRepository.SavePolicy(policy)
+	} else {
+		policy.Enabled = request.Enabled
+		policy.TrustedKeyIds = request.TrustedKeyIds
+		policy.UpdateAuditLog(request.UserId)
+		err = impl.imageSigningPolicyRepository.UpdatePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving image signature policy", "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetSignaturePolicy(envId int) (*bean.SignaturePolicyDto, error) {
+	policy, err := impl.imageSigningPolicyRepository.FindPolicyByEnvId(envId)
+	if util.IsErrNoRows(err) {
+		return &bean.SignaturePolicyDto{EnvId: envId, TrustedKeyIds: []int{}}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "envId", envId, "err", err)
+		return nil, err
+	}
+	trustedKeyIds := policy.TrustedKeyIds
+	if trustedKeyIds == nil {
+		trustedKeyIds = []int{}
+	}
+	return &bean.SignaturePolicyDto{
+		EnvId:         policy.EnvId,
+		Enabled:       policy.Enabled,
+		TrustedKeyIds: trustedKeyIds,
+	}, nil
+}
+
+func (impl *ImageSigningServiceImpl) SignArtifacts(appId int, artifacts []*repository2.CiArtifact, userId int32) {
+	signingConfig, err := impl.imageSigningPolicyRepository.FindSigningConfigByAppId(appId)
+	if util.IsErrNoRows(err) {
+		return
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching image signing config", "appId", appId, "err", err)
+		return
+	}
+	if !signingConfig.Enabled {
+		return
+	}
+	// the images are signed inline with the ci success event, the total time is bounded so that a slow registry
+	// does not hold back the auto triggers of the ci pipeline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.CiSigningTimeoutSecs)*time.Second)
+	defer cancel()
+	for _, artifact := range artifacts {
+		if len(artifact.Image) == 0 {
+			continue
+		}
+		_, err = impl.signArtifact(ctx, artifact, signingConfig.SigningKeyId, userId)
+		if err != nil {
+			impl.logger.Errorw("error in signing artifact image", "appId", appId, "ciArtifactId", artifact.Id, "image", artifact.Image, "err", err)
+		}
+	}
+}
+
+func (impl *ImageSigningServiceImpl) SignArtifact(appId, ciArtifactId int, userId int32) (*bean.ArtifactSignatureDto, error) {
+	artifact, err := impl.getArtifactOfApp(appId, ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	signingConfig, err := impl.imageSigningPolicyRepository.FindSigningConfigByAppId(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing config", "appId", appId, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || !signingConfig.Enabled {
+		return nil, util.NewApiError(http.StatusBadRequest, "image signing is not enabled for the app", "image signing is not enabled for the app")
+	}
+	signature, err := impl.signArtifact(context.Background(), artifact, signingConfig.SigningKeyId, userId)
+	if signature == nil {
+		return nil, err
+	}
+	// failure to sign is recorded and returned in the signature status
+	return adaptArtifactSignature(signature), nil
+}
+
+func (impl *ImageSigningServiceImpl) GetArtifactSignatures(appId, ciArtifactId int) ([]*bean.ArtifactSignatureDto, error) {
+	_, err := impl.getArtifactOfApp(appId, ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := impl.artifactSignatureRepository.FindByCiArtifactId(ciArtifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching artifact signatures", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	signatureDtos := make([]*bean.ArtifactSignatureDto, 0, len(signatures))
+	for _, signature := range signatures {
+		signatureDtos = append(signatureDtos, adaptArtifactSignature(signature))
+	}
+	return signatureDtos, nil
+}
+
+func (impl *ImageSigningServiceImpl) VerifyArtifact(cdPipeline *pipelineConfig.Pipeline, artifact *repository2.CiArtifact) error {
+	if artifact == nil {
+		return nil
+	}
+	policy, err := impl.imageSigningPolicyRepository.FindPolicyByEnvId(cdPipeline.EnvironmentId)
+	if util.IsErrNoRows(err) {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "envId", cdPipeline.EnvironmentId, "err", err)
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+	unverifiedImageError := func(imageDigest, reason string) error {
+		return bean.NewUnverifiedImageError(artifact.Image, imageDigest, cdPipeline.EnvironmentId, reason)
+	}
+	trustedKeys, err := impl.getTrustedKeys(policy)
+	if err != nil {
+		return err
+	}
+	if len(trustedKeys) == 0 {
+		return unverifiedImageError(artifact.ImageDigest, "no trusted signing key is active")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), impl.getRegistryTimeout())
+	defer cancel()
+	reference, remoteRepository, imageDigest, err := impl.getImageRepository(ctx, artifact)
+	if err != nil {
+		impl.logger.Errorw("error in resolving image for signature verification", "ciArtifactId", artifact.Id, "image", artifact.Image, "err", err)
+		return unverifiedImageError(imageDigest, err.Error())
+	}
+	signatures, err := helper.FetchSignatures(ctx, remoteRepository, imageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signatures", "ciArtifactId", artifact.Id, "image", artifact.Image, "err", err)
+		return unverifiedImageError(imageDigest, err.Error())
+	}
+	if len(signatures) == 0 {
+		return unverifiedImageError(imageDigest, "image is not signed")
+	}
+	keyId, verified := findTrustedSignature(signatures, trustedKeys, imageDigest)
+	if !verified {
+		return unverifiedImageError(imageDigest, "image is not signed by a trusted key")
+	}
+	impl.logger.Infow("verified image signature", "cdPipelineId", cdPipeline.Id, "image", helper.GetDockerReference(reference), "imageDigest", imageDigest, "signingKeyId", keyId)
+	return nil
+}
+
+// signArtifact pushes the signature of the artifact image and records it, the record is returned for failures as well
+func (impl *ImageSigningServiceImpl) signArtifact(ctx context.Context, artifact *repository2.CiArtifact, signingKeyId int, userId int32) (*repository.CiArtifactSignature, error) {
+	signature := &repository.CiArtifactSignature{
+		CiArtifactId: artifact.Id,
+		Image:        artifact.Image,
+		ImageDigest:  artifact.ImageDigest,
+		SigningKeyId: signingKeyId,
+		Status:       bean.SignatureStatusSigned,
+		SignedOn:     time.Now(),
+		AuditLog:     sql.NewDefaultAuditLog(userId),
+	}
+	signErr := impl.pushArtifactSignature(ctx, signature, artifact)
+	if signErr != nil {
+		signature.Status = bean.SignatureStatusFailed
+		signature.Message = signErr.Error()
+	}
+	err := impl.artifactSignatureRepository.Save(signature)
+	if err != nil {
+		impl.logger.Errorw("error in saving artifact signature", "ciArtifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	return signature, signErr
+}
+
+func (impl *ImageSigningServiceImpl) pushArtifactSignature(ctx context.Context, signature *repository.CiArtifactSignature, artifact *repository2.CiArtifact) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("signing timed out: %w", err)
+	}
+	signingKey, err := impl.getSigningKey(signature.SigningKeyId)
+	if err != nil {
+		return err
+	}
+	privateKey, err := impl.getPrivateKey(signingKey)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, impl.getRegistryTimeout())
+	defer cancel()
+	reference, remoteRepository, imageDigest, err := impl.getImageRepository(ctx, artifact)
+	if err != nil {
+		return err
+	}
+	signature.ImageDigest = imageDigest
+	payload, err := helper.BuildPayload(helper.GetDockerReference(reference), imageDigest)
+	if err != nil {
+		return err
+	}
+	signature.Signature, err = helper.Sign(privateKey, payload)
+	if err != nil {
+		return fmt.Errorf("signing image: %w", err)
+	}
+	tag, err := helper.PushSignature(ctx, remoteRepository, imageDigest, payload, signature.Signature)
+	if err != nil {
+		return err
+	}
+	signature.SignatureRef = helper.GetDockerReference(reference) + ":" + tag
+	return nil
+}
+
+// getImageRepository returns the registry repository of the artifact image and the manifest digest of the image,
+// the digest is resolved from the registry if it is not recorded in the artifact
+func (impl *ImageSigningServiceImpl) getImageRepository(ctx context.Context, artifact *repository2.CiArtifact) (registry.Reference, *remote.Repository, string, error) {
+	imageDigest := artifact.ImageDigest
+	reference, err := helper.ParseImage(artifact.Image)
+	if err != nil {
+		return reference, nil, imageDigest, fmt.Errorf("invalid image %q: %w", artifact.Image, err)
+	}
+	credential, err := impl.getRegistryCredential(artifact, reference.Registry)
+	if err != nil {
+		return reference, nil, imageDigest, err
+	}
+	remoteRepository, err := helper.NewRemoteRepository(reference, credential, impl.getRegistryTimeout())
+	if err != nil {
+		return reference, nil, imageDigest, err
+	}
+	if _, err = digest.Parse(imageDigest); err != nil {
+		tag := reference.Reference
+		if len(tag) == 0 {
+			tag = defaultImageTag
+		}
+		imageDigest, err = helper.ResolveDigest(ctx, remoteRepository, tag)
+		if err != nil {
+			return reference, nil, artifact.ImageDigest, fmt.Errorf("resolving image digest: %w", err)
+		}
+	}
+	return reference, remoteRepository, imageDigest, nil
+}
+
+// getRegistryCredential returns the credential of the container registry the artifact was pushed to, the registry is
+// matched by host for artifacts without a registry (external ci)
+func (impl *ImageSigningServiceImpl) getRegistryCredential(artifact *repository2.CiArtifact, host string) (*helper.RegistryCredential, error) {
+	credential := &helper.RegistryCredential{PlainHttp: impl.config.IsPlainHttpRegistry(host)}
+	registryId := ""
+	if artifact.IsRegistryCredentialMapped() {
+		registryId = artifact.CredentialSourceValue
+	} else if artifact.PipelineId > 0 {
+		dockerRegistryId, err := impl.ciPipelineConfigReadService.GetDockerRegistryIdForCiPipeline(artifact.PipelineId, artifact)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching docker registry of ci pipeline", "ciPipelineId", artifact.PipelineId, "err", err)
+			return nil, err
+		}
+		if dockerRegistryId != nil {
+			registryId = *dockerRegistryId
+		}
+	}
+	if len(registryId) == 0 {
+		stores, err := impl.dockerArtifactStoreRepository.FindAllActiveForAutocomplete()
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching container registries", "err", err)
+			return nil, err
+		}
+		for _, store := range stores {
+			if helper.GetRegistryHost(store.RegistryURL) == host {
+				registryId = store.Id
+				break
+			}
+		}
+	}
+	if len(registryId) == 0 {
+		// registry is not added in devtron, accessed anonymously
+		return credential, nil
+	}
+	store, err := impl.dockerArtifactStoreRepository.FindOne(registryId)
+	if util.IsErrNoRows(err) {
+		return credential, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching container registry", "registryId", registryId, "err", err)
+		return nil, err
+	}
+	credential.Username, credential.Password = store.Username, store.Password
+	if store.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		credential.Username, credential.Password, err = dockerRegistry.CreateCredentialForEcr(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+		if err != nil {
+			impl.logger.Errorw("error in creating ecr credential", "registryId", registryId, "err", err)
+			return nil, err
+		}
+	}
+	credential.Insecure = store.Connection == registryConnectionInsecure
+	if store.Connection == registryConnectionSecureWithCert {
+		credential.Cert = store.Cert
+	}
+	return credential, nil
+}
+
+func (impl *ImageSigningServiceImpl) getTrustedKeys(policy *repository.ImageSignaturePolicy) ([]*trustedKey, error) {
+	var signingKeys []*repository.ImageSigningKey
+	var err error
+	if len(policy.TrustedKeyIds) == 0 {
+		signingKeys, err = impl.imageSigningKeyRepository.FindAllActive()
+	} else {
+		signingKeys, err = impl.imageSigningKeyRepository.FindActiveByIds(policy.TrustedKeyIds)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching trusted signing keys", "envId", policy.EnvId, "err", err)
+		return nil, err
+	}
+	trustedKeys := make([]*trustedKey, 0, len(signingKeys))
+	for _, signingKey := range signingKeys {
+		publicKey, err := helper.ParsePublicKey([]byte(signingKey.PublicKey))
+		if err != nil {
+			impl.logger.Errorw("error in parsing public key of signing key", "keyId", signingKey.Id, "err", err)
+			continue
+		}
+		trustedKeys = append(trustedKeys, &trustedKey{id: signingKey.Id, publicKey: publicKey})
+	}
+	return trustedKeys, nil
+}
+
+func (impl *ImageSigningServiceImpl) getSigningKey(keyId int) (*repository.ImageSigningKey, error) {
+	signingKey, err := impl.imageSigningKeyRepository.FindById(keyId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "signing key not found", "signing key not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching signing key", "keyId", keyId, "err", err)
+		return nil, err
+	}
+	return signingKey, nil
+}
+
+func (impl *ImageSigningServiceImpl) getArtifactOfApp(appId, ciArtifactId int) (*repository2.CiArtifact, error) {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipeline of artifact", "ciPipelineId", artifact.PipelineId, "err", err)
+		return nil, err
+	}
+	if ciPipeline == nil || ciPipeline.AppId != appId {
+		return nil, util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	}
+	return artifact, nil
+}
+
+func (impl *ImageSigningServiceImpl) createKeySecret(secretName string, keyId int, data map[string][]byte) error {
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return err
+	}
+	labels := map[string]string{
+		bean.SigningKeySecretLabel:   "true",
+		bean.SigningKeySecretLabelId: strconv.Itoa(keyId),
+	}
+	_, err = impl.k8sUtil.CreateSecret(impl.config.KeyNamespace, data, secretName, corev1.SecretTypeOpaque, k8sClient, labels, nil)
+	if err != nil {
+		impl.logger.Errorw("error in creating signing key secret", "secretName", secretName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ImageSigningServiceImpl) getPrivateKey(signingKey *repository.ImageSigningKey) (*ecdsa.PrivateKey, error) {
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return nil, err
+	}
+	secret, err := impl.k8sUtil.GetSecret(impl.config.KeyNamespace, signingKey.SecretName, k8sClient)
+	if err != nil {
+		impl.logger.Errorw("error in fetching signing key secret", "secretName", signingKey.SecretName, "err", err)
+		return nil, err
+	}
+	privateKey, err := helper.ParsePrivateKey(secret.Data[bean.SigningKeySecretKey], secret.Data[bean.SigningKeySecretPwdKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key of signing key %s: %w", signingKey.Name, err)
+	}
+	return privateKey, nil
+}
+
+func (impl *ImageSigningServiceImpl) getRegistryTimeout() time.Duration {
+	return time.Duration(impl.config.RegistryTimeoutSecs) * time.Second
+}
+
+type trustedKey struct {
+	id        int
+	publicKey *ecdsa.PublicKey
+}
+
+// findTrustedSignature returns the id of the first trusted key which signed a payload of the image digest
+func findTrustedSignature(signatures []*helper.Signature, trustedKeys []*trustedKey, imageDigest string) (int, bool) {
+	for _, signature := range signatures {
+		if helper.ValidatePayload(signature.Payload, imageDigest) != nil {
+			continue
+		}
+		for _, key := range trustedKeys {
+			if helper.VerifySignature(key.publicKey, signature.Payload, signature.Signature) {
+				return key.id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// getKeySecretData returns the secret data holding the private key of the request, a key pair is generated if the
+// request has no private key
+func getKeySecretData(request *bean.SigningKeyRequest) (map[string][]byte, []byte, error) {
+	privateKeyPem, password := []byte(request.PrivateKey), []byte(request.Password)
+	var privateKey *ecdsa.PrivateKey
+	var err error
+	if len(privateKeyPem) == 0 {
+		privateKey, err = helper.GenerateKey()
+		if err == nil {
+			privateKeyPem, err = helper.MarshalPrivateKey(privateKey)
+		}
+		password = nil
+	} else {
+		privateKey, err = helper.ParsePrivateKey(privateKeyPem, password)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyPem, err := helper.MarshalPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := map[string][]byte{
+		bean.SigningKeySecretKey:    privateKeyPem,
+		bean.SigningKeySecretPwdKey: password,
+		bean.SigningKeySecretPubKey: publicKeyPem,
+	}
+	return data, publicKeyPem, nil
+}
+
+func adaptSigningKey(signingKey *repository.ImageSigningKey) *bean.SigningKeyDto {
+	return &bean.SigningKeyDto{
+		Id:          signingKey.Id,
+		Name:        signingKey.Name,
+		Description: signingKey.Description,
+		PublicKey:   signingKey.PublicKey,
+		Active:      signingKey.Active,
+		CreatedOn:   signingKey.CreatedOn,
+	}
+}
+
+func adaptArtifactSignature(signature *repository.CiArtifactSignature) *bean.ArtifactSignatureDto {
+	return &bean.ArtifactSignatureDto{
+		Id:           signature.Id,
+		CiArtifactId: signature.CiArtifactId,
+		Image:        signature.Image,
+		ImageDigest:  signature.ImageDigest,
+		SigningKeyId: signature.SigningKeyId,
+		SignatureRef: signature.SignatureRef,
+		Status:       signature.Status,
+		Message:      signature.Message,
+		SignedOn:     signature.SignedOn,
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/helper"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindTrustedSignature(t *testing.T) {
+	signingKey, err := helper.GenerateKey()
+	assert.NoError(t, err)
+	otherKey, err := helper.GenerateKey()
+	assert.NoError(t, err)
+	imageDigest := digest.FromString("manifest").String()
+	payload, err := helper.BuildPayload("registry.local:5000/payments/api", imageDigest)
+	assert.NoError(t, err)
+	otherPayload, err := helper.BuildPayload("registry.local:5000/payments/api", digest.FromString("other").String())
+	assert.NoError(t, err)
+	signature, err := helper.Sign(signingKey, payload)
+	assert.NoError(t, err)
+	otherSignature, err := helper.Sign(signingKey, otherPayload)
+	assert.NoError(t, err)
+
+	trustedKeys := []*trustedKey{{id: 1, publicKey: &otherKey.PublicKey}, {id: 2, publicKey: &signingKey.PublicKey}}
+	keyId, verified := findTrustedSignature([]*helper.Signature{{Payload: payload, Signature: signature}}, trustedKeys, imageDigest)
+	assert.True(t, verified)
+	assert.Equal(t, 2, keyId)
+
+	_, verified = findTrustedSignature([]*helper.Signature{{Payload: payload, Signature: signature}}, trustedKeys[:1], imageDigest)
+	assert.False(t, verified, "signed by a key which is not trusted")
+	_, verified = findTrustedSignature([]*helper.Signature{{Payload: otherPayload, Signature: otherSignature}}, trustedKeys, imageDigest)
+	assert.False(t, verified, "signature of another image digest")
+	_, verified = findTrustedSignature(nil, trustedKeys, imageDigest)
+	assert.False(t, verified)
+}
+
+func TestGetKeySecretData(t *testing.T) {
+	data, publicKeyPem, err := getKeySecretData(&bean.SigningKeyRequest{Name: "generated"})
+	assert.NoError(t, err)
+	assert.Equal(t, publicKeyPem, data[bean.SigningKeySecretPubKey])
+	privateKey, err := helper.ParsePrivateKey(data[bean.SigningKeySecretKey], data[bean.SigningKeySecretPwdKey])
+	assert.NoError(t, err)
+	publicKey, err := helper.ParsePublicKey(publicKeyPem)
+	assert.NoError(t, err)
+	assert.True(t, publicKey.Equal(&privateKey.PublicKey))
+
+	privateKeyPem, err := helper.MarshalPrivateKey(privateKey)
+	assert.NoError(t, err)
+	data, uploadedPublicKeyPem, err := getKeySecretData(&bean.SigningKeyRequest{Name: "uploaded", PrivateKey: string(privateKeyPem)})
+	assert.NoError(t, err)
+	assert.Equal(t, publicKeyPem, uploadedPublicKeyPem)
+	assert.Equal(t, privateKeyPem, data[bean.SigningKeySecretKey])
+
+	_, _, err = getKeySecretData(&bean.SigningKeyRequest{Name: "invalid", PrivateKey: "invalid"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/constants"
+	"github.com/devtron-labs/devtron/internal/util"
+	"net/http"
+	"time"
+)
+
+const (
+	// SigningKeySecretPrefix is suffixed with the key id to get the name of the secret holding the private key
+	SigningKeySecretPrefix  = "devtron-image-signing-key-"
+	SigningKeySecretKey     = "cosign.key"
+	SigningKeySecretPwdKey  = "cosign.password"
+	SigningKeySecretPubKey  = "cosign.pub"
+	SigningKeySecretLabel   = "devtron.ai/image-signing-key"
+	SigningKeySecretLabelId = "devtron.ai/image-signing-key-id"
+)
+
+// CATEGORY=CD
+type ImageSigningConfig struct {
+	KeyNamespace         string   `env:"IMAGE_SIGNING_KEY_NAMESPACE" envDefault:"devtroncd" description:"Namespace of the secrets holding the private keys used for signing images"`
+	PlainHttpRegistries  []string `env:"IMAGE_SIGNING_PLAIN_HTTP_REGISTRIES" envDefault:"" envSeparator:"," description:"Comma separated registry hosts which are accessed over plain http while signing and verifying images, meant for local registries" example:"localhost:5000,registry.local:5000"`
+	RegistryTimeoutSecs  int      `env:"IMAGE_SIGNING_REGISTRY_TIMEOUT_SECS" envDefault:"30" description:"Timeout (in seconds) for registry calls made while signing or verifying an image"`
+	CiSigningTimeoutSecs int      `env:"IMAGE_SIGNING_CI_TIMEOUT_SECS" envDefault:"60" description:"Total time (in seconds) spent signing the images of a ci run before its auto triggers, the images not signed in time are recorded as failed and can be signed manually"`
+}
+
+func GetImageSigningConfig() (*ImageSigningConfig, error) {
+	config := &ImageSigningConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (config *ImageSigningConfig) IsPlainHttpRegistry(host string) bool {
+	for _, registry := range config.PlainHttpRegistries {
+		if registry == host {
+			return true
+		}
+	}
+	return false
+}
+
+type SignatureStatus string
+
+const (
+	SignatureStatusSigned SignatureStatus = "SIGNED"
+	SignatureStatusFailed SignatureStatus = "FAILED"
+)
+
+// SigningKeyRequest creates a signing key, an ECDSA P-256 key pair is generated if PrivateKey is not given.
+// PrivateKey can be a cosign encrypted private key (decrypted using Password) or a PKCS8/EC PEM encoded key.
+type SigningKeyRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	PrivateKey  string `json:"privateKey,omitempty"`
+	Password    string `json:"password,omitempty"`
+	UserId      int32  `json:"-"`
+}
+
+// SigningKeyDto never carries the private key
+type SigningKeyDto struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	PublicKey   string    `json:"publicKey"`
+	Active      bool      `json:"active"`
+	CreatedOn   time.Time `json:"createdOn"`
+}
+
+// AppSigningConfigDto opts an app in to signing of the images built by its ci pipelines
+type AppSigningConfigDto struct {
+	AppId        int   `json:"appId"`
+	SigningKeyId int   `json:"signingKeyId" validate:"required,number,gt=0"`
+	Enabled      bool  `json:"enabled"`
+	UserId       int32 `json:"-"`
+}
+
+// SignaturePolicyDto enforces deployment of signed images on an environment, images signed by any active key
+// are trusted if TrustedKeyIds is empty
+type SignaturePolicyDto struct {
+	EnvId         int   `json:"envId"`
+	Enabled       bool  `json:"enabled"`
+	TrustedKeyIds []int `json:"trustedKeyIds"`
+	UserId        int32 `json:"-"`
+}
+
+type ArtifactSignatureDto struct {
+	Id           int             `json:"id"`
+	CiArtifactId int             `json:"ciArtifactId"`
+	Image        string          `json:"image"`
+	ImageDigest  string          `json:"imageDigest"`
+	SigningKeyId int             `json:"signingKeyId"`
+	SignatureRef string          `json:"signatureRef,omitempty"`
+	Status       SignatureStatus `json:"status"`
+	Message      string          `json:"message,omitempty"`
+	SignedOn     time.Time       `json:"signedOn"`
+}
+
+// UnverifiedImageError is returned when the signature policy of the environment refuses the image
+type UnverifiedImageError struct {
+	Image       string `json:"image"`
+	ImageDigest string `json:"imageDigest"`
+	EnvId       int    `json:"envId"`
+	Reason      string `json:"reason"`
+}
+
+func NewUnverifiedImageError(image, imageDigest string, envId int, reason string) *UnverifiedImageError {
+	return &UnverifiedImageError{
+		Image:       image,
+		ImageDigest: imageDigest,
+		EnvId:       envId,
+		Reason:      reason,
+	}
+}
+
+func (e *UnverifiedImageError) Error() string {
+	return fmt.Sprintf("image signature verification failed for %s: %s", e.Image, e.Reason)
+}
+
+// ToApiError keeps the image and reason in user message so that it can be shown in the trigger response
+func (e *UnverifiedImageError) ToApiError() *util.ApiError {
+	return util.NewApiError(http.StatusUnprocessableEntity, e.Error(), e.Error()).
+		WithCode(constants.ImageSignatureVerificationFail).
+		WithUserMessage(e)
+}
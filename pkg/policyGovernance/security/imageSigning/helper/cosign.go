@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	cosignEncryptedPrivateKeyPemType   = "ENCRYPTED COSIGN PRIVATE KEY"
+	sigstoreEncryptedPrivateKeyPemType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	pkcs8PrivateKeyPemType             = "PRIVATE KEY"
+	ecPrivateKeyPemType                = "EC PRIVATE KEY"
+	publicKeyPemType                   = "PUBLIC KEY"
+
+	kdfScrypt            = "scrypt"
+	cipherNaclSecretbox  = "nacl/secretbox"
+	secretboxKeySize     = 32
+	secretboxNonceSize   = 24
+	cosignSignatureType  = "cosign container image signature"
+	signatureTagSuffix   = ".sig"
+	signatureAlgSeparate = "-"
+)
+
+// encryptedKey is the json body of a cosign encrypted private key pem block
+type encryptedKey struct {
+	Kdf struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// SimpleSigningPayload is the payload signed by cosign for an image, see
+// https://github.com/containers/image/blob/main/docs/containers-signature.5.md#json-data-format
+type SimpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// ParsePrivateKey parses a cosign encrypted private key using password, or a PKCS8/EC pem encoded private key.
+// Only ECDSA keys are supported as cosign generates them by default.
+func ParsePrivateKey(pemData []byte, password []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("private key is not pem encoded")
+	}
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case cosignEncryptedPrivateKeyPemType, sigstoreEncryptedPrivateKeyPemType:
+		var der []byte
+		der, err = decryptCosignKey(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err = x509.ParsePKCS8PrivateKey(der)
+	case pkcs8PrivateKeyPemType:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case ecPrivateKeyPemType:
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key pem type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	ecdsaKey, ok := privateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("only ECDSA private keys are supported")
+	}
+	return ecdsaKey, nil
+}
+
+func decryptCosignKey(data []byte, password []byte) ([]byte, error) {
+	key := &encryptedKey{}
+	err := json.Unmarshal(data, key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing encrypted private key: %w", err)
+	}
+	if key.Kdf.Name != kdfScrypt || key.Cipher.Name != cipherNaclSecretbox {
+		return nil, fmt.Errorf("unsupported private key encryption %s with %s", key.Cipher.Name, key.Kdf.Name)
+	}
+	if len(key.Cipher.Nonce) != secretboxNonceSize {
+		return nil, errors.New("invalid nonce in encrypted private key")
+	}
+	derivedKey, err := scrypt.Key(password, key.Kdf.Salt, key.Kdf.Params.N, key.Kdf.Params.R, key.Kdf.Params.P, secretboxKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving private key encryption key: %w", err)
+	}
+	var boxKey [secretboxKeySize]byte
+	var nonce [secretboxNonceSize]byte
+	copy(boxKey[:], derivedKey)
+	copy(nonce[:], key.Cipher.Nonce)
+	der, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &boxKey)
+	if !ok {
+		return nil, errors.New("decrypting private key failed, password is incorrect")
+	}
+	return der, nil
+}
+
+func MarshalPrivateKey(privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyPemType, Bytes: der}), nil
+}
+
+func MarshalPublicKey(publicKey *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyPemType, Bytes: der}), nil
+}
+
+func ParsePublicKey(pemData []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil || block.Type != publicKeyPemType {
+		return nil, errors.New("public key is not pem encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	ecdsaKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("only ECDSA public keys are supported")
+	}
+	return ecdsaKey, nil
+}
+
+// BuildPayload returns the cosign simple signing payload for the image repository and manifest digest
+func BuildPayload(repository, imageDigest string) ([]byte, error) {
+	payload := &SimpleSigningPayload{}
+	payload.Critical.Identity.DockerReference = repository
+	payload.Critical.Image.DockerManifestDigest = imageDigest
+	payload.Critical.Type = cosignSignatureType
+	return json.Marshal(payload)
+}
+
+// ValidatePayload checks that the payload is a cosign signature payload of the image digest
+func ValidatePayload(payload []byte, imageDigest string) error {
+	signingPayload := &SimpleSigningPayload{}
+	err := json.Unmarshal(payload, signingPayload)
+	if err != nil {
+		return fmt.Errorf("parsing signature payload: %w", err)
+	}
+	if signingPayload.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("unsupported signature type %q", signingPayload.Critical.Type)
+	}
+	if signingPayload.Critical.Image.DockerManifestDigest != imageDigest {
+		return fmt.Errorf("signature is for digest %s", signingPayload.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// Sign returns the base64 encoded ASN.1 ECDSA signature of the payload, as stored by cosign in signature annotations
+func Sign(privateKey *ecdsa.PrivateKey, payload []byte) (string, error) {
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, privateKey, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func VerifySignature(publicKey *ecdsa.PublicKey, payload []byte, signature string) bool {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256(payload)
+	return ecdsa.VerifyASN1(publicKey, hash[:], signatureBytes)
+}
+
+// GetSignatureTag returns the tag at which cosign keeps the signatures of the image digest, sha256:abc -> sha256-abc.sig
+func GetSignatureTag(imageDigest string) (string, error) {
+	parsedDigest, err := digest.Parse(imageDigest)
+	if err != nil {
+		return "", fmt.Errorf("invalid image digest %q: %w", imageDigest, err)
+	}
+	return parsedDigest.Algorithm().String() + signatureAlgSeparate + parsedDigest.Encoded() + signatureTagSuffix, nil
+}
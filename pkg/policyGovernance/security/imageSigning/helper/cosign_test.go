@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	privateKey, err := GenerateKey()
+	assert.NoError(t, err)
+	pkcs8Pem, err := MarshalPrivateKey(privateKey)
+	assert.NoError(t, err)
+	parsedKey, err := ParsePrivateKey(pkcs8Pem, nil)
+	assert.NoError(t, err)
+	assert.True(t, parsedKey.Equal(privateKey))
+
+	encryptedPem := encryptCosignKey(t, privateKey, "s3cret")
+	parsedKey, err = ParsePrivateKey(encryptedPem, []byte("s3cret"))
+	assert.NoError(t, err)
+	assert.True(t, parsedKey.Equal(privateKey))
+	_, err = ParsePrivateKey(encryptedPem, []byte("wrong"))
+	assert.EqualError(t, err, "decrypting private key failed, password is incorrect")
+
+	_, err = ParsePrivateKey([]byte("not a key"), nil)
+	assert.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	privateKey, err := GenerateKey()
+	assert.NoError(t, err)
+	otherKey, err := GenerateKey()
+	assert.NoError(t, err)
+	imageDigest := digest.FromString("manifest").String()
+	payload, err := BuildPayload("registry.local:5000/payments/api", imageDigest)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"critical":{"identity":{"docker-reference":"registry.local:5000/payments/api"},"image":{"docker-manifest-digest":"`+imageDigest+`"},"type":"cosign container image signature"},"optional":null}`, string(payload))
+	assert.NoError(t, ValidatePayload(payload, imageDigest))
+	assert.Error(t, ValidatePayload(payload, digest.FromString("other").String()))
+
+	signature, err := Sign(privateKey, payload)
+	assert.NoError(t, err)
+	publicKeyPem, err := MarshalPublicKey(&privateKey.PublicKey)
+	assert.NoError(t, err)
+	publicKey, err := ParsePublicKey(publicKeyPem)
+	assert.NoError(t, err)
+	assert.True(t, VerifySignature(publicKey, payload, signature))
+	assert.False(t, VerifySignature(&otherKey.PublicKey, payload, signature), "signature of other key")
+	assert.False(t, VerifySignature(publicKey, append(payload, ' '), signature), "tampered payload")
+	assert.False(t, VerifySignature(publicKey, payload, "not-base64"))
+}
+
+func TestGetSignatureTag(t *testing.T) {
+	tag, err := GetSignatureTag("sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256-6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b.sig", tag)
+	_, err = GetSignatureTag("v1.0")
+	assert.Error(t, err)
+}
+
+func TestParseImage(t *testing.T) {
+	reference, err := ParseImage("nginx:1.25")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io/library/nginx", GetDockerReference(reference))
+	assert.Equal(t, "1.25", reference.Reference)
+
+	reference, err = ParseImage("devtron/api:abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.io/devtron/api", GetDockerReference(reference))
+
+	reference, err = ParseImage("localhost:5000/payments/api@sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:5000/payments/api", GetDockerReference(reference))
+	assert.Equal(t, "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b", reference.Reference)
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	assert.Equal(t, "registry.local:5000", GetRegistryHost("https://registry.local:5000/v2/"))
+	assert.Equal(t, "123456789012.dkr.ecr.us-east-1.amazonaws.com", GetRegistryHost("123456789012.dkr.ecr.us-east-1.amazonaws.com"))
+	assert.Equal(t, "docker.io", GetRegistryHost("docker.io"))
+}
+
+func TestPushAndFetchSignatures(t *testing.T) {
+	server := httptest.NewServer(newTestRegistry())
+	defer server.Close()
+	reference, err := ParseImage(strings.TrimPrefix(server.URL, "http://") + "/payments/api:v1")
+	assert.NoError(t, err)
+	repository, err := NewRemoteRepository(reference, &RegistryCredential{PlainHttp: true}, 10*time.Second)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	imageDigest := digest.FromString("manifest").String()
+
+	signatures, err := FetchSignatures(ctx, repository, imageDigest)
+	assert.NoError(t, err)
+	assert.Empty(t, signatures, "image is not signed")
+
+	payload, err := BuildPayload(GetDockerReference(reference), imageDigest)
+	assert.NoError(t, err)
+	for _, signature := range []string{"c2lnbmF0dXJlLTE=", "c2lnbmF0dXJlLTI=", "c2lnbmF0dXJlLTI="} {
+		tag, err := PushSignature(ctx, repository, imageDigest, payload, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, "sha256-"+digest.Digest(imageDigest).Encoded()+".sig", tag)
+	}
+	signatures, err = FetchSignatures(ctx, repository, imageDigest)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 2, "signatures are appended to the manifest once")
+	assert.Equal(t, "c2lnbmF0dXJlLTE=", signatures[0].Signature)
+	assert.Equal(t, "c2lnbmF0dXJlLTI=", signatures[1].Signature)
+	assert.Equal(t, payload, signatures[1].Payload)
+}
+
+// encryptCosignKey encrypts the private key the way `cosign generate-key-pair` does
+func encryptCosignKey(t *testing.T, privateKey interface{}, password string) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	key := &encryptedKey{}
+	key.Kdf.Name, key.Cipher.Name = kdfScrypt, cipherNaclSecretbox
+	key.Kdf.Params.N, key.Kdf.Params.R, key.Kdf.Params.P = 1024, 8, 1
+	key.Kdf.Salt, key.Cipher.Nonce = make([]byte, 32), make([]byte, secretboxNonceSize)
+	_, _ = rand.Read(key.Kdf.Salt)
+	_, _ = rand.Read(key.Cipher.Nonce)
+	derivedKey, err := scrypt.Key([]byte(password), key.Kdf.Salt, key.Kdf.Params.N, key.Kdf.Params.R, key.Kdf.Params.P, secretboxKeySize)
+	assert.NoError(t, err)
+	var boxKey [secretboxKeySize]byte
+	var nonce [secretboxNonceSize]byte
+	copy(boxKey[:], derivedKey)
+	copy(nonce[:], key.Cipher.Nonce)
+	key.Ciphertext = secretbox.Seal(nil, der, &nonce, &boxKey)
+	data, err := json.Marshal(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: sigstoreEncryptedPrivateKeyPemType, Bytes: data})
+}
+
+// testRegistry is an in memory registry serving the distribution api calls made for signatures
+type testRegistry struct {
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+}
+
+func (registry *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	path := r.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", path+"upload")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasSuffix(path, "/blobs/uploads/upload") && r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		registry.blobs[r.URL.Query().Get("digest")] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		registry.serveContent(w, r, registry.blobs[path[strings.LastIndex(path, "/")+1:]], "application/octet-stream")
+	case strings.Contains(path, "/manifests/") && r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		registry.manifests[path[strings.LastIndex(path, "/")+1:]] = data
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/manifests/"):
+		registry.serveContent(w, r, registry.manifests[path[strings.LastIndex(path, "/")+1:]], "application/vnd.oci.image.manifest.v1+json")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (registry *testRegistry) serveContent(w http.ResponseWriter, r *http.Request, data []byte, mediaType string) {
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(data)
+}
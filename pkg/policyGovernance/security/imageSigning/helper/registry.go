@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"net/http"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"strings"
+	"time"
+)
+
+const (
+	cosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	dockerHubRegistry            = "docker.io"
+	dockerHubOfficialRepoPrefix  = "library/"
+)
+
+type RegistryCredential struct {
+	Username string
+	Password string
+	// Insecure skips tls verification of the registry
+	Insecure bool
+	// Cert is the ca certificate of the registry
+	Cert string
+	// PlainHttp accesses the registry over http
+	PlainHttp bool
+}
+
+// Signature is a cosign signature layer of an image
+type Signature struct {
+	Payload   []byte
+	Signature string
+}
+
+// ParseImage parses the image into a registry reference, images without a registry host are of docker hub
+func ParseImage(image string) (registry.Reference, error) {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		image = dockerHubRegistry + "/" + dockerHubOfficialRepoPrefix + image
+	} else if host := parts[0]; !strings.ContainsAny(host, ".:") && host != "localhost" {
+		image = dockerHubRegistry + "/" + image
+	}
+	return registry.ParseReference(image)
+}
+
+// NewRemoteRepository returns the repository of the image reference authenticated with the credential
+func NewRemoteRepository(reference registry.Reference, credential *RegistryCredential, timeout time.Duration) (*remote.Repository, error) {
+	repository, err := remote.NewRepository(reference.Registry + "/" + reference.Repository)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if credential.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	} else if len(credential.Cert) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(credential.Cert)) {
+			return nil, errors.New("invalid registry certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool}
+	}
+	repository.Client = &auth.Client{
+		Client: &http.Client{Transport: transport, Timeout: timeout},
+		Cache:  auth.NewCache(),
+		Credential: auth.StaticCredential(reference.Registry, auth.Credential{
+			Username: credential.Username,
+			Password: credential.Password,
+		}),
+	}
+	repository.PlainHTTP = credential.PlainHttp
+	return repository, nil
+}
+
+// ResolveDigest returns the manifest digest of the tag or digest reference
+func ResolveDigest(ctx context.Context, repository *remote.Repository, reference string) (string, error) {
+	descriptor, err := repository.Resolve(ctx, reference)
+	if err != nil {
+		return "", err
+	}
+	return descriptor.Digest.String(), nil
+}
+
+// PushSignature adds the signature to the cosign signature manifest of the image digest, the manifest is created
+// if absent. Returns the tag of the signature manifest.
+func PushSignature(ctx context.Context, repository *remote.Repository, imageDigest string, payload []byte, signature string) (string, error) {
+	tag, err := GetSignatureTag(imageDigest)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := fetchSignatureManifest(ctx, repository, tag)
+	if err != nil {
+		return "", err
+	}
+	if manifest == nil {
+		manifest = &ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+		}
+	}
+	payloadDescriptor := content.NewDescriptorFromBytes(cosignSimpleSigningMediaType, payload)
+	for _, layer := range manifest.Layers {
+		if layer.Digest == payloadDescriptor.Digest && layer.Annotations[cosignSignatureAnnotation] == signature {
+			return tag, nil
+		}
+	}
+	err = pushBlob(ctx, repository, payloadDescriptor, payload)
+	if err != nil {
+		return "", err
+	}
+	payloadDescriptor.Annotations = map[string]string{cosignSignatureAnnotation: signature}
+	manifest.Layers = append(manifest.Layers, payloadDescriptor)
+
+	imageConfig := ocispec.Image{RootFS: ocispec.RootFS{Type: "layers"}}
+	for _, layer := range manifest.Layers {
+		imageConfig.RootFS.DiffIDs = append(imageConfig.RootFS.DiffIDs, layer.Digest)
+	}
+	configBytes, err := json.Marshal(imageConfig)
+	if err != nil {
+		return "", err
+	}
+	manifest.Config = content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, configBytes)
+	err = pushBlob(ctx, repository, manifest.Config, configBytes)
+	if err != nil {
+		return "", err
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	manifestDescriptor := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestBytes)
+	err = repository.PushReference(ctx, manifestDescriptor, bytes.NewReader(manifestBytes), tag)
+	if err != nil {
+		return "", fmt.Errorf("pushing signature manifest: %w", err)
+	}
+	return tag, nil
+}
+
+// FetchSignatures returns the cosign signatures of the image digest, nil if the image is not signed
+func FetchSignatures(ctx context.Context, repository *remote.Repository, imageDigest string) ([]*Signature, error) {
+	tag, err := GetSignatureTag(imageDigest)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := fetchSignatureManifest(ctx, repository, tag)
+	if err != nil || manifest == nil {
+		return nil, err
+	}
+	signatures := make([]*Signature, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if layer.MediaType != cosignSimpleSigningMediaType || !ok {
+			continue
+		}
+		payload, err := fetchBlob(ctx, repository, layer)
+		if err != nil {
+			return nil, fmt.Errorf("fetching signature payload %s: %w", layer.Digest, err)
+		}
+		signatures = append(signatures, &Signature{Payload: payload, Signature: signature})
+	}
+	return signatures, nil
+}
+
+func fetchSignatureManifest(ctx context.Context, repository *remote.Repository, tag string) (*ocispec.Manifest, error) {
+	descriptor, reader, err := repository.FetchReference(ctx, tag)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("fetching signature manifest: %w", err)
+	}
+	defer reader.Close()
+	manifestBytes, err := content.ReadAll(reader, descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature manifest: %w", err)
+	}
+	manifest := &ocispec.Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signature manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func fetchBlob(ctx context.Context, repository *remote.Repository, descriptor ocispec.Descriptor) ([]byte, error) {
+	reader, err := repository.Blobs().Fetch(ctx, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return content.ReadAll(reader, descriptor)
+}
+
+func pushBlob(ctx context.Context, repository *remote.Repository, descriptor ocispec.Descriptor, data []byte) error {
+	exists, err := repository.Blobs().Exists(ctx, descriptor)
+	if err != nil {
+		return fmt.Errorf("checking blob %s: %w", descriptor.Digest, err)
+	}
+	if exists {
+		return nil
+	}
+	err = repository.Blobs().Push(ctx, descriptor, bytes.NewReader(data))
+	if err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return fmt.Errorf("pushing blob %s: %w", descriptor.Digest, err)
+	}
+	return nil
+}
+
+// GetRegistryHost returns the host of a container registry url, https://registry.local:5000/v2/ -> registry.local:5000
+func GetRegistryHost(registryURL string) string {
+	host := registryURL
+	if index := strings.Index(host, "://"); index != -1 {
+		host = host[index+3:]
+	}
+	if index := strings.Index(host, "/"); index != -1 {
+		host = host[:index]
+	}
+	return host
+}
+
+// GetDockerReference returns the repository of the image as signed in the payload identity
+func GetDockerReference(reference registry.Reference) string {
+	return reference.Registry + "/" + reference.Repository
+}
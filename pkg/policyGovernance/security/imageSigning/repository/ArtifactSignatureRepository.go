@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// CiArtifactSignature records the signing of a ci artifact image, the signature itself is pushed to the registry
+// as a cosign signature manifest referred by SignatureRef
+type CiArtifactSignature struct {
+	tableName    struct{}             `sql:"ci_artifact_signature" pg:",discard_unknown_columns"`
+	Id           int                  `sql:"id,pk"`
+	CiArtifactId int                  `sql:"ci_artifact_id,notnull"`
+	Image        string               `sql:"image,notnull"`
+	ImageDigest  string               `sql:"image_digest"`
+	SigningKeyId int                  `sql:"signing_key_id,notnull"`
+	Signature    string               `sql:"signature"`
+	SignatureRef string               `sql:"signature_ref"`
+	Status       bean.SignatureStatus `sql:"status,notnull"`
+	Message      string               `sql:"message"`
+	SignedOn     time.Time            `sql:"signed_on,notnull"`
+	sql.AuditLog
+}
+
+type ArtifactSignatureRepository interface {
+	Save(model *CiArtifactSignature) error
+	FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactSignature, error)
+}
+
+type ArtifactSignatureRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewArtifactSignatureRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ArtifactSignatureRepositoryImpl {
+	return &ArtifactSignatureRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ArtifactSignatureRepositoryImpl) Save(model *CiArtifactSignature) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ArtifactSignatureRepositoryImpl) FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactSignature, error) {
+	var models []*CiArtifactSignature
+	err := impl.dbConnection.Model(&models).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("id DESC").
+		Select()
+	return models, err
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ImageSigningKey holds the public part of a signing key, the private key is kept in the secret SecretName
+type ImageSigningKey struct {
+	tableName   struct{} `sql:"image_signing_key" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	PublicKey   string   `sql:"public_key,notnull"`
+	SecretName  string   `sql:"secret_name"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ImageSigningKeyRepository interface {
+	Save(model *ImageSigningKey) error
+	Update(model *ImageSigningKey) error
+	FindById(id int) (*ImageSigningKey, error)
+	FindActiveByName(name string) (*ImageSigningKey, error)
+	FindAllActive() ([]*ImageSigningKey, error)
+	FindActiveByIds(ids []int) ([]*ImageSigningKey, error)
+}
+
+type ImageSigningKeyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageSigningKeyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageSigningKeyRepositoryImpl {
+	return &ImageSigningKeyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) Save(model *ImageSigningKey) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) Update(model *ImageSigningKey) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindById(id int) (*ImageSigningKey, error) {
+	model := &ImageSigningKey{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindActiveByName(name string) (*ImageSigningKey, error) {
+	model := &ImageSigningKey{}
+	err := impl.dbConnection.Model(model).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindAllActive() ([]*ImageSigningKey, error) {
+	var models []*ImageSigningKey
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return models, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindActiveByIds(ids []int) ([]*ImageSigningKey, error) {
+	var models []*ImageSigningKey
+	if len(ids) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	return models, err
+}
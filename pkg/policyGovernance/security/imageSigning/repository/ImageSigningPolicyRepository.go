@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ImageSigningConfig opts an app in to signing of the images built by its ci pipelines
+type ImageSigningConfig struct {
+	tableName    struct{} `sql:"image_signing_config" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	AppId        int      `sql:"app_id,notnull"`
+	SigningKeyId int      `sql:"signing_key_id,notnull"`
+	Enabled      bool     `sql:"enabled,notnull"`
+	sql.AuditLog
+}
+
+// ImageSignaturePolicy refuses deployments of images on the environment which are not signed by a trusted key,
+// all active keys are trusted if TrustedKeyIds is empty
+type ImageSignaturePolicy struct {
+	tableName     struct{} `sql:"image_signature_policy" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	EnvId         int      `sql:"env_id,notnull"`
+	Enabled       bool     `sql:"enabled,notnull"`
+	TrustedKeyIds []int    `sql:"trusted_key_ids" pg:",array"`
+	sql.AuditLog
+}
+
+type ImageSigningPolicyRepository interface {
+	SaveSigningConfig(model *ImageSigningConfig) error
+	UpdateSigningConfig(model *ImageSigningConfig) error
+	FindSigningConfigByAppId(appId int) (*ImageSigningConfig, error)
+	FindEnabledSigningConfigsByKeyId(signingKeyId int) ([]*ImageSigningConfig, error)
+
+	SavePolicy(model *ImageSignaturePolicy) error
+	UpdatePolicy(model *ImageSignaturePolicy) error
+	FindPolicyByEnvId(envId int) (*ImageSignaturePolicy, error)
+}
+
+type ImageSigningPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageSigningPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageSigningPolicyRepositoryImpl {
+	return &ImageSigningPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) SaveSigningConfig(model *ImageSigningConfig) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) UpdateSigningConfig(model *ImageSigningConfig) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) FindSigningConfigByAppId(appId int) (*ImageSigningConfig, error) {
+	model := &ImageSigningConfig{}
+	err := impl.dbConnection.Model(model).
+		Where("app_id = ?", appId).
+		Select()
+	return model, err
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) FindEnabledSigningConfigsByKeyId(signingKeyId int) ([]*ImageSigningConfig, error) {
+	var models []*ImageSigningConfig
+	err := impl.dbConnection.Model(&models).
+		Where("signing_key_id = ?", signingKeyId).
+		Where("enabled = ?", true).
+		Select()
+	return models, err
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) SavePolicy(model *ImageSignaturePolicy) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) UpdatePolicy(model *ImageSignaturePolicy) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ImageSigningPolicyRepositoryImpl) FindPolicyByEnvId(envId int) (*ImageSignaturePolicy, error) {
+	model := &ImageSignaturePolicy{}
+	err := impl.dbConnection.Model(model).
+		Where("env_id = ?", envId).
+		Select()
+	return model, err
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"fmt"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/helper"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// SbomService stores the SPDX or CycloneDX sbom of ci artifacts and indexes their packages, so that a package
+// can be searched across the images built and deployed by devtron
+type SbomService interface {
+	// ValidateSbom returns a bad request error if the document is not a json SPDX or CycloneDX sbom
+	ValidateSbom(document []byte) error
+	// SaveSbom replaces the sbom of the artifact
+	SaveSbom(appId, ciArtifactId int, document []byte, source bean.SbomSource, userId int32) (*bean.SbomDto, error)
+	// UploadSbom replaces the sbom of an artifact of the app
+	UploadSbom(appId, ciArtifactId int, document []byte, userId int32) (*bean.SbomDto, error)
+	GetSbom(appId, ciArtifactId int) (*bean.SbomDto, error)
+	GetSbomDocument(appId, ciArtifactId int) (string, error)
+	GetAppIdsWithSbom() ([]int, error)
+	SearchPackages(request *bean.PackageSearchRequest) (*bean.PackageSearchResponse, error)
+}
+
+type SbomServiceImpl struct {
+	logger               *zap.SugaredLogger
+	sbomRepository       repository.SbomRepository
+	ciArtifactRepository repository2.CiArtifactRepository
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+}
+
+func NewSbomServiceImpl(logger *zap.SugaredLogger,
+	sbomRepository repository.SbomRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository) *SbomServiceImpl {
+	return &SbomServiceImpl{
+		logger:               logger,
+		sbomRepository:       sbomRepository,
+		ciArtifactRepository: ciArtifactRepository,
+		ciPipelineRepository: ciPipelineRepository,
+	}
+}
+
+func (impl *SbomServiceImpl) ValidateSbom(document []byte) error {
+	_, err := parseSbom(document)
+	return err
+}
+
+func (impl *SbomServiceImpl) SaveSbom(appId, ciArtifactId int, document []byte, source bean.SbomSource, userId int32) (*bean.SbomDto, error) {
+	parsed, err := parseSbom(document)
+	if err != nil {
+		impl.logger.Errorw("error in parsing sbom", "appId", appId, "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	tx, err := impl.sbomRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.sbomRepository.RollbackTx(tx)
+	err = impl.sbomRepository.DeactivateByCiArtifactId(tx, ciArtifactId, userId)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating existing sbom", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	model := &repository.CiArtifactSbom{
+		CiArtifactId: ciArtifactId,
+		AppId:        appId,
+		Format:       parsed.Format,
+		SpecVersion:  parsed.SpecVersion,
+		Source:       source,
+		Document:     string(document),
+		PackageCount: len(parsed.Packages),
+		Active:       true,
+		AuditLog:     sql.NewDefaultAuditLog(userId),
+	}
+	err = impl.sbomRepository.Save(tx, model)
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	err = impl.sbomRepository.SavePackages(tx, adaptSbomPackages(model, parsed.Packages))
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom packages", "ciArtifactId", ciArtifactId, "sbomId", model.Id, "err", err)
+		return nil, err
+	}
+	err = impl.sbomRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	impl.logger.Infow("sbom saved for artifact", "appId", appId, "ciArtifactId", ciArtifactId, "format", parsed.Format, "packages", len(parsed.Packages))
+	return adaptSbom(model, nil), nil
+}
+
+func (impl *SbomServiceImpl) UploadSbom(appId, ciArtifactId int, document []byte, userId int32) (*bean.SbomDto, error) {
+	err := impl.validateArtifactOfApp(appId, ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.SaveSbom(appId, ciArtifactId, document, bean.SbomSourceUpload, userId)
+}
+
+func (impl *SbomServiceImpl) GetSbom(appId, ciArtifactId int) (*bean.SbomDto, error) {
+	model, err := impl.getActiveSbom(appId, ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := impl.sbomRepository.FindPackagesBySbomId(model.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching sbom packages", "sbomId", model.Id, "err", err)
+		return nil, err
+	}
+	return adaptSbom(model, packages), nil
+}
+
+func (impl *SbomServiceImpl) GetSbomDocument(appId, ciArtifactId int) (string, error) {
+	if _, err := impl.getActiveSbom(appId, ciArtifactId); err != nil {
+		return "", err
+	}
+	document, err := impl.sbomRepository.FindActiveDocumentByCiArtifactId(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching sbom document", "ciArtifactId", ciArtifactId, "err", err)
+		return "", err
+	}
+	return document, nil
+}
+
+func (impl *SbomServiceImpl) GetAppIdsWithSbom() ([]int, error) {
+	appIds, err := impl.sbomRepository.FindAppIdsWithActiveSbom()
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps with sbom", "err", err)
+		return nil, err
+	}
+	return appIds, nil
+}
+
+func (impl *SbomServiceImpl) SearchPackages(request *bean.PackageSearchRequest) (*bean.PackageSearchResponse, error) {
+	if request.Size == 0 {
+		request.Size = bean.DefaultPackageSearchSize
+	}
+	rows, err := impl.sbomRepository.SearchPackages(request)
+	if err != nil {
+		return nil, err
+	}
+	response := &bean.PackageSearchResponse{Results: make([]*bean.PackageSearchResult, 0, len(rows))}
+	if len(rows) == 0 {
+		return response, nil
+	}
+	response.TotalCount = rows[0].TotalCount
+	ciArtifactIds := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ciArtifactIds = append(ciArtifactIds, row.CiArtifactId)
+	}
+	deployments, err := impl.sbomRepository.FindDeployedEnvironments(ciArtifactIds)
+	if err != nil {
+		return nil, err
+	}
+	deployedEnvs := make(map[int][]*bean.DeployedEnvironment)
+	for _, deployment := range deployments {
+		deployedEnvs[deployment.CiArtifactId] = append(deployedEnvs[deployment.CiArtifactId], &bean.DeployedEnvironment{
+			EnvId:   deployment.EnvId,
+			EnvName: deployment.EnvName,
+		})
+	}
+	for _, row := range rows {
+		envs := deployedEnvs[row.CiArtifactId]
+		if envs == nil {
+			envs = make([]*bean.DeployedEnvironment, 0)
+		}
+		response.Results = append(response.Results, &bean.PackageSearchResult{
+			Package: bean.Package{
+				Name:     row.Name,
+				Version:  row.Version,
+				Purl:     row.Purl,
+				Type:     row.PackageType,
+				Licenses: row.Licenses,
+			},
+			AppId:                row.AppId,
+			AppName:              row.AppName,
+			CiArtifactId:         row.CiArtifactId,
+			Image:                row.Image,
+			ImageDigest:          row.ImageDigest,
+			BuiltOn:              row.BuiltOn,
+			DeployedEnvironments: envs,
+		})
+	}
+	return response, nil
+}
+
+func (impl *SbomServiceImpl) getActiveSbom(appId, ciArtifactId int) (*repository.CiArtifactSbom, error) {
+	model, err := impl.sbomRepository.FindActiveByCiArtifactId(ciArtifactId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "sbom not found for artifact", "sbom not found for artifact")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching sbom", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	if model.AppId != appId {
+		return nil, util.NewApiError(http.StatusNotFound, "sbom not found for artifact", "sbom not found for artifact")
+	}
+	return model, nil
+}
+
+func (impl *SbomServiceImpl) validateArtifactOfApp(appId, ciArtifactId int) error {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if util.IsErrNoRows(err) {
+		return util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "ciArtifactId", ciArtifactId, "err", err)
+		return err
+	}
+	artifactAppId := 0
+	if artifact.ExternalCiPipelineId > 0 {
+		externalCiPipeline, err := impl.ciPipelineRepository.FindExternalCiById(artifact.ExternalCiPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching external ci pipeline of artifact", "externalCiPipelineId", artifact.ExternalCiPipelineId, "err", err)
+			return err
+		}
+		if externalCiPipeline != nil {
+			artifactAppId = externalCiPipeline.AppId
+		}
+	} else {
+		ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching ci pipeline of artifact", "ciPipelineId", artifact.PipelineId, "err", err)
+			return err
+		}
+		if ciPipeline != nil {
+			artifactAppId = ciPipeline.AppId
+		}
+	}
+	if artifactAppId != appId {
+		return util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	}
+	return nil
+}
+
+func parseSbom(document []byte) (*bean.ParsedSbom, error) {
+	parsed, err := helper.ParseSbom(document)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), fmt.Sprintf("invalid sbom: %s", err.Error()))
+	}
+	return parsed, nil
+}
+
+func adaptSbomPackages(sbom *repository.CiArtifactSbom, packages []*bean.Package) []*repository.SbomPackage {
+	models := make([]*repository.SbomPackage, 0, len(packages))
+	for _, pkg := range packages {
+		models = append(models, &repository.SbomPackage{
+			CiArtifactSbomId: sbom.Id,
+			CiArtifactId:     sbom.CiArtifactId,
+			Name:             pkg.Name,
+			Version:          pkg.Version,
+			Purl:             pkg.Purl,
+			PackageType:      pkg.Type,
+			Licenses:         pkg.Licenses,
+		})
+	}
+	return models
+}
+
+func adaptSbom(model *repository.CiArtifactSbom, packages []*repository.SbomPackage) *bean.SbomDto {
+	dto := &bean.SbomDto{
+		Id:           model.Id,
+		CiArtifactId: model.CiArtifactId,
+		AppId:        model.AppId,
+		Format:       model.Format,
+		SpecVersion:  model.SpecVersion,
+		Source:       model.Source,
+		PackageCount: model.PackageCount,
+		UploadedBy:   model.CreatedBy,
+		UploadedOn:   model.CreatedOn,
+	}
+	if packages != nil {
+		dto.Packages = make([]*bean.Package, 0, len(packages))
+		for _, pkg := range packages {
+			dto.Packages = append(dto.Packages, &bean.Package{
+				Name:     pkg.Name,
+				Version:  pkg.Version,
+				Purl:     pkg.Purl,
+				Type:     pkg.PackageType,
+				Licenses: pkg.Licenses,
+			})
+		}
+	}
+	return dto
+}
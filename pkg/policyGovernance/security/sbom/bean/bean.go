@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+type SbomFormat string
+
+const (
+	SbomFormatSpdx      SbomFormat = "SPDX"
+	SbomFormatCycloneDx SbomFormat = "CYCLONEDX"
+)
+
+type SbomSource string
+
+const (
+	SbomSourceCi         SbomSource = "CI"
+	SbomSourceExternalCi SbomSource = "EXTERNAL_CI"
+	SbomSourceUpload     SbomSource = "UPLOAD"
+)
+
+const DefaultPackageSearchSize = 20
+
+// Package is a single component listed in a sbom document
+type Package struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Purl     string `json:"purl,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Licenses string `json:"licenses,omitempty"`
+}
+
+// ParsedSbom is the format agnostic view of a SPDX or CycloneDX json document
+type ParsedSbom struct {
+	Format      SbomFormat
+	SpecVersion string
+	Packages    []*Package
+}
+
+type SbomDto struct {
+	Id           int        `json:"id"`
+	CiArtifactId int        `json:"ciArtifactId"`
+	AppId        int        `json:"appId"`
+	Format       SbomFormat `json:"format"`
+	SpecVersion  string     `json:"specVersion"`
+	Source       SbomSource `json:"source"`
+	PackageCount int        `json:"packageCount"`
+	Packages     []*Package `json:"packages,omitempty"`
+	UploadedBy   int32      `json:"uploadedBy"`
+	UploadedOn   time.Time  `json:"uploadedOn"`
+}
+
+type PackageSearchRequest struct {
+	Name         string `json:"name" validate:"required,min=2"`
+	Version      string `json:"version"`
+	DeployedOnly bool   `json:"deployedOnly"`
+	AppIds       []int  `json:"appIds"`
+	EnvIds       []int  `json:"envIds"` // artifacts deployed on these environments only, implies DeployedOnly
+	Offset       int    `json:"offset" validate:"min=0"`
+	Size         int    `json:"size" validate:"min=0,max=500"`
+}
+
+type DeployedEnvironment struct {
+	EnvId   int    `json:"envId"`
+	EnvName string `json:"envName"`
+}
+
+type PackageSearchResult struct {
+	Package
+	AppId                int                    `json:"appId"`
+	AppName              string                 `json:"appName"`
+	CiArtifactId         int                    `json:"ciArtifactId"`
+	Image                string                 `json:"image"`
+	ImageDigest          string                 `json:"imageDigest"`
+	BuiltOn              time.Time              `json:"builtOn"`
+	DeployedEnvironments []*DeployedEnvironment `json:"deployedEnvironments"`
+}
+
+type PackageSearchResponse struct {
+	TotalCount int                    `json:"totalCount"`
+	Results    []*PackageSearchResult `json:"results"`
+}
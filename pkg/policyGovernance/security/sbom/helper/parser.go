@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"strings"
+)
+
+var ErrUnsupportedSbomFormat = errors.New("unsupported sbom document, only SPDX and CycloneDX json documents are supported")
+
+const (
+	cycloneDxBomFormat = "CycloneDX"
+	purlRefType        = "purl"
+	spdxNoAssertion    = "NOASSERTION"
+	spdxNone           = "NONE"
+)
+
+type sbomProbe struct {
+	BomFormat   string `json:"bomFormat"`
+	SpecVersion string `json:"specVersion"`
+	SpdxVersion string `json:"spdxVersion"`
+}
+
+type spdxDocument struct {
+	SpdxVersion       string         `json:"spdxVersion"`
+	DocumentDescribes []string       `json:"documentDescribes"`
+	Packages          []*spdxPackage `json:"packages"`
+}
+
+type spdxPackage struct {
+	SpdxId           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo"`
+	LicenseConcluded string             `json:"licenseConcluded"`
+	LicenseDeclared  string             `json:"licenseDeclared"`
+	ExternalRefs     []*spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceType    string `json:"referenceType"`
+	ReferenceLocator string `json:"referenceLocator"`
+}
+
+type cycloneDxDocument struct {
+	SpecVersion string                `json:"specVersion"`
+	Components  []*cycloneDxComponent `json:"components"`
+}
+
+type cycloneDxComponent struct {
+	Name       string                `json:"name"`
+	Version    string                `json:"version"`
+	Purl       string                `json:"purl"`
+	Licenses   []*cycloneDxLicense   `json:"licenses"`
+	Components []*cycloneDxComponent `json:"components"`
+}
+
+type cycloneDxLicense struct {
+	License *struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"license"`
+	Expression string `json:"expression"`
+}
+
+// ParseSbom detects the format of a json sbom document and returns its packages, duplicate packages are dropped
+func ParseSbom(document []byte) (*bean.ParsedSbom, error) {
+	if len(document) == 0 {
+		return nil, errors.New("sbom document is empty")
+	}
+	probe := &sbomProbe{}
+	if err := json.Unmarshal(document, probe); err != nil {
+		return nil, fmt.Errorf("invalid sbom document: %w", err)
+	}
+	var parsed *bean.ParsedSbom
+	var err error
+	switch {
+	case probe.BomFormat == cycloneDxBomFormat:
+		parsed, err = parseCycloneDx(document)
+	case len(probe.SpdxVersion) > 0:
+		parsed, err = parseSpdx(document)
+	default:
+		return nil, ErrUnsupportedSbomFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed.Packages = dedupePackages(parsed.Packages)
+	return parsed, nil
+}
+
+func parseSpdx(document []byte) (*bean.ParsedSbom, error) {
+	doc := &spdxDocument{}
+	if err := json.Unmarshal(document, doc); err != nil {
+		return nil, fmt.Errorf("invalid SPDX document: %w", err)
+	}
+	// the described element is the image itself and not one of its packages
+	described := make(map[string]bool, len(doc.DocumentDescribes))
+	for _, id := range doc.DocumentDescribes {
+		described[id] = true
+	}
+	packages := make([]*bean.Package, 0, len(doc.Packages))
+	for _, pkg := range doc.Packages {
+		if pkg == nil || len(pkg.Name) == 0 || described[pkg.SpdxId] {
+			continue
+		}
+		var purl string
+		for _, ref := range pkg.ExternalRefs {
+			if ref != nil && ref.ReferenceType == purlRefType {
+				purl = ref.ReferenceLocator
+				break
+			}
+		}
+		packages = append(packages, &bean.Package{
+			Name:     pkg.Name,
+			Version:  spdxValue(pkg.VersionInfo),
+			Purl:     purl,
+			Type:     GetPurlType(purl),
+			Licenses: getSpdxLicense(pkg),
+		})
+	}
+	return &bean.ParsedSbom{
+		Format:      bean.SbomFormatSpdx,
+		SpecVersion: strings.TrimPrefix(doc.SpdxVersion, "SPDX-"),
+		Packages:    packages,
+	}, nil
+}
+
+func getSpdxLicense(pkg *spdxPackage) string {
+	if license := spdxValue(pkg.LicenseConcluded); len(license) > 0 {
+		return license
+	}
+	return spdxValue(pkg.LicenseDeclared)
+}
+
+func spdxValue(value string) string {
+	if value == spdxNoAssertion || value == spdxNone {
+		return ""
+	}
+	return value
+}
+
+func parseCycloneDx(document []byte) (*bean.ParsedSbom, error) {
+	doc := &cycloneDxDocument{}
+	if err := json.Unmarshal(document, doc); err != nil {
+		return nil, fmt.Errorf("invalid CycloneDX document: %w", err)
+	}
+	packages := make([]*bean.Package, 0, len(doc.Components))
+	packages = appendCycloneDxComponents(packages, doc.Components)
+	return &bean.ParsedSbom{
+		Format:      bean.SbomFormatCycloneDx,
+		SpecVersion: doc.SpecVersion,
+		Packages:    packages,
+	}, nil
+}
+
+// appendCycloneDxComponents flattens the component tree, shaded or bundled dependencies are nested components
+func appendCycloneDxComponents(packages []*bean.Package, components []*cycloneDxComponent) []*bean.Package {
+	for _, component := range components {
+		if component == nil {
+			continue
+		}
+		if len(component.Name) > 0 {
+			packages = append(packages, &bean.Package{
+				Name:     component.Name,
+				Version:  component.Version,
+				Purl:     component.Purl,
+				Type:     GetPurlType(component.Purl),
+				Licenses: getCycloneDxLicenses(component.Licenses),
+			})
+		}
+		packages = appendCycloneDxComponents(packages, component.Components)
+	}
+	return packages
+}
+
+func getCycloneDxLicenses(licenses []*cycloneDxLicense) string {
+	names := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		if license == nil {
+			continue
+		}
+		if len(license.Expression) > 0 {
+			names = append(names, license.Expression)
+		} else if license.License != nil && len(license.License.Id) > 0 {
+			names = append(names, license.License.Id)
+		} else if license.License != nil && len(license.License.Name) > 0 {
+			names = append(names, license.License.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetPurlType returns the package ecosystem of a package url, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1" is of type maven
+func GetPurlType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+	purlType, _, found := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(purlType)
+}
+
+func dedupePackages(packages []*bean.Package) []*bean.Package {
+	seen := make(map[string]bool, len(packages))
+	unique := make([]*bean.Package, 0, len(packages))
+	for _, pkg := range packages {
+		key := fmt.Sprintf("%s|%s|%s", pkg.Name, pkg.Version, pkg.Purl)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, pkg)
+	}
+	return unique
+}
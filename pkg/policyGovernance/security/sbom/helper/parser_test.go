@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const spdxSbom = `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "documentDescribes": ["SPDXRef-image"],
+  "packages": [
+    {"SPDXID": "SPDXRef-image", "name": "quay.io/devtron/app", "versionInfo": "sha256:abc"},
+    {
+      "SPDXID": "SPDXRef-log4j",
+      "name": "log4j-core",
+      "versionInfo": "2.14.1",
+      "licenseConcluded": "NOASSERTION",
+      "licenseDeclared": "Apache-2.0",
+      "externalRefs": [
+        {"referenceCategory": "SECURITY", "referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:apache:log4j:2.14.1"},
+        {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}
+      ]
+    },
+    {"SPDXID": "SPDXRef-log4j-dup", "name": "log4j-core", "versionInfo": "2.14.1", "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]},
+    {"SPDXID": "SPDXRef-musl", "name": "musl", "versionInfo": "1.2.4-r2", "licenseConcluded": "MIT"},
+    {"SPDXID": "SPDXRef-empty", "name": ""}
+  ]
+}`
+
+const cycloneDxSbom = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "container", "name": "quay.io/devtron/app"}},
+  "components": [
+    {
+      "type": "library",
+      "group": "org.springframework.boot",
+      "name": "spring-boot-loader",
+      "version": "3.1.0",
+      "purl": "pkg:maven/org.springframework.boot/spring-boot-loader@3.1.0",
+      "licenses": [{"license": {"id": "Apache-2.0"}}],
+      "components": [
+        {"type": "library", "name": "log4j-api", "version": "2.20.0", "purl": "pkg:maven/org.apache.logging.log4j/log4j-api@2.20.0", "licenses": [{"expression": "Apache-2.0 OR MIT"}]}
+      ]
+    },
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"name": "custom"}}]}
+  ]
+}`
+
+func TestParseSbomSpdx(t *testing.T) {
+	parsed, err := ParseSbom([]byte(spdxSbom))
+	assert.NoError(t, err)
+	assert.Equal(t, bean.SbomFormatSpdx, parsed.Format)
+	assert.Equal(t, "2.3", parsed.SpecVersion)
+	assert.Equal(t, []*bean.Package{
+		{Name: "log4j-core", Version: "2.14.1", Purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Type: "maven", Licenses: "Apache-2.0"},
+		{Name: "musl", Version: "1.2.4-r2", Licenses: "MIT"},
+	}, parsed.Packages)
+}
+
+func TestParseSbomCycloneDx(t *testing.T) {
+	parsed, err := ParseSbom([]byte(cycloneDxSbom))
+	assert.NoError(t, err)
+	assert.Equal(t, bean.SbomFormatCycloneDx, parsed.Format)
+	assert.Equal(t, "1.5", parsed.SpecVersion)
+	assert.Equal(t, []*bean.Package{
+		{Name: "spring-boot-loader", Version: "3.1.0", Purl: "pkg:maven/org.springframework.boot/spring-boot-loader@3.1.0", Type: "maven", Licenses: "Apache-2.0"},
+		{Name: "log4j-api", Version: "2.20.0", Purl: "pkg:maven/org.apache.logging.log4j/log4j-api@2.20.0", Type: "maven", Licenses: "Apache-2.0 OR MIT"},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", Type: "npm", Licenses: "custom"},
+	}, parsed.Packages)
+}
+
+func TestParseSbomInvalid(t *testing.T) {
+	_, err := ParseSbom(nil)
+	assert.EqualError(t, err, "sbom document is empty")
+	_, err = ParseSbom([]byte("not json"))
+	assert.Error(t, err)
+	_, err = ParseSbom([]byte(`{"bomFormat": "unknown"}`))
+	assert.ErrorIs(t, err, ErrUnsupportedSbomFormat)
+}
+
+func TestGetPurlType(t *testing.T) {
+	assert.Equal(t, "maven", GetPurlType("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"))
+	assert.Equal(t, "npm", GetPurlType("pkg:NPM/%40angular/core@16.0.0"))
+	assert.Equal(t, "", GetPurlType("maven/log4j"))
+	assert.Equal(t, "", GetPurlType(""))
+}
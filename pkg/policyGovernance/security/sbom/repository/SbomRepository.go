@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// CiArtifactSbom keeps the raw sbom document of a ci artifact, only one sbom is active for an artifact
+type CiArtifactSbom struct {
+	tableName    struct{}        `sql:"ci_artifact_sbom" pg:",discard_unknown_columns"`
+	Id           int             `sql:"id,pk"`
+	CiArtifactId int             `sql:"ci_artifact_id,notnull"`
+	AppId        int             `sql:"app_id,notnull"`
+	Format       bean.SbomFormat `sql:"format,notnull"`
+	SpecVersion  string          `sql:"spec_version"`
+	Source       bean.SbomSource `sql:"source,notnull"`
+	Document     string          `sql:"document,notnull"`
+	PackageCount int             `sql:"package_count,notnull"`
+	Active       bool            `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// SbomPackage is the searchable index of the packages of an active sbom
+type SbomPackage struct {
+	tableName        struct{} `sql:"sbom_package" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	CiArtifactSbomId int      `sql:"ci_artifact_sbom_id,notnull"`
+	CiArtifactId     int      `sql:"ci_artifact_id,notnull"`
+	Name             string   `sql:"name,notnull"`
+	Version          string   `sql:"version"`
+	Purl             string   `sql:"purl"`
+	PackageType      string   `sql:"package_type"`
+	Licenses         string   `sql:"licenses"`
+}
+
+type PackageSearchRow struct {
+	Name         string    `sql:"name"`
+	Version      string    `sql:"version"`
+	Purl         string    `sql:"purl"`
+	PackageType  string    `sql:"package_type"`
+	Licenses     string    `sql:"licenses"`
+	CiArtifactId int       `sql:"ci_artifact_id"`
+	AppId        int       `sql:"app_id"`
+	AppName      string    `sql:"app_name"`
+	Image        string    `sql:"image"`
+	ImageDigest  string    `sql:"image_digest"`
+	BuiltOn      time.Time `sql:"built_on"`
+	TotalCount   int       `sql:"total_count"`
+}
+
+type ArtifactDeploymentRow struct {
+	CiArtifactId int    `sql:"ci_artifact_id"`
+	EnvId        int    `sql:"env_id"`
+	EnvName      string `sql:"env_name"`
+}
+
+type SbomRepository interface {
+	sql.TransactionWrapper
+	DeactivateByCiArtifactId(tx *pg.Tx, ciArtifactId int, userId int32) error
+	Save(tx *pg.Tx, model *CiArtifactSbom) error
+	SavePackages(tx *pg.Tx, packages []*SbomPackage) error
+	FindActiveByCiArtifactId(ciArtifactId int) (*CiArtifactSbom, error)
+	FindActiveDocumentByCiArtifactId(ciArtifactId int) (string, error)
+	FindPackagesBySbomId(sbomId int) ([]*SbomPackage, error)
+	FindAppIdsWithActiveSbom() ([]int, error)
+	SearchPackages(request *bean.PackageSearchRequest) ([]*PackageSearchRow, error)
+	FindDeployedEnvironments(ciArtifactIds []int) ([]*ArtifactDeploymentRow, error)
+}
+
+type SbomRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewSbomRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *SbomRepositoryImpl {
+	return &SbomRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *SbomRepositoryImpl) DeactivateByCiArtifactId(tx *pg.Tx, ciArtifactId int, userId int32) error {
+	// packages of an inactive sbom are never searched, they are dropped to keep the index small
+	_, err := tx.Model((*SbomPackage)(nil)).
+		Where("ci_artifact_sbom_id IN (SELECT id FROM ci_artifact_sbom WHERE ci_artifact_id = ? AND active = true)", ciArtifactId).
+		Delete()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Model((*CiArtifactSbom)(nil)).
+		Set("active = false").
+		Set("updated_by = ?", userId).
+		Set("updated_on = ?", time.Now()).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("active = true").
+		Update()
+	return err
+}
+
+func (impl *SbomRepositoryImpl) Save(tx *pg.Tx, model *CiArtifactSbom) error {
+	return tx.Insert(model)
+}
+
+func (impl *SbomRepositoryImpl) SavePackages(tx *pg.Tx, packages []*SbomPackage) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&packages).Insert()
+	return err
+}
+
+func (impl *SbomRepositoryImpl) FindActiveByCiArtifactId(ciArtifactId int) (*CiArtifactSbom, error) {
+	model := &CiArtifactSbom{}
+	err := impl.dbConnection.Model(model).
+		ExcludeColumn("document").
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("active = true").
+		Select()
+	return model, err
+}
+
+func (impl *SbomRepositoryImpl) FindActiveDocumentByCiArtifactId(ciArtifactId int) (string, error) {
+	var document string
+	err := impl.dbConnection.Model((*CiArtifactSbom)(nil)).
+		Column("document").
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("active = true").
+		Select(&document)
+	return document, err
+}
+
+func (impl *SbomRepositoryImpl) FindPackagesBySbomId(sbomId int) ([]*SbomPackage, error) {
+	var models []*SbomPackage
+	err := impl.dbConnection.Model(&models).
+		Where("ci_artifact_sbom_id = ?", sbomId).
+		Order("name", "version").
+		Select()
+	return models, err
+}
+
+func (impl *SbomRepositoryImpl) FindAppIdsWithActiveSbom() ([]int, error) {
+	var appIds []int
+	err := impl.dbConnection.Model((*CiArtifactSbom)(nil)).
+		ColumnExpr("DISTINCT app_id").
+		Where("active = true").
+		Select(&appIds)
+	return appIds, err
+}
+
+func (impl *SbomRepositoryImpl) SearchPackages(request *bean.PackageSearchRequest) ([]*PackageSearchRow, error) {
+	var models []*PackageSearchRow
+	query, queryParams := buildPackageSearchQuery(request)
+	_, err := impl.dbConnection.Query(&models, query, queryParams...)
+	if err != nil {
+		impl.logger.Errorw("error in searching sbom packages", "request", request, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+// deployedArtifactCondition matches an artifact which is the latest deployed image of its app on any environment,
+// image_scan_deploy_info keeps the scan history of the latest deployed image per app and environment
+const deployedArtifactCondition = ` EXISTS (SELECT 1 FROM image_scan_deploy_info isdi 
+	INNER JOIN image_scan_execution_history iseh ON iseh.id = ANY (isdi.image_scan_execution_history_id) 
+	INNER JOIN environment env ON env.id = isdi.env_id AND env.active = true 
+	WHERE isdi.object_type = 'app' AND isdi.scan_object_meta_id = s.app_id AND iseh.image = cia.image`
+
+func buildPackageSearchQuery(request *bean.PackageSearchRequest) (string, []interface{}) {
+	var queryParams []interface{}
+	query := `SELECT sp.name, sp.version, sp.purl, sp.package_type, sp.licenses, s.ci_artifact_id, s.app_id, a.app_name, 
+				cia.image, cia.image_digest, cia.created_on AS built_on, COUNT(*) OVER() AS total_count 
+				FROM sbom_package sp 
+				INNER JOIN ci_artifact_sbom s ON s.id = sp.ci_artifact_sbom_id AND s.active = true 
+				INNER JOIN ci_artifact cia ON cia.id = s.ci_artifact_id 
+				INNER JOIN app a ON a.id = s.app_id AND a.active = true 
+				WHERE (LOWER(sp.name) LIKE ? OR LOWER(sp.purl) LIKE ?)`
+	namePattern := util.GetLIKEClauseQueryParam(escapeLikePattern(strings.ToLower(request.Name)))
+	queryParams = append(queryParams, namePattern, namePattern)
+	if len(request.Version) > 0 {
+		// a version matches itself and its patch versions, 2.14 matches 2.14.1
+		query += " AND (sp.version = ? OR sp.version LIKE ?)"
+		queryParams = append(queryParams, request.Version, escapeLikePattern(request.Version)+".%")
+	}
+	if len(request.AppIds) > 0 {
+		query += " AND s.app_id IN (?)"
+		queryParams = append(queryParams, pg.In(request.AppIds))
+	}
+	if len(request.EnvIds) > 0 {
+		query += " AND" + deployedArtifactCondition + " AND isdi.env_id IN (?)) "
+		queryParams = append(queryParams, pg.In(request.EnvIds))
+	} else if request.DeployedOnly {
+		query += " AND" + deployedArtifactCondition + ") "
+	}
+	query += " ORDER BY a.app_name, cia.id DESC, sp.name, sp.version LIMIT ? OFFSET ?;"
+	queryParams = append(queryParams, request.Size, request.Offset)
+	return query, queryParams
+}
+
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (impl *SbomRepositoryImpl) FindDeployedEnvironments(ciArtifactIds []int) ([]*ArtifactDeploymentRow, error) {
+	var models []*ArtifactDeploymentRow
+	if len(ciArtifactIds) == 0 {
+		return models, nil
+	}
+	query := `SELECT DISTINCT cia.id AS ci_artifact_id, env.id AS env_id, env.environment_name AS env_name 
+				FROM ci_artifact_sbom s 
+				INNER JOIN ci_artifact cia ON cia.id = s.ci_artifact_id 
+				INNER JOIN image_scan_execution_history iseh ON iseh.image = cia.image 
+				INNER JOIN image_scan_deploy_info isdi ON iseh.id = ANY (isdi.image_scan_execution_history_id) 
+					AND isdi.object_type = 'app' AND isdi.scan_object_meta_id = s.app_id 
+				INNER JOIN environment env ON env.id = isdi.env_id AND env.active = true 
+				WHERE s.active = true AND s.ci_artifact_id IN (?) 
+				ORDER BY env.environment_name;`
+	_, err := impl.dbConnection.Query(&models, query, pg.In(ciArtifactIds))
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed environments of artifacts", "ciArtifactIds", ciArtifactIds, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
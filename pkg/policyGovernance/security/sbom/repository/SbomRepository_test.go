@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestBuildPackageSearchQuery(t *testing.T) {
+	query, params := buildPackageSearchQuery(&bean.PackageSearchRequest{Name: "Log4J_core", Size: 20, Offset: 40})
+	assert.NotContains(t, query, "sp.version = ?")
+	assert.NotContains(t, query, "image_scan_deploy_info")
+	assert.Equal(t, []interface{}{`%log4j\_core%`, `%log4j\_core%`, 20, 40}, params)
+
+	query, params = buildPackageSearchQuery(&bean.PackageSearchRequest{Name: "log4j", Version: "2.14", AppIds: []int{1, 2}, DeployedOnly: true, Size: 10})
+	assert.True(t, strings.Contains(query, "(sp.version = ? OR sp.version LIKE ?)"))
+	assert.True(t, strings.Contains(query, "s.app_id IN (?)"))
+	assert.True(t, strings.Contains(query, "image_scan_deploy_info"))
+	assert.Equal(t, "2.14", params[2])
+	assert.Equal(t, "2.14.%", params[3])
+	assert.Len(t, params, 7)
+
+	query, params = buildPackageSearchQuery(&bean.PackageSearchRequest{Name: "log4j", EnvIds: []int{3}, Size: 10})
+	assert.True(t, strings.Contains(query, "image_scan_deploy_info"))
+	assert.True(t, strings.Contains(query, "isdi.env_id IN (?)"))
+	assert.Len(t, params, 5)
+}
@@ -1,7 +1,11 @@
 package policyGovernance
 
 import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentAdmission"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/deploymentWindow"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool"
 	"github.com/google/wire"
 )
@@ -9,4 +13,8 @@ import (
 var PolicyGovernanceWireSet = wire.NewSet(
 	imageScanning.ImageScanningWireSet,
 	scanTool.ScanToolWireSet,
+	deploymentAdmission.DeploymentAdmissionWireSet,
+	deploymentWindow.DeploymentWindowWireSet,
+	imageSigning.ImageSigningWireSet,
+	sbom.SbomWireSet,
 )
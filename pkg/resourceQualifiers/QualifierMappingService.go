@@ -33,6 +33,9 @@ import (
 type QualifierMappingService interface {
 	CreateQualifierMappings(qualifierMappings []*QualifierMapping, tx *pg.Tx) ([]*QualifierMapping, error)
 	GetQualifierMappings(resourceType ResourceType, scope *Scope, resourceIds []int) ([]*QualifierMapping, error)
+	// GetQualifierMappingsByQualifierValues returns the mappings of the resource type whose qualifier identifies any of
+	// the values of that qualifier, along with the global mappings
+	GetQualifierMappingsByQualifierValues(resourceType ResourceType, qualifierValues map[Qualifier][]int) ([]*QualifierMapping, error)
 	DeleteAllQualifierMappings(resourceType ResourceType, auditLog sql.AuditLog, tx *pg.Tx) error
 	DeleteByIdentifierKeyAndValue(resourceType ResourceType, identifierKey int, identifierValue int, qualifierId int, auditLog sql.AuditLog, tx *pg.Tx) error
 	DeleteAllByIds(qualifierMappingIds []int, userId int32, tx *pg.Tx) error
@@ -67,6 +70,15 @@ func (impl *QualifierMappingServiceImpl) GetQualifierMappings(resourceType Resou
 	return impl.qualifierMappingRepository.GetQualifierMappings(resourceType, scope, searchableKeyNameIdMap, resourceIds)
 }
 
+func (impl *QualifierMappingServiceImpl) GetQualifierMappingsByQualifierValues(resourceType ResourceType, qualifierValues map[Qualifier][]int) ([]*QualifierMapping, error) {
+	searchableKeyNameIdMap := impl.devtronResourceSearchableKeyService.GetAllSearchableKeyNameIdMap()
+	valuesMap := make(map[Qualifier][][]int, len(qualifierValues))
+	for qualifier, values := range qualifierValues {
+		valuesMap[qualifier] = [][]int{values}
+	}
+	return impl.qualifierMappingRepository.GetQualifierMappingsForListOfQualifierValues(resourceType, valuesMap, searchableKeyNameIdMap, nil)
+}
+
 func (impl *QualifierMappingServiceImpl) DeleteAllQualifierMappings(resourceType ResourceType, auditLog sql.AuditLog, tx *pg.Tx) error {
 	return impl.qualifierMappingRepository.DeleteAllQualifierMappings(resourceType, auditLog, tx)
 }
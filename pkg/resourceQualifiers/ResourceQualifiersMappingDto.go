@@ -28,6 +28,7 @@ const (
 	InfraProfile                       = 3
 	ImagePromotionPolicy  ResourceType = 4
 	DeploymentWindow      ResourceType = 5
+	DeploymentAdmission   ResourceType = 6
 )
 
 type ResourceQualifierMappings struct {
@@ -65,13 +66,17 @@ type SelectionIdentifier struct {
 	AppId                   int                      `json:"appId"`
 	EnvId                   int                      `json:"envId"`
 	ClusterId               int                      `json:"clusterId"`
+	ProjectId               int                      `json:"projectId"`
+	EnvironmentType         int                      `json:"environmentType"` // AllExistingAndFutureProdEnvsInt or AllExistingAndFutureNonProdEnvsInt
 	SelectionIdentifierName *SelectionIdentifierName `json:"-"`
 }
 
 type SelectionIdentifierName struct {
-	AppName         string
-	EnvironmentName string
-	ClusterName     string
+	AppName             string
+	EnvironmentName     string
+	ClusterName         string
+	ProjectName         string
+	EnvironmentTypeName string
 }
 
 func (mapping *QualifierMapping) GetIdValueAndName() (int, string) {
@@ -63,11 +63,21 @@ func (repo *QualifiersMappingRepositoryImpl) CreateQualifierMappings(qualifierMa
 }
 
 func (repo *QualifiersMappingRepositoryImpl) addScopeWhereClause(query *orm.Query, scope *Scope, searchableKeyNameIdMap map[bean.DevtronResourceSearchableKeyName]int) *orm.Query {
-	return query.Where(
-		"( (identifier_key = ? AND identifier_value_int = ?)  AND qualifier_id = ?) "+
-			"OR (qualifier_id = ? ) ",
-		searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PIPELINE_ID], scope.PipelineId, PIPELINE_QUALIFIER,
-		GLOBAL_QUALIFIER)
+	return query.WhereGroup(func(query *orm.Query) (*orm.Query, error) {
+		query = query.WhereOr("( (identifier_key = ? AND identifier_value_int = ?)  AND qualifier_id = ?) ",
+			searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PIPELINE_ID], scope.PipelineId, PIPELINE_QUALIFIER)
+		if scope.ProjectId > 0 {
+			query = query.WhereOr("( (identifier_key = ? AND identifier_value_int = ?)  AND qualifier_id = ?) ",
+				searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID], scope.ProjectId, PROJECT_QUALIFIER)
+		}
+		// environment type is matched only for the scopes of an environment
+		if scope.EnvId > 0 {
+			query = query.WhereOr("( (identifier_key = ? AND identifier_value_int = ?)  AND qualifier_id = ?) ",
+				searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_IS_ALL_PRODUCTION_ENV], scope.GetEnvironmentTypeValue(), ENV_TYPE_QUALIFIER)
+		}
+		query = query.WhereOr("(qualifier_id = ? ) ", GLOBAL_QUALIFIER)
+		return query, nil
+	})
 }
 
 func (repo *QualifiersMappingRepositoryImpl) GetQualifierMappings(resourceType ResourceType, scope *Scope, searchableIdMap map[bean.DevtronResourceSearchableKeyName]int, resourceIds []int) ([]*QualifierMapping, error) {
@@ -194,6 +204,8 @@ func (repo *QualifiersMappingRepositoryImpl) addScopeWhereClauseBatch(q *orm.Que
 		query = addCond(query, ENV_QUALIFIER, valuesMap, drs[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID])
 		query = addCond(query, CLUSTER_QUALIFIER, valuesMap, drs[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID])
 		query = addCond(query, PIPELINE_QUALIFIER, valuesMap, drs[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PIPELINE_ID])
+		query = addCond(query, PROJECT_QUALIFIER, valuesMap, drs[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID])
+		query = addCond(query, ENV_TYPE_QUALIFIER, valuesMap, drs[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_IS_ALL_PRODUCTION_ENV])
 		query = query.WhereOr("(qualifier_id = ?)", GLOBAL_QUALIFIER)
 		return query, nil
 	})
@@ -21,9 +21,19 @@ type Scope struct {
 	EnvId          int             `json:"envId"`
 	ClusterId      int             `json:"clusterId"`
 	PipelineId     int             `json:"pipelineId"`
+	ProjectId      int             `json:"projectId"`
+	IsProdEnv      bool            `json:"isProdEnv"`
 	SystemMetadata *SystemMetadata `json:"-"`
 }
 
+// GetEnvironmentTypeValue returns the identifier value of the mappings for all the environments of the type of scope's environment
+func (scope *Scope) GetEnvironmentTypeValue() int {
+	if scope.IsProdEnv {
+		return AllExistingAndFutureProdEnvsInt
+	}
+	return AllExistingAndFutureNonProdEnvsInt
+}
+
 type SystemMetadata struct {
 	EnvironmentName string
 	ClusterName     string
@@ -60,6 +70,8 @@ const (
 	CLUSTER_QUALIFIER     Qualifier = 4
 	GLOBAL_QUALIFIER      Qualifier = 5
 	PIPELINE_QUALIFIER    Qualifier = 6
+	PROJECT_QUALIFIER     Qualifier = 7
+	ENV_TYPE_QUALIFIER    Qualifier = 8
 )
 
 var CompoundQualifiers []Qualifier
@@ -31,6 +31,8 @@ const (
 	ClusterSelector                QualifierSelector = 2
 	ApplicationEnvironmentSelector QualifierSelector = 3
 	GlobalSelector                 QualifierSelector = 4
+	ProjectSelector                QualifierSelector = 5
+	EnvironmentTypeSelector        QualifierSelector = 6
 )
 
 func (selector QualifierSelector) isCompound() bool {
@@ -49,6 +51,10 @@ func (selector QualifierSelector) toQualifier() Qualifier {
 		return APP_AND_ENV_QUALIFIER
 	case GlobalSelector:
 		return GLOBAL_QUALIFIER
+	case ProjectSelector:
+		return PROJECT_QUALIFIER
+	case EnvironmentTypeSelector:
+		return ENV_TYPE_QUALIFIER
 	}
 	return Qualifier(0)
 }
@@ -61,6 +67,10 @@ func GetIdentifierKey(selector QualifierSelector, searchableKeyNameIdMap map[bea
 		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID]
 	case EnvironmentSelector:
 		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID]
+	case ProjectSelector:
+		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID]
+	case EnvironmentTypeSelector:
+		return searchableKeyNameIdMap[bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_IS_ALL_PRODUCTION_ENV]
 	default:
 		return 0
 	}
@@ -80,6 +90,10 @@ func GetSelectorFromKey(key int, searchableKeyIdNameMap map[int]bean.DevtronReso
 		return ClusterSelector
 	case bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID:
 		return EnvironmentSelector
+	case bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_PROJECT_ID:
+		return ProjectSelector
+	case bean.DEVTRON_RESOURCE_SEARCHABLE_KEY_IS_ALL_PRODUCTION_ENV:
+		return EnvironmentTypeSelector
 	default:
 		return 0
 	}
@@ -97,6 +111,10 @@ func GetQualifierIdForSelector(selector QualifierSelector) Qualifier {
 		return CLUSTER_QUALIFIER
 	case GlobalSelector:
 		return GLOBAL_QUALIFIER
+	case ProjectSelector:
+		return PROJECT_QUALIFIER
+	case EnvironmentTypeSelector:
+		return ENV_TYPE_QUALIFIER
 	default:
 		return 0
 	}
@@ -116,6 +134,10 @@ func GetValuesFromSelectionIdentifier(selector QualifierSelector, selectionIdent
 		return selectionIdentifier.EnvId, selectionIdentifier.SelectionIdentifierName.EnvironmentName
 	case ClusterSelector:
 		return selectionIdentifier.ClusterId, selectionIdentifier.SelectionIdentifierName.ClusterName
+	case ProjectSelector:
+		return selectionIdentifier.ProjectId, selectionIdentifier.SelectionIdentifierName.ProjectName
+	case EnvironmentTypeSelector:
+		return selectionIdentifier.EnvironmentType, selectionIdentifier.SelectionIdentifierName.EnvironmentTypeName
 	default:
 		return 0, ""
 	}
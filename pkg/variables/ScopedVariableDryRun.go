@@ -0,0 +1,556 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package variables
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/variables/helper"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
+	repository2 "github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/devtron-labs/devtron/pkg/variables/utils"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// payloadVariable is a variable of a payload with the selections of its values, used to resolve the
+// values of the variables not yet saved
+type payloadVariable struct {
+	definition models.Definition
+	values     []*payloadValue
+}
+
+type payloadValue struct {
+	qualifier           resourceQualifiers.Qualifier
+	selectionIdentifier *resourceQualifiers.SelectionIdentifier
+	value               models.VariableValue
+}
+
+func (value *payloadValue) matches(scope *resourceQualifiers.Scope) bool {
+	switch value.qualifier {
+	case resourceQualifiers.GLOBAL_QUALIFIER:
+		return true
+	case resourceQualifiers.PROJECT_QUALIFIER:
+		return scope.ProjectId > 0 && value.selectionIdentifier.ProjectId == scope.ProjectId
+	case resourceQualifiers.ENV_TYPE_QUALIFIER:
+		return scope.EnvId > 0 && value.selectionIdentifier.EnvironmentType == scope.GetEnvironmentTypeValue()
+	default:
+		return false
+	}
+}
+
+// resolve returns the value of the variable for the scope as per the priority of the qualifiers, nil if no value matches
+func (variable *payloadVariable) resolve(scope *resourceQualifiers.Scope) *payloadValue {
+	if variable == nil {
+		return nil
+	}
+	var selected *payloadValue
+	for _, value := range variable.values {
+		if !value.matches(scope) {
+			continue
+		}
+		if selected == nil || helper.GetPriority(value.qualifier) < helper.GetPriority(selected.qualifier) {
+			selected = value
+		}
+	}
+	return selected
+}
+
+func (impl *ScopedVariableServiceImpl) DryRunVariables(payload models.Payload) (*models.VariableDryRunResponse, error) {
+	err, _ := impl.isValidPayload(payload)
+	if err != nil {
+		impl.logger.Errorw("error in variable payload validation", "err", err)
+		return nil, err
+	}
+	currentPayload, err := impl.GetJsonForVariables()
+	if err != nil {
+		return nil, err
+	}
+	if currentPayload == nil {
+		currentPayload = &models.Payload{}
+	}
+	projectNameToId, err := impl.getProjectNameToIdMap(*currentPayload, payload)
+	if err != nil {
+		return nil, err
+	}
+	currentVariables := getPayloadVariables(*currentPayload, projectNameToId)
+	newVariables := getPayloadVariables(payload, projectNameToId)
+
+	variableNames := make([]string, 0, len(currentVariables)+len(newVariables))
+	for variableName := range currentVariables {
+		variableNames = append(variableNames, variableName)
+	}
+	for variableName := range newVariables {
+		if _, ok := currentVariables[variableName]; !ok {
+			variableNames = append(variableNames, variableName)
+		}
+	}
+	entityScopes, err := impl.variableEntityMappingRepository.GetEntityScopesForVariables(variableNames)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := impl.getScopesForEntityScopes(entityScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.VariableDryRunResponse{Impacts: make([]*models.VariableImpact, 0)}
+	scopeKeyToImpact := make(map[string]*models.VariableImpact)
+	impactKeyToChange := make(map[string]*models.VariableValueChange)
+	for _, entityScope := range entityScopes {
+		scopeKey := fmt.Sprintf("%d-%d", entityScope.AppId, entityScope.EnvId)
+		scope, ok := scopes[scopeKey]
+		if !ok {
+			// app is deleted
+			continue
+		}
+		changeKey := fmt.Sprintf("%s-%s", scopeKey, entityScope.VariableName)
+		change, ok := impactKeyToChange[changeKey]
+		if !ok {
+			// unchanged values are kept as nil to not compare again for the other entities of the scope
+			change = getVariableValueChange(entityScope.VariableName, currentVariables[entityScope.VariableName], newVariables[entityScope.VariableName], scope.Scope)
+			impactKeyToChange[changeKey] = change
+			if change == nil {
+				continue
+			}
+			impact, ok := scopeKeyToImpact[scopeKey]
+			if !ok {
+				impact = &models.VariableImpact{
+					AppId:            entityScope.AppId,
+					AppName:          scope.appName,
+					EnvId:            entityScope.EnvId,
+					EnvName:          scope.envName,
+					Status:           models.VariableImpactChanged,
+					Variables:        make([]*models.VariableValueChange, 0),
+					Templates:        make([]*models.VariableTemplateImpact, 0),
+					CurrentVariables: getScopedVariablesForScope(currentVariables, scope.Scope),
+					NewVariables:     getScopedVariablesForScope(newVariables, scope.Scope),
+				}
+				scopeKeyToImpact[scopeKey] = impact
+				response.Impacts = append(response.Impacts, impact)
+			}
+			impact.Variables = append(impact.Variables, change)
+		}
+		if change == nil {
+			continue
+		}
+		change.Entities = append(change.Entities, &models.VariableEntity{EntityType: int(entityScope.EntityType), EntityId: entityScope.EntityId})
+	}
+	err = impl.renderImpactTemplates(response.Impacts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(response.Impacts, func(i, j int) bool {
+		if response.Impacts[i].AppName != response.Impacts[j].AppName {
+			return response.Impacts[i].AppName < response.Impacts[j].AppName
+		}
+		return response.Impacts[i].EnvName < response.Impacts[j].EnvName
+	})
+	return response, nil
+}
+
+func getPayloadVariables(payload models.Payload, projectNameToId map[string]int) map[string]*payloadVariable {
+	variables := make(map[string]*payloadVariable)
+	for _, variable := range payload.Variables {
+		values := make([]*payloadValue, 0, len(variable.AttributeValues))
+		for _, attributeValue := range variable.AttributeValues {
+			selectionIdentifier, err := getSelectionIdentifier(attributeValue, projectNameToId)
+			if err != nil {
+				// values of deleted projects never match
+				continue
+			}
+			if selectionIdentifier == nil {
+				selectionIdentifier = &resourceQualifiers.SelectionIdentifier{}
+			}
+			values = append(values, &payloadValue{
+				qualifier:           helper.GetQualifierId(attributeValue.AttributeType),
+				selectionIdentifier: selectionIdentifier,
+				value:               attributeValue.VariableValue,
+			})
+		}
+		variables[variable.Definition.VarName] = &payloadVariable{definition: variable.Definition, values: values}
+	}
+	return variables
+}
+
+type dryRunScope struct {
+	*resourceQualifiers.Scope
+	appName string
+	envName string
+}
+
+// getScopesForEntityScopes returns the variable scopes of the apps and envs in which the entities are rendered
+func (impl *ScopedVariableServiceImpl) getScopesForEntityScopes(entityScopes []*repository2.VariableEntityScope) (map[string]*dryRunScope, error) {
+	scopes := make(map[string]*dryRunScope)
+	appIds := make([]int, 0)
+	envIds := make([]*int, 0)
+	appIdSet := make(map[int]bool)
+	envIdSet := make(map[int]bool)
+	for _, entityScope := range entityScopes {
+		if !appIdSet[entityScope.AppId] {
+			appIdSet[entityScope.AppId] = true
+			appIds = append(appIds, entityScope.AppId)
+		}
+		if envId := entityScope.EnvId; envId > 0 && !envIdSet[envId] {
+			envIdSet[envId] = true
+			envIds = append(envIds, &envId)
+		}
+	}
+	if len(appIds) == 0 {
+		return scopes, nil
+	}
+	apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps", "appIds", appIds, "err", err)
+		return nil, err
+	}
+	envs, err := impl.environmentRepository.FindByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "envIds", envIds, "err", err)
+		return nil, err
+	}
+	envIdToName := make(map[int]string)
+	envIdToIsProd := make(map[int]bool)
+	for _, env := range envs {
+		envIdToName[env.Id] = env.Name
+		envIdToIsProd[env.Id] = env.Default
+	}
+	appIdToName := make(map[int]string)
+	appIdToProjectId := make(map[int]int)
+	for _, appObj := range apps {
+		appIdToName[appObj.Id] = appObj.AppName
+		appIdToProjectId[appObj.Id] = appObj.TeamId
+	}
+	for _, entityScope := range entityScopes {
+		appName, ok := appIdToName[entityScope.AppId]
+		if !ok {
+			continue
+		}
+		scopes[fmt.Sprintf("%d-%d", entityScope.AppId, entityScope.EnvId)] = &dryRunScope{
+			Scope: &resourceQualifiers.Scope{
+				AppId:     entityScope.AppId,
+				EnvId:     entityScope.EnvId,
+				ProjectId: appIdToProjectId[entityScope.AppId],
+				IsProdEnv: envIdToIsProd[entityScope.EnvId],
+			},
+			appName: appName,
+			envName: envIdToName[entityScope.EnvId],
+		}
+	}
+	return scopes, nil
+}
+
+// getVariableValueChange compares the current and the new value of the variable for the scope, nil is returned if
+// the value is unchanged or the variable is not resolved in both
+func getVariableValueChange(variableName string, currentVariable, newVariable *payloadVariable, scope *resourceQualifiers.Scope) *models.VariableValueChange {
+	currentValue := currentVariable.resolve(scope)
+	newValue := newVariable.resolve(scope)
+	if currentValue == nil && newValue == nil {
+		return nil
+	}
+	change := &models.VariableValueChange{
+		VariableName: variableName,
+		Status:       models.VariableImpactChanged,
+		Entities:     make([]*models.VariableEntity, 0),
+	}
+	if currentValue != nil {
+		change.CurrentValue = getDisplayValue(currentVariable.definition, currentValue.value)
+	}
+	if newValue == nil {
+		change.Status = models.VariableImpactBroken
+		if newVariable == nil {
+			change.Error = "variable is removed"
+		} else {
+			change.Error = "no value is defined for the scope"
+		}
+		return change
+	}
+	if currentValue != nil && getValueKey(currentValue.value) == getValueKey(newValue.value) {
+		return nil
+	}
+	change.NewValue = getDisplayValue(newVariable.definition, newValue.value)
+	return change
+}
+
+// getValueKey returns the key to compare the values, values are compared by reference for the values sourced
+// from external secret stores
+func getValueKey(value models.VariableValue) string {
+	if value.ValueFrom != nil {
+		return value.ValueFrom.GetCacheKey()
+	}
+	if stringValue, ok := value.Value.(string); ok {
+		return strconv.Quote(stringValue)
+	}
+	return fmt.Sprint(value.Value)
+}
+
+// getScopedVariablesForScope resolves the values of the variables for the scope to render templates with, sensitive
+// values and values from external secret stores are masked
+func getScopedVariablesForScope(variables map[string]*payloadVariable, scope *resourceQualifiers.Scope) []*models.ScopedVariableData {
+	scopedVariables := make([]*models.ScopedVariableData, 0, len(variables))
+	for variableName, variable := range variables {
+		value := variable.resolve(scope)
+		if value == nil {
+			continue
+		}
+		scopedVariable := &models.ScopedVariableData{
+			VariableName:     variableName,
+			ShortDescription: variable.definition.ShortDescription,
+			DataType:         variable.definition.DataType,
+			Constraint:       variable.definition.Constraint,
+		}
+		if variable.definition.VarType.IsTypeSensitive() || value.value.ValueFrom != nil {
+			scopedVariable.VariableValue = &models.VariableValue{Value: models.HiddenValue}
+			scopedVariable.IsRedacted = true
+		} else {
+			scopedVariable.VariableValue = &models.VariableValue{Value: getRenderValue(value.value.Value)}
+		}
+		scopedVariables = append(scopedVariables, scopedVariable)
+	}
+	sort.Slice(scopedVariables, func(i, j int) bool {
+		return scopedVariables[i].VariableName < scopedVariables[j].VariableName
+	})
+	return scopedVariables
+}
+
+// getRenderValue converts the value as it would be read back once saved
+func getRenderValue(value interface{}) interface{} {
+	stringValue, err := utils.StringifyValue(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	renderValue, _ := utils.DestringifyValue(stringValue)
+	return renderValue
+}
+
+func getDisplayValue(definition models.Definition, value models.VariableValue) *models.VariableValue {
+	if definition.VarType.IsTypeSensitive() {
+		return &models.VariableValue{Value: models.HiddenValue, ValueFrom: value.ValueFrom}
+	}
+	return &models.VariableValue{Value: value.Value, ValueFrom: value.ValueFrom}
+}
+
+// entityTemplate is the template of an entity using variables, template is empty for the entities not rendered in
+// the dry run
+type entityTemplate struct {
+	kind         models.VariableTemplateKind
+	templateType parsers.VariableTemplateType
+	template     string
+	message      string
+}
+
+// renderImpactTemplates renders the templates of the entities using the changed variables of each scope, the scope is
+// broken if any of its templates can not be rendered with the proposed variables
+func (impl *ScopedVariableServiceImpl) renderImpactTemplates(impacts []*models.VariableImpact) error {
+	entityToTemplate := make(map[repository2.Entity]*entityTemplate)
+	for _, variableImpact := range impacts {
+		entityToBrokenVariables := getEntityToBrokenVariables(variableImpact)
+		for _, entity := range getImpactedEntities(variableImpact) {
+			template, ok := entityToTemplate[entity]
+			if !ok {
+				var err error
+				template, err = impl.getEntityTemplate(entity)
+				if err != nil {
+					return err
+				}
+				entityToTemplate[entity] = template
+			}
+			templateImpact := impl.renderTemplateImpact(entity, template, variableImpact)
+			if brokenVariables := entityToBrokenVariables[entity]; len(brokenVariables) > 0 {
+				if len(templateImpact.Error) > 0 {
+					brokenVariables = append(brokenVariables, templateImpact.Error)
+				}
+				templateImpact.Error = strings.Join(brokenVariables, ", ")
+			}
+			if len(templateImpact.Error) > 0 {
+				variableImpact.Status = models.VariableImpactBroken
+			}
+			variableImpact.Templates = append(variableImpact.Templates, templateImpact)
+		}
+	}
+	return nil
+}
+
+// getImpactedEntities returns the unique entities using the changed variables of the scope
+func getImpactedEntities(variableImpact *models.VariableImpact) []repository2.Entity {
+	entities := make([]repository2.Entity, 0)
+	entitySet := make(map[repository2.Entity]bool)
+	for _, change := range variableImpact.Variables {
+		for _, variableEntity := range change.Entities {
+			entity := repository2.GetEntity(variableEntity.EntityId, repository2.EntityType(variableEntity.EntityType))
+			if !entitySet[entity] {
+				entitySet[entity] = true
+				entities = append(entities, entity)
+			}
+		}
+	}
+	return entities
+}
+
+// getEntityToBrokenVariables returns the errors of the variables left without a value for the entities using them,
+// templates are rendered ignoring unknown variables so these are not reported by the parser
+func getEntityToBrokenVariables(variableImpact *models.VariableImpact) map[repository2.Entity][]string {
+	entityToBrokenVariables := make(map[repository2.Entity][]string)
+	for _, change := range variableImpact.Variables {
+		if change.Status != models.VariableImpactBroken {
+			continue
+		}
+		for _, variableEntity := range change.Entities {
+			entity := repository2.GetEntity(variableEntity.EntityId, repository2.EntityType(variableEntity.EntityType))
+			entityToBrokenVariables[entity] = append(entityToBrokenVariables[entity], fmt.Sprintf("%s: %s", change.VariableName, change.Error))
+		}
+	}
+	return entityToBrokenVariables
+}
+
+func (impl *ScopedVariableServiceImpl) getEntityTemplate(entity repository2.Entity) (*entityTemplate, error) {
+	switch entity.EntityType {
+	case repository2.EntityTypeDeploymentTemplateAppLevel:
+		chart, err := impl.chartRepository.FindById(entity.EntityId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching chart", "chartId", entity.EntityId, "err", err)
+			return nil, err
+		}
+		template := &entityTemplate{kind: models.DeploymentTemplateKind, templateType: parsers.JsonVariableTemplate}
+		if chart != nil {
+			template.template = chart.GlobalOverride
+		}
+		return template, nil
+	case repository2.EntityTypeDeploymentTemplateEnvLevel:
+		envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(entity.EntityId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching env config override", "envConfigOverrideId", entity.EntityId, "err", err)
+			return nil, err
+		}
+		template := &entityTemplate{kind: models.DeploymentTemplateKind, templateType: parsers.JsonVariableTemplate}
+		if envOverride != nil {
+			template.template = envOverride.EnvOverrideValues
+		}
+		return template, nil
+	case repository2.EntityTypeConfigMapAppLevel:
+		configMap, err := impl.configMapRepository.GetByIdAppLevel(entity.EntityId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching app level config map", "id", entity.EntityId, "err", err)
+			return nil, err
+		}
+		template := &entityTemplate{kind: models.ConfigMapKind, templateType: parsers.StringVariableTemplate}
+		if configMap != nil {
+			template.template = configMap.ConfigMapData
+		}
+		return template, nil
+	case repository2.EntityTypeConfigMapEnvLevel:
+		configMap, err := impl.configMapRepository.GetByIdEnvLevel(entity.EntityId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching env level config map", "id", entity.EntityId, "err", err)
+			return nil, err
+		}
+		template := &entityTemplate{kind: models.ConfigMapKind, templateType: parsers.StringVariableTemplate}
+		if configMap != nil {
+			template.template = configMap.ConfigMapData
+		}
+		return template, nil
+	case repository2.EntityTypeSecretAppLevel, repository2.EntityTypeSecretEnvLevel:
+		return &entityTemplate{kind: models.SecretKind, message: "secrets are not rendered in the dry run"}, nil
+	default:
+		return &entityTemplate{kind: models.PipelineStageKind, message: "pipeline stages are rendered when the pipeline is triggered"}, nil
+	}
+}
+
+// renderTemplateImpact renders the template with the current and the proposed variables of the scope
+func (impl *ScopedVariableServiceImpl) renderTemplateImpact(entity repository2.Entity, template *entityTemplate, variableImpact *models.VariableImpact) *models.VariableTemplateImpact {
+	templateImpact := &models.VariableTemplateImpact{
+		Kind:       template.kind,
+		EntityType: int(entity.EntityType),
+		EntityId:   entity.EntityId,
+		Message:    template.message,
+	}
+	if len(template.template) == 0 {
+		if len(templateImpact.Message) == 0 {
+			templateImpact.Message = "template is deleted"
+		}
+		return templateImpact
+	}
+	after, err := impl.renderTemplate(template, variableImpact.NewVariables)
+	if err != nil {
+		templateImpact.Error = err.Error()
+	}
+	templateImpact.After = after
+	before, err := impl.renderTemplate(template, variableImpact.CurrentVariables)
+	if err != nil {
+		// the proposed variables are still checked for the templates already broken
+		templateImpact.Message = fmt.Sprintf("current template can not be rendered: %s", err.Error())
+		return templateImpact
+	}
+	templateImpact.Before = before
+	if len(templateImpact.Error) > 0 {
+		return templateImpact
+	}
+	templateImpact.Diff, err = getTemplateDiff(before, after)
+	if err != nil {
+		impl.logger.Errorw("error in computing diff of rendered template", "entity", entity, "err", err)
+		templateImpact.Error = err.Error()
+		return templateImpact
+	}
+	if len(templateImpact.Diff) == 0 {
+		templateImpact.Message = "rendered template is unchanged, changed values are masked"
+	}
+	return templateImpact
+}
+
+func (impl *ScopedVariableServiceImpl) renderTemplate(template *entityTemplate, scopedVariables []*models.ScopedVariableData) (string, error) {
+	// system variables are resolved only at deployment, so they are left as is
+	request := parsers.CreateParserRequest(template.template, template.templateType, scopedVariables, true)
+	response := impl.variableTemplateParser.ParseTemplate(request)
+	if response.Error != nil {
+		if len(response.DetailedError) > 0 {
+			return "", errors.New(response.DetailedError)
+		}
+		return "", response.Error
+	}
+	return formatTemplate(response.ResolvedTemplate), nil
+}
+
+// formatTemplate indents json templates to diff them line by line, other templates are returned as is
+func formatTemplate(template string) string {
+	var formatted bytes.Buffer
+	if err := json.Indent(&formatted, []byte(template), "", "  "); err != nil {
+		return template
+	}
+	return formatted.String()
+}
+
+// getTemplateDiff returns the unified diff of the rendered templates, empty if unchanged
+func getTemplateDiff(before, after string) (string, error) {
+	if before == after {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "before",
+		ToFile:   "after",
+		Context:  3,
+	})
+}
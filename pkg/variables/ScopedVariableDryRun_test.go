@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package variables
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
+	"github.com/devtron-labs/devtron/pkg/variables/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTemplateDiff(t *testing.T) {
+	diff, err := getTemplateDiff(formatTemplate(`{"replicas":1}`), formatTemplate(`{"replicas":1}`))
+	assert.Nil(t, err)
+	assert.Empty(t, diff)
+
+	diff, err = getTemplateDiff(formatTemplate(`{"image":"nginx","replicas":1}`), formatTemplate(`{"image":"nginx","replicas":3}`))
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(diff, `-  "replicas": 1`))
+	assert.True(t, strings.Contains(diff, `+  "replicas": 3`))
+	assert.False(t, strings.Contains(diff, `-  "image": "nginx"`))
+}
+
+func TestFormatTemplate(t *testing.T) {
+	assert.Equal(t, "{\n  \"a\": 1\n}", formatTemplate(`{"a":1}`))
+	assert.Equal(t, "key: value", formatTemplate("key: value"))
+}
+
+func TestGetImpactedEntities(t *testing.T) {
+	variableImpact := &models.VariableImpact{
+		Variables: []*models.VariableValueChange{
+			{VariableName: "a", Entities: []*models.VariableEntity{{EntityType: 1, EntityId: 10}, {EntityType: 4, EntityId: 20}}},
+			{VariableName: "b", Entities: []*models.VariableEntity{{EntityType: 1, EntityId: 10}}},
+		},
+	}
+	entities := getImpactedEntities(variableImpact)
+	assert.Equal(t, []repository.Entity{
+		{EntityType: repository.EntityTypeDeploymentTemplateAppLevel, EntityId: 10},
+		{EntityType: repository.EntityTypeConfigMapAppLevel, EntityId: 20},
+	}, entities)
+}
+
+func TestRenderTemplateImpact(t *testing.T) {
+	t.Setenv("SCOPED_VARIABLE_ENABLED", "true")
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	variableTemplateParser, err := parsers.NewVariableTemplateParserImpl(logger)
+	assert.Nil(t, err)
+	impl := &ScopedVariableServiceImpl{logger: logger, variableTemplateParser: variableTemplateParser}
+	entity := repository.Entity{EntityType: repository.EntityTypeDeploymentTemplateAppLevel, EntityId: 10}
+	template := &entityTemplate{
+		kind:         models.DeploymentTemplateKind,
+		templateType: parsers.JsonVariableTemplate,
+		template:     `{"image":"nginx","replicaCount":"@{{REPLICAS}}"}`,
+	}
+	replicas := func(value interface{}) []*models.ScopedVariableData {
+		return []*models.ScopedVariableData{{
+			VariableName:  "REPLICAS",
+			VariableValue: &models.VariableValue{Value: value},
+			DataType:      models.INT_TYPE,
+			Constraint:    &models.ValueConstraint{JsonSchema: map[string]interface{}{"minimum": 1}},
+		}}
+	}
+
+	templateImpact := impl.renderTemplateImpact(entity, template, &models.VariableImpact{CurrentVariables: replicas(1), NewVariables: replicas(3)})
+	assert.Empty(t, templateImpact.Error)
+	assert.True(t, strings.Contains(templateImpact.Diff, `-  "replicaCount": "1"`))
+	assert.True(t, strings.Contains(templateImpact.Diff, `+  "replicaCount": "3"`))
+
+	templateImpact = impl.renderTemplateImpact(entity, template, &models.VariableImpact{CurrentVariables: replicas(1), NewVariables: replicas(0)})
+	assert.True(t, strings.Contains(templateImpact.Error, "REPLICAS"), "values breaking the constraint fail the rendering")
+	assert.Empty(t, templateImpact.Diff)
+	assert.NotEmpty(t, templateImpact.Before)
+
+	templateImpact = impl.renderTemplateImpact(entity, &entityTemplate{kind: models.SecretKind, message: "secrets are not rendered in the dry run"}, &models.VariableImpact{})
+	assert.Empty(t, templateImpact.Before)
+	assert.Equal(t, "secrets are not rendered in the dry run", templateImpact.Message)
+}
+
+func TestGetEntityToBrokenVariables(t *testing.T) {
+	variableImpact := &models.VariableImpact{
+		Variables: []*models.VariableValueChange{
+			{VariableName: "a", Status: models.VariableImpactChanged, Entities: []*models.VariableEntity{{EntityType: 1, EntityId: 10}}},
+			{VariableName: "b", Status: models.VariableImpactBroken, Error: "variable is removed", Entities: []*models.VariableEntity{{EntityType: 4, EntityId: 20}}},
+		},
+	}
+	assert.Equal(t, map[repository.Entity][]string{
+		{EntityType: repository.EntityTypeConfigMapAppLevel, EntityId: 20}: {"b: variable is removed"},
+	}, getEntityToBrokenVariables(variableImpact))
+}
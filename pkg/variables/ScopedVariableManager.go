@@ -18,6 +18,7 @@ package variables
 
 import (
 	"encoding/json"
+	"fmt"
 	mapset "github.com/deckarep/golang-set"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables/models"
@@ -100,7 +101,7 @@ func (impl ScopedVariableManagerImpl) GetMappedVariablesAndResolveTemplate(templ
 	}
 
 	for _, variable := range scopedVariables {
-		variableMap[variable.VariableName] = variable.VariableValue.StringValue()
+		variableMap[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	if len(variableMap) == 0 {
@@ -135,7 +136,7 @@ func (impl ScopedVariableManagerImpl) ExtractVariablesAndResolveTemplate(scope r
 	}
 
 	for _, variable := range scopedVariables {
-		variableSnapshot[variable.VariableName] = variable.VariableValue.StringValue()
+		variableSnapshot[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	if maskUnknownVariable {
@@ -201,6 +202,10 @@ func (impl ScopedVariableManagerImpl) GetVariableSnapshotAndResolveTemplate(temp
 	}
 
 	scopedVariableData := parsers.GetScopedVarData(variableSnapshotMap, varNameToIsSensitive, isSuperAdmin)
+	err = impl.resolveExternalValuesInSnapshot(scopedVariableData)
+	if err != nil {
+		return variableSnapshotMap, template, err
+	}
 	request := parsers.VariableParserRequest{Template: template, TemplateType: templateType, Variables: scopedVariableData, IgnoreUnknownVariables: ignoreUnknown}
 
 	resolvedTemplate, err := impl.ParseTemplateWithScopedVariables(request)
@@ -211,6 +216,27 @@ func (impl ScopedVariableManagerImpl) GetVariableSnapshotAndResolveTemplate(temp
 	return variableSnapshotMap, resolvedTemplate, nil
 }
 
+// resolveExternalValuesInSnapshot resolves the values of variables sourced from external secret stores, saved as references in
+// snapshots. Masked values are left as is.
+func (impl ScopedVariableManagerImpl) resolveExternalValuesInSnapshot(scopedVariableData []*models.ScopedVariableData) error {
+	for _, data := range scopedVariableData {
+		snapshotValue, ok := data.VariableValue.Value.(string)
+		if !ok {
+			continue
+		}
+		valueFrom, isReference := models.GetValueFromSnapshotReference(snapshotValue)
+		if !isReference {
+			continue
+		}
+		value, err := impl.scopedVariableService.ResolveExternalValue(valueFrom)
+		if err != nil {
+			return fmt.Errorf("unable to resolve value of variable %s from %s: %v", data.VariableName, valueFrom.String(), err)
+		}
+		data.VariableValue = &models.VariableValue{Value: value, ValueFrom: valueFrom}
+	}
+	return nil
+}
+
 func (impl ScopedVariableManagerImpl) RemoveMappedVariables(entityId int, entityType repository.EntityType, userId int32, tx *pg.Tx) error {
 
 	err := impl.variableEntityMappingService.DeleteMappingsForEntities([]repository.Entity{{
@@ -253,7 +279,7 @@ func (impl ScopedVariableManagerImpl) GetMappedVariablesAndResolveTemplateBatch(
 
 	variableSnapshot := make(map[string]string)
 	for _, variable := range scopedVariables {
-		variableSnapshot[variable.VariableName] = variable.VariableValue.StringValue()
+		variableSnapshot[variable.VariableName] = variable.GetSnapshotValue()
 	}
 
 	request := parsers.VariableParserRequest{
@@ -26,14 +26,19 @@ import (
 	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/async"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
 	"github.com/devtron-labs/devtron/pkg/variables/cache"
+	"github.com/devtron-labs/devtron/pkg/variables/externalSource"
 	"github.com/devtron-labs/devtron/pkg/variables/helper"
 	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/devtron-labs/devtron/pkg/variables/parsers"
 	repository2 "github.com/devtron-labs/devtron/pkg/variables/repository"
 	"github.com/devtron-labs/devtron/pkg/variables/utils"
 	"github.com/go-pg/pg"
@@ -49,25 +54,51 @@ type ScopedVariableService interface {
 	GetFormattedVariableForName(name string) string
 	GetMatchedScopedVariables(varScope []*resourceQualifiers.QualifierMapping) map[int][]*resourceQualifiers.QualifierMapping
 	GetScopeWithPriority(variableIdToVariableScopes map[int][]*resourceQualifiers.QualifierMapping) map[int]int
+	// ResolveExternalValue returns the value referenced by valueFrom from the external secret store, cached values are reused
+	ResolveExternalValue(valueFrom *models.ValueFrom) (string, error)
+	// DryRunVariables renders the templates using the changed variables with the saved and the proposed variables, and
+	// lists the apps and envs in which the templates would change or fail to render if the payload was saved
+	DryRunVariables(payload models.Payload) (*models.VariableDryRunResponse, error)
 }
 
 type ScopedVariableServiceImpl struct {
-	logger                   *zap.SugaredLogger
-	scopedVariableRepository repository2.ScopedVariableRepository
-	qualifierMappingService  resourceQualifiers.QualifierMappingService
-	VariableNameConfig       *VariableConfig
-	VariableCache            *cache.VariableCacheObj
-	asyncRunnable            *async.Runnable
+	logger                          *zap.SugaredLogger
+	scopedVariableRepository        repository2.ScopedVariableRepository
+	qualifierMappingService         resourceQualifiers.QualifierMappingService
+	VariableNameConfig              *VariableConfig
+	VariableCache                   *cache.VariableCacheObj
+	asyncRunnable                   *async.Runnable
+	externalValueResolver           externalSource.ExternalValueResolver
+	appRepository                   app.AppRepository
+	environmentRepository           repository3.EnvironmentRepository
+	teamRepository                  teamRepository.TeamRepository
+	variableEntityMappingRepository repository2.VariableEntityMappingRepository
+	variableTemplateParser          parsers.VariableTemplateParser
+	chartRepository                 chartRepoRepository.ChartRepository
+	envConfigOverrideRepository     chartConfig.EnvConfigOverrideRepository
+	configMapRepository             chartConfig.ConfigMapRepository
 }
 
 func NewScopedVariableServiceImpl(logger *zap.SugaredLogger, scopedVariableRepository repository2.ScopedVariableRepository, appRepository app.AppRepository, environmentRepository repository3.EnvironmentRepository, devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService, clusterRepository repository.ClusterRepository,
-	qualifierMappingService resourceQualifiers.QualifierMappingService, asyncRunnable *async.Runnable) (*ScopedVariableServiceImpl, error) {
+	qualifierMappingService resourceQualifiers.QualifierMappingService, asyncRunnable *async.Runnable, externalValueResolver externalSource.ExternalValueResolver,
+	teamRepository teamRepository.TeamRepository, variableEntityMappingRepository repository2.VariableEntityMappingRepository,
+	variableTemplateParser parsers.VariableTemplateParser, chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository, configMapRepository chartConfig.ConfigMapRepository) (*ScopedVariableServiceImpl, error) {
 	scopedVariableService := &ScopedVariableServiceImpl{
-		logger:                   logger,
-		scopedVariableRepository: scopedVariableRepository,
-		qualifierMappingService:  qualifierMappingService,
-		VariableCache:            &cache.VariableCacheObj{CacheLock: &sync.Mutex{}},
-		asyncRunnable:            asyncRunnable,
+		logger:                          logger,
+		scopedVariableRepository:        scopedVariableRepository,
+		qualifierMappingService:         qualifierMappingService,
+		VariableCache:                   &cache.VariableCacheObj{CacheLock: &sync.Mutex{}},
+		asyncRunnable:                   asyncRunnable,
+		externalValueResolver:           externalValueResolver,
+		appRepository:                   appRepository,
+		environmentRepository:           environmentRepository,
+		teamRepository:                  teamRepository,
+		variableEntityMappingRepository: variableEntityMappingRepository,
+		variableTemplateParser:          variableTemplateParser,
+		chartRepository:                 chartRepository,
+		envConfigOverrideRepository:     envConfigOverrideRepository,
+		configMapRepository:             configMapRepository,
 	}
 	cfg, err := GetVariableNameConfig()
 	if err != nil {
@@ -205,15 +236,17 @@ func (impl *ScopedVariableServiceImpl) CreateVariables(payload models.Payload) e
 		return err
 	}
 	loadVariableCache(impl.VariableNameConfig, impl)
+	impl.VariableCache.ResetExternalValues()
 	return nil
 }
 
-func (impl *ScopedVariableServiceImpl) storeVariableData(scopeIdToVarData map[int]string, auditLog sql.AuditLog, tx *pg.Tx) error {
+func (impl *ScopedVariableServiceImpl) storeVariableData(scopeIdToVarData map[int]*models.VariableScope, auditLog sql.AuditLog, tx *pg.Tx) error {
 	VariableDataList := make([]*repository2.VariableData, 0)
 	for scopeId, data := range scopeIdToVarData {
 		varData := &repository2.VariableData{
 			VariableScopeId: scopeId,
-			Data:            data,
+			Data:            data.Data,
+			ValueFrom:       data.ValueFrom,
 			AuditLog:        auditLog,
 		}
 		VariableDataList = append(VariableDataList, varData)
@@ -246,23 +279,37 @@ func (impl *ScopedVariableServiceImpl) storeVariableDefinitions(payload models.P
 	return variableNameToId, nil
 }
 
-func (impl *ScopedVariableServiceImpl) createVariableScopes(payload models.Payload, variableNameToId map[string]int, userId int32, tx *pg.Tx) (map[int]string, error) {
+func (impl *ScopedVariableServiceImpl) createVariableScopes(payload models.Payload, variableNameToId map[string]int, userId int32, tx *pg.Tx) (map[int]*models.VariableScope, error) {
 
+	projectNameToId, err := impl.getProjectNameToIdMap(payload)
+	if err != nil {
+		return nil, err
+	}
 	variableScopes := make([]*models.VariableScope, 0)
 	for _, variable := range payload.Variables {
 		variableId := variableNameToId[variable.Definition.VarName]
 		for _, value := range variable.AttributeValues {
-			varValue, err := utils.StringifyValue(value.VariableValue.Value)
+			var varValue string
+			// values sourced from external secret stores are saved as references only
+			if value.VariableValue.ValueFrom == nil {
+				var err error
+				varValue, err = utils.StringifyValue(value.VariableValue.Value)
+				if err != nil {
+					return nil, err
+				}
+			}
+			selectionIdentifier, err := getSelectionIdentifier(value, projectNameToId)
 			if err != nil {
 				return nil, err
 			}
-			selector := resourceQualifiers.GlobalSelector
 			varScope := &models.VariableScope{
-				Data: varValue,
+				Data:      varValue,
+				ValueFrom: value.VariableValue.ValueFrom,
 				ResourceMappingSelection: &resourceQualifiers.ResourceMappingSelection{
-					ResourceType:      resourceQualifiers.Variable,
-					ResourceId:        variableId,
-					QualifierSelector: selector,
+					ResourceType:        resourceQualifiers.Variable,
+					ResourceId:          variableId,
+					QualifierSelector:   helper.GetQualifierSelector(value.AttributeType),
+					SelectionIdentifier: selectionIdentifier,
 				},
 			}
 			variableScopes = append(variableScopes, varScope)
@@ -280,13 +327,64 @@ func (impl *ScopedVariableServiceImpl) createVariableScopes(payload models.Paylo
 	if err != nil {
 		return nil, err
 	}
-	scopeIdToVarData := make(map[int]string)
+	scopeIdToVarData := make(map[int]*models.VariableScope)
 	for _, savedSelection := range savedSelections {
-		scopeIdToVarData[savedSelection.Id] = varScopeToSelection[savedSelection].Data //parentVar.Data
+		scopeIdToVarData[savedSelection.Id] = varScopeToSelection[savedSelection] //parentVar.Data
 	}
 	return scopeIdToVarData, nil
 }
 
+// getProjectNameToIdMap returns the ids of the active projects, fetched only if the payloads have project level values
+func (impl *ScopedVariableServiceImpl) getProjectNameToIdMap(payloads ...models.Payload) (map[string]int, error) {
+	projectNameToId := make(map[string]int)
+	hasProjectValues := false
+	for _, payload := range payloads {
+		for _, variable := range payload.Variables {
+			for _, value := range variable.AttributeValues {
+				if value.AttributeType == models.Project {
+					hasProjectValues = true
+				}
+			}
+		}
+	}
+	if !hasProjectValues {
+		return projectNameToId, nil
+	}
+	teams, err := impl.teamRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching projects", "err", err)
+		return nil, err
+	}
+	for _, team := range teams {
+		projectNameToId[team.Name] = team.Id
+	}
+	return projectNameToId, nil
+}
+
+func getSelectionIdentifier(value models.AttributeValue, projectNameToId map[string]int) (*resourceQualifiers.SelectionIdentifier, error) {
+	switch value.AttributeType {
+	case models.Project:
+		projectName := value.AttributeParams[models.ProjectName]
+		projectId, ok := projectNameToId[projectName]
+		if !ok {
+			return nil, models.ValidationError{Err: fmt.Errorf("project %s not found", projectName)}
+		}
+		return &resourceQualifiers.SelectionIdentifier{
+			ProjectId:               projectId,
+			SelectionIdentifierName: &resourceQualifiers.SelectionIdentifierName{ProjectName: projectName},
+		}, nil
+	case models.EnvironmentType:
+		envType := value.AttributeParams[models.EnvType]
+		envTypeValue, _ := helper.GetEnvironmentTypeValue(envType)
+		return &resourceQualifiers.SelectionIdentifier{
+			EnvironmentType:         envTypeValue,
+			SelectionIdentifierName: &resourceQualifiers.SelectionIdentifierName{EnvironmentTypeName: envType},
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
 func (impl *ScopedVariableServiceImpl) GetMatchedScopedVariables(varScope []*resourceQualifiers.QualifierMapping) map[int][]*resourceQualifiers.QualifierMapping {
 	variableIdToVariableScopes := make(map[int][]*resourceQualifiers.QualifierMapping)
 	for _, vScope := range varScope {
@@ -419,6 +517,10 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 		variableIdToDefinition[definition.Id] = definition
 	}
 
+	err = impl.populateProjectAndEnvironmentType(&scope)
+	if err != nil {
+		return nil, err
+	}
 	varScope, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.Variable, &scope, allVariableIds)
 	if err != nil {
 		impl.logger.Errorw("error in getting varScope", "err", err)
@@ -449,26 +551,34 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 	}
 
 	for varId, scopeId := range variableIdToSelectedScopeId {
-		var value interface{}
-		value, err = utils.DestringifyValue(scopeIdToVarData[scopeId].Data)
-		if err != nil {
-			impl.logger.Errorw("error in validating value", "err", err)
-			return nil, err
-		}
-
 		var varValue *models.VariableValue
 		var isRedacted bool
-		if !unmaskSensitiveData && variableIdToDefinition[varId].VarType == models.PRIVATE {
-			varValue = &models.VariableValue{Value: models.HiddenValue}
-			isRedacted = true
+		if valueFrom := scopeIdToVarData[scopeId].ValueFrom; valueFrom != nil {
+			varValue, isRedacted, err = impl.getExternalVariableValue(variableIdToDefinition[varId].Name, valueFrom, unmaskSensitiveData, varNames == nil)
+			if err != nil {
+				return nil, err
+			}
 		} else {
-			varValue = &models.VariableValue{Value: value}
+			var value interface{}
+			value, err = utils.DestringifyValue(scopeIdToVarData[scopeId].Data)
+			if err != nil {
+				impl.logger.Errorw("error in validating value", "err", err)
+				return nil, err
+			}
+			if !unmaskSensitiveData && variableIdToDefinition[varId].VarType == models.PRIVATE {
+				varValue = &models.VariableValue{Value: models.HiddenValue}
+				isRedacted = true
+			} else {
+				varValue = &models.VariableValue{Value: value}
+			}
 		}
 		scopedVariableData := &models.ScopedVariableData{
 			VariableName:     variableIdToDefinition[varId].Name,
 			ShortDescription: variableIdToDefinition[varId].ShortDescription,
 			VariableValue:    varValue,
-			IsRedacted:       isRedacted}
+			IsRedacted:       isRedacted,
+			DataType:         variableIdToDefinition[varId].DataType,
+			Constraint:       variableIdToDefinition[varId].Constraint}
 
 		scopedVariableDataObj = append(scopedVariableDataObj, scopedVariableData)
 	}
@@ -489,6 +599,8 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 				usedScopedVariableDataObj = append(usedScopedVariableDataObj, &models.ScopedVariableData{
 					VariableName:     definition.Name,
 					ShortDescription: definition.ShortDescription,
+					DataType:         definition.DataType,
+					Constraint:       definition.Constraint,
 				})
 			}
 		}
@@ -499,6 +611,65 @@ func (impl *ScopedVariableServiceImpl) GetScopedVariables(scope resourceQualifie
 	return usedScopedVariableDataObj, err
 }
 
+// populateProjectAndEnvironmentType sets the project of the app and the type of the environment of the scope, these are
+// matched against the values defined at project and environment type level. Both are always derived from the app and env
+// as the scope can be passed by clients.
+func (impl *ScopedVariableServiceImpl) populateProjectAndEnvironmentType(scope *resourceQualifiers.Scope) error {
+	scope.ProjectId, scope.IsProdEnv = 0, false
+	if scope.AppId > 0 {
+		appObj, err := impl.appRepository.FindById(scope.AppId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching app", "appId", scope.AppId, "err", err)
+			return err
+		}
+		if appObj != nil {
+			scope.ProjectId = appObj.TeamId
+		}
+	}
+	if scope.EnvId > 0 {
+		env, err := impl.environmentRepository.FindById(scope.EnvId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching environment", "envId", scope.EnvId, "err", err)
+			return err
+		}
+		if env != nil {
+			scope.IsProdEnv = env.Default
+		}
+	}
+	return nil
+}
+
+// getExternalVariableValue resolves the value of the variable sourced from an external secret store, the value is
+// resolved only when unmasked. Unresolvable values fail the resolution unless ignoreUnresolved, when they are redacted.
+func (impl *ScopedVariableServiceImpl) getExternalVariableValue(variableName string, valueFrom *models.ValueFrom, unmaskSensitiveData bool, ignoreUnresolved bool) (*models.VariableValue, bool, error) {
+	if !unmaskSensitiveData {
+		return &models.VariableValue{Value: models.HiddenValue, ValueFrom: valueFrom}, true, nil
+	}
+	value, err := impl.ResolveExternalValue(valueFrom)
+	if err != nil {
+		if ignoreUnresolved {
+			impl.logger.Warnw("variable value not resolved from external source", "variableName", variableName, "valueFrom", valueFrom.String(), "err", err)
+			return &models.VariableValue{Value: models.HiddenValue, ValueFrom: valueFrom}, true, nil
+		}
+		return nil, false, fmt.Errorf("unable to resolve value of variable %s from %s: %v", variableName, valueFrom.String(), err)
+	}
+	return &models.VariableValue{Value: value, ValueFrom: valueFrom}, false, nil
+}
+
+func (impl *ScopedVariableServiceImpl) ResolveExternalValue(valueFrom *models.ValueFrom) (string, error) {
+	cacheKey := valueFrom.GetCacheKey()
+	if value, ok := impl.VariableCache.GetExternalValue(cacheKey); ok {
+		return value, nil
+	}
+	value, err := impl.externalValueResolver.Resolve(valueFrom)
+	if err != nil {
+		impl.logger.Errorw("error in resolving variable value from external source", "valueFrom", valueFrom.String(), "err", err)
+		return "", err
+	}
+	impl.VariableCache.SetExternalValue(cacheKey, value, impl.externalValueResolver.GetCacheTtl())
+	return value, nil
+}
+
 func resolveExpressionWithVariableValues(expr string, varNameToData map[string]*models.ScopedVariableData) (string, error) {
 	// regex to find  variable placeholder and extracts a variable name which is alphanumeric
 	// and can contain hyphen, underscore and whitespaces. white spaces will be trimmed on lookup
@@ -599,6 +770,7 @@ func (impl *ScopedVariableServiceImpl) GetJsonForVariables() (*models.Payload, e
 			VarType:          data.VarType,
 			Description:      data.Description,
 			ShortDescription: data.ShortDescription,
+			Constraint:       data.Constraint,
 		}
 		attributes := make([]models.AttributeValue, 0)
 
@@ -619,15 +791,25 @@ func (impl *ScopedVariableServiceImpl) GetJsonForVariables() (*models.Payload, e
 				scopeId := scope.Id
 				if parentScopeId == scopeId {
 					variableData := scopeIdVsDataMap[scopeId]
-					var value interface{}
-					value, err = utils.DestringifyValue(variableData.Data)
-					if err != nil {
-						return nil, err
+					if variableData.ValueFrom != nil {
+						attribute.VariableValue = models.VariableValue{
+							ValueFrom: variableData.ValueFrom,
+						}
+					} else {
+						var value interface{}
+						value, err = utils.DestringifyValue(variableData.Data)
+						if err != nil {
+							return nil, err
+						}
+						attribute.VariableValue = models.VariableValue{
+							Value: value,
+						}
 					}
-					attribute.VariableValue = models.VariableValue{
-						Value: value,
+					qualifier := resourceQualifiers.Qualifier(scope.QualifierId)
+					attribute.AttributeType = helper.GetAttributeType(qualifier)
+					if identifierType := helper.GetIdentifierTypeForQualifier(qualifier); len(identifierType) > 0 {
+						attribute.AttributeParams[identifierType] = scope.IdentifierValueString
 					}
-					attribute.AttributeType = helper.GetAttributeType(resourceQualifiers.Qualifier(scope.QualifierId))
 				}
 			}
 			if len(attribute.AttributeParams) == 0 {
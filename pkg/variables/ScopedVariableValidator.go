@@ -44,11 +44,27 @@ func (impl *ScopedVariableServiceImpl) isValidPayload(payload models.Payload) (e
 			return models.ValidationError{Err: fmt.Errorf("%s does not match the required format (Alphanumeric, 64 characters max, no hyphen/underscore at start/end)", variable.Definition.VarName)}, false
 		}
 		variableNamesList = append(variableNamesList, variable.Definition.VarName)
+		if err := utils.ValidateValueConstraint(variable.Definition.DataType, variable.Definition.Constraint); err != nil {
+			return models.ValidationError{Err: fmt.Errorf("invalid constraint for variable %s: %v", variable.Definition.VarName, err)}, false
+		}
 		uniqueVariableMap := make(map[string]interface{})
 		for _, attributeValue := range variable.AttributeValues {
 
-			if !utils.IsStringType(attributeValue.VariableValue.Value) && variable.Definition.VarType.IsTypeSensitive() {
+			if valueFrom := attributeValue.VariableValue.ValueFrom; valueFrom != nil {
+				if attributeValue.VariableValue.Value != nil {
+					return models.ValidationError{Err: fmt.Errorf("only one of value or valueFrom can be set for variable %s", variable.Definition.VarName)}, false
+				}
+				// values of external secret stores are masked like the values of sensitive variables
+				if !variable.Definition.VarType.IsTypeSensitive() {
+					return models.ValidationError{Err: fmt.Errorf("variable %s must be sensitive to source values from external secret stores", variable.Definition.VarName)}, false
+				}
+				if err := impl.externalValueResolver.Validate(valueFrom); err != nil {
+					return models.ValidationError{Err: fmt.Errorf("invalid valueFrom for variable %s: %v", variable.Definition.VarName, err)}, false
+				}
+			} else if !utils.IsStringType(attributeValue.VariableValue.Value) && variable.Definition.VarType.IsTypeSensitive() {
 				return models.ValidationError{Err: fmt.Errorf("data type other than string cannot be sensitive")}, false
+			} else if err := utils.ValidateValue(attributeValue.VariableValue.Value, variable.Definition.DataType, variable.Definition.Constraint); err != nil {
+				return models.ValidationError{Err: fmt.Errorf("invalid value for variable %s: %v", variable.Definition.VarName, err)}, false
 			}
 
 			validIdentifierTypeList := helper.GetIdentifierTypeFromAttributeType(attributeValue.AttributeType)
@@ -60,6 +76,11 @@ func (impl *ScopedVariableServiceImpl) isValidPayload(payload models.Payload) (e
 					return models.ValidationError{Err: fmt.Errorf("invalid attribute selector key %s", key)}, false
 				}
 			}
+			if envType, ok := attributeValue.AttributeParams[models.EnvType]; ok {
+				if _, isValid := helper.GetEnvironmentTypeValue(envType); !isValid {
+					return models.ValidationError{Err: fmt.Errorf("invalid environment type %s, must be one of %s or %s", envType, models.ProductionEnvironmentType, models.NonProductionEnvironmentType)}, false
+				}
+			}
 			identifierString := fmt.Sprintf("%s-%s", variable.Definition.VarName, string(attributeValue.AttributeType))
 			for _, key := range validIdentifierTypeList {
 				identifierString = fmt.Sprintf("%s-%s", identifierString, attributeValue.AttributeParams[key])
@@ -78,6 +99,9 @@ func complexTypeValidator(payload models.Payload) bool {
 		variableType := variable.Definition.DataType
 		if variableType == models.YAML_TYPE || variableType == models.JSON_TYPE {
 			for _, attributeValue := range variable.AttributeValues {
+				if attributeValue.VariableValue.ValueFrom != nil {
+					continue
+				}
 				if attributeValue.VariableValue.Value != "" {
 					if variable.Definition.DataType == models.YAML_TYPE {
 						if !utils.IsValidYAML(attributeValue.VariableValue.Value.(string)) {
@@ -19,12 +19,20 @@ package cache
 import (
 	"github.com/devtron-labs/devtron/pkg/variables/repository"
 	"sync"
+	"time"
 )
 
 type VariableCacheObj struct {
 	definitions []*repository.VariableDefinition
 	loaded      bool
 	CacheLock   *sync.Mutex
+	// externalValues caches the values resolved from external secret stores, keyed by their reference
+	externalValues sync.Map
+}
+
+type externalValue struct {
+	value     string
+	expiresOn time.Time
 }
 
 func (cache *VariableCacheObj) TakeLock() {
@@ -54,3 +62,30 @@ func (cache *VariableCacheObj) GetData() []*repository.VariableDefinition {
 	}
 	return nil
 }
+
+func (cache *VariableCacheObj) GetExternalValue(key string) (string, bool) {
+	cached, ok := cache.externalValues.Load(key)
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(cached.(*externalValue).expiresOn) {
+		cache.externalValues.Delete(key)
+		return "", false
+	}
+	return cached.(*externalValue).value, true
+}
+
+func (cache *VariableCacheObj) SetExternalValue(key, value string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	cache.externalValues.Store(key, &externalValue{value: value, expiresOn: time.Now().Add(ttl)})
+}
+
+// ResetExternalValues drops the cached external values, so that changed references are resolved afresh
+func (cache *VariableCacheObj) ResetExternalValues() {
+	cache.externalValues.Range(func(key, _ interface{}) bool {
+		cache.externalValues.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package externalSource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ExternalValueResolverConfig struct {
+	VaultAddress         string `env:"SCOPED_VARIABLE_VAULT_ADDR" envDefault:"" description:"Address of the HashiCorp Vault server, variable values can be sourced from its KV v2 secrets engine when set"`
+	VaultToken           string `env:"SCOPED_VARIABLE_VAULT_TOKEN" envDefault:"" description:"Token used to read variable values from Vault"`
+	VaultNamespace       string `env:"SCOPED_VARIABLE_VAULT_NAMESPACE" envDefault:"" description:"Vault enterprise namespace of the KV secrets engine"`
+	VaultKvMount         string `env:"SCOPED_VARIABLE_VAULT_KV_MOUNT" envDefault:"secret" description:"Mount path of the Vault KV v2 secrets engine"`
+	SecretClusterName    string `env:"SCOPED_VARIABLE_SECRET_CLUSTER" envDefault:"default_cluster" description:"Cluster from which variable values referencing kubernetes secrets are read"`
+	ResolveTimeoutSecs   int    `env:"SCOPED_VARIABLE_EXTERNAL_VALUE_TIMEOUT_SECS" envDefault:"10" description:"Timeout in seconds for reading a variable value from an external secret store"`
+	ValueCacheTtlSeconds int    `env:"SCOPED_VARIABLE_EXTERNAL_VALUE_CACHE_TTL_SECS" envDefault:"300" description:"Duration in seconds for which the variable values read from external secret stores are cached, 0 disables caching"`
+}
+
+func GetExternalValueResolverConfig() (*ExternalValueResolverConfig, error) {
+	cfg := &ExternalValueResolverConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+func (cfg *ExternalValueResolverConfig) IsVaultConfigured() bool {
+	return len(cfg.VaultAddress) > 0
+}
+
+// ExternalValueResolver reads the values of variables from the external secret stores referenced by them
+type ExternalValueResolver interface {
+	// Validate returns an error if the store referenced by valueFrom is not configured
+	Validate(valueFrom *models.ValueFrom) error
+	Resolve(valueFrom *models.ValueFrom) (string, error)
+	GetCacheTtl() time.Duration
+}
+
+type ExternalValueResolverImpl struct {
+	logger             *zap.SugaredLogger
+	config             *ExternalValueResolverConfig
+	httpClient         *http.Client
+	clusterReadService read.ClusterReadService
+	k8sUtil            k8s.K8sService
+}
+
+func NewExternalValueResolverImpl(logger *zap.SugaredLogger,
+	clusterReadService read.ClusterReadService,
+	k8sUtil k8s.K8sService) (*ExternalValueResolverImpl, error) {
+	cfg, err := GetExternalValueResolverConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalValueResolverImpl{
+		logger:             logger,
+		config:             cfg,
+		httpClient:         &http.Client{Timeout: time.Duration(cfg.ResolveTimeoutSecs) * time.Second},
+		clusterReadService: clusterReadService,
+		k8sUtil:            k8sUtil,
+	}, nil
+}
+
+func (impl *ExternalValueResolverImpl) GetCacheTtl() time.Duration {
+	return time.Duration(impl.config.ValueCacheTtlSeconds) * time.Second
+}
+
+func (impl *ExternalValueResolverImpl) Validate(valueFrom *models.ValueFrom) error {
+	err := valueFrom.Validate()
+	if err != nil {
+		return err
+	}
+	if valueFrom.Vault != nil && !impl.config.IsVaultConfigured() {
+		return fmt.Errorf("vault is not configured, set SCOPED_VARIABLE_VAULT_ADDR to reference vault secrets")
+	}
+	if valueFrom.KubernetesSecret != nil && len(impl.config.SecretClusterName) == 0 {
+		return fmt.Errorf("cluster is not configured, set SCOPED_VARIABLE_SECRET_CLUSTER to reference kubernetes secrets")
+	}
+	return nil
+}
+
+func (impl *ExternalValueResolverImpl) Resolve(valueFrom *models.ValueFrom) (string, error) {
+	err := impl.Validate(valueFrom)
+	if err != nil {
+		return "", err
+	}
+	if valueFrom.Vault != nil {
+		return impl.resolveVaultSecret(valueFrom.Vault)
+	}
+	return impl.resolveKubernetesSecret(valueFrom.KubernetesSecret)
+}
+
+type vaultKvV2Response struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (impl *ExternalValueResolverImpl) resolveVaultSecret(ref *models.VaultSecretRef) (string, error) {
+	secretUrl := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(impl.config.VaultAddress, "/"),
+		strings.Trim(impl.config.VaultKvMount, "/"), strings.TrimPrefix(ref.Path, "/"))
+	req, err := http.NewRequest(http.MethodGet, secretUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", impl.config.VaultToken)
+	if len(impl.config.VaultNamespace) > 0 {
+		req.Header.Set("X-Vault-Namespace", impl.config.VaultNamespace)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		impl.logger.Errorw("error in reading secret from vault", "path", ref.Path, "err", err)
+		return "", fmt.Errorf("vault is not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("secret %s not found in vault", ref.Path)
+	case http.StatusForbidden:
+		return "", fmt.Errorf("permission denied on reading secret %s from vault", ref.Path)
+	default:
+		return "", fmt.Errorf("vault responded with status %d on reading secret %s", resp.StatusCode, ref.Path)
+	}
+	vaultResp := &vaultKvV2Response{}
+	err = json.NewDecoder(resp.Body).Decode(vaultResp)
+	if err != nil {
+		return "", fmt.Errorf("invalid response from vault on reading secret %s: %w", ref.Path, err)
+	}
+	value, ok := vaultResp.Data.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s", ref.Key, ref.Path)
+	}
+	return stringifyValue(value)
+}
+
+func (impl *ExternalValueResolverImpl) resolveKubernetesSecret(ref *models.KubernetesSecretRef) (string, error) {
+	cluster, err := impl.clusterReadService.FindOne(impl.config.SecretClusterName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster for variable secrets", "clusterName", impl.config.SecretClusterName, "err", err)
+		return "", fmt.Errorf("cluster %s not found", impl.config.SecretClusterName)
+	}
+	client, err := impl.k8sUtil.GetCoreV1Client(cluster.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "clusterName", impl.config.SecretClusterName, "err", err)
+		return "", fmt.Errorf("cluster %s is not reachable: %w", impl.config.SecretClusterName, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.ResolveTimeoutSecs)*time.Second)
+	defer cancel()
+	secret, err := impl.k8sUtil.GetSecretWithCtx(ctx, ref.Namespace, ref.Name, client)
+	if k8sErrors.IsNotFound(err) {
+		return "", fmt.Errorf("secret %s/%s not found in cluster %s", ref.Namespace, ref.Name, impl.config.SecretClusterName)
+	} else if k8sErrors.IsForbidden(err) {
+		return "", fmt.Errorf("permission denied on reading secret %s/%s from cluster %s", ref.Namespace, ref.Name, impl.config.SecretClusterName)
+	} else if err != nil {
+		impl.logger.Errorw("error in reading secret", "namespace", ref.Namespace, "name", ref.Name, "err", err)
+		return "", fmt.Errorf("error in reading secret %s/%s from cluster %s: %w", ref.Namespace, ref.Name, impl.config.SecretClusterName, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, ref.Namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// stringifyValue returns string values as is and the json of the other values
+func stringifyValue(value interface{}) (string, error) {
+	if stringValue, ok := value.(string); ok {
+		return stringValue, nil
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(valueBytes), nil
+}
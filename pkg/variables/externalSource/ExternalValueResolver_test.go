@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package externalSource
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func getVaultServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/devtron/db":
+			_, err := w.Write([]byte(`{"data":{"data":{"password":"s3cr3t","port":5432},"metadata":{"version":1}}}`))
+			assert.Nil(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestExternalValueResolverImpl_ResolveVault(t *testing.T) {
+	server := getVaultServer(t)
+	defer server.Close()
+	logger, _ := util.NewSugardLogger()
+	resolver := &ExternalValueResolverImpl{
+		logger:     logger,
+		config:     &ExternalValueResolverConfig{VaultAddress: server.URL, VaultToken: "root", VaultKvMount: "secret"},
+		httpClient: server.Client(),
+	}
+
+	value, err := resolver.Resolve(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/db", Key: "password"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	value, err = resolver.Resolve(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/db", Key: "port"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "5432", value)
+
+	_, err = resolver.Resolve(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/db", Key: "user"}})
+	assert.EqualError(t, err, "key user not found in vault secret devtron/db")
+
+	_, err = resolver.Resolve(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/cache", Key: "password"}})
+	assert.EqualError(t, err, "secret devtron/cache not found in vault")
+
+	resolver.config.VaultToken = "invalid"
+	_, err = resolver.Resolve(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/db", Key: "password"}})
+	assert.EqualError(t, err, "permission denied on reading secret devtron/db from vault")
+}
+
+func TestExternalValueResolverImpl_Validate(t *testing.T) {
+	resolver := &ExternalValueResolverImpl{config: &ExternalValueResolverConfig{SecretClusterName: "default_cluster"}}
+	assert.NotNil(t, resolver.Validate(&models.ValueFrom{}))
+	assert.NotNil(t, resolver.Validate(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "devtron/db", Key: "password"}}))
+	assert.NotNil(t, resolver.Validate(&models.ValueFrom{Vault: &models.VaultSecretRef{Path: "../sys/policy", Key: "password"}}))
+	assert.Nil(t, resolver.Validate(&models.ValueFrom{KubernetesSecret: &models.KubernetesSecretRef{Namespace: "devtroncd", Name: "db", Key: "password"}}))
+	assert.NotNil(t, resolver.Validate(&models.ValueFrom{
+		Vault:            &models.VaultSecretRef{Path: "devtron/db", Key: "password"},
+		KubernetesSecret: &models.KubernetesSecretRef{Namespace: "devtroncd", Name: "db", Key: "password"},
+	}))
+}
+
+func TestValueFromSnapshotReference(t *testing.T) {
+	valueFrom := &models.ValueFrom{KubernetesSecret: &models.KubernetesSecretRef{Namespace: "devtroncd", Name: "db", Key: "password"}}
+	parsed, ok := models.GetValueFromSnapshotReference(valueFrom.GetSnapshotReference())
+	assert.True(t, ok)
+	assert.Equal(t, valueFrom, parsed)
+
+	_, ok = models.GetValueFromSnapshotReference("s3cr3t")
+	assert.False(t, ok)
+}
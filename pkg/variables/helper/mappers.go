@@ -25,15 +25,34 @@ func GetQualifierId(attributeType models.AttributeType) resourceQualifiers.Quali
 	switch attributeType {
 	case models.Global:
 		return resourceQualifiers.GLOBAL_QUALIFIER
+	case models.Project:
+		return resourceQualifiers.PROJECT_QUALIFIER
+	case models.EnvironmentType:
+		return resourceQualifiers.ENV_TYPE_QUALIFIER
 	default:
 		return 0
 	}
 }
 
+func GetQualifierSelector(attributeType models.AttributeType) resourceQualifiers.QualifierSelector {
+	switch attributeType {
+	case models.Project:
+		return resourceQualifiers.ProjectSelector
+	case models.EnvironmentType:
+		return resourceQualifiers.EnvironmentTypeSelector
+	default:
+		return resourceQualifiers.GlobalSelector
+	}
+}
+
 func GetAttributeType(qualifier resourceQualifiers.Qualifier) models.AttributeType {
 	switch qualifier {
 	case resourceQualifiers.GLOBAL_QUALIFIER:
 		return models.Global
+	case resourceQualifiers.PROJECT_QUALIFIER:
+		return models.Project
+	case resourceQualifiers.ENV_TYPE_QUALIFIER:
+		return models.EnvironmentType
 	default:
 		return ""
 	}
@@ -41,7 +60,35 @@ func GetAttributeType(qualifier resourceQualifiers.Qualifier) models.AttributeTy
 
 func GetIdentifierTypeFromAttributeType(attribute models.AttributeType) []models.IdentifierType {
 	switch attribute {
+	case models.Project:
+		return []models.IdentifierType{models.ProjectName}
+	case models.EnvironmentType:
+		return []models.IdentifierType{models.EnvType}
 	default:
 		return nil
 	}
 }
+
+// GetIdentifierTypeForQualifier returns the selector key under which the identifier of the qualifier is exported
+func GetIdentifierTypeForQualifier(qualifier resourceQualifiers.Qualifier) models.IdentifierType {
+	switch qualifier {
+	case resourceQualifiers.PROJECT_QUALIFIER:
+		return models.ProjectName
+	case resourceQualifiers.ENV_TYPE_QUALIFIER:
+		return models.EnvType
+	default:
+		return ""
+	}
+}
+
+// GetEnvironmentTypeValue maps the value of EnvType selector to the identifier value of environment type qualifier
+func GetEnvironmentTypeValue(envType string) (int, bool) {
+	switch envType {
+	case models.ProductionEnvironmentType:
+		return resourceQualifiers.AllExistingAndFutureProdEnvsInt, true
+	case models.NonProductionEnvironmentType:
+		return resourceQualifiers.AllExistingAndFutureNonProdEnvsInt, true
+	default:
+		return 0, false
+	}
+}
@@ -34,10 +34,17 @@ func FindMinWithComparator(variableScope []*resourceQualifiers.QualifierMapping,
 	return min
 }
 
+// GetPriority returns the priority of the qualifier, value of the matched qualifier with the least priority is used.
+// Values of a project or an environment type (all prod or non-prod environments) are less specific than the values
+// of app, env and cluster but override the global values.
 func GetPriority(qualifier resourceQualifiers.Qualifier) int {
 	switch qualifier {
-	case resourceQualifiers.GLOBAL_QUALIFIER:
+	case resourceQualifiers.PROJECT_QUALIFIER:
 		return 5
+	case resourceQualifiers.ENV_TYPE_QUALIFIER:
+		return 6
+	case resourceQualifiers.GLOBAL_QUALIFIER:
+		return 7
 	default:
 		return 0
 	}
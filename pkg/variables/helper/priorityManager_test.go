@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindMinWithComparator(t *testing.T) {
+	globalScope := &resourceQualifiers.QualifierMapping{Id: 1, QualifierId: int(resourceQualifiers.GLOBAL_QUALIFIER)}
+	envTypeScope := &resourceQualifiers.QualifierMapping{Id: 2, QualifierId: int(resourceQualifiers.ENV_TYPE_QUALIFIER)}
+	projectScope := &resourceQualifiers.QualifierMapping{Id: 3, QualifierId: int(resourceQualifiers.PROJECT_QUALIFIER)}
+
+	t.Run("project value overrides environment type and global values", func(t *testing.T) {
+		scopes := []*resourceQualifiers.QualifierMapping{globalScope, envTypeScope, projectScope}
+		assert.Equal(t, projectScope, FindMinWithComparator(scopes, QualifierComparator))
+	})
+	t.Run("environment type value overrides global value", func(t *testing.T) {
+		scopes := []*resourceQualifiers.QualifierMapping{globalScope, envTypeScope}
+		assert.Equal(t, envTypeScope, FindMinWithComparator(scopes, QualifierComparator))
+	})
+	t.Run("no scopes", func(t *testing.T) {
+		assert.Nil(t, FindMinWithComparator(nil, QualifierComparator))
+	})
+}
+
+func TestAttributeTypeMappings(t *testing.T) {
+	for _, attributeType := range []models.AttributeType{models.Global, models.Project, models.EnvironmentType} {
+		qualifier := GetQualifierId(attributeType)
+		assert.Equal(t, attributeType, GetAttributeType(qualifier))
+		assert.Equal(t, qualifier, resourceQualifiers.GetQualifierIdForSelector(GetQualifierSelector(attributeType)))
+		identifierTypes := GetIdentifierTypeFromAttributeType(attributeType)
+		if identifierType := GetIdentifierTypeForQualifier(qualifier); len(identifierType) > 0 {
+			assert.Equal(t, []models.IdentifierType{identifierType}, identifierTypes)
+		} else {
+			assert.Empty(t, identifierTypes)
+		}
+	}
+
+	value, ok := GetEnvironmentTypeValue(models.ProductionEnvironmentType)
+	assert.True(t, ok)
+	assert.Equal(t, resourceQualifiers.AllExistingAndFutureProdEnvsInt, value)
+	value, ok = GetEnvironmentTypeValue(models.NonProductionEnvironmentType)
+	assert.True(t, ok)
+	assert.Equal(t, resourceQualifiers.AllExistingAndFutureNonProdEnvsInt, value)
+	_, ok = GetEnvironmentTypeValue("staging")
+	assert.False(t, ok)
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impact
+
+import (
+	"fmt"
+
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	bean2 "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"go.uber.org/zap"
+)
+
+type VariableImpactService interface {
+	// GetVariableImpact lists the templates of the apps and envs affected by the payload with their rendered diffs
+	GetVariableImpact(payload models.Payload) (*models.VariableImpactResponse, error)
+	// ApplyVariables saves the payload, and if redeploy is set, triggers the deployments of the affected pipelines with
+	// their last deployed artifacts
+	ApplyVariables(payload models.Payload, redeploy bool) (*models.VariableImpactResponse, error)
+}
+
+type VariableImpactServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	scopedVariableService       variables.ScopedVariableService
+	pipelineRepository          pipelineConfig.PipelineRepository
+	cdWorkflowRepository        pipelineConfig.CdWorkflowRepository
+	workflowEventPublishService out.WorkflowEventPublishService
+}
+
+func NewVariableImpactServiceImpl(logger *zap.SugaredLogger, scopedVariableService variables.ScopedVariableService,
+	pipelineRepository pipelineConfig.PipelineRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	workflowEventPublishService out.WorkflowEventPublishService) *VariableImpactServiceImpl {
+	return &VariableImpactServiceImpl{
+		logger:                      logger,
+		scopedVariableService:       scopedVariableService,
+		pipelineRepository:          pipelineRepository,
+		cdWorkflowRepository:        cdWorkflowRepository,
+		workflowEventPublishService: workflowEventPublishService,
+	}
+}
+
+func (impl *VariableImpactServiceImpl) GetVariableImpact(payload models.Payload) (*models.VariableImpactResponse, error) {
+	dryRunResponse, err := impl.scopedVariableService.DryRunVariables(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &models.VariableImpactResponse{Impacts: dryRunResponse.Impacts}, nil
+}
+
+func (impl *VariableImpactServiceImpl) ApplyVariables(payload models.Payload, redeploy bool) (*models.VariableImpactResponse, error) {
+	// impact is computed before saving as it compares the proposed variables with the saved ones
+	response, err := impl.GetVariableImpact(payload)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.scopedVariableService.CreateVariables(payload)
+	if err != nil {
+		return nil, err
+	}
+	if redeploy {
+		response.Redeployments = impl.triggerRedeployments(response.Impacts, payload.UserId)
+	}
+	return response, nil
+}
+
+// triggerRedeployments triggers the deployments of the deployment pipelines of the changed scopes with their last
+// deployed artifacts, scopes with broken templates and scopes never deployed are skipped
+func (impl *VariableImpactServiceImpl) triggerRedeployments(impacts []*models.VariableImpact, userId int32) []*models.VariableRedeployment {
+	redeployments := make([]*models.VariableRedeployment, 0)
+	requests := make([]*bean2.BulkTriggerRequest, 0)
+	triggeredRedeployments := make([]*models.VariableRedeployment, 0)
+	for _, scopeImpact := range getRedeployableImpacts(impacts) {
+		redeployment := &models.VariableRedeployment{
+			AppId:   scopeImpact.AppId,
+			AppName: scopeImpact.AppName,
+			EnvId:   scopeImpact.EnvId,
+			EnvName: scopeImpact.EnvName,
+		}
+		redeployments = append(redeployments, redeployment)
+		if scopeImpact.Status == models.VariableImpactBroken {
+			redeployment.Error = "skipped as the templates of the scope could not be rendered"
+			continue
+		}
+		pipeline, err := impl.pipelineRepository.FindActiveByAppIdAndEnvId(scopeImpact.AppId, scopeImpact.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching pipeline", "appId", scopeImpact.AppId, "envId", scopeImpact.EnvId, "err", err)
+			redeployment.Error = "no active deployment pipeline found"
+			continue
+		}
+		redeployment.PipelineId = pipeline.Id
+		wfrs, err := impl.cdWorkflowRepository.FindArtifactByPipelineIdAndRunnerType(pipeline.Id, apiBean.CD_WORKFLOW_TYPE_DEPLOY, 1, nil)
+		if err != nil || len(wfrs) == 0 || wfrs[0].CdWorkflow == nil {
+			impl.logger.Errorw("error in fetching last deployed artifact", "pipelineId", pipeline.Id, "err", err)
+			redeployment.Error = "pipeline is not deployed yet"
+			continue
+		}
+		redeployment.CiArtifactId = wfrs[0].CdWorkflow.CiArtifactId
+		requests = append(requests, &bean2.BulkTriggerRequest{
+			CiArtifactId: redeployment.CiArtifactId,
+			PipelineId:   redeployment.PipelineId,
+		})
+		triggeredRedeployments = append(triggeredRedeployments, redeployment)
+	}
+	if len(requests) == 0 {
+		return redeployments
+	}
+	cdWorkflows, err := impl.workflowEventPublishService.TriggerBulkDeploymentAsync(requests, userId)
+	if err != nil {
+		impl.logger.Errorw("error in triggering redeployments after variables change", "err", err)
+		for _, redeployment := range triggeredRedeployments {
+			redeployment.Error = fmt.Sprintf("error in triggering deployment: %s", err.Error())
+		}
+		return redeployments
+	}
+	for i, redeployment := range triggeredRedeployments {
+		if i < len(cdWorkflows) {
+			redeployment.CdWorkflowId = cdWorkflows[i].Id
+		}
+	}
+	return redeployments
+}
+
+// getRedeployableImpacts returns the impacts of deployment pipelines, impacts without env are of CI pipeline stages or
+// of apps without deployment pipelines
+func getRedeployableImpacts(impacts []*models.VariableImpact) []*models.VariableImpact {
+	redeployableImpacts := make([]*models.VariableImpact, 0)
+	for _, scopeImpact := range impacts {
+		if scopeImpact.EnvId > 0 {
+			redeployableImpacts = append(redeployableImpacts, scopeImpact)
+		}
+	}
+	return redeployableImpacts
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impact
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRedeployableImpacts(t *testing.T) {
+	impacts := []*models.VariableImpact{
+		{AppId: 1, EnvId: 0},
+		{AppId: 1, EnvId: 2},
+	}
+	redeployableImpacts := getRedeployableImpacts(impacts)
+	assert.Len(t, redeployableImpacts, 1)
+	assert.Equal(t, 2, redeployableImpacts[0].EnvId)
+}
@@ -56,6 +56,32 @@ func (_m *VariableEntityMappingRepository) DeleteVariablesForEntity(tx *pg.Tx, v
 	return r0
 }
 
+// GetEntityScopesForVariables provides a mock function with given fields: variableNames
+func (_m *VariableEntityMappingRepository) GetEntityScopesForVariables(variableNames []string) ([]*repository.VariableEntityScope, error) {
+	ret := _m.Called(variableNames)
+
+	var r0 []*repository.VariableEntityScope
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]*repository.VariableEntityScope, error)); ok {
+		return rf(variableNames)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []*repository.VariableEntityScope); ok {
+		r0 = rf(variableNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.VariableEntityScope)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(variableNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVariablesForEntities provides a mock function with given fields: entities
 func (_m *VariableEntityMappingRepository) GetVariablesForEntities(entities []repository.Entity) ([]*repository.VariableEntityMapping, error) {
 	ret := _m.Called(entities)
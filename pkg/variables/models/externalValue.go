@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExternalValueReferencePrefix prefixes the reference saved in variable snapshots in place of
+// the values of variables sourced from external secret stores, values are resolved again when needed
+const ExternalValueReferencePrefix = "external-value-ref:"
+
+// ValueFrom references a variable value kept in an external secret store, the value is resolved
+// when the template is rendered and is never saved in devtron
+type ValueFrom struct {
+	Vault            *VaultSecretRef      `json:"vault,omitempty"`
+	KubernetesSecret *KubernetesSecretRef `json:"kubernetesSecret,omitempty"`
+}
+
+// VaultSecretRef is the key of a secret in the HashiCorp Vault KV v2 secrets engine
+type VaultSecretRef struct {
+	// Path is the path of the secret relative to the mount of the KV secrets engine
+	Path string `json:"path" validate:"required"`
+	Key  string `json:"key" validate:"required"`
+}
+
+// KubernetesSecretRef is the key of a secret in the cluster configured for scoped variables
+type KubernetesSecretRef struct {
+	Namespace string `json:"namespace" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+	Key       string `json:"key" validate:"required"`
+}
+
+func (valueFrom *ValueFrom) Validate() error {
+	if valueFrom.Vault != nil && valueFrom.KubernetesSecret != nil {
+		return fmt.Errorf("only one of vault or kubernetesSecret can be referenced")
+	}
+	if valueFrom.Vault != nil {
+		if len(valueFrom.Vault.Path) == 0 || len(valueFrom.Vault.Key) == 0 {
+			return fmt.Errorf("path and key are required for vault reference")
+		}
+		if strings.Contains(valueFrom.Vault.Path, "..") {
+			return fmt.Errorf("invalid vault secret path %s", valueFrom.Vault.Path)
+		}
+		return nil
+	}
+	if valueFrom.KubernetesSecret != nil {
+		ref := valueFrom.KubernetesSecret
+		if len(ref.Namespace) == 0 || len(ref.Name) == 0 || len(ref.Key) == 0 {
+			return fmt.Errorf("namespace, name and key are required for kubernetes secret reference")
+		}
+		return nil
+	}
+	return fmt.Errorf("one of vault or kubernetesSecret is required in valueFrom")
+}
+
+// String identifies the referenced value in logs and errors
+func (valueFrom *ValueFrom) String() string {
+	if valueFrom.Vault != nil {
+		return fmt.Sprintf("vault secret %s, key %s", valueFrom.Vault.Path, valueFrom.Vault.Key)
+	} else if valueFrom.KubernetesSecret != nil {
+		ref := valueFrom.KubernetesSecret
+		return fmt.Sprintf("kubernetes secret %s/%s, key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+	return ""
+}
+
+// GetCacheKey identifies the referenced value in the resolved value cache
+func (valueFrom *ValueFrom) GetCacheKey() string {
+	return valueFrom.GetSnapshotReference()
+}
+
+// GetSnapshotReference returns the value saved in variable snapshots in place of the resolved value
+func (valueFrom *ValueFrom) GetSnapshotReference() string {
+	refBytes, _ := json.Marshal(valueFrom)
+	return ExternalValueReferencePrefix + string(refBytes)
+}
+
+// GetValueFromSnapshotReference returns the reference if the snapshot value is a reference to an external value
+func GetValueFromSnapshotReference(snapshotValue string) (*ValueFrom, bool) {
+	if !strings.HasPrefix(snapshotValue, ExternalValueReferencePrefix) {
+		return nil, false
+	}
+	valueFrom := &ValueFrom{}
+	err := json.Unmarshal([]byte(strings.TrimPrefix(snapshotValue, ExternalValueReferencePrefix)), valueFrom)
+	if err != nil || valueFrom.Validate() != nil {
+		return nil, false
+	}
+	return valueFrom, true
+}
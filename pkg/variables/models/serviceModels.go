@@ -21,10 +21,21 @@ import (
 )
 
 type ScopedVariableData struct {
-	VariableName     string         `json:"variableName"`
-	ShortDescription string         `json:"shortDescription"`
-	VariableValue    *VariableValue `json:"variableValue,omitempty"`
-	IsRedacted       bool           `json:"isRedacted"`
+	VariableName     string           `json:"variableName"`
+	ShortDescription string           `json:"shortDescription"`
+	VariableValue    *VariableValue   `json:"variableValue,omitempty"`
+	IsRedacted       bool             `json:"isRedacted"`
+	DataType         DataType         `json:"dataType,omitempty"`
+	Constraint       *ValueConstraint `json:"constraint,omitempty"`
+}
+
+// GetSnapshotValue returns the value to be saved in variable snapshots, values sourced from
+// external secret stores are saved as references
+func (data *ScopedVariableData) GetSnapshotValue() string {
+	if data.VariableValue.ValueFrom != nil {
+		return data.VariableValue.ValueFrom.GetSnapshotReference()
+	}
+	return data.VariableValue.StringValue()
 }
 
 type VariableScopeMapping struct {
@@ -34,5 +45,6 @@ type VariableScopeMapping struct {
 type VariableScope struct {
 	id int
 	*resourceQualifiers.ResourceMappingSelection
-	Data string
+	Data      string
+	ValueFrom *ValueFrom
 }
@@ -0,0 +1,27 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+// ValueConstraint restricts the values of a variable, a value must satisfy all the constraints set
+type ValueConstraint struct {
+	// JsonSchema is the schema the value is validated against, documents of object and list values are validated
+	JsonSchema map[string]interface{} `json:"jsonSchema,omitempty"`
+	// Enum is the list of allowed values
+	Enum []interface{} `json:"enum,omitempty"`
+	// Regex is matched against the string form of string and int values
+	Regex string `json:"regex,omitempty"`
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+type VariableImpactStatus string
+
+const (
+	// VariableImpactChanged is the status of the scopes in which the rendered templates would change
+	VariableImpactChanged VariableImpactStatus = "Changed"
+	// VariableImpactBroken is the status of the scopes in which a template could not be rendered with the proposed
+	// variables, or uses a variable left without a value
+	VariableImpactBroken VariableImpactStatus = "Broken"
+)
+
+// VariableDryRunResponse lists the apps and envs in which the templates using variables would change or break
+// if the proposed variables were saved, with the rendered diffs of the templates
+type VariableDryRunResponse struct {
+	Impacts []*VariableImpact `json:"impacts"`
+}
+
+type VariableImpact struct {
+	AppId     int                    `json:"appId"`
+	AppName   string                 `json:"appName"`
+	EnvId     int                    `json:"envId"`
+	EnvName   string                 `json:"envName,omitempty"`
+	Status    VariableImpactStatus   `json:"status"`
+	Variables []*VariableValueChange `json:"variables"`
+	// Templates are the templates of the scope using the changed variables, rendered with the current and the
+	// proposed variables
+	Templates []*VariableTemplateImpact `json:"templates"`
+	// CurrentVariables and NewVariables are the values of all the current and the proposed variables for the scope,
+	// used to render the templates of the scope. Sensitive values and values from external secret stores are masked
+	CurrentVariables []*ScopedVariableData `json:"-"`
+	NewVariables     []*ScopedVariableData `json:"-"`
+}
+
+type VariableValueChange struct {
+	VariableName string               `json:"variableName"`
+	Status       VariableImpactStatus `json:"status"`
+	CurrentValue *VariableValue       `json:"currentValue,omitempty"`
+	NewValue     *VariableValue       `json:"newValue,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	Entities     []*VariableEntity    `json:"entities"`
+}
+
+// VariableEntity is the entity using the variable, EntityType is one of the entity types of variable entity mappings
+type VariableEntity struct {
+	EntityType int `json:"entityType"`
+	EntityId   int `json:"entityId"`
+}
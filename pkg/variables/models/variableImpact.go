@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+// VariableImpactRequest is the request to preview or apply the proposed variables, Redeploy triggers the deployments of
+// the affected pipelines once the variables are saved
+type VariableImpactRequest struct {
+	Manifest ScopedVariableManifest `json:"manifest"`
+	Redeploy bool                   `json:"redeploy"`
+	UserId   int32                  `json:"-"`
+}
+
+type VariableTemplateKind string
+
+const (
+	DeploymentTemplateKind VariableTemplateKind = "DeploymentTemplate"
+	ConfigMapKind          VariableTemplateKind = "ConfigMap"
+	SecretKind             VariableTemplateKind = "Secret"
+	PipelineStageKind      VariableTemplateKind = "PipelineStage"
+)
+
+// VariableImpactResponse lists the templates of the apps and envs affected by the proposed variables with their
+// rendered diffs, and the deployments triggered once the variables are applied
+type VariableImpactResponse struct {
+	Impacts       []*VariableImpact       `json:"impacts"`
+	Redeployments []*VariableRedeployment `json:"redeployments,omitempty"`
+}
+
+// VariableTemplateImpact is the rendered diff of a template using the changed variables, Message is set if the template
+// is not rendered, as for secrets and pipeline stages. Error is set if the template can not be rendered with the
+// proposed variables or uses a variable left without a value
+type VariableTemplateImpact struct {
+	Kind       VariableTemplateKind `json:"kind"`
+	EntityType int                  `json:"entityType"`
+	EntityId   int                  `json:"entityId"`
+	Before     string               `json:"before,omitempty"`
+	After      string               `json:"after,omitempty"`
+	Diff       string               `json:"diff,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	Message    string               `json:"message,omitempty"`
+}
+
+type VariableRedeployment struct {
+	AppId        int    `json:"appId"`
+	AppName      string `json:"appName"`
+	EnvId        int    `json:"envId"`
+	EnvName      string `json:"envName"`
+	PipelineId   int    `json:"pipelineId,omitempty"`
+	CiArtifactId int    `json:"ciArtifactId,omitempty"`
+	CdWorkflowId int    `json:"cdWorkflowId,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
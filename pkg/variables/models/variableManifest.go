@@ -32,11 +32,14 @@ type VariableSpec struct {
 	IsSensitive      bool                `json:"isSensitive"`
 	Name             string              `json:"name" validate:"required"`
 	Values           []VariableValueSpec `json:"values" validate:"dive"`
+	Type             DataType            `json:"type,omitempty" validate:"omitempty,oneof=primitive string int bool json yaml"`
+	Constraint       *ValueConstraint    `json:"constraint,omitempty"`
 }
 
 type VariableValueSpec struct {
-	Category  AttributeType `json:"category" validate:"oneof=Global"`
-	Value     interface{}   `json:"value" validate:"required"`
+	Category  AttributeType `json:"category" validate:"oneof=Global Project EnvironmentType"`
+	Value     interface{}   `json:"value" validate:"required_without=ValueFrom"`
+	ValueFrom *ValueFrom    `json:"valueFrom,omitempty"`
 	Selectors *Selector     `json:"selectors,omitempty"`
 }
 
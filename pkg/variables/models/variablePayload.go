@@ -31,16 +31,19 @@ type Variables struct {
 }
 type AttributeValue struct {
 	VariableValue   VariableValue             `json:"variableValue" validate:"required,dive"`
-	AttributeType   AttributeType             `json:"attributeType" validate:"oneof=Global"`
+	AttributeType   AttributeType             `json:"attributeType" validate:"oneof=Global Project EnvironmentType"`
 	AttributeParams map[IdentifierType]string `json:"attributeParams"`
 }
 
 type Definition struct {
 	VarName          string       `json:"varName" validate:"required"`
-	DataType         DataType     `json:"dataType" validate:"oneof=json yaml primitive"`
+	DataType         DataType     `json:"dataType" validate:"oneof=json yaml primitive string int bool"`
 	VarType          VariableType `json:"varType" validate:"oneof=private public"`
 	Description      string       `json:"description" validate:"max=300"`
 	ShortDescription string       `json:"shortDescription"`
+	// Constraint is validated, along with the DataType, for the values of the variable when saved and when a
+	// template is parsed
+	Constraint *ValueConstraint `json:"constraint,omitempty"`
 }
 
 type VariableType string
@@ -53,11 +56,25 @@ const (
 type DataType string
 
 const (
-	YAML_TYPE      DataType = "yaml"
-	JSON_TYPE      DataType = "json"
+	YAML_TYPE DataType = "yaml"
+	JSON_TYPE DataType = "json"
+	// PRIMITIVE_TYPE values are not validated, STRING_TYPE, INT_TYPE and BOOL_TYPE values are primitives of the type
 	PRIMITIVE_TYPE DataType = "primitive"
+	STRING_TYPE    DataType = "string"
+	INT_TYPE       DataType = "int"
+	BOOL_TYPE      DataType = "bool"
 )
 
+// IsComplexType returns true for the types whose values are JSON or YAML documents
+func (dataType DataType) IsComplexType() bool {
+	return dataType == JSON_TYPE || dataType == YAML_TYPE
+}
+
+// IsValidated returns true for the types whose values are checked against the type
+func (dataType DataType) IsValidated() bool {
+	return len(dataType) > 0 && dataType != PRIMITIVE_TYPE
+}
+
 const HiddenValue = "hidden-value"
 const UndefinedValue = "undefined-variable-value"
 
@@ -71,15 +88,30 @@ func (variableType VariableType) IsTypeSensitive() bool {
 type AttributeType string
 
 const (
-	Global AttributeType = "Global"
+	Global          AttributeType = "Global"
+	Project         AttributeType = "Project"
+	EnvironmentType AttributeType = "EnvironmentType"
 )
 
 type IdentifierType string
 
-var IdentifiersList []IdentifierType
+const (
+	ProjectName IdentifierType = "ProjectName"
+	EnvType     IdentifierType = "EnvironmentType"
+)
+
+var IdentifiersList = []IdentifierType{ProjectName, EnvType}
+
+// values of EnvType selector
+const (
+	ProductionEnvironmentType    = "production"
+	NonProductionEnvironmentType = "non-production"
+)
 
 type VariableValue struct {
-	Value interface{} `json:"value" validate:"required"`
+	Value interface{} `json:"value" validate:"required_without=ValueFrom"`
+	// ValueFrom is set if the value is sourced from an external secret store
+	ValueFrom *ValueFrom `json:"valueFrom,omitempty"`
 }
 
 func (value VariableValue) StringValue() string {
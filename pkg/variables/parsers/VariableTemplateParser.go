@@ -184,6 +184,10 @@ func (impl *VariableTemplateParserImpl) parseTemplate(parserRequest VariablePars
 	if updatedHclExpression != nil {
 		hclExpression = updatedHclExpression
 	}
+	containsError = impl.checkForInvalidVariableValues(parserRequest, hclExpression.Variables(), &response)
+	if containsError {
+		return response
+	}
 
 	hclVarValues := impl.getHclVarValues(values)
 	opValue, diagnostics := hclExpression.Value(&hcl.EvalContext{
@@ -227,6 +231,34 @@ func (impl *VariableTemplateParserImpl) checkForDefaultedVariables(parserRequest
 	return hclExpression, template, false
 }
 
+// checkForInvalidVariableValues validates the values of the used variables against their declared type and constraints,
+// redacted values are not validated
+func (impl *VariableTemplateParserImpl) checkForInvalidVariableValues(parserRequest VariableParserRequest, variables []hcl.Traversal, response *VariableParserResponse) bool {
+	usedVariables := make(map[string]bool)
+	for _, variable := range variables {
+		usedVariables[variable.RootName()] = true
+	}
+	invalidValueErrs := make([]string, 0)
+	for _, variable := range parserRequest.Variables {
+		if !usedVariables[variable.VariableName] || variable.VariableValue == nil || variable.IsRedacted {
+			continue
+		}
+		if !variable.DataType.IsValidated() && variable.Constraint == nil {
+			continue
+		}
+		if err := utils.ValidateValue(variable.VariableValue.Value, variable.DataType, variable.Constraint); err != nil {
+			invalidValueErrs = append(invalidValueErrs, fmt.Sprintf("%s: %s", variable.VariableName, err.Error()))
+		}
+	}
+	if len(invalidValueErrs) == 0 {
+		return false
+	}
+	impl.logger.Errorw("error occurred while parsing template, invalid variable values found", "errs", invalidValueErrs)
+	response.Error = errors.New(InvalidVariableValue)
+	response.DetailedError = fmt.Sprintf(InvalidVariableValueErrorMsg, strings.Join(invalidValueErrs, ", "))
+	return true
+}
+
 func (impl *VariableTemplateParserImpl) extractResolvedTemplate(templateType VariableTemplateType, opValue cty.Value) (string, error) {
 	var output string
 	if templateType == StringVariableTemplate {
@@ -24,8 +24,10 @@ import (
 const InvalidTemplate = "invalid-template"
 const VariableParsingFailed = "variable-parsing-failed"
 const UnknownVariableFound = "unknown-variable-found"
+const InvalidVariableValue = "invalid-variable-value"
 
 const UnknownVariableErrorMsg = "unknown variables found, %s"
+const InvalidVariableValueErrorMsg = "invalid values of variables, %s"
 
 type VariableTemplateType int
 
@@ -100,7 +102,7 @@ func GetVariableMapForUsedVariables(scopedVariables []*models.ScopedVariableData
 	variableMap := make(map[string]string)
 	for _, variable := range scopedVariables {
 		if slices.Contains(usedVars, variable.VariableName) {
-			variableMap[variable.VariableName] = variable.VariableValue.StringValue()
+			variableMap[variable.VariableName] = variable.GetSnapshotValue()
 		}
 	}
 	return variableMap
@@ -22,14 +22,15 @@ import (
 )
 
 type VariableDefinition struct {
-	tableName        struct{}            `sql:"variable_definition" pg:",discard_unknown_columns"`
-	Id               int                 `sql:"id,pk"`
-	Name             string              `sql:"name"`
-	DataType         models.DataType     `sql:"data_type"`
-	VarType          models.VariableType `sql:"var_type"`
-	Active           bool                `sql:"active"`
-	Description      string              `sql:"description"`
-	ShortDescription string              `json:"short_description"`
+	tableName        struct{}                `sql:"variable_definition" pg:",discard_unknown_columns"`
+	Id               int                     `sql:"id,pk"`
+	Name             string                  `sql:"name"`
+	DataType         models.DataType         `sql:"data_type"`
+	VarType          models.VariableType     `sql:"var_type"`
+	Active           bool                    `sql:"active"`
+	Description      string                  `sql:"description"`
+	ShortDescription string                  `json:"short_description"`
+	Constraint       *models.ValueConstraint `sql:"value_constraint"`
 	sql.AuditLog
 }
 
@@ -38,6 +39,8 @@ type VariableData struct {
 	Id              int      `sql:"id,pk"`
 	VariableScopeId int      `sql:"variable_scope_id"`
 	Data            string   `sql:"data"`
+	// ValueFrom is set if the value is sourced from an external secret store, Data is empty then
+	ValueFrom *models.ValueFrom `sql:"value_from"`
 	sql.AuditLog
 }
 
@@ -48,6 +51,7 @@ func CreateFromDefinition(definition models.Definition, auditLog sql.AuditLog) *
 	varDefinition.VarType = definition.VarType
 	varDefinition.Description = definition.Description
 	varDefinition.ShortDescription = definition.ShortDescription
+	varDefinition.Constraint = definition.Constraint
 	varDefinition.Active = true
 	varDefinition.AuditLog = auditLog
 	return varDefinition
@@ -82,7 +82,7 @@ func (impl *ScopedVariableRepositoryImpl) GetAllVariableMetadata() ([]*VariableD
 	variableDefinition := make([]*VariableDefinition, 0)
 	err := impl.
 		dbConnection.Model(&variableDefinition).
-		Column("id", "name", "data_type", "var_type", "short_description").
+		Column("id", "name", "data_type", "var_type", "short_description", "value_type", "value_constraint").
 		Where("active = ?", true).
 		Select()
 	if err == pg.ErrNoRows {
@@ -47,6 +47,15 @@ const (
 	EntityTypeSecretEnvLevel             EntityType = 7
 )
 
+// VariableEntityScope is an app and env in which the entity using the variable is rendered, EnvId is 0 for CI pipeline
+// stages and for the app level entities of apps without deployment pipelines
+type VariableEntityScope struct {
+	VariableName string `sql:"variable_name"`
+	Entity
+	AppId int `sql:"app_id"`
+	EnvId int `sql:"env_id"`
+}
+
 func GetEntity(entityId int, entityType EntityType) Entity {
 
 	return Entity{
@@ -30,6 +30,7 @@ type VariableEntityMappingRepository interface {
 	SaveVariableEntityMappings(tx *pg.Tx, mappings []*VariableEntityMapping) error
 	DeleteAllVariablesForEntities(tx *pg.Tx, entities []Entity, userId int32) error
 	DeleteVariablesForEntity(tx *pg.Tx, variableIDs []string, entity Entity, userId int32) error
+	GetEntityScopesForVariables(variableNames []string) ([]*VariableEntityScope, error)
 }
 
 func NewVariableEntityMappingRepository(logger *zap.SugaredLogger, dbConnection *pg.DB, TransactionUtilImpl *sql.TransactionUtilImpl) *VariableEntityMappingRepositoryImpl {
@@ -116,3 +117,50 @@ func (impl *VariableEntityMappingRepositoryImpl) DeleteAllVariablesForEntities(t
 	}
 	return nil
 }
+
+// GetEntityScopesForVariables returns the apps and envs in which the entities using the variables are rendered, app level
+// entities are rendered in every env of the app's deployment pipelines, app level deployment templates only in the envs
+// not overriding the template
+func (impl *VariableEntityMappingRepositoryImpl) GetEntityScopesForVariables(variableNames []string) ([]*VariableEntityScope, error) {
+	scopes := make([]*VariableEntityScope, 0)
+	if len(variableNames) == 0 {
+		return scopes, nil
+	}
+	query := `WITH mapping AS (SELECT variable_name, entity_type, entity_id FROM variable_entity_mapping WHERE is_deleted = false AND variable_name IN (?))
+		SELECT m.variable_name, m.entity_type, m.entity_id, ch.app_id, COALESCE(p.environment_id, 0) AS env_id FROM mapping m
+		INNER JOIN charts ch ON ch.id = m.entity_id AND ch.active = true
+		LEFT JOIN pipeline p ON p.app_id = ch.app_id AND p.deleted = false
+		WHERE m.entity_type = ? AND NOT EXISTS (SELECT 1 FROM chart_env_config_override ceco WHERE ceco.chart_id = ch.id
+			AND ceco.target_environment = p.environment_id AND ceco.active = true AND ceco.is_override = true)
+		UNION ALL
+		SELECT m.variable_name, m.entity_type, m.entity_id, ch.app_id, ceco.target_environment AS env_id FROM mapping m
+		INNER JOIN chart_env_config_override ceco ON ceco.id = m.entity_id AND ceco.active = true
+		INNER JOIN charts ch ON ch.id = ceco.chart_id
+		WHERE m.entity_type = ?
+		UNION ALL
+		SELECT m.variable_name, m.entity_type, m.entity_id, COALESCE(p.app_id, cp.app_id) AS app_id, COALESCE(p.environment_id, 0) AS env_id FROM mapping m
+		INNER JOIN pipeline_stage ps ON ps.id = m.entity_id AND ps.deleted = false
+		LEFT JOIN pipeline p ON p.id = ps.cd_pipeline_id AND p.deleted = false
+		LEFT JOIN ci_pipeline cp ON cp.id = ps.ci_pipeline_id AND cp.deleted = false
+		WHERE m.entity_type = ? AND (p.id IS NOT NULL OR cp.id IS NOT NULL)
+		UNION ALL
+		SELECT m.variable_name, m.entity_type, m.entity_id, cm.app_id, COALESCE(p.environment_id, 0) AS env_id FROM mapping m
+		INNER JOIN config_map_app_level cm ON cm.id = m.entity_id
+		LEFT JOIN pipeline p ON p.app_id = cm.app_id AND p.deleted = false
+		WHERE m.entity_type IN (?, ?)
+		UNION ALL
+		SELECT m.variable_name, m.entity_type, m.entity_id, cm.app_id, cm.environment_id AS env_id FROM mapping m
+		INNER JOIN config_map_env_level cm ON cm.id = m.entity_id AND cm.deleted = false
+		WHERE m.entity_type IN (?, ?);`
+	_, err := impl.dbConnection.Query(&scopes, query, pg.In(variableNames),
+		EntityTypeDeploymentTemplateAppLevel,
+		EntityTypeDeploymentTemplateEnvLevel,
+		EntityTypePipelineStage,
+		EntityTypeConfigMapAppLevel, EntityTypeSecretAppLevel,
+		EntityTypeConfigMapEnvLevel, EntityTypeSecretEnvLevel)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("err in getting entity scopes for variables", "variableNames", variableNames, "err", err)
+		return nil, err
+	}
+	return scopes, nil
+}
@@ -17,6 +17,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"github.com/devtron-labs/devtron/pkg/variables/models"
 )
 
@@ -28,8 +29,8 @@ func ManifestToPayload(manifest models.ScopedVariableManifest, userId int32) mod
 		attributes := make([]models.AttributeValue, 0)
 		for _, value := range spec.Values {
 			attribute := models.AttributeValue{
-				VariableValue: models.VariableValue{Value: value.Value},
-				AttributeType: models.Global,
+				VariableValue: models.VariableValue{Value: getManifestValue(value.Value, spec.Type), ValueFrom: value.ValueFrom},
+				AttributeType: value.Category,
 			}
 
 			if value.Selectors != nil && value.Selectors.AttributeSelectors != nil {
@@ -40,13 +41,17 @@ func ManifestToPayload(manifest models.ScopedVariableManifest, userId int32) mod
 		variable := models.Variables{
 			Definition: models.Definition{
 				VarName:          spec.Name,
-				DataType:         models.PRIMITIVE_TYPE,
+				DataType:         spec.Type,
 				VarType:          models.PUBLIC,
 				Description:      spec.Notes,
 				ShortDescription: spec.ShortDescription,
+				Constraint:       spec.Constraint,
 			},
 			AttributeValues: attributes,
 		}
+		if len(spec.Type) == 0 {
+			variable.Definition.DataType = models.PRIMITIVE_TYPE
+		}
 		if spec.IsSensitive {
 			variable.Definition.VarType = models.PRIVATE
 		}
@@ -72,11 +77,14 @@ func PayloadToManifest(payload models.Payload) models.ScopedVariableManifest {
 			ShortDescription: variable.Definition.ShortDescription,
 			Values:           make([]models.VariableValueSpec, 0),
 			IsSensitive:      variable.Definition.VarType.IsTypeSensitive(),
+			Type:             variable.Definition.DataType,
+			Constraint:       variable.Definition.Constraint,
 		}
 		for _, attribute := range variable.AttributeValues {
 			valueSpec := models.VariableValueSpec{
-				Value:    attribute.VariableValue.Value,
-				Category: attribute.AttributeType,
+				Value:     attribute.VariableValue.Value,
+				ValueFrom: attribute.VariableValue.ValueFrom,
+				Category:  attribute.AttributeType,
 			}
 			if attribute.AttributeParams != nil {
 				valueSpec.Selectors = &models.Selector{AttributeSelectors: attribute.AttributeParams}
@@ -87,3 +95,18 @@ func PayloadToManifest(payload models.Payload) models.ScopedVariableManifest {
 	}
 	return manifest
 }
+
+// getManifestValue returns the JSON document of the json and yaml values written as YAML in the manifest,
+// values are saved as strings
+func getManifestValue(value interface{}, dataType models.DataType) interface{} {
+	if !dataType.IsComplexType() {
+		return value
+	}
+	if _, ok := value.(string); ok || value == nil {
+		return value
+	}
+	if document, err := json.Marshal(value); err == nil {
+		return string(document)
+	}
+	return value
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/ghodss/yaml"
+	"github.com/xeipuuv/gojsonschema"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// ValidateValueConstraint checks that the constraint can be applied on the values of the data type
+func ValidateValueConstraint(dataType models.DataType, constraint *models.ValueConstraint) error {
+	if constraint == nil {
+		return nil
+	}
+	if len(constraint.Regex) > 0 {
+		if dataType.IsValidated() && dataType != models.STRING_TYPE && dataType != models.INT_TYPE {
+			return fmt.Errorf("regex is supported only for primitive, string and int values")
+		}
+		if _, err := regexp.Compile(constraint.Regex); err != nil {
+			return fmt.Errorf("invalid regex %s: %v", constraint.Regex, err)
+		}
+	}
+	if constraint.JsonSchema != nil {
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(constraint.JsonSchema)); err != nil {
+			return fmt.Errorf("invalid json schema: %v", err)
+		}
+	}
+	for _, enumValue := range constraint.Enum {
+		if _, err := GetTypedValue(enumValue, dataType); err != nil {
+			return fmt.Errorf("invalid enum value: %v", err)
+		}
+	}
+	return nil
+}
+
+// ValidateValue checks that the value is of the data type and satisfies the constraint
+func ValidateValue(value interface{}, dataType models.DataType, constraint *models.ValueConstraint) error {
+	typedValue, err := GetTypedValue(value, dataType)
+	if err != nil {
+		return err
+	}
+	if constraint == nil {
+		return nil
+	}
+	if len(constraint.Enum) > 0 {
+		if err = validateEnum(typedValue, dataType, constraint.Enum); err != nil {
+			return err
+		}
+	}
+	if len(constraint.Regex) > 0 {
+		if err = validateRegex(typedValue, constraint.Regex); err != nil {
+			return err
+		}
+	}
+	if constraint.JsonSchema != nil {
+		if err = validateJsonSchema(typedValue, constraint.JsonSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTypedValue converts the value to the data type, values are saved as strings hence documents of json and
+// yaml values are parsed. Values of primitive variables are returned as is.
+func GetTypedValue(value interface{}, dataType models.DataType) (interface{}, error) {
+	switch dataType {
+	case models.STRING_TYPE:
+		if stringValue, ok := value.(string); ok {
+			return stringValue, nil
+		}
+	case models.INT_TYPE:
+		if intValue, ok := getIntValue(value); ok {
+			return intValue, nil
+		}
+	case models.BOOL_TYPE:
+		if boolValue, ok := value.(bool); ok {
+			return boolValue, nil
+		}
+	case models.JSON_TYPE, models.YAML_TYPE:
+		return getDocument(value)
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("value %v is not of type %s", value, dataType)
+}
+
+func getIntValue(value interface{}) (int64, bool) {
+	switch typedValue := value.(type) {
+	case int:
+		return int64(typedValue), true
+	case int32:
+		return int64(typedValue), true
+	case int64:
+		return typedValue, true
+	case float64:
+		if typedValue == math.Trunc(typedValue) {
+			return int64(typedValue), true
+		}
+	case json.Number:
+		if intValue, err := typedValue.Int64(); err == nil {
+			return intValue, true
+		}
+	}
+	return 0, false
+}
+
+// getDocument parses the JSON or YAML document of the value, values already parsed are returned as is
+func getDocument(value interface{}) (interface{}, error) {
+	stringValue, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	jsonValue, err := yaml.YAMLToJSON([]byte(stringValue))
+	if err != nil {
+		return nil, fmt.Errorf("value is not a valid json or yaml document: %v", err)
+	}
+	var document interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(jsonValue)))
+	decoder.UseNumber()
+	if err = decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("value is not a valid json or yaml document: %v", err)
+	}
+	return document, nil
+}
+
+func validateEnum(typedValue interface{}, dataType models.DataType, enum []interface{}) error {
+	value, err := json.Marshal(typedValue)
+	if err != nil {
+		return err
+	}
+	for _, enumValue := range enum {
+		typedEnumValue, err := GetTypedValue(enumValue, dataType)
+		if err != nil {
+			continue
+		}
+		if allowedValue, err := json.Marshal(typedEnumValue); err == nil && string(allowedValue) == string(value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("value %s is not one of the allowed values", string(value))
+}
+
+func validateRegex(typedValue interface{}, regex string) error {
+	regexExpression, err := regexp.Compile(regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex %s: %v", regex, err)
+	}
+	value := fmt.Sprint(typedValue)
+	if !regexExpression.MatchString(value) {
+		return fmt.Errorf("value %s does not match regex %s", value, regex)
+	}
+	return nil
+}
+
+func validateJsonSchema(typedValue interface{}, jsonSchema map[string]interface{}) error {
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(jsonSchema), gojsonschema.NewGoLoader(typedValue))
+	if err != nil {
+		return fmt.Errorf("value cannot be validated against json schema: %v", err)
+	}
+	if !result.Valid() {
+		errs := make([]string, 0, len(result.Errors()))
+		for _, resultErr := range result.Errors() {
+			errs = append(errs, resultErr.String())
+		}
+		return fmt.Errorf("value does not match json schema: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
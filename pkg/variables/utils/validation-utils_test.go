@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/variables/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      interface{}
+		dataType   models.DataType
+		constraint *models.ValueConstraint
+		wantErr    bool
+	}{
+		{name: "untyped value", value: "abc"},
+		{name: "primitive value", value: 10, dataType: models.PRIMITIVE_TYPE},
+		{name: "int value", value: json.Number("10"), dataType: models.INT_TYPE},
+		{name: "saved int value", value: 10, dataType: models.INT_TYPE},
+		{name: "int value with typo", value: "1o", dataType: models.INT_TYPE, wantErr: true},
+		{name: "decimal int value", value: json.Number("1.5"), dataType: models.INT_TYPE, wantErr: true},
+		{name: "bool value", value: true, dataType: models.BOOL_TYPE},
+		{name: "string as bool value", value: "true", dataType: models.BOOL_TYPE, wantErr: true},
+		{name: "object value", value: `{"cpu": "100m"}`, dataType: models.JSON_TYPE},
+		{name: "yaml object value", value: "cpu: 100m\nmemory: 1Gi", dataType: models.YAML_TYPE},
+		{name: "list value", value: "- a\n- b", dataType: models.YAML_TYPE},
+		{name: "invalid json value", value: `{"cpu": `, dataType: models.JSON_TYPE, wantErr: true},
+		{name: "list as object value", value: `["a"]`, dataType: models.JSON_TYPE,
+			constraint: &models.ValueConstraint{JsonSchema: map[string]interface{}{"type": "object"}}, wantErr: true},
+		{name: "enum value", value: "prod", dataType: models.STRING_TYPE,
+			constraint: &models.ValueConstraint{Enum: []interface{}{"dev", "prod"}}},
+		{name: "value not in enum", value: "qa", dataType: models.STRING_TYPE,
+			constraint: &models.ValueConstraint{Enum: []interface{}{"dev", "prod"}}, wantErr: true},
+		{name: "int enum value", value: 3, dataType: models.INT_TYPE,
+			constraint: &models.ValueConstraint{Enum: []interface{}{json.Number("1"), json.Number("3")}}},
+		{name: "regex match", value: "v1.2.0", dataType: models.STRING_TYPE,
+			constraint: &models.ValueConstraint{Regex: `^v\d+\.\d+\.\d+$`}},
+		{name: "regex mismatch", value: "1.2", dataType: models.STRING_TYPE,
+			constraint: &models.ValueConstraint{Regex: `^v\d+\.\d+\.\d+$`}, wantErr: true},
+		{name: "json schema match", value: `{"replicas": 2}`, dataType: models.JSON_TYPE,
+			constraint: &models.ValueConstraint{JsonSchema: map[string]interface{}{
+				"type":       "object",
+				"required":   []interface{}{"replicas"},
+				"properties": map[string]interface{}{"replicas": map[string]interface{}{"type": "integer", "minimum": 1}},
+			}}},
+		{name: "json schema mismatch", value: `{"replicas": 0}`, dataType: models.JSON_TYPE,
+			constraint: &models.ValueConstraint{JsonSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"replicas": map[string]interface{}{"type": "integer", "minimum": 1}},
+			}}, wantErr: true},
+		{name: "json schema on int", value: json.Number("200"), dataType: models.INT_TYPE,
+			constraint: &models.ValueConstraint{JsonSchema: map[string]interface{}{"maximum": 100}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(tt.value, tt.dataType, tt.constraint)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateValueConstraint(t *testing.T) {
+	assert.Nil(t, ValidateValueConstraint(models.STRING_TYPE, nil))
+	assert.Nil(t, ValidateValueConstraint(models.INT_TYPE, &models.ValueConstraint{Regex: `^\d{2}$`}))
+	assert.NotNil(t, ValidateValueConstraint(models.BOOL_TYPE, &models.ValueConstraint{Regex: `^true$`}))
+	assert.NotNil(t, ValidateValueConstraint(models.STRING_TYPE, &models.ValueConstraint{Regex: `(`}))
+	assert.NotNil(t, ValidateValueConstraint(models.INT_TYPE, &models.ValueConstraint{Enum: []interface{}{"one"}}))
+	assert.NotNil(t, ValidateValueConstraint(models.JSON_TYPE, &models.ValueConstraint{JsonSchema: map[string]interface{}{"type": 1}}))
+}
+
+func TestManifestToPayloadForComplexTypes(t *testing.T) {
+	manifest := models.ScopedVariableManifest{
+		Spec: []models.VariableSpec{{
+			Name:   "RESOURCES",
+			Type:   models.JSON_TYPE,
+			Values: []models.VariableValueSpec{{Category: models.Global, Value: map[string]interface{}{"cpu": "100m"}}},
+		}},
+	}
+	payload := ManifestToPayload(manifest, 1)
+	assert.Equal(t, `{"cpu":"100m"}`, payload.Variables[0].AttributeValues[0].VariableValue.Value)
+	assert.Equal(t, models.JSON_TYPE, payload.Variables[0].Definition.DataType)
+
+	manifest.Spec[0].Type = ""
+	payload = ManifestToPayload(manifest, 1)
+	assert.Equal(t, models.PRIMITIVE_TYPE, payload.Variables[0].Definition.DataType, "variables without a type are primitives")
+}
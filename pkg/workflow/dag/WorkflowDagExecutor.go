@@ -43,6 +43,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/build/trigger"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	repository5 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/autoRollback/watch"
 	common2 "github.com/devtron-labs/devtron/pkg/deployment/common"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
@@ -57,6 +58,9 @@ import (
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
 	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom"
+	sbomBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/sbom/bean"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
 	bean4 "github.com/devtron-labs/devtron/pkg/workflow/cd/bean"
@@ -151,6 +155,10 @@ type WorkflowDagExecutorImpl struct {
 	k8sCommonService k8sPkg.K8sCommonService
 	workflowService  executor.WorkflowService
 	ciHandlerService trigger.HandlerService
+
+	autoRollbackWatchService watch.AutoRollbackWatchService
+	imageSigningService      imageSigning.ImageSigningService
+	sbomService              sbom.SbomService
 }
 
 func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -184,6 +192,9 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	k8sCommonService k8sPkg.K8sCommonService,
 	workflowService executor.WorkflowService,
 	ciHandlerService trigger.HandlerService,
+	autoRollbackWatchService watch.AutoRollbackWatchService,
+	imageSigningService imageSigning.ImageSigningService,
+	sbomService sbom.SbomService,
 ) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
@@ -217,6 +228,9 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		k8sCommonService:              k8sCommonService,
 		workflowService:               workflowService,
 		ciHandlerService:              ciHandlerService,
+		autoRollbackWatchService:      autoRollbackWatchService,
+		imageSigningService:           imageSigningService,
+		sbomService:                   sbomService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -784,6 +798,9 @@ func (impl *WorkflowDagExecutorImpl) HandleDeploymentSuccessEvent(triggerContext
 		impl.logger.Errorw("error in fetching cd workflow by id", "pipelineOverride", pipelineOverride)
 		return err
 	}
+	if pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_STOP && pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_START {
+		impl.startAutoRollbackWatch(cdWorkflow)
+	}
 
 	postStage, err := impl.getPipelineStage(pipelineOverride.PipelineId, repository4.PIPELINE_STAGE_TYPE_POST_CD)
 	if err != nil {
@@ -828,6 +845,24 @@ func (impl *WorkflowDagExecutorImpl) HandleDeploymentSuccessEvent(triggerContext
 	return nil
 }
 
+// startAutoRollbackWatch starts watching the deployment for the auto rollback policy of the pipeline, failure
+// to start the watch does not fail the success event handling
+func (impl *WorkflowDagExecutorImpl) startAutoRollbackWatch(cdWorkflow *pipelineConfig.CdWorkflow) {
+	deployRunnerId := 0
+	for _, runner := range cdWorkflow.CdWorkflowRunner {
+		if runner.WorkflowType == bean.CD_WORKFLOW_TYPE_DEPLOY && runner.Id > deployRunnerId {
+			deployRunnerId = runner.Id
+		}
+	}
+	if deployRunnerId == 0 {
+		return
+	}
+	err := impl.autoRollbackWatchService.StartWatch(cdWorkflow.PipelineId, cdWorkflow.Id, deployRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in starting auto rollback watch", "cdWorkflowId", cdWorkflow.Id, "cdWorkflowRunnerId", deployRunnerId, "err", err)
+	}
+}
+
 func (impl *WorkflowDagExecutorImpl) HandlePostStageSuccessEvent(triggerContext triggerBean.TriggerContext, wfr *bean4.CdWorkflowRunnerDto, cdWorkflowId int, cdPipelineId int, triggeredBy int32, pluginRegistryImageDetails map[string][]string) error {
 	// finding children cd by pipeline id
 	cdPipelinesMapping, err := impl.appWorkflowRepository.FindWFCDMappingByParentCDPipelineId(cdPipelineId)
@@ -1045,6 +1080,12 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(triggerContext trigger
 			return 0, err
 		}
 	}
+	// images are signed before auto trigger so that signature policies of the target environments can verify them,
+	// child ci artifacts share the image of the build artifact. Signing is bounded by IMAGE_SIGNING_CI_TIMEOUT_SECS
+	impl.imageSigningService.SignArtifacts(pipelineModal.AppId, append([]*repository.CiArtifact{buildArtifact}, pluginArtifacts...), request.UserId)
+	if len(request.Sbom) > 0 {
+		impl.saveCiSbom(request, pipelineModal.AppId, buildArtifact, pluginArtifacts, childrenCi, ciArtifactArr)
+	}
 	if len(pluginArtifacts) == 0 {
 		ciArtifactArr = append(ciArtifactArr, buildArtifact)
 	} else {
@@ -1187,6 +1228,13 @@ func (impl *WorkflowDagExecutorImpl) HandleExternalCiWebhook(externalCiId int, r
 		return 0, err
 	}
 	materialJson = dst.Bytes()
+	if len(request.Sbom) > 0 {
+		// an invalid sbom is rejected before the artifact is created and its deployments are triggered
+		if err = impl.sbomService.ValidateSbom(request.Sbom); err != nil {
+			impl.logger.Errorw("invalid sbom in external ci webhook", "externalCiId", externalCiId, "err", err)
+			return 0, err
+		}
+	}
 	artifact := &repository.CiArtifact{
 		Image:                request.Image,
 		ImageDigest:          request.ImageDigest,
@@ -1203,6 +1251,13 @@ func (impl *WorkflowDagExecutorImpl) HandleExternalCiWebhook(externalCiId int, r
 		return 0, err
 	}
 
+	if len(request.Sbom) > 0 {
+		_, err = impl.sbomService.SaveSbom(externalCiPipeline.AppId, artifact.Id, request.Sbom, sbomBean.SbomSourceExternalCi, request.UserId)
+		if err != nil {
+			// the sbom was validated, the artifact is not rolled back for a failure in storing it
+			impl.logger.Errorw("error in saving sbom of external ci artifact", "externalCiId", externalCiId, "ciArtifactId", artifact.Id, "err", err)
+		}
+	}
 	hasAnyTriggered, err := impl.handleWebhookExternalCiEvent(artifact, request.UserId, externalCiId, auth, token)
 	if err != nil {
 		impl.logger.Errorw("error on handle ext ci webhook", "err", err)
@@ -1217,6 +1272,30 @@ func (impl *WorkflowDagExecutorImpl) HandleExternalCiWebhook(externalCiId int, r
 	return artifact.Id, err
 }
 
+// saveCiSbom saves the sbom of the build for every artifact sharing the built image, so that their deployments
+// are found by package searches. The build is not failed for an invalid sbom.
+func (impl *WorkflowDagExecutorImpl) saveCiSbom(request *bean2.CiArtifactWebhookRequest, appId int, buildArtifact *repository.CiArtifact,
+	pluginArtifacts []*repository.CiArtifact, childrenCi []*pipelineConfig.CiPipeline, childArtifacts []*repository.CiArtifact) {
+	artifactAppIds := make(map[int]int)
+	artifactAppIds[buildArtifact.Id] = appId
+	for _, pluginArtifact := range pluginArtifacts {
+		artifactAppIds[pluginArtifact.Id] = appId
+	}
+	// child artifacts are created in the order of the child ci pipelines
+	for i, childArtifact := range childArtifacts {
+		if i < len(childrenCi) {
+			artifactAppIds[childArtifact.Id] = childrenCi[i].AppId
+		}
+	}
+	for ciArtifactId, artifactAppId := range artifactAppIds {
+		_, err := impl.sbomService.SaveSbom(artifactAppId, ciArtifactId, request.Sbom, sbomBean.SbomSourceCi, request.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in saving sbom of ci artifact", "appId", artifactAppId, "ciArtifactId", ciArtifactId, "err", err)
+			continue
+		}
+	}
+}
+
 // TODO: move in adapter
 func (impl *WorkflowDagExecutorImpl) BuildCiArtifactRequestForWebhook(event pipeline.ExternalCiWebhookDto) (*bean2.CiArtifactWebhookRequest, error) {
 	ciMaterialInfos := make([]repository.CiMaterialInfo, 0)
@@ -1284,6 +1363,7 @@ func (impl *WorkflowDagExecutorImpl) BuildCiArtifactRequestForWebhook(event pipe
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		Sbom:               event.Sbom,
 	}
 	// if DataSource is empty, repository.WEBHOOK is considered as default
 	if request.DataSource == "" {
@@ -36,6 +36,7 @@ type CiArtifactWebhookRequest struct {
 	PluginArtifactStage           string                         `json:"pluginArtifactStage"`           // at which stage of CI artifact was generated by plugin ("pre_ci/post_ci")
 	IsScanEnabled                 bool                           `json:"isScanEnabled"`
 	TargetPlatforms               []string                       `json:"targetPlatforms"`
+	Sbom                          json.RawMessage                `json:"sbom"` // SPDX or CycloneDX json sbom of the image, if generated by the build
 }
 
 const (
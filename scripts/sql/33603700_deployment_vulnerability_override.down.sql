@@ -0,0 +1,11 @@
+-- Drop Table: deployment_vulnerability_override_audit
+DROP TABLE IF EXISTS "public"."deployment_vulnerability_override_audit";
+
+-- Drop Sequence: id_seq_deployment_vulnerability_override_audit
+DROP SEQUENCE IF EXISTS id_seq_deployment_vulnerability_override_audit;
+
+-- Drop Table: deployment_vulnerability_override
+DROP TABLE IF EXISTS "public"."deployment_vulnerability_override";
+
+-- Drop Sequence: id_seq_deployment_vulnerability_override
+DROP SEQUENCE IF EXISTS id_seq_deployment_vulnerability_override;
@@ -0,0 +1,47 @@
+BEGIN;
+
+-- Create Sequence for deployment_vulnerability_override
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_vulnerability_override;
+
+-- Table Definition: deployment_vulnerability_override
+CREATE TABLE IF NOT EXISTS "public"."deployment_vulnerability_override" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_vulnerability_override'::regclass),
+    "cd_pipeline_id"        int          NOT NULL,
+    "ci_artifact_id"        int          NOT NULL,
+    "image_digest"          text,
+    "reason"                text         NOT NULL,
+    "expires_on"            timestamptz  NOT NULL,
+    "active"                bool         NOT NULL DEFAULT true,
+    "revoked_by"            int4,
+    "revoked_on"            timestamptz,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "deployment_vulnerability_override_cd_pipeline_id_fkey" FOREIGN KEY ("cd_pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "deployment_vulnerability_override_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_vulnerability_override_pipeline_artifact"
+    ON "public"."deployment_vulnerability_override" ("cd_pipeline_id", "ci_artifact_id");
+
+-- Create Sequence for deployment_vulnerability_override_audit
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_vulnerability_override_audit;
+
+-- Table Definition: deployment_vulnerability_override_audit
+CREATE TABLE IF NOT EXISTS "public"."deployment_vulnerability_override_audit" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_vulnerability_override_audit'::regclass),
+    "override_id"           int          NOT NULL,
+    "action"                VARCHAR(20)  NOT NULL,
+    "cd_workflow_runner_id" int,
+    "blocked_cves"          text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "deployment_vulnerability_override_audit_override_id_fkey" FOREIGN KEY ("override_id") REFERENCES "public"."deployment_vulnerability_override" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+COMMIT;
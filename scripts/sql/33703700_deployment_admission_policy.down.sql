@@ -0,0 +1,8 @@
+BEGIN;
+
+-- resource_type 6 is DeploymentAdmission, refer resourceQualifiers.ResourceType
+DELETE FROM "public"."resource_qualifier_mapping" WHERE resource_type = 6;
+DROP TABLE IF EXISTS "public"."deployment_admission_policy";
+DROP SEQUENCE IF EXISTS "public"."id_seq_deployment_admission_policy";
+
+COMMIT;
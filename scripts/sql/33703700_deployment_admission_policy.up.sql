@@ -0,0 +1,27 @@
+BEGIN;
+
+-- Create Sequence for deployment_admission_policy
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_admission_policy;
+
+-- Table Definition: deployment_admission_policy
+CREATE TABLE IF NOT EXISTS "public"."deployment_admission_policy" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_admission_policy'::regclass),
+    "name"                  VARCHAR(250) NOT NULL,
+    "description"           text,
+    "expression"            text         NOT NULL,
+    "action"                VARCHAR(10)  NOT NULL,
+    "message"               text,
+    "enabled"               bool         NOT NULL DEFAULT true,
+    "deleted"               bool         NOT NULL DEFAULT false,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_unique_deployment_admission_policy_name"
+    ON "public"."deployment_admission_policy" ("name")
+    WHERE deleted = false;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- resource_type 5 is DeploymentWindow, refer resourceQualifiers.ResourceType
+DELETE FROM "public"."resource_qualifier_mapping" WHERE resource_type = 5;
+DROP TABLE IF EXISTS "public"."deployment_window_exception_audit";
+DROP SEQUENCE IF EXISTS "public"."id_seq_deployment_window_exception_audit";
+DROP TABLE IF EXISTS "public"."deployment_window_exception";
+DROP SEQUENCE IF EXISTS "public"."id_seq_deployment_window_exception";
+DROP TABLE IF EXISTS "public"."deployment_window";
+DROP SEQUENCE IF EXISTS "public"."id_seq_deployment_window";
+
+COMMIT;
@@ -0,0 +1,74 @@
+BEGIN;
+
+-- Create Sequence for deployment_window
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+-- Table Definition: deployment_window
+-- a window either recurs (cron_expression + duration_in_minutes) or is a one-off calendar entry (start_time + end_time)
+CREATE TABLE IF NOT EXISTS "public"."deployment_window" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    "name"                  VARCHAR(250) NOT NULL,
+    "description"           text,
+    "window_type"           VARCHAR(20)  NOT NULL,
+    "timezone"              VARCHAR(100) NOT NULL DEFAULT 'UTC',
+    "cron_expression"       VARCHAR(100),
+    "duration_in_minutes"   int,
+    "start_time"            timestamptz,
+    "end_time"              timestamptz,
+    "enabled"               bool         NOT NULL DEFAULT true,
+    "deleted"               bool         NOT NULL DEFAULT false,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_unique_deployment_window_name"
+    ON "public"."deployment_window" ("name")
+    WHERE deleted = false;
+
+-- Create Sequence for deployment_window_exception
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_exception;
+
+-- Table Definition: deployment_window_exception
+CREATE TABLE IF NOT EXISTS "public"."deployment_window_exception" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_window_exception'::regclass),
+    "cd_pipeline_id"        int          NOT NULL,
+    "reason"                text         NOT NULL,
+    "status"                VARCHAR(20)  NOT NULL,
+    "valid_from"            timestamptz  NOT NULL,
+    "valid_till"            timestamptz  NOT NULL,
+    "reviewed_by"           int4,
+    "reviewed_on"           timestamptz,
+    "review_comment"        text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "deployment_window_exception_cd_pipeline_id_fkey" FOREIGN KEY ("cd_pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_window_exception_pipeline_status"
+    ON "public"."deployment_window_exception" ("cd_pipeline_id", "status");
+
+-- Create Sequence for deployment_window_exception_audit
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_exception_audit;
+
+-- Table Definition: deployment_window_exception_audit
+CREATE TABLE IF NOT EXISTS "public"."deployment_window_exception_audit" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_window_exception_audit'::regclass),
+    "exception_id"          int          NOT NULL,
+    "action"                VARCHAR(20)  NOT NULL,
+    "cd_workflow_runner_id" int,
+    "comment"               text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "deployment_window_exception_audit_exception_id_fkey" FOREIGN KEY ("exception_id") REFERENCES "public"."deployment_window_exception" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+COMMIT;
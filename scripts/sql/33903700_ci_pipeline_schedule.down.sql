@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."ci_pipeline_schedule_material";
+DROP SEQUENCE IF EXISTS "public"."id_seq_ci_pipeline_schedule_material";
+DROP TABLE IF EXISTS "public"."ci_pipeline_schedule";
+DROP SEQUENCE IF EXISTS "public"."id_seq_ci_pipeline_schedule";
+
+COMMIT;
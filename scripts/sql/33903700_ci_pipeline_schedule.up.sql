@@ -0,0 +1,62 @@
+BEGIN;
+
+-- Create Sequence for ci_pipeline_schedule
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule;
+
+-- Table Definition: ci_pipeline_schedule
+-- next_run_at is claimed by the schedule poller before triggering, last_built_commits holds the commits
+-- (json map of ci pipeline material id to commit hash) built by the last run for skipping runs without new commits
+CREATE TABLE IF NOT EXISTS "public"."ci_pipeline_schedule" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule'::regclass),
+    "ci_pipeline_id"        int          NOT NULL,
+    "name"                  VARCHAR(100) NOT NULL,
+    "cron_expression"       VARCHAR(100) NOT NULL,
+    "timezone"              VARCHAR(100) NOT NULL DEFAULT 'UTC',
+    "skip_if_no_new_commit" bool         NOT NULL DEFAULT true,
+    "active"                bool         NOT NULL DEFAULT true,
+    "deleted"               bool         NOT NULL DEFAULT false,
+    "next_run_at"           timestamptz,
+    "last_run_at"           timestamptz,
+    "last_run_status"       VARCHAR(20),
+    "last_run_message"      text,
+    "last_ci_workflow_id"   int,
+    "last_built_commits"    text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "ci_pipeline_schedule_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_unique_ci_pipeline_schedule_name"
+    ON "public"."ci_pipeline_schedule" ("ci_pipeline_id", "name")
+    WHERE deleted = false;
+
+CREATE INDEX IF NOT EXISTS "idx_ci_pipeline_schedule_next_run_at"
+    ON "public"."ci_pipeline_schedule" ("next_run_at")
+    WHERE active = true AND deleted = false;
+
+-- Create Sequence for ci_pipeline_schedule_material
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule_material;
+
+-- Table Definition: ci_pipeline_schedule_material
+-- branch to be built by the schedule for a git material, materials without an entry build the branch configured in the pipeline
+CREATE TABLE IF NOT EXISTS "public"."ci_pipeline_schedule_material" (
+    "id"                      int          NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule_material'::regclass),
+    "ci_pipeline_schedule_id" int          NOT NULL,
+    "git_material_id"         int          NOT NULL,
+    "branch_name"             VARCHAR(250) NOT NULL,
+    "created_on"              timestamptz  NOT NULL,
+    "created_by"              int4         NOT NULL,
+    "updated_on"              timestamptz  NOT NULL,
+    "updated_by"              int4         NOT NULL,
+    CONSTRAINT "ci_pipeline_schedule_material_schedule_id_fkey" FOREIGN KEY ("ci_pipeline_schedule_id") REFERENCES "public"."ci_pipeline_schedule" ("id"),
+    CONSTRAINT "ci_pipeline_schedule_material_git_material_id_fkey" FOREIGN KEY ("git_material_id") REFERENCES "public"."git_material" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_ci_pipeline_schedule_material_schedule_id"
+    ON "public"."ci_pipeline_schedule_material" ("ci_pipeline_schedule_id");
+
+COMMIT;
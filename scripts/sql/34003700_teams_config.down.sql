@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."teams_config";
+DROP SEQUENCE IF EXISTS public.id_seq_teams_config;
+
+COMMIT;
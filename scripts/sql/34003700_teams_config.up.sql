@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Create Sequence for teams_config
+CREATE SEQUENCE IF NOT EXISTS id_seq_teams_config;
+
+-- Table Definition: teams_config
+-- holds the Microsoft Teams incoming webhook (or workflow) urls notifications are delivered to
+CREATE TABLE IF NOT EXISTS "public"."teams_config" (
+    "id"           int          NOT NULL DEFAULT nextval('id_seq_teams_config'::regclass),
+    "web_hook_url" text         NOT NULL,
+    "config_name"  VARCHAR(250) NOT NULL,
+    "description"  text,
+    "owner_id"     int4,
+    "team_id"      int4,
+    "deleted"      bool         NOT NULL DEFAULT false,
+    "created_on"   timestamptz  NOT NULL,
+    "created_by"   int4         NOT NULL,
+    "updated_on"   timestamptz  NOT NULL,
+    "updated_by"   int4         NOT NULL,
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_teams_config_team_id ON public.teams_config (team_id) WHERE deleted = false;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."deployment_incident";
+DROP SEQUENCE IF EXISTS public.id_seq_deployment_incident;
+DROP TABLE IF EXISTS "public"."incident_integration_config";
+DROP SEQUENCE IF EXISTS public.id_seq_incident_integration_config;
+
+COMMIT;
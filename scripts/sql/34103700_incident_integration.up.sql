@@ -0,0 +1,57 @@
+BEGIN;
+
+-- Create Sequence for incident_integration_config
+CREATE SEQUENCE IF NOT EXISTS id_seq_incident_integration_config;
+
+-- Table Definition: incident_integration_config
+-- routing_key is the PagerDuty Events v2 integration key or the Opsgenie API key, api_url overrides the provider endpoint
+CREATE TABLE IF NOT EXISTS "public"."incident_integration_config" (
+    "id"             int          NOT NULL DEFAULT nextval('id_seq_incident_integration_config'::regclass),
+    "environment_id" int          NOT NULL,
+    "provider"       VARCHAR(50)  NOT NULL,
+    "routing_key"    text         NOT NULL,
+    "api_url"        text,
+    "severity"       VARCHAR(20)  NOT NULL DEFAULT 'critical',
+    "active"         bool         NOT NULL DEFAULT true,
+    "deleted"        bool         NOT NULL DEFAULT false,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    CONSTRAINT "incident_integration_config_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_incident_integration_config_env_id
+    ON public.incident_integration_config (environment_id) WHERE deleted = false;
+
+-- Create Sequence for deployment_incident
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_incident;
+
+-- Table Definition: deployment_incident
+-- an incident opened with the provider for an app on an environment, dedup_key identifies it with the provider for resolution
+CREATE TABLE IF NOT EXISTS "public"."deployment_incident" (
+    "id"                             int          NOT NULL DEFAULT nextval('id_seq_deployment_incident'::regclass),
+    "incident_integration_config_id" int          NOT NULL,
+    "app_id"                         int          NOT NULL,
+    "environment_id"                 int          NOT NULL,
+    "pipeline_id"                    int          NOT NULL,
+    "cd_workflow_runner_id"          int,
+    "provider"                       VARCHAR(50)  NOT NULL,
+    "dedup_key"                      VARCHAR(250) NOT NULL,
+    "trigger_reason"                 VARCHAR(50)  NOT NULL,
+    "summary"                        text,
+    "status"                         VARCHAR(20)  NOT NULL,
+    "resolved_on"                    timestamptz,
+    "created_on"                     timestamptz  NOT NULL,
+    "created_by"                     int4         NOT NULL,
+    "updated_on"                     timestamptz  NOT NULL,
+    "updated_by"                     int4         NOT NULL,
+    CONSTRAINT "deployment_incident_incident_integration_config_id_fkey" FOREIGN KEY ("incident_integration_config_id") REFERENCES "public"."incident_integration_config" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_deployment_incident_app_env_status
+    ON public.deployment_incident (app_id, environment_id, status);
+
+COMMIT;
@@ -0,0 +1,2 @@
+ALTER TABLE gitops_config
+    ADD COLUMN IF NOT EXISTS gitea_org_id text;
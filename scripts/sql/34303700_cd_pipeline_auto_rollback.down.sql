@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."cd_pipeline_auto_rollback_watch";
+DROP SEQUENCE IF EXISTS public.id_seq_cd_pipeline_auto_rollback_watch;
+DROP TABLE IF EXISTS "public"."cd_pipeline_auto_rollback_config";
+DROP SEQUENCE IF EXISTS public.id_seq_cd_pipeline_auto_rollback_config;
+
+COMMIT;
@@ -0,0 +1,56 @@
+BEGIN;
+
+-- Create Sequence for cd_pipeline_auto_rollback_config
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_auto_rollback_config;
+
+-- Table Definition: cd_pipeline_auto_rollback_config
+CREATE TABLE IF NOT EXISTS "public"."cd_pipeline_auto_rollback_config" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_cd_pipeline_auto_rollback_config'::regclass),
+    "pipeline_id"           int          NOT NULL,
+    "enabled"               bool         NOT NULL DEFAULT false,
+    "window_in_minutes"     int          NOT NULL,
+    "on_degraded"           bool         NOT NULL DEFAULT false,
+    "on_post_stage_failure" bool         NOT NULL DEFAULT false,
+    "active"                bool         NOT NULL DEFAULT true,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "cd_pipeline_auto_rollback_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_cd_pipeline_auto_rollback_config_pipeline_id
+    ON public.cd_pipeline_auto_rollback_config (pipeline_id) WHERE active = true;
+
+-- Create Sequence for cd_pipeline_auto_rollback_watch
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_auto_rollback_watch;
+
+-- Table Definition: cd_pipeline_auto_rollback_watch
+-- a successful deployment watched till watch_until, rollback_cd_workflow_runner_id is the redeployment of target_cd_workflow_runner_id
+CREATE TABLE IF NOT EXISTS "public"."cd_pipeline_auto_rollback_watch" (
+    "id"                             int          NOT NULL DEFAULT nextval('id_seq_cd_pipeline_auto_rollback_watch'::regclass),
+    "pipeline_id"                    int          NOT NULL,
+    "cd_workflow_id"                 int          NOT NULL,
+    "cd_workflow_runner_id"          int          NOT NULL,
+    "watch_until"                    timestamptz  NOT NULL,
+    "status"                         VARCHAR(50)  NOT NULL,
+    "reason"                         VARCHAR(250),
+    "target_cd_workflow_runner_id"   int,
+    "rollback_cd_workflow_runner_id" int,
+    "message"                        text,
+    "created_on"                     timestamptz  NOT NULL,
+    "created_by"                     int4         NOT NULL,
+    "updated_on"                     timestamptz  NOT NULL,
+    "updated_by"                     int4         NOT NULL,
+    CONSTRAINT "cd_pipeline_auto_rollback_watch_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_cd_pipeline_auto_rollback_watch_status
+    ON public.cd_pipeline_auto_rollback_watch (status);
+
+CREATE INDEX IF NOT EXISTS idx_cd_pipeline_auto_rollback_watch_pipeline_id
+    ON public.cd_pipeline_auto_rollback_watch (pipeline_id);
+
+COMMIT;
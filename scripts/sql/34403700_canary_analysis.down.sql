@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."canary_analysis_run";
+DROP SEQUENCE IF EXISTS public.id_seq_canary_analysis_run;
+DROP TABLE IF EXISTS "public"."canary_analysis_config";
+DROP SEQUENCE IF EXISTS public.id_seq_canary_analysis_config;
+
+COMMIT;
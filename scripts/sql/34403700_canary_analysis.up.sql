@@ -0,0 +1,53 @@
+BEGIN;
+
+-- Create Sequence for canary_analysis_config
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_config;
+
+-- Table Definition: canary_analysis_config
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_config" (
+    "id"          int          NOT NULL DEFAULT nextval('id_seq_canary_analysis_config'::regclass),
+    "pipeline_id" int          NOT NULL,
+    "enabled"     bool         NOT NULL DEFAULT false,
+    "config"      text         NOT NULL,
+    "active"      bool         NOT NULL DEFAULT true,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  int4         NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  int4         NOT NULL,
+    CONSTRAINT "canary_analysis_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_canary_analysis_config_pipeline_id
+    ON public.canary_analysis_config (pipeline_id) WHERE active = true;
+
+-- Create Sequence for canary_analysis_run
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_run;
+
+-- Table Definition: canary_analysis_run
+-- analysis of a canary deployment, config is the snapshot of canary_analysis_config the deployment was rendered with
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_run" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_canary_analysis_run'::regclass),
+    "pipeline_id"           int          NOT NULL,
+    "cd_workflow_runner_id" int          NOT NULL,
+    "status"                VARCHAR(50)  NOT NULL,
+    "current_step"          int          NOT NULL DEFAULT 0,
+    "step_started_on"       timestamptz,
+    "inconclusive_count"    int          NOT NULL DEFAULT 0,
+    "config"                text         NOT NULL,
+    "message"               text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "canary_analysis_run_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_canary_analysis_run_status
+    ON public.canary_analysis_run (status);
+
+CREATE INDEX IF NOT EXISTS idx_canary_analysis_run_cd_workflow_runner_id
+    ON public.canary_analysis_run (cd_workflow_runner_id);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."deployment_group_release_app";
+DROP SEQUENCE IF EXISTS id_seq_deployment_group_release_app;
+DROP TABLE IF EXISTS "public"."deployment_group_release_wave";
+DROP SEQUENCE IF EXISTS id_seq_deployment_group_release_wave;
+DROP TABLE IF EXISTS "public"."deployment_group_release";
+DROP SEQUENCE IF EXISTS id_seq_deployment_group_release;
+DROP TABLE IF EXISTS "public"."deployment_group_wave";
+DROP SEQUENCE IF EXISTS id_seq_deployment_group_wave;
+
+COMMIT;
@@ -0,0 +1,113 @@
+BEGIN;
+
+-- Create Sequence for deployment_group_wave
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_group_wave;
+
+-- Table Definition: deployment_group_wave
+-- waves of a deployment group, released in the order of their dependencies
+CREATE TABLE IF NOT EXISTS "public"."deployment_group_wave" (
+    "id"                     int          NOT NULL DEFAULT nextval('id_seq_deployment_group_wave'::regclass),
+    "deployment_group_id"    int          NOT NULL,
+    "name"                   VARCHAR(250) NOT NULL,
+    "wave_order"             int          NOT NULL,
+    "app_ids"                int[],
+    "depends_on"             text[],
+    "requires_approval"      bool         NOT NULL DEFAULT false,
+    "health_gate"            bool         NOT NULL DEFAULT false,
+    "health_timeout_minutes" int          NOT NULL DEFAULT 30,
+    "active"                 bool         NOT NULL DEFAULT true,
+    "created_on"             timestamptz  NOT NULL,
+    "created_by"             int4         NOT NULL,
+    "updated_on"             timestamptz  NOT NULL,
+    "updated_by"             int4         NOT NULL,
+    CONSTRAINT "deployment_group_wave_deployment_group_id_fkey" FOREIGN KEY ("deployment_group_id") REFERENCES "public"."deployment_group" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_deployment_group_wave_deployment_group_id
+    ON public.deployment_group_wave (deployment_group_id) WHERE active = true;
+
+-- Create Sequence for deployment_group_release
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_group_release;
+
+-- Table Definition: deployment_group_release
+CREATE TABLE IF NOT EXISTS "public"."deployment_group_release" (
+    "id"                  int          NOT NULL DEFAULT nextval('id_seq_deployment_group_release'::regclass),
+    "deployment_group_id" int          NOT NULL,
+    "ci_artifact_id"      int          NOT NULL,
+    "status"              VARCHAR(50)  NOT NULL,
+    "message"             text,
+    "finished_on"         timestamptz,
+    "created_on"          timestamptz  NOT NULL,
+    "created_by"          int4         NOT NULL,
+    "updated_on"          timestamptz  NOT NULL,
+    "updated_by"          int4         NOT NULL,
+    CONSTRAINT "deployment_group_release_deployment_group_id_fkey" FOREIGN KEY ("deployment_group_id") REFERENCES "public"."deployment_group" ("id"),
+    CONSTRAINT "deployment_group_release_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_deployment_group_release_deployment_group_id
+    ON public.deployment_group_release (deployment_group_id);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_group_release_status
+    ON public.deployment_group_release (status);
+
+-- Create Sequence for deployment_group_release_wave
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_group_release_wave;
+
+-- Table Definition: deployment_group_release_wave
+-- snapshot of a deployment_group_wave for a release
+CREATE TABLE IF NOT EXISTS "public"."deployment_group_release_wave" (
+    "id"                     int          NOT NULL DEFAULT nextval('id_seq_deployment_group_release_wave'::regclass),
+    "release_id"             int          NOT NULL,
+    "name"                   VARCHAR(250) NOT NULL,
+    "wave_order"             int          NOT NULL,
+    "depends_on"             text[],
+    "requires_approval"      bool         NOT NULL DEFAULT false,
+    "health_gate"            bool         NOT NULL DEFAULT false,
+    "health_timeout_minutes" int          NOT NULL DEFAULT 30,
+    "status"                 VARCHAR(50)  NOT NULL,
+    "approved_by"            int4,
+    "approved_on"            timestamptz,
+    "started_on"             timestamptz,
+    "finished_on"            timestamptz,
+    "message"                text,
+    "created_on"             timestamptz  NOT NULL,
+    "created_by"             int4         NOT NULL,
+    "updated_on"             timestamptz  NOT NULL,
+    "updated_by"             int4         NOT NULL,
+    CONSTRAINT "deployment_group_release_wave_release_id_fkey" FOREIGN KEY ("release_id") REFERENCES "public"."deployment_group_release" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_deployment_group_release_wave_release_id
+    ON public.deployment_group_release_wave (release_id);
+
+-- Create Sequence for deployment_group_release_app
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_group_release_app;
+
+-- Table Definition: deployment_group_release_app
+CREATE TABLE IF NOT EXISTS "public"."deployment_group_release_app" (
+    "id"              int          NOT NULL DEFAULT nextval('id_seq_deployment_group_release_app'::regclass),
+    "release_wave_id" int          NOT NULL,
+    "app_id"          int          NOT NULL,
+    "cd_pipeline_id"  int          NOT NULL,
+    "ci_artifact_id"  int          NOT NULL,
+    "cd_workflow_id"  int,
+    "status"          VARCHAR(50)  NOT NULL,
+    "message"         text,
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      int4         NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      int4         NOT NULL,
+    CONSTRAINT "deployment_group_release_app_release_wave_id_fkey" FOREIGN KEY ("release_wave_id") REFERENCES "public"."deployment_group_release_wave" ("id"),
+    CONSTRAINT "deployment_group_release_app_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    CONSTRAINT "deployment_group_release_app_cd_pipeline_id_fkey" FOREIGN KEY ("cd_pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_deployment_group_release_app_release_wave_id
+    ON public.deployment_group_release_app (release_wave_id);
+
+COMMIT;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.idx_resource_protection_app_id_env_id;
+DROP INDEX IF EXISTS public.idx_draft_version_draft_id;
+DROP INDEX IF EXISTS public.idx_unique_draft_awaiting_approval;
@@ -0,0 +1,9 @@
+-- at most one draft awaiting approval per config of the app in the environment
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_draft_awaiting_approval
+    ON public.draft (app_id, env_id, resource, resource_name) WHERE draft_state = 4;
+
+CREATE INDEX IF NOT EXISTS idx_draft_version_draft_id
+    ON public.draft_version (draft_id);
+
+CREATE INDEX IF NOT EXISTS idx_resource_protection_app_id_env_id
+    ON public.resource_protection (app_id, env_id, resource);
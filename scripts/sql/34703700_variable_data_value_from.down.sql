@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "public"."variable_data"
+    DROP COLUMN IF EXISTS "value_from";
+
+COMMIT;
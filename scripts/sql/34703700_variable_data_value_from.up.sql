@@ -0,0 +1,7 @@
+BEGIN;
+
+-- reference of the value in an external secret store, data is empty for such values
+ALTER TABLE "public"."variable_data"
+    ADD COLUMN IF NOT EXISTS "value_from" jsonb;
+
+COMMIT;
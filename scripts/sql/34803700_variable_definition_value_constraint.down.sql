@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "public"."variable_definition"
+    DROP COLUMN IF EXISTS "value_constraint";
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- constraints on the values of the variable, values are validated against the data type and the constraints
+ALTER TABLE "public"."variable_definition"
+    ADD COLUMN IF NOT EXISTS "value_constraint" jsonb;
+
+COMMIT;
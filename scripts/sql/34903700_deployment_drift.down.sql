@@ -0,0 +1,10 @@
+BEGIN;
+
+delete from "public"."notification_templates" where event_type_id=10;
+delete from notifier_event_log where event_type_id=10;
+delete from public.event where event_type='CONFIG DRIFT';
+
+DROP TABLE IF EXISTS "public"."deployment_drift";
+DROP SEQUENCE IF EXISTS id_seq_deployment_drift;
+
+COMMIT;
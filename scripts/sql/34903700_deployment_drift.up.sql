@@ -0,0 +1,88 @@
+BEGIN;
+
+-- Create Sequence for deployment_drift
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_drift;
+
+-- Table Definition: deployment_drift
+-- last drift scan of the live resources of a helm deployed cd pipeline against its last successful deployment
+CREATE TABLE IF NOT EXISTS "public"."deployment_drift" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_deployment_drift'::regclass),
+    "pipeline_id"           int          NOT NULL,
+    "app_id"                int          NOT NULL,
+    "env_id"                int          NOT NULL,
+    "status"                VARCHAR(50),
+    "drifted_resources"     text,
+    "message"               text,
+    "cd_workflow_runner_id" int,
+    "notify"                bool         NOT NULL DEFAULT false,
+    "auto_reconcile"        bool         NOT NULL DEFAULT false,
+    "scanned_on"            timestamptz,
+    "drift_detected_on"     timestamptz,
+    "notified_on"           timestamptz,
+    "reconciled_on"         timestamptz,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "deployment_drift_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_deployment_drift_pipeline_id
+    ON public.deployment_drift (pipeline_id);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_drift_app_id_env_id
+    ON public.deployment_drift (app_id, env_id);
+
+INSERT INTO public.event (id, event_type, description) VALUES (10, 'CONFIG DRIFT', '');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CD', 10, 'Config drift ses template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "⚠️ Configuration drift detected | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">Configuration drift detected</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>Drift: <strong>{{driftSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CD', 10, 'Config drift smtp template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "⚠️ Configuration drift detected | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">Configuration drift detected</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>Drift: <strong>{{driftSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CD', 10, 'Config drift slack template', '{
+    "text": ":warning: Configuration drift detected | Application > {{appName}} | Environment > {{envName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":warning: *Configuration drift detected*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [{
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "*Drift*\n{{driftSummary}}"
+            }
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "App details"
+                },
+                "url": "{{& appDetailsLink}}"
+            }]
+        }
+    ]
+}');
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."user_access_grant_audit";
+DROP SEQUENCE IF EXISTS id_seq_user_access_grant_audit;
+
+DROP TABLE IF EXISTS "public"."user_access_grant";
+DROP SEQUENCE IF EXISTS id_seq_user_access_grant;
+
+COMMIT;
@@ -0,0 +1,50 @@
+BEGIN;
+
+-- Create Sequence for user_access_grant
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_grant;
+
+-- Table Definition: user_access_grant
+-- time bound role grants of a user or a role group, revoked by the access grant cron on expiry
+CREATE TABLE IF NOT EXISTS "public"."user_access_grant" (
+    "id"             int          NOT NULL DEFAULT nextval('id_seq_user_access_grant'::regclass),
+    "subject_type"   VARCHAR(50)  NOT NULL,
+    "user_id"        int,
+    "role_group_id"  int,
+    "role_filters"   text         NOT NULL,
+    "roles"          text,
+    "start_time"     timestamptz  NOT NULL,
+    "end_time"       timestamptz  NOT NULL,
+    "reason"         text,
+    "status"         VARCHAR(50)  NOT NULL,
+    "requested_by"   int4         NOT NULL,
+    "reviewed_by"    int4,
+    "review_comment" text,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_user_access_grant_status_end_time
+    ON public.user_access_grant (status, end_time);
+
+-- Create Sequence for user_access_grant_audit
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_grant_audit;
+
+-- Table Definition: user_access_grant_audit
+CREATE TABLE IF NOT EXISTS "public"."user_access_grant_audit" (
+    "id"        int          NOT NULL DEFAULT nextval('id_seq_user_access_grant_audit'::regclass),
+    "grant_id"  int          NOT NULL,
+    "action"    VARCHAR(50)  NOT NULL,
+    "comment"   text,
+    "action_by" int4         NOT NULL,
+    "action_on" timestamptz  NOT NULL,
+    CONSTRAINT "user_access_grant_audit_grant_id_fkey" FOREIGN KEY ("grant_id") REFERENCES "public"."user_access_grant" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_user_access_grant_audit_grant_id
+    ON public.user_access_grant_audit (grant_id);
+
+COMMIT;
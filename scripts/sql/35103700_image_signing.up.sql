@@ -0,0 +1,95 @@
+BEGIN;
+
+-- Create Sequence for image_signing_key
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_key;
+
+-- Table Definition: image_signing_key
+-- public part of cosign signing keys, private keys are kept in kubernetes secrets referred by secret_name
+CREATE TABLE IF NOT EXISTS "public"."image_signing_key" (
+    "id"          int           NOT NULL DEFAULT nextval('id_seq_image_signing_key'::regclass),
+    "name"        VARCHAR(100)  NOT NULL,
+    "description" text,
+    "public_key"  text          NOT NULL,
+    "secret_name" VARCHAR(250),
+    "active"      bool          NOT NULL,
+    "created_on"  timestamptz   NOT NULL,
+    "created_by"  int4          NOT NULL,
+    "updated_on"  timestamptz   NOT NULL,
+    "updated_by"  int4          NOT NULL,
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_image_signing_key_name
+    ON public.image_signing_key (name)
+    WHERE active = true;
+
+-- Create Sequence for image_signing_config
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_config;
+
+-- Table Definition: image_signing_config
+-- images built by the ci pipelines of an app are signed by signing_key_id when enabled
+CREATE TABLE IF NOT EXISTS "public"."image_signing_config" (
+    "id"             int          NOT NULL DEFAULT nextval('id_seq_image_signing_config'::regclass),
+    "app_id"         int          NOT NULL,
+    "signing_key_id" int          NOT NULL,
+    "enabled"        bool         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    CONSTRAINT "image_signing_config_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    CONSTRAINT "image_signing_config_signing_key_id_fkey" FOREIGN KEY ("signing_key_id") REFERENCES "public"."image_signing_key" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_image_signing_config_app_id
+    ON public.image_signing_config (app_id);
+
+-- Create Sequence for image_signature_policy
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature_policy;
+
+-- Table Definition: image_signature_policy
+-- deployments on env_id are refused for images not signed by one of trusted_key_ids, any active key if empty
+CREATE TABLE IF NOT EXISTS "public"."image_signature_policy" (
+    "id"              int          NOT NULL DEFAULT nextval('id_seq_image_signature_policy'::regclass),
+    "env_id"          int          NOT NULL,
+    "enabled"         bool         NOT NULL,
+    "trusted_key_ids" int[],
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      int4         NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      int4         NOT NULL,
+    CONSTRAINT "image_signature_policy_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_image_signature_policy_env_id
+    ON public.image_signature_policy (env_id);
+
+-- Create Sequence for ci_artifact_signature
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_signature;
+
+-- Table Definition: ci_artifact_signature
+CREATE TABLE IF NOT EXISTS "public"."ci_artifact_signature" (
+    "id"             int           NOT NULL DEFAULT nextval('id_seq_ci_artifact_signature'::regclass),
+    "ci_artifact_id" int           NOT NULL,
+    "image"          text          NOT NULL,
+    "image_digest"   VARCHAR(250),
+    "signing_key_id" int           NOT NULL,
+    "signature"      text,
+    "signature_ref"  text,
+    "status"         VARCHAR(50)   NOT NULL,
+    "message"        text,
+    "signed_on"      timestamptz   NOT NULL,
+    "created_on"     timestamptz   NOT NULL,
+    "created_by"     int4          NOT NULL,
+    "updated_on"     timestamptz   NOT NULL,
+    "updated_by"     int4          NOT NULL,
+    CONSTRAINT "ci_artifact_signature_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_ci_artifact_signature_ci_artifact_id
+    ON public.ci_artifact_signature (ci_artifact_id);
+
+COMMIT;
@@ -0,0 +1,59 @@
+BEGIN;
+
+-- Create Sequence for ci_artifact_sbom
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_sbom;
+
+-- Table Definition: ci_artifact_sbom
+-- SPDX or CycloneDX json sbom of a ci artifact, uploaded by the build, the external ci webhook or a user
+CREATE TABLE IF NOT EXISTS "public"."ci_artifact_sbom" (
+    "id"             int           NOT NULL DEFAULT nextval('id_seq_ci_artifact_sbom'::regclass),
+    "ci_artifact_id" int           NOT NULL,
+    "app_id"         int           NOT NULL,
+    "format"         VARCHAR(20)   NOT NULL,
+    "spec_version"   VARCHAR(20),
+    "source"         VARCHAR(20)   NOT NULL,
+    "document"       text          NOT NULL,
+    "package_count"  int           NOT NULL,
+    "active"         bool          NOT NULL,
+    "created_on"     timestamptz   NOT NULL,
+    "created_by"     int4          NOT NULL,
+    "updated_on"     timestamptz   NOT NULL,
+    "updated_by"     int4          NOT NULL,
+    CONSTRAINT "ci_artifact_sbom_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    CONSTRAINT "ci_artifact_sbom_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_ci_artifact_sbom_ci_artifact_id
+    ON public.ci_artifact_sbom (ci_artifact_id)
+    WHERE active = true;
+
+CREATE INDEX IF NOT EXISTS idx_ci_artifact_sbom_app_id
+    ON public.ci_artifact_sbom (app_id)
+    WHERE active = true;
+
+-- Create Sequence for sbom_package
+CREATE SEQUENCE IF NOT EXISTS id_seq_sbom_package;
+
+-- Table Definition: sbom_package
+-- packages of the active sbom of ci artifacts, searched by name and version
+CREATE TABLE IF NOT EXISTS "public"."sbom_package" (
+    "id"                  int           NOT NULL DEFAULT nextval('id_seq_sbom_package'::regclass),
+    "ci_artifact_sbom_id" int           NOT NULL,
+    "ci_artifact_id"      int           NOT NULL,
+    "name"                VARCHAR(500)  NOT NULL,
+    "version"             VARCHAR(250),
+    "purl"                text,
+    "package_type"        VARCHAR(50),
+    "licenses"            text,
+    CONSTRAINT "sbom_package_ci_artifact_sbom_id_fkey" FOREIGN KEY ("ci_artifact_sbom_id") REFERENCES "public"."ci_artifact_sbom" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS idx_sbom_package_ci_artifact_sbom_id
+    ON public.sbom_package (ci_artifact_sbom_id);
+
+CREATE INDEX IF NOT EXISTS idx_sbom_package_name_version
+    ON public.sbom_package (LOWER(name), version);
+
+COMMIT;
@@ -0,0 +1,15 @@
+delete from "public"."notification_templates" where event_type_id=11;
+delete from notifier_event_log where event_type_id=11;
+delete from public.event where event_type='CVE EXCEPTION EXPIRY';
+
+-- Drop Table: cve_exception_audit
+DROP TABLE IF EXISTS "public"."cve_exception_audit";
+
+-- Drop Sequence: id_seq_cve_exception_audit
+DROP SEQUENCE IF EXISTS id_seq_cve_exception_audit;
+
+-- Drop Table: cve_exception
+DROP TABLE IF EXISTS "public"."cve_exception";
+
+-- Drop Sequence: id_seq_cve_exception
+DROP SEQUENCE IF EXISTS id_seq_cve_exception;
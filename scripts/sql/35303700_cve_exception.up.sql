@@ -0,0 +1,119 @@
+BEGIN;
+
+-- Create Sequence for cve_exception
+CREATE SEQUENCE IF NOT EXISTS id_seq_cve_exception;
+
+-- Table Definition: cve_exception
+CREATE TABLE IF NOT EXISTS "public"."cve_exception" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_cve_exception'::regclass),
+    "cve_name"              varchar(255) NOT NULL,
+    "exception_type"        VARCHAR(20)  NOT NULL,
+    "vex_justification"     VARCHAR(100),
+    "justification"         text         NOT NULL,
+    "owner_id"              int4         NOT NULL,
+    "cluster_id"            int,
+    "env_id"                int,
+    "app_id"                int,
+    "image_digest"          text,
+    "ci_artifact_id"        int,
+    "expires_on"            timestamptz  NOT NULL,
+    "expiry_notified"       bool         NOT NULL DEFAULT false,
+    "active"                bool         NOT NULL DEFAULT true,
+    "revoked_by"            int4,
+    "revoked_on"            timestamptz,
+    "revoke_reason"         text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "cve_exception_owner_id_fkey" FOREIGN KEY ("owner_id") REFERENCES "public"."users" ("id"),
+    CONSTRAINT "cve_exception_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "cve_exception_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "cve_exception_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    CONSTRAINT "cve_exception_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_cve_exception_active_expires_on"
+    ON "public"."cve_exception" ("active", "expires_on");
+
+CREATE INDEX IF NOT EXISTS "idx_cve_exception_cve_name"
+    ON "public"."cve_exception" ("cve_name");
+
+-- Create Sequence for cve_exception_audit
+CREATE SEQUENCE IF NOT EXISTS id_seq_cve_exception_audit;
+
+-- Table Definition: cve_exception_audit
+CREATE TABLE IF NOT EXISTS "public"."cve_exception_audit" (
+    "id"                    int          NOT NULL DEFAULT nextval('id_seq_cve_exception_audit'::regclass),
+    "exception_id"          int          NOT NULL,
+    "action"                VARCHAR(20)  NOT NULL,
+    "expires_on"            timestamptz  NOT NULL,
+    "justification"         text,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    CONSTRAINT "cve_exception_audit_exception_id_fkey" FOREIGN KEY ("exception_id") REFERENCES "public"."cve_exception" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_cve_exception_audit_exception_id"
+    ON "public"."cve_exception_audit" ("exception_id");
+
+INSERT INTO public.event (id, event_type, description) VALUES (11, 'CVE EXCEPTION EXPIRY', '');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CD', 11, 'CVE exception expiring ses template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "⏳ CVE exception expiring | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">CVE exception expiring</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>CVE exception: <strong>{{cveExceptionSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CD', 11, 'CVE exception expiring smtp template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "⏳ CVE exception expiring | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">CVE exception expiring</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>CVE exception: <strong>{{cveExceptionSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CD', 11, 'CVE exception expiring slack template', '{
+    "text": ":hourglass: CVE exception expiring | Application > {{appName}} | Environment > {{envName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":hourglass: *CVE exception expiring*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "*CVE exception*\n{{cveExceptionSummary}}"
+            }
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "App details"
+                },
+                "url": "{{& appDetailsLink}}"
+            }]
+        }
+    ]
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('webhook', 'CD', 11, 'CVE exception expiring webhook template', '{"text": "CVE exception expiring | Application: {{appName}} | Environment: {{envName}}", "appName": "{{appName}}", "envName": "{{envName}}", "cveExceptionSummary": "{{cveExceptionSummary}}", "eventTime": "{{eventTime}}", "appDetailsLink": "{{& appDetailsLink}}"}');
+
+COMMIT;
@@ -0,0 +1,9 @@
+delete from "public"."notification_templates" where event_type_id=12;
+delete from notifier_event_log where event_type_id=12;
+delete from public.event where event_type='NEW CRITICAL CVES';
+
+-- Drop Table: deployed_image_rescan
+DROP TABLE IF EXISTS "public"."deployed_image_rescan";
+
+-- Drop Sequence: id_seq_deployed_image_rescan
+DROP SEQUENCE IF EXISTS id_seq_deployed_image_rescan;
@@ -0,0 +1,92 @@
+BEGIN;
+
+-- Create Sequence for deployed_image_rescan
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployed_image_rescan;
+
+-- Table Definition: deployed_image_rescan
+CREATE TABLE IF NOT EXISTS "public"."deployed_image_rescan" (
+    "id"                             int          NOT NULL DEFAULT nextval('id_seq_deployed_image_rescan'::regclass),
+    "image"                          text         NOT NULL,
+    "image_digest"                   text         NOT NULL,
+    "ci_artifact_id"                 int,
+    "previous_execution_history_id"  int          NOT NULL,
+    "execution_history_id"           int,
+    "status"                         VARCHAR(20)  NOT NULL,
+    "new_critical_cves"              text[],
+    "triggered_on"                   timestamptz  NOT NULL,
+    "completed_on"                   timestamptz,
+    "created_on"                     timestamptz  NOT NULL,
+    "created_by"                     int4         NOT NULL,
+    "updated_on"                     timestamptz  NOT NULL,
+    "updated_by"                     int4         NOT NULL,
+    CONSTRAINT "deployed_image_rescan_previous_execution_history_id_fkey" FOREIGN KEY ("previous_execution_history_id") REFERENCES "public"."image_scan_execution_history" ("id"),
+    CONSTRAINT "deployed_image_rescan_execution_history_id_fkey" FOREIGN KEY ("execution_history_id") REFERENCES "public"."image_scan_execution_history" ("id"),
+    PRIMARY KEY ("id")
+    );
+
+CREATE INDEX IF NOT EXISTS "idx_deployed_image_rescan_status"
+    ON "public"."deployed_image_rescan" ("status");
+
+CREATE INDEX IF NOT EXISTS "idx_deployed_image_rescan_image_triggered_on"
+    ON "public"."deployed_image_rescan" ("image", "image_digest", "triggered_on");
+
+INSERT INTO public.event (id, event_type, description) VALUES (12, 'NEW CRITICAL CVES', '');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CD', 12, 'New critical CVEs in deployed image ses template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🚨 New critical CVEs in deployed image | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">New critical CVEs in deployed image</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>Image: <strong>{{dockerImageUrl}}</strong></span><br><br><span>CVEs: <strong>{{newCriticalCveSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CD', 12, 'New critical CVEs in deployed image smtp template', '{"from": "{{fromEmail}}", "to": "{{toEmail}}","subject": "🚨 New critical CVEs in deployed image | Application: {{appName}} | Environment: {{envName}}","html": "<h2 style=\"color:#ff7e5b;\">New critical CVEs in deployed image</h2><span>{{eventTime}}</span><br><br>{{#appDetailsLink}}<a href=\"{{& appDetailsLink}}\" style=\"height:32px;padding:7px 12px;line-height:32px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;outline:none;min-width:64px;text-transform:capitalize;text-align:center;background:#0066cc;color:#fff;border:1px solid transparent;cursor:pointer;\">App Details</a><br><br>{{/appDetailsLink}}<hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br><span>Image: <strong>{{dockerImageUrl}}</strong></span><br><br><span>CVEs: <strong>{{newCriticalCveSummary}}</strong></span><br>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CD', 12, 'New critical CVEs in deployed image slack template', '{
+    "text": ":rotating_light: New critical CVEs in deployed image | Application > {{appName}} | Environment > {{envName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":rotating_light: *New critical CVEs in deployed image*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n{{dockerImageUrl}}"
+                }
+            ]
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "*CVEs*\n{{newCriticalCveSummary}}"
+            }
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "App details"
+                },
+                "url": "{{& appDetailsLink}}"
+            }]
+        }
+    ]
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('webhook', 'CD', 12, 'New critical CVEs in deployed image webhook template', '{"text": "New critical CVEs in deployed image | Application: {{appName}} | Environment: {{envName}}", "appName": "{{appName}}", "envName": "{{envName}}", "dockerImageUrl": "{{dockerImageUrl}}", "newCriticalCveSummary": "{{newCriticalCveSummary}}", "eventTime": "{{eventTime}}", "appDetailsLink": "{{& appDetailsLink}}"}');
+
+COMMIT;
@@ -21,6 +21,11 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const Approval EventType = 4
+const ConfigApproval EventType = 5
+const ConfigDrift EventType = 10
+const CveExceptionExpiry EventType = 11
+const NewCriticalCves EventType = 12
 
 type PipelineType string
 
@@ -36,6 +41,7 @@ const (
 	SES     Channel = "ses"
 	SMTP    Channel = "smtp"
 	Webhook Channel = "webhook"
+	Teams   Channel = "teams"
 )
 
 func (c Channel) String() string {